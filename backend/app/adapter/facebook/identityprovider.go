@@ -28,7 +28,7 @@ type IdentityProvider struct {
 }
 
 // GetAuthorizationURL retrieves the URL of Facebook sign in page.
-func (g IdentityProvider) GetAuthorizationURL() string {
+func (g IdentityProvider) GetAuthorizationURL(state string) string {
 	clientID := g.clientID
 	redirectURI := g.redirectURI
 	responseType := fbResponseType
@@ -44,6 +44,7 @@ func (g IdentityProvider) GetAuthorizationURL() string {
 	query.Set("redirect_uri", redirectURI)
 	query.Set("scope", scope)
 	query.Set("response_type", responseType)
+	query.Set("state", state)
 	u.RawQuery = query.Encode()
 
 	return u.String()
@@ -51,7 +52,7 @@ func (g IdentityProvider) GetAuthorizationURL() string {
 
 // RequestAccessToken retrieves access token of user's Facebook account using
 // authorization code.
-func (g IdentityProvider) RequestAccessToken(authorizationCode string) (accessToken string, err error) {
+func (g IdentityProvider) RequestAccessToken(authorizationCode string, state string) (accessToken string, err error) {
 	type fbAccessTokenResponse struct {
 		AccessToken string `json:"access_token"`
 		TokenType   string `json:"token_type"`
@@ -26,7 +26,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	redirectURI := "http://localhost/oauth/facebook/sign-in/callback"
 	identityProvider := NewIdentityProvider(httpRequest, clientID, clientSecret, redirectURI)
 
-	urlResponse := identityProvider.GetAuthorizationURL()
+	urlResponse := identityProvider.GetAuthorizationURL("state/+=")
 
 	parsedUrl, err := url.Parse(urlResponse)
 
@@ -37,6 +37,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	assert.Equal(t, "code", parsedUrl.Query().Get("response_type"))
 	assert.Equal(t, clientID, parsedUrl.Query().Get("client_id"))
 	assert.Equal(t, redirectURI, parsedUrl.Query().Get("redirect_uri"))
+	assert.Equal(t, "state/+=", parsedUrl.Query().Get("state"))
 
 	expectedScope := []string{"public_profile", "email"}
 	actualScope := strings.Split(parsedUrl.Query().Get("scope"), ",")
@@ -141,7 +142,7 @@ func TestIdentityProvider_RequestAccessToken(t *testing.T) {
 					return testCase.httpResponse, testCase.httpErr
 				})
 			identityProvider := NewIdentityProvider(httpRequest, testCase.clientID, testCase.clientSecret, testCase.redirectURI)
-			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode)
+			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode, "")
 
 			if testCase.expectHasErr {
 				assert.NotEqual(t, nil, err)
@@ -24,7 +24,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	clientSecret := "client_secret"
 	identityProvider := NewIdentityProvider(httpRequest, clientID, clientSecret)
 
-	urlResponse := identityProvider.GetAuthorizationURL()
+	urlResponse := identityProvider.GetAuthorizationURL("state/+=")
 
 	parsedUrl, err := url.Parse(urlResponse)
 	assert.Equal(t, nil, err)
@@ -33,6 +33,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	assert.Equal(t, "/login/oauth/authorize", parsedUrl.Path)
 	assert.Equal(t, clientID, parsedUrl.Query().Get("client_id"))
 	assert.Equal(t, "read:user", parsedUrl.Query().Get("scope"))
+	assert.Equal(t, "state/+=", parsedUrl.Query().Get("state"))
 }
 
 func TestIdentityProvider_RequestAccessToken(t *testing.T) {
@@ -115,7 +116,7 @@ func TestIdentityProvider_RequestAccessToken(t *testing.T) {
 				})
 			identityProvider := NewIdentityProvider(httpRequest, testCase.clientID, testCase.clientSecret)
 
-			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode)
+			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode, "")
 
 			if testCase.expectHasErr {
 				assert.NotEqual(t, nil, err)
@@ -25,7 +25,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	redirectURI := "http://localhost/oauth/google/sign-in/callback"
 	identityProvider := NewIdentityProvider(httpRequest, clientID, clientSecret, redirectURI)
 
-	urlResponse := identityProvider.GetAuthorizationURL()
+	urlResponse := identityProvider.GetAuthorizationURL("state/+=")
 
 	parsedUrl, err := url.Parse(urlResponse)
 	assert.Equal(t, nil, err)
@@ -37,6 +37,7 @@ func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
 	assert.Equal(t, "code", parsedUrl.Query().Get("response_type"))
 	assert.Equal(t, clientID, parsedUrl.Query().Get("client_id"))
 	assert.Equal(t, redirectURI, parsedUrl.Query().Get("redirect_uri"))
+	assert.Equal(t, "state/+=", parsedUrl.Query().Get("state"))
 }
 
 func TestIdentityProvider_RequestAccessToken(t *testing.T) {
@@ -138,7 +139,7 @@ func TestIdentityProvider_RequestAccessToken(t *testing.T) {
 				})
 			identityProvider := NewIdentityProvider(httpRequest, testCase.clientID, testCase.clientSecret, testCase.redirectURI)
 
-			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode)
+			actualAccessToken, err := identityProvider.RequestAccessToken(testCase.authorizationCode, "")
 
 			if testCase.expectHasErr {
 				assert.NotEqual(t, nil, err)
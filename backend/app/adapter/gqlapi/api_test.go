@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/graphql"
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/timer"
@@ -15,14 +16,23 @@ import (
 	"github.com/short-d/short/backend/app/fw/filesystem"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/requester"
 	"github.com/short-d/short/backend/app/usecase/risk"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 	"github.com/short-d/short/backend/app/usecase/validator"
 )
 
@@ -32,37 +42,66 @@ func TestGraphQlAPI(t *testing.T) {
 	blockedURLs := map[string]bool{}
 	blacklist := risk.NewBlackListFake(blockedURLs)
 
-	shortLinkRepo := repository.NewShortLinkFake(nil, map[string]entity.ShortLink{})
+	shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{})
 	userShortLinkRepo := repository.NewUserShortLinkRepoFake([]entity.User{}, []entity.ShortLink{})
-	retriever := shortlink.NewRetrieverPersist(&shortLinkRepo, &userShortLinkRepo)
+	appShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+	retriever := shortlink.NewRetrieverPersist(&shortLinkRepo, &userShortLinkRepo, &appShortLinkRepo)
 	keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
 	keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
 	assert.Equal(t, nil, err)
 
-	longLinkValidator := validator.NewLongLink()
-	customAliasValidator := validator.NewCustomAlias()
+	longLinkValidator := validator.NewLongLink(validator.LongLinkPolicy{})
+	aliasNormalizer := normalizer.NewAlias(normalizer.AliasPolicy{})
+	wordList := validator.NewAliasWordList([]string{}, []string{})
+	customAliasValidator := validator.NewCustomAlias(aliasNormalizer, wordList)
 	tm := timer.NewStub(now)
 	riskDetector := risk.NewDetector(blacklist)
+	fakeRolesRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+	rb := rbac.NewRBAC(fakeRolesRepo)
+	au := authorizer.NewAuthorizer(rb)
+	redirectResolver := shortlink.NewRedirectResolver(
+		shortlink.RedirectPolicy{},
+		&shortLinkRepo,
+		aliasNormalizer,
+	)
+	metaTagQueue := shortlink.NewMetaTagQueueFake()
+	linkQuota := quota.NewQuota(&userShortLinkRepo, &appShortLinkRepo, rb, tm, quota.Policy{})
 
 	creator := shortlink.NewCreatorPersist(
 		&shortLinkRepo,
-		&userShortLinkRepo,
 		keyGen,
 		longLinkValidator,
 		customAliasValidator,
+		aliasNormalizer,
+		normalizer.NewLongLink(),
 		tm,
 		riskDetector,
+		au,
+		redirectResolver,
+		metaTagQueue,
+		linkQuota,
 	)
 
+	attributeRepo := repository.NewShortLinkAttributeFake(nil)
+	resourceFinder := policy.NewResourceFinder(&userShortLinkRepo, &attributeRepo)
 	updater := shortlink.NewUpdaterPersist(
 		&shortLinkRepo,
 		&userShortLinkRepo,
+		&appShortLinkRepo,
 		longLinkValidator,
 		customAliasValidator,
+		aliasNormalizer,
+		normalizer.NewLongLink(),
 		tm,
 		riskDetector,
+		au,
+		resourceFinder,
+		redirectResolver,
+		metaTagQueue,
 	)
 
+	metaTag := shortlink.NewMetaTagPersist(&shortLinkRepo, &userShortLinkRepo, validator.NewImageURL())
+
 	s := requester.NewReCaptchaFake(requester.VerifyResponse{})
 	verifier := requester.NewReCaptchaVerifier(s)
 	auth := authenticator.NewAuthenticatorFake(time.Now(), time.Hour)
@@ -73,11 +112,39 @@ func TestGraphQlAPI(t *testing.T) {
 
 	changeLogRepo := repository.NewChangeLogFake([]entity.Change{})
 	userChangeLogRepo := repository.NewUserChangeLogFake(map[string]time.Time{})
-	fakeRolesRepo := repository.NewUserRoleFake(map[string][]role.Role{})
-	rb := rbac.NewRBAC(fakeRolesRepo)
-	au := authorizer.NewAuthorizer(rb)
 	changeLog := changelog.NewPersist(keyGen, tm, &changeLogRepo, &userChangeLogRepo, au)
-	r := resolver.NewResolver(lg, retriever, creator, updater, changeLog, verifier, auth)
+	apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{})
+	appRepo := repository.NewAppFake([]entity.App{})
+	thirdPartyApp := authenticator.NewThirdPartyApp(au, crypto.NewTokenizerFake(), keyGen, tm, &apiKeyRepo, &appRepo, lg)
+
+	appRegistry := thirdparty.NewPersist(keyGen, tm, &appRepo)
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	sessionManager := session.NewManager(&sessionRepo, au, tm)
+
+	visitRepo := repository.NewShortLinkVisitFake(nil)
+	analytics := shortlink.NewAnalyticsPersist(&shortLinkRepo, &visitRepo, au, resourceFinder, tm)
+	attribute := shortlink.NewAttributePersist(&shortLinkRepo, &attributeRepo, au)
+
+	r := resolver.NewResolver(
+		lg,
+		retriever,
+		creator,
+		updater,
+		metaTag,
+		changeLog,
+		verifier,
+		auth,
+		thirdPartyApp,
+		appRegistry,
+		linkQuota,
+		sessionManager,
+		sso.AccountManager{},
+		twofactor.TwoFactor{},
+		userrole.Manager{},
+		customrole.Manager{},
+		analytics,
+		attribute,
+	)
 
 	schema := "schema.graphql"
 	fileSystem := filesystem.NewLocal()
@@ -0,0 +1,43 @@
+package input
+
+import "github.com/short-d/short/backend/app/entity/metatag"
+
+// OpenGraphTagsInput represents possible Open Graph meta tags of a short link.
+type OpenGraphTagsInput struct {
+	Title       *string
+	Description *string
+	ImageURL    *string
+}
+
+// CreateOpenGraphTags converts GraphQL OpenGraphTagsInput into consumable
+// entity for use cases.
+func (o *OpenGraphTagsInput) CreateOpenGraphTags() *metatag.OpenGraph {
+	if o == nil {
+		return nil
+	}
+	return &metatag.OpenGraph{
+		Title:       o.Title,
+		Description: o.Description,
+		ImageURL:    o.ImageURL,
+	}
+}
+
+// TwitterTagsInput represents possible Twitter meta tags of a short link.
+type TwitterTagsInput struct {
+	Title       *string
+	Description *string
+	ImageURL    *string
+}
+
+// CreateTwitterTags converts GraphQL TwitterTagsInput into consumable entity
+// for use cases.
+func (t *TwitterTagsInput) CreateTwitterTags() *metatag.Twitter {
+	if t == nil {
+		return nil
+	}
+	return &metatag.Twitter{
+		Title:       t.Title,
+		Description: t.Description,
+		ImageURL:    t.ImageURL,
+	}
+}
@@ -0,0 +1,65 @@
+package resolver
+
+import (
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator/scope"
+)
+
+// APIKey retrieves requested fields of an APIKey.
+type APIKey struct {
+	apiKey entity.APIKey
+}
+
+// AppID retrieves the ID of the app which owns the APIKey entity.
+func (a APIKey) AppID() string {
+	return a.apiKey.AppID
+}
+
+// Key retrieves the key of APIKey entity.
+func (a APIKey) Key() string {
+	return a.apiKey.Key
+}
+
+// IsDisabled retrieves whether the APIKey entity has been revoked.
+func (a APIKey) IsDisabled() bool {
+	return a.apiKey.IsDisabled
+}
+
+// CreatedAt retrieves the creation time of APIKey entity.
+func (a APIKey) CreatedAt() scalar.Time {
+	return scalar.Time{Time: a.apiKey.CreatedAt}
+}
+
+// ExpireAt retrieves the expiration time of APIKey entity.
+func (a APIKey) ExpireAt() *scalar.Time {
+	if a.apiKey.ExpireAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *a.apiKey.ExpireAt}
+}
+
+// LastUsedAt retrieves the time when APIKey entity was last used.
+func (a APIKey) LastUsedAt() *scalar.Time {
+	if a.apiKey.LastUsedAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *a.apiKey.LastUsedAt}
+}
+
+// Scopes retrieves the operations APIKey entity is allowed to perform.
+func (a APIKey) Scopes() []string {
+	return a.apiKey.Scopes
+}
+
+func newAPIKey(apiKey entity.APIKey) APIKey {
+	return APIKey{apiKey: apiKey}
+}
+
+func newScopes(scopes []string) []scope.Scope {
+	apiKeyScopes := make([]scope.Scope, len(scopes))
+	for i, s := range scopes {
+		apiKeyScopes[i] = scope.Scope(s)
+	}
+	return apiKeyScopes
+}
@@ -0,0 +1,30 @@
+package resolver
+
+import (
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// App retrieves requested fields of an App.
+type App struct {
+	app entity.App
+}
+
+// ID retrieves the ID of App entity.
+func (a App) ID() string {
+	return a.app.ID
+}
+
+// Name retrieves the name of App entity.
+func (a App) Name() string {
+	return a.app.Name
+}
+
+// CreatedAt retrieves the creation time of App entity.
+func (a App) CreatedAt() scalar.Time {
+	return scalar.Time{Time: a.app.CreatedAt}
+}
+
+func newApp(app entity.App) App {
+	return App{app: app}
+}
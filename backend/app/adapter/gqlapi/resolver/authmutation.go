@@ -3,22 +3,41 @@ package resolver
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/short-d/short/backend/app/adapter/gqlapi/input"
 	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 // AuthMutation represents GraphQL mutation resolver that acts differently based
 // on the identify of the user
 type AuthMutation struct {
-	authToken        *string
-	authenticator    authenticator.Authenticator
-	changeLog        changelog.ChangeLog
-	shortLinkCreator shortlink.Creator
-	shortLinkUpdater shortlink.Updater
+	authToken          *string
+	authenticator      authenticator.Authenticator
+	thirdPartyApp      authenticator.ThirdPartyApp
+	appRegistry        thirdparty.Registry
+	changeLog          changelog.ChangeLog
+	shortLinkCreator   shortlink.Creator
+	shortLinkUpdater   shortlink.Updater
+	metaTag            shortlink.MetaTag
+	sessionManager     session.Manager
+	accountManager     sso.AccountManager
+	twoFactor          twofactor.TwoFactor
+	roleManager        userrole.Manager
+	customRoles        customrole.Manager
+	shortLinkAttribute shortlink.Attribute
 }
 
 // CreateShortLinkArgs represents the possible parameters for CreateShortLink endpoint
@@ -47,7 +66,13 @@ func (a AuthMutation) CreateShortLink(args *CreateShortLinkArgs) (*ShortLink, er
 		l  shortlink.ErrInvalidLongLink
 		c  shortlink.ErrInvalidCustomAlias
 		m  shortlink.ErrMaliciousLongLink
+		rl shortlink.ErrRedirectLoop
+		tm shortlink.ErrTooManyRedirects
+		qe quota.ErrQuotaExceeded
 	)
+	if errors.As(err, &qe) {
+		return nil, ErrQuotaExceeded{string(qe.Period), qe.Limit}
+	}
 	if errors.As(err, &ae) {
 		return nil, ErrAliasExist(shortLink.GetCustomAlias(""))
 	}
@@ -60,6 +85,12 @@ func (a AuthMutation) CreateShortLink(args *CreateShortLinkArgs) (*ShortLink, er
 	if errors.As(err, &m) {
 		return nil, ErrMaliciousContent(shortLink.GetLongLink(""))
 	}
+	if errors.As(err, &rl) {
+		return nil, ErrRedirectLoop(shortLink.GetLongLink(""))
+	}
+	if errors.As(err, &tm) {
+		return nil, ErrTooManyRedirects(shortLink.GetLongLink(""))
+	}
 	return nil, ErrUnknown{}
 }
 
@@ -90,6 +121,8 @@ func (a AuthMutation) UpdateShortLink(args *UpdateShortLinkArgs) (*ShortLink, er
 		m  shortlink.ErrMaliciousLongLink
 		nf shortlink.ErrShortLinkNotFound
 		ns shortlink.ErrEmptyAlias
+		rl shortlink.ErrRedirectLoop
+		tm shortlink.ErrTooManyRedirects
 	)
 	if errors.As(err, &ae) {
 		return nil, ErrAliasExist(update.GetCustomAlias(""))
@@ -103,6 +136,12 @@ func (a AuthMutation) UpdateShortLink(args *UpdateShortLinkArgs) (*ShortLink, er
 	if errors.As(err, &m) {
 		return nil, ErrMaliciousContent(update.GetLongLink(""))
 	}
+	if errors.As(err, &rl) {
+		return nil, ErrRedirectLoop(update.GetLongLink(""))
+	}
+	if errors.As(err, &tm) {
+		return nil, ErrTooManyRedirects(update.GetLongLink(""))
+	}
 	if errors.As(err, &nf) {
 		return nil, ErrShortLinkNotFound(args.OldAlias)
 	}
@@ -112,6 +151,45 @@ func (a AuthMutation) UpdateShortLink(args *UpdateShortLinkArgs) (*ShortLink, er
 	return nil, ErrUnknown{}
 }
 
+// UpdateShortLinkMetaTagsArgs represents the possible parameters for
+// updateShortLinkMetaTags endpoint
+type UpdateShortLinkMetaTagsArgs struct {
+	Alias         string
+	OpenGraphTags *input.OpenGraphTagsInput
+	TwitterTags   *input.TwitterTagsInput
+}
+
+// UpdateShortLinkMetaTags updates the social meta tags of a short link owned by
+// the user
+func (a AuthMutation) UpdateShortLinkMetaTags(args *UpdateShortLinkMetaTagsArgs) (*ShortLink, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	shortLink, err := a.metaTag.UpdateMetaTags(
+		args.Alias,
+		args.OpenGraphTags.CreateOpenGraphTags(),
+		args.TwitterTags.CreateTwitterTags(),
+		user,
+	)
+	if err == nil {
+		return &ShortLink{shortLink: shortLink}, nil
+	}
+
+	var (
+		nf shortlink.ErrShortLinkNotFound
+		mt shortlink.ErrInvalidMetaTag
+	)
+	if errors.As(err, &nf) {
+		return nil, ErrShortLinkNotFound(args.Alias)
+	}
+	if errors.As(err, &mt) {
+		return nil, ErrInvalidMetaTag{mt.Tag, string(mt.Violation)}
+	}
+	return nil, ErrUnknown{}
+}
+
 // ChangeInput represents possible properties for Change
 type ChangeInput struct {
 	Title           string
@@ -215,18 +293,594 @@ func (a AuthMutation) ViewChangeLog() (scalar.Time, error) {
 	return scalar.Time{Time: lastViewedAt}, err
 }
 
+// SignOut revokes the session of the current auth token
+func (a AuthMutation) SignOut() (bool, error) {
+	if a.authToken == nil {
+		return false, ErrInvalidAuthToken{}
+	}
+
+	err := a.authenticator.SignOut(*a.authToken)
+	if err != nil {
+		return false, ErrInvalidAuthToken{}
+	}
+	return true, nil
+}
+
+// SignOutEverywhere revokes all the sessions of the current user
+func (a AuthMutation) SignOutEverywhere() (bool, error) {
+	if a.authToken == nil {
+		return false, ErrInvalidAuthToken{}
+	}
+
+	err := a.authenticator.SignOutEverywhere(*a.authToken)
+	if err != nil {
+		return false, ErrInvalidAuthToken{}
+	}
+	return true, nil
+}
+
+// LinkAccountArgs represents the possible parameters for LinkAccount endpoint
+type LinkAccountArgs struct {
+	Provider string
+}
+
+// LinkAccount authorizes the current user to link an account of the given
+// identity provider. Returns the ticket to start signing in with.
+func (a AuthMutation) LinkAccount(args *LinkAccountArgs) (string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return "", ErrInvalidAuthToken{}
+	}
+
+	ticket, err := a.accountManager.IssueLinkTicket(user, args.Provider)
+	if err == nil {
+		return ticket, nil
+	}
+
+	var (
+		up sso.ErrUnknownProvider
+	)
+	if errors.As(err, &up) {
+		return "", ErrUnknownProvider(args.Provider)
+	}
+	return "", ErrUnknown{}
+}
+
+// UnlinkAccountArgs represents the possible parameters for UnlinkAccount
+// endpoint
+type UnlinkAccountArgs struct {
+	Provider string
+}
+
+// UnlinkAccount unlinks the account of the given identity provider from the
+// current user
+func (a AuthMutation) UnlinkAccount(args *UnlinkAccountArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.accountManager.UnlinkAccount(user, args.Provider)
+	if err == nil {
+		return &args.Provider, nil
+	}
+
+	var (
+		up sso.ErrUnknownProvider
+		nl sso.ErrAccountNotLinked
+		l  sso.ErrLastLinkedAccount
+	)
+	if errors.As(err, &up) {
+		return nil, ErrUnknownProvider(args.Provider)
+	}
+	if errors.As(err, &nl) {
+		return nil, ErrAccountNotLinked(args.Provider)
+	}
+	if errors.As(err, &l) {
+		return nil, ErrLastLinkedAccount(args.Provider)
+	}
+	return nil, ErrUnknown{}
+}
+
+// MergeUserArgs represents the possible parameters for MergeUser endpoint
+type MergeUserArgs struct {
+	MergeTicket string
+}
+
+// MergeUser moves everything owned by the other user in the merge ticket to
+// the current user. Returns the ID of the removed user.
+func (a AuthMutation) MergeUser(args *MergeUserArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	sourceUserID, err := a.accountManager.MergeUser(user, args.MergeTicket)
+	if err == nil {
+		return &sourceUserID, nil
+	}
+
+	var (
+		it sso.ErrInvalidTicket
+		mc sso.ErrMergeConflict
+		pu sso.ErrProtectedUser
+	)
+	if errors.As(err, &it) {
+		return nil, ErrInvalidTicket{}
+	}
+	if errors.As(err, &mc) {
+		return nil, ErrMergeConflict(string(mc))
+	}
+	if errors.As(err, &pu) {
+		return nil, ErrProtectedUser{}
+	}
+	return nil, ErrUnknown{}
+}
+
+// EnrollTwoFactor generates a new secret and recovery codes for the current
+// user. The second factor is enabled once confirmed with ConfirmTwoFactor.
+func (a AuthMutation) EnrollTwoFactor() (*TwoFactorEnrollment, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	enrollment, err := a.twoFactor.Enroll(user)
+	if err == nil {
+		return &TwoFactorEnrollment{enrollment: enrollment}, nil
+	}
+	return nil, newTwoFactorError(err)
+}
+
+// ConfirmTwoFactorArgs represents the possible parameters for
+// ConfirmTwoFactor endpoint
+type ConfirmTwoFactorArgs struct {
+	Code string
+}
+
+// ConfirmTwoFactor enables the second factor enrolled by the current user
+// with a password from the authenticator app. Returns the ID of the user.
+func (a AuthMutation) ConfirmTwoFactor(args *ConfirmTwoFactorArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.twoFactor.ConfirmEnrollment(user, args.Code)
+	if err == nil {
+		return &user.ID, nil
+	}
+	return nil, newTwoFactorError(err)
+}
+
+// DisableTwoFactorArgs represents the possible parameters for
+// DisableTwoFactor endpoint
+type DisableTwoFactorArgs struct {
+	Code string
+}
+
+// DisableTwoFactor removes the second factor of the current user unless the
+// roles of the user require one. Returns the ID of the user.
+func (a AuthMutation) DisableTwoFactor(args *DisableTwoFactorArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.twoFactor.Disable(user, args.Code)
+	if err == nil {
+		return &user.ID, nil
+	}
+	return nil, newTwoFactorError(err)
+}
+
+// RevokeSessionArgs represents the possible parameters for RevokeSession
+// endpoint
+type RevokeSessionArgs struct {
+	ID string
+}
+
+// RevokeSession signs out of a session of the current user, or of any user
+// given the permission
+func (a AuthMutation) RevokeSession(args *RevokeSessionArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.sessionManager.RevokeSession(user, args.ID)
+	if err == nil {
+		return &args.ID, nil
+	}
+
+	var (
+		nf session.ErrSessionNotFound
+		u  session.ErrUnauthorizedAction
+	)
+	if errors.As(err, &nf) {
+		return nil, ErrSessionNotFound(args.ID)
+	}
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to revoke the session %s", user.ID, args.ID))
+	}
+	return nil, ErrUnknown{}
+}
+
+// GrantRoleArgs represents the possible parameters for GrantRole endpoint
+type GrantRoleArgs struct {
+	UserID string
+	Role   string
+}
+
+// GrantRole grants a role to a given user. Returns the ID of the user.
+func (a AuthMutation) GrantRole(args *GrantRoleArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.roleManager.GrantRole(user, args.UserID, role.Role(args.Role))
+	if err == nil {
+		return &args.UserID, nil
+	}
+	return nil, newUserRoleError(err, user, fmt.Sprintf("grant role %s to user %s", args.Role, args.UserID))
+}
+
+// RevokeRoleArgs represents the possible parameters for RevokeRole endpoint
+type RevokeRoleArgs struct {
+	UserID string
+	Role   string
+}
+
+// RevokeRole revokes a role from a given user unless the user is the last
+// admin. Returns the ID of the user.
+func (a AuthMutation) RevokeRole(args *RevokeRoleArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.roleManager.RevokeRole(user, args.UserID, role.Role(args.Role))
+	if err == nil {
+		return &args.UserID, nil
+	}
+	return nil, newUserRoleError(err, user, fmt.Sprintf("revoke role %s from user %s", args.Role, args.UserID))
+}
+
+// CreateRoleArgs represents the possible parameters for CreateRole endpoint
+type CreateRoleArgs struct {
+	Name        string
+	Permissions []string
+}
+
+// CreateRole creates a custom role granting the given permissions.
+func (a AuthMutation) CreateRole(args *CreateRoleArgs) (*Role, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	definition, err := a.customRoles.CreateRole(user, args.Name, args.Permissions)
+	if err == nil {
+		return &Role{definition: definition}, nil
+	}
+	return nil, newCustomRoleError(err, user, fmt.Sprintf("create role %s", args.Name))
+}
+
+// UpdateRoleArgs represents the possible parameters for UpdateRole endpoint
+type UpdateRoleArgs struct {
+	Name        string
+	Permissions []string
+}
+
+// UpdateRole replaces the permissions granted by a given custom role.
+func (a AuthMutation) UpdateRole(args *UpdateRoleArgs) (*Role, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	definition, err := a.customRoles.UpdateRole(user, args.Name, args.Permissions)
+	if err == nil {
+		return &Role{definition: definition}, nil
+	}
+	return nil, newCustomRoleError(err, user, fmt.Sprintf("update role %s", args.Name))
+}
+
+// DeleteRoleArgs represents the possible parameters for DeleteRole endpoint
+type DeleteRoleArgs struct {
+	Name string
+}
+
+// DeleteRole deletes a given custom role and revokes it from all of its
+// users. Returns the name of the role.
+func (a AuthMutation) DeleteRole(args *DeleteRoleArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.customRoles.DeleteRole(user, args.Name)
+	if err == nil {
+		return &args.Name, nil
+	}
+	return nil, newCustomRoleError(err, user, fmt.Sprintf("delete role %s", args.Name))
+}
+
+// ShortLinkAttributeArgs represents the possible parameters for
+// AddShortLinkAttribute and RemoveShortLinkAttribute endpoints
+type ShortLinkAttributeArgs struct {
+	Alias string
+	Name  string
+	Value string
+}
+
+// AddShortLinkAttribute adds a value to an attribute of a given short link.
+// Returns all the attributes of the short link.
+func (a AuthMutation) AddShortLinkAttribute(args *ShortLinkAttributeArgs) ([]ShortLinkAttribute, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return []ShortLinkAttribute{}, ErrInvalidAuthToken{}
+	}
+
+	attributes, err := a.shortLinkAttribute.AddAttribute(args.Alias, args.Name, args.Value, user)
+	if err == nil {
+		return newShortLinkAttributes(attributes), nil
+	}
+	return []ShortLinkAttribute{}, newShortLinkAttributeError(
+		err,
+		user,
+		args.Alias,
+		fmt.Sprintf("add attributes to short link %s", args.Alias),
+	)
+}
+
+// RemoveShortLinkAttribute removes a value from an attribute of a given short
+// link. Returns all the attributes of the short link.
+func (a AuthMutation) RemoveShortLinkAttribute(args *ShortLinkAttributeArgs) ([]ShortLinkAttribute, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return []ShortLinkAttribute{}, ErrInvalidAuthToken{}
+	}
+
+	attributes, err := a.shortLinkAttribute.RemoveAttribute(args.Alias, args.Name, args.Value, user)
+	if err == nil {
+		return newShortLinkAttributes(attributes), nil
+	}
+	return []ShortLinkAttribute{}, newShortLinkAttributeError(
+		err,
+		user,
+		args.Alias,
+		fmt.Sprintf("remove attributes from short link %s", args.Alias),
+	)
+}
+
+// CreateAppArgs represents the possible parameters for CreateApp endpoint
+type CreateAppArgs struct {
+	Name string
+}
+
+// CreateApp registers a new third party app owned by the user
+func (a AuthMutation) CreateApp(args *CreateAppArgs) (*App, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	app, err := a.appRegistry.CreateApp(args.Name, user)
+	if err == nil {
+		gqlApp := newApp(app)
+		return &gqlApp, nil
+	}
+
+	var (
+		n thirdparty.ErrInvalidAppName
+	)
+	if errors.As(err, &n) {
+		return nil, ErrInvalidAppName(args.Name)
+	}
+	return nil, ErrUnknown{}
+}
+
+// RenameAppArgs represents the possible parameters for RenameApp endpoint
+type RenameAppArgs struct {
+	ID   string
+	Name string
+}
+
+// RenameApp changes the name of a third party app owned by the user
+func (a AuthMutation) RenameApp(args *RenameAppArgs) (*App, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	app, err := a.appRegistry.RenameApp(args.ID, args.Name, user)
+	if err == nil {
+		gqlApp := newApp(app)
+		return &gqlApp, nil
+	}
+
+	var (
+		n  thirdparty.ErrInvalidAppName
+		nf thirdparty.ErrAppNotFound
+		u  thirdparty.ErrUnauthorizedAction
+	)
+	if errors.As(err, &n) {
+		return nil, ErrInvalidAppName(args.Name)
+	}
+	if errors.As(err, &nf) {
+		return nil, ErrAppNotFound(args.ID)
+	}
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to rename the app %s", user.ID, args.ID))
+	}
+	return nil, ErrUnknown{}
+}
+
+// DeleteAppArgs represents the possible parameters for DeleteApp endpoint
+type DeleteAppArgs struct {
+	ID string
+}
+
+// DeleteApp removes a third party app owned by the user together with its API
+// keys
+func (a AuthMutation) DeleteApp(args *DeleteAppArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	err = a.appRegistry.DeleteApp(args.ID, user)
+	if err == nil {
+		return &args.ID, nil
+	}
+
+	var (
+		nf thirdparty.ErrAppNotFound
+		u  thirdparty.ErrUnauthorizedAction
+	)
+	if errors.As(err, &nf) {
+		return nil, ErrAppNotFound(args.ID)
+	}
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to delete the app %s", user.ID, args.ID))
+	}
+	return nil, ErrUnknown{}
+}
+
+// CreateAPIKeyArgs represents the possible parameters for CreateAPIKey endpoint
+type CreateAPIKeyArgs struct {
+	AppID    string
+	Scopes   []string
+	ExpireAt *scalar.Time
+}
+
+// CreateAPIKey issues a new API key to a given app
+func (a AuthMutation) CreateAPIKey(args *CreateAPIKeyArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	var expireAt *time.Time
+	if args.ExpireAt != nil {
+		expireAt = &args.ExpireAt.Time
+	}
+
+	app := entity.App{ID: args.AppID}
+	apiKey, err := a.thirdPartyApp.GenerateAPIKey(user, app, newScopes(args.Scopes), expireAt)
+	if err == nil {
+		return &apiKey, nil
+	}
+
+	var (
+		u authenticator.ErrUnauthorizedAction
+		s authenticator.ErrInvalidScope
+	)
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to create api key for app %s", user.ID, args.AppID))
+	}
+	if errors.As(err, &s) {
+		return nil, ErrInvalidScope(s.Scope)
+	}
+	return nil, ErrUnknown{}
+}
+
+// RevokeAPIKeyArgs represents the possible parameters for RevokeAPIKey endpoint
+type RevokeAPIKeyArgs struct {
+	AppID string
+	Key   string
+}
+
+// RevokeAPIKey disables an API key of a given app immediately
+func (a AuthMutation) RevokeAPIKey(args *RevokeAPIKeyArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	app := entity.App{ID: args.AppID}
+	err = a.thirdPartyApp.RevokeAPIKey(user, app, args.Key)
+	if err == nil {
+		return &args.Key, nil
+	}
+
+	var (
+		u authenticator.ErrUnauthorizedAction
+	)
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to revoke api key of app %s", user.ID, args.AppID))
+	}
+	return nil, ErrUnknown{}
+}
+
+// RotateAPIKeyArgs represents the possible parameters for RotateAPIKey endpoint
+type RotateAPIKeyArgs struct {
+	AppID              string
+	Key                string
+	Scopes             []string
+	GracePeriodSeconds int32
+}
+
+// RotateAPIKey replaces an API key of a given app with a new one. The old key
+// keeps working until the grace period ends.
+func (a AuthMutation) RotateAPIKey(args *RotateAPIKeyArgs) (*string, error) {
+	user, err := viewer(a.authToken, a.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	app := entity.App{ID: args.AppID}
+	gracePeriod := time.Duration(args.GracePeriodSeconds) * time.Second
+	apiKey, err := a.thirdPartyApp.RotateAPIKey(user, app, args.Key, newScopes(args.Scopes), gracePeriod)
+	if err == nil {
+		return &apiKey, nil
+	}
+
+	var (
+		u authenticator.ErrUnauthorizedAction
+		s authenticator.ErrInvalidScope
+	)
+	if errors.As(err, &u) {
+		return nil, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to rotate api key of app %s", user.ID, args.AppID))
+	}
+	if errors.As(err, &s) {
+		return nil, ErrInvalidScope(s.Scope)
+	}
+	return nil, ErrUnknown{}
+}
+
 func newAuthMutation(
 	authToken *string,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	appRegistry thirdparty.Registry,
 	changeLog changelog.ChangeLog,
 	shortLinkCreator shortlink.Creator,
 	shortLinkUpdater shortlink.Updater,
+	metaTag shortlink.MetaTag,
+	sessionManager session.Manager,
+	accountManager sso.AccountManager,
+	twoFactor twofactor.TwoFactor,
+	roleManager userrole.Manager,
+	customRoles customrole.Manager,
+	shortLinkAttribute shortlink.Attribute,
 ) AuthMutation {
 	return AuthMutation{
-		authToken:        authToken,
-		authenticator:    authenticator,
-		changeLog:        changeLog,
-		shortLinkCreator: shortLinkCreator,
-		shortLinkUpdater: shortLinkUpdater,
+		authToken:          authToken,
+		authenticator:      authenticator,
+		thirdPartyApp:      thirdPartyApp,
+		appRegistry:        appRegistry,
+		changeLog:          changeLog,
+		shortLinkCreator:   shortLinkCreator,
+		shortLinkUpdater:   shortLinkUpdater,
+		metaTag:            metaTag,
+		sessionManager:     sessionManager,
+		accountManager:     accountManager,
+		twoFactor:          twoFactor,
+		roleManager:        roleManager,
+		customRoles:        customRoles,
+		shortLinkAttribute: shortLinkAttribute,
 	}
 }
@@ -1,13 +1,22 @@
 package resolver
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 // AuthQuery represents GraphQL query resolver that acts differently based
@@ -15,8 +24,18 @@ import (
 type AuthQuery struct {
 	authToken          *string
 	authenticator      authenticator.Authenticator
+	thirdPartyApp      authenticator.ThirdPartyApp
+	appRegistry        thirdparty.Registry
+	linkQuota          quota.Quota
 	changeLog          changelog.ChangeLog
 	shortLinkRetriever shortlink.Retriever
+	sessionManager     session.Manager
+	accountManager     sso.AccountManager
+	twoFactor          twofactor.TwoFactor
+	roleManager        userrole.Manager
+	customRoles        customrole.Manager
+	shortLinkAnalytics shortlink.Analytics
+	shortLinkAttribute shortlink.Attribute
 }
 
 // ShortLinkArgs represents possible parameters for ShortLink endpoint
@@ -95,16 +114,277 @@ func (v AuthQuery) ShortLinks() ([]ShortLink, error) {
 	return gqlShortLinks, nil
 }
 
+// Viewer retrieves the information of the current user
+func (v AuthQuery) Viewer() (Viewer, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return Viewer{}, ErrInvalidAuthToken{}
+	}
+	return newViewer(user, v.linkQuota), nil
+}
+
+// Apps retrieves third party apps owned by the current user
+func (v AuthQuery) Apps() ([]App, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []App{}, ErrInvalidAuthToken{}
+	}
+
+	apps, err := v.appRegistry.GetAppsByOwner(user)
+	if err != nil {
+		return []App{}, ErrUnknown{}
+	}
+
+	gqlApps := []App{}
+	for _, app := range apps {
+		gqlApps = append(gqlApps, newApp(app))
+	}
+	return gqlApps, nil
+}
+
+// APIKeysArgs represents possible parameters for APIKeys endpoint
+type APIKeysArgs struct {
+	AppID string
+}
+
+// APIKeys retrieves all the API keys issued to a given app
+func (v AuthQuery) APIKeys(args *APIKeysArgs) ([]APIKey, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []APIKey{}, ErrInvalidAuthToken{}
+	}
+
+	app := entity.App{ID: args.AppID}
+	apiKeys, err := v.thirdPartyApp.ListAPIKeys(user, app)
+	if err == nil {
+		gqlAPIKeys := []APIKey{}
+		for _, apiKey := range apiKeys {
+			gqlAPIKeys = append(gqlAPIKeys, newAPIKey(apiKey))
+		}
+		return gqlAPIKeys, nil
+	}
+
+	var (
+		u authenticator.ErrUnauthorizedAction
+	)
+	if errors.As(err, &u) {
+		return []APIKey{}, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to view api keys of app %s", user.ID, args.AppID))
+	}
+	return []APIKey{}, ErrUnknown{}
+}
+
+// Sessions retrieves the places where the current user is signed in
+func (v AuthQuery) Sessions() ([]Session, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []Session{}, ErrInvalidAuthToken{}
+	}
+
+	sessions, err := v.sessionManager.GetActiveSessions(user)
+	if err != nil {
+		return []Session{}, ErrUnknown{}
+	}
+	return newSessions(sessions), nil
+}
+
+// UserSessionsArgs represents possible parameters for UserSessions endpoint
+type UserSessionsArgs struct {
+	UserID string
+}
+
+// UserSessions retrieves all the sessions of a given user for security review
+func (v AuthQuery) UserSessions(args *UserSessionsArgs) ([]Session, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []Session{}, ErrInvalidAuthToken{}
+	}
+
+	sessions, err := v.sessionManager.GetUserSessions(user, args.UserID)
+	if err == nil {
+		return newSessions(sessions), nil
+	}
+
+	var (
+		u session.ErrUnauthorizedAction
+	)
+	if errors.As(err, &u) {
+		return []Session{}, ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to view sessions of user %s", user.ID, args.UserID))
+	}
+	return []Session{}, ErrUnknown{}
+}
+
+// LinkedAccounts retrieves the accounts of identity providers the current
+// user can sign in with
+func (v AuthQuery) LinkedAccounts() ([]LinkedAccount, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []LinkedAccount{}, ErrInvalidAuthToken{}
+	}
+
+	linkedAccounts, err := v.accountManager.GetLinkedAccounts(user)
+	if err != nil {
+		return []LinkedAccount{}, ErrUnknown{}
+	}
+	return newLinkedAccounts(linkedAccounts), nil
+}
+
+// TwoFactor retrieves whether the current user signs in with a second factor
+// and whether the roles of the user require one
+func (v AuthQuery) TwoFactor() (*TwoFactorStatus, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	status, err := v.twoFactor.GetStatus(user)
+	if err != nil {
+		return nil, ErrUnknown{}
+	}
+	return &TwoFactorStatus{status: status}, nil
+}
+
+// UsersArgs represents possible parameters for Users endpoint
+type UsersArgs struct {
+	After *string
+	First *int32
+}
+
+// Users retrieves users ordered by ID together with their roles
+func (v AuthQuery) Users(args *UsersArgs) ([]User, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []User{}, ErrInvalidAuthToken{}
+	}
+
+	afterID := ""
+	if args.After != nil {
+		afterID = *args.After
+	}
+	limit := userrole.MaxUsersPerPage
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+
+	userRoles, err := v.roleManager.GetUsers(user, afterID, limit)
+	if err == nil {
+		return newUsers(userRoles), nil
+	}
+	return []User{}, newUserRoleError(err, user, "view users")
+}
+
+// RoleChangesArgs represents possible parameters for RoleChanges endpoint
+type RoleChangesArgs struct {
+	UserID string
+}
+
+// RoleChanges retrieves the audit log of the role changes of a given user
+func (v AuthQuery) RoleChanges(args *RoleChangesArgs) ([]RoleChange, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []RoleChange{}, ErrInvalidAuthToken{}
+	}
+
+	roleChanges, err := v.roleManager.GetRoleChanges(user, args.UserID)
+	if err == nil {
+		return newRoleChanges(roleChanges), nil
+	}
+	return []RoleChange{}, newUserRoleError(err, user, fmt.Sprintf("view role changes of user %s", args.UserID))
+}
+
+// Roles retrieves all the roles, with the built-in ones first
+func (v AuthQuery) Roles() ([]Role, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []Role{}, ErrInvalidAuthToken{}
+	}
+
+	definitions, err := v.customRoles.GetRoles(user)
+	if err == nil {
+		return newRoles(definitions), nil
+	}
+	return []Role{}, newCustomRoleError(err, user, "view roles")
+}
+
+// ShortLinkAnalyticsArgs represents possible parameters for ShortLinkAnalytics
+// endpoint
+type ShortLinkAnalyticsArgs struct {
+	Alias string
+}
+
+// ShortLinkAnalytics retrieves how often a given short link has been visited
+func (v AuthQuery) ShortLinkAnalytics(args *ShortLinkAnalyticsArgs) (*ShortLinkAnalytics, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return nil, ErrInvalidAuthToken{}
+	}
+
+	analytics, err := v.shortLinkAnalytics.GetAnalytics(args.Alias, user)
+	if err == nil {
+		return &ShortLinkAnalytics{analytics: analytics}, nil
+	}
+
+	var nf shortlink.ErrShortLinkNotFound
+	if errors.As(err, &nf) {
+		return nil, ErrShortLinkNotFound(args.Alias)
+	}
+	return nil, ErrUnknown{}
+}
+
+// ShortLinkAttributesArgs represents possible parameters for
+// ShortLinkAttributes endpoint
+type ShortLinkAttributesArgs struct {
+	Alias string
+}
+
+// ShortLinkAttributes retrieves the attributes of a given short link
+func (v AuthQuery) ShortLinkAttributes(args *ShortLinkAttributesArgs) ([]ShortLinkAttribute, error) {
+	user, err := viewer(v.authToken, v.authenticator)
+	if err != nil {
+		return []ShortLinkAttribute{}, ErrInvalidAuthToken{}
+	}
+
+	attributes, err := v.shortLinkAttribute.GetAttributes(args.Alias, user)
+	if err == nil {
+		return newShortLinkAttributes(attributes), nil
+	}
+	return []ShortLinkAttribute{}, newShortLinkAttributeError(
+		err,
+		user,
+		args.Alias,
+		fmt.Sprintf("view attributes of short link %s", args.Alias),
+	)
+}
+
 func newAuthQuery(
 	authToken *string,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	appRegistry thirdparty.Registry,
+	linkQuota quota.Quota,
 	changeLog changelog.ChangeLog,
 	shortLinkRetriever shortlink.Retriever,
+	sessionManager session.Manager,
+	accountManager sso.AccountManager,
+	twoFactor twofactor.TwoFactor,
+	roleManager userrole.Manager,
+	customRoles customrole.Manager,
+	shortLinkAnalytics shortlink.Analytics,
+	shortLinkAttribute shortlink.Attribute,
 ) AuthQuery {
 	return AuthQuery{
 		authToken:          authToken,
 		authenticator:      authenticator,
+		thirdPartyApp:      thirdPartyApp,
+		appRegistry:        appRegistry,
+		linkQuota:          linkQuota,
 		changeLog:          changeLog,
 		shortLinkRetriever: shortLinkRetriever,
+		sessionManager:     sessionManager,
+		accountManager:     accountManager,
+		twoFactor:          twoFactor,
+		roleManager:        roleManager,
+		customRoles:        customRoles,
+		shortLinkAnalytics: shortLinkAnalytics,
+		shortLinkAttribute: shortLinkAttribute,
 	}
 }
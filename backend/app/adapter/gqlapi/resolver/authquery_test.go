@@ -8,17 +8,30 @@ import (
 
 	"github.com/short-d/app/fw/assert"
 	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 type shortLinkMap = map[string]entity.ShortLink
@@ -91,9 +104,10 @@ func TestAuthQuery_ShortLink(t *testing.T) {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
-			fakeShortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
+			fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
 			fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
-			retrieverFake := shortlink.NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo)
+			fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			retrieverFake := shortlink.NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
 
 			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
 			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
@@ -110,12 +124,24 @@ func TestAuthQuery_ShortLink(t *testing.T) {
 			changeLog := changelog.NewPersist(keyGen, timerFake, &changeLogRepo, &userChangeLogRepo, au)
 
 			tokenizer := crypto.NewTokenizerFake()
-			auth := authenticator.NewAuthenticator(tokenizer, timerFake, time.Hour)
+			auth := authenticator.NewAuthenticatorFake(now, time.Hour)
 
-			authToken, err := auth.GenerateToken(testCase.user)
+			authTokens, err := auth.SignIn(testCase.user, "github", entity.Device{})
 			assert.Equal(t, nil, err)
+			authToken := authTokens.AccessToken
 
-			query := newAuthQuery(&authToken, auth, changeLog, retrieverFake)
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{})
+			appRepo := repository.NewAppFake([]entity.App{})
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			thirdPartyApp := authenticator.NewThirdPartyApp(au, tokenizer, keyGen, timerFake, &apiKeyRepo, &appRepo, lg)
+
+			appRegistry := thirdparty.NewPersist(keyGen, timerFake, &appRepo)
+			linkQuota := quota.NewQuota(&fakeUserShortLinkRepo, &fakeAppShortLinkRepo, rb, timerFake, quota.Policy{})
+
+			query := newAuthQuery(&authToken, auth, thirdPartyApp, appRegistry, linkQuota, changeLog, retrieverFake, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
 
 			shortLinkArgs := &ShortLinkArgs{
 				Alias:       testCase.alias,
@@ -132,3 +158,358 @@ func TestAuthQuery_ShortLink(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthQuery_Viewer(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	user := entity.User{ID: "alpha"}
+	timerFake := timer.NewStub(now)
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	authTokens, err := auth.SignIn(user, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	authToken := authTokens.AccessToken
+
+	fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(
+		[]entity.User{user, user},
+		[]entity.ShortLink{
+			{Alias: "old", CreatedAt: ptr.Time(must.Time(t, "2020-04-30T08:02:16Z"))},
+			{Alias: "new", CreatedAt: ptr.Time(must.Time(t, "2020-05-01T08:00:00Z"))},
+		},
+	)
+	fakeRolesRepo := repository.NewUserRoleFake(map[string][]role.Role{
+		"alpha": {role.Premium},
+	})
+	fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+	linkQuota := quota.NewQuota(&fakeUserShortLinkRepo, &fakeAppShortLinkRepo, rbac.NewRBAC(fakeRolesRepo), timerFake, quota.Policy{
+		Default: quota.Limit{Daily: 5, Total: 10},
+		Roles: map[role.Role]quota.Limit{
+			role.Premium: {Daily: 50},
+		},
+	})
+
+	query := newAuthQuery(&authToken, auth, authenticator.ThirdPartyApp{}, nil, linkQuota, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	v, err := query.Viewer()
+	assert.Equal(t, nil, err)
+
+	q, err := v.Quota()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int32(1), q.DailyUsed())
+	assert.Equal(t, int32(50), *q.DailyLimit())
+	assert.Equal(t, int32(49), *q.DailyRemaining())
+	assert.Equal(t, must.Time(t, "2020-05-02T00:00:00Z"), q.DailyResetAt().Time)
+	assert.Equal(t, int32(2), q.TotalUsed())
+	assert.Equal(t, (*int32)(nil), q.TotalLimit())
+	assert.Equal(t, (*int32)(nil), q.TotalRemaining())
+
+	invalidToken := "invalid"
+	query = newAuthQuery(&invalidToken, auth, authenticator.ThirdPartyApp{}, nil, linkQuota, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	_, err = query.Viewer()
+	assert.Equal(t, ErrInvalidAuthToken{}, err)
+}
+
+func TestAuthQuery_Sessions(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	timerFake := timer.NewStub(now)
+	keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{"laptop", "phone", "tablet"})
+	keyGen, err := keygen.NewKeyGenerator(3, &keyFetcher)
+	assert.Equal(t, nil, err)
+
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	auth := authenticator.NewAuthenticator(
+		crypto.NewTokenizerFake(), timerFake, keyGen, &sessionRepo, time.Hour, 2*time.Hour,
+	)
+
+	laptop := entity.Device{
+		ClientIP:  "10.0.0.1",
+		Location:  "San Francisco, California, United States",
+		UserAgent: "Mozilla/5.0",
+	}
+	aliceTokens, err := auth.SignIn(entity.User{ID: "alice"}, "github", laptop)
+	assert.Equal(t, nil, err)
+	_, err = auth.SignIn(entity.User{ID: "alice"}, "google", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := auth.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	fakeRolesRepo := repository.NewUserRoleFake(map[string][]role.Role{
+		"bob": {role.SecuritySpecialist},
+	})
+	au := authorizer.NewAuthorizer(rbac.NewRBAC(fakeRolesRepo))
+	sessionManager := session.NewManager(&sessionRepo, au, timerFake)
+
+	err = sessionManager.RevokeSession(entity.User{ID: "alice"}, "phone")
+	assert.Equal(t, nil, err)
+
+	aliceQuery := newAuthQuery(&aliceTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, sessionManager, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	sessions, err := aliceQuery.Sessions()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, "laptop", sessions[0].ID())
+	assert.Equal(t, "github", sessions[0].IdentityProvider())
+	assert.Equal(t, laptop.ClientIP, sessions[0].ClientIP())
+	assert.Equal(t, laptop.Location, sessions[0].Location())
+	assert.Equal(t, laptop.UserAgent, sessions[0].UserAgent())
+	assert.Equal(t, now, sessions[0].CreatedAt().Time)
+	assert.Equal(t, (*scalar.Time)(nil), sessions[0].RevokedAt())
+
+	_, err = aliceQuery.UserSessions(&UserSessionsArgs{UserID: "bob"})
+	assert.NotEqual(t, nil, err)
+
+	bobQuery := newAuthQuery(&bobTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, sessionManager, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	sessions, err = bobQuery.UserSessions(&UserSessionsArgs{UserID: "alice"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(sessions))
+	assert.Equal(t, now, sessions[1].RevokedAt().Time)
+
+	invalidToken := "invalid"
+	query := newAuthQuery(&invalidToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, sessionManager, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	_, err = query.Sessions()
+	assert.Equal(t, ErrInvalidAuthToken{}, err)
+}
+
+func TestAuthQuery_LinkedAccounts(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	timerFake := timer.NewStub(now)
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	authTokens, err := auth.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	githubSSOMap, err := repository.NewsSSOMapFake([]string{"octocat"}, []string{"alpha"})
+	assert.Equal(t, nil, err)
+	googleSSOMap, err := repository.NewsSSOMapFake([]string{"110169484474386276334"}, []string{"alpha"})
+	assert.Equal(t, nil, err)
+	facebookSSOMap, err := repository.NewsSSOMapFake([]string{"10224"}, []string{"beta"})
+	assert.Equal(t, nil, err)
+
+	userRepo := repository.NewUserFake([]entity.User{})
+	twoFactorRepo := repository.NewTwoFactorFake([]entity.TwoFactor{})
+	accountManager := sso.NewAccountManager(
+		map[string]repository.SSOMap{
+			"github":   &githubSSOMap,
+			"google":   &googleSSOMap,
+			"facebook": &facebookSSOMap,
+		},
+		repository.NewUserMergerFake(&userRepo),
+		&twoFactorRepo,
+		rbac.NewRBAC(repository.NewUserRoleFake(map[string][]role.Role{})),
+		crypto.NewTokenizerFake(),
+		timerFake,
+	)
+
+	query := newAuthQuery(&authTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, accountManager, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	linkedAccounts, err := query.LinkedAccounts()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(linkedAccounts))
+	assert.Equal(t, "github", linkedAccounts[0].Provider())
+	assert.Equal(t, "octocat", linkedAccounts[0].SSOUserID())
+	assert.Equal(t, "google", linkedAccounts[1].Provider())
+	assert.Equal(t, "110169484474386276334", linkedAccounts[1].SSOUserID())
+
+	invalidToken := "invalid"
+	query = newAuthQuery(&invalidToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, accountManager, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	_, err = query.LinkedAccounts()
+	assert.Equal(t, ErrInvalidAuthToken{}, err)
+}
+
+func TestAuthQuery_TwoFactor(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	enforceFrom := must.Time(t, "2020-06-01T00:00:00Z")
+	timerFake := timer.NewStub(now)
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	authTokens, err := auth.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	twoFactorRepo := repository.NewTwoFactorFake([]entity.TwoFactor{})
+	userRepo := repository.NewUserFake([]entity.User{{ID: "alpha"}})
+	userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{
+		"alpha": {role.Admin},
+	})
+	twoFactor := twofactor.NewTwoFactor(
+		&twoFactorRepo,
+		&userRepo,
+		rbac.NewRBAC(userRoleRepo),
+		rbac.TwoFactorPolicy{Roles: []role.Role{role.Admin}, EnforceFrom: enforceFrom},
+		ratelimit.NewLimiter(ratelimit.NewMemoryStore(timerFake), timerFake),
+		auth,
+		crypto.NewTokenizerFake(),
+		timerFake,
+	)
+
+	query := newAuthQuery(&authTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twoFactor, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	status, err := query.TwoFactor()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, status.IsEnabled())
+	assert.Equal(t, true, status.IsRequired())
+	assert.Equal(t, &scalar.Time{Time: enforceFrom}, status.EnforceFrom())
+
+	invalidToken := "invalid"
+	query = newAuthQuery(&invalidToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twoFactor, userrole.Manager{}, customrole.Manager{}, nil, nil)
+	_, err = query.TwoFactor()
+	assert.Equal(t, ErrInvalidAuthToken{}, err)
+}
+
+func TestAuthQuery_Users(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := auth.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := auth.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	userRepo := repository.NewUserFake([]entity.User{
+		{ID: "alice", Email: "alice@example.com"},
+		{ID: "bob", Email: "bob@example.com"},
+	})
+	userRoles := map[string][]role.Role{"alice": {role.Admin}}
+	userRoleRepo := repository.NewUserRoleFake(userRoles)
+	roleAssignmentRepo := repository.NewRoleAssignmentFake(userRoles)
+	ac := rbac.NewRBAC(userRoleRepo)
+	roleManager := userrole.NewManager(&userRepo, &roleAssignmentRepo, ac, authorizer.NewAuthorizer(ac), timer.NewStub(now))
+	err = roleManager.GrantRole(entity.User{ID: "alice"}, "bob", role.Premium)
+	assert.Equal(t, nil, err)
+
+	aliceQuery := newAuthQuery(&aliceTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, roleManager, customrole.Manager{}, nil, nil)
+	first := int32(1)
+	users, err := aliceQuery.Users(&UsersArgs{First: &first})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, "alice", users[0].ID())
+	assert.Equal(t, []string{"admin"}, users[0].Roles())
+
+	users, err = aliceQuery.Users(&UsersArgs{After: ptr.String("alice")})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, "bob", users[0].ID())
+	assert.Equal(t, []string{"premium"}, users[0].Roles())
+
+	roleChanges, err := aliceQuery.RoleChanges(&RoleChangesArgs{UserID: "bob"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(roleChanges))
+	assert.Equal(t, "alice", roleChanges[0].ActorID())
+	assert.Equal(t, "granted", roleChanges[0].Action())
+
+	bobQuery := newAuthQuery(&bobTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, roleManager, customrole.Manager{}, nil, nil)
+	_, err = bobQuery.Users(&UsersArgs{})
+	assert.Equal(t, ErrUnauthorizedAction("user bob is not allowed to view users"), err)
+}
+
+func TestAuthQuery_Roles(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := auth.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := auth.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	userRoles := map[string][]role.Role{"alice": {role.Admin}}
+	roleRepo := repository.NewRoleFake([]role.Definition{
+		{Name: "editor", Permissions: []permission.Permission{permission.EditChange}},
+	}, userRoles)
+	definitions := rbac.NewRoleDefinitions(&roleRepo, timer.NewStub(now))
+	ac := rbac.NewCustomRBAC(repository.NewUserRoleFake(userRoles), definitions)
+	customRoles := customrole.NewManager(&roleRepo, definitions, authorizer.NewAuthorizer(ac), timer.NewStub(now))
+
+	aliceQuery := newAuthQuery(&aliceTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customRoles, nil, nil)
+	roles, err := aliceQuery.Roles()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(role.BuiltIns())+1, len(roles))
+	assert.Equal(t, "admin", roles[0].Name())
+	assert.Equal(t, true, roles[0].IsBuiltIn())
+	editor := roles[len(roles)-1]
+	assert.Equal(t, "editor", editor.Name())
+	assert.Equal(t, false, editor.IsBuiltIn())
+	assert.Equal(t, []string{"edit_change"}, editor.Permissions())
+
+	bobQuery := newAuthQuery(&bobTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customRoles, nil, nil)
+	_, err = bobQuery.Roles()
+	assert.Equal(t, ErrUnauthorizedAction("user bob is not allowed to view roles"), err)
+}
+
+func TestAuthQuery_ShortLinkAnalytics(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := auth.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := auth.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	shortLinkRepo := repository.NewShortLinkFake(nil, nil, shortLinkMap{"docs": {Alias: "docs"}})
+	userShortLinkRepo := repository.NewUserShortLinkRepoFake(
+		[]entity.User{{ID: "alice"}},
+		[]entity.ShortLink{{Alias: "docs"}},
+	)
+	attributeRepo := repository.NewShortLinkAttributeFake(nil)
+	visitRepo := repository.NewShortLinkVisitFake(nil)
+	au := authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(nil)))
+	analytics := shortlink.NewAnalyticsPersist(
+		&shortLinkRepo,
+		&visitRepo,
+		au,
+		policy.NewResourceFinder(&userShortLinkRepo, &attributeRepo),
+		timer.NewStub(now),
+	)
+	err = analytics.RecordVisit("docs")
+	assert.Equal(t, nil, err)
+
+	aliceQuery := newAuthQuery(&aliceTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, analytics, nil)
+	gotAnalytics, err := aliceQuery.ShortLinkAnalytics(&ShortLinkAnalyticsArgs{Alias: "docs"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int32(1), gotAnalytics.VisitCount())
+	assert.Equal(t, &scalar.Time{Time: now}, gotAnalytics.LastVisitedAt())
+
+	bobQuery := newAuthQuery(&bobTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, analytics, nil)
+	_, err = bobQuery.ShortLinkAnalytics(&ShortLinkAnalyticsArgs{Alias: "docs"})
+	assert.Equal(t, ErrShortLinkNotFound("docs"), err)
+}
+
+func TestAuthQuery_ShortLinkAttributes(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := auth.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := auth.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	shortLinkRepo := repository.NewShortLinkFake(nil, nil, shortLinkMap{"docs": {Alias: "docs"}})
+	attributeRepo := repository.NewShortLinkAttributeFake(map[string]map[string][]string{
+		"docs": {
+			"team": {"growth"},
+			"tag":  {"api", "public"},
+		},
+	})
+	au := authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(map[string][]role.Role{
+		"alice": {role.Admin},
+	})))
+	attribute := shortlink.NewAttributePersist(&shortLinkRepo, &attributeRepo, au)
+
+	aliceQuery := newAuthQuery(&aliceTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, attribute)
+	attributes, err := aliceQuery.ShortLinkAttributes(&ShortLinkAttributesArgs{Alias: "docs"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(attributes))
+	assert.Equal(t, "tag", attributes[0].Name())
+	assert.Equal(t, []string{"api", "public"}, attributes[0].Values())
+	assert.Equal(t, "team", attributes[1].Name())
+	assert.Equal(t, []string{"growth"}, attributes[1].Values())
+
+	_, err = aliceQuery.ShortLinkAttributes(&ShortLinkAttributesArgs{Alias: "missing"})
+	assert.Equal(t, ErrShortLinkNotFound("missing"), err)
+
+	bobQuery := newAuthQuery(&bobTokens.AccessToken, auth, authenticator.ThirdPartyApp{}, nil, quota.Quota{}, nil, nil, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, attribute)
+	_, err = bobQuery.ShortLinkAttributes(&ShortLinkAttributesArgs{Alias: "docs"})
+	assert.Equal(t, ErrUnauthorizedAction("user bob is not allowed to view attributes of short link docs"), err)
+}
@@ -0,0 +1,19 @@
+package resolver
+
+import "github.com/short-d/short/backend/app/usecase/authenticator"
+
+// AuthTokens retrieves requested fields of the tokens issued to a signed in
+// user.
+type AuthTokens struct {
+	authTokens authenticator.AuthTokens
+}
+
+// AccessToken retrieves the short lived token authenticating the user.
+func (a AuthTokens) AccessToken() string {
+	return a.authTokens.AccessToken
+}
+
+// RefreshToken retrieves the token which can be exchanged for new tokens.
+func (a AuthTokens) RefreshToken() string {
+	return a.authTokens.RefreshToken
+}
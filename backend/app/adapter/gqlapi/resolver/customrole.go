@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+)
+
+// Role retrieves requested fields of a role definition.
+type Role struct {
+	definition role.Definition
+}
+
+// Name retrieves the name of the role.
+func (r Role) Name() string {
+	return string(r.definition.Name)
+}
+
+// Permissions retrieves the names of the permissions granted by the role.
+func (r Role) Permissions() []string {
+	permissions := []string{}
+	for _, p := range r.definition.Permissions {
+		permissions = append(permissions, p.Name())
+	}
+	return permissions
+}
+
+// IsBuiltIn retrieves whether the role is built-in and can't be changed.
+func (r Role) IsBuiltIn() bool {
+	return r.definition.IsBuiltIn
+}
+
+func newRoles(definitions []role.Definition) []Role {
+	gqlRoles := []Role{}
+	for _, definition := range definitions {
+		gqlRoles = append(gqlRoles, Role{definition: definition})
+	}
+	return gqlRoles
+}
+
+func newCustomRoleError(err error, user entity.User, action string) error {
+	var (
+		u  customrole.ErrUnauthorizedAction
+		in customrole.ErrInvalidRoleName
+		up customrole.ErrUnknownPermission
+		re customrole.ErrRoleExists
+		nf customrole.ErrRoleNotFound
+		bi customrole.ErrBuiltInRole
+	)
+	if errors.As(err, &u) {
+		return ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to %s", user.ID, action))
+	}
+	if errors.As(err, &in) {
+		return ErrInvalidRoleName(in)
+	}
+	if errors.As(err, &up) {
+		return ErrUnknownPermission(up)
+	}
+	if errors.As(err, &re) {
+		return ErrRoleExists(re)
+	}
+	if errors.As(err, &nf) {
+		return ErrRoleNotFound(nf)
+	}
+	if errors.As(err, &bi) {
+		return ErrBuiltInRole(bi)
+	}
+	return ErrUnknown{}
+}
@@ -5,17 +5,50 @@ type ErrCode string
 
 // The constants enumerate all supported error codes.
 const (
-	ErrCodeUnknown            ErrCode = "unknown"
-	ErrCodeAliasAlreadyExist          = "aliasAlreadyExist"
-	ErrCodeShortLinkNotFound          = "shortLinkNotFound"
-	ErrCodeEmptyAlias                 = "emptyAlias"
-	ErrCodeRequesterNotHuman          = "requesterNotHuman"
-	ErrCodeInvalidLongLink            = "invalidLongLink"
-	ErrCodeInvalidCustomAlias         = "invalidCustomAlias"
-	ErrCodeAliasWithFragment          = "aliasWithFragment"
-	ErrCodeMaliciousContent           = "maliciousContent"
-	ErrCodeInvalidAuthToken           = "invalidAuthToken"
-	ErrCodeUnauthorizedAction         = "unauthorizedAction"
+	ErrCodeUnknown             ErrCode = "unknown"
+	ErrCodeAliasAlreadyExist           = "aliasAlreadyExist"
+	ErrCodeShortLinkNotFound           = "shortLinkNotFound"
+	ErrCodeEmptyAlias                  = "emptyAlias"
+	ErrCodeRequesterNotHuman           = "requesterNotHuman"
+	ErrCodeInvalidLongLink             = "invalidLongLink"
+	ErrCodeInvalidCustomAlias          = "invalidCustomAlias"
+	ErrCodeAliasWithFragment           = "aliasWithFragment"
+	ErrCodeMaliciousContent            = "maliciousContent"
+	ErrCodeInvalidAuthToken            = "invalidAuthToken"
+	ErrCodeUnauthorizedAction          = "unauthorizedAction"
+	ErrCodeRedirectLoop                = "redirectLoop"
+	ErrCodeInvalidMetaTag              = "invalidMetaTag"
+	ErrCodeInvalidScope                = "invalidScope"
+	ErrCodeAppNotFound                 = "appNotFound"
+	ErrCodeInvalidAppName              = "invalidAppName"
+	ErrCodeQuotaExceeded               = "quotaExceeded"
+	ErrCodeInvalidRefreshToken         = "invalidRefreshToken"
+	ErrCodeSessionNotFound             = "sessionNotFound"
+	ErrCodeUnknownProvider             = "unknownProvider"
+	ErrCodeAccountNotLinked            = "accountNotLinked"
+	ErrCodeLastLinkedAccount           = "lastLinkedAccount"
+	ErrCodeInvalidTicket               = "invalidTicket"
+	ErrCodeMergeConflict               = "mergeConflict"
+	ErrCodeProtectedUser               = "protectedUser"
+	ErrCodeInvalidChallenge            = "invalidChallenge"
+	ErrCodeInvalidCode                 = "invalidCode"
+	ErrCodeTooManyAttempts             = "tooManyAttempts"
+	ErrCodeTwoFactorEnabled            = "twoFactorEnabled"
+	ErrCodeNotEnrolled                 = "notEnrolled"
+	ErrCodeTwoFactorRequired           = "twoFactorRequired"
+	ErrCodeUserNotFound                = "userNotFound"
+	ErrCodeUnknownRole                 = "unknownRole"
+	ErrCodeRoleAlreadyGranted          = "roleAlreadyGranted"
+	ErrCodeRoleNotGranted              = "roleNotGranted"
+	ErrCodeLastAdmin                   = "lastAdmin"
+	ErrCodeInvalidRoleName             = "invalidRoleName"
+	ErrCodeUnknownPermission           = "unknownPermission"
+	ErrCodeRoleExists                  = "roleExists"
+	ErrCodeRoleNotFound                = "roleNotFound"
+	ErrCodeBuiltInRole                 = "builtInRole"
+	ErrCodeTooManyRedirects            = "tooManyRedirects"
+	ErrCodeInvalidAttribute            = "invalidAttribute"
+	ErrCodeAttributeNotFound           = "attributeNotFound"
 )
 
 // GraphQLError represents a GraphAPI error.
@@ -218,3 +251,660 @@ func (e ErrUnauthorizedAction) Extensions() map[string]interface{} {
 func (e ErrUnauthorizedAction) Error() string {
 	return "unauthorized action"
 }
+
+// ErrRedirectLoop signifies the long link redirects back to the short link
+// through other short links.
+type ErrRedirectLoop string
+
+var _ GraphQLError = (*ErrRedirectLoop)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrRedirectLoop) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeRedirectLoop,
+		"longLink": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrRedirectLoop) Error() string {
+	return "long link redirects back to short link"
+}
+
+// ErrTooManyRedirects signifies the long link goes through too many short
+// links before reaching its destination.
+type ErrTooManyRedirects string
+
+var _ GraphQLError = (*ErrTooManyRedirects)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrTooManyRedirects) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeTooManyRedirects,
+		"longLink": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrTooManyRedirects) Error() string {
+	return "long link goes through too many short links"
+}
+
+// ErrInvalidMetaTag signifies that the provided social meta tag cannot be
+// used.
+type ErrInvalidMetaTag struct {
+	tag       string
+	violation string
+}
+
+var _ GraphQLError = (*ErrInvalidMetaTag)(nil)
+
+// Extensions keeps structured error metadata so that the clients can gracefully
+// handle the error.
+func (e ErrInvalidMetaTag) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      ErrCodeInvalidMetaTag,
+		"tag":       e.tag,
+		"violation": e.violation,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidMetaTag) Error() string {
+	return "meta tag is invalid"
+}
+
+// ErrInvalidScope signifies that the requested API key scope is not supported.
+type ErrInvalidScope string
+
+var _ GraphQLError = (*ErrInvalidScope)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidScope) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":  ErrCodeInvalidScope,
+		"scope": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidScope) Error() string {
+	return "scope is not supported"
+}
+
+// ErrAppNotFound signifies that the third party app with given ID does not
+// exist.
+type ErrAppNotFound string
+
+var _ GraphQLError = (*ErrAppNotFound)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrAppNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":  ErrCodeAppNotFound,
+		"appID": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrAppNotFound) Error() string {
+	return "app not found"
+}
+
+// ErrInvalidAppName signifies that the app name is empty or too long.
+type ErrInvalidAppName string
+
+var _ GraphQLError = (*ErrInvalidAppName)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidAppName) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidAppName,
+		"name": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidAppName) Error() string {
+	return "app name is invalid"
+}
+
+// ErrQuotaExceeded signifies that the user used up the quota of creating
+// short links.
+type ErrQuotaExceeded struct {
+	period string
+	limit  int
+}
+
+var _ GraphQLError = (*ErrQuotaExceeded)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrQuotaExceeded) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   ErrCodeQuotaExceeded,
+		"period": e.period,
+		"limit":  e.limit,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrQuotaExceeded) Error() string {
+	return "short link quota exceeded"
+}
+
+// ErrInvalidRefreshToken signifies the provided refresh token is malformed,
+// already used or belongs to a revoked session.
+type ErrInvalidRefreshToken struct{}
+
+var _ GraphQLError = (*ErrInvalidRefreshToken)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidRefreshToken) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidRefreshToken,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidRefreshToken) Error() string {
+	return "refresh token is invalid"
+}
+
+// ErrSessionNotFound signifies that the session with given ID does not exist.
+type ErrSessionNotFound string
+
+var _ GraphQLError = (*ErrSessionNotFound)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrSessionNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      ErrCodeSessionNotFound,
+		"sessionID": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrSessionNotFound) Error() string {
+	return "session not found"
+}
+
+// ErrUnknownProvider signifies that the identity provider is not supported.
+type ErrUnknownProvider string
+
+var _ GraphQLError = (*ErrUnknownProvider)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrUnknownProvider) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeUnknownProvider,
+		"provider": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrUnknownProvider) Error() string {
+	return "identity provider not supported"
+}
+
+// ErrAccountNotLinked signifies that the user has no account of the identity
+// provider linked.
+type ErrAccountNotLinked string
+
+var _ GraphQLError = (*ErrAccountNotLinked)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrAccountNotLinked) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeAccountNotLinked,
+		"provider": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrAccountNotLinked) Error() string {
+	return "account not linked"
+}
+
+// ErrLastLinkedAccount signifies that unlinking the account would leave the
+// user unable to sign in.
+type ErrLastLinkedAccount string
+
+var _ GraphQLError = (*ErrLastLinkedAccount)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrLastLinkedAccount) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeLastLinkedAccount,
+		"provider": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrLastLinkedAccount) Error() string {
+	return "can't unlink the last linked account"
+}
+
+// ErrInvalidTicket signifies that the provided ticket is malformed, expired
+// or issued to another user.
+type ErrInvalidTicket struct{}
+
+var _ GraphQLError = (*ErrInvalidTicket)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidTicket) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidTicket,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidTicket) Error() string {
+	return "ticket is invalid"
+}
+
+// ErrMergeConflict signifies that both users linked an account of the same
+// identity provider.
+type ErrMergeConflict string
+
+var _ GraphQLError = (*ErrMergeConflict)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrMergeConflict) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":     ErrCodeMergeConflict,
+		"provider": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrMergeConflict) Error() string {
+	return "both users linked an account of the same identity provider"
+}
+
+// ErrProtectedUser signifies that the other user enabled a second factor or
+// is assigned privileged roles and therefore can't be merged.
+type ErrProtectedUser struct{}
+
+var _ GraphQLError = (*ErrProtectedUser)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrProtectedUser) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeProtectedUser,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrProtectedUser) Error() string {
+	return "user is protected from being merged"
+}
+
+// ErrInvalidChallenge signifies that the two factor challenge is malformed or
+// expired.
+type ErrInvalidChallenge struct{}
+
+var _ GraphQLError = (*ErrInvalidChallenge)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidChallenge) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidChallenge,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidChallenge) Error() string {
+	return "two factor challenge is invalid"
+}
+
+// ErrInvalidCode signifies that the password or recovery code is wrong or
+// already used.
+type ErrInvalidCode struct{}
+
+var _ GraphQLError = (*ErrInvalidCode)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidCode) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidCode,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidCode) Error() string {
+	return "two factor code is invalid"
+}
+
+// ErrTooManyAttempts signifies that too many wrong codes were entered in a
+// short period of time.
+type ErrTooManyAttempts struct {
+	retryAfter int
+}
+
+var _ GraphQLError = (*ErrTooManyAttempts)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrTooManyAttempts) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":              ErrCodeTooManyAttempts,
+		"retryAfterSeconds": e.retryAfter,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrTooManyAttempts) Error() string {
+	return "too many two factor attempts"
+}
+
+// ErrTwoFactorEnabled signifies that the user already enabled a second
+// factor.
+type ErrTwoFactorEnabled struct{}
+
+var _ GraphQLError = (*ErrTwoFactorEnabled)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrTwoFactorEnabled) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeTwoFactorEnabled,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrTwoFactorEnabled) Error() string {
+	return "two factor already enabled"
+}
+
+// ErrNotEnrolled signifies that the user didn't enroll a second factor.
+type ErrNotEnrolled struct{}
+
+var _ GraphQLError = (*ErrNotEnrolled)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrNotEnrolled) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeNotEnrolled,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrNotEnrolled) Error() string {
+	return "two factor not enrolled"
+}
+
+// ErrTwoFactorRequired signifies that the roles of the user require a second
+// factor.
+type ErrTwoFactorRequired struct{}
+
+var _ GraphQLError = (*ErrTwoFactorRequired)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrTwoFactorRequired) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeTwoFactorRequired,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrTwoFactorRequired) Error() string {
+	return "two factor is required by the roles of the user"
+}
+
+// ErrUserNotFound signifies that the user with the given ID doesn't exist.
+type ErrUserNotFound string
+
+var _ GraphQLError = (*ErrUserNotFound)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrUserNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   ErrCodeUserNotFound,
+		"userID": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrUserNotFound) Error() string {
+	return "user not found"
+}
+
+// ErrUnknownRole signifies that the role is not defined.
+type ErrUnknownRole string
+
+var _ GraphQLError = (*ErrUnknownRole)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrUnknownRole) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeUnknownRole,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrUnknownRole) Error() string {
+	return "role is unknown"
+}
+
+// ErrRoleAlreadyGranted signifies that the user already has the role.
+type ErrRoleAlreadyGranted string
+
+var _ GraphQLError = (*ErrRoleAlreadyGranted)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrRoleAlreadyGranted) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeRoleAlreadyGranted,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrRoleAlreadyGranted) Error() string {
+	return "role already granted to the user"
+}
+
+// ErrRoleNotGranted signifies that the user doesn't have the role.
+type ErrRoleNotGranted string
+
+var _ GraphQLError = (*ErrRoleNotGranted)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrRoleNotGranted) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeRoleNotGranted,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrRoleNotGranted) Error() string {
+	return "role not granted to the user"
+}
+
+// ErrLastAdmin signifies that the admin role can't be revoked from the last
+// admin.
+type ErrLastAdmin struct{}
+
+var _ GraphQLError = (*ErrLastAdmin)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrLastAdmin) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeLastAdmin,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrLastAdmin) Error() string {
+	return "can't revoke the admin role from the last admin"
+}
+
+// ErrInvalidRoleName signifies that the name of the role contains anything
+// other than lowercase letters, digits and underscores, or is too long.
+type ErrInvalidRoleName string
+
+var _ GraphQLError = (*ErrInvalidRoleName)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidRoleName) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeInvalidRoleName,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidRoleName) Error() string {
+	return "role name is invalid"
+}
+
+// ErrUnknownPermission signifies that the permission is not defined.
+type ErrUnknownPermission string
+
+var _ GraphQLError = (*ErrUnknownPermission)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrUnknownPermission) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       ErrCodeUnknownPermission,
+		"permission": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrUnknownPermission) Error() string {
+	return "permission is unknown"
+}
+
+// ErrRoleExists signifies that a role with the same name already exists.
+type ErrRoleExists string
+
+var _ GraphQLError = (*ErrRoleExists)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrRoleExists) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeRoleExists,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrRoleExists) Error() string {
+	return "role already exists"
+}
+
+// ErrRoleNotFound signifies that the custom role doesn't exist.
+type ErrRoleNotFound string
+
+var _ GraphQLError = (*ErrRoleNotFound)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrRoleNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeRoleNotFound,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrRoleNotFound) Error() string {
+	return "role not found"
+}
+
+// ErrBuiltInRole signifies that the built-in role can't be changed.
+type ErrBuiltInRole string
+
+var _ GraphQLError = (*ErrBuiltInRole)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrBuiltInRole) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": ErrCodeBuiltInRole,
+		"role": string(e),
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrBuiltInRole) Error() string {
+	return "built-in role can't be changed"
+}
+
+// ErrInvalidAttribute signifies that the name of the short link attribute
+// contains anything other than lowercase letters, digits and underscores, or
+// that its value is empty or too long.
+type ErrInvalidAttribute struct {
+	name  string
+	value string
+}
+
+var _ GraphQLError = (*ErrInvalidAttribute)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrInvalidAttribute) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":  ErrCodeInvalidAttribute,
+		"name":  e.name,
+		"value": e.value,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrInvalidAttribute) Error() string {
+	return "short link attribute is invalid"
+}
+
+// ErrAttributeNotFound signifies that the short link doesn't have the value of
+// the attribute.
+type ErrAttributeNotFound struct {
+	name  string
+	value string
+}
+
+var _ GraphQLError = (*ErrAttributeNotFound)(nil)
+
+// Extensions keeps structured error metadata so that the clients can reliably
+// handle the error.
+func (e ErrAttributeNotFound) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":  ErrCodeAttributeNotFound,
+		"name":  e.name,
+		"value": e.value,
+	}
+}
+
+// Error retrieves the human readable error message.
+func (e ErrAttributeNotFound) Error() string {
+	return "short link attribute not found"
+}
@@ -0,0 +1,26 @@
+package resolver
+
+import "github.com/short-d/short/backend/app/entity"
+
+// LinkedAccount retrieves requested fields of a LinkedAccount.
+type LinkedAccount struct {
+	linkedAccount entity.LinkedAccount
+}
+
+// Provider retrieves the identity provider of the account.
+func (l LinkedAccount) Provider() string {
+	return l.linkedAccount.Provider
+}
+
+// SSOUserID retrieves the ID of the user at the identity provider.
+func (l LinkedAccount) SSOUserID() string {
+	return l.linkedAccount.SSOUserID
+}
+
+func newLinkedAccounts(linkedAccounts []entity.LinkedAccount) []LinkedAccount {
+	gqlLinkedAccounts := []LinkedAccount{}
+	for _, linkedAccount := range linkedAccounts {
+		gqlLinkedAccounts = append(gqlLinkedAccounts, LinkedAccount{linkedAccount: linkedAccount})
+	}
+	return gqlLinkedAccounts
+}
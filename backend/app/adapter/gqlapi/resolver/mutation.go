@@ -1,21 +1,38 @@
 package resolver
 
 import (
+	"errors"
+
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
 	"github.com/short-d/short/backend/app/usecase/requester"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 // Mutation represents GraphQL mutation resolver
 type Mutation struct {
-	logger            logger.Logger
-	shortLinkCreator  shortlink.Creator
-	shortLinkUpdater  shortlink.Updater
-	requesterVerifier requester.Verifier
-	authenticator     authenticator.Authenticator
-	changeLog         changelog.ChangeLog
+	logger             logger.Logger
+	shortLinkCreator   shortlink.Creator
+	shortLinkUpdater   shortlink.Updater
+	metaTag            shortlink.MetaTag
+	requesterVerifier  requester.Verifier
+	authenticator      authenticator.Authenticator
+	thirdPartyApp      authenticator.ThirdPartyApp
+	appRegistry        thirdparty.Registry
+	changeLog          changelog.ChangeLog
+	sessionManager     session.Manager
+	accountManager     sso.AccountManager
+	twoFactor          twofactor.TwoFactor
+	roleManager        userrole.Manager
+	customRoles        customrole.Manager
+	shortLinkAttribute shortlink.Attribute
 }
 
 // AuthMutationArgs represents possible parameters for AuthMutation endpoint
@@ -39,27 +56,108 @@ func (m Mutation) AuthMutation(args *AuthMutationArgs) (*AuthMutation, error) {
 	authMutation := newAuthMutation(
 		args.AuthToken,
 		m.authenticator,
+		m.thirdPartyApp,
+		m.appRegistry,
 		m.changeLog,
 		m.shortLinkCreator,
 		m.shortLinkUpdater,
+		m.metaTag,
+		m.sessionManager,
+		m.accountManager,
+		m.twoFactor,
+		m.roleManager,
+		m.customRoles,
+		m.shortLinkAttribute,
 	)
 	return &authMutation, nil
 }
 
+// RefreshTokenArgs represents the possible parameters for RefreshToken endpoint
+type RefreshTokenArgs struct {
+	RefreshToken string
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens
+func (m Mutation) RefreshToken(args *RefreshTokenArgs) (*AuthTokens, error) {
+	authTokens, err := m.authenticator.RefreshToken(args.RefreshToken)
+	if err == nil {
+		return &AuthTokens{authTokens: authTokens}, nil
+	}
+
+	var (
+		i authenticator.ErrInvalidRefreshToken
+	)
+	if errors.As(err, &i) {
+		return nil, ErrInvalidRefreshToken{}
+	}
+	return nil, ErrUnknown{}
+}
+
+// VerifyTwoFactorArgs represents the possible parameters for VerifyTwoFactor
+// endpoint
+type VerifyTwoFactorArgs struct {
+	Challenge string
+	Code      string
+}
+
+// VerifyTwoFactor completes a sign in with the password from the
+// authenticator app or a recovery code
+func (m Mutation) VerifyTwoFactor(args *VerifyTwoFactorArgs) (*AuthTokens, error) {
+	authTokens, err := m.twoFactor.CompleteSignIn(args.Challenge, args.Code)
+	if err == nil {
+		return &AuthTokens{authTokens: authTokens}, nil
+	}
+	return nil, newTwoFactorError(err)
+}
+
+// EnrollTwoFactorAtSignInArgs represents the possible parameters for
+// EnrollTwoFactorAtSignIn endpoint
+type EnrollTwoFactorAtSignInArgs struct {
+	Challenge string
+}
+
+// EnrollTwoFactorAtSignIn starts enrolling a second factor for the user who
+// is required to enroll before signing in
+func (m Mutation) EnrollTwoFactorAtSignIn(args *EnrollTwoFactorAtSignInArgs) (*TwoFactorEnrollment, error) {
+	enrollment, err := m.twoFactor.EnrollAtSignIn(args.Challenge)
+	if err == nil {
+		return &TwoFactorEnrollment{enrollment: enrollment}, nil
+	}
+	return nil, newTwoFactorError(err)
+}
+
 func newMutation(
 	logger logger.Logger,
 	changeLog changelog.ChangeLog,
 	shortLinkCreator shortlink.Creator,
 	shortLinkUpdater shortlink.Updater,
+	metaTag shortlink.MetaTag,
 	requesterVerifier requester.Verifier,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	appRegistry thirdparty.Registry,
+	sessionManager session.Manager,
+	accountManager sso.AccountManager,
+	twoFactor twofactor.TwoFactor,
+	roleManager userrole.Manager,
+	customRoles customrole.Manager,
+	shortLinkAttribute shortlink.Attribute,
 ) Mutation {
 	return Mutation{
-		logger:            logger,
-		changeLog:         changeLog,
-		shortLinkCreator:  shortLinkCreator,
-		shortLinkUpdater:  shortLinkUpdater,
-		requesterVerifier: requesterVerifier,
-		authenticator:     authenticator,
+		logger:             logger,
+		changeLog:          changeLog,
+		shortLinkCreator:   shortLinkCreator,
+		shortLinkUpdater:   shortLinkUpdater,
+		metaTag:            metaTag,
+		requesterVerifier:  requesterVerifier,
+		authenticator:      authenticator,
+		thirdPartyApp:      thirdPartyApp,
+		appRegistry:        appRegistry,
+		sessionManager:     sessionManager,
+		accountManager:     accountManager,
+		twoFactor:          twoFactor,
+		roleManager:        roleManager,
+		customRoles:        customRoles,
+		shortLinkAttribute: shortLinkAttribute,
 	}
 }
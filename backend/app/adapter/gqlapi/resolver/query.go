@@ -4,15 +4,32 @@ import (
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 // Query represents GraphQL query resolver
 type Query struct {
 	logger             logger.Logger
 	authenticator      authenticator.Authenticator
+	thirdPartyApp      authenticator.ThirdPartyApp
+	appRegistry        thirdparty.Registry
+	linkQuota          quota.Quota
 	changeLog          changelog.ChangeLog
 	shortLinkRetriever shortlink.Retriever
+	sessionManager     session.Manager
+	accountManager     sso.AccountManager
+	twoFactor          twofactor.TwoFactor
+	roleManager        userrole.Manager
+	customRoles        customrole.Manager
+	shortLinkAnalytics shortlink.Analytics
+	shortLinkAttribute shortlink.Attribute
 }
 
 // AuthQueryArgs represents possible parameters for AuthQuery endpoint
@@ -22,20 +39,55 @@ type AuthQueryArgs struct {
 
 // AuthQuery extracts user information from authentication token
 func (q Query) AuthQuery(args *AuthQueryArgs) (*AuthQuery, error) {
-	authQuery := newAuthQuery(args.AuthToken, q.authenticator, q.changeLog, q.shortLinkRetriever)
+	authQuery := newAuthQuery(
+		args.AuthToken,
+		q.authenticator,
+		q.thirdPartyApp,
+		q.appRegistry,
+		q.linkQuota,
+		q.changeLog,
+		q.shortLinkRetriever,
+		q.sessionManager,
+		q.accountManager,
+		q.twoFactor,
+		q.roleManager,
+		q.customRoles,
+		q.shortLinkAnalytics,
+		q.shortLinkAttribute,
+	)
 	return &authQuery, nil
 }
 
 func newQuery(
 	logger logger.Logger,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	appRegistry thirdparty.Registry,
+	linkQuota quota.Quota,
 	changeLog changelog.ChangeLog,
 	shortLinkRetriever shortlink.Retriever,
+	sessionManager session.Manager,
+	accountManager sso.AccountManager,
+	twoFactor twofactor.TwoFactor,
+	roleManager userrole.Manager,
+	customRoles customrole.Manager,
+	shortLinkAnalytics shortlink.Analytics,
+	shortLinkAttribute shortlink.Attribute,
 ) Query {
 	return Query{
 		logger:             logger,
 		authenticator:      authenticator,
+		thirdPartyApp:      thirdPartyApp,
+		appRegistry:        appRegistry,
+		linkQuota:          linkQuota,
 		changeLog:          changeLog,
 		shortLinkRetriever: shortLinkRetriever,
+		sessionManager:     sessionManager,
+		accountManager:     accountManager,
+		twoFactor:          twoFactor,
+		roleManager:        roleManager,
+		customRoles:        customRoles,
+		shortLinkAnalytics: shortLinkAnalytics,
+		shortLinkAttribute: shortLinkAttribute,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
@@ -16,9 +17,16 @@ import (
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/quota"
 	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 func TestQuery_AuthQuery(t *testing.T) {
@@ -28,8 +36,9 @@ func TestQuery_AuthQuery(t *testing.T) {
 	user := entity.User{
 		Email: "alpha@example.com",
 	}
-	authToken, err := auth.GenerateToken(user)
+	authTokens, err := auth.SignIn(user, "github", entity.Device{})
 	assert.Equal(t, nil, err)
+	authToken := authTokens.AccessToken
 
 	testCases := []struct {
 		name      string
@@ -62,10 +71,11 @@ func TestQuery_AuthQuery(t *testing.T) {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
-			fakeShortLinkRepo := repository.NewShortLinkFake(nil, map[string]entity.ShortLink{})
+			fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{})
 			fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
 			auth := authenticator.NewAuthenticatorFake(time.Now(), time.Hour)
-			retrieverFake := shortlink.NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo)
+			retrieverFake := shortlink.NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
 			entryRepo := logger.NewEntryRepoFake()
 			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
 			assert.Equal(t, nil, err)
@@ -84,7 +94,14 @@ func TestQuery_AuthQuery(t *testing.T) {
 
 			changeLog := changelog.NewPersist(keyGen, tm, &changeLogRepo, &userChangeLogRepo, au)
 
-			query := newQuery(lg, auth, changeLog, retrieverFake)
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{})
+			appRepo := repository.NewAppFake([]entity.App{})
+			thirdPartyApp := authenticator.NewThirdPartyApp(au, crypto.NewTokenizerFake(), keyGen, tm, &apiKeyRepo, &appRepo, lg)
+
+			appRegistry := thirdparty.NewPersist(keyGen, tm, &appRepo)
+			linkQuota := quota.NewQuota(&fakeUserShortLinkRepo, &fakeAppShortLinkRepo, rb, tm, quota.Policy{})
+
+			query := newQuery(lg, auth, thirdPartyApp, appRegistry, linkQuota, changeLog, retrieverFake, session.Manager{}, sso.AccountManager{}, twofactor.TwoFactor{}, userrole.Manager{}, customrole.Manager{}, nil, nil)
 
 			assert.Equal(t, nil, err)
 			authQueryArgs := AuthQueryArgs{AuthToken: testCase.authToken}
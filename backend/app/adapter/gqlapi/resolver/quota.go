@@ -0,0 +1,70 @@
+package resolver
+
+import (
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/usecase/quota"
+)
+
+// Quota retrieves requested fields of the short link creation quota.
+type Quota struct {
+	usage quota.Usage
+}
+
+// DailyUsed retrieves the number of short links created today.
+func (q Quota) DailyUsed() int32 {
+	return int32(q.usage.DailyCreated)
+}
+
+// DailyLimit retrieves the maximum number of short links which can be created
+// per day. It's nil if there is no daily limit.
+func (q Quota) DailyLimit() *int32 {
+	if q.usage.DailyRemaining() == nil {
+		return nil
+	}
+	limit := int32(q.usage.Limit.Daily)
+	return &limit
+}
+
+// DailyRemaining retrieves the number of short links which can still be
+// created today.
+func (q Quota) DailyRemaining() *int32 {
+	return toInt32Ptr(q.usage.DailyRemaining())
+}
+
+// DailyResetAt retrieves the time when the daily usage is reset.
+func (q Quota) DailyResetAt() scalar.Time {
+	return scalar.Time{Time: q.usage.DailyResetAt}
+}
+
+// TotalUsed retrieves the number of short links created so far.
+func (q Quota) TotalUsed() int32 {
+	return int32(q.usage.TotalCreated)
+}
+
+// TotalLimit retrieves the maximum number of short links which can be
+// created. It's nil if there is no total limit.
+func (q Quota) TotalLimit() *int32 {
+	if q.usage.TotalRemaining() == nil {
+		return nil
+	}
+	limit := int32(q.usage.Limit.Total)
+	return &limit
+}
+
+// TotalRemaining retrieves the number of short links which can still be
+// created.
+func (q Quota) TotalRemaining() *int32 {
+	return toInt32Ptr(q.usage.TotalRemaining())
+}
+
+func toInt32Ptr(num *int) *int32 {
+	if num == nil {
+		return nil
+	}
+	converted := int32(*num)
+	return &converted
+}
+
+func newQuota(usage quota.Usage) Quota {
+	return Quota{usage: usage}
+}
@@ -4,8 +4,15 @@ import (
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+	"github.com/short-d/short/backend/app/usecase/quota"
 	"github.com/short-d/short/backend/app/usecase/requester"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 )
 
 // Resolver contains GraphQL request handlers.
@@ -20,19 +27,54 @@ func NewResolver(
 	shortLinkRetriever shortlink.Retriever,
 	shortLinkCreator shortlink.Creator,
 	shortLinkUpdater shortlink.Updater,
+	metaTag shortlink.MetaTag,
 	changeLog changelog.ChangeLog,
 	requesterVerifier requester.Verifier,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	appRegistry thirdparty.Registry,
+	linkQuota quota.Quota,
+	sessionManager session.Manager,
+	accountManager sso.AccountManager,
+	twoFactor twofactor.TwoFactor,
+	roleManager userrole.Manager,
+	customRoles customrole.Manager,
+	shortLinkAnalytics shortlink.Analytics,
+	shortLinkAttribute shortlink.Attribute,
 ) Resolver {
 	return Resolver{
-		Query: newQuery(logger, authenticator, changeLog, shortLinkRetriever),
+		Query: newQuery(
+			logger,
+			authenticator,
+			thirdPartyApp,
+			appRegistry,
+			linkQuota,
+			changeLog,
+			shortLinkRetriever,
+			sessionManager,
+			accountManager,
+			twoFactor,
+			roleManager,
+			customRoles,
+			shortLinkAnalytics,
+			shortLinkAttribute,
+		),
 		Mutation: newMutation(
 			logger,
 			changeLog,
 			shortLinkCreator,
 			shortLinkUpdater,
+			metaTag,
 			requesterVerifier,
 			authenticator,
+			thirdPartyApp,
+			appRegistry,
+			sessionManager,
+			accountManager,
+			twoFactor,
+			roleManager,
+			customRoles,
+			shortLinkAttribute,
 		),
 	}
 }
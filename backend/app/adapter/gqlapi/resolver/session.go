@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// Session retrieves requested fields of a Session.
+type Session struct {
+	session entity.Session
+}
+
+// ID retrieves the ID of Session entity.
+func (s Session) ID() string {
+	return s.session.ID
+}
+
+// IdentityProvider retrieves the service the user signed in with.
+func (s Session) IdentityProvider() string {
+	return s.session.IdentityProvider
+}
+
+// ClientIP retrieves the IP address of the device signed in.
+func (s Session) ClientIP() string {
+	return s.session.Device.ClientIP
+}
+
+// Location retrieves the approximate location of the device signed in.
+func (s Session) Location() string {
+	return s.session.Device.Location
+}
+
+// UserAgent retrieves the user agent of the device signed in.
+func (s Session) UserAgent() string {
+	return s.session.Device.UserAgent
+}
+
+// CreatedAt retrieves the time when the user signed in.
+func (s Session) CreatedAt() scalar.Time {
+	return scalar.Time{Time: s.session.CreatedAt}
+}
+
+// ExpireAt retrieves the time when the session expires.
+func (s Session) ExpireAt() scalar.Time {
+	return scalar.Time{Time: s.session.ExpireAt}
+}
+
+// RevokedAt retrieves the time when the session was revoked.
+func (s Session) RevokedAt() *scalar.Time {
+	if s.session.RevokedAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *s.session.RevokedAt}
+}
+
+func newSession(session entity.Session) Session {
+	return Session{session: session}
+}
+
+func newSessions(sessions []entity.Session) []Session {
+	gqlSessions := []Session{}
+	for _, session := range sessions {
+		gqlSessions = append(gqlSessions, newSession(session))
+	}
+	return gqlSessions
+}
@@ -0,0 +1,25 @@
+package resolver
+
+import (
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// ShortLinkAnalytics retrieves requested fields of the visits to a short link.
+type ShortLinkAnalytics struct {
+	analytics entity.ShortLinkAnalytics
+}
+
+// VisitCount retrieves the number of times the short link has been visited.
+func (s ShortLinkAnalytics) VisitCount() int32 {
+	return int32(s.analytics.VisitCount)
+}
+
+// LastVisitedAt retrieves the time when the short link was last visited. It's
+// nil if the short link has never been visited.
+func (s ShortLinkAnalytics) LastVisitedAt() *scalar.Time {
+	if s.analytics.LastVisitedAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *s.analytics.LastVisitedAt}
+}
@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+// ShortLinkAttribute retrieves requested fields of an attribute of a short
+// link.
+type ShortLinkAttribute struct {
+	name   string
+	values []string
+}
+
+// Name retrieves the name of the attribute, such as team.
+func (s ShortLinkAttribute) Name() string {
+	return s.name
+}
+
+// Values retrieves the values of the attribute, such as growth.
+func (s ShortLinkAttribute) Values() []string {
+	return s.values
+}
+
+func newShortLinkAttributes(attributes map[string][]string) []ShortLinkAttribute {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gqlAttributes := []ShortLinkAttribute{}
+	for _, name := range names {
+		gqlAttributes = append(gqlAttributes, ShortLinkAttribute{
+			name:   name,
+			values: attributes[name],
+		})
+	}
+	return gqlAttributes
+}
+
+func newShortLinkAttributeError(err error, user entity.User, alias string, action string) error {
+	var (
+		u  shortlink.ErrUnauthorizedAction
+		nf shortlink.ErrShortLinkNotFound
+		in shortlink.ErrInvalidAttribute
+		an shortlink.ErrAttributeNotFound
+	)
+	if errors.As(err, &u) {
+		return ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to %s", user.ID, action))
+	}
+	if errors.As(err, &nf) {
+		return ErrShortLinkNotFound(alias)
+	}
+	if errors.As(err, &in) {
+		return ErrInvalidAttribute{name: in.Name, value: in.Value}
+	}
+	if errors.As(err, &an) {
+		return ErrAttributeNotFound{name: an.Name, value: an.Value}
+	}
+	return ErrUnknown{}
+}
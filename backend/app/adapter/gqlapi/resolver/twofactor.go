@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"errors"
+
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+)
+
+// TwoFactorEnrollment retrieves requested fields of a TwoFactorEnrollment.
+type TwoFactorEnrollment struct {
+	enrollment entity.TwoFactorEnrollment
+}
+
+// Secret retrieves the base32 encoded secret for entering manually.
+func (t TwoFactorEnrollment) Secret() string {
+	return t.enrollment.Secret
+}
+
+// ProvisioningURI retrieves the otpauth URI to be shown as a QR code.
+func (t TwoFactorEnrollment) ProvisioningURI() string {
+	return t.enrollment.ProvisioningURI
+}
+
+// RecoveryCodes retrieves the one-time codes for signing in without the
+// authenticator app.
+func (t TwoFactorEnrollment) RecoveryCodes() []string {
+	return t.enrollment.RecoveryCodes
+}
+
+// TwoFactorStatus retrieves requested fields of a TwoFactorStatus.
+type TwoFactorStatus struct {
+	status entity.TwoFactorStatus
+}
+
+// IsEnabled retrieves whether the user signs in with a second factor.
+func (t TwoFactorStatus) IsEnabled() bool {
+	return t.status.IsEnabled
+}
+
+// IsRequired retrieves whether the roles of the user require a second factor.
+func (t TwoFactorStatus) IsRequired() bool {
+	return t.status.IsRequired
+}
+
+// EnforceFrom retrieves the time when signing in without a second factor is
+// no longer allowed.
+func (t TwoFactorStatus) EnforceFrom() *scalar.Time {
+	if t.status.EnforceFrom == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *t.status.EnforceFrom}
+}
+
+func newTwoFactorError(err error) error {
+	var (
+		ic twofactor.ErrInvalidChallenge
+		c  twofactor.ErrInvalidCode
+		ta twofactor.ErrTooManyAttempts
+		ae twofactor.ErrAlreadyEnabled
+		ne twofactor.ErrNotEnrolled
+		rr twofactor.ErrRequiredByRole
+	)
+	if errors.As(err, &ic) {
+		return ErrInvalidChallenge{}
+	}
+	if errors.As(err, &c) {
+		return ErrInvalidCode{}
+	}
+	if errors.As(err, &ta) {
+		return ErrTooManyAttempts{retryAfter: int(ta.RetryAfter.Seconds())}
+	}
+	if errors.As(err, &ae) {
+		return ErrTwoFactorEnabled{}
+	}
+	if errors.As(err, &ne) {
+		return ErrNotEnrolled{}
+	}
+	if errors.As(err, &rr) {
+		return ErrTwoFactorRequired{}
+	}
+	return ErrUnknown{}
+}
@@ -3,6 +3,7 @@ package resolver
 import (
 	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/entity/metatag"
 )
 
 // ShortLink retrieves requested fields of ShortLink entity.
@@ -29,6 +30,91 @@ func (s ShortLink) ExpireAt() *scalar.Time {
 	return &scalar.Time{Time: *s.shortLink.ExpireAt}
 }
 
+// OpenGraphTags retrieves the Open Graph meta tags of ShortLink entity.
+func (s ShortLink) OpenGraphTags() OpenGraphTags {
+	return OpenGraphTags{tags: s.shortLink.OpenGraphTags}
+}
+
+// TwitterTags retrieves the Twitter meta tags of ShortLink entity.
+func (s ShortLink) TwitterTags() TwitterTags {
+	return TwitterTags{tags: s.shortLink.TwitterTags}
+}
+
+// Health retrieves the result of the latest reachability check of ShortLink
+// entity.
+func (s ShortLink) Health() *LinkHealth {
+	if !s.shortLink.Health.IsChecked() {
+		return nil
+	}
+
+	return &LinkHealth{health: s.shortLink.Health}
+}
+
+// LinkHealth retrieves requested fields of LinkHealth entity.
+type LinkHealth struct {
+	health entity.LinkHealth
+}
+
+// StatusCode retrieves the HTTP status code responded by the destination.
+func (l LinkHealth) StatusCode() *int32 {
+	if l.health.StatusCode == nil {
+		return nil
+	}
+
+	statusCode := int32(*l.health.StatusCode)
+	return &statusCode
+}
+
+// CheckedAt retrieves the time when the destination was last checked.
+func (l LinkHealth) CheckedAt() scalar.Time {
+	return scalar.Time{Time: *l.health.CheckedAt}
+}
+
+// IsBroken retrieves whether the destination failed its latest check.
+func (l LinkHealth) IsBroken() bool {
+	return l.health.IsBroken()
+}
+
+// OpenGraphTags retrieves requested fields of Open Graph meta tags.
+type OpenGraphTags struct {
+	tags metatag.OpenGraph
+}
+
+// Title retrieves og:title.
+func (o OpenGraphTags) Title() *string {
+	return o.tags.Title
+}
+
+// Description retrieves og:description.
+func (o OpenGraphTags) Description() *string {
+	return o.tags.Description
+}
+
+// ImageURL retrieves og:image.
+func (o OpenGraphTags) ImageURL() *string {
+	return o.tags.ImageURL
+}
+
+// TwitterTags retrieves requested fields of Twitter meta tags.
+type TwitterTags struct {
+	tags metatag.Twitter
+}
+
+// Title retrieves twitter:title.
+func (t TwitterTags) Title() *string {
+	return t.tags.Title
+}
+
+// Description retrieves twitter:description.
+func (t TwitterTags) Description() *string {
+	return t.tags.Description
+}
+
+// ImageURL retrieves twitter:image.
+func (t TwitterTags) ImageURL() *string {
+	return t.tags.ImageURL
+}
+
 func newShortLink(shortLink entity.ShortLink) ShortLink {
 	return ShortLink{shortLink: shortLink}
 }
@@ -9,6 +9,8 @@ import (
 	"github.com/short-d/app/fw/assert"
 	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/fw/ptr"
 )
 
 func TestShortLink_Alias(t *testing.T) {
@@ -49,3 +51,83 @@ func TestShortLink_ExpireAt(t *testing.T) {
 		assert.Equal(t, testCase.expected, testCase.shortLink.ExpireAt())
 	}
 }
+
+func TestShortLink_Health(t *testing.T) {
+	t.Parallel()
+	checkedAt := time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name               string
+		health             entity.LinkHealth
+		expectedIsNil      bool
+		expectedStatusCode *int32
+		expectedIsBroken   bool
+	}{
+		{
+			name:          "never checked",
+			health:        entity.LinkHealth{},
+			expectedIsNil: true,
+		},
+		{
+			name: "destination is reachable",
+			health: entity.LinkHealth{
+				StatusCode: ptr.Int(200),
+				CheckedAt:  &checkedAt,
+			},
+			expectedStatusCode: func() *int32 { code := int32(200); return &code }(),
+			expectedIsBroken:   false,
+		},
+		{
+			name: "destination responds with error",
+			health: entity.LinkHealth{
+				StatusCode: ptr.Int(404),
+				CheckedAt:  &checkedAt,
+			},
+			expectedStatusCode: func() *int32 { code := int32(404); return &code }(),
+			expectedIsBroken:   true,
+		},
+		{
+			name:             "destination is unreachable",
+			health:           entity.LinkHealth{CheckedAt: &checkedAt},
+			expectedIsBroken: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			shortLink := ShortLink{shortLink: entity.ShortLink{Health: testCase.health}}
+
+			health := shortLink.Health()
+			if testCase.expectedIsNil {
+				assert.Equal(t, (*LinkHealth)(nil), health)
+				return
+			}
+			assert.Equal(t, testCase.expectedStatusCode, health.StatusCode())
+			assert.Equal(t, scalar.Time{Time: checkedAt}, health.CheckedAt())
+			assert.Equal(t, testCase.expectedIsBroken, health.IsBroken())
+		})
+	}
+}
+
+func TestShortLink_MetaTags(t *testing.T) {
+	t.Parallel()
+	shortLink := ShortLink{shortLink: entity.ShortLink{
+		OpenGraphTags: metatag.OpenGraph{
+			Title:    ptr.String("OG Title"),
+			ImageURL: ptr.String("https://short-d.com/og.png"),
+		},
+		TwitterTags: metatag.Twitter{
+			Description: ptr.String("Twitter Description"),
+		},
+	}}
+
+	openGraphTags := shortLink.OpenGraphTags()
+	assert.Equal(t, ptr.String("OG Title"), openGraphTags.Title())
+	assert.Equal(t, (*string)(nil), openGraphTags.Description())
+	assert.Equal(t, ptr.String("https://short-d.com/og.png"), openGraphTags.ImageURL())
+
+	twitterTags := shortLink.TwitterTags()
+	assert.Equal(t, (*string)(nil), twitterTags.Title())
+	assert.Equal(t, ptr.String("Twitter Description"), twitterTags.Description())
+	assert.Equal(t, (*string)(nil), twitterTags.ImageURL())
+}
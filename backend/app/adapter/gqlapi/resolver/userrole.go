@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/short-d/short/backend/app/adapter/gqlapi/scalar"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/userrole"
+)
+
+// User retrieves requested fields of an User together with the roles.
+type User struct {
+	userRoles userrole.UserRoles
+}
+
+// ID retrieves the ID of User entity.
+func (u User) ID() string {
+	return u.userRoles.User.ID
+}
+
+// Email retrieves the email of the user.
+func (u User) Email() string {
+	return u.userRoles.User.Email
+}
+
+// Name retrieves the name of the user.
+func (u User) Name() string {
+	return u.userRoles.User.Name
+}
+
+// Roles retrieves the roles granted to the user.
+func (u User) Roles() []string {
+	roles := []string{}
+	for _, r := range u.userRoles.Roles {
+		roles = append(roles, string(r))
+	}
+	return roles
+}
+
+// LastSignedInAt retrieves the time when the user last signed in.
+func (u User) LastSignedInAt() *scalar.Time {
+	if u.userRoles.User.LastSignedInAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *u.userRoles.User.LastSignedInAt}
+}
+
+// CreatedAt retrieves the time when the user signed up.
+func (u User) CreatedAt() *scalar.Time {
+	if u.userRoles.User.CreatedAt == nil {
+		return nil
+	}
+	return &scalar.Time{Time: *u.userRoles.User.CreatedAt}
+}
+
+func newUsers(userRoles []userrole.UserRoles) []User {
+	gqlUsers := []User{}
+	for _, userRole := range userRoles {
+		gqlUsers = append(gqlUsers, User{userRoles: userRole})
+	}
+	return gqlUsers
+}
+
+// RoleChange retrieves requested fields of a RoleChange.
+type RoleChange struct {
+	roleChange entity.RoleChange
+}
+
+// ActorID retrieves the ID of the user who changed the role.
+func (r RoleChange) ActorID() string {
+	return r.roleChange.ActorID
+}
+
+// UserID retrieves the ID of the user whose role was changed.
+func (r RoleChange) UserID() string {
+	return r.roleChange.UserID
+}
+
+// Role retrieves the role granted or revoked.
+func (r RoleChange) Role() string {
+	return r.roleChange.Role
+}
+
+// Action retrieves whether the role was granted or revoked.
+func (r RoleChange) Action() string {
+	return string(r.roleChange.Action)
+}
+
+// CreatedAt retrieves the time when the role was changed.
+func (r RoleChange) CreatedAt() scalar.Time {
+	return scalar.Time{Time: r.roleChange.CreatedAt}
+}
+
+func newRoleChanges(roleChanges []entity.RoleChange) []RoleChange {
+	gqlRoleChanges := []RoleChange{}
+	for _, roleChange := range roleChanges {
+		gqlRoleChanges = append(gqlRoleChanges, RoleChange{roleChange: roleChange})
+	}
+	return gqlRoleChanges
+}
+
+func newUserRoleError(err error, user entity.User, action string) error {
+	var (
+		u  userrole.ErrUnauthorizedAction
+		nf userrole.ErrUserNotFound
+		ur userrole.ErrUnknownRole
+		ag userrole.ErrRoleAlreadyGranted
+		ng userrole.ErrRoleNotGranted
+		la userrole.ErrLastAdmin
+	)
+	if errors.As(err, &u) {
+		return ErrUnauthorizedAction(fmt.Sprintf("user %s is not allowed to %s", user.ID, action))
+	}
+	if errors.As(err, &nf) {
+		return ErrUserNotFound(nf)
+	}
+	if errors.As(err, &ur) {
+		return ErrUnknownRole(ur)
+	}
+	if errors.As(err, &ag) {
+		return ErrRoleAlreadyGranted(ag.Role)
+	}
+	if errors.As(err, &ng) {
+		return ErrRoleNotGranted(ng.Role)
+	}
+	if errors.As(err, &la) {
+		return ErrLastAdmin{}
+	}
+	return ErrUnknown{}
+}
@@ -5,8 +5,28 @@ import (
 
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/quota"
 )
 
+// Viewer retrieves requested fields of the current user.
+type Viewer struct {
+	user      entity.User
+	linkQuota quota.Quota
+}
+
+// Quota retrieves the short link creation quota of the current user.
+func (v Viewer) Quota() (Quota, error) {
+	usage, err := v.linkQuota.GetUsage(v.user)
+	if err != nil {
+		return Quota{}, ErrUnknown{}
+	}
+	return newQuota(usage), nil
+}
+
+func newViewer(user entity.User, linkQuota quota.Quota) Viewer {
+	return Viewer{user: user, linkQuota: linkQuota}
+}
+
 func viewer(authToken *string, auth authenticator.Authenticator) (entity.User, error) {
 	if authToken == nil {
 		return entity.User{}, errors.New("auth token can't be empty")
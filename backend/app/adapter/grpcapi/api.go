@@ -10,15 +10,23 @@ var _ rpc.API = (*Short)(nil)
 
 // Short provides an efficient way for remote systems to interact with Short backend.
 type Short struct {
-	metaTagServer proto.MetaTagServiceServer
+	metaTagServer   proto.MetaTagServiceServer
+	shortLinkServer proto.ShortLinkServiceServer
 }
 
 // RegisterServers registers gRPC servers that handle user requests.
 func (s Short) RegisterServers(server *grpc.Server) {
 	proto.RegisterMetaTagServiceServer(server, s.metaTagServer)
+	proto.RegisterShortLinkServiceServer(server, s.shortLinkServer)
 }
 
 // NewShort creates Short.
-func NewShort(metaTagServer proto.MetaTagServiceServer) Short {
-	return Short{metaTagServer: metaTagServer}
+func NewShort(
+	metaTagServer proto.MetaTagServiceServer,
+	shortLinkServer proto.ShortLinkServiceServer,
+) Short {
+	return Short{
+		metaTagServer:   metaTagServer,
+		shortLinkServer: shortLinkServer,
+	}
 }
@@ -0,0 +1,95 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authenticator/scope"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const apiKeyMetadataKey = "x-api-key"
+
+// apiKeyServices are the services only accessible with a valid API key.
+var apiKeyServices = []string{
+	"/proto.ShortLinkService/",
+}
+
+// methodScopes are the scopes an API key must be granted to call the methods
+// of the protected services. Methods missing from here are rejected.
+var methodScopes = map[string]scope.Scope{
+	"/proto.ShortLinkService/CreateShortLink": scope.ShortLinkWrite,
+	"/proto.ShortLinkService/GetShortLink":    scope.ShortLinkRead,
+	"/proto.ShortLinkService/UpdateShortLink": scope.ShortLinkWrite,
+	"/proto.ShortLinkService/ListShortLinks":  scope.ShortLinkRead,
+}
+
+// APIKeyAuth rejects requests to the protected services which do not carry a
+// valid API key in the metadata.
+type APIKeyAuth struct {
+	thirdPartyApp authenticator.ThirdPartyApp
+}
+
+// Intercept authenticates the calling app before handing the request over.
+func (a APIKeyAuth) Intercept(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !isAPIKeyRequired(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	apiKey, ok := getAPIKey(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "api key not provided")
+	}
+
+	requiredScope, ok := methodScopes[info.FullMethod]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not accessible with api key", info.FullMethod)
+	}
+
+	_, err := a.thirdPartyApp.GetApp(authenticator.Credential{APIKey: &apiKey}, requiredScope)
+	if err == nil {
+		return handler(ctx, req)
+	}
+
+	var missingScope authenticator.ErrMissingScope
+	if errors.As(err, &missingScope) {
+		return nil, status.Error(codes.PermissionDenied, missingScope.Error())
+	}
+	return nil, status.Error(codes.Unauthenticated, "invalid api key")
+}
+
+func isAPIKeyRequired(fullMethod string) bool {
+	for _, service := range apiKeyServices {
+		if strings.HasPrefix(fullMethod, service) {
+			return true
+		}
+	}
+	return false
+}
+
+func getAPIKey(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(apiKeyMetadataKey)
+	if len(values) < 1 || values[0] == "" {
+		return "", false
+	}
+	return values[0], true
+}
+
+// NewAPIKeyAuth creates APIKeyAuth.
+func NewAPIKeyAuth(thirdPartyApp authenticator.ThirdPartyApp) APIKeyAuth {
+	return APIKeyAuth{thirdPartyApp: thirdPartyApp}
+}
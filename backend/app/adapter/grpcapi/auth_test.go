@@ -0,0 +1,126 @@
+// +build !integration all
+
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIKeyAuth_Intercept(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		fullMethod     string
+		apiKey         *string
+		expectedCalled bool
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "public service does not require api key",
+			fullMethod:     "/proto.MetaTagService/GetOpenGraphTags",
+			expectedCalled: true,
+			expectedCode:   codes.OK,
+		},
+		{
+			name:           "api key not provided",
+			fullMethod:     "/proto.ShortLinkService/GetShortLink",
+			expectedCalled: false,
+			expectedCode:   codes.Unauthenticated,
+		},
+		{
+			name:           "invalid api key",
+			fullMethod:     "/proto.ShortLinkService/GetShortLink",
+			apiKey:         ptr.String(`{"app_id": "alpha","key":"unknown"}`),
+			expectedCalled: false,
+			expectedCode:   codes.Unauthenticated,
+		},
+		{
+			name:           "api key missing required scope",
+			fullMethod:     "/proto.ShortLinkService/CreateShortLink",
+			apiKey:         ptr.String(`{"app_id": "alpha","key":"secret"}`),
+			expectedCalled: false,
+			expectedCode:   codes.PermissionDenied,
+		},
+		{
+			name:           "method without scope",
+			fullMethod:     "/proto.ShortLinkService/DeleteShortLink",
+			apiKey:         ptr.String(`{"app_id": "alpha","key":"secret"}`),
+			expectedCalled: false,
+			expectedCode:   codes.PermissionDenied,
+		},
+		{
+			name:           "valid api key",
+			fullMethod:     "/proto.ShortLinkService/GetShortLink",
+			apiKey:         ptr.String(`{"app_id": "alpha","key":"secret"}`),
+			expectedCalled: true,
+			expectedCode:   codes.OK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+			auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{
+				{AppID: "alpha", Key: "secret", Scopes: []string{"shortlink:read"}},
+			})
+			appRepo := repository.NewAppFake([]entity.App{{ID: "alpha"}})
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			thirdPartyApp := authenticator.NewThirdPartyApp(
+				auth,
+				crypto.NewTokenizerFake(),
+				keyGen,
+				timer.NewStub(time.Now()),
+				&apiKeyRepo,
+				&appRepo,
+				lg,
+			)
+			apiKeyAuth := NewAPIKeyAuth(thirdPartyApp)
+
+			ctx := context.Background()
+			if testCase.apiKey != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(apiKeyMetadataKey, *testCase.apiKey))
+			}
+
+			isCalled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				isCalled = true
+				return nil, nil
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: testCase.fullMethod}
+			_, err = apiKeyAuth.Intercept(ctx, nil, info, handler)
+			assert.Equal(t, testCase.expectedCalled, isCalled)
+			assert.Equal(t, testCase.expectedCode, status.Code(err))
+		})
+	}
+}
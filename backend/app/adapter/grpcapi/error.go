@@ -0,0 +1,54 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatusError converts errors returned by use cases into gRPC status errors
+// so that clients can act on the status code.
+func toStatusError(err error) error {
+	var (
+		aliasExist       shortlink.ErrAliasExist
+		invalidLongLink  shortlink.ErrInvalidLongLink
+		invalidAlias     shortlink.ErrInvalidCustomAlias
+		emptyAlias       shortlink.ErrEmptyAlias
+		maliciousLink    shortlink.ErrMaliciousLongLink
+		redirectLoop     shortlink.ErrRedirectLoop
+		tooManyRedirects shortlink.ErrTooManyRedirects
+		shortLinkMissing shortlink.ErrShortLinkNotFound
+		aliasNotFound    repository.ErrAliasNotFound
+		entryNotFound    repository.ErrEntryNotFound
+		quotaExceeded    quota.ErrQuotaExceeded
+	)
+
+	switch {
+	case errors.As(err, &aliasExist):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.As(err, &invalidLongLink):
+		return status.Errorf(codes.InvalidArgument, "invalid long link(%s): %s", invalidLongLink.LongLink, invalidLongLink.Violation)
+	case errors.As(err, &invalidAlias):
+		return status.Errorf(codes.InvalidArgument, "invalid custom alias(%s): %s", invalidAlias.Error(), invalidAlias.Violation)
+	case errors.As(err, &emptyAlias):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &maliciousLink):
+		return status.Errorf(codes.InvalidArgument, "malicious long link(%s)", err.Error())
+	case errors.As(err, &redirectLoop):
+		return status.Errorf(codes.InvalidArgument, "long link(%s) redirects back to the short link", err.Error())
+	case errors.As(err, &tooManyRedirects):
+		return status.Errorf(codes.InvalidArgument, "long link(%s) goes through too many short links", err.Error())
+	case errors.As(err, &shortLinkMissing):
+		return status.Errorf(codes.NotFound, "short link(%s) not found", err.Error())
+	case errors.As(err, &aliasNotFound), errors.As(err, &entryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &quotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
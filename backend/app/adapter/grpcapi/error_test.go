@@ -0,0 +1,93 @@
+// +build !integration all
+
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/validator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{
+			name:         "alias exists",
+			err:          shortlink.ErrAliasExist("short link alias already exist"),
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "invalid long link",
+			err:          shortlink.ErrInvalidLongLink{LongLink: "aaa", Violation: validator.LongLinkNotURL},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "empty alias",
+			err:          shortlink.ErrEmptyAlias("alias is empty"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "malicious long link",
+			err:          shortlink.ErrMaliciousLongLink("http://malware.wicar.org/data/ms14_064_ole_not_xp.html"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "redirect loop",
+			err:          shortlink.ErrRedirectLoop("https://s.short-d.com/r/alpha"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "too many redirects",
+			err:          shortlink.ErrTooManyRedirects("https://s.short-d.com/r/alpha"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "short link not found",
+			err:          shortlink.ErrShortLinkNotFound("alpha"),
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "alias not found",
+			err:          repository.ErrAliasNotFound{Alias: "alpha"},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "wrapped entry not found",
+			err:          fmt.Errorf("fail to get short link: %w", repository.ErrEntryNotFound("alias(alpha) not found")),
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "quota exceeded",
+			err:          quota.ErrQuotaExceeded{UserID: "alpha", Period: quota.Daily, Limit: 50},
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			name:         "unknown error",
+			err:          errors.New("connection refused"),
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := toStatusError(testCase.err)
+			assert.Equal(t, testCase.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/short-d/app/fw/logger"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultPingTimeout         = 5 * time.Second
+)
+
+// Dependency represents an external system the gRPC API relies on.
+type Dependency interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthServer reports the serving status of the gRPC API through the
+// standard grpc.health.v1 service. Each dependency is reported under its own
+// service name, while the empty service name reports the overall status.
+type HealthServer struct {
+	server       *health.Server
+	dependencies map[string]Dependency
+	interval     time.Duration
+	logger       logger.Logger
+}
+
+// CheckDependencies pings all dependencies and updates their serving status.
+func (h HealthServer) CheckDependencies() {
+	overallStatus := grpc_health_v1.HealthCheckResponse_SERVING
+	for name, dependency := range h.dependencies {
+		status := h.checkDependency(name, dependency)
+		h.server.SetServingStatus(name, status)
+		if status != grpc_health_v1.HealthCheckResponse_SERVING {
+			overallStatus = status
+		}
+	}
+	h.server.SetServingStatus("", overallStatus)
+}
+
+func (h HealthServer) checkDependency(name string, dependency Dependency) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPingTimeout)
+	defer cancel()
+
+	err := dependency.PingContext(ctx)
+	if err != nil {
+		h.logger.Error(fmt.Errorf("dependency(%s) is unhealthy: %w", name, err))
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// StartAsync checks the dependencies immediately and then once every interval
+// without blocking the caller.
+func (h HealthServer) StartAsync() {
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			h.CheckDependencies()
+			<-ticker.C
+		}
+	}()
+}
+
+// NewHealthServer creates HealthServer. All services are reported as not
+// serving until the dependencies are checked.
+func NewHealthServer(
+	interval time.Duration,
+	dependencies map[string]Dependency,
+	logger logger.Logger,
+) HealthServer {
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	server := health.NewServer()
+	server.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	for name := range dependencies {
+		server.SetServingStatus(name, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+	return HealthServer{
+		server:       server,
+		dependencies: dependencies,
+		interval:     interval,
+		logger:       logger,
+	}
+}
@@ -0,0 +1,94 @@
+// +build !integration all
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/logger"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type dependencyFake struct {
+	err error
+}
+
+func (d dependencyFake) PingContext(ctx context.Context) error {
+	return d.err
+}
+
+func TestHealthServer_CheckDependencies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		dependencies   map[string]Dependency
+		expectedStatus map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name: "all dependencies are healthy",
+			dependencies: map[string]Dependency{
+				"db":  dependencyFake{},
+				"kgs": dependencyFake{},
+			},
+			expectedStatus: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+				"":    grpc_health_v1.HealthCheckResponse_SERVING,
+				"db":  grpc_health_v1.HealthCheckResponse_SERVING,
+				"kgs": grpc_health_v1.HealthCheckResponse_SERVING,
+			},
+		},
+		{
+			name: "one dependency is unreachable",
+			dependencies: map[string]Dependency{
+				"db":  dependencyFake{},
+				"kgs": dependencyFake{err: errors.New("connection refused")},
+			},
+			expectedStatus: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+				"":    grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+				"db":  grpc_health_v1.HealthCheckResponse_SERVING,
+				"kgs": grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			healthServer := NewHealthServer(time.Minute, testCase.dependencies, lg)
+			healthServer.CheckDependencies()
+
+			for service, expectedStatus := range testCase.expectedStatus {
+				req := grpc_health_v1.HealthCheckRequest{Service: service}
+				res, err := healthServer.server.Check(context.Background(), &req)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, expectedStatus, res.GetStatus())
+			}
+		})
+	}
+}
+
+func TestNewHealthServer(t *testing.T) {
+	t.Parallel()
+
+	entryRepo := logger.NewEntryRepoFake()
+	lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+	assert.Equal(t, nil, err)
+
+	dependencies := map[string]Dependency{"db": dependencyFake{}}
+	healthServer := NewHealthServer(time.Minute, dependencies, lg)
+
+	req := grpc_health_v1.HealthCheckRequest{Service: "db"}
+	res, err := healthServer.server.Check(context.Background(), &req)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, res.GetStatus())
+}
@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/short-d/short/backend/app/adapter/request"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+// Observability tags each gRPC call with a request ID and records its latency
+// and failures.
+type Observability struct {
+	instrumentationFactory request.InstrumentationFactory
+}
+
+// Intercept instruments the call handled by the handler. The request ID is
+// taken from the caller when provided and returned in the response header.
+func (o Observability) Intercept(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	requestID := o.getRequestID(ctx)
+	ins := o.instrumentationFactory.NewRPC(requestID)
+
+	// Setting header only fails when the call is not served over a gRPC
+	// transport, which leaves nothing to return the request ID to.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+
+	res, err := handler(ctx, req)
+	if err != nil {
+		ins.RPCFailed(info.FullMethod, err)
+	}
+	ins.RPCHandled()
+	return res, err
+}
+
+func (o Observability) getRequestID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		values := md.Get(requestIDMetadataKey)
+		if len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	// Request IDs are generated locally so that frequent calls, such as health
+	// checks, don't use up the keys reserved for short links.
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// NewObservability creates Observability.
+func NewObservability(instrumentationFactory request.InstrumentationFactory) Observability {
+	return Observability{instrumentationFactory: instrumentationFactory}
+}
@@ -0,0 +1,92 @@
+// +build !integration all
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/analytics"
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/metrics"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestObservability_Intercept(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		incomingRequestID *string
+		handlerErr        error
+		expectedRequestID *string
+	}{
+		{
+			name: "generate request ID",
+		},
+		{
+			name:              "reuse request ID from caller",
+			incomingRequestID: ptr.String("from-caller"),
+			expectedRequestID: ptr.String("from-caller"),
+		},
+		{
+			name:       "return handler error",
+			handlerErr: errors.New("handler failed"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{"generated", "next"})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+
+			factory := request.NewInstrumentationFactory(
+				lg,
+				timer.NewStub(time.Now()),
+				metrics.NewFake(),
+				analytics.NewFake(),
+				keyGen,
+				request.Client{},
+			)
+			observability := NewObservability(factory)
+
+			ctx := context.Background()
+			if testCase.incomingRequestID != nil {
+				md := metadata.Pairs(requestIDMetadataKey, *testCase.incomingRequestID)
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", testCase.handlerErr
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/proto.ShortLinkService/GetShortLink"}
+			requestID := observability.getRequestID(ctx)
+			if testCase.expectedRequestID == nil {
+				assert.Equal(t, 32, len(requestID))
+				assert.NotEqual(t, requestID, observability.getRequestID(ctx))
+			} else {
+				assert.Equal(t, *testCase.expectedRequestID, requestID)
+			}
+
+			res, err := observability.Intercept(ctx, nil, info, handler)
+			assert.Equal(t, testCase.handlerErr, err)
+			assert.Equal(t, "response", res)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/rpc"
+	"github.com/short-d/app/fw/security"
+	"github.com/short-d/app/fw/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+var _ service.Service = (*Service)(nil)
+
+// Service serves the gRPC API. Unlike service.GRPC, it runs every request
+// through the interceptors of Short backend and reports its health.
+type Service struct {
+	server           *grpc.Server
+	api              rpc.API
+	healthServer     HealthServer
+	enableReflection bool
+	logger           logger.Logger
+}
+
+// Stop stops the gRPC server immediately.
+func (s Service) Stop() {
+	s.server.Stop()
+}
+
+// StartAsync starts the gRPC server without blocking the caller.
+func (s Service) StartAsync(port int) {
+	defer s.logger.Info(fmt.Sprintf("gRPC service started at localhost:%d", port))
+
+	go func() {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			s.logger.Error(err)
+			panic(err)
+		}
+
+		s.api.RegisterServers(s.server)
+		grpc_health_v1.RegisterHealthServer(s.server, s.healthServer.server)
+		if s.enableReflection {
+			reflection.Register(s.server)
+		}
+		s.healthServer.StartAsync()
+
+		err = s.server.Serve(lis)
+		if err != nil {
+			s.logger.Error(err)
+		}
+	}()
+}
+
+// StartAndWait starts the gRPC server and blocks the caller forever.
+func (s Service) StartAndWait(port int) {
+	s.StartAsync(port)
+	select {}
+}
+
+// NewService creates gRPC service.
+func NewService(
+	logger logger.Logger,
+	api rpc.API,
+	securityPolicy security.Policy,
+	healthServer HealthServer,
+	enableReflection bool,
+	observability Observability,
+	apiKeyAuth APIKeyAuth,
+) (Service, error) {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			observability.Intercept,
+			apiKeyAuth.Intercept,
+		),
+	}
+
+	if securityPolicy.IsEncrypted {
+		cred, err := credentials.NewServerTLSFromFile(
+			securityPolicy.CertificateFilePath,
+			securityPolicy.KeyFilePath,
+		)
+		if err != nil {
+			return Service{}, err
+		}
+		options = append(options, grpc.Creds(cred))
+	}
+
+	return Service{
+		server:           grpc.NewServer(options...),
+		api:              api,
+		healthServer:     healthServer,
+		enableReflection: enableReflection,
+		logger:           logger,
+	}, nil
+}
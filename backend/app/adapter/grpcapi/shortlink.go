@@ -0,0 +1,157 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/adapter/grpcapi/proto"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ShortLinkServer allows internal services to manage short links on behalf of
+// signed in users.
+type ShortLinkServer struct {
+	creator       shortlink.Creator
+	retriever     shortlink.Retriever
+	updater       shortlink.Updater
+	authenticator authenticator.Authenticator
+	timer         timer.Timer
+}
+
+var _ proto.ShortLinkServiceServer = (*ShortLinkServer)(nil)
+
+// CreateShortLink creates a short link for the user.
+func (s ShortLinkServer) CreateShortLink(ctx context.Context, req *proto.CreateShortLinkRequest) (*proto.CreateShortLinkResponse, error) {
+	user, err := s.getUser(req.GetAuthToken())
+	if err != nil {
+		return &proto.CreateShortLinkResponse{}, err
+	}
+
+	longLink := req.GetLongLink()
+	shortLinkInput := entity.ShortLinkInput{
+		LongLink:    &longLink,
+		CustomAlias: optionalString(req.GetCustomAlias()),
+	}
+	if req.GetExpireAt() != nil {
+		expireAt, err := ptypes.Timestamp(req.GetExpireAt())
+		if err != nil {
+			return &proto.CreateShortLinkResponse{}, status.Errorf(codes.InvalidArgument, "invalid expire at: %v", err)
+		}
+		shortLinkInput.ExpireAt = &expireAt
+	}
+
+	shortLink, err := s.creator.CreateShortLink(shortLinkInput, user, false)
+	if err != nil {
+		return &proto.CreateShortLinkResponse{}, toStatusError(err)
+	}
+	return &proto.CreateShortLinkResponse{ShortLink: newShortLink(shortLink)}, nil
+}
+
+// GetShortLink fetches the short link with the given alias unless it has
+// expired.
+func (s ShortLinkServer) GetShortLink(ctx context.Context, req *proto.GetShortLinkRequest) (*proto.GetShortLinkResponse, error) {
+	now := s.timer.Now()
+	shortLink, err := s.retriever.GetShortLink(req.GetAlias(), &now)
+	if err != nil {
+		return &proto.GetShortLinkResponse{}, toStatusError(err)
+	}
+	return &proto.GetShortLinkResponse{ShortLink: newShortLink(shortLink)}, nil
+}
+
+// UpdateShortLink changes the alias or the long link of a short link owned by
+// the user. Empty fields are left unchanged.
+func (s ShortLinkServer) UpdateShortLink(ctx context.Context, req *proto.UpdateShortLinkRequest) (*proto.UpdateShortLinkResponse, error) {
+	user, err := s.getUser(req.GetAuthToken())
+	if err != nil {
+		return &proto.UpdateShortLinkResponse{}, err
+	}
+
+	shortLinkInput := entity.ShortLinkInput{
+		LongLink:    optionalString(req.GetLongLink()),
+		CustomAlias: optionalString(req.GetNewAlias()),
+	}
+	shortLink, err := s.updater.UpdateShortLink(req.GetAlias(), shortLinkInput, user)
+	if err != nil {
+		return &proto.UpdateShortLinkResponse{}, toStatusError(err)
+	}
+	return &proto.UpdateShortLinkResponse{ShortLink: newShortLink(shortLink)}, nil
+}
+
+// ListShortLinks fetches all short links created by the user.
+func (s ShortLinkServer) ListShortLinks(ctx context.Context, req *proto.ListShortLinksRequest) (*proto.ListShortLinksResponse, error) {
+	user, err := s.getUser(req.GetAuthToken())
+	if err != nil {
+		return &proto.ListShortLinksResponse{}, err
+	}
+
+	shortLinks, err := s.retriever.GetShortLinksByUser(user)
+	if err != nil {
+		return &proto.ListShortLinksResponse{}, toStatusError(err)
+	}
+
+	res := &proto.ListShortLinksResponse{}
+	for _, shortLink := range shortLinks {
+		res.ShortLinks = append(res.ShortLinks, newShortLink(shortLink))
+	}
+	return res, nil
+}
+
+func (s ShortLinkServer) getUser(authToken string) (entity.User, error) {
+	user, err := s.authenticator.GetUser(authToken)
+	if err != nil {
+		return entity.User{}, status.Error(codes.Unauthenticated, "invalid auth token")
+	}
+	return user, nil
+}
+
+func newShortLink(shortLink entity.ShortLink) *proto.ShortLink {
+	return &proto.ShortLink{
+		Alias:     shortLink.Alias,
+		LongLink:  shortLink.LongLink,
+		ExpireAt:  newTimestamp(shortLink.ExpireAt),
+		CreatedAt: newTimestamp(shortLink.CreatedAt),
+		UpdatedAt: newTimestamp(shortLink.UpdatedAt),
+	}
+}
+
+func newTimestamp(t *time.Time) *timestamp.Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts, err := ptypes.TimestampProto(*t)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+func optionalString(str string) *string {
+	if str == "" {
+		return nil
+	}
+	return &str
+}
+
+// NewShortLinkServer creates ShortLink gRPC server
+func NewShortLinkServer(
+	creator shortlink.Creator,
+	retriever shortlink.Retriever,
+	updater shortlink.Updater,
+	authenticator authenticator.Authenticator,
+	timer timer.Timer,
+) proto.ShortLinkServiceServer {
+	return ShortLinkServer{
+		creator:       creator,
+		retriever:     retriever,
+		updater:       updater,
+		authenticator: authenticator,
+		timer:         timer,
+	}
+}
@@ -2,10 +2,14 @@ package kgs
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/short-d/app/fw/rpc"
 	"github.com/short-d/kgs/app/adapter/rpc/proto"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 var _ keygen.KeyFetcher = (*RPC)(nil)
@@ -13,7 +17,8 @@ var _ keygen.KeyFetcher = (*RPC)(nil)
 // RPC represents remote procedure calls which interact with key generation
 // service.
 type RPC struct {
-	gRPCClient proto.KeyGenClient
+	gRPCClient   proto.KeyGenClient
+	healthClient grpc_health_v1.HealthClient
 }
 
 // FetchKeys retrieves keys in batch from key generation service.
@@ -35,6 +40,24 @@ func (k RPC) FetchKeys(maxCount int) ([]keygen.Key, error) {
 	return keys, nil
 }
 
+// PingContext verifies that key generation service is reachable.
+func (k RPC) PingContext(ctx context.Context) error {
+	req := grpc_health_v1.HealthCheckRequest{}
+	res, err := k.healthClient.Check(ctx, &req)
+	if status.Code(err) == codes.Unimplemented {
+		// Key generation service does not report its health, but it
+		// answered the call.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if res.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("key generation service is %s", res.GetStatus())
+	}
+	return nil
+}
+
 // NewRPC initializes GRPC client for key generation service APIs.
 func NewRPC(hostname string, port int) (RPC, error) {
 	connection, err := rpc.
@@ -45,5 +68,9 @@ func NewRPC(hostname string, port int) (RPC, error) {
 		return RPC{}, err
 	}
 	gRPCClient := proto.NewKeyGenClient(connection)
-	return RPC{gRPCClient: gRPCClient}, nil
+	healthClient := grpc_health_v1.NewHealthClient(connection)
+	return RPC{
+		gRPCClient:   gRPCClient,
+		healthClient: healthClient,
+	}, nil
 }
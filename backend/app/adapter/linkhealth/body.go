@@ -0,0 +1,63 @@
+package linkhealth
+
+import (
+	"io"
+	"net/http"
+)
+
+// bodyLimit is the maximum number of bytes read from the response body of a
+// destination.
+const bodyLimit = 4096
+
+// boundedBody stops reading the response body after the given number of
+// bytes and closes it once the reading stops, since webreq.HTTP reads the
+// whole body without closing it.
+type boundedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *boundedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		b.body.Close()
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if err != nil {
+		b.body.Close()
+	}
+	return n, err
+}
+
+func (b *boundedBody) Close() error {
+	return b.body.Close()
+}
+
+type boundedTransport struct {
+	transport http.RoundTripper
+	limit     int64
+}
+
+func (b boundedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := b.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = &boundedBody{body: res.Body, remaining: b.limit}
+	return res, nil
+}
+
+// LimitResponseBody makes the client read a bounded amount of each response
+// body and release the connection afterwards.
+func LimitResponseBody(client http.Client) http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.Transport = boundedTransport{transport: transport, limit: bodyLimit}
+	return client
+}
@@ -0,0 +1,55 @@
+package linkhealth
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+const defaultInterval = 10 * time.Minute
+
+// Job periodically checks the destinations of short links in the background.
+type Job struct {
+	checker                shortlink.HealthChecker
+	instrumentationFactory request.InstrumentationFactory
+	interval               time.Duration
+}
+
+// RunOnce checks the short links which are due for a check.
+func (j Job) RunOnce() (shortlink.HealthReport, error) {
+	ins := j.instrumentationFactory.NewRequest()
+	return j.checker.CheckLinks(ins)
+}
+
+// StartAsync runs the job immediately and then once every interval without
+// blocking the caller.
+func (j Job) StartAsync() {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			// Failures are reported through instrumentation and retried
+			// in the next run.
+			_, _ = j.RunOnce()
+			<-ticker.C
+		}
+	}()
+}
+
+// NewJob creates Job.
+func NewJob(
+	checker shortlink.HealthChecker,
+	instrumentationFactory request.InstrumentationFactory,
+	interval time.Duration,
+) Job {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return Job{
+		checker:                checker,
+		instrumentationFactory: instrumentationFactory,
+		interval:               interval,
+	}
+}
@@ -0,0 +1,83 @@
+// +build !integration all
+
+package linkhealth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/analytics"
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/metrics"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+func TestJob_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	now := time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC)
+	shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{
+		"ok":   {Alias: "ok", LongLink: server.URL + "/ok"},
+		"gone": {Alias: "gone", LongLink: server.URL + "/gone"},
+	})
+
+	entryRepo := logger.NewEntryRepoFake()
+	lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+	assert.Equal(t, nil, err)
+
+	keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{"request"})
+	keyGen, err := keygen.NewKeyGenerator(1, &keyFetcher)
+	assert.Equal(t, nil, err)
+
+	tm := timer.NewStub(now)
+	factory := request.NewInstrumentationFactory(
+		lg,
+		tm,
+		metrics.NewFake(),
+		analytics.NewFake(),
+		keyGen,
+		request.Client{},
+	)
+	prober := NewHTTPProber(webreq.NewHTTP(LimitResponseBody(webreq.NewHTTPClient())))
+	checker := shortlink.NewHealthChecker(
+		shortlink.HealthPolicy{},
+		&shortLinkRepo,
+		prober,
+		tm,
+	)
+
+	job := NewJob(checker, factory, time.Hour)
+	report, err := job.RunOnce()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, shortlink.HealthReport{Checked: 2, Broken: 1}, report)
+
+	expectedHealths := map[string]entity.LinkHealth{
+		"ok":   {StatusCode: ptr.Int(http.StatusOK), CheckedAt: &now},
+		"gone": {StatusCode: ptr.Int(http.StatusGone), CheckedAt: &now},
+	}
+	for alias, expectedHealth := range expectedHealths {
+		shortLink, err := shortLinkRepo.GetShortLinkByAlias(alias)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expectedHealth, shortLink.Health)
+	}
+}
@@ -0,0 +1,83 @@
+package linkhealth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+var _ shortlink.LinkProber = (*HTTPProber)(nil)
+
+const userAgent = "Short-LinkHealthChecker/1.0"
+
+// HTTPProber requests long links over HTTP, preferring HEAD requests and
+// falling back to GET for servers which do not support HEAD.
+type HTTPProber struct {
+	http webreq.HTTP
+}
+
+// Probe returns the status code responded by the destination of the long link.
+func (h HTTPProber) Probe(longLink string) (int, error) {
+	statusCode, err := h.request(http.MethodHead, longLink)
+	if err != nil {
+		return 0, err
+	}
+
+	switch statusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return h.request(http.MethodGet, longLink)
+	default:
+		return statusCode, nil
+	}
+}
+
+// request sends a request to the long link. webreq.HTTP only reports the
+// status of failed responses, so the other responses are reported as
+// http.StatusOK.
+func (h HTTPProber) request(method string, longLink string) (int, error) {
+	headers := map[string]string{"User-Agent": userAgent}
+	var body interface{}
+	err := h.http.JSON(method, longLink, headers, "", &body)
+	if err == nil {
+		return http.StatusOK, nil
+	}
+
+	// Destinations are rarely JSON APIs, so failing to decode the body still
+	// means the destination responded successfully.
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return http.StatusOK, nil
+	}
+
+	statusCode, ok := parseStatusCode(err.Error())
+	if !ok {
+		return 0, err
+	}
+	return statusCode, nil
+}
+
+// parseStatusCode extracts the status code from the status of a failed
+// response, such as "404 Not Found".
+func parseStatusCode(status string) (int, bool) {
+	fields := strings.Fields(status)
+	if len(fields) < 1 {
+		return 0, false
+	}
+	statusCode, err := strconv.Atoi(fields[0])
+	if err != nil || statusCode < http.StatusBadRequest {
+		return 0, false
+	}
+	return statusCode, true
+}
+
+// NewHTTPProber creates HTTPProber. The client behind http should be guarded
+// with netguard.Guard since long links are supplied by users.
+func NewHTTPProber(http webreq.HTTP) HTTPProber {
+	return HTTPProber{http: http}
+}
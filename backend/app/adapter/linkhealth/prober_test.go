@@ -0,0 +1,117 @@
+// +build !integration all
+
+package linkhealth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/webreq"
+)
+
+func TestHTTPProber_Probe(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/gone", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("<p>short</p>", 10000)))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	closedServer := httptest.NewServer(mux)
+	closedServer.Close()
+
+	testCases := []struct {
+		name               string
+		longLink           string
+		hasErr             bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "destination is reachable",
+			longLink:           server.URL + "/ok",
+			hasErr:             false,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "destination is not found",
+			longLink:           server.URL + "/gone",
+			hasErr:             false,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "fall back to GET when HEAD is not allowed",
+			longLink:           server.URL + "/get-only",
+			hasErr:             false,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "follow redirects",
+			longLink:           server.URL + "/moved",
+			hasErr:             false,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "read a bounded amount of large pages",
+			longLink:           server.URL + "/large",
+			hasErr:             false,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:     "destination times out",
+			longLink: server.URL + "/slow",
+			hasErr:   true,
+		},
+		{
+			name:     "destination is unreachable",
+			longLink: closedServer.URL + "/ok",
+			hasErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			client := webreq.NewHTTPClient()
+			client.Timeout = 50 * time.Millisecond
+			prober := NewHTTPProber(webreq.NewHTTP(LimitResponseBody(client)))
+			statusCode, err := prober.Probe(testCase.longLink)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedStatusCode, statusCode)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// +build !integration all
+
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestIsPrivateIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		ip        string
+		isPrivate bool
+	}{
+		{name: "loopback", ip: "127.0.0.1", isPrivate: true},
+		{name: "private network", ip: "10.1.2.3", isPrivate: true},
+		{name: "cloud metadata", ip: "169.254.169.254", isPrivate: true},
+		{name: "unspecified", ip: "0.0.0.0", isPrivate: true},
+		{name: "IPv4 mapped loopback", ip: "::ffff:127.0.0.1", isPrivate: true},
+		{name: "IPv6 loopback", ip: "::1", isPrivate: true},
+		{name: "IPv6 unique local", ip: "fd00::1", isPrivate: true},
+		{name: "IPv6 link-local", ip: "fe80::1", isPrivate: true},
+		{name: "public", ip: "93.184.216.34", isPrivate: false},
+		{name: "IPv6 public", ip: "2606:2800:220:1::1", isPrivate: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.isPrivate, IsPrivateIP(net.ParseIP(testCase.ip)))
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	assert.Equal(t, nil, err)
+	res.Body.Close()
+
+	client := Guard(http.Client{})
+	_, err = client.Get(server.URL)
+	var privateAddress ErrPrivateAddress
+	assert.Equal(t, true, errors.As(err, &privateAddress))
+}
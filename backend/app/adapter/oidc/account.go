@@ -0,0 +1,49 @@
+package oidc
+
+import (
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/sso"
+)
+
+var _ sso.Account = (*Account)(nil)
+
+// Account reads user's account data from the ID token issued by an OpenID
+// Connect identity provider.
+type Account struct {
+	issuer   Issuer
+	timer    timer.Timer
+	clientID string
+}
+
+// GetSingleSignOnUser verifies the ID token and retrieves user's subject,
+// email and name from its claims. The email is only trusted when the identity
+// provider has verified it, otherwise it could be used to take over an
+// existing account with the same email.
+func (a Account) GetSingleSignOnUser(idToken string) (entity.SSOUser, error) {
+	claims, err := verifyIDToken(a.issuer, a.clientID, a.timer.Now(), idToken)
+	if err != nil {
+		return entity.SSOUser{}, err
+	}
+
+	ssoUser := entity.SSOUser{
+		ID:   claims.Subject,
+		Name: claims.Name,
+	}
+	if ssoUser.Name == "" {
+		ssoUser.Name = claims.PreferredUsername
+	}
+	if claims.EmailVerified {
+		ssoUser.Email = claims.Email
+	}
+	return ssoUser, nil
+}
+
+// NewAccount initializes OpenID Connect account client.
+func NewAccount(issuer Issuer, timer timer.Timer, config Config) Account {
+	return Account{
+		issuer:   issuer,
+		timer:    timer,
+		clientID: config.ClientID,
+	}
+}
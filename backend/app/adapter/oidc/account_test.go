@@ -0,0 +1,156 @@
+// +build !integration all
+
+package oidc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestAccount_GetSingleSignOnUser(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	stub := newStubIssuer(t)
+	defer stub.server.Close()
+
+	newClaims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"iss":            stub.url(),
+			"sub":            "00u1a2b3c",
+			"aud":            "short",
+			"exp":            now.Add(time.Hour).Unix(),
+			"iat":            now.Unix(),
+			"email":          "alpha@example.com",
+			"email_verified": true,
+			"name":           "Alpha",
+		}
+		for key, value := range overrides {
+			if value == nil {
+				delete(claims, key)
+				continue
+			}
+			claims[key] = value
+		}
+		return claims
+	}
+
+	otherIssuer := newStubIssuer(t)
+	defer otherIssuer.server.Close()
+	otherIssuer.keyID = stub.keyID
+
+	testCases := []struct {
+		name            string
+		idToken         string
+		hasErr          bool
+		expectedSSOUser entity.SSOUser
+	}{
+		{
+			name:    "malformed token",
+			idToken: "not.a-token",
+			hasErr:  true,
+		},
+		{
+			name:    "signed by other issuer",
+			idToken: otherIssuer.signIDToken(t, newClaims(nil)),
+			hasErr:  true,
+		},
+		{
+			name: "signature tampered",
+			idToken: func() string {
+				parts := strings.Split(stub.signIDToken(t, newClaims(nil)), ".")
+				forged := stub.signIDToken(t, newClaims(map[string]interface{}{"sub": "admin"}))
+				return strings.Join([]string{parts[0], strings.Split(forged, ".")[1], parts[2]}, ".")
+			}(),
+			hasErr: true,
+		},
+		{
+			name:    "issuer mismatch",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			hasErr:  true,
+		},
+		{
+			name:    "audience mismatch",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{"aud": "other"})),
+			hasErr:  true,
+		},
+		{
+			name: "authorized party mismatch",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{
+				"aud": []string{"short", "other"},
+				"azp": "other",
+			})),
+			hasErr: true,
+		},
+		{
+			name:    "token expired",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{"exp": now.Unix()})),
+			hasErr:  true,
+		},
+		{
+			name:    "subject missing",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{"sub": nil})),
+			hasErr:  true,
+		},
+		{
+			name:    "verified email",
+			idToken: stub.signIDToken(t, newClaims(nil)),
+			hasErr:  false,
+			expectedSSOUser: entity.SSOUser{
+				ID:    "00u1a2b3c",
+				Email: "alpha@example.com",
+				Name:  "Alpha",
+			},
+		},
+		{
+			name: "unverified email ignored",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{
+				"email_verified":     false,
+				"name":               nil,
+				"preferred_username": "alpha",
+			})),
+			hasErr: false,
+			expectedSSOUser: entity.SSOUser{
+				ID:   "00u1a2b3c",
+				Name: "alpha",
+			},
+		},
+		{
+			name: "multiple audiences",
+			idToken: stub.signIDToken(t, newClaims(map[string]interface{}{
+				"aud": []string{"short", "other"},
+				"azp": "short",
+			})),
+			hasErr: false,
+			expectedSSOUser: entity.SSOUser{
+				ID:    "00u1a2b3c",
+				Email: "alpha@example.com",
+				Name:  "Alpha",
+			},
+		},
+	}
+
+	httpRequest := webreq.NewHTTP(webreq.NewHTTPClient())
+	account := NewAccount(NewIssuer(httpRequest, stub.url()), timer.NewStub(now), newConfig(stub.url()))
+
+	for _, testCase := range testCases {
+		// Subtests share the stub issuer and hence run sequentially before
+		// it is closed.
+		t.Run(testCase.name, func(t *testing.T) {
+			gotSSOUser, err := account.GetSingleSignOnUser(testCase.idToken)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedSSOUser, gotSSOUser)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/usecase/sso"
+)
+
+var _ sso.IdentityProvider = (*IdentityProvider)(nil)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// Config contains the settings of an OpenID Connect identity provider
+// registered for Short.
+type Config struct {
+	// Name identifies the identity provider, such as okta. It is recorded on
+	// the linked accounts and the sessions.
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	// Scopes defaults to openid, email and profile when empty.
+	Scopes []string
+}
+
+// IdentityProvider represents an OpenID Connect identity provider which
+// signs users in with authorization code flow and PKCE.
+type IdentityProvider struct {
+	issuer Issuer
+	http   webreq.HTTP
+	config Config
+	secret []byte
+}
+
+// GetAuthorizationURL retrieves the URL of the identity provider's sign in
+// page. Each state gets its own code verifier, which is derived from the state
+// again when the user comes back.
+func (i IdentityProvider) GetAuthorizationURL(state string) string {
+	md, err := i.issuer.getMetadata()
+	if err != nil {
+		return ""
+	}
+
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return ""
+	}
+
+	codeVerifier := deriveCodeVerifier(i.secret, state)
+
+	scopes := i.config.Scopes
+	if len(scopes) < 1 {
+		scopes = defaultScopes
+	}
+
+	query := u.Query()
+	query.Set("client_id", i.config.ClientID)
+	query.Set("redirect_uri", i.config.RedirectURI)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("response_type", "code")
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// RequestAccessToken exchanges the authorization code for the user's ID token.
+// The ID token is verified by Account before its claims are trusted. The
+// state must be verified against the browser beforehand.
+func (i IdentityProvider) RequestAccessToken(authorizationCode string, state string) (string, error) {
+	codeVerifier := deriveCodeVerifier(i.secret, state)
+
+	md, err := i.issuer.getMetadata()
+	if err != nil {
+		return "", err
+	}
+
+	body := url.Values{}
+	body.Set("grant_type", "authorization_code")
+	body.Set("code", authorizationCode)
+	body.Set("redirect_uri", i.config.RedirectURI)
+	body.Set("client_id", i.config.ClientID)
+	body.Set("client_secret", i.config.ClientSecret)
+	body.Set("code_verifier", codeVerifier)
+
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	}
+
+	apiRes := tokenResponse{}
+	err = i.http.JSON(http.MethodPost, md.TokenEndpoint, headers, body.Encode(), &apiRes)
+	if err != nil {
+		return "", err
+	}
+	if apiRes.IDToken == "" {
+		return "", errors.New("ID token missing in token response")
+	}
+	return apiRes.IDToken, nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// NewIdentityProvider initializes OpenID Connect identity provider. The secret
+// is used to derive the PKCE code verifiers and must be shared by all the
+// instances.
+func NewIdentityProvider(
+	issuer Issuer,
+	http webreq.HTTP,
+	config Config,
+	secret []byte,
+) IdentityProvider {
+	return IdentityProvider{
+		issuer: issuer,
+		http:   http,
+		config: config,
+		secret: secret,
+	}
+}
@@ -0,0 +1,294 @@
+// +build !integration all
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/webreq"
+)
+
+// stubIssuer is a local OpenID Connect provider which issues the given ID
+// token to the authorizations registered beforehand. Each authorization code
+// can only be exchanged once.
+type stubIssuer struct {
+	server     *httptest.Server
+	key        *rsa.PrivateKey
+	keyID      string
+	mutex      sync.Mutex
+	challenges map[string]string
+	idToken    string
+}
+
+func (s *stubIssuer) url() string {
+	return s.server.URL
+}
+
+func (s *stubIssuer) authorize(code string, codeChallenge string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.challenges[code] = codeChallenge
+}
+
+func (s *stubIssuer) setIDToken(idToken string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.idToken = idToken
+}
+
+func (s *stubIssuer) signIDToken(t *testing.T, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": s.keyID})
+	assert.Equal(t, nil, err)
+	payload, err := json.Marshal(claims)
+	assert.Equal(t, nil, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	assert.Equal(t, nil, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (s *stubIssuer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case discoveryPath:
+		writeJSON(w, map[string]string{
+			"issuer":                 s.server.URL,
+			"authorization_endpoint": s.server.URL + "/authorize",
+			"token_endpoint":         s.server.URL + "/token",
+			"jwks_uri":               s.server.URL + "/jwks",
+		})
+	case "/jwks":
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": s.keyID,
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+				},
+			},
+		})
+	case "/token":
+		err := r.ParseForm()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		challenge, ok := s.challenges[r.PostForm.Get("code")]
+		if !ok || r.PostForm.Get("grant_type") != "authorization_code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if codeChallenge(r.PostForm.Get("code_verifier")) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delete(s.challenges, r.PostForm.Get("code"))
+		writeJSON(w, map[string]string{
+			"access_token": "access_token",
+			"token_type":   "Bearer",
+			"id_token":     s.idToken,
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, nil, err)
+
+	issuer := &stubIssuer{
+		key:        key,
+		keyID:      "key1",
+		challenges: make(map[string]string),
+	}
+	issuer.server = httptest.NewServer(http.HandlerFunc(issuer.handle))
+	return issuer
+}
+
+func newConfig(issuerURL string) Config {
+	return Config{
+		Name:         "corp",
+		IssuerURL:    issuerURL,
+		ClientID:     "short",
+		ClientSecret: "secret",
+		RedirectURI:  "http://localhost/oauth/oidc/sign-in/callback",
+	}
+}
+
+func TestIdentityProvider_GetAuthorizationURL(t *testing.T) {
+	t.Parallel()
+
+	stub := newStubIssuer(t)
+	defer stub.server.Close()
+
+	httpRequest := webreq.NewHTTP(webreq.NewHTTPClient())
+	config := newConfig(stub.url())
+	identityProvider := NewIdentityProvider(
+		NewIssuer(httpRequest, stub.url()), httpRequest, config, []byte("secret"),
+	)
+
+	first, err := url.Parse(identityProvider.GetAuthorizationURL("first"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, stub.url()+"/authorize", first.Scheme+"://"+first.Host+first.Path)
+
+	query := first.Query()
+	assert.Equal(t, config.ClientID, query.Get("client_id"))
+	assert.Equal(t, config.RedirectURI, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "first", query.Get("state"))
+	assert.Equal(t, 43, len(query.Get("code_challenge")))
+
+	second, err := url.Parse(identityProvider.GetAuthorizationURL("second"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "second", second.Query().Get("state"))
+	assert.NotEqual(t, query.Get("code_challenge"), second.Query().Get("code_challenge"))
+
+	again, err := url.Parse(identityProvider.GetAuthorizationURL("first"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, query.Get("code_challenge"), again.Query().Get("code_challenge"))
+}
+
+func TestIdentityProvider_RequestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		code          string
+		state         func(authorizedState string) string
+		secret        string
+		exchangeTwice bool
+		hasErr        bool
+	}{
+		{
+			name: "unknown state",
+			code: "authorized",
+			state: func(authorizedState string) string {
+				return "unknown"
+			},
+			secret: "secret",
+			hasErr: true,
+		},
+		{
+			name: "secret not shared",
+			code: "authorized",
+			state: func(authorizedState string) string {
+				return authorizedState
+			},
+			secret: "other_secret",
+			hasErr: true,
+		},
+		{
+			name: "authorization code rejected",
+			code: "unknown",
+			state: func(authorizedState string) string {
+				return authorizedState
+			},
+			secret: "secret",
+			hasErr: true,
+		},
+		{
+			name: "authorization code reused",
+			code: "authorized",
+			state: func(authorizedState string) string {
+				return authorizedState
+			},
+			secret:        "secret",
+			exchangeTwice: true,
+			hasErr:        true,
+		},
+		{
+			name: "ID token issued",
+			code: "authorized",
+			state: func(authorizedState string) string {
+				return authorizedState
+			},
+			secret: "secret",
+			hasErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			stub := newStubIssuer(t)
+			defer stub.server.Close()
+			stub.setIDToken("id_token")
+
+			httpRequest := webreq.NewHTTP(webreq.NewHTTPClient())
+			config := newConfig(stub.url())
+			signInInstance := NewIdentityProvider(
+				NewIssuer(httpRequest, stub.url()), httpRequest, config, []byte("secret"),
+			)
+
+			authURL, err := url.Parse(signInInstance.GetAuthorizationURL("authorized_state"))
+			assert.Equal(t, nil, err)
+			stub.authorize("authorized", authURL.Query().Get("code_challenge"))
+			state := testCase.state(authURL.Query().Get("state"))
+
+			// The callback may be handled by another instance.
+			identityProvider := NewIdentityProvider(
+				NewIssuer(httpRequest, stub.url()), httpRequest, config, []byte(testCase.secret),
+			)
+			if testCase.exchangeTwice {
+				_, err = identityProvider.RequestAccessToken(testCase.code, state)
+				assert.Equal(t, nil, err)
+			}
+
+			idToken, err := identityProvider.RequestAccessToken(testCase.code, state)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "id_token", idToken)
+		})
+	}
+}
+
+func TestIssuer_getMetadata(t *testing.T) {
+	t.Parallel()
+
+	stub := newStubIssuer(t)
+	defer stub.server.Close()
+
+	httpRequest := webreq.NewHTTP(webreq.NewHTTPClient())
+
+	issuer := NewIssuer(httpRequest, stub.url()+"/")
+	_, err := issuer.getMetadata()
+	assert.NotEqual(t, nil, err)
+
+	issuer = NewIssuer(httpRequest, stub.url())
+	md, err := issuer.getMetadata()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, strings.HasSuffix(md.TokenEndpoint, "/token"))
+}
@@ -0,0 +1,145 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidIDToken represents an ID token which is malformed, not signed by
+// the issuer or not issued to Short.
+type ErrInvalidIDToken struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidIDToken)(nil)
+
+func (e ErrInvalidIDToken) Error() string {
+	return fmt.Sprintf("invalid ID token: %s", e.Reason)
+}
+
+type idTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// audience accepts both the single string and the array forms of the aud
+// claim.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// idTokenClaims contains the claims of an ID token used by Short.
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+type idTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	ExpireAt          int64    `json:"exp"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// verifyIDToken checks the RS256 signature and the standard claims of an ID
+// token before returning its claims.
+func verifyIDToken(
+	issuer Issuer,
+	clientID string,
+	now time.Time,
+	rawIDToken string,
+) (idTokenClaims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "malformed token"}
+	}
+
+	var header idTokenHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "malformed header"}
+	}
+	if header.Algorithm != "RS256" {
+		return idTokenClaims{}, ErrInvalidIDToken{
+			Reason: fmt.Sprintf("unsupported algorithm %s", header.Algorithm),
+		}
+	}
+
+	key, err := issuer.getKey(header.KeyID)
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "malformed signature"}
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "signature mismatch"}
+	}
+
+	var claims idTokenClaims
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "malformed claims"}
+	}
+
+	md, err := issuer.getMetadata()
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+	if claims.Issuer != md.Issuer {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "issuer mismatch"}
+	}
+	if !claims.Audience.contains(clientID) {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "audience mismatch"}
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != clientID {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "authorized party mismatch"}
+	}
+	if !now.Before(time.Unix(claims.ExpireAt, 0)) {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "token expired"}
+	}
+	if claims.Subject == "" {
+		return idTokenClaims{}, ErrInvalidIDToken{Reason: "subject missing"}
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	buf, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
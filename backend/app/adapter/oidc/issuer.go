@@ -0,0 +1,143 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/short-d/app/fw/webreq"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// metadata represents the provider configuration published by an OpenID
+// Connect issuer.
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Modulus string `json:"n"`
+	Exp     string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type issuerCache struct {
+	mutex    sync.Mutex
+	metadata *metadata
+	keys     map[string]*rsa.PublicKey
+}
+
+// Issuer discovers the endpoints and the signing keys of an OpenID Connect
+// provider. Both are fetched on first use and cached afterwards.
+type Issuer struct {
+	url   string
+	http  webreq.HTTP
+	cache *issuerCache
+}
+
+func (i Issuer) getMetadata() (metadata, error) {
+	i.cache.mutex.Lock()
+	defer i.cache.mutex.Unlock()
+
+	if i.cache.metadata != nil {
+		return *i.cache.metadata, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(i.url, "/") + discoveryPath
+	var md metadata
+	err := i.http.JSON(http.MethodGet, discoveryURL, map[string]string{}, "", &md)
+	if err != nil {
+		return metadata{}, err
+	}
+	if md.Issuer != i.url {
+		return metadata{}, fmt.Errorf("issuer(%s) does not match discovery document(%s)", i.url, md.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return metadata{}, errors.New("discovery document is incomplete")
+	}
+
+	i.cache.metadata = &md
+	return md, nil
+}
+
+// getKey retrieves the public key the issuer signs ID tokens with. The key set
+// is fetched again when the key is unknown so that key rotation is picked up.
+func (i Issuer) getKey(keyID string) (*rsa.PublicKey, error) {
+	md, err := i.getMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	i.cache.mutex.Lock()
+	defer i.cache.mutex.Unlock()
+
+	if key, ok := i.cache.keys[keyID]; ok {
+		return key, nil
+	}
+
+	var keySet jsonWebKeySet
+	err = i.http.JSON(http.MethodGet, md.JWKSURI, map[string]string{}, "", &keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range keySet.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAPublicKey(jwk)
+		if err != nil {
+			return nil, err
+		}
+		keys[jwk.KeyID] = key
+	}
+	i.cache.keys = keys
+
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key(%s) not found", keyID)
+	}
+	return key, nil
+}
+
+func parseRSAPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := base64.RawURLEncoding.DecodeString(jwk.Exp)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exp).Int64()),
+	}, nil
+}
+
+// NewIssuer creates Issuer for the OpenID Connect provider identified by the
+// given URL.
+func NewIssuer(http webreq.HTTP, url string) Issuer {
+	return Issuer{
+		url:   url,
+		http:  http,
+		cache: &issuerCache{},
+	}
+}
@@ -0,0 +1,30 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// codeVerifierPurpose separates the code verifiers from other values derived
+// from the same secret.
+const codeVerifierPurpose = "oidc_pkce_code_verifier:"
+
+// deriveCodeVerifier derives the PKCE code verifier of an authorization from
+// its state, so that the sign in can be completed by any instance without
+// keeping the verifier in memory. The state is signed, expires shortly and is
+// bound to the browser by sso.StateSigner, while the verifier can't be derived
+// without the secret.
+func deriveCodeVerifier(secret []byte, state string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(codeVerifierPurpose))
+	mac.Write([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// codeChallenge derives the S256 PKCE code challenge from the code verifier.
+// https://tools.ietf.org/html/rfc7636#section-4.2
+func codeChallenge(codeVerifier string) string {
+	digest := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
@@ -0,0 +1,11 @@
+package oidc
+
+import "github.com/short-d/short/backend/app/usecase/sso"
+
+// AccountLinker links user's account at the OpenID Connect identity provider
+// with Short account.
+type AccountLinker sso.AccountLinker
+
+// SingleSignOn enables users to sign in through an OpenID Connect identity
+// provider.
+type SingleSignOn sso.SingleSignOn
@@ -2,9 +2,11 @@ package request
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/short-d/app/fw/geo"
 	"github.com/short-d/app/fw/network"
+	"github.com/short-d/short/backend/app/entity"
 )
 
 // Client retrieves user device info.
@@ -23,6 +25,43 @@ func (c Client) GetLocation(request *http.Request) (geo.Location, error) {
 	return c.geo.GetLocation(clientIP)
 }
 
+// GetDevice extracts the IP, geo location and user agent of the user's device
+// from the HTTP request. The device is returned without location when the
+// location is not available.
+func (c Client) GetDevice(request *http.Request) (entity.Device, error) {
+	connection := c.network.FromHTTP(request)
+	device := entity.Device{
+		ClientIP:  connection.ClientIP,
+		UserAgent: request.UserAgent(),
+	}
+	if connection.ClientIP == "" {
+		return device, nil
+	}
+
+	location, err := c.geo.GetLocation(connection.ClientIP)
+	if err != nil {
+		return device, err
+	}
+	device.Location = formatLocation(location)
+	return device, nil
+}
+
+// formatLocation describes the location from the most specific place, such as
+// "San Francisco, California, United States".
+func formatLocation(location geo.Location) string {
+	var places []string
+	for _, place := range []string{
+		location.City,
+		location.Region.Name,
+		location.Country.Name,
+	} {
+		if place != "" {
+			places = append(places, place)
+		}
+	}
+	return strings.Join(places, ", ")
+}
+
 // NewClient creates user device info retriever.
 func NewClient(network network.Network, geo geo.Geo) Client {
 	return Client{
@@ -0,0 +1,114 @@
+// +build !integration all
+
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/geo"
+	"github.com/short-d/app/fw/network"
+	"github.com/short-d/short/backend/app/entity"
+)
+
+type geoFake struct {
+	locations map[string]geo.Location
+}
+
+func (g geoFake) GetLocation(ipAddress string) (geo.Location, error) {
+	location, ok := g.locations[ipAddress]
+	if !ok {
+		return geo.Location{}, errors.New("location not found")
+	}
+	return location, nil
+}
+
+func TestClient_GetDevice(t *testing.T) {
+	t.Parallel()
+
+	locations := map[string]geo.Location{
+		"10.0.0.1": {
+			Country: geo.Country{Code: "US", Name: "United States"},
+			Region:  geo.Region{Code: "CA", Name: "California"},
+			City:    "San Francisco",
+		},
+		"10.0.0.2": {
+			Country: geo.Country{Code: "CA", Name: "Canada"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		headers        map[string]string
+		hasErr         bool
+		expectedDevice entity.Device
+	}{
+		{
+			name:    "without client IP",
+			headers: map[string]string{"User-Agent": "Mozilla/5.0"},
+			expectedDevice: entity.Device{
+				UserAgent: "Mozilla/5.0",
+			},
+		},
+		{
+			name: "full location",
+			headers: map[string]string{
+				"X-Forwarded-For": "10.0.0.1",
+				"User-Agent":      "Mozilla/5.0",
+			},
+			expectedDevice: entity.Device{
+				ClientIP:  "10.0.0.1",
+				Location:  "San Francisco, California, United States",
+				UserAgent: "Mozilla/5.0",
+			},
+		},
+		{
+			name: "country only",
+			headers: map[string]string{
+				"X-Forwarded-For": "10.0.0.2",
+				"User-Agent":      "curl/7.64.1",
+			},
+			expectedDevice: entity.Device{
+				ClientIP:  "10.0.0.2",
+				Location:  "Canada",
+				UserAgent: "curl/7.64.1",
+			},
+		},
+		{
+			name: "location not available",
+			headers: map[string]string{
+				"X-Forwarded-For": "10.0.0.3",
+				"User-Agent":      "Mozilla/5.0",
+			},
+			hasErr: true,
+			expectedDevice: entity.Device{
+				ClientIP:  "10.0.0.3",
+				UserAgent: "Mozilla/5.0",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range testCase.headers {
+				req.Header.Set(name, value)
+			}
+
+			client := NewClient(network.NewProxy(), geoFake{locations: locations})
+			device, err := client.GetDevice(req)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+			} else {
+				assert.Equal(t, nil, err)
+			}
+			assert.Equal(t, testCase.expectedDevice, device)
+		})
+	}
+}
@@ -81,6 +81,29 @@ func (f InstrumentationFactory) NewRequest() instrumentation.Instrumentation {
 	)
 }
 
+// NewRPC creates and initializes Instrumentation for a gRPC call with the given
+// request ID.
+func (f InstrumentationFactory) NewRPC(requestID string) instrumentation.Instrumentation {
+	ctxCh := make(chan ctx.ExecutionContext)
+	startAt := f.timer.Now()
+
+	go func() {
+		c := ctx.ExecutionContext{
+			RequestID:      requestID,
+			RequestStartAt: startAt,
+		}
+		ctxCh <- c
+	}()
+
+	return instrumentation.NewInstrumentation(
+		f.logger,
+		f.timer,
+		f.metrics,
+		f.analytics,
+		ctxCh,
+	)
+}
+
 // NewInstrumentationFactory creates Instrumentation factory.
 func NewInstrumentationFactory(
 	logger logger.Logger,
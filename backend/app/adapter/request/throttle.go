@@ -0,0 +1,200 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/short-d/app/fw/ctx"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/metrics"
+	"github.com/short-d/app/fw/network"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// maxGraphQLAuthTokenScan is the maximum number of bytes of a GraphQL request
+// body read to find the auth token of the client.
+const maxGraphQLAuthTokenScan = 1 << 20
+
+// Throttler rejects the requests exceeding the rate limit of their clients.
+type Throttler struct {
+	limiter       ratelimit.Limiter
+	network       network.Network
+	authenticator authenticator.Authenticator
+	thirdPartyApp authenticator.ThirdPartyApp
+	metrics       metrics.Metrics
+	logger        logger.Logger
+	timer         timer.Timer
+}
+
+// Allow checks whether the client sending the request is still within the
+// limit of the given route. Otherwise, it responds with 429 Too Many Requests.
+func (t Throttler) Allow(
+	w http.ResponseWriter,
+	r *http.Request,
+	route string,
+	limit ratelimit.Limit,
+) bool {
+	return t.allow(w, r, route, limit, getBearerToken(r))
+}
+
+func (t Throttler) allow(
+	w http.ResponseWriter,
+	r *http.Request,
+	route string,
+	limit ratelimit.Limit,
+	authToken string,
+) bool {
+	if limit.IsUnlimited() {
+		return true
+	}
+
+	key := fmt.Sprintf("%s:%s", route, t.identifyClient(r, authToken))
+	decision, err := t.limiter.Allow(key, limit)
+	if err != nil {
+		// Fail open so that an unavailable store does not take down the API.
+		t.logger.Error(err)
+		return true
+	}
+	if decision.IsAllowed {
+		return true
+	}
+
+	t.metrics.Count("request-throttled", 1, 1, ctx.ExecutionContext{
+		RequestStartAt: t.timer.Now(),
+	})
+
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+	return false
+}
+
+// Throttle wraps the handler with the rate limit of the given route.
+func (t Throttler) Throttle(route string, limit ratelimit.Limit, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !t.Allow(w, r, route, limit) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// ThrottleGraphQL wraps the GraphQL handler with the rate limit of the given
+// route. GraphQL clients send their auth token as the authToken variable
+// instead of the Authorization header.
+func (t Throttler) ThrottleGraphQL(route string, limit ratelimit.Limit, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authToken := getBearerToken(r)
+		if authToken == "" && !limit.IsUnlimited() {
+			authToken = getGraphQLAuthToken(r)
+		}
+		if !t.allow(w, r, route, limit, authToken) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// identifyClient prefers API keys and user IDs over client IPs so that clients
+// sharing the same IP don't exhaust each other's limit. The auth token is only
+// decoded, without looking up its session, so that throttled requests never
+// reach the database.
+func (t Throttler) identifyClient(r *http.Request, authToken string) string {
+	apiKey := r.Header.Get(apiKeyHeader)
+	if apiKey != "" {
+		key, err := t.thirdPartyApp.IdentifyAPIKey(authenticator.Credential{APIKey: &apiKey})
+		if err == nil {
+			return fmt.Sprintf("api-key:%s:%s", key.AppID, key.Key)
+		}
+	}
+
+	if authToken != "" {
+		user, err := t.authenticator.IdentifyUser(authToken)
+		if err == nil {
+			return fmt.Sprintf("user:%s", user.ID)
+		}
+	}
+
+	return fmt.Sprintf("ip:%s", t.getClientIP(r))
+}
+
+func (t Throttler) getClientIP(r *http.Request) string {
+	connection := t.network.FromHTTP(r)
+	if connection.ClientIP != "" {
+		return connection.ClientIP
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// getBearerToken parses Authorization token with format "Bearer <token>"
+func getBearerToken(r *http.Request) string {
+	words := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(words) != 2 || words[0] != "Bearer" {
+		return ""
+	}
+	return words[1]
+}
+
+// getGraphQLAuthToken finds the authToken variable of a GraphQL request. The
+// request body is restored so that it can still be read by the GraphQL
+// handler.
+func getGraphQLAuthToken(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	buf, err := ioutil.ReadAll(io.LimitReader(r.Body, maxGraphQLAuthTokenScan))
+	r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(buf), r.Body))
+	if err != nil {
+		return ""
+	}
+
+	var params struct {
+		Variables struct {
+			AuthToken string `json:"authToken"`
+		} `json:"variables"`
+	}
+	if err := json.Unmarshal(buf, &params); err != nil {
+		return ""
+	}
+	return params.Variables.AuthToken
+}
+
+// NewThrottler creates Throttler.
+func NewThrottler(
+	limiter ratelimit.Limiter,
+	network network.Network,
+	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	metrics metrics.Metrics,
+	logger logger.Logger,
+	timer timer.Timer,
+) Throttler {
+	return Throttler{
+		limiter:       limiter,
+		network:       network,
+		authenticator: authenticator,
+		thirdPartyApp: thirdPartyApp,
+		metrics:       metrics,
+		logger:        logger,
+		timer:         timer,
+	}
+}
@@ -0,0 +1,281 @@
+// +build !integration all
+
+package request
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/metrics"
+	"github.com/short-d/app/fw/network"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestThrottler_Allow(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	tm := timer.NewStub(now)
+	tokenizer := crypto.NewTokenizerFake()
+	authn := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := authn.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	aliceToken := aliceTokens.AccessToken
+	bobTokens, err := authn.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobToken := bobTokens.AccessToken
+
+	testCases := []struct {
+		name             string
+		limit            ratelimit.Limit
+		headers          []map[string]string
+		expectedStatuses []int
+	}{
+		{
+			name:  "unlimited",
+			limit: ratelimit.Limit{},
+			headers: []map[string]string{
+				{"X-Forwarded-For": "10.0.0.1"},
+				{"X-Forwarded-For": "10.0.0.1"},
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:  "throttle by client IP",
+			limit: ratelimit.Limit{Requests: 1, Window: time.Minute},
+			headers: []map[string]string{
+				{"X-Forwarded-For": "10.0.0.1"},
+				{"X-Forwarded-For": "10.0.0.2"},
+				{"X-Forwarded-For": "10.0.0.1"},
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+		{
+			name:  "throttle by user ID",
+			limit: ratelimit.Limit{Requests: 1, Window: time.Minute},
+			headers: []map[string]string{
+				{"X-Forwarded-For": "10.0.0.1", "Authorization": "Bearer " + aliceToken},
+				{"X-Forwarded-For": "10.0.0.1", "Authorization": "Bearer " + bobToken},
+				{"X-Forwarded-For": "10.0.0.2", "Authorization": "Bearer " + aliceToken},
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+		{
+			name:  "throttle by API key",
+			limit: ratelimit.Limit{Requests: 1, Window: time.Minute},
+			headers: []map[string]string{
+				{"X-Forwarded-For": "10.0.0.1", "X-API-Key": `{"app_id":"alpha","key":"secret"}`},
+				{"X-Forwarded-For": "10.0.0.1", "X-API-Key": `{"app_id":"beta","key":"secret"}`},
+				{"X-Forwarded-For": "10.0.0.2", "X-API-Key": `{"app_id":"alpha","key":"secret"}`},
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+		{
+			name:  "fall back to client IP for invalid API key",
+			limit: ratelimit.Limit{Requests: 1, Window: time.Minute},
+			headers: []map[string]string{
+				{"X-Forwarded-For": "10.0.0.1", "X-API-Key": "invalid"},
+				{"X-Forwarded-For": "10.0.0.1", "X-API-Key": "also invalid"},
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+			auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{})
+			appRepo := repository.NewAppFake([]entity.App{})
+
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			thirdPartyApp := authenticator.NewThirdPartyApp(
+				auth, tokenizer, keyGen, tm, &apiKeyRepo, &appRepo, lg,
+			)
+
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(tm), tm)
+			throttler := NewThrottler(
+				limiter,
+				network.NewProxy(),
+				authn,
+				thirdPartyApp,
+				metrics.NewFake(),
+				lg,
+				tm,
+			)
+			handler := throttler.Throttle("route", testCase.limit, http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			))
+
+			for idx, headers := range testCase.headers {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				for name, value := range headers {
+					req.Header.Set(name, value)
+				}
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				expectedStatus := testCase.expectedStatuses[idx]
+				assert.Equal(t, expectedStatus, recorder.Code)
+				if expectedStatus == http.StatusTooManyRequests {
+					assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
+
+func TestThrottler_ThrottleGraphQL(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	tm := timer.NewStub(now)
+	authn := authenticator.NewAuthenticatorFake(now, time.Hour)
+	aliceTokens, err := authn.SignIn(entity.User{ID: "alice"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	bobTokens, err := authn.SignIn(entity.User{ID: "bob"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	newBody := func(authToken string) string {
+		body, err := json.Marshal(map[string]interface{}{
+			"query":     "query params($authToken: String!) { authQuery(authToken: $authToken) { user { id } } }",
+			"variables": map[string]string{"authToken": authToken},
+		})
+		assert.Equal(t, nil, err)
+		return string(body)
+	}
+
+	testCases := []struct {
+		name             string
+		clientIPs        []string
+		bodies           []string
+		expectedStatuses []int
+	}{
+		{
+			name:      "throttle by user ID in variables",
+			clientIPs: []string{"10.0.0.1", "10.0.0.1", "10.0.0.2"},
+			bodies: []string{
+				newBody(aliceTokens.AccessToken),
+				newBody(bobTokens.AccessToken),
+				newBody(aliceTokens.AccessToken),
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+		{
+			name:      "fall back to client IP without auth token",
+			clientIPs: []string{"10.0.0.1", "10.0.0.1"},
+			bodies: []string{
+				`{"query":"query { viewer { id } }"}`,
+				"not json",
+			},
+			expectedStatuses: []int{
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+			auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{})
+			appRepo := repository.NewAppFake([]entity.App{})
+
+			entryRepo := logger.NewEntryRepoFake()
+			lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+			assert.Equal(t, nil, err)
+
+			thirdPartyApp := authenticator.NewThirdPartyApp(
+				auth, crypto.NewTokenizerFake(), keyGen, tm, &apiKeyRepo, &appRepo, lg,
+			)
+
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(tm), tm)
+			throttler := NewThrottler(
+				limiter,
+				network.NewProxy(),
+				authn,
+				thirdPartyApp,
+				metrics.NewFake(),
+				lg,
+				tm,
+			)
+
+			limit := ratelimit.Limit{Requests: 1, Window: time.Minute}
+			for idx, body := range testCase.bodies {
+				var gotBody []byte
+				handler := throttler.ThrottleGraphQL("graphql", limit, http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						gotBody, err = ioutil.ReadAll(r.Body)
+						assert.Equal(t, nil, err)
+						w.WriteHeader(http.StatusOK)
+					},
+				))
+
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+				req.Header.Set("X-Forwarded-For", testCase.clientIPs[idx])
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				expectedStatus := testCase.expectedStatuses[idx]
+				assert.Equal(t, expectedStatus, recorder.Code)
+				if expectedStatus == http.StatusOK {
+					assert.Equal(t, body, string(gotBody))
+				}
+			}
+		})
+	}
+}
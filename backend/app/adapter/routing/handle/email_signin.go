@@ -0,0 +1,89 @@
+package handle
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/short-d/app/fw/router"
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+)
+
+// EmailSignInRequest represents the request received from email sign in API.
+type EmailSignInRequest struct {
+	Email string `json:"email"`
+}
+
+// EmailSignIn emails a one-time sign in link to the user.
+func EmailSignIn(emailSignIn emailsignin.EmailSignIn) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		var body EmailSignInRequest
+		err := json.NewDecoder(r.Body).Decode(&body)
+		defer r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = emailSignIn.RequestLink(body.Email)
+
+		var (
+			invalidEmail    emailsignin.ErrInvalidEmail
+			tooManyRequests emailsignin.ErrTooManyRequests
+		)
+		switch {
+		case err == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case errors.As(err, &invalidEmail):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.As(err, &tooManyRequests):
+			retryAfter := int(math.Ceil(tooManyRequests.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+// EmailSignInCallback signs the user in with the token in the sign in link
+// and sends the user back to Short's home page with the auth tokens.
+func EmailSignInCallback(
+	emailSignIn emailsignin.EmailSignIn,
+	client request.Client,
+	webFrontendURL url.URL,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		// Location lookup failure should not block sign in. The session is
+		// recorded with whatever device information is available.
+		device, _ := client.GetDevice(r)
+
+		authTokens, err := emailSignIn.SignIn(params["token"], device)
+
+		var (
+			errInvalidToken         emailsignin.ErrInvalidToken
+			errSecondFactorRequired twofactor.ErrSecondFactorRequired
+		)
+		switch {
+		case err == nil:
+			webFrontendURL = setTokens(w, webFrontendURL, authTokens)
+		case errors.As(err, &errInvalidToken):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.As(err, &errSecondFactorRequired):
+			webFrontendURL = setTwoFactorChallenge(webFrontendURL, errSecondFactorRequired)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, webFrontendURL.String(), http.StatusSeeOther)
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/router"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/adapter/request"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
 )
 
-// LongLink translates alias to the original long link.
+// LongLink translates alias to the original long link and records the visit.
+// Failing to record the visit doesn't affect the redirect.
 func LongLink(
 	instrumentationFactory request.InstrumentationFactory,
+	logger logger.Logger,
 	shortLinkRetriever shortlink.Retriever,
+	shortLinkAnalytics shortlink.Analytics,
 	timer timer.Timer,
 	webFrontendURL url.URL,
 ) router.Handle {
@@ -35,5 +39,10 @@ func LongLink(
 		longLink := s.LongLink
 		http.Redirect(w, r, longLink, http.StatusSeeOther)
 		i.RedirectedAliasToLongLink(s)
+
+		err = shortLinkAnalytics.RecordVisit(s.Alias)
+		if err != nil {
+			logger.Error(err)
+		}
 	}
 }
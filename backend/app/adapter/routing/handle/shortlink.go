@@ -0,0 +1,256 @@
+package handle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/router"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authenticator/scope"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// ShortLinkRequest represents the request body received from Short Link API.
+type ShortLinkRequest struct {
+	Alias    *string    `json:"alias,omitempty"`
+	LongLink *string    `json:"long_link,omitempty"`
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+}
+
+// ShortLinksResponse represents the response to the list short links request.
+type ShortLinksResponse struct {
+	ShortLinks []ShortLink `json:"short_links"`
+}
+
+// CreateShortLink creates a short link on behalf of the app owning the API
+// key.
+func CreateShortLink(
+	logger logger.Logger,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	creator shortlink.Creator,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		app, ok := authenticateApp(w, r, thirdPartyApp, scope.ShortLinkWrite)
+		if !ok {
+			return
+		}
+
+		var body ShortLinkRequest
+		err := json.NewDecoder(r.Body).Decode(&body)
+		defer r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		shortLink, err := creator.CreateAppShortLink(entity.ShortLinkInput{
+			CustomAlias: body.Alias,
+			LongLink:    body.LongLink,
+			ExpireAt:    body.ExpireAt,
+		}, app)
+		if err != nil {
+			serveShortLinkError(w, err)
+			return
+		}
+		writeJSON(w, logger, http.StatusCreated, newShortLink(shortLink))
+	}
+}
+
+// GetShortLink fetches a short link created by the app owning the API key.
+func GetShortLink(
+	logger logger.Logger,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	retriever shortlink.Retriever,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		app, ok := authenticateApp(w, r, thirdPartyApp, scope.ShortLinkRead)
+		if !ok {
+			return
+		}
+
+		shortLink, err := retriever.GetAppShortLink(params["alias"], app)
+		if err != nil {
+			serveShortLinkError(w, err)
+			return
+		}
+		writeJSON(w, logger, http.StatusOK, newShortLink(shortLink))
+	}
+}
+
+// ListShortLinks fetches all the short links created by the app owning the
+// API key.
+func ListShortLinks(
+	logger logger.Logger,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	retriever shortlink.Retriever,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		app, ok := authenticateApp(w, r, thirdPartyApp, scope.ShortLinkRead)
+		if !ok {
+			return
+		}
+
+		shortLinks, err := retriever.GetShortLinksByApp(app)
+		if err != nil {
+			serveShortLinkError(w, err)
+			return
+		}
+
+		response := ShortLinksResponse{ShortLinks: make([]ShortLink, len(shortLinks))}
+		for i, shortLink := range shortLinks {
+			response.ShortLinks[i] = newShortLink(shortLink)
+		}
+		writeJSON(w, logger, http.StatusOK, response)
+	}
+}
+
+// UpdateShortLink mutates a short link created by the app owning the API key.
+// Fields missing from the request body are left unchanged.
+func UpdateShortLink(
+	logger logger.Logger,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	updater shortlink.Updater,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		app, ok := authenticateApp(w, r, thirdPartyApp, scope.ShortLinkWrite)
+		if !ok {
+			return
+		}
+
+		var body ShortLinkRequest
+		err := json.NewDecoder(r.Body).Decode(&body)
+		defer r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		shortLink, err := updater.UpdateAppShortLink(params["alias"], entity.ShortLinkInput{
+			CustomAlias: body.Alias,
+			LongLink:    body.LongLink,
+			ExpireAt:    body.ExpireAt,
+		}, app)
+		if err != nil {
+			serveShortLinkError(w, err)
+			return
+		}
+		writeJSON(w, logger, http.StatusOK, newShortLink(shortLink))
+	}
+}
+
+// DeleteShortLink removes a short link created by the app owning the API key.
+func DeleteShortLink(
+	thirdPartyApp authenticator.ThirdPartyApp,
+	deleter shortlink.Deleter,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		app, ok := authenticateApp(w, r, thirdPartyApp, scope.ShortLinkWrite)
+		if !ok {
+			return
+		}
+
+		err := deleter.DeleteAppShortLink(params["alias"], app)
+		if err != nil {
+			serveShortLinkError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// authenticateApp identifies the app owning the API key and makes sure the key
+// is granted the required scope. It responds with an error and returns false
+// otherwise.
+func authenticateApp(
+	w http.ResponseWriter,
+	r *http.Request,
+	thirdPartyApp authenticator.ThirdPartyApp,
+	requiredScope scope.Scope,
+) (entity.App, bool) {
+	apiKey := r.Header.Get(apiKeyHeader)
+	if apiKey == "" {
+		http.Error(w, "api key not provided", http.StatusUnauthorized)
+		return entity.App{}, false
+	}
+
+	app, err := thirdPartyApp.GetApp(authenticator.Credential{APIKey: &apiKey}, requiredScope)
+	if err == nil {
+		return app, true
+	}
+
+	var missingScope authenticator.ErrMissingScope
+	if errors.As(err, &missingScope) {
+		http.Error(w, missingScope.Error(), http.StatusForbidden)
+		return entity.App{}, false
+	}
+	http.Error(w, "invalid api key", http.StatusUnauthorized)
+	return entity.App{}, false
+}
+
+// serveShortLinkError responds with the HTTP status code matching the error
+// returned by short link use cases.
+func serveShortLinkError(w http.ResponseWriter, err error) {
+	var (
+		aliasExist       shortlink.ErrAliasExist
+		invalidLongLink  shortlink.ErrInvalidLongLink
+		invalidAlias     shortlink.ErrInvalidCustomAlias
+		emptyAlias       shortlink.ErrEmptyAlias
+		maliciousLink    shortlink.ErrMaliciousLongLink
+		redirectLoop     shortlink.ErrRedirectLoop
+		tooManyRedirects shortlink.ErrTooManyRedirects
+		shortLinkMissing shortlink.ErrShortLinkNotFound
+		aliasNotFound    repository.ErrAliasNotFound
+		entryNotFound    repository.ErrEntryNotFound
+		quotaExceeded    quota.ErrAppQuotaExceeded
+	)
+
+	switch {
+	case errors.As(err, &aliasExist):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &invalidLongLink):
+		http.Error(w, fmt.Sprintf("invalid long link(%s): %s", invalidLongLink.LongLink, invalidLongLink.Violation), http.StatusBadRequest)
+	case errors.As(err, &invalidAlias):
+		http.Error(w, fmt.Sprintf("invalid custom alias(%s): %s", invalidAlias.Error(), invalidAlias.Violation), http.StatusBadRequest)
+	case errors.As(err, &emptyAlias):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.As(err, &maliciousLink):
+		http.Error(w, fmt.Sprintf("malicious long link(%s)", err.Error()), http.StatusBadRequest)
+	case errors.As(err, &redirectLoop):
+		http.Error(w, fmt.Sprintf("long link(%s) redirects back to the short link", err.Error()), http.StatusBadRequest)
+	case errors.As(err, &tooManyRedirects):
+		http.Error(w, fmt.Sprintf("long link(%s) goes through too many short links", err.Error()), http.StatusBadRequest)
+	case errors.As(err, &shortLinkMissing):
+		http.Error(w, fmt.Sprintf("short link(%s) not found", err.Error()), http.StatusNotFound)
+	case errors.As(err, &aliasNotFound), errors.As(err, &entryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.As(err, &quotaExceeded):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeJSON responds with the body encoded in JSON. Failures to write the
+// response are logged since the status code has already been sent.
+func writeJSON(w http.ResponseWriter, logger logger.Logger, statusCode int, body interface{}) {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(buf)
+	if err != nil {
+		logger.Error(fmt.Errorf("fail to write response: %w", err))
+	}
+}
@@ -1,44 +1,192 @@
 package handle
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
 
 	"github.com/short-d/app/fw/router"
+	"github.com/short-d/short/backend/app/adapter/request"
 	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
 )
 
-// SSOSignIn redirects user to the sign in page.
+// stateCookie keeps the nonce binding the OAuth state to the browser which
+// started signing in.
+const stateCookie = "oauth_state"
+
+// SSOSignIn redirects user to the sign in page. With a link ticket, the
+// account the user signs in with is linked to the signed in user instead.
 func SSOSignIn(
 	singleSignOn sso.SingleSignOn,
-	webFrontendURL string,
+	stateSigner sso.StateSigner,
+	accountManager sso.AccountManager,
+	webFrontendURL url.URL,
 ) router.Handle {
 	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
-		token := getToken(params)
-		if singleSignOn.IsSignedIn(token) {
-			http.Redirect(w, r, webFrontendURL, http.StatusSeeOther)
+		intent := sso.Intent{RedirectTo: params["redirect_to"]}
+		if sso.ValidateRedirect(intent.RedirectTo) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		linkTicket := params["link_ticket"]
+		if linkTicket != "" {
+			userID, err := accountManager.VerifyLinkTicket(linkTicket, singleSignOn.Name())
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			intent.LinkUserID = userID
+		} else if singleSignOn.IsSignedIn(getToken(params)) {
+			webFrontendURL = setRedirectTo(webFrontendURL, intent.RedirectTo)
+			http.Redirect(w, r, webFrontendURL.String(), http.StatusSeeOther)
+			return
+		}
+
+		state, nonce, err := stateSigner.Issue(intent)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		signInLink := singleSignOn.GetSignInLink(state)
+		if signInLink == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
 			return
 		}
-		signInLink := singleSignOn.GetSignInLink()
+		http.SetCookie(w, &http.Cookie{
+			Name:     stateCookie,
+			Value:    nonce,
+			Path:     "/oauth",
+			MaxAge:   int(sso.StateLifetime.Seconds()),
+			Secure:   isHTTPS(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
 		http.Redirect(w, r, signInLink, http.StatusSeeOther)
 	}
 }
 
-// SSOSignInCallback generates Short's authentication token given identity provider's authorization code.
+// SSOSignInCallback generates Short's authentication tokens given identity provider's authorization code.
 func SSOSignInCallback(
 	singleSignOn sso.SingleSignOn,
+	stateSigner sso.StateSigner,
+	accountManager sso.AccountManager,
+	client request.Client,
 	webFrontendURL url.URL,
 ) router.Handle {
 	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
 		code := params["code"]
+		state := params["state"]
 
-		authToken, err := singleSignOn.SignIn(code)
+		cookie, err := r.Cookie(stateCookie)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		// The state can only be used once.
+		http.SetCookie(w, &http.Cookie{
+			Name:     stateCookie,
+			Path:     "/oauth",
+			MaxAge:   -1,
+			Secure:   isHTTPS(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		intent, err := stateSigner.Verify(state, cookie.Value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if intent.LinkUserID != "" {
+			linkAccount(w, r, singleSignOn, accountManager, webFrontendURL, code, state, intent)
+			return
+		}
+
+		// Location lookup failure should not block sign in. The session is
+		// recorded with whatever device information is available.
+		device, _ := client.GetDevice(r)
 
-		webFrontendURL = setToken(webFrontendURL, authToken)
+		authTokens, err := singleSignOn.SignIn(code, state, device)
+
+		var errSecondFactorRequired twofactor.ErrSecondFactorRequired
+		switch {
+		case err == nil:
+			webFrontendURL = setTokens(w, webFrontendURL, authTokens)
+		case errors.As(err, &errSecondFactorRequired):
+			webFrontendURL = setTwoFactorChallenge(webFrontendURL, errSecondFactorRequired)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		webFrontendURL = setRedirectTo(webFrontendURL, intent.RedirectTo)
 		http.Redirect(w, r, webFrontendURL.String(), http.StatusSeeOther)
 	}
 }
+
+// linkAccount links the external account to the user who started linking.
+// When the external account belongs to another user, the user is sent back
+// with a merge ticket so that the two users can be merged after confirmation,
+// unless the other user is protected by a second factor or privileged roles.
+func linkAccount(
+	w http.ResponseWriter,
+	r *http.Request,
+	singleSignOn sso.SingleSignOn,
+	accountManager sso.AccountManager,
+	webFrontendURL url.URL,
+	code string,
+	state string,
+	intent sso.Intent,
+) {
+	err := singleSignOn.LinkAccount(code, state, intent.LinkUserID)
+
+	var (
+		errLinkedToOtherUser sso.ErrAccountLinkedToOtherUser
+		errProviderLinked    sso.ErrProviderAlreadyLinked
+	)
+	switch {
+	case err == nil:
+	case errors.As(err, &errLinkedToOtherUser):
+		mergeTicket, err := accountManager.IssueMergeTicket(intent.LinkUserID, errLinkedToOtherUser.UserID)
+		var errProtectedUser sso.ErrProtectedUser
+		if errors.As(err, &errProtectedUser) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		query := webFrontendURL.Query()
+		query.Set("merge_ticket", mergeTicket)
+		webFrontendURL.RawQuery = query.Encode()
+	case errors.As(err, &errProviderLinked):
+		w.WriteHeader(http.StatusConflict)
+		return
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webFrontendURL = setRedirectTo(webFrontendURL, intent.RedirectTo)
+	http.Redirect(w, r, webFrontendURL.String(), http.StatusSeeOther)
+}
+
+// setRedirectTo asks the web frontend to navigate to the given path once it
+// receives the auth tokens.
+func setRedirectTo(url url.URL, redirectTo string) url.URL {
+	if redirectTo == "" {
+		return url
+	}
+	query := url.Query()
+	query.Set("redirect_to", redirectTo)
+	url.RawQuery = query.Encode()
+	return url
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
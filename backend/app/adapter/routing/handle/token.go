@@ -1,18 +1,115 @@
 package handle
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/router"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
 )
 
+// refreshTokenCookie keeps the refresh token out of the reach of scripts and
+// out of URLs, which end up in browser history and server logs.
+const refreshTokenCookie = "refresh_token"
+
+// refreshTokenPath limits the refresh token cookie to the token endpoints.
+const refreshTokenPath = "/auth/token"
+
+// AccessTokenResponse represents the response to the refresh token request.
+type AccessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+}
+
+// RefreshToken exchanges the refresh token in the cookie for a new pair of
+// tokens. The new refresh token replaces the old one in the cookie.
+func RefreshToken(
+	logger logger.Logger,
+	auth authenticator.Authenticator,
+	webFrontendURL url.URL,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		allowWebFrontend(w, webFrontendURL)
+
+		cookie, err := r.Cookie(refreshTokenCookie)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		authTokens, err := auth.RefreshToken(cookie.Value)
+		var errInvalidRefreshToken authenticator.ErrInvalidRefreshToken
+		switch {
+		case err == nil:
+			setRefreshToken(w, authTokens)
+			writeJSON(w, logger, http.StatusOK, AccessTokenResponse{AccessToken: authTokens.AccessToken})
+		case errors.As(err, &errInvalidRefreshToken):
+			clearRefreshToken(w)
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
 func getToken(params map[string]string) string {
 	return params["token"]
 }
 
-func setToken(url url.URL, token string) url.URL {
+// setTokens passes the access token to the web frontend in the URL and keeps
+// the refresh token in a cookie.
+func setTokens(w http.ResponseWriter, url url.URL, authTokens authenticator.AuthTokens) url.URL {
+	setRefreshToken(w, authTokens)
+	query := url.Query()
+	query.Set("token", authTokens.AccessToken)
+	url.RawQuery = query.Encode()
+	return url
+}
+
+func setRefreshToken(w http.ResponseWriter, authTokens authenticator.AuthTokens) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    authTokens.RefreshToken,
+		Path:     refreshTokenPath,
+		Expires:  authTokens.RefreshTokenExpireAt,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearRefreshToken(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Path:     refreshTokenPath,
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// allowWebFrontend lets the web frontend send the refresh token cookie along
+// with its requests. Other origins can't read the response.
+func allowWebFrontend(w http.ResponseWriter, webFrontendURL url.URL) {
+	origin := fmt.Sprintf("%s://%s", webFrontendURL.Scheme, webFrontendURL.Host)
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Add("Vary", "Origin")
+}
+
+// setTwoFactorChallenge asks the web frontend to complete the sign in with a
+// second factor, enrolling one first if required.
+func setTwoFactorChallenge(url url.URL, errRequired twofactor.ErrSecondFactorRequired) url.URL {
 	query := url.Query()
-	query.Set("token", token)
+	query.Set("two_factor_challenge", errRequired.Challenge)
+	if errRequired.MustEnroll {
+		query.Set("two_factor_enroll", "true")
+	}
 	url.RawQuery = query.Encode()
 	return url
 }
@@ -3,15 +3,19 @@ package routing
 import (
 	"net/url"
 
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/router"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/adapter/facebook"
 	"github.com/short-d/short/backend/app/adapter/github"
 	"github.com/short-d/short/backend/app/adapter/google"
+	"github.com/short-d/short/backend/app/adapter/oidc"
 	"github.com/short-d/short/backend/app/adapter/request"
 	"github.com/short-d/short/backend/app/adapter/routing/handle"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
 	"github.com/short-d/short/backend/app/usecase/feature"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 	"github.com/short-d/short/backend/app/usecase/search"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
 	"github.com/short-d/short/backend/app/usecase/sso"
@@ -20,15 +24,28 @@ import (
 // NewShort creates HTTP routing table.
 func NewShort(
 	instrumentationFactory request.InstrumentationFactory,
+	logger logger.Logger,
+	client request.Client,
 	webFrontendURL string,
 	timer timer.Timer,
 	shortLinkRetriever shortlink.Retriever,
+	shortLinkCreator shortlink.Creator,
+	shortLinkUpdater shortlink.Updater,
+	shortLinkDeleter shortlink.Deleter,
+	shortLinkAnalytics shortlink.Analytics,
 	featureDecisionMakerFactory feature.DecisionMakerFactory,
 	githubSSO github.SingleSignOn,
 	facebookSSO facebook.SingleSignOn,
 	googleSSO google.SingleSignOn,
+	oidcSSO oidc.SingleSignOn,
+	stateSigner sso.StateSigner,
+	accountManager sso.AccountManager,
+	emailSignIn emailsignin.EmailSignIn,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
 	search search.Search,
+	throttler request.Throttler,
+	rateLimitPolicy ratelimit.Policy,
 	swaggerUIDir string,
 	openAPISpecPath string,
 ) []router.Route {
@@ -42,7 +59,9 @@ func NewShort(
 			Path:   "/oauth/github/sign-in",
 			Handle: handle.SSOSignIn(
 				sso.SingleSignOn(githubSSO),
-				webFrontendURL,
+				stateSigner,
+				accountManager,
+				*frontendURL,
 			),
 		},
 		{
@@ -50,6 +69,9 @@ func NewShort(
 			Path:   "/oauth/github/sign-in/callback",
 			Handle: handle.SSOSignInCallback(
 				sso.SingleSignOn(githubSSO),
+				stateSigner,
+				accountManager,
+				client,
 				*frontendURL,
 			),
 		},
@@ -58,7 +80,9 @@ func NewShort(
 			Path:   "/oauth/facebook/sign-in",
 			Handle: handle.SSOSignIn(
 				sso.SingleSignOn(facebookSSO),
-				webFrontendURL,
+				stateSigner,
+				accountManager,
+				*frontendURL,
 			),
 		},
 		{
@@ -66,6 +90,9 @@ func NewShort(
 			Path:   "/oauth/facebook/sign-in/callback",
 			Handle: handle.SSOSignInCallback(
 				sso.SingleSignOn(facebookSSO),
+				stateSigner,
+				accountManager,
+				client,
 				*frontendURL,
 			),
 		},
@@ -74,7 +101,9 @@ func NewShort(
 			Path:   "/oauth/google/sign-in",
 			Handle: handle.SSOSignIn(
 				sso.SingleSignOn(googleSSO),
-				webFrontendURL,
+				stateSigner,
+				accountManager,
+				*frontendURL,
 			),
 		},
 		{
@@ -82,19 +111,65 @@ func NewShort(
 			Path:   "/oauth/google/sign-in/callback",
 			Handle: handle.SSOSignInCallback(
 				sso.SingleSignOn(googleSSO),
+				stateSigner,
+				accountManager,
+				client,
 				*frontendURL,
 			),
 		},
 		{
 			Method: "GET",
-			Path:   "/r/:alias",
-			Handle: handle.LongLink(
-				instrumentationFactory,
-				shortLinkRetriever,
-				timer,
+			Path:   "/oauth/oidc/sign-in",
+			Handle: handle.SSOSignIn(
+				sso.SingleSignOn(oidcSSO),
+				stateSigner,
+				accountManager,
+				*frontendURL,
+			),
+		},
+		{
+			Method: "GET",
+			Path:   "/oauth/oidc/sign-in/callback",
+			Handle: handle.SSOSignInCallback(
+				sso.SingleSignOn(oidcSSO),
+				stateSigner,
+				accountManager,
+				client,
 				*frontendURL,
 			),
 		},
+		{
+			Method: "POST",
+			Path:   "/email/sign-in",
+			Handle: handle.EmailSignIn(emailSignIn),
+		},
+		{
+			Method: "GET",
+			Path:   "/email/sign-in/callback",
+			Handle: handle.EmailSignInCallback(emailSignIn, client, *frontendURL),
+		},
+		{
+			Method: "POST",
+			Path:   "/auth/token/refresh",
+			Handle: handle.RefreshToken(logger, authenticator, *frontendURL),
+		},
+		{
+			Method: "GET",
+			Path:   "/r/:alias",
+			Handle: throttle(
+				throttler,
+				"redirect",
+				rateLimitPolicy.Redirect,
+				handle.LongLink(
+					instrumentationFactory,
+					logger,
+					shortLinkRetriever,
+					shortLinkAnalytics,
+					timer,
+					*frontendURL,
+				),
+			),
+		},
 		{
 			Method: "GET",
 			Path:   "/features/:featureID",
@@ -112,10 +187,65 @@ func NewShort(
 		{
 			Method: "POST",
 			Path:   "/search",
-			Handle: handle.Search(
-				instrumentationFactory,
-				search,
-				authenticator,
+			Handle: throttle(
+				throttler,
+				"search",
+				rateLimitPolicy.Search,
+				handle.Search(
+					instrumentationFactory,
+					search,
+					authenticator,
+				),
+			),
+		},
+		{
+			Method: "POST",
+			Path:   "/v1/short-links",
+			Handle: throttle(
+				throttler,
+				"cloud-api",
+				rateLimitPolicy.CloudAPI,
+				handle.CreateShortLink(logger, thirdPartyApp, shortLinkCreator),
+			),
+		},
+		{
+			Method: "GET",
+			Path:   "/v1/short-links",
+			Handle: throttle(
+				throttler,
+				"cloud-api",
+				rateLimitPolicy.CloudAPI,
+				handle.ListShortLinks(logger, thirdPartyApp, shortLinkRetriever),
+			),
+		},
+		{
+			Method: "GET",
+			Path:   "/v1/short-links/:alias",
+			Handle: throttle(
+				throttler,
+				"cloud-api",
+				rateLimitPolicy.CloudAPI,
+				handle.GetShortLink(logger, thirdPartyApp, shortLinkRetriever),
+			),
+		},
+		{
+			Method: "PUT",
+			Path:   "/v1/short-links/:alias",
+			Handle: throttle(
+				throttler,
+				"cloud-api",
+				rateLimitPolicy.CloudAPI,
+				handle.UpdateShortLink(logger, thirdPartyApp, shortLinkUpdater),
+			),
+		},
+		{
+			Method: "DELETE",
+			Path:   "/v1/short-links/:alias",
+			Handle: throttle(
+				throttler,
+				"cloud-api",
+				rateLimitPolicy.CloudAPI,
+				handle.DeleteShortLink(thirdPartyApp, shortLinkDeleter),
 			),
 		},
 		{
@@ -0,0 +1,23 @@
+package routing
+
+import (
+	"net/http"
+
+	"github.com/short-d/app/fw/router"
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+)
+
+func throttle(
+	throttler request.Throttler,
+	route string,
+	limit ratelimit.Limit,
+	handle router.Handle,
+) router.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params router.Params) {
+		if !throttler.Allow(w, r, route, limit) {
+			return
+		}
+		handle(w, r, params)
+	}
+}
@@ -0,0 +1,249 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+var _ shortlink.MetaTagScraper = (*MetaTag)(nil)
+
+const (
+	userAgent = "Short-MetaTagScraper/1.0"
+
+	// maxTagLength matches the size of the meta tag columns in short_link
+	// table.
+	maxTagLength = 200
+
+	defaultTimeout     = 5 * time.Second
+	defaultMaxPageSize = 512 * 1024
+)
+
+// Limit bounds the resources spent on scraping a single page.
+type Limit struct {
+	Timeout time.Duration
+	// MaxPageSize is the maximum number of bytes read from a page. Meta tags
+	// appearing after it are ignored.
+	MaxPageSize int64
+}
+
+// ErrUnexpectedStatus represents a page responded with an error status code.
+type ErrUnexpectedStatus int
+
+func (e ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected status code %d", int(e))
+}
+
+// MetaTag scrapes Open Graph and Twitter meta tags from HTML pages, falling
+// back to the title and description of the page for missing tags.
+type MetaTag struct {
+	client      http.Client
+	maxPageSize int64
+}
+
+type pageTags struct {
+	title      string
+	properties map[string]string
+}
+
+// Scrape fetches the page of the long link and extracts its meta tags.
+func (m MetaTag) Scrape(longLink string) (metatag.OpenGraph, metatag.Twitter, error) {
+	req, err := http.NewRequest(http.MethodGet, longLink, nil)
+	if err != nil {
+		return metatag.OpenGraph{}, metatag.Twitter{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return metatag.OpenGraph{}, metatag.Twitter{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return metatag.OpenGraph{}, metatag.Twitter{}, ErrUnexpectedStatus(res.StatusCode)
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if !isHTML(contentType) {
+		return metatag.OpenGraph{}, metatag.Twitter{}, nil
+	}
+
+	body, err := charset.NewReader(io.LimitReader(res.Body, m.maxPageSize), contentType)
+	if err != nil {
+		return metatag.OpenGraph{}, metatag.Twitter{}, err
+	}
+
+	tags := parseHead(body)
+	openGraphTags, twitterTags := tags.toMetaTags(res.Request.URL)
+	return openGraphTags, twitterTags, nil
+}
+
+func isHTML(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// parseHead collects the title and meta tags until the body of the page
+// starts.
+func parseHead(body io.Reader) pageTags {
+	tags := pageTags{properties: make(map[string]string)}
+	tokenizer := html.NewTokenizer(body)
+	isInTitle := false
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return tags
+		case html.TextToken:
+			if isInTitle && tags.title == "" {
+				tags.title = strings.TrimSpace(string(tokenizer.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				isInTitle = false
+			case "head":
+				return tags
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				isInTitle = true
+			case "body":
+				return tags
+			case "meta":
+				if hasAttr {
+					tags.addMeta(tokenizer)
+				}
+			}
+		}
+	}
+}
+
+func (p pageTags) addMeta(tokenizer *html.Tokenizer) {
+	var key, content string
+	for {
+		name, val, hasMore := tokenizer.TagAttr()
+		switch string(name) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(string(val)))
+			}
+		case "content":
+			content = strings.TrimSpace(string(val))
+		}
+		if !hasMore {
+			break
+		}
+	}
+
+	if key == "" || content == "" {
+		return
+	}
+	if _, ok := p.properties[key]; ok {
+		return
+	}
+	p.properties[key] = content
+}
+
+func (p pageTags) toMetaTags(pageURL *url.URL) (metatag.OpenGraph, metatag.Twitter) {
+	description := p.properties["description"]
+
+	openGraphTags := metatag.OpenGraph{
+		Title:       p.text("og:title", "twitter:title", p.title),
+		Description: p.text("og:description", "twitter:description", description),
+		ImageURL:    p.image(pageURL, "og:image", "twitter:image"),
+	}
+	twitterTags := metatag.Twitter{
+		Title:       p.text("twitter:title", "og:title", p.title),
+		Description: p.text("twitter:description", "og:description", description),
+		ImageURL:    p.image(pageURL, "twitter:image", "og:image"),
+	}
+	return openGraphTags, twitterTags
+}
+
+func (p pageTags) text(key string, fallbackKey string, fallback string) *string {
+	value := p.properties[key]
+	if value == "" {
+		value = p.properties[fallbackKey]
+	}
+	if value == "" {
+		value = fallback
+	}
+	if value == "" {
+		return nil
+	}
+
+	value = truncate(value, maxTagLength)
+	return &value
+}
+
+func (p pageTags) image(pageURL *url.URL, key string, fallbackKey string) *string {
+	value := p.properties[key]
+	if value == "" {
+		value = p.properties[fallbackKey]
+	}
+	if value == "" {
+		return nil
+	}
+
+	imageURL, err := pageURL.Parse(value)
+	if err != nil {
+		return nil
+	}
+	if imageURL.Scheme != "http" && imageURL.Scheme != "https" {
+		return nil
+	}
+
+	absoluteURL := imageURL.String()
+	if utf8.RuneCountInString(absoluteURL) > maxTagLength {
+		return nil
+	}
+	return &absoluteURL
+}
+
+func truncate(text string, maxLength int) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxLength])
+}
+
+// NewMetaTag creates MetaTag scraper bounded by the given limit. The client
+// should be guarded with netguard.Guard since long links are supplied by
+// users.
+func NewMetaTag(client http.Client, limit Limit) MetaTag {
+	if limit.Timeout <= 0 {
+		limit.Timeout = defaultTimeout
+	}
+	if limit.MaxPageSize <= 0 {
+		limit.MaxPageSize = defaultMaxPageSize
+	}
+
+	client.Timeout = limit.Timeout
+	return MetaTag{
+		client:      client,
+		maxPageSize: limit.MaxPageSize,
+	}
+}
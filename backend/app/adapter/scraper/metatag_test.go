@@ -0,0 +1,202 @@
+// +build !integration all
+
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/fw/ptr"
+)
+
+func TestMetaTag_Scrape(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"/full": `<!DOCTYPE html>
+<html>
+<head>
+  <title>Page Title</title>
+  <meta property="og:title" content="OG Title">
+  <meta property="og:description" content="OG &amp; Description">
+  <meta property="og:image" content="/images/og.png">
+  <meta name="twitter:title" content="Twitter Title">
+  <meta name="twitter:description" content="Twitter Description">
+  <meta name="twitter:image" content="https://cdn.com/twitter.png">
+</head>
+<body><meta property="og:title" content="Ignored"></body>
+</html>`,
+		"/fallback": `<html><head>
+  <title> Only Title </title>
+  <meta name="description" content="Only Description">
+</head></html>`,
+		"/og-only": `<html><head>
+  <meta property="og:title" content="Shared Title">
+  <meta property="og:image" content="javascript:alert(1)">
+</head></html>`,
+		"/long": `<html><head><title>` + strings.Repeat("a", 300) + `</title></head></html>`,
+		"/late": `<html><head>` + strings.Repeat("<!-- padding -->", 100) +
+			`<title>Too Late</title></head></html>`,
+	}
+
+	mux := http.NewServeMux()
+	for path, page := range pages {
+		page := page
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(page))
+		})
+	}
+	mux.HandleFunc("/latin1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte("<html><head><title>Caf\xe9</title></head></html>"))
+	})
+	mux.HandleFunc("/pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.4"))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/full", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name                  string
+		path                  string
+		hasErr                bool
+		expectedOpenGraphTags metatag.OpenGraph
+		expectedTwitterTags   metatag.Twitter
+	}{
+		{
+			name:   "page with all meta tags",
+			path:   "/full",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title:       ptr.String("OG Title"),
+				Description: ptr.String("OG & Description"),
+				ImageURL:    ptr.String(server.URL + "/images/og.png"),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title:       ptr.String("Twitter Title"),
+				Description: ptr.String("Twitter Description"),
+				ImageURL:    ptr.String("https://cdn.com/twitter.png"),
+			},
+		},
+		{
+			name:   "fall back to title and description",
+			path:   "/fallback",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title:       ptr.String("Only Title"),
+				Description: ptr.String("Only Description"),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title:       ptr.String("Only Title"),
+				Description: ptr.String("Only Description"),
+			},
+		},
+		{
+			name:   "share tags between Open Graph and Twitter",
+			path:   "/og-only",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title: ptr.String("Shared Title"),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title: ptr.String("Shared Title"),
+			},
+		},
+		{
+			name:   "truncate long tags",
+			path:   "/long",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title: ptr.String(strings.Repeat("a", 200)),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title: ptr.String(strings.Repeat("a", 200)),
+			},
+		},
+		{
+			name:                  "ignore tags beyond max page size",
+			path:                  "/late",
+			hasErr:                false,
+			expectedOpenGraphTags: metatag.OpenGraph{},
+			expectedTwitterTags:   metatag.Twitter{},
+		},
+		{
+			name:   "decode page charset",
+			path:   "/latin1",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title: ptr.String("Café"),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title: ptr.String("Café"),
+			},
+		},
+		{
+			name:                  "skip non HTML page",
+			path:                  "/pdf",
+			hasErr:                false,
+			expectedOpenGraphTags: metatag.OpenGraph{},
+			expectedTwitterTags:   metatag.Twitter{},
+		},
+		{
+			name:   "follow redirects",
+			path:   "/redirect",
+			hasErr: false,
+			expectedOpenGraphTags: metatag.OpenGraph{
+				Title:       ptr.String("OG Title"),
+				Description: ptr.String("OG & Description"),
+				ImageURL:    ptr.String(server.URL + "/images/og.png"),
+			},
+			expectedTwitterTags: metatag.Twitter{
+				Title:       ptr.String("Twitter Title"),
+				Description: ptr.String("Twitter Description"),
+				ImageURL:    ptr.String("https://cdn.com/twitter.png"),
+			},
+		},
+		{
+			name:   "page not found",
+			path:   "/missing",
+			hasErr: true,
+		},
+		{
+			name:   "page times out",
+			path:   "/slow",
+			hasErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			scraper := NewMetaTag(webreq.NewHTTPClient(), Limit{
+				Timeout:     50 * time.Millisecond,
+				MaxPageSize: 1024,
+			})
+			openGraphTags, twitterTags, err := scraper.Scrape(server.URL + testCase.path)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedOpenGraphTags, openGraphTags)
+			assert.Equal(t, testCase.expectedTwitterTags, twitterTags)
+		})
+	}
+}
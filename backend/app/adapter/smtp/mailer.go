@@ -0,0 +1,87 @@
+package smtp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
+)
+
+// Config contains the settings of the SMTP server which delivers emails.
+type Config struct {
+	Host string
+	Port int
+	// Username and Password are only sent when Username is not empty.
+	Username string
+	Password string
+	// From is the address emails are sent from, such as
+	// no-reply@short-d.com.
+	From string
+}
+
+type sendMailFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+var _ emailsignin.Mailer = (*Mailer)(nil)
+
+// Mailer delivers emails through an SMTP server.
+type Mailer struct {
+	config   Config
+	timer    timer.Timer
+	sendMail sendMailFunc
+}
+
+// SendEmail delivers a plain text email to its recipient.
+func (m Mailer) SendEmail(email emailsignin.Email) error {
+	if m.config.Host == "" {
+		return errors.New("SMTP server is not configured")
+	}
+
+	msg, err := m.buildMessage(email)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	return m.sendMail(addr, auth, m.config.From, []string{email.To}, msg)
+}
+
+func (m Mailer) buildMessage(email emailsignin.Email) ([]byte, error) {
+	for _, header := range []string{m.config.From, email.To, email.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errors.New("email header contains line breaks")
+		}
+	}
+
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", email.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", m.timer.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	body := strings.ReplaceAll(email.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+// NewMailer creates Mailer.
+func NewMailer(config Config, timer timer.Timer) Mailer {
+	return Mailer{
+		config:   config,
+		timer:    timer,
+		sendMail: smtp.SendMail,
+	}
+}
@@ -0,0 +1,150 @@
+// +build !integration all
+
+package smtp
+
+import (
+	"net/smtp"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
+)
+
+type sentMail struct {
+	addr  string
+	auth  smtp.Auth
+	from  string
+	to    []string
+	msg   string
+	isSet bool
+}
+
+func TestMailer_SendEmail(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+
+	testCases := []struct {
+		name            string
+		config          Config
+		email           emailsignin.Email
+		hasErr          bool
+		expectedAddr    string
+		expectedHasAuth bool
+		expectedTo      []string
+		expectedMsg     string
+	}{
+		{
+			name:   "server not configured",
+			config: Config{},
+			email: emailsignin.Email{
+				To:      "alpha@example.com",
+				Subject: "Sign in to Short",
+				Body:    "Hello",
+			},
+			hasErr: true,
+		},
+		{
+			name: "header injection",
+			config: Config{
+				Host: "smtp.example.com",
+				Port: 587,
+				From: "no-reply@short-d.com",
+			},
+			email: emailsignin.Email{
+				To:      "alpha@example.com\r\nBcc: beta@example.com",
+				Subject: "Sign in to Short",
+				Body:    "Hello",
+			},
+			hasErr: true,
+		},
+		{
+			name: "without authentication",
+			config: Config{
+				Host: "localhost",
+				Port: 25,
+				From: "no-reply@short-d.com",
+			},
+			email: emailsignin.Email{
+				To:      "alpha@example.com",
+				Subject: "Sign in to Short",
+				Body:    "Hello\nWorld\n",
+			},
+			hasErr:          false,
+			expectedAddr:    "localhost:25",
+			expectedHasAuth: false,
+			expectedTo:      []string{"alpha@example.com"},
+			expectedMsg: "From: no-reply@short-d.com\r\n" +
+				"To: alpha@example.com\r\n" +
+				"Subject: Sign in to Short\r\n" +
+				"Date: Mon, 20 Jul 2020 10:00:00 +0000\r\n" +
+				"MIME-Version: 1.0\r\n" +
+				"Content-Type: text/plain; charset=utf-8\r\n" +
+				"\r\n" +
+				"Hello\r\nWorld\r\n",
+		},
+		{
+			name: "with authentication",
+			config: Config{
+				Host:     "smtp.example.com",
+				Port:     587,
+				Username: "short",
+				Password: "secret",
+				From:     "no-reply@short-d.com",
+			},
+			email: emailsignin.Email{
+				To:      "alpha@example.com",
+				Subject: "Sign in to Short",
+				Body:    "Hello",
+			},
+			hasErr:          false,
+			expectedAddr:    "smtp.example.com:587",
+			expectedHasAuth: true,
+			expectedTo:      []string{"alpha@example.com"},
+			expectedMsg: "From: no-reply@short-d.com\r\n" +
+				"To: alpha@example.com\r\n" +
+				"Subject: Sign in to Short\r\n" +
+				"Date: Mon, 20 Jul 2020 10:00:00 +0000\r\n" +
+				"MIME-Version: 1.0\r\n" +
+				"Content-Type: text/plain; charset=utf-8\r\n" +
+				"\r\n" +
+				"Hello",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sent := sentMail{}
+			mailer := NewMailer(testCase.config, timer.NewStub(now))
+			mailer.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+				sent = sentMail{
+					addr:  addr,
+					auth:  auth,
+					from:  from,
+					to:    to,
+					msg:   string(msg),
+					isSet: true,
+				}
+				return nil
+			}
+
+			err := mailer.SendEmail(testCase.email)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				assert.Equal(t, false, sent.isSet)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedAddr, sent.addr)
+			assert.Equal(t, testCase.expectedHasAuth, sent.auth != nil)
+			assert.Equal(t, testCase.config.From, sent.from)
+			assert.Equal(t, testCase.expectedTo, sent.to)
+			assert.Equal(t, testCase.expectedMsg, sent.msg)
+		})
+	}
+}
@@ -21,58 +21,268 @@ type APIKeySQL struct {
 // GetAPIKey fetches APIKey from APIKey table using SQL.
 func (a APIKeySQL) GetAPIKey(appID string, key string) (entity.APIKey, error) {
 	query := fmt.Sprintf(`
-SELECT "%s", "%s" 
+SELECT "%s", "%s", "%s", "%s" 
 FROM "%s" WHERE "%s"=$1 AND "%s"=$2;
 `,
 		table.APIKey.ColumnDisabled,
 		table.APIKey.ColumnCreatedAt,
+		table.APIKey.ColumnExpireAt,
+		table.APIKey.ColumnLastUsedAt,
 		table.APIKey.TableName,
 		table.APIKey.ColumnAppID,
 		table.APIKey.ColumnKey,
 	)
 	apiKey := entity.APIKey{}
-	err := a.db.QueryRow(query, appID, key).Scan(&apiKey.IsDisabled, &apiKey.CreatedAt)
-	if err == nil {
-		apiKey.AppID = appID
-		apiKey.Key = key
-		return apiKey, nil
-	}
+	err := a.db.QueryRow(query, appID, key).Scan(
+		&apiKey.IsDisabled,
+		&apiKey.CreatedAt,
+		&apiKey.ExpireAt,
+		&apiKey.LastUsedAt,
+	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return entity.APIKey{},
 			repository.ErrEntryNotFound(
-				fmt.Sprintf("appID(%s) and key(%s) not found", appID, key))
+				fmt.Sprintf("api key of app(%s) not found", appID))
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	scopes, err := a.getScopes(appID, key)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	apiKey.AppID = appID
+	apiKey.Key = key
+	apiKey.ExpireAt = utc(apiKey.ExpireAt)
+	apiKey.LastUsedAt = utc(apiKey.LastUsedAt)
+	apiKey.Scopes = scopesOrEmpty(scopes)
+	return apiKey, nil
+}
+
+// GetAPIKeysByApp fetches all the APIKeys of the given app from APIKey table
+// using SQL.
+func (a APIKeySQL) GetAPIKeysByApp(appID string) ([]entity.APIKey, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s", "%s", "%s", "%s"
+FROM "%s" WHERE "%s"=$1
+ORDER BY "%s";
+`,
+		table.APIKey.ColumnKey,
+		table.APIKey.ColumnDisabled,
+		table.APIKey.ColumnCreatedAt,
+		table.APIKey.ColumnExpireAt,
+		table.APIKey.ColumnLastUsedAt,
+		table.APIKey.TableName,
+		table.APIKey.ColumnAppID,
+		table.APIKey.ColumnCreatedAt,
+	)
+	rows, err := a.db.Query(query, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apiKeys []entity.APIKey
+	for rows.Next() {
+		apiKey := entity.APIKey{AppID: appID}
+		err = rows.Scan(
+			&apiKey.Key,
+			&apiKey.IsDisabled,
+			&apiKey.CreatedAt,
+			&apiKey.ExpireAt,
+			&apiKey.LastUsedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		apiKey.ExpireAt = utc(apiKey.ExpireAt)
+		apiKey.LastUsedAt = utc(apiKey.LastUsedAt)
+		apiKeys = append(apiKeys, apiKey)
 	}
-	return entity.APIKey{}, err
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := a.getAppScopes(appID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range apiKeys {
+		apiKeys[i].Scopes = scopesOrEmpty(scopes[apiKeys[i].Key])
+	}
+	return apiKeys, nil
 }
 
-// CreateAPIKey appends a new APIKey entry to APIKey table using SQL.
+// CreateAPIKey appends a new APIKey entry to APIKey table together with its
+// scopes using SQL.
 func (a APIKeySQL) CreateAPIKey(input entity.APIKeyInput) (entity.APIKey, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
 	stmt := fmt.Sprintf(`
-INSERT INTO "%s"("%s", "%s", "%s", "%s")
-VALUES ($1, $2, $3, $4);
+INSERT INTO "%s"("%s", "%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4, $5);
 `,
 		table.APIKey.TableName,
 		table.APIKey.ColumnAppID,
 		table.APIKey.ColumnKey,
 		table.APIKey.ColumnDisabled,
 		table.APIKey.ColumnCreatedAt,
+		table.APIKey.ColumnExpireAt,
 	)
 
 	isDisabled := input.GetIsDisabled(false)
-	_, err := a.db.Exec(
+	_, err = tx.Exec(
 		stmt,
 		input.GetAppID(""),
 		input.GetKey(""),
 		SQLBool(isDisabled),
 		input.GetCreatedAt(time.Time{}),
+		input.ExpireAt,
 	)
+	if err != nil {
+		tx.Rollback()
+		return entity.APIKey{}, err
+	}
+
+	stmt = fmt.Sprintf(`
+INSERT INTO "%s"("%s", "%s", "%s")
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+`,
+		table.APIKeyScope.TableName,
+		table.APIKeyScope.ColumnAppID,
+		table.APIKeyScope.ColumnKey,
+		table.APIKeyScope.ColumnScope,
+	)
+	for _, scope := range input.Scopes {
+		_, err = tx.Exec(stmt, input.GetAppID(""), input.GetKey(""), scope)
+		if err != nil {
+			tx.Rollback()
+			return entity.APIKey{}, err
+		}
+	}
 
+	err = tx.Commit()
+	if err != nil {
+		return entity.APIKey{}, err
+	}
 	return entity.APIKey{
 		AppID:      input.GetAppID(""),
 		Key:        input.GetKey(""),
 		IsDisabled: isDisabled,
 		CreatedAt:  input.GetCreatedAt(time.Time{}),
-	}, err
+		ExpireAt:   input.ExpireAt,
+		Scopes:     scopesOrEmpty(input.Scopes),
+	}, nil
+}
+
+// DisableAPIKey marks an APIKey as disabled in APIKey table using SQL.
+func (a APIKeySQL) DisableAPIKey(appID string, key string) error {
+	return a.updateColumn(appID, key, table.APIKey.ColumnDisabled, SQLBool(true))
+}
+
+// UpdateExpireAt changes the expiration time of an APIKey in APIKey table
+// using SQL.
+func (a APIKeySQL) UpdateExpireAt(appID string, key string, expireAt time.Time) error {
+	return a.updateColumn(appID, key, table.APIKey.ColumnExpireAt, expireAt)
+}
+
+// UpdateLastUsedAt records when an APIKey was last used in APIKey table using
+// SQL.
+func (a APIKeySQL) UpdateLastUsedAt(appID string, key string, lastUsedAt time.Time) error {
+	return a.updateColumn(appID, key, table.APIKey.ColumnLastUsedAt, lastUsedAt)
+}
+
+func (a APIKeySQL) updateColumn(appID string, key string, column string, value interface{}) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s"=$3;
+`,
+		table.APIKey.TableName,
+		column,
+		table.APIKey.ColumnAppID,
+		table.APIKey.ColumnKey,
+	)
+
+	result, err := a.db.Exec(stmt, value, appID, key)
+	if err != nil {
+		return err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRowCount == 0 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("api key of app(%s) not found", appID))
+	}
+	return nil
+}
+
+// getScopes fetches the scopes of the given API key.
+func (a APIKeySQL) getScopes(appID string, key string) ([]string, error) {
+	condition := fmt.Sprintf(
+		`"%s"=$1 AND "%s"=$2`,
+		table.APIKeyScope.ColumnAppID,
+		table.APIKeyScope.ColumnKey,
+	)
+	scopes, err := a.queryScopes(condition, appID, key)
+	if err != nil {
+		return nil, err
+	}
+	return scopes[key], nil
+}
+
+// getAppScopes fetches the scopes of all the API keys of the given app,
+// grouped by key.
+func (a APIKeySQL) getAppScopes(appID string) (map[string][]string, error) {
+	condition := fmt.Sprintf(`"%s"=$1`, table.APIKeyScope.ColumnAppID)
+	return a.queryScopes(condition, appID)
+}
+
+func (a APIKeySQL) queryScopes(condition string, args ...interface{}) (map[string][]string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s"
+FROM "%s" WHERE %s
+ORDER BY "%s";
+`,
+		table.APIKeyScope.ColumnKey,
+		table.APIKeyScope.ColumnScope,
+		table.APIKeyScope.TableName,
+		condition,
+		table.APIKeyScope.ColumnScope,
+	)
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scopes := make(map[string][]string)
+	for rows.Next() {
+		var key, scope string
+		err = rows.Scan(&key, &scope)
+		if err != nil {
+			return nil, err
+		}
+		scopes[key] = append(scopes[key], scope)
+	}
+	return scopes, rows.Err()
+}
+
+func scopesOrEmpty(scopes []string) []string {
+	if scopes == nil {
+		return []string{}
+	}
+	return scopes
 }
 
 // NewAPIKeySQL creates database access object for APIKey.
@@ -13,6 +13,7 @@ import (
 	"github.com/short-d/short/backend/app/adapter/sqldb"
 	"github.com/short-d/short/backend/app/adapter/sqldb/table"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
 )
 
 var insertAPIKeyRowSQL = fmt.Sprintf(`
@@ -25,11 +26,21 @@ VALUES ($1, $2, $3, $4);`,
 	table.APIKey.ColumnCreatedAt,
 )
 
+var insertAPIKeyScopeRowSQL = fmt.Sprintf(`
+INSERT INTO %s (%s, %s, %s)
+VALUES ($1, $2, $3);`,
+	table.APIKeyScope.TableName,
+	table.APIKeyScope.ColumnAppID,
+	table.APIKeyScope.ColumnKey,
+	table.APIKeyScope.ColumnScope,
+)
+
 type apiKeyTableRow struct {
 	appID      string
 	key        string
 	isDisabled bool
 	createdAt  time.Time
+	scopes     []string
 }
 
 func TestAPIKeySQL_GetAPIKey(t *testing.T) {
@@ -78,6 +89,7 @@ func TestAPIKeySQL_GetAPIKey(t *testing.T) {
 					appID:      "emotic",
 					key:        "key",
 					isDisabled: false,
+					scopes:     []string{"shortlink:write", "shortlink:read"},
 				},
 			},
 			appID:  "emotic",
@@ -87,6 +99,7 @@ func TestAPIKeySQL_GetAPIKey(t *testing.T) {
 				AppID:      "emotic",
 				Key:        "key",
 				IsDisabled: false,
+				Scopes:     []string{"shortlink:read", "shortlink:write"},
 			},
 		},
 	}
@@ -113,6 +126,7 @@ func TestAPIKeySQL_GetAPIKey(t *testing.T) {
 					assert.Equal(t, testCase.expectedAPIKey.AppID, gotAPIKey.AppID)
 					assert.Equal(t, testCase.expectedAPIKey.Key, gotAPIKey.Key)
 					assert.Equal(t, testCase.expectedAPIKey.IsDisabled, gotAPIKey.IsDisabled)
+					assert.Equal(t, testCase.expectedAPIKey.Scopes, gotAPIKey.Scopes)
 				})
 		})
 	}
@@ -126,6 +140,7 @@ func TestAPIKeySQL_CreateAPIKey(t *testing.T) {
 		appID           string
 		key             string
 		isDisabled      bool
+		scopes          []string
 		hasErr          bool
 		expectedAPIKey  entity.APIKey
 	}{
@@ -170,11 +185,13 @@ func TestAPIKeySQL_CreateAPIKey(t *testing.T) {
 			appID:      "emotic",
 			key:        "key2",
 			isDisabled: false,
+			scopes:     []string{"shortlink:read"},
 			hasErr:     false,
 			expectedAPIKey: entity.APIKey{
 				AppID:      "emotic",
 				Key:        "key2",
 				IsDisabled: false,
+				Scopes:     []string{"shortlink:read"},
 			},
 		},
 	}
@@ -196,6 +213,7 @@ func TestAPIKeySQL_CreateAPIKey(t *testing.T) {
 						Key:        &testCase.key,
 						IsDisabled: &testCase.isDisabled,
 						CreatedAt:  nil,
+						Scopes:     testCase.scopes,
 					}
 					gotAPIKey, err := apiKeyRepo.CreateAPIKey(input)
 					if testCase.hasErr {
@@ -207,11 +225,87 @@ func TestAPIKeySQL_CreateAPIKey(t *testing.T) {
 					assert.Equal(t, testCase.expectedAPIKey.AppID, gotAPIKey.AppID)
 					assert.Equal(t, testCase.expectedAPIKey.Key, gotAPIKey.Key)
 					assert.Equal(t, testCase.expectedAPIKey.IsDisabled, gotAPIKey.IsDisabled)
+					assert.Equal(t, testCase.expectedAPIKey.Scopes, gotAPIKey.Scopes)
+
+					storedAPIKey, err := apiKeyRepo.GetAPIKey(testCase.appID, testCase.key)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedAPIKey.Scopes, storedAPIKey.Scopes)
 				})
 		})
 	}
 }
 
+func TestAPIKeySQL_GetAPIKeysByApp(t *testing.T) {
+	appTableRows := []appTableRow{
+		{id: "emotic"},
+		{id: "other"},
+	}
+	apiKeyTableRows := []apiKeyTableRow{
+		{appID: "emotic", key: "key1", createdAt: must.Time(t, "2020-07-17T15:04:05Z"), scopes: []string{"shortlink:read"}},
+		{appID: "emotic", key: "key2", isDisabled: true, createdAt: must.Time(t, "2020-07-18T15:04:05Z")},
+		{appID: "other", key: "key3", createdAt: must.Time(t, "2020-07-19T15:04:05Z")},
+	}
+
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertAppRows(t, sqlDB, appTableRows)
+			insertAPIKeyRows(t, sqlDB, apiKeyTableRows)
+
+			apiKeyRepo := sqldb.NewAPIKeySQL(sqlDB)
+			apiKeys, err := apiKeyRepo.GetAPIKeysByApp("emotic")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, 2, len(apiKeys))
+			assert.Equal(t, "key1", apiKeys[0].Key)
+			assert.Equal(t, false, apiKeys[0].IsDisabled)
+			assert.Equal(t, []string{"shortlink:read"}, apiKeys[0].Scopes)
+			assert.Equal(t, "key2", apiKeys[1].Key)
+			assert.Equal(t, true, apiKeys[1].IsDisabled)
+			assert.Equal(t, []string{}, apiKeys[1].Scopes)
+		})
+}
+
+func TestAPIKeySQL_UpdateAPIKey(t *testing.T) {
+	appTableRows := []appTableRow{
+		{id: "emotic"},
+	}
+	apiKeyTableRows := []apiKeyTableRow{
+		{appID: "emotic", key: "key"},
+	}
+	expireAt := must.Time(t, "2020-08-17T15:04:05Z")
+	lastUsedAt := must.Time(t, "2020-07-17T15:04:05Z")
+
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertAppRows(t, sqlDB, appTableRows)
+			insertAPIKeyRows(t, sqlDB, apiKeyTableRows)
+
+			apiKeyRepo := sqldb.NewAPIKeySQL(sqlDB)
+			err := apiKeyRepo.DisableAPIKey("emotic", "key")
+			assert.Equal(t, nil, err)
+			err = apiKeyRepo.UpdateExpireAt("emotic", "key", expireAt)
+			assert.Equal(t, nil, err)
+			err = apiKeyRepo.UpdateLastUsedAt("emotic", "key", lastUsedAt)
+			assert.Equal(t, nil, err)
+
+			apiKey, err := apiKeyRepo.GetAPIKey("emotic", "key")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, apiKey.IsDisabled)
+			assert.Equal(t, &expireAt, apiKey.ExpireAt)
+			assert.Equal(t, &lastUsedAt, apiKey.LastUsedAt)
+
+			err = apiKeyRepo.DisableAPIKey("emotic", "unknown")
+			assert.NotEqual(t, nil, err)
+		})
+}
+
 func insertAPIKeyRows(t *testing.T, sqlDB *sql.DB, tableRows []apiKeyTableRow) {
 	for _, tableRow := range tableRows {
 		_, err := sqlDB.Exec(
@@ -222,5 +316,10 @@ func insertAPIKeyRows(t *testing.T, sqlDB *sql.DB, tableRows []apiKeyTableRow) {
 			tableRow.createdAt,
 		)
 		assert.Equal(t, nil, err)
+
+		for _, scope := range tableRow.scopes {
+			_, err = sqlDB.Exec(insertAPIKeyScopeRowSQL, tableRow.appID, tableRow.key, scope)
+			assert.Equal(t, nil, err)
+		}
 	}
 }
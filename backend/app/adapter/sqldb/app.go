@@ -20,18 +20,21 @@ type AppSQL struct {
 // GetAppByID fetches an app with given ID from SQL DB.
 func (a AppSQL) GetAppByID(id string) (entity.App, error) {
 	query := fmt.Sprintf(`
-SELECT "%s", "%s" 
+SELECT "%s", "%s", "%s" 
 FROM "%s" WHERE "%s"=$1;
 `,
 		table.App.ColumnName,
+		table.App.ColumnOwnerID,
 		table.App.ColumnCreatedAt,
 		table.App.TableName,
 		table.App.ColumnID,
 	)
 	app := entity.App{}
-	err := a.db.QueryRow(query, id).Scan(&app.Name, &app.CreatedAt)
+	var ownerID sql.NullString
+	err := a.db.QueryRow(query, id).Scan(&app.Name, &ownerID, &app.CreatedAt)
 	if err == nil {
 		app.ID = id
+		app.OwnerID = ownerID.String
 		return app, nil
 	}
 	if errors.Is(err, sql.ErrNoRows) {
@@ -41,6 +44,123 @@ FROM "%s" WHERE "%s"=$1;
 	return entity.App{}, err
 }
 
+// GetAppsByOwner fetches all the apps owned by the given user from SQL DB.
+func (a AppSQL) GetAppsByOwner(ownerID string) ([]entity.App, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s", "%s"
+FROM "%s" WHERE "%s"=$1
+ORDER BY "%s";
+`,
+		table.App.ColumnID,
+		table.App.ColumnName,
+		table.App.ColumnCreatedAt,
+		table.App.TableName,
+		table.App.ColumnOwnerID,
+		table.App.ColumnCreatedAt,
+	)
+	rows, err := a.db.Query(query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apps []entity.App
+	for rows.Next() {
+		app := entity.App{OwnerID: ownerID}
+		err = rows.Scan(&app.ID, &app.Name, &app.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		app.CreatedAt = app.CreatedAt.UTC()
+		apps = append(apps, app)
+	}
+	return apps, rows.Err()
+}
+
+// CreateApp inserts a new app into SQL DB.
+func (a AppSQL) CreateApp(newApp entity.App) (entity.App, error) {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4);
+`,
+		table.App.TableName,
+		table.App.ColumnID,
+		table.App.ColumnName,
+		table.App.ColumnOwnerID,
+		table.App.ColumnCreatedAt,
+	)
+
+	_, err := a.db.Exec(
+		statement,
+		newApp.ID,
+		newApp.Name,
+		newApp.OwnerID,
+		newApp.CreatedAt,
+	)
+	if err != nil {
+		return entity.App{}, err
+	}
+	return newApp, nil
+}
+
+// UpdateApp renames an existing app in SQL DB.
+func (a AppSQL) UpdateApp(newApp entity.App) (entity.App, error) {
+	statement := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2
+RETURNING "%s", "%s";
+`,
+		table.App.TableName,
+		table.App.ColumnName,
+		table.App.ColumnID,
+		table.App.ColumnOwnerID,
+		table.App.ColumnCreatedAt,
+	)
+
+	app := entity.App{
+		ID:   newApp.ID,
+		Name: newApp.Name,
+	}
+	var ownerID sql.NullString
+	err := a.db.QueryRow(statement, newApp.Name, newApp.ID).Scan(&ownerID, &app.CreatedAt)
+	if err == nil {
+		app.OwnerID = ownerID.String
+		return app, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.App{},
+			repository.ErrEntryNotFound(fmt.Sprintf("ID(%s)", newApp.ID))
+	}
+	return entity.App{}, err
+}
+
+// DeleteApp removes an app with given ID together with its API keys from SQL
+// DB.
+func (a AppSQL) DeleteApp(id string) error {
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.App.TableName,
+		table.App.ColumnID,
+	)
+
+	result, err := a.db.Exec(statement, id)
+	if err != nil {
+		return err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRowCount == 0 {
+		return repository.ErrEntryNotFound(fmt.Sprintf("ID(%s)", id))
+	}
+	return nil
+}
+
 // NewAppSQL creates AppSQL.
 func NewAppSQL(db *sql.DB) AppSQL {
 	return AppSQL{
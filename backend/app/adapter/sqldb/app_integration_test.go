@@ -90,6 +90,107 @@ func TestAppSQL_FindAppByID(t *testing.T) {
 	}
 }
 
+func TestAppSQL_GetAppsByOwner(t *testing.T) {
+	userRows := []userTableRow{{id: "alpha"}, {id: "beta"}}
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, userRows)
+
+			appRepo := sqldb.NewAppSQL(sqlDB)
+			apps := []entity.App{
+				{
+					ID:        "emotic",
+					Name:      "Feedback Widget",
+					OwnerID:   "alpha",
+					CreatedAt: must.Time(t, "2017-05-01T08:02:16Z"),
+				},
+				{
+					ID:        "chatbot",
+					Name:      "Chat Bot",
+					OwnerID:   "beta",
+					CreatedAt: must.Time(t, "2017-05-02T08:02:16Z"),
+				},
+				{
+					ID:        "gallery",
+					Name:      "Photo Gallery",
+					OwnerID:   "alpha",
+					CreatedAt: must.Time(t, "2017-05-03T08:02:16Z"),
+				},
+			}
+			for _, app := range apps {
+				_, err := appRepo.CreateApp(app)
+				assert.Equal(t, nil, err)
+			}
+
+			gotApps, err := appRepo.GetAppsByOwner("alpha")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.App{apps[0], apps[2]}, gotApps)
+		})
+}
+
+func TestAppSQL_UpdateApp(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}})
+
+			appRepo := sqldb.NewAppSQL(sqlDB)
+			_, err := appRepo.CreateApp(entity.App{
+				ID:        "emotic",
+				Name:      "Feedback Widget",
+				OwnerID:   "alpha",
+				CreatedAt: must.Time(t, "2017-05-01T08:02:16Z"),
+			})
+			assert.Equal(t, nil, err)
+
+			_, err = appRepo.UpdateApp(entity.App{ID: "unknown", Name: "Emotic"})
+			assert.NotEqual(t, nil, err)
+
+			gotApp, err := appRepo.UpdateApp(entity.App{ID: "emotic", Name: "Emotic"})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "Emotic", gotApp.Name)
+			assert.Equal(t, "alpha", gotApp.OwnerID)
+
+			gotApp, err = appRepo.GetAppByID("emotic")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "Emotic", gotApp.Name)
+		})
+}
+
+func TestAppSQL_DeleteApp(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertAppRows(t, sqlDB, []appTableRow{
+				{
+					id:        "emotic",
+					name:      "Feedback Widget",
+					createdAt: must.Time(t, "2017-05-01T08:02:16Z"),
+				},
+			})
+
+			appRepo := sqldb.NewAppSQL(sqlDB)
+			err := appRepo.DeleteApp("unknown")
+			assert.NotEqual(t, nil, err)
+
+			err = appRepo.DeleteApp("emotic")
+			assert.Equal(t, nil, err)
+
+			_, err = appRepo.GetAppByID("emotic")
+			assert.NotEqual(t, nil, err)
+		})
+}
+
 func insertAppRows(t *testing.T, sqlDB *sql.DB, tableRows []appTableRow) {
 	for _, tableRow := range tableRows {
 		_, err := sqlDB.Exec(
@@ -0,0 +1,115 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.AppShortLink = (*AppShortLinkSQL)(nil)
+
+// AppShortLinkSQL accesses AppShortLink information in app_short_link table.
+type AppShortLinkSQL struct {
+	db *sql.DB
+}
+
+// CreateRelation records that a short link is created by the given app in
+// app_short_link table.
+func (a AppShortLinkSQL) CreateRelation(app entity.App, shortLinkInput entity.ShortLinkInput) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s","%s")
+VALUES ($1,$2)
+`,
+		table.AppShortLink.TableName,
+		table.AppShortLink.ColumnAppID,
+		table.AppShortLink.ColumnShortLinkAlias,
+	)
+
+	_, err := a.db.Exec(statement, app.ID, shortLinkInput.GetCustomAlias(""))
+	return err
+}
+
+// FindAliasesByApp fetches the aliases of all the ShortLinks created by the given app.
+func (a AppShortLinkSQL) FindAliasesByApp(app entity.App) ([]string, error) {
+	statement := fmt.Sprintf(`SELECT "%s" FROM "%s" WHERE "%s"=$1;`,
+		table.AppShortLink.ColumnShortLinkAlias,
+		table.AppShortLink.TableName,
+		table.AppShortLink.ColumnAppID,
+	)
+
+	rows, err := a.db.Query(statement, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		err = rows.Scan(&alias)
+		if err != nil {
+			return aliases, err
+		}
+
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+// HasMapping checks whether a given short link is created by an app.
+func (a AppShortLinkSQL) HasMapping(app entity.App, alias string) (bool, error) {
+	query := fmt.Sprintf(`SELECT "%s" FROM "%s" WHERE "%s"=$1 AND "%s"=$2`,
+		table.AppShortLink.ColumnAppID,
+		table.AppShortLink.TableName,
+		table.AppShortLink.ColumnAppID,
+		table.AppShortLink.ColumnShortLinkAlias,
+	)
+
+	var id string
+	err := a.db.QueryRow(query, app.ID, alias).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CountShortLinksByApp counts the short links ever created by the given app
+// from app_short_link_creation table, including the deleted ones.
+func (a AppShortLinkSQL) CountShortLinksByApp(app entity.App) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s" WHERE "%s"=$1;`,
+		table.AppShortLinkCreation.TableName,
+		table.AppShortLinkCreation.ColumnAppID,
+	)
+
+	var count int
+	err := a.db.QueryRow(query, app.ID).Scan(&count)
+	return count, err
+}
+
+// CountShortLinksByAppSince counts the short links created by the given app
+// at or after the given time, including the deleted ones.
+func (a AppShortLinkSQL) CountShortLinksByAppSince(app entity.App, since time.Time) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s" WHERE "%s"=$1 AND "%s">=$2;`,
+		table.AppShortLinkCreation.TableName,
+		table.AppShortLinkCreation.ColumnAppID,
+		table.AppShortLinkCreation.ColumnCreatedAt,
+	)
+
+	var count int
+	err := a.db.QueryRow(query, app.ID, since.UTC()).Scan(&count)
+	return count, err
+}
+
+// NewAppShortLinkSQL creates AppShortLinkSQL
+func NewAppShortLinkSQL(db *sql.DB) AppShortLinkSQL {
+	return AppShortLinkSQL{
+		db: db,
+	}
+}
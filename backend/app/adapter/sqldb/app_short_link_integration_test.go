@@ -0,0 +1,249 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
+)
+
+var insertAppShortLinkRowSQL = fmt.Sprintf(`
+INSERT INTO %s (%s, %s)
+VALUES ($1, $2)`,
+	table.AppShortLink.TableName,
+	table.AppShortLink.ColumnAppID,
+	table.AppShortLink.ColumnShortLinkAlias,
+)
+
+type appShortLinkTableRow struct {
+	appID string
+	alias string
+}
+
+func TestAppShortLinkSQL_CreateRelation(t *testing.T) {
+	appRows := []appTableRow{
+		{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+	}
+	shortLinkRows := []shortLinkTableRow{
+		{alias: "abcd-123-xyz", longLink: "https://www.google.com"},
+	}
+
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertAppRows(t, sqlDB, appRows)
+			insertShortLinkTableRows(t, sqlDB, shortLinkRows)
+
+			app := entity.App{ID: "emotic"}
+			appShortLinkRepo := sqldb.NewAppShortLinkSQL(sqlDB)
+			err := appShortLinkRepo.CreateRelation(app, entity.ShortLinkInput{
+				CustomAlias: ptr.String("abcd-123-xyz"),
+			})
+			assert.Equal(t, nil, err)
+
+			aliases, err := appShortLinkRepo.FindAliasesByApp(app)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []string{"abcd-123-xyz"}, aliases)
+		})
+}
+
+func TestAppShortLinkSQL_FindAliasesByApp(t *testing.T) {
+	testCases := []struct {
+		name               string
+		appTableRows       []appTableRow
+		shortLinkTableRows []shortLinkTableRow
+		relationTableRows  []appShortLinkTableRow
+		app                entity.App
+		expectedAliases    []string
+	}{
+		{
+			name:               "no alias found",
+			appTableRows:       []appTableRow{},
+			shortLinkTableRows: []shortLinkTableRow{},
+			relationTableRows:  []appShortLinkTableRow{},
+			app:                entity.App{ID: "emotic"},
+			expectedAliases:    nil,
+		},
+		{
+			name: "only aliases of the app found",
+			appTableRows: []appTableRow{
+				{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+				{id: "other", name: "Other App", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+			},
+			shortLinkTableRows: []shortLinkTableRow{
+				{alias: "abcd-123-xyz", longLink: "https://www.google.com"},
+				{alias: "efgh", longLink: "https://www.facebook.com"},
+			},
+			relationTableRows: []appShortLinkTableRow{
+				{appID: "emotic", alias: "abcd-123-xyz"},
+				{appID: "other", alias: "efgh"},
+			},
+			app:             entity.App{ID: "emotic"},
+			expectedAliases: []string{"abcd-123-xyz"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertAppRows(t, sqlDB, testCase.appTableRows)
+					insertShortLinkTableRows(t, sqlDB, testCase.shortLinkTableRows)
+					insertAppShortLinkTableRows(t, sqlDB, testCase.relationTableRows)
+
+					appShortLinkRepo := sqldb.NewAppShortLinkSQL(sqlDB)
+					aliases, err := appShortLinkRepo.FindAliasesByApp(testCase.app)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedAliases, aliases)
+				})
+		})
+	}
+}
+
+func TestAppShortLinkSQL_HasMapping(t *testing.T) {
+	testCases := []struct {
+		name               string
+		appTableRows       []appTableRow
+		shortLinkTableRows []shortLinkTableRow
+		relationTableRows  []appShortLinkTableRow
+		app                entity.App
+		alias              string
+		expectIsFound      bool
+	}{
+		{
+			name: "alias not created by the app",
+			appTableRows: []appTableRow{
+				{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+			},
+			shortLinkTableRows: []shortLinkTableRow{
+				{alias: "abcd-123-xyz", longLink: "https://www.google.com"},
+			},
+			relationTableRows: []appShortLinkTableRow{},
+			app:               entity.App{ID: "emotic"},
+			alias:             "abcd-123-xyz",
+			expectIsFound:     false,
+		},
+		{
+			name: "alias created by the app",
+			appTableRows: []appTableRow{
+				{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+			},
+			shortLinkTableRows: []shortLinkTableRow{
+				{alias: "abcd-123-xyz", longLink: "https://www.google.com"},
+			},
+			relationTableRows: []appShortLinkTableRow{
+				{appID: "emotic", alias: "abcd-123-xyz"},
+			},
+			app:           entity.App{ID: "emotic"},
+			alias:         "abcd-123-xyz",
+			expectIsFound: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertAppRows(t, sqlDB, testCase.appTableRows)
+					insertShortLinkTableRows(t, sqlDB, testCase.shortLinkTableRows)
+					insertAppShortLinkTableRows(t, sqlDB, testCase.relationTableRows)
+
+					appShortLinkRepo := sqldb.NewAppShortLinkSQL(sqlDB)
+					isFound, err := appShortLinkRepo.HasMapping(testCase.app, testCase.alias)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectIsFound, isFound)
+				})
+		})
+	}
+}
+
+func TestAppShortLinkSQL_CountShortLinksByApp(t *testing.T) {
+	testCases := []struct {
+		name                string
+		creationTableRows   []appShortLinkCreationTableRow
+		since               time.Time
+		expectedTotalCount  int
+		expectedRecentCount int
+	}{
+		{
+			name:                "no short link created",
+			creationTableRows:   []appShortLinkCreationTableRow{},
+			since:               must.Time(t, "2020-05-01T00:00:00Z"),
+			expectedTotalCount:  0,
+			expectedRecentCount: 0,
+		},
+		{
+			name: "short links created by different apps",
+			creationTableRows: []appShortLinkCreationTableRow{
+				{appID: "emotic", alias: "old", createdAt: must.Time(t, "2020-04-30T23:59:59Z")},
+				{appID: "emotic", alias: "new", createdAt: must.Time(t, "2020-05-01T00:00:00Z")},
+				{appID: "widget", alias: "other", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+			},
+			since:               must.Time(t, "2020-05-01T00:00:00Z"),
+			expectedTotalCount:  2,
+			expectedRecentCount: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertAppRows(t, sqlDB, []appTableRow{
+						{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+						{id: "widget", name: "Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+					})
+					insertAppShortLinkCreationTableRows(t, sqlDB, testCase.creationTableRows)
+
+					appShortLinkRepo := sqldb.NewAppShortLinkSQL(sqlDB)
+					app := entity.App{ID: "emotic"}
+
+					totalCount, err := appShortLinkRepo.CountShortLinksByApp(app)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedTotalCount, totalCount)
+
+					recentCount, err := appShortLinkRepo.CountShortLinksByAppSince(app, testCase.since)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedRecentCount, recentCount)
+				})
+		})
+	}
+}
+
+func insertAppShortLinkTableRows(t *testing.T, sqlDB *sql.DB, tableRows []appShortLinkTableRow) {
+	for _, tableRow := range tableRows {
+		_, err := sqlDB.Exec(
+			insertAppShortLinkRowSQL,
+			tableRow.appID,
+			tableRow.alias,
+		)
+		assert.Equal(t, nil, err)
+	}
+}
@@ -0,0 +1,81 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.EmailSignInToken = (*EmailSignInTokenSQL)(nil)
+
+// EmailSignInTokenSQL accesses EmailSignInToken from the database through SQL.
+type EmailSignInTokenSQL struct {
+	db *sql.DB
+}
+
+// CreateToken appends a new EmailSignInToken entry to EmailSignInToken table
+// using SQL.
+func (e EmailSignInTokenSQL) CreateToken(token entity.EmailSignInToken) error {
+	stmt := fmt.Sprintf(`
+INSERT INTO "%s"("%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4);
+`,
+		table.EmailSignInToken.TableName,
+		table.EmailSignInToken.ColumnID,
+		table.EmailSignInToken.ColumnEmail,
+		table.EmailSignInToken.ColumnCreatedAt,
+		table.EmailSignInToken.ColumnExpireAt,
+	)
+	_, err := e.db.Exec(stmt, token.ID, token.Email, token.CreatedAt, token.ExpireAt)
+	return err
+}
+
+// UseToken marks an EmailSignInToken as used in EmailSignInToken table using
+// SQL. It fails if the token is already used or expired, so that a token can
+// only be redeemed once.
+func (e EmailSignInTokenSQL) UseToken(id string, usedAt time.Time) (entity.EmailSignInToken, error) {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s" IS NULL AND "%s">$1
+RETURNING "%s", "%s", "%s", "%s", "%s";
+`,
+		table.EmailSignInToken.TableName,
+		table.EmailSignInToken.ColumnUsedAt,
+		table.EmailSignInToken.ColumnID,
+		table.EmailSignInToken.ColumnUsedAt,
+		table.EmailSignInToken.ColumnExpireAt,
+		table.EmailSignInToken.ColumnID,
+		table.EmailSignInToken.ColumnEmail,
+		table.EmailSignInToken.ColumnCreatedAt,
+		table.EmailSignInToken.ColumnExpireAt,
+		table.EmailSignInToken.ColumnUsedAt,
+	)
+
+	token := entity.EmailSignInToken{}
+	err := e.db.QueryRow(stmt, usedAt, id).Scan(
+		&token.ID,
+		&token.Email,
+		&token.CreatedAt,
+		&token.ExpireAt,
+		&token.UsedAt,
+	)
+	if err == nil {
+		return token, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.EmailSignInToken{}, repository.ErrEntryNotFound(
+			fmt.Sprintf("unused email sign in token(%s) not found", id))
+	}
+	return entity.EmailSignInToken{}, err
+}
+
+// NewEmailSignInTokenSQL creates EmailSignInTokenSQL.
+func NewEmailSignInTokenSQL(db *sql.DB) EmailSignInTokenSQL {
+	return EmailSignInTokenSQL{db: db}
+}
@@ -0,0 +1,60 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestEmailSignInTokenSQL_UseToken(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			tokenRepo := sqldb.NewEmailSignInTokenSQL(sqlDB)
+
+			tokens := []entity.EmailSignInToken{
+				{
+					ID:        "token1",
+					Email:     "alpha@example.com",
+					CreatedAt: must.Time(t, "2020-05-01T08:00:00Z"),
+					ExpireAt:  must.Time(t, "2020-05-01T08:15:00Z"),
+				},
+				{
+					ID:        "token2",
+					Email:     "beta@example.com",
+					CreatedAt: must.Time(t, "2020-05-01T07:00:00Z"),
+					ExpireAt:  must.Time(t, "2020-05-01T07:15:00Z"),
+				},
+			}
+			for _, token := range tokens {
+				err := tokenRepo.CreateToken(token)
+				assert.Equal(t, nil, err)
+			}
+
+			usedAt := must.Time(t, "2020-05-01T08:05:00Z")
+			_, err := tokenRepo.UseToken("unknown", usedAt)
+			assert.NotEqual(t, nil, err)
+
+			_, err = tokenRepo.UseToken("token2", usedAt)
+			assert.NotEqual(t, nil, err)
+
+			token, err := tokenRepo.UseToken("token1", usedAt)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "alpha@example.com", token.Email)
+			assert.NotEqual(t, nil, token.UsedAt)
+			assert.Equal(t, true, usedAt.Equal(*token.UsedAt))
+
+			_, err = tokenRepo.UseToken("token1", usedAt)
+			assert.NotEqual(t, nil, err)
+		})
+}
@@ -83,6 +83,59 @@ VALUES ($1, $2);
 	return err
 }
 
+// GetSSOUserID retrieves the ID of the Facebook account linked to the user's
+// Short account.
+func (g FacebookSSOSql) GetSSOUserID(shortUserID string) (string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.FacebookSSO.ColumnFacebookUserID,
+		table.FacebookSSO.TableName,
+		table.FacebookSSO.ColumnShortUserID,
+	)
+	var id string
+	err := g.db.QueryRow(query, shortUserID).Scan(&id)
+	if err == nil {
+		return id, err
+	}
+	if err == sql.ErrNoRows {
+		return "", repository.ErrEntryNotFound(
+			fmt.Sprintf("Facebook account of user %s not found", shortUserID),
+		)
+	}
+	g.logger.Error(err)
+	return "", err
+}
+
+// DeleteMapping unlinks user's Facebook and Short accounts in the database.
+func (g FacebookSSOSql) DeleteMapping(ssoUserID string, shortUserID string) error {
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.FacebookSSO.TableName,
+		table.FacebookSSO.ColumnFacebookUserID,
+		table.FacebookSSO.ColumnShortUserID,
+	)
+	res, err := g.db.Exec(statement, ssoUserID, shortUserID)
+	if err != nil {
+		g.logger.Error(err)
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("Facebook account %s not linked to user %s", ssoUserID, shortUserID),
+		)
+	}
+	return nil
+}
+
 // NewFacebookSSOSql creates FacebookSSOSql.
 func NewFacebookSSOSql(db *sql.DB, logger logger.Logger) FacebookSSOSql {
 	return FacebookSSOSql{db: db, logger: logger}
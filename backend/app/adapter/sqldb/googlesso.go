@@ -83,6 +83,59 @@ VALUES ($1, $2);
 	return err
 }
 
+// GetSSOUserID retrieves the ID of the Google account linked to the user's
+// Short account.
+func (g GoogleSSOSql) GetSSOUserID(shortUserID string) (string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.GoogleSSO.ColumnGoogleUserID,
+		table.GoogleSSO.TableName,
+		table.GoogleSSO.ColumnShortUserID,
+	)
+	var id string
+	err := g.db.QueryRow(query, shortUserID).Scan(&id)
+	if err == nil {
+		return id, err
+	}
+	if err == sql.ErrNoRows {
+		return "", repository.ErrEntryNotFound(
+			fmt.Sprintf("Google account of user %s not found", shortUserID),
+		)
+	}
+	g.logger.Error(err)
+	return "", err
+}
+
+// DeleteMapping unlinks user's Google and Short accounts in the database.
+func (g GoogleSSOSql) DeleteMapping(ssoUserID string, shortUserID string) error {
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.GoogleSSO.TableName,
+		table.GoogleSSO.ColumnGoogleUserID,
+		table.GoogleSSO.ColumnShortUserID,
+	)
+	res, err := g.db.Exec(statement, ssoUserID, shortUserID)
+	if err != nil {
+		g.logger.Error(err)
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("Google account %s not linked to user %s", ssoUserID, shortUserID),
+		)
+	}
+	return nil
+}
+
 // NewGoogleSSOSql creates GoogleSSOSql.
 func NewGoogleSSOSql(db *sql.DB, logger logger.Logger) GoogleSSOSql {
 	return GoogleSSOSql{db: db, logger: logger}
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE "short_link"
+    ADD COLUMN "health_status_code" INTEGER,
+    ADD COLUMN "health_checked_at" TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE "short_link"
+    DROP COLUMN "health_status_code",
+    DROP COLUMN "health_checked_at";
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE "app_short_link"
+(
+    "app_id" VARCHAR(10) NOT NULL REFERENCES "app"("id") ON DELETE CASCADE,
+    "short_link_alias" CHARACTER VARYING(50) NOT NULL REFERENCES "short_link"("alias") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT pk_app_short_link PRIMARY KEY ("app_id", "short_link_alias")
+);
+
+-- +migrate Down
+DROP TABLE "app_short_link";
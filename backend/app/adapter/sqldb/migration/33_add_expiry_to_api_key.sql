@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE "api_key"
+    ADD COLUMN "expire_at" TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN "last_used_at" TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE "api_key"
+    DROP COLUMN "expire_at",
+    DROP COLUMN "last_used_at";
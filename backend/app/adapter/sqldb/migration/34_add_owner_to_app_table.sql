@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE "app"
+    ADD COLUMN "owner_id" CHARACTER VARYING(5) REFERENCES "user"(id) ON DELETE CASCADE;
+ALTER TABLE "api_key"
+    DROP CONSTRAINT "api_key_app_id_fkey";
+ALTER TABLE "api_key"
+    ADD CONSTRAINT "api_key_app_id_fkey" FOREIGN KEY (app_id) REFERENCES "app"(id) ON DELETE CASCADE;
+
+-- +migrate Down
+ALTER TABLE "api_key"
+    DROP CONSTRAINT "api_key_app_id_fkey";
+ALTER TABLE "api_key"
+    ADD CONSTRAINT "api_key_app_id_fkey" FOREIGN KEY (app_id) REFERENCES "app"(id);
+ALTER TABLE "app"
+    DROP COLUMN "owner_id";
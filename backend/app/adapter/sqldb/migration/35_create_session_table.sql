@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE "session"
+(
+    "id"                       VARCHAR(10) PRIMARY KEY,
+    "user_id"                  CHARACTER VARYING(5) NOT NULL REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    "refresh_token_generation" INTEGER NOT NULL DEFAULT 0,
+    "created_at"               TIMESTAMP WITH TIME ZONE NOT NULL,
+    "expire_at"                TIMESTAMP WITH TIME ZONE NOT NULL,
+    "revoked_at"               TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX "session_user_id_idx" ON "session"("user_id");
+
+-- +migrate Down
+DROP TABLE "session";
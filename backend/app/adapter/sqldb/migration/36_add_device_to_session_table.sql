@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE "session"
+    ADD COLUMN "identity_provider" CHARACTER VARYING(50) NOT NULL DEFAULT '',
+    ADD COLUMN "client_ip"         CHARACTER VARYING(45) NOT NULL DEFAULT '',
+    ADD COLUMN "location"          CHARACTER VARYING(200) NOT NULL DEFAULT '',
+    ADD COLUMN "user_agent"        TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE "session"
+    DROP COLUMN "identity_provider",
+    DROP COLUMN "client_ip",
+    DROP COLUMN "location",
+    DROP COLUMN "user_agent";
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE "sso_account"
+(
+    "provider"      CHARACTER VARYING(50)  NOT NULL,
+    "subject"       CHARACTER VARYING(254) NOT NULL,
+    "short_user_id" CHARACTER VARYING(5)   NOT NULL REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY ("provider", "subject"),
+    UNIQUE ("provider", "short_user_id")
+);
+
+-- +migrate Down
+DROP TABLE "sso_account";
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE "email_sign_in_token"
+(
+    "id"         CHARACTER VARYING(64)    PRIMARY KEY,
+    "email"      CHARACTER VARYING(254)   NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "expire_at"  TIMESTAMP WITH TIME ZONE NOT NULL,
+    "used_at"    TIMESTAMP WITH TIME ZONE
+);
+
+-- +migrate Down
+DROP TABLE "email_sign_in_token";
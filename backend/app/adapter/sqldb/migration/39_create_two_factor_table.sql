@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE "two_factor"
+(
+    "user_id"        CHARACTER VARYING(5)     PRIMARY KEY REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    "secret"         CHARACTER VARYING(64)    NOT NULL,
+    "last_time_step" BIGINT                   NOT NULL DEFAULT 0,
+    "created_at"     TIMESTAMP WITH TIME ZONE NOT NULL,
+    "enabled_at"     TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE "two_factor_recovery_code"
+(
+    "user_id"   CHARACTER VARYING(5)     NOT NULL REFERENCES "two_factor"(user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    "code_hash" CHARACTER VARYING(64)    NOT NULL,
+    "used_at"   TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY ("user_id", "code_hash")
+);
+
+-- +migrate Down
+DROP TABLE "two_factor_recovery_code";
+DROP TABLE "two_factor";
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE "role_change"
+(
+    "id"         BIGSERIAL                PRIMARY KEY,
+    "actor_id"   CHARACTER VARYING(5)     NOT NULL,
+    "user_id"    CHARACTER VARYING(5)     NOT NULL,
+    "role"       CHARACTER VARYING(255)   NOT NULL,
+    "action"     CHARACTER VARYING(10)    NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX "role_change_user_id_idx" ON "role_change"("user_id");
+
+-- +migrate Down
+DROP TABLE "role_change";
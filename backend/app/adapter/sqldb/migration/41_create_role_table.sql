@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE "role"
+(
+    "name"        CHARACTER VARYING(50)    PRIMARY KEY,
+    "is_built_in" BOOLEAN                  NOT NULL DEFAULT FALSE,
+    "created_at"  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "role_permission"
+(
+    "role"       CHARACTER VARYING(50)  NOT NULL REFERENCES "role"("name")
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    "permission" CHARACTER VARYING(100) NOT NULL,
+    PRIMARY KEY ("role", "permission")
+);
+
+INSERT INTO "role" ("name", "is_built_in")
+VALUES ('basic', TRUE),
+       ('premium', TRUE),
+       ('security_specialist', TRUE),
+       ('short_link_viewer', TRUE),
+       ('short_link_editor', TRUE),
+       ('changelog_viewer', TRUE),
+       ('changelog_editor', TRUE),
+       ('admin', TRUE);
+
+-- +migrate Down
+DROP TABLE "role_permission";
+DROP TABLE "role";
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE "short_link_attribute"
+(
+    "short_link_alias" CHARACTER VARYING(50)  NOT NULL REFERENCES "short_link"("alias")
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    "name"             CHARACTER VARYING(50)  NOT NULL,
+    "value"            CHARACTER VARYING(100) NOT NULL,
+    CONSTRAINT pk_short_link_attribute PRIMARY KEY ("short_link_alias", "name", "value")
+);
+
+-- +migrate Down
+DROP TABLE "short_link_attribute";
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE "api_key_scope"
+(
+    "app_id" VARCHAR(10)           NOT NULL,
+    "key"    VARCHAR(10)           NOT NULL,
+    "scope"  CHARACTER VARYING(50) NOT NULL,
+    CONSTRAINT pk_api_key_scope PRIMARY KEY ("app_id", "key", "scope"),
+    CONSTRAINT fk_api_key_scope_api_key FOREIGN KEY ("app_id", "key")
+        REFERENCES "api_key" ("app_id", "key") ON DELETE CASCADE
+);
+
+-- API keys issued before scopes were introduced could access every short link
+-- API. Grant them the same access so that they keep working.
+INSERT INTO "api_key_scope" ("app_id", "key", "scope")
+SELECT "app_id", "key", 'shortlink:read'
+FROM "api_key";
+
+INSERT INTO "api_key_scope" ("app_id", "key", "scope")
+SELECT "app_id", "key", 'shortlink:write'
+FROM "api_key";
+
+-- +migrate Down
+DROP TABLE "api_key_scope";
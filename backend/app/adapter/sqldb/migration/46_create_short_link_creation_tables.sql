@@ -0,0 +1,40 @@
+-- +migrate Up
+CREATE TABLE "user_short_link_creation"
+(
+    "id"               BIGSERIAL                PRIMARY KEY,
+    "user_id"          CHARACTER VARYING(5)     NOT NULL REFERENCES "user"("id") ON DELETE CASCADE,
+    "short_link_alias" CHARACTER VARYING(50)    NOT NULL,
+    "created_at"       TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX "user_short_link_creation_user_id_created_at_idx"
+    ON "user_short_link_creation"("user_id", "created_at");
+
+CREATE TABLE "app_short_link_creation"
+(
+    "id"               BIGSERIAL                PRIMARY KEY,
+    "app_id"           VARCHAR(10)              NOT NULL REFERENCES "app"("id") ON DELETE CASCADE,
+    "short_link_alias" CHARACTER VARYING(50)    NOT NULL,
+    "created_at"       TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX "app_short_link_creation_app_id_created_at_idx"
+    ON "app_short_link_creation"("app_id", "created_at");
+
+-- Only the short links which still exist can be recorded. Short links without
+-- creation time are counted as created long ago.
+INSERT INTO "user_short_link_creation" ("user_id", "short_link_alias", "created_at")
+SELECT "user_short_link"."user_id",
+       "user_short_link"."short_link_alias",
+       COALESCE("short_link"."created_at", TIMESTAMP WITH TIME ZONE 'epoch')
+FROM "user_short_link"
+INNER JOIN "short_link" ON "short_link"."alias"="user_short_link"."short_link_alias";
+
+INSERT INTO "app_short_link_creation" ("app_id", "short_link_alias", "created_at")
+SELECT "app_short_link"."app_id",
+       "app_short_link"."short_link_alias",
+       COALESCE("short_link"."created_at", TIMESTAMP WITH TIME ZONE 'epoch')
+FROM "app_short_link"
+INNER JOIN "short_link" ON "short_link"."alias"="app_short_link"."short_link_alias";
+
+-- +migrate Down
+DROP TABLE "app_short_link_creation";
+DROP TABLE "user_short_link_creation";
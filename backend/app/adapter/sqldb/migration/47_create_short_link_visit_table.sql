@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE "short_link_visit"
+(
+    "short_link_alias" CHARACTER VARYING(50)    PRIMARY KEY REFERENCES "short_link"("alias")
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    "visit_count"      BIGINT                   NOT NULL,
+    "last_visited_at"  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE "short_link_visit";
@@ -0,0 +1,281 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.Role = (*RoleSQL)(nil)
+
+// RoleSQL accesses the definitions of custom roles from role and
+// role_permission tables.
+type RoleSQL struct {
+	db *sql.DB
+}
+
+// GetCustomRoles fetches the definitions of all the custom roles ordered by
+// name. The permissions which are no longer defined in code are skipped.
+func (r RoleSQL) GetCustomRoles() ([]role.Definition, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"."%s", "%s"."%s"
+FROM "%s"
+LEFT JOIN "%s"
+ON "%s"."%s"="%s"."%s"
+WHERE "%s"."%s"=FALSE
+ORDER BY "%s"."%s", "%s"."%s";
+`,
+		table.Role.TableName,
+		table.Role.ColumnName,
+		table.RolePermission.TableName,
+		table.RolePermission.ColumnPermission,
+		table.Role.TableName,
+		table.RolePermission.TableName,
+		table.Role.TableName,
+		table.Role.ColumnName,
+		table.RolePermission.TableName,
+		table.RolePermission.ColumnRole,
+		table.Role.TableName,
+		table.Role.ColumnIsBuiltIn,
+		table.Role.TableName,
+		table.Role.ColumnName,
+		table.RolePermission.TableName,
+		table.RolePermission.ColumnPermission,
+	)
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	definitions := []role.Definition{}
+	for rows.Next() {
+		var (
+			name           string
+			permissionName sql.NullString
+		)
+		err = rows.Scan(&name, &permissionName)
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(definitions) - 1
+		if last < 0 || definitions[last].Name != role.Role(name) {
+			definitions = append(definitions, role.Definition{
+				Name:        role.Role(name),
+				Permissions: []permission.Permission{},
+			})
+			last++
+		}
+		if !permissionName.Valid {
+			continue
+		}
+		p, ok := permission.Parse(permissionName.String)
+		if !ok {
+			continue
+		}
+		definitions[last].Permissions = append(definitions[last].Permissions, p)
+	}
+	return definitions, rows.Err()
+}
+
+// CreateRole adds a new custom role together with its permissions.
+func (r RoleSQL) CreateRole(definition role.Definition) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s")
+VALUES ($1)
+ON CONFLICT DO NOTHING;
+`,
+		table.Role.TableName,
+		table.Role.ColumnName,
+	)
+	res, err := tx.Exec(statement, definition.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected < 1 {
+		tx.Rollback()
+		return repository.ErrEntryExists(fmt.Sprintf("role(%s)", definition.Name))
+	}
+
+	err = createRolePermissions(tx, definition)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateRole replaces the permissions of a custom role.
+func (r RoleSQL) UpdateRole(definition role.Definition) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1 AND "%s"=FALSE
+FOR UPDATE;
+`,
+		table.Role.ColumnName,
+		table.Role.TableName,
+		table.Role.ColumnName,
+		table.Role.ColumnIsBuiltIn,
+	)
+	var name string
+	err = tx.QueryRow(query, definition.Name).Scan(&name)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("custom role(%s) not found", definition.Name))
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.RolePermission.TableName,
+		table.RolePermission.ColumnRole,
+	)
+	_, err = tx.Exec(statement, definition.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = createRolePermissions(tx, definition)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteRole removes a custom role and revokes it from its users, recording
+// each revocation as a role change made by the actor.
+func (r RoleSQL) DeleteRole(name role.Role, actorID string, deletedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=FALSE;
+`,
+		table.Role.TableName,
+		table.Role.ColumnName,
+		table.Role.ColumnIsBuiltIn,
+	)
+	res, err := tx.Exec(statement, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected < 1 {
+		tx.Rollback()
+		return repository.ErrEntryNotFound(fmt.Sprintf("custom role(%s) not found", name))
+	}
+
+	statement = fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1
+RETURNING "%s";
+`,
+		table.UserRole.TableName,
+		table.UserRole.ColumnRole,
+		table.UserRole.ColumnUserID,
+	)
+	userIDs, err := revokedUserIDs(tx, statement, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, userID := range userIDs {
+		err = createRoleChange(tx, entity.RoleChange{
+			ActorID:   actorID,
+			UserID:    userID,
+			Role:      string(name),
+			Action:    entity.RoleRevoked,
+			CreatedAt: deletedAt,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func revokedUserIDs(tx *sql.Tx, statement string, name role.Role) ([]string, error) {
+	rows, err := tx.Query(statement, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		err = rows.Scan(&userID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+func createRolePermissions(tx *sql.Tx, definition role.Definition) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s")
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+`,
+		table.RolePermission.TableName,
+		table.RolePermission.ColumnRole,
+		table.RolePermission.ColumnPermission,
+	)
+	for _, p := range definition.Permissions {
+		_, err := tx.Exec(statement, definition.Name, p.Name())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewRoleSQL creates RoleSQL.
+func NewRoleSQL(db *sql.DB) RoleSQL {
+	return RoleSQL{db: db}
+}
@@ -0,0 +1,109 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+func TestRoleSQL(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			roleRepo := sqldb.NewRoleSQL(sqlDB)
+			userRoleRepo := sqldb.NewUserRoleSQL(sqlDB)
+			roleAssignmentRepo := sqldb.NewRoleAssignmentSQL(sqlDB)
+
+			definitions, err := roleRepo.GetCustomRoles()
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Definition{}, definitions)
+
+			err = roleRepo.CreateRole(role.Definition{Name: role.Admin})
+			assert.NotEqual(t, nil, err)
+
+			editor := role.Definition{
+				Name: "editor",
+				Permissions: []permission.Permission{
+					permission.EditChange,
+					permission.ViewChange,
+				},
+			}
+			err = roleRepo.CreateRole(editor)
+			assert.Equal(t, nil, err)
+			err = roleRepo.CreateRole(editor)
+			assert.NotEqual(t, nil, err)
+
+			auditor := role.Definition{
+				Name:        "auditor",
+				Permissions: []permission.Permission{},
+			}
+			err = roleRepo.CreateRole(auditor)
+			assert.Equal(t, nil, err)
+
+			definitions, err = roleRepo.GetCustomRoles()
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Definition{auditor, editor}, definitions)
+
+			err = roleRepo.UpdateRole(role.Definition{
+				Name:        role.Admin,
+				Permissions: []permission.Permission{},
+			})
+			assert.NotEqual(t, nil, err)
+
+			auditor.Permissions = []permission.Permission{permission.ViewUser}
+			err = roleRepo.UpdateRole(auditor)
+			assert.Equal(t, nil, err)
+
+			definitions, err = roleRepo.GetCustomRoles()
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Definition{auditor, editor}, definitions)
+
+			insertUserTableRows(t, sqlDB, []userTableRow{
+				{id: "alpha", email: "alpha@example.com"},
+			})
+			insertUserRoleRow(t, sqlDB, []userRoleTableRow{
+				{"alpha", role.Premium},
+				{"alpha", "editor"},
+			})
+
+			deletedAt := must.Time(t, "2020-07-21T10:00:00Z")
+			err = roleRepo.DeleteRole(role.Admin, "beta", deletedAt)
+			assert.NotEqual(t, nil, err)
+			err = roleRepo.DeleteRole("editor", "beta", deletedAt)
+			assert.Equal(t, nil, err)
+			err = roleRepo.DeleteRole("editor", "beta", deletedAt)
+			assert.NotEqual(t, nil, err)
+
+			definitions, err = roleRepo.GetCustomRoles()
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Definition{auditor}, definitions)
+
+			roles, err := userRoleRepo.GetRoles(entity.User{ID: "alpha"})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Role{role.Premium}, roles)
+
+			changes, err := roleAssignmentRepo.GetRoleChanges("alpha")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{
+				{
+					ActorID:   "beta",
+					UserID:    "alpha",
+					Role:      "editor",
+					Action:    entity.RoleRevoked,
+					CreatedAt: deletedAt,
+				},
+			}, changes)
+		})
+}
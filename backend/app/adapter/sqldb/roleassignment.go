@@ -0,0 +1,217 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.RoleAssignment = (*RoleAssignmentSQL)(nil)
+
+// RoleAssignmentSQL grants and revokes roles in user_role table and records
+// the changes in role_change table within a single transaction.
+type RoleAssignmentSQL struct {
+	db *sql.DB
+}
+
+// GrantRole adds the role to the user and records the change.
+func (r RoleAssignmentSQL) GrantRole(change entity.RoleChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = grantRole(tx, change)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeRole removes the role from the user and records the change. The rows
+// of the role are locked while checking for other holders so that concurrent
+// revocations can't remove all of them.
+func (r RoleAssignmentSQL) RevokeRole(change entity.RoleChange, keepLastHolder bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = revokeRole(tx, change, keepLastHolder)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetRoleChanges fetches the role changes of the given user from role_change
+// table, with the latest first.
+func (r RoleAssignmentSQL) GetRoleChanges(userID string) ([]entity.RoleChange, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s", "%s", "%s", "%s"
+FROM "%s"
+WHERE "%s"=$1
+ORDER BY "%s" DESC;
+`,
+		table.RoleChange.ColumnActorID,
+		table.RoleChange.ColumnUserID,
+		table.RoleChange.ColumnRole,
+		table.RoleChange.ColumnAction,
+		table.RoleChange.ColumnCreatedAt,
+		table.RoleChange.TableName,
+		table.RoleChange.ColumnUserID,
+		table.RoleChange.ColumnID,
+	)
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roleChanges := []entity.RoleChange{}
+	for rows.Next() {
+		roleChange := entity.RoleChange{}
+		err = rows.Scan(
+			&roleChange.ActorID,
+			&roleChange.UserID,
+			&roleChange.Role,
+			&roleChange.Action,
+			&roleChange.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		roleChange.CreatedAt = roleChange.CreatedAt.UTC()
+		roleChanges = append(roleChanges, roleChange)
+	}
+	return roleChanges, rows.Err()
+}
+
+// grantRole adds the role to the user and records the change within the
+// given transaction.
+func grantRole(tx *sql.Tx, change entity.RoleChange) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s")
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+`,
+		table.UserRole.TableName,
+		table.UserRole.ColumnUserID,
+		table.UserRole.ColumnRole,
+	)
+	res, err := tx.Exec(statement, change.UserID, change.Role)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryExists(
+			fmt.Sprintf("role(%s) of user(%s)", change.Role, change.UserID))
+	}
+	return createRoleChange(tx, change)
+}
+
+// revokeRole removes the role from the user and records the change within the
+// given transaction.
+func revokeRole(tx *sql.Tx, change entity.RoleChange, keepLastHolder bool) error {
+	if keepLastHolder {
+		query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1
+FOR UPDATE;
+`,
+			table.UserRole.ColumnUserID,
+			table.UserRole.TableName,
+			table.UserRole.ColumnRole,
+		)
+		rows, err := tx.Query(query, change.Role)
+		if err != nil {
+			return err
+		}
+		holders := 0
+		isHolder := false
+		for rows.Next() {
+			var userID string
+			err = rows.Scan(&userID)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			holders++
+			isHolder = isHolder || userID == change.UserID
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if !isHolder {
+			return repository.ErrEntryNotFound(
+				fmt.Sprintf("role(%s) of user(%s) not found", change.Role, change.UserID))
+		}
+		if holders < 2 {
+			return repository.ErrLastEntry(
+				fmt.Sprintf("user(%s) is the last one with role(%s)", change.UserID, change.Role))
+		}
+	}
+
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.UserRole.TableName,
+		table.UserRole.ColumnUserID,
+		table.UserRole.ColumnRole,
+	)
+	res, err := tx.Exec(statement, change.UserID, change.Role)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("role(%s) of user(%s) not found", change.Role, change.UserID))
+	}
+	return createRoleChange(tx, change)
+}
+
+func createRoleChange(tx *sql.Tx, change entity.RoleChange) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4, $5);
+`,
+		table.RoleChange.TableName,
+		table.RoleChange.ColumnActorID,
+		table.RoleChange.ColumnUserID,
+		table.RoleChange.ColumnRole,
+		table.RoleChange.ColumnAction,
+		table.RoleChange.ColumnCreatedAt,
+	)
+	_, err := tx.Exec(
+		statement,
+		change.ActorID,
+		change.UserID,
+		change.Role,
+		change.Action,
+		change.CreatedAt,
+	)
+	return err
+}
+
+// NewRoleAssignmentSQL creates RoleAssignmentSQL.
+func NewRoleAssignmentSQL(db *sql.DB) RoleAssignmentSQL {
+	return RoleAssignmentSQL{db: db}
+}
@@ -0,0 +1,88 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestRoleAssignmentSQL(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{
+				{id: "alpha", email: "alpha@example.com"},
+				{id: "beta", email: "beta@example.com"},
+			})
+			insertUserRoleRow(t, sqlDB, []userRoleTableRow{{"alpha", role.Admin}})
+			roleAssignmentRepo := sqldb.NewRoleAssignmentSQL(sqlDB)
+			userRoleRepo := sqldb.NewUserRoleSQL(sqlDB)
+
+			grantedAt := must.Time(t, "2020-05-01T08:00:00Z")
+			grant := entity.RoleChange{
+				ActorID:   "alpha",
+				UserID:    "beta",
+				Role:      string(role.Admin),
+				Action:    entity.RoleGranted,
+				CreatedAt: grantedAt,
+			}
+			err := roleAssignmentRepo.GrantRole(grant)
+			assert.Equal(t, nil, err)
+			err = roleAssignmentRepo.GrantRole(grant)
+			assert.NotEqual(t, nil, err)
+
+			revokedAt := must.Time(t, "2020-05-01T09:00:00Z")
+			revoke := entity.RoleChange{
+				ActorID:   "beta",
+				UserID:    "alpha",
+				Role:      string(role.Admin),
+				Action:    entity.RoleRevoked,
+				CreatedAt: revokedAt,
+			}
+			err = roleAssignmentRepo.RevokeRole(revoke, true)
+			assert.Equal(t, nil, err)
+			err = roleAssignmentRepo.RevokeRole(revoke, false)
+			assert.NotEqual(t, nil, err)
+
+			// Only beta is admin now, but alpha doesn't have the role to
+			// begin with.
+			err = roleAssignmentRepo.RevokeRole(revoke, true)
+			var notFound repository.ErrEntryNotFound
+			assert.Equal(t, true, errors.As(err, &notFound))
+
+			lastRevoke := entity.RoleChange{
+				ActorID:   "beta",
+				UserID:    "beta",
+				Role:      string(role.Admin),
+				Action:    entity.RoleRevoked,
+				CreatedAt: revokedAt,
+			}
+			err = roleAssignmentRepo.RevokeRole(lastRevoke, true)
+			assert.NotEqual(t, nil, err)
+
+			roles, err := userRoleRepo.GetRoles(entity.User{ID: "beta"})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []role.Role{role.Admin}, roles)
+
+			roleChanges, err := roleAssignmentRepo.GetRoleChanges("beta")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{grant}, roleChanges)
+
+			roleChanges, err = roleAssignmentRepo.GetRoleChanges("alpha")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{revoke}, roleChanges)
+		})
+}
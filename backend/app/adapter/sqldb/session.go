@@ -0,0 +1,213 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.Session = (*SessionSQL)(nil)
+
+// SessionSQL accesses Session from the database through SQL.
+type SessionSQL struct {
+	db *sql.DB
+}
+
+// GetSessionByID fetches a Session from Session table using SQL.
+func (s SessionSQL) GetSessionByID(id string) (entity.Session, error) {
+	query := fmt.Sprintf(`
+SELECT %s
+FROM "%s" WHERE "%s"=$1;
+`,
+		sessionColumns,
+		table.Session.TableName,
+		table.Session.ColumnID,
+	)
+	session, err := scanSession(s.db.QueryRow(query, id))
+	if err == nil {
+		return session, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Session{}, repository.ErrEntryNotFound(fmt.Sprintf("session(%s) not found", id))
+	}
+	return entity.Session{}, err
+}
+
+// GetSessionsByUser fetches all the Sessions of the given user from Session
+// table using SQL, with the latest first.
+func (s SessionSQL) GetSessionsByUser(userID string) ([]entity.Session, error) {
+	query := fmt.Sprintf(`
+SELECT %s
+FROM "%s" WHERE "%s"=$1
+ORDER BY "%s" DESC;
+`,
+		sessionColumns,
+		table.Session.TableName,
+		table.Session.ColumnUserID,
+		table.Session.ColumnCreatedAt,
+	)
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []entity.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// CreateSession appends a new Session entry to Session table using SQL.
+func (s SessionSQL) CreateSession(session entity.Session) error {
+	stmt := fmt.Sprintf(`
+INSERT INTO "%s"("%s", "%s", "%s", "%s", "%s", "%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+`,
+		table.Session.TableName,
+		table.Session.ColumnID,
+		table.Session.ColumnUserID,
+		table.Session.ColumnRefreshTokenGeneration,
+		table.Session.ColumnIdentityProvider,
+		table.Session.ColumnClientIP,
+		table.Session.ColumnLocation,
+		table.Session.ColumnUserAgent,
+		table.Session.ColumnCreatedAt,
+		table.Session.ColumnExpireAt,
+	)
+	_, err := s.db.Exec(
+		stmt,
+		session.ID,
+		session.UserID,
+		session.RefreshTokenGeneration,
+		session.IdentityProvider,
+		session.Device.ClientIP,
+		session.Device.Location,
+		session.Device.UserAgent,
+		session.CreatedAt,
+		session.ExpireAt,
+	)
+	return err
+}
+
+// RotateRefreshToken advances the refresh token of an active Session to the
+// next generation using SQL. It fails if the Session is no longer at the given
+// generation, so that a refresh token can only be used once.
+func (s SessionSQL) RotateRefreshToken(id string, generation int, expireAt time.Time) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1, "%s"=$2
+WHERE "%s"=$3 AND "%s"=$4 AND "%s" IS NULL;
+`,
+		table.Session.TableName,
+		table.Session.ColumnRefreshTokenGeneration,
+		table.Session.ColumnExpireAt,
+		table.Session.ColumnID,
+		table.Session.ColumnRefreshTokenGeneration,
+		table.Session.ColumnRevokedAt,
+	)
+	result, err := s.db.Exec(stmt, generation+1, expireAt, id, generation)
+	if err != nil {
+		return err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRowCount == 0 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("session(%s) at generation %d not found", id, generation))
+	}
+	return nil
+}
+
+// RevokeSession marks a Session as revoked in Session table using SQL.
+func (s SessionSQL) RevokeSession(id string, revokedAt time.Time) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s" IS NULL;
+`,
+		table.Session.TableName,
+		table.Session.ColumnRevokedAt,
+		table.Session.ColumnID,
+		table.Session.ColumnRevokedAt,
+	)
+	_, err := s.db.Exec(stmt, revokedAt, id)
+	return err
+}
+
+// RevokeSessionsByUser marks all the Sessions of the given user as revoked in
+// Session table using SQL.
+func (s SessionSQL) RevokeSessionsByUser(userID string, revokedAt time.Time) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s" IS NULL;
+`,
+		table.Session.TableName,
+		table.Session.ColumnRevokedAt,
+		table.Session.ColumnUserID,
+		table.Session.ColumnRevokedAt,
+	)
+	_, err := s.db.Exec(stmt, revokedAt, userID)
+	return err
+}
+
+var sessionColumns = fmt.Sprintf(
+	`"%s", "%s", "%s", "%s", "%s", "%s", "%s", "%s", "%s", "%s"`,
+	table.Session.ColumnID,
+	table.Session.ColumnUserID,
+	table.Session.ColumnRefreshTokenGeneration,
+	table.Session.ColumnIdentityProvider,
+	table.Session.ColumnClientIP,
+	table.Session.ColumnLocation,
+	table.Session.ColumnUserAgent,
+	table.Session.ColumnCreatedAt,
+	table.Session.ColumnExpireAt,
+	table.Session.ColumnRevokedAt,
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSession reads a Session from a row selected with sessionColumns.
+func scanSession(row scanner) (entity.Session, error) {
+	session := entity.Session{}
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenGeneration,
+		&session.IdentityProvider,
+		&session.Device.ClientIP,
+		&session.Device.Location,
+		&session.Device.UserAgent,
+		&session.CreatedAt,
+		&session.ExpireAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return entity.Session{}, err
+	}
+	session.CreatedAt = session.CreatedAt.UTC()
+	session.ExpireAt = session.ExpireAt.UTC()
+	session.RevokedAt = utc(session.RevokedAt)
+	return session, nil
+}
+
+// NewSessionSQL creates database access object for Session.
+func NewSessionSQL(db *sql.DB) SessionSQL {
+	return SessionSQL{db: db}
+}
@@ -0,0 +1,170 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
+)
+
+func TestSessionSQL_GetSessionByID(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}})
+
+			sessionRepo := sqldb.NewSessionSQL(sqlDB)
+			_, err := sessionRepo.GetSessionByID("unknown")
+			assert.NotEqual(t, nil, err)
+
+			session := entity.Session{
+				ID:               "session1",
+				UserID:           "alpha",
+				IdentityProvider: "github",
+				Device: entity.Device{
+					ClientIP:  "10.0.0.1",
+					Location:  "San Francisco, California, United States",
+					UserAgent: "Mozilla/5.0",
+				},
+				CreatedAt: must.Time(t, "2020-05-01T08:02:16Z"),
+				ExpireAt:  must.Time(t, "2020-05-31T08:02:16Z"),
+			}
+			err = sessionRepo.CreateSession(session)
+			assert.Equal(t, nil, err)
+
+			gotSession, err := sessionRepo.GetSessionByID("session1")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, session, gotSession)
+		})
+}
+
+func TestSessionSQL_GetSessionsByUser(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}, {id: "beta"}})
+
+			sessionRepo := sqldb.NewSessionSQL(sqlDB)
+			sessions := []entity.Session{
+				{
+					ID:        "session1",
+					UserID:    "alpha",
+					CreatedAt: must.Time(t, "2020-05-01T08:02:16Z"),
+					ExpireAt:  must.Time(t, "2020-05-31T08:02:16Z"),
+				},
+				{
+					ID:        "session2",
+					UserID:    "beta",
+					CreatedAt: must.Time(t, "2020-05-02T08:02:16Z"),
+					ExpireAt:  must.Time(t, "2020-06-01T08:02:16Z"),
+				},
+				{
+					ID:        "session3",
+					UserID:    "alpha",
+					CreatedAt: must.Time(t, "2020-05-03T08:02:16Z"),
+					ExpireAt:  must.Time(t, "2020-06-02T08:02:16Z"),
+				},
+			}
+			for _, session := range sessions {
+				err := sessionRepo.CreateSession(session)
+				assert.Equal(t, nil, err)
+			}
+
+			gotSessions, err := sessionRepo.GetSessionsByUser("alpha")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.Session{sessions[2], sessions[0]}, gotSessions)
+		})
+}
+
+func TestSessionSQL_RotateRefreshToken(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}})
+
+			sessionRepo := sqldb.NewSessionSQL(sqlDB)
+			err := sessionRepo.CreateSession(entity.Session{
+				ID:        "session1",
+				UserID:    "alpha",
+				CreatedAt: must.Time(t, "2020-05-01T08:02:16Z"),
+				ExpireAt:  must.Time(t, "2020-05-31T08:02:16Z"),
+			})
+			assert.Equal(t, nil, err)
+
+			expireAt := must.Time(t, "2020-06-01T08:02:16Z")
+			err = sessionRepo.RotateRefreshToken("session1", 0, expireAt)
+			assert.Equal(t, nil, err)
+
+			err = sessionRepo.RotateRefreshToken("session1", 0, expireAt)
+			assert.NotEqual(t, nil, err)
+
+			gotSession, err := sessionRepo.GetSessionByID("session1")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, 1, gotSession.RefreshTokenGeneration)
+			assert.Equal(t, expireAt, gotSession.ExpireAt)
+		})
+}
+
+func TestSessionSQL_RevokeSession(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}, {id: "beta"}})
+
+			sessionRepo := sqldb.NewSessionSQL(sqlDB)
+			sessions := []entity.Session{
+				{ID: "session1", UserID: "alpha"},
+				{ID: "session2", UserID: "alpha"},
+				{ID: "session3", UserID: "alpha"},
+				{ID: "session4", UserID: "beta"},
+			}
+			for _, session := range sessions {
+				session.CreatedAt = must.Time(t, "2020-05-01T08:02:16Z")
+				session.ExpireAt = must.Time(t, "2020-05-31T08:02:16Z")
+				err := sessionRepo.CreateSession(session)
+				assert.Equal(t, nil, err)
+			}
+
+			firstRevokedAt := must.Time(t, "2020-05-02T08:02:16Z")
+			err := sessionRepo.RevokeSession("session1", firstRevokedAt)
+			assert.Equal(t, nil, err)
+
+			err = sessionRepo.RevokeSessionsByUser("alpha", must.Time(t, "2020-05-03T08:02:16Z"))
+			assert.Equal(t, nil, err)
+
+			expectedRevokedAt := map[string]*time.Time{
+				"session1": ptr.Time(firstRevokedAt),
+				"session2": ptr.Time(must.Time(t, "2020-05-03T08:02:16Z")),
+				"session3": ptr.Time(must.Time(t, "2020-05-03T08:02:16Z")),
+				"session4": nil,
+			}
+			for id, revokedAt := range expectedRevokedAt {
+				gotSession, err := sessionRepo.GetSessionByID(id)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, revokedAt, gotSession.RevokedAt)
+			}
+
+			err = sessionRepo.RotateRefreshToken("session2", 0, must.Time(t, "2020-06-01T08:02:16Z"))
+			assert.NotEqual(t, nil, err)
+		})
+}
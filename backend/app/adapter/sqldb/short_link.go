@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/short-d/short/backend/app/adapter/sqldb/table"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
@@ -15,19 +17,22 @@ var _ repository.ShortLink = (*ShortLinkSQL)(nil)
 
 // ShortLinkSQL accesses ShortLink information in short_link table through SQL.
 type ShortLinkSQL struct {
-	db *sql.DB
+	db              *sql.DB
+	aliasNormalizer normalizer.Alias
 }
 
-// UpdateOpenGraphTags updates OpenGraph meta tags for a given short link.
+// UpdateOpenGraphTags updates OpenGraph meta tags for a given short link and
+// marks them as customized so that they are no longer scraped.
 func (s ShortLinkSQL) UpdateOpenGraphTags(alias string, openGraphTags metatag.OpenGraph) (entity.ShortLink, error) {
 	statement := fmt.Sprintf(`
 UPDATE "%s"
-SET "%s"=$1, "%s"=$2, "%s"=$3
+SET "%s"=$1, "%s"=$2, "%s"=$3, "%s"=TRUE
 WHERE "%s"=$4;`,
 		table.ShortLink.TableName,
 		table.ShortLink.ColumnOpenGraphTitle,
 		table.ShortLink.ColumnOpenGraphDescription,
 		table.ShortLink.ColumnOpenGraphImageURL,
+		table.ShortLink.ColumnHasCustomMetaTags,
 		table.ShortLink.ColumnAlias,
 	)
 
@@ -45,16 +50,18 @@ WHERE "%s"=$4;`,
 	return s.GetShortLinkByAlias(alias)
 }
 
-// UpdateTwitterTags updates Twitter meta tags for a given short link.
+// UpdateTwitterTags updates Twitter meta tags for a given short link and marks
+// them as customized so that they are no longer scraped.
 func (s ShortLinkSQL) UpdateTwitterTags(alias string, twitterTags metatag.Twitter) (entity.ShortLink, error) {
 	statement := fmt.Sprintf(`
 UPDATE "%s"
-SET "%s"=$1, "%s"=$2, "%s"=$3
+SET "%s"=$1, "%s"=$2, "%s"=$3, "%s"=TRUE
 WHERE "%s"=$4;`,
 		table.ShortLink.TableName,
 		table.ShortLink.ColumnTwitterTitle,
 		table.ShortLink.ColumnTwitterDescription,
 		table.ShortLink.ColumnTwitterImageURL,
+		table.ShortLink.ColumnHasCustomMetaTags,
 		table.ShortLink.ColumnAlias,
 	)
 
@@ -72,18 +79,59 @@ WHERE "%s"=$4;`,
 	return s.GetShortLinkByAlias(alias)
 }
 
-// IsAliasExist checks whether a given alias exist in short_link table.
+// UpdateScrapedMetaTags stores the meta tags scraped from the long link unless
+// the long link has changed since or the meta tags are customized by the user.
+func (s ShortLinkSQL) UpdateScrapedMetaTags(
+	alias string,
+	longLink string,
+	openGraphTags metatag.OpenGraph,
+	twitterTags metatag.Twitter,
+) error {
+	statement := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1, "%s"=$2, "%s"=$3, "%s"=$4, "%s"=$5, "%s"=$6
+WHERE "%s"=$7 AND "%s"=$8 AND "%s"=FALSE;`,
+		table.ShortLink.TableName,
+		table.ShortLink.ColumnOpenGraphTitle,
+		table.ShortLink.ColumnOpenGraphDescription,
+		table.ShortLink.ColumnOpenGraphImageURL,
+		table.ShortLink.ColumnTwitterTitle,
+		table.ShortLink.ColumnTwitterDescription,
+		table.ShortLink.ColumnTwitterImageURL,
+		table.ShortLink.ColumnAlias,
+		table.ShortLink.ColumnLongLink,
+		table.ShortLink.ColumnHasCustomMetaTags,
+	)
+
+	_, err := s.db.Exec(
+		statement,
+		openGraphTags.Title,
+		openGraphTags.Description,
+		openGraphTags.ImageURL,
+		twitterTags.Title,
+		twitterTags.Description,
+		twitterTags.ImageURL,
+		alias,
+		longLink,
+	)
+	return err
+}
+
+// IsAliasExist checks whether a given alias or its normalized form exist in
+// short_link table.
 func (s ShortLinkSQL) IsAliasExist(alias string) (bool, error) {
 	query := fmt.Sprintf(`
 SELECT "%s" 
 FROM "%s" 
-WHERE "%s"=$1;`,
+WHERE "%s"=$1 OR "%s"=$2;`,
 		table.ShortLink.ColumnAlias,
 		table.ShortLink.TableName,
 		table.ShortLink.ColumnAlias,
+		table.ShortLink.ColumnAlias,
 	)
 
-	err := s.db.QueryRow(query, alias).Scan(&alias)
+	normalizedAlias := s.aliasNormalizer.Normalize(alias)
+	err := s.db.QueryRow(query, alias, normalizedAlias).Scan(&alias)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
@@ -95,7 +143,18 @@ WHERE "%s"=$1;`,
 
 // CreateShortLink inserts a new ShortLink into short_link table.
 func (s ShortLinkSQL) CreateShortLink(shortLinkInput entity.ShortLinkInput) error {
-	statement := fmt.Sprintf(`
+	_, err := s.db.Exec(
+		createShortLinkStatement(),
+		shortLinkInput.GetCustomAlias(""),
+		shortLinkInput.GetLongLink(""),
+		shortLinkInput.ExpireAt,
+		shortLinkInput.CreatedAt,
+	)
+	return err
+}
+
+func createShortLinkStatement() string {
+	return fmt.Sprintf(`
 INSERT INTO "%s" ("%s","%s","%s","%s")
 VALUES ($1, $2, $3, $4);`,
 		table.ShortLink.TableName,
@@ -104,14 +163,6 @@ VALUES ($1, $2, $3, $4);`,
 		table.ShortLink.ColumnExpireAt,
 		table.ShortLink.ColumnCreatedAt,
 	)
-	_, err := s.db.Exec(
-		statement,
-		shortLinkInput.GetCustomAlias(""),
-		shortLinkInput.GetLongLink(""),
-		shortLinkInput.ExpireAt,
-		shortLinkInput.CreatedAt,
-	)
-	return err
 }
 
 // UpdateShortLink updates a ShortLink that exists within the short_link table.
@@ -150,11 +201,15 @@ WHERE "%s"=$5;`,
 }
 
 // GetShortLinkByAlias finds an ShortLink in short_link table given alias.
+// Aliases created before the normalization policy is enforced may not be
+// normalized yet, so the exact match is preferred over the normalized one.
 func (s ShortLinkSQL) GetShortLinkByAlias(alias string) (entity.ShortLink, error) {
 	statement := fmt.Sprintf(`
-SELECT "%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s"
+SELECT "%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s"
 FROM "%s" 
-WHERE "%s"=$1;`,
+WHERE "%s"=$1 OR "%s"=$2
+ORDER BY "%s"=$1 DESC
+LIMIT 1;`,
 		table.ShortLink.ColumnAlias,
 		table.ShortLink.ColumnLongLink,
 		table.ShortLink.ColumnExpireAt,
@@ -166,11 +221,16 @@ WHERE "%s"=$1;`,
 		table.ShortLink.ColumnTwitterTitle,
 		table.ShortLink.ColumnTwitterDescription,
 		table.ShortLink.ColumnTwitterImageURL,
+		table.ShortLink.ColumnHealthStatusCode,
+		table.ShortLink.ColumnHealthCheckedAt,
 		table.ShortLink.TableName,
 		table.ShortLink.ColumnAlias,
+		table.ShortLink.ColumnAlias,
+		table.ShortLink.ColumnAlias,
 	)
 
-	row := s.db.QueryRow(statement, alias)
+	normalizedAlias := s.aliasNormalizer.Normalize(alias)
+	row := s.db.QueryRow(statement, alias, normalizedAlias)
 
 	shortLink := entity.ShortLink{}
 	err := row.Scan(
@@ -185,7 +245,12 @@ WHERE "%s"=$1;`,
 		&shortLink.TwitterTags.Title,
 		&shortLink.TwitterTags.Description,
 		&shortLink.TwitterTags.ImageURL,
+		&shortLink.Health.StatusCode,
+		&shortLink.Health.CheckedAt,
 	)
+	if err == sql.ErrNoRows {
+		return entity.ShortLink{}, repository.ErrAliasNotFound{Alias: alias}
+	}
 	if err != nil {
 		return entity.ShortLink{}, err
 	}
@@ -193,6 +258,7 @@ WHERE "%s"=$1;`,
 	shortLink.CreatedAt = utc(shortLink.CreatedAt)
 	shortLink.UpdatedAt = utc(shortLink.UpdatedAt)
 	shortLink.ExpireAt = utc(shortLink.ExpireAt)
+	shortLink.Health.CheckedAt = utc(shortLink.Health.CheckedAt)
 
 	return shortLink, nil
 }
@@ -215,7 +281,7 @@ func (s ShortLinkSQL) GetShortLinksByAliases(aliases []string) ([]entity.ShortLi
 
 	// TODO: compare performance between Query and QueryRow. Prefer QueryRow for readability
 	statement := fmt.Sprintf(`
-SELECT "%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s" 
+SELECT "%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s","%s" 
 FROM "%s"
 WHERE "%s" IN (%s);`,
 		table.ShortLink.ColumnAlias,
@@ -229,6 +295,8 @@ WHERE "%s" IN (%s);`,
 		table.ShortLink.ColumnTwitterTitle,
 		table.ShortLink.ColumnTwitterDescription,
 		table.ShortLink.ColumnTwitterImageURL,
+		table.ShortLink.ColumnHealthStatusCode,
+		table.ShortLink.ColumnHealthCheckedAt,
 		table.ShortLink.TableName,
 		table.ShortLink.ColumnAlias,
 		parameterStr,
@@ -260,6 +328,8 @@ WHERE "%s" IN (%s);`,
 			&shortLink.TwitterTags.Title,
 			&shortLink.TwitterTags.Description,
 			&shortLink.TwitterTags.ImageURL,
+			&shortLink.Health.StatusCode,
+			&shortLink.Health.CheckedAt,
 		)
 		if err != nil {
 			return shortLinks, err
@@ -268,6 +338,7 @@ WHERE "%s" IN (%s);`,
 		shortLink.CreatedAt = utc(shortLink.CreatedAt)
 		shortLink.UpdatedAt = utc(shortLink.UpdatedAt)
 		shortLink.ExpireAt = utc(shortLink.ExpireAt)
+		shortLink.Health.CheckedAt = utc(shortLink.Health.CheckedAt)
 
 		shortLinks = append(shortLinks, shortLink)
 	}
@@ -275,6 +346,80 @@ WHERE "%s" IN (%s);`,
 	return shortLinks, nil
 }
 
+// GetShortLinksCheckedBefore finds at most limit ShortLinks in short_link
+// table which have never been checked or were last checked before the given
+// time, least recently checked first.
+func (s ShortLinkSQL) GetShortLinksCheckedBefore(checkedBefore time.Time, limit int) ([]entity.ShortLink, error) {
+	statement := fmt.Sprintf(`
+SELECT "%s","%s","%s","%s"
+FROM "%s"
+WHERE "%s" IS NULL OR "%s"<$1
+ORDER BY "%s" ASC NULLS FIRST, "%s" ASC
+LIMIT $2;`,
+		table.ShortLink.ColumnAlias,
+		table.ShortLink.ColumnLongLink,
+		table.ShortLink.ColumnHealthStatusCode,
+		table.ShortLink.ColumnHealthCheckedAt,
+		table.ShortLink.TableName,
+		table.ShortLink.ColumnHealthCheckedAt,
+		table.ShortLink.ColumnHealthCheckedAt,
+		table.ShortLink.ColumnHealthCheckedAt,
+		table.ShortLink.ColumnAlias,
+	)
+
+	rows, err := s.db.Query(statement, checkedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shortLinks []entity.ShortLink
+	for rows.Next() {
+		shortLink := entity.ShortLink{}
+		err := rows.Scan(
+			&shortLink.Alias,
+			&shortLink.LongLink,
+			&shortLink.Health.StatusCode,
+			&shortLink.Health.CheckedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		shortLink.Health.CheckedAt = utc(shortLink.Health.CheckedAt)
+		shortLinks = append(shortLinks, shortLink)
+	}
+	return shortLinks, rows.Err()
+}
+
+// UpdateHealth records the result of the latest reachability check of a
+// ShortLink in short_link table.
+func (s ShortLinkSQL) UpdateHealth(alias string, health entity.LinkHealth) error {
+	statement := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1, "%s"=$2
+WHERE "%s"=$3;`,
+		table.ShortLink.TableName,
+		table.ShortLink.ColumnHealthStatusCode,
+		table.ShortLink.ColumnHealthCheckedAt,
+		table.ShortLink.ColumnAlias,
+	)
+
+	result, err := s.db.Exec(statement, health.StatusCode, health.CheckedAt, alias)
+	if err != nil {
+		return err
+	}
+
+	affectedRowCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affectedRowCount == 0 {
+		return repository.ErrAliasNotFound{Alias: alias}
+	}
+	return nil
+}
+
 // composeParamList converts an slice to a parameters string with format: $1, $2, $3, ...
 func (s ShortLinkSQL) composeParamList(numParams int) string {
 	params := make([]string, 0, numParams)
@@ -313,8 +458,9 @@ func (s ShortLinkSQL) DeleteShortLink(alias string) error {
 }
 
 // NewShortLinkSQL creates ShortLinkSQL
-func NewShortLinkSQL(db *sql.DB) ShortLinkSQL {
+func NewShortLinkSQL(db *sql.DB, aliasNormalizer normalizer.Alias) ShortLinkSQL {
 	return ShortLinkSQL{
-		db: db,
+		db:              db,
+		aliasNormalizer: aliasNormalizer,
 	}
 }
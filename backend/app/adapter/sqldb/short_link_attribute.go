@@ -0,0 +1,99 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.ShortLinkAttribute = (*ShortLinkAttributeSQL)(nil)
+
+// ShortLinkAttributeSQL accesses the attributes of short links in
+// short_link_attribute table.
+type ShortLinkAttributeSQL struct {
+	db *sql.DB
+}
+
+// GetAttributes fetches every value of each attribute of the short link.
+func (s ShortLinkAttributeSQL) GetAttributes(alias string) (map[string][]string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s"
+FROM "%s"
+WHERE "%s"=$1
+ORDER BY "%s", "%s";
+`,
+		table.ShortLinkAttribute.ColumnName,
+		table.ShortLinkAttribute.ColumnValue,
+		table.ShortLinkAttribute.TableName,
+		table.ShortLinkAttribute.ColumnShortLinkAlias,
+		table.ShortLinkAttribute.ColumnName,
+		table.ShortLinkAttribute.ColumnValue,
+	)
+
+	rows, err := s.db.Query(query, alias)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := map[string][]string{}
+	for rows.Next() {
+		var name, value string
+		err = rows.Scan(&name, &value)
+		if err != nil {
+			return nil, err
+		}
+		attributes[name] = append(attributes[name], value)
+	}
+	return attributes, rows.Err()
+}
+
+// AddAttribute adds the value to the attribute of the short link. Adding a
+// value the attribute already has makes no change.
+func (s ShortLinkAttributeSQL) AddAttribute(alias string, name string, value string) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s")
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+`,
+		table.ShortLinkAttribute.TableName,
+		table.ShortLinkAttribute.ColumnShortLinkAlias,
+		table.ShortLinkAttribute.ColumnName,
+		table.ShortLinkAttribute.ColumnValue,
+	)
+	_, err := s.db.Exec(statement, alias, name, value)
+	return err
+}
+
+// RemoveAttribute removes the value from the attribute of the short link.
+func (s ShortLinkAttributeSQL) RemoveAttribute(alias string, name string, value string) error {
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2 AND "%s"=$3;
+`,
+		table.ShortLinkAttribute.TableName,
+		table.ShortLinkAttribute.ColumnShortLinkAlias,
+		table.ShortLinkAttribute.ColumnName,
+		table.ShortLinkAttribute.ColumnValue,
+	)
+	res, err := s.db.Exec(statement, alias, name, value)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("attribute(%s=%s) of short link(%s) not found", name, value, alias))
+	}
+	return nil
+}
+
+// NewShortLinkAttributeSQL creates ShortLinkAttributeSQL.
+func NewShortLinkAttributeSQL(db *sql.DB) ShortLinkAttributeSQL {
+	return ShortLinkAttributeSQL{db: db}
+}
@@ -0,0 +1,129 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var insertShortLinkAttributeRowSQL = fmt.Sprintf(`
+INSERT INTO %s (%s, %s, %s)
+VALUES ($1, $2, $3)`,
+	table.ShortLinkAttribute.TableName,
+	table.ShortLinkAttribute.ColumnShortLinkAlias,
+	table.ShortLinkAttribute.ColumnName,
+	table.ShortLinkAttribute.ColumnValue,
+)
+
+func TestShortLinkAttributeSQL_GetAttributes(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "docs", longLink: "https://example.com/docs"},
+				{alias: "blog", longLink: "https://example.com/blog"},
+			})
+			for _, row := range [][]string{
+				{"docs", "team", "growth"},
+				{"docs", "tag", "public"},
+				{"docs", "tag", "api"},
+			} {
+				_, err := sqlDB.Exec(insertShortLinkAttributeRowSQL, row[0], row[1], row[2])
+				assert.Equal(t, nil, err)
+			}
+
+			attributeRepo := sqldb.NewShortLinkAttributeSQL(sqlDB)
+
+			attributes, err := attributeRepo.GetAttributes("docs")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, map[string][]string{
+				"team": {"growth"},
+				"tag":  {"api", "public"},
+			}, attributes)
+
+			attributes, err = attributeRepo.GetAttributes("blog")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, map[string][]string{}, attributes)
+		})
+}
+
+func TestShortLinkAttributeSQL_AddAttribute(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "docs", longLink: "https://example.com/docs"},
+			})
+			_, err := sqlDB.Exec(insertShortLinkAttributeRowSQL, "docs", "tag", "public")
+			assert.Equal(t, nil, err)
+
+			attributeRepo := sqldb.NewShortLinkAttributeSQL(sqlDB)
+
+			err = attributeRepo.AddAttribute("docs", "tag", "api")
+			assert.Equal(t, nil, err)
+			err = attributeRepo.AddAttribute("docs", "tag", "public")
+			assert.Equal(t, nil, err)
+			err = attributeRepo.AddAttribute("docs", "team", "growth")
+			assert.Equal(t, nil, err)
+
+			attributes, err := attributeRepo.GetAttributes("docs")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, map[string][]string{
+				"team": {"growth"},
+				"tag":  {"api", "public"},
+			}, attributes)
+
+			err = attributeRepo.AddAttribute("blog", "tag", "api")
+			assert.NotEqual(t, nil, err)
+		})
+}
+
+func TestShortLinkAttributeSQL_RemoveAttribute(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "docs", longLink: "https://example.com/docs"},
+			})
+			for _, row := range [][]string{
+				{"docs", "team", "growth"},
+				{"docs", "tag", "public"},
+			} {
+				_, err := sqlDB.Exec(insertShortLinkAttributeRowSQL, row[0], row[1], row[2])
+				assert.Equal(t, nil, err)
+			}
+
+			attributeRepo := sqldb.NewShortLinkAttributeSQL(sqlDB)
+
+			err := attributeRepo.RemoveAttribute("docs", "tag", "public")
+			assert.Equal(t, nil, err)
+
+			err = attributeRepo.RemoveAttribute("docs", "tag", "public")
+			var errNotFound repository.ErrEntryNotFound
+			assert.Equal(t, true, errors.As(err, &errNotFound))
+
+			attributes, err := attributeRepo.GetAttributes("docs")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, map[string][]string{
+				"team": {"growth"},
+			}, attributes)
+		})
+}
@@ -0,0 +1,199 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// shortLinkCreator describes the tables which record the short links created
+// by a user or an app.
+type shortLinkCreator struct {
+	id                string
+	lockStatement     string
+	countQuery        string
+	relationStatement string
+	creationStatement string
+}
+
+// CreateUserShortLink persists the short link together with its relationship
+// with the user and records its creation in user_short_link_creation table, all
+// in a single transaction. The row of the user is locked until the transaction
+// ends, so that concurrent creations by the same user are checked one by one.
+func (s ShortLinkSQL) CreateUserShortLink(
+	user entity.User,
+	shortLinkInput entity.ShortLinkInput,
+	quotaCheck repository.QuotaCheck,
+) error {
+	creator := shortLinkCreator{
+		id: user.ID,
+		lockStatement: lockRowStatement(
+			table.User.TableName,
+			table.User.ColumnID,
+		),
+		countQuery: countCreationsQuery(
+			table.UserShortLinkCreation.TableName,
+			table.UserShortLinkCreation.ColumnUserID,
+			table.UserShortLinkCreation.ColumnCreatedAt,
+		),
+		relationStatement: createRelationStatement(
+			table.UserShortLink.TableName,
+			table.UserShortLink.ColumnUserID,
+			table.UserShortLink.ColumnShortLinkAlias,
+		),
+		creationStatement: recordCreationStatement(
+			table.UserShortLinkCreation.TableName,
+			table.UserShortLinkCreation.ColumnUserID,
+			table.UserShortLinkCreation.ColumnShortLinkAlias,
+			table.UserShortLinkCreation.ColumnCreatedAt,
+		),
+	}
+	return s.createShortLinkWithinQuota(creator, shortLinkInput, quotaCheck)
+}
+
+// CreateAppShortLink persists the short link together with its relationship
+// with the app and records its creation in app_short_link_creation table, all
+// in a single transaction. The row of the app is locked until the transaction
+// ends, so that concurrent creations by the same app are checked one by one.
+func (s ShortLinkSQL) CreateAppShortLink(
+	app entity.App,
+	shortLinkInput entity.ShortLinkInput,
+	quotaCheck repository.QuotaCheck,
+) error {
+	creator := shortLinkCreator{
+		id: app.ID,
+		lockStatement: lockRowStatement(
+			table.App.TableName,
+			table.App.ColumnID,
+		),
+		countQuery: countCreationsQuery(
+			table.AppShortLinkCreation.TableName,
+			table.AppShortLinkCreation.ColumnAppID,
+			table.AppShortLinkCreation.ColumnCreatedAt,
+		),
+		relationStatement: createRelationStatement(
+			table.AppShortLink.TableName,
+			table.AppShortLink.ColumnAppID,
+			table.AppShortLink.ColumnShortLinkAlias,
+		),
+		creationStatement: recordCreationStatement(
+			table.AppShortLinkCreation.TableName,
+			table.AppShortLinkCreation.ColumnAppID,
+			table.AppShortLinkCreation.ColumnShortLinkAlias,
+			table.AppShortLinkCreation.ColumnCreatedAt,
+		),
+	}
+	return s.createShortLinkWithinQuota(creator, shortLinkInput, quotaCheck)
+}
+
+func (s ShortLinkSQL) createShortLinkWithinQuota(
+	creator shortLinkCreator,
+	shortLinkInput entity.ShortLinkInput,
+	quotaCheck repository.QuotaCheck,
+) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(creator.lockStatement, creator.id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var totalCreated, createdSince int
+	err = tx.QueryRow(creator.countQuery, creator.id, quotaCheck.Since.UTC()).
+		Scan(&totalCreated, &createdSince)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = quotaCheck.Check(totalCreated, createdSince)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	alias := shortLinkInput.GetCustomAlias("")
+	_, err = tx.Exec(
+		createShortLinkStatement(),
+		alias,
+		shortLinkInput.GetLongLink(""),
+		shortLinkInput.ExpireAt,
+		shortLinkInput.CreatedAt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(creator.relationStatement, creator.id, alias)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(creator.creationStatement, creator.id, alias, shortLinkInput.CreatedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func lockRowStatement(tableName string, idColumn string) string {
+	return fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1
+FOR UPDATE;
+`,
+		idColumn,
+		tableName,
+		idColumn,
+	)
+}
+
+func countCreationsQuery(tableName string, creatorColumn string, createdAtColumn string) string {
+	return fmt.Sprintf(`
+SELECT COUNT(*), COUNT(*) FILTER (WHERE "%s">=$2)
+FROM "%s"
+WHERE "%s"=$1;
+`,
+		createdAtColumn,
+		tableName,
+		creatorColumn,
+	)
+}
+
+func createRelationStatement(tableName string, creatorColumn string, aliasColumn string) string {
+	return fmt.Sprintf(`
+INSERT INTO "%s" ("%s","%s")
+VALUES ($1,$2);
+`,
+		tableName,
+		creatorColumn,
+		aliasColumn,
+	)
+}
+
+func recordCreationStatement(
+	tableName string,
+	creatorColumn string,
+	aliasColumn string,
+	createdAtColumn string,
+) string {
+	return fmt.Sprintf(`
+INSERT INTO "%s" ("%s","%s","%s")
+VALUES ($1,$2,$3);
+`,
+		tableName,
+		creatorColumn,
+		aliasColumn,
+		createdAtColumn,
+	)
+}
@@ -0,0 +1,257 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var insertUserShortLinkCreationRowSQL = fmt.Sprintf(`
+INSERT INTO %s (%s, %s, %s)
+VALUES ($1, $2, $3)`,
+	table.UserShortLinkCreation.TableName,
+	table.UserShortLinkCreation.ColumnUserID,
+	table.UserShortLinkCreation.ColumnShortLinkAlias,
+	table.UserShortLinkCreation.ColumnCreatedAt,
+)
+
+var insertAppShortLinkCreationRowSQL = fmt.Sprintf(`
+INSERT INTO %s (%s, %s, %s)
+VALUES ($1, $2, $3)`,
+	table.AppShortLinkCreation.TableName,
+	table.AppShortLinkCreation.ColumnAppID,
+	table.AppShortLinkCreation.ColumnShortLinkAlias,
+	table.AppShortLinkCreation.ColumnCreatedAt,
+)
+
+type userShortLinkCreationTableRow struct {
+	userID    string
+	alias     string
+	createdAt time.Time
+}
+
+type appShortLinkCreationTableRow struct {
+	appID     string
+	alias     string
+	createdAt time.Time
+}
+
+func TestShortLinkSQL_CreateUserShortLink(t *testing.T) {
+	errQuotaExceeded := errors.New("quota exceeded")
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+
+	testCases := []struct {
+		name                 string
+		creationTableRows    []userShortLinkCreationTableRow
+		expectedTotalCreated int
+		expectedCreatedSince int
+		quotaErr             error
+	}{
+		{
+			name:                 "first short link",
+			creationTableRows:    []userShortLinkCreationTableRow{},
+			expectedTotalCreated: 0,
+			expectedCreatedSince: 0,
+		},
+		{
+			name: "deleted short links still count",
+			creationTableRows: []userShortLinkCreationTableRow{
+				{userID: "alpha", alias: "deleted", createdAt: must.Time(t, "2020-04-30T08:00:00Z")},
+				{userID: "alpha", alias: "deleted", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+				{userID: "beta", alias: "other", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+			},
+			expectedTotalCreated: 2,
+			expectedCreatedSince: 1,
+		},
+		{
+			name: "quota exceeded",
+			creationTableRows: []userShortLinkCreationTableRow{
+				{userID: "alpha", alias: "deleted", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+			},
+			expectedTotalCreated: 1,
+			expectedCreatedSince: 1,
+			quotaErr:             errQuotaExceeded,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertUserTableRows(t, sqlDB, []userTableRow{
+						{id: "alpha", email: "alpha@example.com"},
+						{id: "beta", email: "beta@example.com"},
+					})
+					insertUserShortLinkCreationTableRows(t, sqlDB, testCase.creationTableRows)
+
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
+					userShortLinkRepo := sqldb.NewUserShortLinkSQL(sqlDB)
+					user := entity.User{ID: "alpha"}
+					quotaCheck := repository.QuotaCheck{
+						Since: must.Time(t, "2020-05-01T00:00:00Z"),
+						Check: func(totalCreated int, createdSince int) error {
+							assert.Equal(t, testCase.expectedTotalCreated, totalCreated)
+							assert.Equal(t, testCase.expectedCreatedSince, createdSince)
+							return testCase.quotaErr
+						},
+					}
+					shortLinkInput := entity.ShortLinkInput{
+						CustomAlias: ptr.String("new"),
+						LongLink:    ptr.String("https://www.google.com"),
+						CreatedAt:   &now,
+					}
+
+					err := shortLinkRepo.CreateUserShortLink(user, shortLinkInput, quotaCheck)
+					totalCreated, countErr := userShortLinkRepo.CountShortLinksByUser(user)
+					assert.Equal(t, nil, countErr)
+					hasMapping, mappingErr := userShortLinkRepo.HasMapping(user, "new")
+					assert.Equal(t, nil, mappingErr)
+					isExist, existErr := shortLinkRepo.IsAliasExist("new")
+					assert.Equal(t, nil, existErr)
+
+					if testCase.quotaErr != nil {
+						assert.Equal(t, testCase.quotaErr, err)
+						assert.Equal(t, testCase.expectedTotalCreated, totalCreated)
+						assert.Equal(t, false, hasMapping)
+						assert.Equal(t, false, isExist)
+						return
+					}
+
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedTotalCreated+1, totalCreated)
+					assert.Equal(t, true, hasMapping)
+					assert.Equal(t, true, isExist)
+				})
+		})
+	}
+}
+
+func TestShortLinkSQL_CreateAppShortLink(t *testing.T) {
+	errQuotaExceeded := errors.New("quota exceeded")
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+
+	testCases := []struct {
+		name                 string
+		creationTableRows    []appShortLinkCreationTableRow
+		expectedTotalCreated int
+		expectedCreatedSince int
+		quotaErr             error
+	}{
+		{
+			name: "within quota",
+			creationTableRows: []appShortLinkCreationTableRow{
+				{appID: "emotic", alias: "deleted", createdAt: must.Time(t, "2020-04-30T08:00:00Z")},
+			},
+			expectedTotalCreated: 1,
+			expectedCreatedSince: 0,
+		},
+		{
+			name: "quota exceeded",
+			creationTableRows: []appShortLinkCreationTableRow{
+				{appID: "emotic", alias: "deleted", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+			},
+			expectedTotalCreated: 1,
+			expectedCreatedSince: 1,
+			quotaErr:             errQuotaExceeded,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertAppRows(t, sqlDB, []appTableRow{
+						{id: "emotic", name: "Feedback Widget", createdAt: must.Time(t, "2017-05-01T08:02:16-07:00")},
+					})
+					insertAppShortLinkCreationTableRows(t, sqlDB, testCase.creationTableRows)
+
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
+					appShortLinkRepo := sqldb.NewAppShortLinkSQL(sqlDB)
+					app := entity.App{ID: "emotic"}
+					quotaCheck := repository.QuotaCheck{
+						Since: must.Time(t, "2020-05-01T00:00:00Z"),
+						Check: func(totalCreated int, createdSince int) error {
+							assert.Equal(t, testCase.expectedTotalCreated, totalCreated)
+							assert.Equal(t, testCase.expectedCreatedSince, createdSince)
+							return testCase.quotaErr
+						},
+					}
+					shortLinkInput := entity.ShortLinkInput{
+						CustomAlias: ptr.String("new"),
+						LongLink:    ptr.String("https://www.google.com"),
+						CreatedAt:   &now,
+					}
+
+					err := shortLinkRepo.CreateAppShortLink(app, shortLinkInput, quotaCheck)
+					totalCreated, countErr := appShortLinkRepo.CountShortLinksByApp(app)
+					assert.Equal(t, nil, countErr)
+					hasMapping, mappingErr := appShortLinkRepo.HasMapping(app, "new")
+					assert.Equal(t, nil, mappingErr)
+
+					if testCase.quotaErr != nil {
+						assert.Equal(t, testCase.quotaErr, err)
+						assert.Equal(t, testCase.expectedTotalCreated, totalCreated)
+						assert.Equal(t, false, hasMapping)
+						return
+					}
+
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedTotalCreated+1, totalCreated)
+					assert.Equal(t, true, hasMapping)
+				})
+		})
+	}
+}
+
+func insertUserShortLinkCreationTableRows(
+	t *testing.T,
+	sqlDB *sql.DB,
+	tableRows []userShortLinkCreationTableRow,
+) {
+	for _, tableRow := range tableRows {
+		_, err := sqlDB.Exec(
+			insertUserShortLinkCreationRowSQL,
+			tableRow.userID,
+			tableRow.alias,
+			tableRow.createdAt,
+		)
+		assert.Equal(t, nil, err)
+	}
+}
+
+func insertAppShortLinkCreationTableRows(
+	t *testing.T,
+	sqlDB *sql.DB,
+	tableRows []appShortLinkCreationTableRow,
+) {
+	for _, tableRow := range tableRows {
+		_, err := sqlDB.Exec(
+			insertAppShortLinkCreationRowSQL,
+			tableRow.appID,
+			tableRow.alias,
+			tableRow.createdAt,
+		)
+		assert.Equal(t, nil, err)
+	}
+}
@@ -16,11 +16,14 @@ import (
 	"github.com/short-d/short/backend/app/entity/metatag"
 	"github.com/short-d/short/backend/app/fw/must"
 	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 )
 
+var aliasNormalizer = normalizer.NewAlias(normalizer.AliasPolicy{})
+
 var insertShortLinkRowSQL = fmt.Sprintf(`
-INSERT INTO %s (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+INSERT INTO %s (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
 	table.ShortLink.TableName,
 	table.ShortLink.ColumnAlias,
 	table.ShortLink.ColumnLongLink,
@@ -33,6 +36,8 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
 	table.ShortLink.ColumnTwitterTitle,
 	table.ShortLink.ColumnTwitterDescription,
 	table.ShortLink.ColumnTwitterImageURL,
+	table.ShortLink.ColumnHealthStatusCode,
+	table.ShortLink.ColumnHealthCheckedAt,
 )
 
 type shortLinkTableRow struct {
@@ -47,6 +52,8 @@ type shortLinkTableRow struct {
 	twitterTitle       *string
 	twitterDescription *string
 	twitterImageURL    *string
+	healthStatusCode   *int
+	healthCheckedAt    *time.Time
 }
 
 func TestShortLinkSql_UpdateOGMetaTags(t *testing.T) {
@@ -128,7 +135,7 @@ func TestShortLinkSql_UpdateOGMetaTags(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 
 					shortLink, err := shortLinkRepo.UpdateOpenGraphTags(testCase.alias, testCase.metaTags)
 					assert.Equal(t, nil, err)
@@ -225,7 +232,7 @@ func TestShortLinkSql_UpdateTwitterTags(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 
 					shortLink, err := shortLinkRepo.UpdateTwitterTags(testCase.alias, testCase.metaTags)
 					assert.Equal(t, nil, err)
@@ -268,7 +275,7 @@ func TestShortLinkSql_IsAliasExist(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					gotIsExist, err := shortLinkRepo.IsAliasExist(testCase.alias)
 					assert.Equal(t, nil, err)
 					assert.Equal(t, testCase.expIsExist, gotIsExist)
@@ -403,7 +410,7 @@ func TestShortLinkSql_GetShortLinkByAlias(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					shortLink, err := shortLinkRepo.GetShortLinkByAlias(testCase.alias)
 
 					if testCase.hasErr {
@@ -483,7 +490,7 @@ func TestShortLinkSql_CreateShortLink(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					err := shortLinkRepo.CreateShortLink(testCase.shortLinkInput)
 
 					if testCase.hasErr {
@@ -580,7 +587,7 @@ func TestShortLinkSql_UpdateShortLink(t *testing.T) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 					expectedShortLink := testCase.expectedShortLink
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					shortLink, err := shortLinkRepo.UpdateShortLink(
 						testCase.oldAlias,
 						testCase.shortLinkInput,
@@ -699,7 +706,7 @@ func TestShortLinkSql_GetShortLinkByAliases(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					shortLink, err := shortLinkRepo.GetShortLinksByAliases(testCase.aliases)
 
 					if testCase.hasErr {
@@ -759,7 +766,7 @@ func TestShortLinkSql_DeleteShortLink(t *testing.T) {
 				func(sqlDB *sql.DB) {
 					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
 
-					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB)
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
 					err := shortLinkRepo.DeleteShortLink(testCase.alias)
 					if testCase.hasErr {
 						assert.NotEqual(t, nil, err)
@@ -772,6 +779,208 @@ func TestShortLinkSql_DeleteShortLink(t *testing.T) {
 	}
 }
 
+func TestShortLinkSql_GetShortLinksCheckedBefore(t *testing.T) {
+	testCases := []struct {
+		name               string
+		tableRows          []shortLinkTableRow
+		checkedBefore      time.Time
+		limit              int
+		expectedShortLinks []entity.ShortLink
+	}{
+		{
+			name:               "no short link",
+			tableRows:          []shortLinkTableRow{},
+			checkedBefore:      must.Time(t, "2020-05-01T08:00:00-07:00"),
+			limit:              10,
+			expectedShortLinks: nil,
+		},
+		{
+			name: "least recently checked first",
+			tableRows: []shortLinkTableRow{
+				{
+					alias:            "recent",
+					longLink:         "https://short-d.com/recent",
+					healthStatusCode: ptr.Int(200),
+					healthCheckedAt:  ptr.Time(must.Time(t, "2020-05-01T09:00:00-07:00")),
+				},
+				{
+					alias:            "stale",
+					longLink:         "https://short-d.com/stale",
+					healthStatusCode: ptr.Int(404),
+					healthCheckedAt:  ptr.Time(must.Time(t, "2020-04-01T08:00:00-07:00")),
+				},
+				{
+					alias:    "unchecked",
+					longLink: "https://short-d.com/unchecked",
+				},
+			},
+			checkedBefore: must.Time(t, "2020-05-01T08:00:00-07:00"),
+			limit:         10,
+			expectedShortLinks: []entity.ShortLink{
+				{
+					Alias:    "unchecked",
+					LongLink: "https://short-d.com/unchecked",
+				},
+				{
+					Alias:    "stale",
+					LongLink: "https://short-d.com/stale",
+					Health: entity.LinkHealth{
+						StatusCode: ptr.Int(404),
+						CheckedAt:  ptr.Time(must.Time(t, "2020-04-01T15:00:00Z")),
+					},
+				},
+			},
+		},
+		{
+			name: "limit number of short links",
+			tableRows: []shortLinkTableRow{
+				{
+					alias:    "a",
+					longLink: "https://short-d.com/a",
+				},
+				{
+					alias:    "b",
+					longLink: "https://short-d.com/b",
+				},
+			},
+			checkedBefore: must.Time(t, "2020-05-01T08:00:00-07:00"),
+			limit:         1,
+			expectedShortLinks: []entity.ShortLink{
+				{
+					Alias:    "a",
+					LongLink: "https://short-d.com/a",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
+
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
+					shortLinks, err := shortLinkRepo.GetShortLinksCheckedBefore(testCase.checkedBefore, testCase.limit)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedShortLinks, shortLinks)
+				},
+			)
+		})
+	}
+}
+
+func TestShortLinkSql_UpdateHealth(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tableRows      []shortLinkTableRow
+		alias          string
+		health         entity.LinkHealth
+		hasErr         bool
+		expectedHealth entity.LinkHealth
+	}{
+		{
+			name:      "short link does not exist",
+			tableRows: []shortLinkTableRow{},
+			alias:     "missing",
+			health: entity.LinkHealth{
+				StatusCode: ptr.Int(200),
+				CheckedAt:  ptr.Time(must.Time(t, "2020-05-01T08:00:00Z")),
+			},
+			hasErr: true,
+		},
+		{
+			name: "record broken destination",
+			tableRows: []shortLinkTableRow{
+				{
+					alias:            "short",
+					longLink:         "https://short-d.com",
+					healthStatusCode: ptr.Int(200),
+					healthCheckedAt:  ptr.Time(must.Time(t, "2020-04-01T08:00:00Z")),
+				},
+			},
+			alias: "short",
+			health: entity.LinkHealth{
+				CheckedAt: ptr.Time(must.Time(t, "2020-05-01T08:00:00Z")),
+			},
+			hasErr: false,
+			expectedHealth: entity.LinkHealth{
+				CheckedAt: ptr.Time(must.Time(t, "2020-05-01T08:00:00Z")),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertShortLinkTableRows(t, sqlDB, testCase.tableRows)
+
+					shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
+					err := shortLinkRepo.UpdateHealth(testCase.alias, testCase.health)
+					if testCase.hasErr {
+						assert.NotEqual(t, nil, err)
+						return
+					}
+					assert.Equal(t, nil, err)
+
+					shortLink, err := shortLinkRepo.GetShortLinkByAlias(testCase.alias)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedHealth, shortLink.Health)
+				},
+			)
+		})
+	}
+}
+
+func TestShortLinkSql_UpdateScrapedMetaTags(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "short", longLink: "https://short-d.com"},
+			})
+			shortLinkRepo := sqldb.NewShortLinkSQL(sqlDB, aliasNormalizer)
+
+			scrapedTags := metatag.OpenGraph{Title: ptr.String("Scraped")}
+			err := shortLinkRepo.UpdateScrapedMetaTags("short", "https://short-d.com", scrapedTags, metatag.Twitter{})
+			assert.Equal(t, nil, err)
+			shortLink, err := shortLinkRepo.GetShortLinkByAlias("short")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, scrapedTags, shortLink.OpenGraphTags)
+
+			outdatedTags := metatag.OpenGraph{Title: ptr.String("Outdated")}
+			err = shortLinkRepo.UpdateScrapedMetaTags("short", "https://old.short-d.com", outdatedTags, metatag.Twitter{})
+			assert.Equal(t, nil, err)
+			shortLink, err = shortLinkRepo.GetShortLinkByAlias("short")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, scrapedTags, shortLink.OpenGraphTags)
+
+			customTags := metatag.OpenGraph{Title: ptr.String("Custom")}
+			_, err = shortLinkRepo.UpdateOpenGraphTags("short", customTags)
+			assert.Equal(t, nil, err)
+			err = shortLinkRepo.UpdateScrapedMetaTags("short", "https://short-d.com", scrapedTags, metatag.Twitter{})
+			assert.Equal(t, nil, err)
+			shortLink, err = shortLinkRepo.GetShortLinkByAlias("short")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, customTags, shortLink.OpenGraphTags)
+		},
+	)
+}
+
 func insertShortLinkTableRows(t *testing.T, sqlDB *sql.DB, tableRows []shortLinkTableRow) {
 	for _, tableRow := range tableRows {
 		_, err := sqlDB.Exec(
@@ -787,6 +996,8 @@ func insertShortLinkTableRows(t *testing.T, sqlDB *sql.DB, tableRows []shortLink
 			tableRow.twitterTitle,
 			tableRow.twitterDescription,
 			tableRow.twitterImageURL,
+			tableRow.healthStatusCode,
+			tableRow.healthCheckedAt,
 		)
 		assert.Equal(t, nil, err)
 	}
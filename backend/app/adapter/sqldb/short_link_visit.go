@@ -0,0 +1,81 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.ShortLinkVisit = (*ShortLinkVisitSQL)(nil)
+
+// ShortLinkVisitSQL accesses the visits of short links in short_link_visit
+// table.
+type ShortLinkVisitSQL struct {
+	db *sql.DB
+}
+
+// RecordVisit counts a visit to the short link at the given time.
+func (s ShortLinkVisitSQL) RecordVisit(alias string, visitedAt time.Time) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s")
+VALUES ($1, 1, $2)
+ON CONFLICT ("%s") DO UPDATE
+SET "%s"="%s"."%s"+1, "%s"=GREATEST("%s"."%s", EXCLUDED."%s");
+`,
+		table.ShortLinkVisit.TableName,
+		table.ShortLinkVisit.ColumnShortLinkAlias,
+		table.ShortLinkVisit.ColumnVisitCount,
+		table.ShortLinkVisit.ColumnLastVisitedAt,
+		table.ShortLinkVisit.ColumnShortLinkAlias,
+		table.ShortLinkVisit.ColumnVisitCount,
+		table.ShortLinkVisit.TableName,
+		table.ShortLinkVisit.ColumnVisitCount,
+		table.ShortLinkVisit.ColumnLastVisitedAt,
+		table.ShortLinkVisit.TableName,
+		table.ShortLinkVisit.ColumnLastVisitedAt,
+		table.ShortLinkVisit.ColumnLastVisitedAt,
+	)
+	_, err := s.db.Exec(statement, alias, visitedAt.UTC())
+	return err
+}
+
+// GetAnalytics fetches how often the short link has been visited. The
+// analytics are empty for the short links never visited.
+func (s ShortLinkVisitSQL) GetAnalytics(alias string) (entity.ShortLinkAnalytics, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s"
+FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.ShortLinkVisit.ColumnVisitCount,
+		table.ShortLinkVisit.ColumnLastVisitedAt,
+		table.ShortLinkVisit.TableName,
+		table.ShortLinkVisit.ColumnShortLinkAlias,
+	)
+
+	var (
+		visitCount    int
+		lastVisitedAt time.Time
+	)
+	err := s.db.QueryRow(query, alias).Scan(&visitCount, &lastVisitedAt)
+	if err == sql.ErrNoRows {
+		return entity.ShortLinkAnalytics{}, nil
+	}
+	if err != nil {
+		return entity.ShortLinkAnalytics{}, err
+	}
+	lastVisitedAt = lastVisitedAt.UTC()
+	return entity.ShortLinkAnalytics{
+		VisitCount:    visitCount,
+		LastVisitedAt: &lastVisitedAt,
+	}, nil
+}
+
+// NewShortLinkVisitSQL creates ShortLinkVisitSQL.
+func NewShortLinkVisitSQL(db *sql.DB) ShortLinkVisitSQL {
+	return ShortLinkVisitSQL{db: db}
+}
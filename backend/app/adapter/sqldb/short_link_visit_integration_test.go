@@ -0,0 +1,63 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestShortLinkVisitSQL_RecordVisit(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "docs", longLink: "https://example.com/docs"},
+				{alias: "blog", longLink: "https://example.com/blog"},
+			})
+			visitRepo := sqldb.NewShortLinkVisitSQL(sqlDB)
+
+			analytics, err := visitRepo.GetAnalytics("docs")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, entity.ShortLinkAnalytics{}, analytics)
+
+			firstVisitedAt := must.Time(t, "2020-05-01T08:02:16Z")
+			lastVisitedAt := must.Time(t, "2020-05-02T08:02:16Z")
+			for _, visitedAt := range []string{
+				"2020-05-01T08:02:16Z",
+				"2020-05-02T08:02:16Z",
+				"2020-05-01T20:02:16Z",
+			} {
+				err = visitRepo.RecordVisit("docs", must.Time(t, visitedAt))
+				assert.Equal(t, nil, err)
+			}
+			err = visitRepo.RecordVisit("blog", firstVisitedAt)
+			assert.Equal(t, nil, err)
+
+			analytics, err = visitRepo.GetAnalytics("docs")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, entity.ShortLinkAnalytics{
+				VisitCount:    3,
+				LastVisitedAt: &lastVisitedAt,
+			}, analytics)
+
+			analytics, err = visitRepo.GetAnalytics("blog")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, entity.ShortLinkAnalytics{
+				VisitCount:    1,
+				LastVisitedAt: &firstVisitedAt,
+			}, analytics)
+
+			err = visitRepo.RecordVisit("missing", firstVisitedAt)
+			assert.NotEqual(t, nil, err)
+		})
+}
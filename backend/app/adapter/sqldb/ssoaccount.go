@@ -0,0 +1,150 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.SSOMap = (*SSOAccountSQL)(nil)
+
+// SSOAccountSQL accesses mapping between the accounts of an identity provider
+// and Short accounts in the SQL database. The accounts are identified by the
+// name of the identity provider together with their subject.
+type SSOAccountSQL struct {
+	db       *sql.DB
+	logger   logger.Logger
+	provider string
+}
+
+// GetShortUserID retrieves the internal user ID that is linked to the user's
+// account at the identity provider.
+func (s SSOAccountSQL) GetShortUserID(ssoUserID string) (string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.SSOAccount.ColumnShortUserID,
+		table.SSOAccount.TableName,
+		table.SSOAccount.ColumnProvider,
+		table.SSOAccount.ColumnSubject,
+	)
+	var id string
+	err := s.db.QueryRow(query, s.provider, ssoUserID).Scan(&id)
+	if err == nil {
+		return id, err
+	}
+	if err == sql.ErrNoRows {
+		return "", repository.ErrEntryNotFound(
+			fmt.Sprintf("user with %s subject %s not found", s.provider, ssoUserID),
+		)
+	}
+	s.logger.Error(err)
+	return "", err
+}
+
+// IsSSOUserExist checks whether mapping for a given account of the identity
+// provider exists in the database.
+func (s SSOAccountSQL) IsSSOUserExist(ssoUserID string) (bool, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.SSOAccount.ColumnSubject,
+		table.SSOAccount.TableName,
+		table.SSOAccount.ColumnProvider,
+		table.SSOAccount.ColumnSubject,
+	)
+	var subject string
+	err := s.db.QueryRow(query, s.provider, ssoUserID).Scan(&subject)
+	if err == nil {
+		return true, err
+	}
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	s.logger.Error(err)
+	return false, err
+}
+
+// CreateMapping links user's account at the identity provider with Short
+// account in the database.
+func (s SSOAccountSQL) CreateMapping(ssoUserID string, userID string) error {
+	statement := fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s")
+VALUES ($1, $2, $3);
+`,
+		table.SSOAccount.TableName,
+		table.SSOAccount.ColumnProvider,
+		table.SSOAccount.ColumnSubject,
+		table.SSOAccount.ColumnShortUserID,
+	)
+	_, err := s.db.Exec(statement, s.provider, ssoUserID, userID)
+	return err
+}
+
+// GetSSOUserID retrieves the subject of the user's account at the identity
+// provider.
+func (s SSOAccountSQL) GetSSOUserID(shortUserID string) (string, error) {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2;
+`,
+		table.SSOAccount.ColumnSubject,
+		table.SSOAccount.TableName,
+		table.SSOAccount.ColumnProvider,
+		table.SSOAccount.ColumnShortUserID,
+	)
+	var subject string
+	err := s.db.QueryRow(query, s.provider, shortUserID).Scan(&subject)
+	if err == nil {
+		return subject, err
+	}
+	if err == sql.ErrNoRows {
+		return "", repository.ErrEntryNotFound(
+			fmt.Sprintf("%s account of user %s not found", s.provider, shortUserID),
+		)
+	}
+	s.logger.Error(err)
+	return "", err
+}
+
+// DeleteMapping unlinks user's account at the identity provider from Short
+// account in the database.
+func (s SSOAccountSQL) DeleteMapping(ssoUserID string, shortUserID string) error {
+	statement := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s"=$2 AND "%s"=$3;
+`,
+		table.SSOAccount.TableName,
+		table.SSOAccount.ColumnProvider,
+		table.SSOAccount.ColumnSubject,
+		table.SSOAccount.ColumnShortUserID,
+	)
+	res, err := s.db.Exec(statement, s.provider, ssoUserID, shortUserID)
+	if err != nil {
+		s.logger.Error(err)
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("%s account %s not linked to user %s", s.provider, ssoUserID, shortUserID),
+		)
+	}
+	return nil
+}
+
+// NewSSOAccountSQL creates SSOAccountSQL for the given identity provider.
+func NewSSOAccountSQL(db *sql.DB, logger logger.Logger, provider string) SSOAccountSQL {
+	return SSOAccountSQL{db: db, logger: logger, provider: provider}
+}
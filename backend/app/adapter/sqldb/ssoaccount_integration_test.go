@@ -0,0 +1,200 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+)
+
+type ssoAccountTableRow struct {
+	provider    string
+	subject     string
+	shortUserID string
+}
+
+func TestSSOAccountSQL_GetShortUserID(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userTableRows []userTableRow
+		tableRows     []ssoAccountTableRow
+		provider      string
+		subject       string
+		hasErr        bool
+		expectedID    string
+	}{
+		{
+			name:          "account not found",
+			userTableRows: []userTableRow{},
+			tableRows:     []ssoAccountTableRow{},
+			provider:      "okta",
+			subject:       "00u1a2b3c",
+			hasErr:        true,
+		},
+		{
+			name: "same subject of other provider",
+			userTableRows: []userTableRow{
+				{id: "alpha", email: "alpha@example.com", name: "alpha"},
+			},
+			tableRows: []ssoAccountTableRow{
+				{provider: "keycloak", subject: "00u1a2b3c", shortUserID: "alpha"},
+			},
+			provider: "okta",
+			subject:  "00u1a2b3c",
+			hasErr:   true,
+		},
+		{
+			name: "account found",
+			userTableRows: []userTableRow{
+				{id: "alpha", email: "alpha@example.com", name: "alpha"},
+			},
+			tableRows: []ssoAccountTableRow{
+				{provider: "okta", subject: "00u1a2b3c", shortUserID: "alpha"},
+			},
+			provider:   "okta",
+			subject:    "00u1a2b3c",
+			hasErr:     false,
+			expectedID: "alpha",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertUserTableRows(t, sqlDB, testCase.userTableRows)
+					insertSSOAccountTableRows(t, sqlDB, testCase.tableRows)
+
+					entryRepo := logger.NewEntryRepoFake()
+					lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+					assert.Equal(t, nil, err)
+
+					ssoAccountRepo := sqldb.NewSSOAccountSQL(sqlDB, lg, testCase.provider)
+					gotID, err := ssoAccountRepo.GetShortUserID(testCase.subject)
+					if testCase.hasErr {
+						assert.NotEqual(t, nil, err)
+						return
+					}
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedID, gotID)
+
+					isExist, err := ssoAccountRepo.IsSSOUserExist(testCase.subject)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, true, isExist)
+				})
+		})
+	}
+}
+
+func TestSSOAccountSQL_CreateMapping(t *testing.T) {
+	defaultUserTableRows := []userTableRow{
+		{id: "short", email: "short@example.com", name: "short"},
+		{id: "alpha", email: "alpha@example.com", name: "alpha"},
+	}
+
+	testCases := []struct {
+		name          string
+		userTableRows []userTableRow
+		tableRows     []ssoAccountTableRow
+		provider      string
+		subject       string
+		shortUserID   string
+		hasErr        bool
+	}{
+		{
+			name:          "subject already linked",
+			userTableRows: defaultUserTableRows,
+			tableRows: []ssoAccountTableRow{
+				{provider: "okta", subject: "00u1a2b3c", shortUserID: "short"},
+			},
+			provider:    "okta",
+			subject:     "00u1a2b3c",
+			shortUserID: "alpha",
+			hasErr:      true,
+		},
+		{
+			name:          "user already linked to the provider",
+			userTableRows: defaultUserTableRows,
+			tableRows: []ssoAccountTableRow{
+				{provider: "okta", subject: "00u1a2b3c", shortUserID: "short"},
+			},
+			provider:    "okta",
+			subject:     "00u4d5e6f",
+			shortUserID: "short",
+			hasErr:      true,
+		},
+		{
+			name:          "user linked to other provider",
+			userTableRows: defaultUserTableRows,
+			tableRows: []ssoAccountTableRow{
+				{provider: "keycloak", subject: "00u1a2b3c", shortUserID: "short"},
+			},
+			provider:    "okta",
+			subject:     "00u1a2b3c",
+			shortUserID: "short",
+			hasErr:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertUserTableRows(t, sqlDB, testCase.userTableRows)
+					insertSSOAccountTableRows(t, sqlDB, testCase.tableRows)
+
+					entryRepo := logger.NewEntryRepoFake()
+					lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+					assert.Equal(t, nil, err)
+
+					ssoAccountRepo := sqldb.NewSSOAccountSQL(sqlDB, lg, testCase.provider)
+					err = ssoAccountRepo.CreateMapping(testCase.subject, testCase.shortUserID)
+					if testCase.hasErr {
+						assert.NotEqual(t, nil, err)
+						return
+					}
+					assert.Equal(t, nil, err)
+
+					gotID, err := ssoAccountRepo.GetShortUserID(testCase.subject)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.shortUserID, gotID)
+				})
+		})
+	}
+}
+
+var insertSSOAccountRowSQL = fmt.Sprintf(`
+INSERT INTO "%s" ("%s", "%s", "%s")
+VALUES ($1, $2, $3)`,
+	table.SSOAccount.TableName,
+	table.SSOAccount.ColumnProvider,
+	table.SSOAccount.ColumnSubject,
+	table.SSOAccount.ColumnShortUserID,
+)
+
+func insertSSOAccountTableRows(t *testing.T, sqlDB *sql.DB, rows []ssoAccountTableRow) {
+	for _, row := range rows {
+		_, err := sqlDB.Exec(
+			insertSSOAccountRowSQL,
+			row.provider,
+			row.subject,
+			row.shortUserID,
+		)
+		assert.Equal(t, nil, err)
+	}
+}
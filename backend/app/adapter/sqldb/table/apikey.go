@@ -2,15 +2,19 @@ package table
 
 // APIKey represents database table columns for 'api_key' table.
 var APIKey = struct {
-	TableName       string
-	ColumnAppID     string
-	ColumnKey       string
-	ColumnDisabled  string
-	ColumnCreatedAt string
+	TableName        string
+	ColumnAppID      string
+	ColumnKey        string
+	ColumnDisabled   string
+	ColumnCreatedAt  string
+	ColumnExpireAt   string
+	ColumnLastUsedAt string
 }{
-	TableName:       "api_key",
-	ColumnAppID:     "app_id",
-	ColumnKey:       "key",
-	ColumnDisabled:  "disabled",
-	ColumnCreatedAt: "created_at",
+	TableName:        "api_key",
+	ColumnAppID:      "app_id",
+	ColumnKey:        "key",
+	ColumnDisabled:   "disabled",
+	ColumnCreatedAt:  "created_at",
+	ColumnExpireAt:   "expire_at",
+	ColumnLastUsedAt: "last_used_at",
 }
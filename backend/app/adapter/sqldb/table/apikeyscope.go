@@ -0,0 +1,14 @@
+package table
+
+// APIKeyScope represents database table columns for 'api_key_scope' table.
+var APIKeyScope = struct {
+	TableName   string
+	ColumnAppID string
+	ColumnKey   string
+	ColumnScope string
+}{
+	TableName:   "api_key_scope",
+	ColumnAppID: "app_id",
+	ColumnKey:   "key",
+	ColumnScope: "scope",
+}
@@ -5,10 +5,12 @@ var App = struct {
 	TableName       string
 	ColumnID        string
 	ColumnName      string
+	ColumnOwnerID   string
 	ColumnCreatedAt string
 }{
 	TableName:       "app",
 	ColumnID:        "id",
 	ColumnName:      "name",
+	ColumnOwnerID:   "owner_id",
 	ColumnCreatedAt: "created_at",
 }
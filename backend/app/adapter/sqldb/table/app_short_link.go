@@ -0,0 +1,12 @@
+package table
+
+// AppShortLink represents database table columns for 'app_short_link' table
+var AppShortLink = struct {
+	TableName            string
+	ColumnAppID          string
+	ColumnShortLinkAlias string
+}{
+	TableName:            "app_short_link",
+	ColumnAppID:          "app_id",
+	ColumnShortLinkAlias: "short_link_alias",
+}
@@ -0,0 +1,19 @@
+package table
+
+// EmailSignInToken represents database table columns for
+// 'email_sign_in_token' table.
+var EmailSignInToken = struct {
+	TableName       string
+	ColumnID        string
+	ColumnEmail     string
+	ColumnCreatedAt string
+	ColumnExpireAt  string
+	ColumnUsedAt    string
+}{
+	TableName:       "email_sign_in_token",
+	ColumnID:        "id",
+	ColumnEmail:     "email",
+	ColumnCreatedAt: "created_at",
+	ColumnExpireAt:  "expire_at",
+	ColumnUsedAt:    "used_at",
+}
@@ -0,0 +1,14 @@
+package table
+
+// Role represents database table columns for 'role' table
+var Role = struct {
+	TableName       string
+	ColumnName      string
+	ColumnIsBuiltIn string
+	ColumnCreatedAt string
+}{
+	TableName:       "role",
+	ColumnName:      "name",
+	ColumnIsBuiltIn: "is_built_in",
+	ColumnCreatedAt: "created_at",
+}
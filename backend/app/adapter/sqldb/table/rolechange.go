@@ -0,0 +1,20 @@
+package table
+
+// RoleChange represents database table columns for 'role_change' table
+var RoleChange = struct {
+	TableName       string
+	ColumnID        string
+	ColumnActorID   string
+	ColumnUserID    string
+	ColumnRole      string
+	ColumnAction    string
+	ColumnCreatedAt string
+}{
+	TableName:       "role_change",
+	ColumnID:        "id",
+	ColumnActorID:   "actor_id",
+	ColumnUserID:    "user_id",
+	ColumnRole:      "role",
+	ColumnAction:    "action",
+	ColumnCreatedAt: "created_at",
+}
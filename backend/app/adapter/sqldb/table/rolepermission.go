@@ -0,0 +1,13 @@
+package table
+
+// RolePermission represents database table columns for 'role_permission'
+// table
+var RolePermission = struct {
+	TableName        string
+	ColumnRole       string
+	ColumnPermission string
+}{
+	TableName:        "role_permission",
+	ColumnRole:       "role",
+	ColumnPermission: "permission",
+}
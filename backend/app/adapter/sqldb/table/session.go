@@ -0,0 +1,28 @@
+package table
+
+// Session represents database table columns for 'session' table.
+var Session = struct {
+	TableName                    string
+	ColumnID                     string
+	ColumnUserID                 string
+	ColumnRefreshTokenGeneration string
+	ColumnIdentityProvider       string
+	ColumnClientIP               string
+	ColumnLocation               string
+	ColumnUserAgent              string
+	ColumnCreatedAt              string
+	ColumnExpireAt               string
+	ColumnRevokedAt              string
+}{
+	TableName:                    "session",
+	ColumnID:                     "id",
+	ColumnUserID:                 "user_id",
+	ColumnRefreshTokenGeneration: "refresh_token_generation",
+	ColumnIdentityProvider:       "identity_provider",
+	ColumnClientIP:               "client_ip",
+	ColumnLocation:               "location",
+	ColumnUserAgent:              "user_agent",
+	ColumnCreatedAt:              "created_at",
+	ColumnExpireAt:               "expire_at",
+	ColumnRevokedAt:              "revoked_at",
+}
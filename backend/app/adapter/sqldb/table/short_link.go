@@ -14,6 +14,9 @@ var ShortLink = struct {
 	ColumnTwitterTitle         string
 	ColumnTwitterDescription   string
 	ColumnTwitterImageURL      string
+	ColumnHealthStatusCode     string
+	ColumnHealthCheckedAt      string
+	ColumnHasCustomMetaTags    string
 }{
 	TableName:                  "short_link",
 	ColumnAlias:                "alias",
@@ -27,4 +30,7 @@ var ShortLink = struct {
 	ColumnTwitterTitle:         "twitter_title",
 	ColumnTwitterDescription:   "twitter_description",
 	ColumnTwitterImageURL:      "twitter_image_url",
+	ColumnHealthStatusCode:     "health_status_code",
+	ColumnHealthCheckedAt:      "health_checked_at",
+	ColumnHasCustomMetaTags:    "has_custom_meta_tags",
 }
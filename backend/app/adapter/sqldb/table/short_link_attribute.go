@@ -0,0 +1,15 @@
+package table
+
+// ShortLinkAttribute represents database table columns for
+// 'short_link_attribute' table
+var ShortLinkAttribute = struct {
+	TableName            string
+	ColumnShortLinkAlias string
+	ColumnName           string
+	ColumnValue          string
+}{
+	TableName:            "short_link_attribute",
+	ColumnShortLinkAlias: "short_link_alias",
+	ColumnName:           "name",
+	ColumnValue:          "value",
+}
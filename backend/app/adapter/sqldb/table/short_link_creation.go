@@ -0,0 +1,33 @@
+package table
+
+// UserShortLinkCreation represents database table columns for
+// 'user_short_link_creation' table
+var UserShortLinkCreation = struct {
+	TableName            string
+	ColumnID             string
+	ColumnUserID         string
+	ColumnShortLinkAlias string
+	ColumnCreatedAt      string
+}{
+	TableName:            "user_short_link_creation",
+	ColumnID:             "id",
+	ColumnUserID:         "user_id",
+	ColumnShortLinkAlias: "short_link_alias",
+	ColumnCreatedAt:      "created_at",
+}
+
+// AppShortLinkCreation represents database table columns for
+// 'app_short_link_creation' table
+var AppShortLinkCreation = struct {
+	TableName            string
+	ColumnID             string
+	ColumnAppID          string
+	ColumnShortLinkAlias string
+	ColumnCreatedAt      string
+}{
+	TableName:            "app_short_link_creation",
+	ColumnID:             "id",
+	ColumnAppID:          "app_id",
+	ColumnShortLinkAlias: "short_link_alias",
+	ColumnCreatedAt:      "created_at",
+}
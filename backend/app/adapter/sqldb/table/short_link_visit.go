@@ -0,0 +1,15 @@
+package table
+
+// ShortLinkVisit represents database table columns for 'short_link_visit'
+// table
+var ShortLinkVisit = struct {
+	TableName            string
+	ColumnShortLinkAlias string
+	ColumnVisitCount     string
+	ColumnLastVisitedAt  string
+}{
+	TableName:            "short_link_visit",
+	ColumnShortLinkAlias: "short_link_alias",
+	ColumnVisitCount:     "visit_count",
+	ColumnLastVisitedAt:  "last_visited_at",
+}
@@ -0,0 +1,14 @@
+package table
+
+// SSOAccount represents database table columns for 'sso_account' table.
+var SSOAccount = struct {
+	TableName         string
+	ColumnProvider    string
+	ColumnSubject     string
+	ColumnShortUserID string
+}{
+	TableName:         "sso_account",
+	ColumnProvider:    "provider",
+	ColumnSubject:     "subject",
+	ColumnShortUserID: "short_user_id",
+}
@@ -0,0 +1,32 @@
+package table
+
+// TwoFactor represents database table columns for 'two_factor' table.
+var TwoFactor = struct {
+	TableName          string
+	ColumnUserID       string
+	ColumnSecret       string
+	ColumnLastTimeStep string
+	ColumnCreatedAt    string
+	ColumnEnabledAt    string
+}{
+	TableName:          "two_factor",
+	ColumnUserID:       "user_id",
+	ColumnSecret:       "secret",
+	ColumnLastTimeStep: "last_time_step",
+	ColumnCreatedAt:    "created_at",
+	ColumnEnabledAt:    "enabled_at",
+}
+
+// TwoFactorRecoveryCode represents database table columns for
+// 'two_factor_recovery_code' table.
+var TwoFactorRecoveryCode = struct {
+	TableName      string
+	ColumnUserID   string
+	ColumnCodeHash string
+	ColumnUsedAt   string
+}{
+	TableName:      "two_factor_recovery_code",
+	ColumnUserID:   "user_id",
+	ColumnCodeHash: "code_hash",
+	ColumnUsedAt:   "used_at",
+}
@@ -0,0 +1,220 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.TwoFactor = (*TwoFactorSQL)(nil)
+
+// TwoFactorSQL accesses TwoFactor and its recovery codes from the database
+// through SQL.
+type TwoFactorSQL struct {
+	db *sql.DB
+}
+
+// GetTwoFactor fetches the TwoFactor of the given user from TwoFactor table
+// using SQL.
+func (t TwoFactorSQL) GetTwoFactor(userID string) (entity.TwoFactor, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s", "%s", "%s", "%s"
+FROM "%s" WHERE "%s"=$1;
+`,
+		table.TwoFactor.ColumnUserID,
+		table.TwoFactor.ColumnSecret,
+		table.TwoFactor.ColumnLastTimeStep,
+		table.TwoFactor.ColumnCreatedAt,
+		table.TwoFactor.ColumnEnabledAt,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnUserID,
+	)
+
+	twoFactor := entity.TwoFactor{}
+	err := t.db.QueryRow(query, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.LastTimeStep,
+		&twoFactor.CreatedAt,
+		&twoFactor.EnabledAt,
+	)
+	if err == nil {
+		return twoFactor, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.TwoFactor{}, repository.ErrEntryNotFound(
+			fmt.Sprintf("two factor of user(%s) not found", userID))
+	}
+	return entity.TwoFactor{}, err
+}
+
+// CreateTwoFactor replaces the pending TwoFactor of the user and its recovery
+// codes within a single transaction.
+func (t TwoFactorSQL) CreateTwoFactor(twoFactor entity.TwoFactor, recoveryCodeHashes []string) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	deleteStmt := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1 AND "%s" IS NULL;
+`,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnUserID,
+		table.TwoFactor.ColumnEnabledAt,
+	)
+	_, err = tx.Exec(deleteStmt, twoFactor.UserID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	insertStmt := fmt.Sprintf(`
+INSERT INTO "%s"("%s", "%s", "%s", "%s")
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+`,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnUserID,
+		table.TwoFactor.ColumnSecret,
+		table.TwoFactor.ColumnLastTimeStep,
+		table.TwoFactor.ColumnCreatedAt,
+	)
+	res, err := tx.Exec(
+		insertStmt,
+		twoFactor.UserID,
+		twoFactor.Secret,
+		twoFactor.LastTimeStep,
+		twoFactor.CreatedAt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected < 1 {
+		tx.Rollback()
+		return repository.ErrEntryExists(
+			fmt.Sprintf("two factor of user(%s)", twoFactor.UserID))
+	}
+
+	codeStmt := fmt.Sprintf(`
+INSERT INTO "%s"("%s", "%s")
+VALUES ($1, $2);
+`,
+		table.TwoFactorRecoveryCode.TableName,
+		table.TwoFactorRecoveryCode.ColumnUserID,
+		table.TwoFactorRecoveryCode.ColumnCodeHash,
+	)
+	for _, codeHash := range recoveryCodeHashes {
+		_, err = tx.Exec(codeStmt, twoFactor.UserID, codeHash)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// EnableTwoFactor marks the TwoFactor of the user as enabled in TwoFactor
+// table using SQL.
+func (t TwoFactorSQL) EnableTwoFactor(userID string, enabledAt time.Time) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2;
+`,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnEnabledAt,
+		table.TwoFactor.ColumnUserID,
+	)
+	return t.execOnExistingRow(stmt, fmt.Sprintf("two factor of user(%s) not found", userID), enabledAt, userID)
+}
+
+// UseTimeStep records the time step of an accepted password in TwoFactor
+// table using SQL. It fails unless the time step is later than the last one,
+// so that a password can only be used once.
+func (t TwoFactorSQL) UseTimeStep(userID string, timeStep int64) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s"<$1;
+`,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnLastTimeStep,
+		table.TwoFactor.ColumnUserID,
+		table.TwoFactor.ColumnLastTimeStep,
+	)
+	return t.execOnExistingRow(
+		stmt,
+		fmt.Sprintf("two factor of user(%s) before time step %d not found", userID, timeStep),
+		timeStep,
+		userID,
+	)
+}
+
+// UseRecoveryCode marks an unused recovery code as used in
+// TwoFactorRecoveryCode table using SQL.
+func (t TwoFactorSQL) UseRecoveryCode(userID string, codeHash string, usedAt time.Time) error {
+	stmt := fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$1
+WHERE "%s"=$2 AND "%s"=$3 AND "%s" IS NULL;
+`,
+		table.TwoFactorRecoveryCode.TableName,
+		table.TwoFactorRecoveryCode.ColumnUsedAt,
+		table.TwoFactorRecoveryCode.ColumnUserID,
+		table.TwoFactorRecoveryCode.ColumnCodeHash,
+		table.TwoFactorRecoveryCode.ColumnUsedAt,
+	)
+	return t.execOnExistingRow(
+		stmt,
+		fmt.Sprintf("unused recovery code of user(%s) not found", userID),
+		usedAt,
+		userID,
+		codeHash,
+	)
+}
+
+// DeleteTwoFactor removes the TwoFactor of the user from TwoFactor table
+// using SQL. Its recovery codes are removed in cascade.
+func (t TwoFactorSQL) DeleteTwoFactor(userID string) error {
+	stmt := fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1;
+`,
+		table.TwoFactor.TableName,
+		table.TwoFactor.ColumnUserID,
+	)
+	return t.execOnExistingRow(stmt, fmt.Sprintf("two factor of user(%s) not found", userID), userID)
+}
+
+func (t TwoFactorSQL) execOnExistingRow(stmt string, notFoundMsg string, args ...interface{}) error {
+	res, err := t.db.Exec(stmt, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected < 1 {
+		return repository.ErrEntryNotFound(notFoundMsg)
+	}
+	return nil
+}
+
+// NewTwoFactorSQL creates TwoFactorSQL.
+func NewTwoFactorSQL(db *sql.DB) TwoFactorSQL {
+	return TwoFactorSQL{db: db}
+}
@@ -0,0 +1,67 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestTwoFactorSQL(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{{id: "alpha"}})
+			twoFactorRepo := sqldb.NewTwoFactorSQL(sqlDB)
+
+			_, err := twoFactorRepo.GetTwoFactor("alpha")
+			assert.NotEqual(t, nil, err)
+
+			createdAt := must.Time(t, "2020-05-01T08:00:00Z")
+			pending := entity.TwoFactor{UserID: "alpha", Secret: "PENDING", CreatedAt: createdAt}
+			err = twoFactorRepo.CreateTwoFactor(pending, []string{"hash1"})
+			assert.Equal(t, nil, err)
+
+			twoFactor := entity.TwoFactor{UserID: "alpha", Secret: "SECRET", CreatedAt: createdAt}
+			err = twoFactorRepo.CreateTwoFactor(twoFactor, []string{"hash2", "hash3"})
+			assert.Equal(t, nil, err)
+
+			gotTwoFactor, err := twoFactorRepo.GetTwoFactor("alpha")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "SECRET", gotTwoFactor.Secret)
+			assert.Equal(t, false, gotTwoFactor.IsEnabled())
+
+			err = twoFactorRepo.UseRecoveryCode("alpha", "hash1", createdAt)
+			assert.NotEqual(t, nil, err)
+
+			err = twoFactorRepo.EnableTwoFactor("alpha", must.Time(t, "2020-05-01T08:01:00Z"))
+			assert.Equal(t, nil, err)
+
+			err = twoFactorRepo.CreateTwoFactor(pending, []string{"hash1"})
+			assert.NotEqual(t, nil, err)
+
+			err = twoFactorRepo.UseTimeStep("alpha", 100)
+			assert.Equal(t, nil, err)
+			err = twoFactorRepo.UseTimeStep("alpha", 100)
+			assert.NotEqual(t, nil, err)
+
+			err = twoFactorRepo.UseRecoveryCode("alpha", "hash2", createdAt)
+			assert.Equal(t, nil, err)
+			err = twoFactorRepo.UseRecoveryCode("alpha", "hash2", createdAt)
+			assert.NotEqual(t, nil, err)
+
+			err = twoFactorRepo.DeleteTwoFactor("alpha")
+			assert.Equal(t, nil, err)
+			err = twoFactorRepo.UseRecoveryCode("alpha", "hash3", createdAt)
+			assert.NotEqual(t, nil, err)
+		})
+}
@@ -173,6 +173,55 @@ VALUES ($1, $2, $3, $4, $5, $6)
 	return err
 }
 
+// GetUsers fetches at most limit users ordered by ID from user table, starting
+// after the user with the given ID.
+func (u UserSQL) GetUsers(afterID string, limit int) ([]entity.User, error) {
+	query := fmt.Sprintf(`
+SELECT "%s", "%s", "%s", "%s", "%s", "%s"
+FROM "%s"
+WHERE "%s">$1
+ORDER BY "%s"
+LIMIT $2;
+`,
+		table.User.ColumnID,
+		table.User.ColumnEmail,
+		table.User.ColumnName,
+		table.User.ColumnLastSignedInAt,
+		table.User.ColumnCreatedAt,
+		table.User.ColumnUpdatedAt,
+		table.User.TableName,
+		table.User.ColumnID,
+		table.User.ColumnID,
+	)
+
+	rows, err := u.db.Query(query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []entity.User{}
+	for rows.Next() {
+		user := entity.User{}
+		err = rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Name,
+			&user.LastSignedInAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		user.CreatedAt = utc(user.CreatedAt)
+		user.UpdatedAt = utc(user.UpdatedAt)
+		user.LastSignedInAt = utc(user.LastSignedInAt)
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
 // NewUserSQL creates UserSQL
 func NewUserSQL(db *sql.DB) UserSQL {
 	return UserSQL{
@@ -339,6 +339,63 @@ func TestUserSql_CreateUser(t *testing.T) {
 	}
 }
 
+func TestUserSql_GetUsers(t *testing.T) {
+	tableRows := []userTableRow{
+		{id: "gamma", email: "gamma@example.com", name: "Gamma"},
+		{id: "alpha", email: "alpha@example.com", name: "Alpha"},
+		{id: "beta", email: "beta@example.com", name: "Beta"},
+	}
+
+	testCases := []struct {
+		name     string
+		afterID  string
+		limit    int
+		expUsers []entity.User
+	}{
+		{
+			name:    "first page",
+			afterID: "",
+			limit:   2,
+			expUsers: []entity.User{
+				{ID: "alpha", Name: "Alpha", Email: "alpha@example.com"},
+				{ID: "beta", Name: "Beta", Email: "beta@example.com"},
+			},
+		},
+		{
+			name:    "last page",
+			afterID: "beta",
+			limit:   2,
+			expUsers: []entity.User{
+				{ID: "gamma", Name: "Gamma", Email: "gamma@example.com"},
+			},
+		},
+		{
+			name:     "after last user",
+			afterID:  "gamma",
+			limit:    2,
+			expUsers: []entity.User{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertUserTableRows(t, sqlDB, tableRows)
+
+					userRepo := sqldb.NewUserSQL(sqlDB)
+					gotUsers, err := userRepo.GetUsers(testCase.afterID, testCase.limit)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expUsers, gotUsers)
+				})
+		})
+	}
+}
+
 func insertUserTableRows(t *testing.T, sqlDB *sql.DB, tableRows []userTableRow) {
 	for _, tableRow := range tableRows {
 		_, err := sqlDB.Exec(
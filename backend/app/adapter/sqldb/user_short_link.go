@@ -3,6 +3,7 @@ package sqldb
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/short-d/short/backend/app/adapter/sqldb/table"
 	"github.com/short-d/short/backend/app/entity"
@@ -83,6 +84,51 @@ func (u UserShortLinkSQL) HasMapping(user entity.User, alias string) (bool, erro
 	return true, nil
 }
 
+// FindUserIDByAlias fetches the ID of the user who created the short link
+// from user_short_link table.
+func (u UserShortLinkSQL) FindUserIDByAlias(alias string) (string, error) {
+	query := fmt.Sprintf(`SELECT "%s" FROM "%s" WHERE "%s"=$1;`,
+		table.UserShortLink.ColumnUserID,
+		table.UserShortLink.TableName,
+		table.UserShortLink.ColumnShortLinkAlias,
+	)
+
+	var userID string
+	err := u.db.QueryRow(query, alias).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", repository.ErrEntryNotFound(
+			fmt.Sprintf("owner of short link(%s) not found", alias))
+	}
+	return userID, err
+}
+
+// CountShortLinksByUser counts the short links ever created by the given user
+// from user_short_link_creation table, including the deleted ones.
+func (u UserShortLinkSQL) CountShortLinksByUser(user entity.User) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s" WHERE "%s"=$1;`,
+		table.UserShortLinkCreation.TableName,
+		table.UserShortLinkCreation.ColumnUserID,
+	)
+
+	var count int
+	err := u.db.QueryRow(query, user.ID).Scan(&count)
+	return count, err
+}
+
+// CountShortLinksByUserSince counts the short links created by the given user
+// at or after the given time, including the deleted ones.
+func (u UserShortLinkSQL) CountShortLinksByUserSince(user entity.User, since time.Time) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s" WHERE "%s"=$1 AND "%s">=$2;`,
+		table.UserShortLinkCreation.TableName,
+		table.UserShortLinkCreation.ColumnUserID,
+		table.UserShortLinkCreation.ColumnCreatedAt,
+	)
+
+	var count int
+	err := u.db.QueryRow(query, user.ID, since.UTC()).Scan(&count)
+	return count, err
+}
+
 // NewUserShortLinkSQL creates UserShortLinkSQL
 func NewUserShortLinkSQL(db *sql.DB) UserShortLinkSQL {
 	return UserShortLinkSQL{
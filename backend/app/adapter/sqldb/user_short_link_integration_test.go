@@ -4,8 +4,10 @@ package sqldb_test
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/short-d/app/fw/assert"
 	"github.com/short-d/app/fw/db/dbtest"
@@ -14,6 +16,7 @@ import (
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/fw/must"
 	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
 var insertUserShortLinkRowSQL = fmt.Sprintf(`
@@ -274,6 +277,103 @@ func TestListShortLinkSql_HasMapping(t *testing.T) {
 	}
 }
 
+func TestListShortLinkSql_FindUserIDByAlias(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{
+				{id: "test", email: "test@example.com"},
+			})
+			insertShortLinkTableRows(t, sqlDB, []shortLinkTableRow{
+				{alias: "owned"},
+				{alias: "orphan"},
+			})
+			insertUserShortLinkTableRows(t, sqlDB, []userShortLinkTableRow{
+				{alias: "owned", userID: "test"},
+			})
+
+			userShortLinkRepo := sqldb.NewUserShortLinkSQL(sqlDB)
+
+			userID, err := userShortLinkRepo.FindUserIDByAlias("owned")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "test", userID)
+
+			_, err = userShortLinkRepo.FindUserIDByAlias("orphan")
+			var notFound repository.ErrEntryNotFound
+			assert.Equal(t, true, errors.As(err, &notFound))
+		})
+}
+
+func TestListShortLinkSql_CountShortLinksByUser(t *testing.T) {
+	testCases := []struct {
+		name                string
+		creationTableRows   []userShortLinkCreationTableRow
+		since               time.Time
+		expectedTotalCount  int
+		expectedRecentCount int
+	}{
+		{
+			name:                "no short link created",
+			creationTableRows:   []userShortLinkCreationTableRow{},
+			since:               must.Time(t, "2020-05-01T00:00:00Z"),
+			expectedTotalCount:  0,
+			expectedRecentCount: 0,
+		},
+		{
+			name: "short links created by different users",
+			creationTableRows: []userShortLinkCreationTableRow{
+				{userID: "test", alias: "old", createdAt: must.Time(t, "2020-04-30T23:59:59Z")},
+				{userID: "test", alias: "new", createdAt: must.Time(t, "2020-05-01T00:00:00Z")},
+				{userID: "test2", alias: "other", createdAt: must.Time(t, "2020-05-01T08:00:00Z")},
+			},
+			since:               must.Time(t, "2020-05-01T00:00:00Z"),
+			expectedTotalCount:  2,
+			expectedRecentCount: 1,
+		},
+		{
+			name: "short link created again after deletion",
+			creationTableRows: []userShortLinkCreationTableRow{
+				{userID: "test", alias: "reused", createdAt: must.Time(t, "2020-05-01T01:00:00Z")},
+				{userID: "test", alias: "reused", createdAt: must.Time(t, "2020-05-01T02:00:00Z")},
+			},
+			since:               must.Time(t, "2020-05-01T00:00:00Z"),
+			expectedTotalCount:  2,
+			expectedRecentCount: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dbtest.AccessTestDB(
+				dbConnector,
+				dbMigrationTool,
+				dbMigrationRoot,
+				dbConfig,
+				func(sqlDB *sql.DB) {
+					insertUserTableRows(t, sqlDB, []userTableRow{
+						{id: "test", email: "test@example.com"},
+						{id: "test2", email: "test2@example.com"},
+					})
+					insertUserShortLinkCreationTableRows(t, sqlDB, testCase.creationTableRows)
+
+					userShortLinkRepo := sqldb.NewUserShortLinkSQL(sqlDB)
+					user := entity.User{ID: "test"}
+
+					totalCount, err := userShortLinkRepo.CountShortLinksByUser(user)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedTotalCount, totalCount)
+
+					recentCount, err := userShortLinkRepo.CountShortLinksByUserSince(user, testCase.since)
+					assert.Equal(t, nil, err)
+					assert.Equal(t, testCase.expectedRecentCount, recentCount)
+				})
+		})
+	}
+}
+
 func insertUserShortLinkTableRows(
 	t *testing.T,
 	sqlDB *sql.DB,
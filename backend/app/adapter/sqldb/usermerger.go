@@ -0,0 +1,186 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/adapter/sqldb/table"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ repository.UserMerger = (*UserMergerSQL)(nil)
+
+// UserMergerSQL merges two users in the SQL database within a single
+// transaction.
+type UserMergerSQL struct {
+	db *sql.DB
+}
+
+// MergeUser moves the short links, apps, roles and external accounts of the
+// source user to the target user and deletes the source user together with
+// its sessions. The roles are moved the same way RoleAssignmentSQL grants and
+// revokes them, so that every change is recorded in role_change table on
+// behalf of the target user.
+func (u UserMergerSQL) MergeUser(sourceUserID string, targetUserID string, mergedAt time.Time) error {
+	if sourceUserID == targetUserID {
+		return fmt.Errorf("can't merge user %s into itself", sourceUserID)
+	}
+
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range moveUserStatements() {
+		_, err = tx.Exec(statement, sourceUserID, targetUserID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = moveRoles(tx, sourceUserID, targetUserID, mergedAt)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, statement := range deleteUserStatements() {
+		_, err = tx.Exec(statement, sourceUserID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	res, err := tx.Exec(deleteUserRowsStatement(table.User.TableName, table.User.ColumnID), sourceUserID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if rowsAffected < 1 {
+		tx.Rollback()
+		return repository.ErrEntryNotFound(
+			fmt.Sprintf("user %s not found", sourceUserID),
+		)
+	}
+	return tx.Commit()
+}
+
+// moveUserStatements moves the rows referencing the source user, $1, to the
+// target user, $2.
+func moveUserStatements() []string {
+	return []string{
+		moveUserStatement(table.UserShortLink.TableName, table.UserShortLink.ColumnUserID),
+		moveUserStatement(table.UserShortLinkCreation.TableName, table.UserShortLinkCreation.ColumnUserID),
+		moveUserStatement(table.App.TableName, table.App.ColumnOwnerID),
+		moveUserStatement(table.GithubSSO.TableName, table.GithubSSO.ColumnShortUserID),
+		moveUserStatement(table.FacebookSSO.TableName, table.FacebookSSO.ColumnShortUserID),
+		moveUserStatement(table.GoogleSSO.TableName, table.GoogleSSO.ColumnShortUserID),
+		moveUserStatement(table.SSOAccount.TableName, table.SSOAccount.ColumnShortUserID),
+	}
+}
+
+// moveRoles grants the roles of the source user to the target user and
+// revokes them from the source user, recording each change. Roles the target
+// user already has are only revoked from the source user.
+func moveRoles(tx *sql.Tx, sourceUserID string, targetUserID string, movedAt time.Time) error {
+	query := fmt.Sprintf(`
+SELECT "%s"
+FROM "%s"
+WHERE "%s"=$1
+FOR UPDATE;
+`,
+		table.UserRole.ColumnRole,
+		table.UserRole.TableName,
+		table.UserRole.ColumnUserID,
+	)
+	rows, err := tx.Query(query, sourceUserID)
+	if err != nil {
+		return err
+	}
+	var roles []string
+	for rows.Next() {
+		var role string
+		err = rows.Scan(&role)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		roles = append(roles, role)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		err = grantRole(tx, entity.RoleChange{
+			ActorID:   targetUserID,
+			UserID:    targetUserID,
+			Role:      role,
+			Action:    entity.RoleGranted,
+			CreatedAt: movedAt,
+		})
+		var exists repository.ErrEntryExists
+		if err != nil && !errors.As(err, &exists) {
+			return err
+		}
+
+		err = revokeRole(tx, entity.RoleChange{
+			ActorID:   targetUserID,
+			UserID:    sourceUserID,
+			Role:      role,
+			Action:    entity.RoleRevoked,
+			CreatedAt: movedAt,
+		}, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUserStatements deletes the rows of the source user, $1, which are
+// not worth keeping.
+func deleteUserStatements() []string {
+	return []string{
+		deleteUserRowsStatement(table.UserChangeLog.TableName, table.UserChangeLog.ColumnUserID),
+	}
+}
+
+func moveUserStatement(tableName string, userIDColumn string) string {
+	return fmt.Sprintf(`
+UPDATE "%s"
+SET "%s"=$2
+WHERE "%s"=$1;
+`,
+		tableName,
+		userIDColumn,
+		userIDColumn,
+	)
+}
+
+func deleteUserRowsStatement(tableName string, userIDColumn string) string {
+	return fmt.Sprintf(`
+DELETE FROM "%s"
+WHERE "%s"=$1;
+`,
+		tableName,
+		userIDColumn,
+	)
+}
+
+// NewUserMergerSQL creates UserMergerSQL.
+func NewUserMergerSQL(db *sql.DB) UserMergerSQL {
+	return UserMergerSQL{db: db}
+}
@@ -0,0 +1,76 @@
+// +build integration all
+
+package sqldb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/db/dbtest"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+func TestUserMergerSQL_MergeUser(t *testing.T) {
+	dbtest.AccessTestDB(
+		dbConnector,
+		dbMigrationTool,
+		dbMigrationRoot,
+		dbConfig,
+		func(sqlDB *sql.DB) {
+			insertUserTableRows(t, sqlDB, []userTableRow{
+				{id: "alpha", email: "alpha@example.com"},
+				{id: "beta", email: "beta@example.com"},
+			})
+			insertUserRoleRow(t, sqlDB, []userRoleTableRow{
+				{"alpha", role.Basic},
+				{"alpha", role.Admin},
+				{"beta", role.Basic},
+			})
+			userMerger := sqldb.NewUserMergerSQL(sqlDB)
+			userRoleRepo := sqldb.NewUserRoleSQL(sqlDB)
+			roleAssignmentRepo := sqldb.NewRoleAssignmentSQL(sqlDB)
+
+			mergedAt := must.Time(t, "2020-05-01T08:00:00Z")
+			err := userMerger.MergeUser("alpha", "beta", mergedAt)
+			assert.Equal(t, nil, err)
+
+			roles, err := userRoleRepo.GetRoles(entity.User{ID: "beta"})
+			assert.Equal(t, nil, err)
+			assert.SameElements(t, []role.Role{role.Basic, role.Admin}, roles)
+
+			roleChanges, err := roleAssignmentRepo.GetRoleChanges("beta")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{
+				{
+					ActorID:   "beta",
+					UserID:    "beta",
+					Role:      string(role.Admin),
+					Action:    entity.RoleGranted,
+					CreatedAt: mergedAt,
+				},
+			}, roleChanges)
+
+			roleChanges, err = roleAssignmentRepo.GetRoleChanges("alpha")
+			assert.Equal(t, nil, err)
+			assert.SameElements(t, []entity.RoleChange{
+				{
+					ActorID:   "beta",
+					UserID:    "alpha",
+					Role:      string(role.Basic),
+					Action:    entity.RoleRevoked,
+					CreatedAt: mergedAt,
+				},
+				{
+					ActorID:   "beta",
+					UserID:    "alpha",
+					Role:      string(role.Admin),
+					Action:    entity.RoleRevoked,
+					CreatedAt: mergedAt,
+				},
+			}, roleChanges)
+		})
+}
@@ -7,6 +7,15 @@ import (
 	"github.com/short-d/app/fw/env"
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/security"
+	"github.com/short-d/short/backend/app/adapter/oidc"
+	"github.com/short-d/short/backend/app/adapter/scraper"
+	"github.com/short-d/short/backend/app/adapter/smtp"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/validator"
 	"github.com/short-d/short/backend/dep"
 	"github.com/short-d/short/backend/dep/provider"
 )
@@ -26,11 +35,16 @@ type ServiceConfig struct {
 	GoogleClientID       string
 	GoogleClientSecret   string
 	GoogleRedirectURI    string
+	OIDCConfig           oidc.Config
+	SMTPConfig           smtp.Config
+	EmailSignInURL       string
 	JwtSecret            string
 	WebFrontendURL       string
 	GraphQLAPIPort       int
 	HTTPAPIPort          int
 	GRPCAPIPort          int
+	GRPCEnableReflection bool
+	GRPCHealthInterval   time.Duration
 	EnableEncryption     bool
 	CertFilePath         string
 	KeyFilePath          string
@@ -38,6 +52,7 @@ type ServiceConfig struct {
 	KgsHostname          string
 	KgsPort              int
 	AuthTokenLifetime    time.Duration
+	RefreshTokenLifetime time.Duration
 	SearchTimeout        time.Duration
 	SwaggerUIDir         string
 	OpenAPISpecPath      string
@@ -47,6 +62,19 @@ type ServiceConfig struct {
 	SegmentAPIKey        string
 	IPStackAPIKey        string
 	GoogleAPIKey         string
+	AliasPolicy          normalizer.AliasPolicy
+	AliasWordListPath    string
+	AccessPolicyPath     string
+	LongLinkPolicy       validator.LongLinkPolicy
+	RedirectPolicy       shortlink.RedirectPolicy
+	LinkHealthPolicy     shortlink.HealthPolicy
+	LinkProbeTimeout     time.Duration
+	LinkHealthInterval   time.Duration
+	ScrapePolicy         shortlink.ScrapePolicy
+	ScrapeLimit          scraper.Limit
+	RateLimitPolicy      ratelimit.Policy
+	QuotaPolicy          quota.Policy
+	TwoFactorPolicy      rbac.TwoFactorPolicy
 }
 
 // Start launches the GraphQL & HTTP APIs
@@ -90,10 +118,22 @@ func Start(
 		kgsBufferSize,
 		kgsRPCConfig,
 		provider.TokenValidDuration(config.AuthTokenLifetime),
+		provider.RefreshTokenValidDuration(config.RefreshTokenLifetime),
 		dataDogAPIKey,
 		segmentAPIKey,
 		ipStackAPIKey,
 		googleAPIKey,
+		config.AliasPolicy,
+		provider.AliasWordListPath(config.AliasWordListPath),
+		provider.AccessPolicyPath(config.AccessPolicyPath),
+		config.LongLinkPolicy,
+		config.RedirectPolicy,
+		config.ScrapePolicy,
+		config.ScrapeLimit,
+		config.RateLimitPolicy,
+		config.QuotaPolicy,
+		config.TwoFactorPolicy,
+		config.OIDCConfig,
 	)
 	if err != nil {
 		panic(err)
@@ -114,17 +154,32 @@ func Start(
 		provider.GoogleClientID(config.GoogleClientID),
 		provider.GoogleClientSecret(config.GoogleClientSecret),
 		provider.GoogleRedirectURI(config.GoogleRedirectURI),
+		config.OIDCConfig,
+		config.SMTPConfig,
+		provider.EmailSignInCallbackURL(config.EmailSignInURL),
 		provider.JwtSecret(config.JwtSecret),
 		kgsBufferSize,
 		kgsRPCConfig,
 		provider.WebFrontendURL(config.WebFrontendURL),
 		provider.TokenValidDuration(config.AuthTokenLifetime),
+		provider.RefreshTokenValidDuration(config.RefreshTokenLifetime),
 		provider.SearchTimeout(config.SearchTimeout),
 		provider.SwaggerUIDir(config.SwaggerUIDir),
 		provider.OpenAPISpecPath(config.OpenAPISpecPath),
 		dataDogAPIKey,
 		segmentAPIKey,
 		ipStackAPIKey,
+		googleAPIKey,
+		config.AliasPolicy,
+		provider.AliasWordListPath(config.AliasWordListPath),
+		provider.AccessPolicyPath(config.AccessPolicyPath),
+		config.LongLinkPolicy,
+		config.RedirectPolicy,
+		config.ScrapePolicy,
+		config.ScrapeLimit,
+		config.RateLimitPolicy,
+		config.QuotaPolicy,
+		config.TwoFactorPolicy,
 	)
 	if err != nil {
 		panic(err)
@@ -132,6 +187,27 @@ func Start(
 
 	httpAPI.StartAsync(config.HTTPAPIPort)
 
+	linkHealthJob, err := dep.InjectLinkHealthJob(
+		env.Runtime(config.Runtime),
+		provider.LogPrefix(config.LogPrefix),
+		config.LogLevel,
+		sqlDB,
+		kgsBufferSize,
+		kgsRPCConfig,
+		dataDogAPIKey,
+		segmentAPIKey,
+		ipStackAPIKey,
+		config.AliasPolicy,
+		config.LinkHealthPolicy,
+		provider.LinkProbeTimeout(config.LinkProbeTimeout),
+		provider.LinkHealthRunInterval(config.LinkHealthInterval),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	linkHealthJob.StartAsync()
+
 	gRPCService, err := dep.InjectGRPCService(
 		env.Runtime(config.Runtime),
 		provider.LogPrefix(config.LogPrefix),
@@ -142,7 +218,25 @@ func Start(
 			CertificateFilePath: config.CertFilePath,
 			KeyFilePath:         config.KeyFilePath,
 		},
+		provider.EnableGRPCReflection(config.GRPCEnableReflection),
+		provider.GRPCHealthCheckInterval(config.GRPCHealthInterval),
+		provider.JwtSecret(config.JwtSecret),
+		kgsBufferSize,
+		kgsRPCConfig,
+		provider.TokenValidDuration(config.AuthTokenLifetime),
+		provider.RefreshTokenValidDuration(config.RefreshTokenLifetime),
 		dataDogAPIKey,
+		segmentAPIKey,
+		ipStackAPIKey,
+		googleAPIKey,
+		config.AliasPolicy,
+		provider.AliasWordListPath(config.AliasWordListPath),
+		provider.AccessPolicyPath(config.AccessPolicyPath),
+		config.LongLinkPolicy,
+		config.RedirectPolicy,
+		config.ScrapePolicy,
+		config.ScrapeLimit,
+		config.QuotaPolicy,
 	)
 	if err != nil {
 		panic(err)
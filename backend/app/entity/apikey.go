@@ -9,6 +9,17 @@ type APIKey struct {
 	Key        string
 	IsDisabled bool
 	CreatedAt  time.Time
+	ExpireAt   *time.Time
+	LastUsedAt *time.Time
+	Scopes     []string
+}
+
+// IsExpired checks whether the APIKey can no longer be used at the given time.
+func (a APIKey) IsExpired(now time.Time) bool {
+	if a.ExpireAt == nil {
+		return false
+	}
+	return !now.Before(*a.ExpireAt)
 }
 
 // APIKeyInput represents APIKey with all fields as optional, with some of which having default values
@@ -17,6 +28,8 @@ type APIKeyInput struct {
 	Key        *string
 	IsDisabled *bool
 	CreatedAt  *time.Time
+	ExpireAt   *time.Time
+	Scopes     []string
 }
 
 // GetAppID fetches AppID for APIKeyInput with default value.
@@ -6,5 +6,6 @@ import "time"
 type App struct {
 	ID        string
 	Name      string
+	OwnerID   string
 	CreatedAt time.Time
 }
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// EmailSignInToken represents a one-time token emailed to an user to sign in
+// without an external identity provider.
+type EmailSignInToken struct {
+	ID        string
+	Email     string
+	CreatedAt time.Time
+	ExpireAt  time.Time
+	// UsedAt is set once the token is redeemed. A token can only be redeemed
+	// once.
+	UsedAt *time.Time
+}
@@ -0,0 +1,8 @@
+package entity
+
+// LinkedAccount represents an account of an identity provider which the user
+// can sign in with.
+type LinkedAccount struct {
+	Provider  string
+	SSOUserID string
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// RoleChangeAction represents how the roles of an user are changed.
+type RoleChangeAction string
+
+const (
+	// RoleGranted indicates a role is granted to an user.
+	RoleGranted RoleChangeAction = "granted"
+	// RoleRevoked indicates a role is revoked from an user.
+	RoleRevoked RoleChangeAction = "revoked"
+)
+
+// RoleChange records an actor granting or revoking a role of an user, so that
+// every change of privileges can be audited.
+type RoleChange struct {
+	ActorID   string
+	UserID    string
+	Role      string
+	Action    RoleChangeAction
+	CreatedAt time.Time
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Device represents the client from which an user signs in.
+type Device struct {
+	ClientIP  string
+	Location  string
+	UserAgent string
+}
+
+// Session represents an user signed in on a device. Revoking a session
+// invalidates all the tokens issued for it.
+type Session struct {
+	ID     string
+	UserID string
+	// RefreshTokenGeneration increases every time the refresh token is rotated.
+	// Only the refresh token of the latest generation is accepted.
+	RefreshTokenGeneration int
+	// IdentityProvider is the external service the user signed in with, such
+	// as github.
+	IdentityProvider string
+	Device           Device
+	CreatedAt        time.Time
+	ExpireAt         time.Time
+	RevokedAt        *time.Time
+}
+
+// IsActive checks whether the session can still be used at the given time.
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpireAt)
+}
@@ -16,6 +16,37 @@ type ShortLink struct {
 	UpdatedAt     *time.Time
 	OpenGraphTags metatag.OpenGraph
 	TwitterTags   metatag.Twitter
+	Health        LinkHealth
+}
+
+// LinkHealth represents the result of the latest reachability check of the
+// long link. StatusCode is nil when the destination could not be reached.
+type LinkHealth struct {
+	StatusCode *int
+	CheckedAt  *time.Time
+}
+
+// IsChecked determines whether the long link has been checked before.
+func (l LinkHealth) IsChecked() bool {
+	return l.CheckedAt != nil
+}
+
+// IsBroken determines whether the long link failed its latest check.
+func (l LinkHealth) IsBroken() bool {
+	if !l.IsChecked() {
+		return false
+	}
+	if l.StatusCode == nil {
+		return true
+	}
+	return *l.StatusCode >= 400
+}
+
+// ShortLinkAnalytics represents how often a short link has been visited.
+// LastVisitedAt is nil when the short link has never been visited.
+type ShortLinkAnalytics struct {
+	VisitCount    int
+	LastVisitedAt *time.Time
 }
 
 // ShortLinkInput represents possible ShortLink attributes for a short link.
@@ -42,3 +73,11 @@ func (s *ShortLinkInput) GetCustomAlias(defaultVal string) string {
 	}
 	return *s.CustomAlias
 }
+
+// GetExpireAt fetches ExpireAt for ShortLinkInput with default value.
+func (s *ShortLinkInput) GetExpireAt(defaultVal *time.Time) *time.Time {
+	if s.ExpireAt == nil {
+		return defaultVal
+	}
+	return s.ExpireAt
+}
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// TwoFactor represents the time-based one-time password (TOTP) generator an
+// user enrolled as the second factor to sign in.
+type TwoFactor struct {
+	UserID string
+	// Secret is the base32 encoded key shared with the authenticator app.
+	Secret string
+	// LastTimeStep is the time step of the latest accepted password. Only
+	// passwords of later time steps are accepted so that a password can't be
+	// replayed.
+	LastTimeStep int64
+	CreatedAt    time.Time
+	// EnabledAt is set once the user confirms the enrollment with a valid
+	// password.
+	EnabledAt *time.Time
+}
+
+// IsEnabled checks whether the user confirmed the enrollment.
+func (t TwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// TwoFactorEnrollment represents what the user needs to set up the
+// authenticator app, shown only once at enrollment.
+type TwoFactorEnrollment struct {
+	Secret string
+	// ProvisioningURI is the otpauth URI rendered as a QR code for the
+	// authenticator app to scan.
+	ProvisioningURI string
+	// RecoveryCodes can each be used once in place of a password when the
+	// authenticator app is lost.
+	RecoveryCodes []string
+}
+
+// TwoFactorStatus represents whether an user signs in with a second factor.
+type TwoFactorStatus struct {
+	IsEnabled  bool
+	IsRequired bool
+	// EnforceFrom is when users required to sign in with a second factor
+	// can no longer sign in without one. It is nil when not required.
+	EnforceFrom *time.Time
+}
@@ -0,0 +1,6 @@
+package ptr
+
+// Int returns the address of an int literal.
+func Int(num int) *int {
+	return &num
+}
@@ -2,18 +2,48 @@ package authenticator
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
+// ErrInvalidRefreshToken represents the failure of issuing new tokens with a
+// refresh token which is malformed, already used or belongs to a revoked
+// session.
+type ErrInvalidRefreshToken struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidRefreshToken)(nil)
+
+func (e ErrInvalidRefreshToken) Error() string {
+	return fmt.Sprintf("invalid refresh token: %s", e.Reason)
+}
+
+// AuthTokens represents the tokens issued to a signed in user. The short lived
+// access token authenticates the user, while the refresh token can be
+// exchanged for new tokens once the access token expires.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	// RefreshTokenExpireAt is the time when the session of the refresh token
+	// expires.
+	RefreshTokenExpireAt time.Time
+}
+
 // Authenticator securely authenticates an user's identity.
 type Authenticator struct {
-	tokenizer          crypto.Tokenizer
-	timer              timer.Timer
-	tokenValidDuration time.Duration
+	tokenizer                 crypto.Tokenizer
+	timer                     timer.Timer
+	keyGen                    keygen.KeyGenerator
+	sessionRepo               repository.Session
+	tokenValidDuration        time.Duration
+	refreshTokenValidDuration time.Duration
 }
 
 func (a Authenticator) isTokenValid(payload Payload, validDuring time.Duration) bool {
@@ -38,52 +68,202 @@ func (a Authenticator) getPayload(token string) (Payload, error) {
 	return payload, nil
 }
 
-// IsSignedIn checks whether user successfully signed in
-func (a Authenticator) IsSignedIn(token string) bool {
+// getActivePayload decodes the access token and makes sure it is neither
+// expired nor issued for a revoked session.
+func (a Authenticator) getActivePayload(token string) (Payload, error) {
 	payload, err := a.getPayload(token)
 	if err != nil {
-		return false
+		return Payload{}, err
+	}
+
+	if !a.isTokenValid(payload, a.tokenValidDuration) {
+		return Payload{}, errors.New("token expired")
+	}
+
+	session, err := a.sessionRepo.GetSessionByID(payload.sessionID)
+	if err != nil {
+		return Payload{}, err
+	}
+	if session.UserID != payload.id {
+		return Payload{}, errors.New("token is not issued for the session")
+	}
+	if !session.IsActive(a.timer.Now()) {
+		return Payload{}, errors.New("session is revoked")
 	}
+	return payload, nil
+}
 
-	return a.isTokenValid(payload, a.tokenValidDuration)
+// IsSignedIn checks whether user successfully signed in
+func (a Authenticator) IsSignedIn(token string) bool {
+	_, err := a.getActivePayload(token)
+	return err == nil
 }
 
 // GetUser decodes authentication token to user data
 func (a Authenticator) GetUser(token string) (entity.User, error) {
-	payload, err := a.getPayload(token)
+	payload, err := a.getActivePayload(token)
 	if err != nil {
 		return entity.User{}, err
 	}
+	return entity.User{
+		ID: payload.id,
+	}, nil
+}
 
+// IdentifyUser returns the user the access token is issued to. It only
+// verifies the signature and the age of the token without checking whether
+// its session is still active, which is cheap enough to run before the request
+// is throttled.
+func (a Authenticator) IdentifyUser(token string) (entity.User, error) {
+	payload, err := a.getPayload(token)
+	if err != nil {
+		return entity.User{}, err
+	}
 	if !a.isTokenValid(payload, a.tokenValidDuration) {
 		return entity.User{}, errors.New("token expired")
 	}
-
-	if len(payload.id) < 1 {
-		return entity.User{}, errors.New("id can't be empty")
-	}
 	return entity.User{
 		ID: payload.id,
 	}, nil
 }
 
-// GenerateToken encodes part of user data into authentication token
-func (a Authenticator) GenerateToken(user entity.User) (string, error) {
+// SignIn starts a new session for the user on the given device and issues the
+// tokens for it.
+func (a Authenticator) SignIn(
+	user entity.User,
+	identityProvider string,
+	device entity.Device,
+) (AuthTokens, error) {
+	key, err := a.keyGen.NewKey()
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	now := a.timer.Now()
+	session := entity.Session{
+		ID:               string(key),
+		UserID:           user.ID,
+		IdentityProvider: identityProvider,
+		Device:           device,
+		CreatedAt:        now,
+		ExpireAt:         now.Add(a.refreshTokenValidDuration),
+	}
+	err = a.sessionRepo.CreateSession(session)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	return a.issueTokens(session)
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens. Each
+// refresh token can only be used once. Reusing a rotated refresh token
+// revokes the whole session since the token is likely stolen.
+func (a Authenticator) RefreshToken(refreshToken string) (AuthTokens, error) {
+	tokenPayload, err := a.tokenizer.Decode(refreshToken)
+	if err != nil {
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: err.Error()}
+	}
+
+	payload, err := fromRefreshTokenPayload(tokenPayload)
+	if err != nil {
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: err.Error()}
+	}
+
+	session, err := a.sessionRepo.GetSessionByID(payload.sessionID)
+	var notFound repository.ErrEntryNotFound
+	if errors.As(err, &notFound) {
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: "session not found"}
+	}
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	now := a.timer.Now()
+	if !session.IsActive(now) {
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: "session is no longer active"}
+	}
+
+	if payload.generation != session.RefreshTokenGeneration {
+		err = a.sessionRepo.RevokeSession(session.ID, now)
+		if err != nil {
+			return AuthTokens{}, err
+		}
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: "token is already used"}
+	}
+
+	expireAt := now.Add(a.refreshTokenValidDuration)
+	err = a.sessionRepo.RotateRefreshToken(session.ID, session.RefreshTokenGeneration, expireAt)
+	if errors.As(err, &notFound) {
+		// Another request rotated the token in the meantime.
+		return AuthTokens{}, ErrInvalidRefreshToken{Reason: "token is already used"}
+	}
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	session.RefreshTokenGeneration++
+	session.ExpireAt = expireAt
+	return a.issueTokens(session)
+}
+
+// SignOut revokes the session of the given access token.
+func (a Authenticator) SignOut(token string) error {
+	payload, err := a.getActivePayload(token)
+	if err != nil {
+		return err
+	}
+	return a.sessionRepo.RevokeSession(payload.sessionID, a.timer.Now())
+}
+
+// SignOutEverywhere revokes all the sessions of the user owning the given
+// access token.
+func (a Authenticator) SignOutEverywhere(token string) error {
+	payload, err := a.getActivePayload(token)
+	if err != nil {
+		return err
+	}
+	return a.sessionRepo.RevokeSessionsByUser(payload.id, a.timer.Now())
+}
+
+func (a Authenticator) issueTokens(session entity.Session) (AuthTokens, error) {
 	issuedAt := a.timer.Now()
-	payload := newPayload(user.ID, issuedAt)
-	tokenPayload := payload.TokenPayload()
-	return a.tokenizer.Encode(tokenPayload)
+	payload := newPayload(session.UserID, session.ID, issuedAt)
+	accessToken, err := a.tokenizer.Encode(payload.TokenPayload())
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	refresh := refreshPayload{
+		sessionID:  session.ID,
+		generation: session.RefreshTokenGeneration,
+		issuedAt:   issuedAt,
+	}
+	refreshToken, err := a.tokenizer.Encode(refresh.TokenPayload())
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	return AuthTokens{
+		AccessToken:          accessToken,
+		RefreshToken:         refreshToken,
+		RefreshTokenExpireAt: session.ExpireAt,
+	}, nil
 }
 
-// NewAuthenticator initializes authenticator with custom token valid duration
+// NewAuthenticator initializes authenticator with custom token valid durations
 func NewAuthenticator(
 	tokenizer crypto.Tokenizer,
 	timer timer.Timer,
+	keyGen keygen.KeyGenerator,
+	sessionRepo repository.Session,
 	tokenValidDuration time.Duration,
+	refreshTokenValidDuration time.Duration,
 ) Authenticator {
 	return Authenticator{
-		tokenizer:          tokenizer,
-		timer:              timer,
-		tokenValidDuration: tokenValidDuration,
+		tokenizer:                 tokenizer,
+		timer:                     timer,
+		keyGen:                    keyGen,
+		sessionRepo:               sessionRepo,
+		tokenValidDuration:        tokenValidDuration,
+		refreshTokenValidDuration: refreshTokenValidDuration,
 	}
 }
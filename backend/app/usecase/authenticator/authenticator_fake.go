@@ -1,15 +1,34 @@
 package authenticator
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
-// NewAuthenticatorFake creates fake authenticator for easy testing.
+const fakeSessionCount = 100
+
+// NewAuthenticatorFake creates fake authenticator for easy testing. It keeps
+// sessions in memory and supports up to 100 sign ins.
 func NewAuthenticatorFake(current time.Time, validPeriod time.Duration) Authenticator {
 	tokenizer := crypto.NewTokenizerFake()
 	tm := timer.NewStub(current)
-	return NewAuthenticator(tokenizer, tm, validPeriod)
+
+	keys := make([]keygen.Key, fakeSessionCount)
+	for idx := range keys {
+		keys[idx] = keygen.Key(fmt.Sprintf("session%d", idx))
+	}
+	keyFetcher := keygen.NewKeyFetcherFake(keys)
+	keyGen, err := keygen.NewKeyGenerator(fakeSessionCount, &keyFetcher)
+	if err != nil {
+		panic(err)
+	}
+
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	return NewAuthenticator(tokenizer, tm, keyGen, &sessionRepo, validPeriod, 2*validPeriod)
 }
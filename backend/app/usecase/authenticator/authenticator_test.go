@@ -3,6 +3,7 @@
 package authenticator
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -10,33 +11,61 @@ import (
 	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
-func TestAuthenticator_GenerateToken(t *testing.T) {
+func TestAuthenticator_SignIn(t *testing.T) {
 	t.Parallel()
 	tokenizer := crypto.NewTokenizerFake()
 	expIssuedAt := time.Now()
 	tm := timer.NewStub(expIssuedAt)
-	authenticator := NewAuthenticator(tokenizer, tm, 2*time.Millisecond)
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	authenticator := NewAuthenticator(
+		tokenizer, tm, newKeyGen(t, "session1"), &sessionRepo, time.Hour, 24*time.Hour,
+	)
 
 	expUser := entity.User{
 		ID: "alpha",
 	}
-	token, err := authenticator.GenerateToken(expUser)
+	device := entity.Device{
+		ClientIP:  "10.0.0.1",
+		Location:  "San Francisco, California, United States",
+		UserAgent: "Mozilla/5.0",
+	}
+	authTokens, err := authenticator.SignIn(expUser, "github", device)
 	assert.Equal(t, nil, err)
 
-	tokenPayload, err := tokenizer.Decode(token)
+	tokenPayload, err := tokenizer.Decode(authTokens.AccessToken)
 	assert.Equal(t, nil, err)
 
 	assert.Equal(t, expUser.ID, tokenPayload["id"])
+	assert.Equal(t, "session1", tokenPayload["session_id"])
 
 	expIssuedAtStr := expIssuedAt.Format(time.RFC3339Nano)
 	assert.Equal(t, expIssuedAtStr, tokenPayload["issued_at"])
+
+	session, err := sessionRepo.GetSessionByID("session1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, entity.Session{
+		ID:               "session1",
+		UserID:           "alpha",
+		IdentityProvider: "github",
+		Device:           device,
+		CreatedAt:        expIssuedAt,
+		ExpireAt:         expIssuedAt.Add(24 * time.Hour),
+	}, session)
+
+	assert.Equal(t, expIssuedAt.Add(24*time.Hour), authTokens.RefreshTokenExpireAt)
+
+	assert.Equal(t, true, authenticator.IsSignedIn(authTokens.AccessToken))
+	assert.Equal(t, false, authenticator.IsSignedIn(authTokens.RefreshToken))
 }
 
 func TestAuthenticator_IsSignedIn(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
+	sessions := newSessions(now)
 
 	testCases := []struct {
 		name               string
@@ -70,8 +99,9 @@ func TestAuthenticator_IsSignedIn(t *testing.T) {
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
-				"id":        "",
-				"issued_at": now.Format(time.RFC3339Nano),
+				"id":         "",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
 			},
 			expIsSignIn: false,
 		},
@@ -81,7 +111,8 @@ func TestAuthenticator_IsSignedIn(t *testing.T) {
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
-				"id": "alpha",
+				"id":         "alpha",
+				"session_id": "session1",
 			},
 			expIsSignIn: false,
 		},
@@ -90,20 +121,69 @@ func TestAuthenticator_IsSignedIn(t *testing.T) {
 			expIssuedAt:        now,
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(2 * time.Hour),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			expIsSignIn: false,
+		},
+		{
+			name:               "Token payload without session ID",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
 				"id":        "alpha",
 				"issued_at": now.Format(time.RFC3339Nano),
 			},
 			expIsSignIn: false,
 		},
+		{
+			name:               "Session not found",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "unknown",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			expIsSignIn: false,
+		},
+		{
+			name:               "Session revoked",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session2",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			expIsSignIn: false,
+		},
+		{
+			name:               "Session of another user",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session3",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			expIsSignIn: false,
+		},
 		{
 			name:               "Token valid",
 			expIssuedAt:        now,
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
-				"id":        "alpha",
-				"issued_at": now.Format(time.RFC3339Nano),
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
 			},
 			expIsSignIn: true,
 		},
@@ -115,7 +195,7 @@ func TestAuthenticator_IsSignedIn(t *testing.T) {
 			t.Parallel()
 			tokenizer := crypto.NewTokenizerFake()
 			tm := timer.NewStub(testCase.currentTime)
-			authenticator := NewAuthenticator(tokenizer, tm, testCase.tokenValidDuration)
+			authenticator := newAuthenticator(t, tokenizer, tm, testCase.tokenValidDuration, sessions)
 
 			token, err := tokenizer.Encode(testCase.tokenPayload)
 			assert.Equal(t, nil, err)
@@ -128,6 +208,7 @@ func TestAuthenticator_IsSignedIn(t *testing.T) {
 func TestAuthenticator_GetUser(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
+	sessions := newSessions(now)
 
 	testCases := []struct {
 		name               string
@@ -164,7 +245,8 @@ func TestAuthenticator_GetUser(t *testing.T) {
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
-				"id": "alpha",
+				"id":         "alpha",
+				"session_id": "session1",
 			},
 			hasErr:  true,
 			expUser: entity.User{},
@@ -175,8 +257,9 @@ func TestAuthenticator_GetUser(t *testing.T) {
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(2 * time.Hour),
 			tokenPayload: map[string]interface{}{
-				"id":        "alpha",
-				"issued_at": now.Format(time.RFC3339Nano),
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
 			},
 			hasErr:  true,
 			expUser: entity.User{},
@@ -187,14 +270,15 @@ func TestAuthenticator_GetUser(t *testing.T) {
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
 			tokenPayload: map[string]interface{}{
-				"id":        "",
-				"issued_at": now.Format(time.RFC3339Nano),
+				"id":         "",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
 			},
 			hasErr:  true,
 			expUser: entity.User{},
 		},
 		{
-			name:               "Token valid with correct ID",
+			name:               "Token payload without session ID",
 			expIssuedAt:        now,
 			tokenValidDuration: time.Hour,
 			currentTime:        now.Add(30 * time.Minute),
@@ -202,6 +286,58 @@ func TestAuthenticator_GetUser(t *testing.T) {
 				"id":        "alpha",
 				"issued_at": now.Format(time.RFC3339Nano),
 			},
+			hasErr:  true,
+			expUser: entity.User{},
+		},
+		{
+			name:               "Session not found",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "unknown",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr:  true,
+			expUser: entity.User{},
+		},
+		{
+			name:               "Session revoked",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session2",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr:  true,
+			expUser: entity.User{},
+		},
+		{
+			name:               "Session of another user",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session3",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr:  true,
+			expUser: entity.User{},
+		},
+		{
+			name:               "Token valid with correct ID",
+			expIssuedAt:        now,
+			tokenValidDuration: time.Hour,
+			currentTime:        now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
 			hasErr: false,
 			expUser: entity.User{
 				ID: "alpha",
@@ -215,7 +351,7 @@ func TestAuthenticator_GetUser(t *testing.T) {
 			t.Parallel()
 			tokenizer := crypto.NewTokenizerFake()
 			tm := timer.NewStub(testCase.currentTime)
-			authenticator := NewAuthenticator(tokenizer, tm, testCase.tokenValidDuration)
+			authenticator := newAuthenticator(t, tokenizer, tm, testCase.tokenValidDuration, sessions)
 
 			token, err := tokenizer.Encode(testCase.tokenPayload)
 			assert.Equal(t, nil, err)
@@ -228,3 +364,307 @@ func TestAuthenticator_GetUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticator_IdentifyUser(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	sessions := newSessions(now)
+
+	testCases := []struct {
+		name         string
+		currentTime  time.Time
+		tokenPayload crypto.TokenPayload
+		hasErr       bool
+		expUser      entity.User
+	}{
+		{
+			name:         "Token payload empty",
+			currentTime:  now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{},
+			hasErr:       true,
+		},
+		{
+			name:        "Token expired",
+			currentTime: now.Add(2 * time.Hour),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name:        "Session revoked",
+			currentTime: now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session2",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr:  false,
+			expUser: entity.User{ID: "alpha"},
+		},
+		{
+			name:        "Session not found",
+			currentTime: now.Add(30 * time.Minute),
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "unknown",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr:  false,
+			expUser: entity.User{ID: "alpha"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			tokenizer := crypto.NewTokenizerFake()
+			tm := timer.NewStub(testCase.currentTime)
+			authenticator := newAuthenticator(t, tokenizer, tm, time.Hour, sessions)
+
+			token, err := tokenizer.Encode(testCase.tokenPayload)
+			assert.Equal(t, nil, err)
+			gotUser, err := authenticator.IdentifyUser(token)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expUser, gotUser)
+		})
+	}
+}
+
+func TestAuthenticator_RefreshToken(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	sessions := newSessions(now)
+
+	testCases := []struct {
+		name         string
+		tokenPayload crypto.TokenPayload
+		hasErr       bool
+		expUser      entity.User
+	}{
+		{
+			name:         "Token payload empty",
+			tokenPayload: map[string]interface{}{},
+			hasErr:       true,
+		},
+		{
+			name: "Access token",
+			tokenPayload: map[string]interface{}{
+				"id":         "alpha",
+				"session_id": "session1",
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name: "Session not found",
+			tokenPayload: map[string]interface{}{
+				"session_id": "unknown",
+				"generation": 0,
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name: "Session revoked",
+			tokenPayload: map[string]interface{}{
+				"session_id": "session2",
+				"generation": 0,
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name: "Session expired",
+			tokenPayload: map[string]interface{}{
+				"session_id": "session4",
+				"generation": 0,
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name: "Token already used",
+			tokenPayload: map[string]interface{}{
+				"session_id": "session3",
+				"generation": 1,
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: true,
+		},
+		{
+			name: "Token valid",
+			tokenPayload: map[string]interface{}{
+				"session_id": "session3",
+				"generation": 2,
+				"issued_at":  now.Format(time.RFC3339Nano),
+			},
+			hasErr: false,
+			expUser: entity.User{
+				ID: "beta",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			tokenizer := crypto.NewTokenizerFake()
+			tm := timer.NewStub(now.Add(30 * time.Minute))
+			authenticator := newAuthenticator(t, tokenizer, tm, time.Hour, sessions)
+
+			token, err := tokenizer.Encode(testCase.tokenPayload)
+			assert.Equal(t, nil, err)
+			authTokens, err := authenticator.RefreshToken(token)
+			if testCase.hasErr {
+				var invalidToken ErrInvalidRefreshToken
+				assert.Equal(t, true, errors.As(err, &invalidToken))
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			gotUser, err := authenticator.GetUser(authTokens.AccessToken)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expUser, gotUser)
+		})
+	}
+}
+
+func TestAuthenticator_RefreshTokenReused(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tokenizer := crypto.NewTokenizerFake()
+	tm := timer.NewStub(now)
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	authenticator := NewAuthenticator(
+		tokenizer, tm, newKeyGen(t, "session1"), &sessionRepo, time.Hour, 24*time.Hour,
+	)
+
+	firstTokens, err := authenticator.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	secondTokens, err := authenticator.RefreshToken(firstTokens.RefreshToken)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, authenticator.IsSignedIn(secondTokens.AccessToken))
+
+	_, err = authenticator.RefreshToken(firstTokens.RefreshToken)
+	assert.NotEqual(t, nil, err)
+
+	assert.Equal(t, false, authenticator.IsSignedIn(secondTokens.AccessToken))
+	_, err = authenticator.RefreshToken(secondTokens.RefreshToken)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestAuthenticator_SignOut(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tokenizer := crypto.NewTokenizerFake()
+	tm := timer.NewStub(now)
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	authenticator := NewAuthenticator(
+		tokenizer, tm, newKeyGen(t, "session1", "session2"), &sessionRepo, time.Hour, 24*time.Hour,
+	)
+
+	laptopTokens, err := authenticator.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	phoneTokens, err := authenticator.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	err = authenticator.SignOut(laptopTokens.AccessToken)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, false, authenticator.IsSignedIn(laptopTokens.AccessToken))
+	assert.Equal(t, true, authenticator.IsSignedIn(phoneTokens.AccessToken))
+
+	_, err = authenticator.RefreshToken(laptopTokens.RefreshToken)
+	assert.NotEqual(t, nil, err)
+
+	err = authenticator.SignOut(laptopTokens.AccessToken)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestAuthenticator_SignOutEverywhere(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tokenizer := crypto.NewTokenizerFake()
+	tm := timer.NewStub(now)
+	sessionRepo := repository.NewSessionFake([]entity.Session{})
+	authenticator := NewAuthenticator(
+		tokenizer,
+		tm,
+		newKeyGen(t, "session1", "session2", "session3"),
+		&sessionRepo,
+		time.Hour,
+		24*time.Hour,
+	)
+
+	laptopTokens, err := authenticator.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	phoneTokens, err := authenticator.SignIn(entity.User{ID: "alpha"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+	otherUserTokens, err := authenticator.SignIn(entity.User{ID: "beta"}, "github", entity.Device{})
+	assert.Equal(t, nil, err)
+
+	err = authenticator.SignOutEverywhere(phoneTokens.AccessToken)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, false, authenticator.IsSignedIn(laptopTokens.AccessToken))
+	assert.Equal(t, false, authenticator.IsSignedIn(phoneTokens.AccessToken))
+	assert.Equal(t, true, authenticator.IsSignedIn(otherUserTokens.AccessToken))
+}
+
+func newSessions(now time.Time) []entity.Session {
+	revokedAt := now.Add(-time.Minute)
+	return []entity.Session{
+		{
+			ID:       "session1",
+			UserID:   "alpha",
+			ExpireAt: now.Add(24 * time.Hour),
+		},
+		{
+			ID:        "session2",
+			UserID:    "alpha",
+			ExpireAt:  now.Add(24 * time.Hour),
+			RevokedAt: &revokedAt,
+		},
+		{
+			ID:                     "session3",
+			UserID:                 "beta",
+			RefreshTokenGeneration: 2,
+			ExpireAt:               now.Add(24 * time.Hour),
+		},
+		{
+			ID:       "session4",
+			UserID:   "alpha",
+			ExpireAt: now.Add(-time.Hour),
+		},
+	}
+}
+
+func newAuthenticator(
+	t *testing.T,
+	tokenizer crypto.Tokenizer,
+	tm timer.Timer,
+	tokenValidDuration time.Duration,
+	sessions []entity.Session,
+) Authenticator {
+	sessionRepo := repository.NewSessionFake(append([]entity.Session{}, sessions...))
+	return NewAuthenticator(
+		tokenizer, tm, newKeyGen(t), &sessionRepo, tokenValidDuration, 24*time.Hour,
+	)
+}
+
+func newKeyGen(t *testing.T, keys ...keygen.Key) keygen.KeyGenerator {
+	keyFetcher := keygen.NewKeyFetcherFake(keys)
+	keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+	assert.Equal(t, nil, err)
+	return keyGen
+}
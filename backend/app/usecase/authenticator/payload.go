@@ -9,22 +9,25 @@ import (
 
 // Payload represents the metadata encoded in the authentication token.
 type Payload struct {
-	id       string
-	issuedAt time.Time
+	id        string
+	sessionID string
+	issuedAt  time.Time
 }
 
 // TokenPayload retrieves key-value pairs representation of the payload.
 func (p Payload) TokenPayload() crypto.TokenPayload {
 	return map[string]interface{}{
-		"id":        p.id,
-		"issued_at": p.issuedAt,
+		"id":         p.id,
+		"session_id": p.sessionID,
+		"issued_at":  p.issuedAt,
 	}
 }
 
-func newPayload(id string, issuedAt time.Time) Payload {
+func newPayload(id string, sessionID string, issuedAt time.Time) Payload {
 	return Payload{
-		id:       id,
-		issuedAt: issuedAt,
+		id:        id,
+		sessionID: sessionID,
+		issuedAt:  issuedAt,
 	}
 }
 
@@ -37,13 +40,12 @@ func fromTokenPayload(tokenPayload crypto.TokenPayload) (Payload, error) {
 		return payload, errors.New("expect payload to contain id")
 	}
 
-	issuedAtJSON := tokenPayload["issued_at"]
-	var issuedAtStr string
-	if issuedAtStr, ok = issuedAtJSON.(string); !ok {
-		return payload, errors.New("expect payload to contain issued_at")
+	sessionID := tokenPayload["session_id"]
+	if payload.sessionID, ok = sessionID.(string); !ok {
+		return payload, errors.New("expect payload to contain session_id")
 	}
 
-	issuedAt, err := time.Parse(time.RFC3339, issuedAtStr)
+	issuedAt, err := parseIssuedAt(tokenPayload)
 	if err != nil {
 		return payload, err
 	}
@@ -51,3 +53,51 @@ func fromTokenPayload(tokenPayload crypto.TokenPayload) (Payload, error) {
 
 	return payload, nil
 }
+
+// refreshPayload represents the metadata encoded in the refresh token.
+type refreshPayload struct {
+	sessionID  string
+	generation int
+	issuedAt   time.Time
+}
+
+func (r refreshPayload) TokenPayload() crypto.TokenPayload {
+	return map[string]interface{}{
+		"session_id": r.sessionID,
+		"generation": r.generation,
+		"issued_at":  r.issuedAt,
+	}
+}
+
+func fromRefreshTokenPayload(tokenPayload crypto.TokenPayload) (refreshPayload, error) {
+	payload := refreshPayload{}
+	var ok bool
+
+	sessionID := tokenPayload["session_id"]
+	if payload.sessionID, ok = sessionID.(string); !ok {
+		return payload, errors.New("expect payload to contain session_id")
+	}
+
+	// JSON numbers are decoded as float64.
+	generation, ok := tokenPayload["generation"].(float64)
+	if !ok {
+		return payload, errors.New("expect payload to contain generation")
+	}
+	payload.generation = int(generation)
+
+	issuedAt, err := parseIssuedAt(tokenPayload)
+	if err != nil {
+		return payload, err
+	}
+	payload.issuedAt = issuedAt
+
+	return payload, nil
+}
+
+func parseIssuedAt(tokenPayload crypto.TokenPayload) (time.Time, error) {
+	issuedAtStr, ok := tokenPayload["issued_at"].(string)
+	if !ok {
+		return time.Time{}, errors.New("expect payload to contain issued_at")
+	}
+	return time.Parse(time.RFC3339, issuedAtStr)
+}
@@ -0,0 +1,38 @@
+package scope
+
+// Scope represents a group of operations an API key is allowed to perform.
+type Scope string
+
+const (
+	// ShortLinkRead allows API keys to fetch and list the short links of the
+	// app.
+	ShortLinkRead Scope = "shortlink:read"
+	// ShortLinkWrite allows API keys to create, update and delete the short
+	// links of the app.
+	ShortLinkWrite Scope = "shortlink:write"
+)
+
+var scopes = []Scope{
+	ShortLinkRead,
+	ShortLinkWrite,
+}
+
+// IsValid checks whether the given scope is supported.
+func IsValid(scope Scope) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains checks whether the required scope is one of the granted scopes.
+func Contains(granted []string, required Scope) bool {
+	for _, s := range granted {
+		if Scope(s) == required {
+			return true
+		}
+	}
+	return false
+}
@@ -3,16 +3,59 @@ package authenticator
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authenticator/payload"
+	"github.com/short-d/short/backend/app/usecase/authenticator/scope"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
 	"github.com/short-d/short/backend/app/usecase/keygen"
 	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
+// ErrUnauthorizedAction represents the failure of performing an action which
+// the user is not permitted to.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrMissingScope represents the failure of performing an operation outside
+// of the scopes granted to an API key.
+type ErrMissingScope struct {
+	Scope scope.Scope
+}
+
+var _ error = (*ErrMissingScope)(nil)
+
+func (e ErrMissingScope) Error() string {
+	return fmt.Sprintf("api key is missing scope %s", e.Scope)
+}
+
+// ErrInvalidScope represents a scope which is not supported.
+type ErrInvalidScope struct {
+	Scope scope.Scope
+}
+
+var _ error = (*ErrInvalidScope)(nil)
+
+func (e ErrInvalidScope) Error() string {
+	return fmt.Sprintf("scope %s is not supported", e.Scope)
+}
+
+// lastUsedAtPrecision is the minimum time between two updates of when an API
+// key was last used, so that busy keys don't cause a write on every request.
+const lastUsedAtPrecision = time.Minute
+
 // ThirdPartyApp authenticates the identity of a third party application.
 type ThirdPartyApp struct {
 	authorizer authorizer.Authorizer
@@ -21,45 +64,216 @@ type ThirdPartyApp struct {
 	timer      timer.Timer
 	apiKeyRepo repository.APIKey
 	appRepo    repository.App
+	logger     logger.Logger
 }
 
-// GetApp retrieves app information based on the credential provided.
-func (t ThirdPartyApp) GetApp(cred Credential) (entity.App, error) {
-	if cred.APIKey == nil {
-		return entity.App{}, errors.New("no credential provided")
-	}
-	apiKeyStr := *cred.APIKey
-	tokenPayload, err := t.tokenizer.Decode(apiKeyStr)
+// GetApp retrieves app information based on the credential provided. The API
+// key must be granted the required scope.
+func (t ThirdPartyApp) GetApp(cred Credential, requiredScope scope.Scope) (entity.App, error) {
+	apiKeyPayload, err := t.decodeAPIKey(cred)
 	if err != nil {
 		return entity.App{}, err
 	}
-
-	apiKeyPayload, err := payload.NewAPIKey(tokenPayload)
-	if err != nil {
-		return entity.App{}, fmt.Errorf("invalid api key: %w", err)
-	}
 	apiKey, err := t.apiKeyRepo.GetAPIKey(apiKeyPayload.AppID, apiKeyPayload.Key)
+	var entryNotFound repository.ErrEntryNotFound
+	if errors.As(err, &entryNotFound) {
+		return entity.App{}, errors.New("invalid api key: api key not found")
+	}
 	if err != nil {
 		return entity.App{}, fmt.Errorf("invalid api key: %w", err)
 	}
 	if apiKey.IsDisabled {
-		return entity.App{}, fmt.Errorf("invalid api key: %w", err)
+		return entity.App{}, errors.New("invalid api key: api key is disabled")
+	}
+
+	now := t.timer.Now()
+	if apiKey.IsExpired(now) {
+		return entity.App{}, errors.New("invalid api key: api key is expired")
 	}
+	if !scope.Contains(apiKey.Scopes, requiredScope) {
+		return entity.App{}, ErrMissingScope{Scope: requiredScope}
+	}
+
+	t.updateLastUsedAt(apiKey, now)
 	return t.appRepo.GetAppByID(apiKey.AppID)
 }
 
-// GenerateAPIKey generates a new API key for the given app.
-func (t ThirdPartyApp) GenerateAPIKey(user entity.User, app entity.App) (string, error) {
-	canGenerateKey, err := t.authorizer.CanGenerateAPIKey(user)
+// updateLastUsedAt records when the API key was used at most once per
+// lastUsedAtPrecision. Failing to record it doesn't fail the request.
+func (t ThirdPartyApp) updateLastUsedAt(apiKey entity.APIKey, now time.Time) {
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < lastUsedAtPrecision {
+		return
+	}
+	err := t.apiKeyRepo.UpdateLastUsedAt(apiKey.AppID, apiKey.Key, now)
+	if err != nil {
+		t.logger.Error(fmt.Errorf(
+			"fail to update last used time of api key of app(%s): %v",
+			apiKey.AppID, err,
+		))
+	}
+}
+
+// IdentifyAPIKey returns the app ID and the key of the API key in the
+// credential. It only verifies the signature of the API key without checking
+// whether the key is still active, which is cheap enough to run before the
+// request is throttled.
+func (t ThirdPartyApp) IdentifyAPIKey(cred Credential) (entity.APIKey, error) {
+	apiKeyPayload, err := t.decodeAPIKey(cred)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	return entity.APIKey{
+		AppID: apiKeyPayload.AppID,
+		Key:   apiKeyPayload.Key,
+	}, nil
+}
+
+// GenerateAPIKey generates a new API key granted the given scopes for the app.
+// The key never expires if expireAt is nil.
+func (t ThirdPartyApp) GenerateAPIKey(
+	user entity.User,
+	app entity.App,
+	scopes []scope.Scope,
+	expireAt *time.Time,
+) (string, error) {
+	canGenerateKey, err := t.canManageAPIKeys(user, app, t.authorizer.CanGenerateAPIKey)
+	if err != nil {
+		return "", err
+	}
+
+	if !canGenerateKey {
+		return "", ErrUnauthorizedAction{
+			UserID: user.ID,
+			Action: fmt.Sprintf("generate api key for app(%s)", app.ID),
+		}
+	}
+	return t.createAPIKey(app, scopes, expireAt)
+}
+
+// ListAPIKeys retrieves all the API keys of the given app.
+func (t ThirdPartyApp) ListAPIKeys(user entity.User, app entity.App) ([]entity.APIKey, error) {
+	canViewKeys, err := t.canManageAPIKeys(user, app, t.authorizer.CanViewAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewKeys {
+		return nil, ErrUnauthorizedAction{
+			UserID: user.ID,
+			Action: fmt.Sprintf("view api keys of app(%s)", app.ID),
+		}
+	}
+	return t.apiKeyRepo.GetAPIKeysByApp(app.ID)
+}
+
+// RevokeAPIKey disables the given API key immediately.
+func (t ThirdPartyApp) RevokeAPIKey(user entity.User, app entity.App, key string) error {
+	canRevokeKey, err := t.canManageAPIKeys(user, app, t.authorizer.CanRevokeAPIKey)
+	if err != nil {
+		return err
+	}
+
+	if !canRevokeKey {
+		return ErrUnauthorizedAction{
+			UserID: user.ID,
+			Action: fmt.Sprintf("revoke api key of app(%s)", app.ID),
+		}
+	}
+	return t.apiKeyRepo.DisableAPIKey(app.ID, key)
+}
+
+// RotateAPIKey generates a new API key granted the given scopes for the app and
+// expires the old one after the grace period, during which both keys are
+// accepted.
+func (t ThirdPartyApp) RotateAPIKey(
+	user entity.User,
+	app entity.App,
+	key string,
+	scopes []scope.Scope,
+	gracePeriod time.Duration,
+) (string, error) {
+	canGenerateKey, err := t.canManageAPIKeys(user, app, t.authorizer.CanGenerateAPIKey)
 	if err != nil {
 		return "", err
 	}
 
 	if !canGenerateKey {
-		return "", fmt.Errorf("user(%s) cannot generate api key for app(%s)", user.ID, app.ID)
+		return "", ErrUnauthorizedAction{
+			UserID: user.ID,
+			Action: fmt.Sprintf("rotate api key of app(%s)", app.ID),
+		}
+	}
+
+	oldAPIKey, err := t.apiKeyRepo.GetAPIKey(app.ID, key)
+	if err != nil {
+		return "", err
+	}
+
+	now := t.timer.Now()
+	if oldAPIKey.IsDisabled || oldAPIKey.IsExpired(now) {
+		return "", fmt.Errorf("api key of app(%s) is no longer valid", app.ID)
+	}
+
+	newAPIKey, err := t.createAPIKey(app, scopes, nil)
+	if err != nil {
+		return "", err
 	}
 
-	_, err = t.appRepo.GetAppByID(app.ID)
+	graceEndAt := now.Add(gracePeriod)
+	if oldAPIKey.ExpireAt != nil && oldAPIKey.ExpireAt.Before(graceEndAt) {
+		return newAPIKey, nil
+	}
+	err = t.apiKeyRepo.UpdateExpireAt(app.ID, key, graceEndAt)
+	if err != nil {
+		return "", err
+	}
+	return newAPIKey, nil
+}
+
+// decodeAPIKey verifies the signature of the API key in the credential and
+// extracts its payload.
+func (t ThirdPartyApp) decodeAPIKey(cred Credential) (payload.APIKey, error) {
+	if cred.APIKey == nil {
+		return payload.APIKey{}, errors.New("no credential provided")
+	}
+	tokenPayload, err := t.tokenizer.Decode(*cred.APIKey)
+	if err != nil {
+		return payload.APIKey{}, err
+	}
+
+	apiKeyPayload, err := payload.NewAPIKey(tokenPayload)
+	if err != nil {
+		return payload.APIKey{}, fmt.Errorf("invalid api key: %w", err)
+	}
+	return apiKeyPayload, nil
+}
+
+// canManageAPIKeys allows the owner of the app to manage its API keys. Other
+// users must be granted the permission.
+func (t ThirdPartyApp) canManageAPIKeys(
+	user entity.User,
+	app entity.App,
+	hasPermission func(user entity.User) (bool, error),
+) (bool, error) {
+	storedApp, err := t.appRepo.GetAppByID(app.ID)
+	if err == nil && storedApp.OwnerID != "" && storedApp.OwnerID == user.ID {
+		return true, nil
+	}
+	return hasPermission(user)
+}
+
+func (t ThirdPartyApp) createAPIKey(
+	app entity.App,
+	scopes []scope.Scope,
+	expireAt *time.Time,
+) (string, error) {
+	for _, s := range scopes {
+		if !scope.IsValid(s) {
+			return "", ErrInvalidScope{Scope: s}
+		}
+	}
+
+	_, err := t.appRepo.GetAppByID(app.ID)
 	if err != nil {
 		return "", err
 	}
@@ -74,7 +288,12 @@ func (t ThirdPartyApp) GenerateAPIKey(user entity.User, app entity.App) (string,
 		return "", err
 	}
 	if isExist {
-		return "", fmt.Errorf("key(%s) already exists for app(%s)", key, app.ID)
+		return "", fmt.Errorf("generated api key already exists for app(%s)", app.ID)
+	}
+
+	apiKeyScopes := make([]string, len(scopes))
+	for i, s := range scopes {
+		apiKeyScopes[i] = string(s)
 	}
 
 	isDisabled := false
@@ -84,6 +303,8 @@ func (t ThirdPartyApp) GenerateAPIKey(user entity.User, app entity.App) (string,
 		Key:        &key,
 		IsDisabled: &isDisabled,
 		CreatedAt:  &now,
+		ExpireAt:   expireAt,
+		Scopes:     apiKeyScopes,
 	}
 	apiKey, err := t.apiKeyRepo.CreateAPIKey(in)
 	if err != nil {
@@ -123,6 +344,7 @@ func NewThirdPartyApp(
 	timer timer.Timer,
 	apiKeyRepo repository.APIKey,
 	appRepo repository.App,
+	logger logger.Logger,
 ) ThirdPartyApp {
 	return ThirdPartyApp{
 		authorizer: authorizer,
@@ -131,5 +353,6 @@ func NewThirdPartyApp(
 		timer:      timer,
 		apiKeyRepo: apiKeyRepo,
 		appRepo:    appRepo,
+		logger:     logger,
 	}
 }
@@ -1,14 +1,18 @@
 package authenticator
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/short-d/app/fw/assert"
 	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/authenticator/scope"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
@@ -24,6 +28,7 @@ func TestThirdPartyApp_GetApp(t *testing.T) {
 		existingAPIKeys []entity.APIKey
 		existingApps    []entity.App
 		apiKey          string
+		requiredScope   scope.Scope
 		expectHasErr    bool
 		expectApp       entity.App
 	}{
@@ -83,6 +88,68 @@ func TestThirdPartyApp_GetApp(t *testing.T) {
 			apiKey:       `{"app_id": "alpha","key":"secret"}`,
 			expectHasErr: true,
 		},
+		{
+			name: "expired API key",
+			existingApps: []entity.App{
+				{ID: "alpha"},
+			},
+			existingAPIKeys: []entity.APIKey{
+				{
+					AppID:    "alpha",
+					Key:      "secret",
+					ExpireAt: mustTimePtr(t, "2020-07-17T15:04:05+07:00"),
+				},
+			},
+			apiKey:       `{"app_id": "alpha","key":"secret"}`,
+			expectHasErr: true,
+		},
+		{
+			name: "API key without scopes",
+			existingApps: []entity.App{
+				{ID: "alpha"},
+			},
+			existingAPIKeys: []entity.APIKey{
+				{
+					AppID: "alpha",
+					Key:   "secret",
+				},
+			},
+			apiKey:        `{"app_id": "alpha","key":"secret"}`,
+			requiredScope: scope.ShortLinkRead,
+			expectHasErr:  true,
+		},
+		{
+			name: "API key missing required scope",
+			existingApps: []entity.App{
+				{ID: "alpha"},
+			},
+			existingAPIKeys: []entity.APIKey{
+				{
+					AppID:  "alpha",
+					Key:    "secret",
+					Scopes: []string{"shortlink:read"},
+				},
+			},
+			apiKey:        `{"app_id": "alpha","key":"secret"}`,
+			requiredScope: scope.ShortLinkWrite,
+			expectHasErr:  true,
+		},
+		{
+			name: "scopes in API key token are ignored",
+			existingApps: []entity.App{
+				{ID: "alpha"},
+			},
+			existingAPIKeys: []entity.APIKey{
+				{
+					AppID:  "alpha",
+					Key:    "secret",
+					Scopes: []string{"shortlink:read"},
+				},
+			},
+			apiKey:        `{"app_id": "alpha","key":"secret","scopes":["shortlink:write"]}`,
+			requiredScope: scope.ShortLinkWrite,
+			expectHasErr:  true,
+		},
 		{
 			name: "valid API key",
 			existingApps: []entity.App{
@@ -94,12 +161,14 @@ func TestThirdPartyApp_GetApp(t *testing.T) {
 			},
 			existingAPIKeys: []entity.APIKey{
 				{
-					AppID: "alpha",
-					Key:   "secret",
+					AppID:  "alpha",
+					Key:    "secret",
+					Scopes: []string{"shortlink:read"},
 				},
 			},
-			apiKey:       `{"app_id": "alpha","key":"secret"}`,
-			expectHasErr: false,
+			apiKey:        `{"app_id": "alpha","key":"secret"}`,
+			requiredScope: scope.ShortLinkRead,
+			expectHasErr:  false,
 			expectApp: entity.App{
 				ID:        "alpha",
 				Name:      "Alpha",
@@ -126,10 +195,10 @@ func TestThirdPartyApp_GetApp(t *testing.T) {
 			tm := timer.NewStub(time.Now())
 			apiKeyRepo := repository.NewAPIKeyFake(testCase.existingAPIKeys)
 			appRepo := repository.NewAppFake(testCase.existingApps)
-			thirdPartyApp := NewThirdPartyApp(auth, tokenizer, keyGen, tm, &apiKeyRepo, appRepo)
+			thirdPartyApp := NewThirdPartyApp(auth, tokenizer, keyGen, tm, &apiKeyRepo, &appRepo, newLogger(t))
 
 			cred := Credential{APIKey: &testCase.apiKey}
-			gotApp, err := thirdPartyApp.GetApp(cred)
+			gotApp, err := thirdPartyApp.GetApp(cred, testCase.requiredScope)
 			if testCase.expectHasErr {
 				assert.NotEqual(t, nil, err)
 				return
@@ -151,6 +220,7 @@ func TestThirdPartyApp_GenerateAPIKey(t *testing.T) {
 		now             time.Time
 		user            entity.User
 		app             entity.App
+		scopes          []scope.Scope
 		expectHasErr    bool
 		expectKeyExist  bool
 		expectAPIKey    entity.APIKey
@@ -215,6 +285,45 @@ func TestThirdPartyApp_GenerateAPIKey(t *testing.T) {
 			app:           entity.App{ID: "app"},
 			expectHasErr:  true,
 		},
+		{
+			name: "Unsupported scope",
+			existingApps: []entity.App{
+				{ID: "app"},
+			},
+			existingAPIKeys: []entity.APIKey{},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			availableKeys: []keygen.Key{"secret"},
+			user:          entity.User{ID: "alpha"},
+			app:           entity.App{ID: "app"},
+			scopes:        []scope.Scope{"shortlink:admin"},
+			expectHasErr:  true,
+		},
+		{
+			name: "App owner generated API key",
+			existingApps: []entity.App{
+				{ID: "app", OwnerID: "alpha"},
+			},
+			existingAPIKeys: []entity.APIKey{},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Basic},
+			},
+			availableKeys: []keygen.Key{"secret"},
+			now:           must.Time(t, "2020-07-17T15:04:05+07:00"),
+			user:          entity.User{ID: "alpha"},
+			app:           entity.App{ID: "app"},
+			scopes:        []scope.Scope{scope.ShortLinkRead},
+			expectHasErr:  false,
+			expectAPIKey: entity.APIKey{
+				AppID:      "app",
+				Key:        "secret",
+				IsDisabled: false,
+				CreatedAt:  must.Time(t, "2020-07-17T15:04:05+07:00"),
+				Scopes:     []string{"shortlink:read"},
+			},
+			expectKey: `{"app_id":"app","key":"secret"}`,
+		},
 		{
 			name: "Successfully generated API key",
 			existingApps: []entity.App{
@@ -228,12 +337,14 @@ func TestThirdPartyApp_GenerateAPIKey(t *testing.T) {
 			now:           must.Time(t, "2020-07-17T15:04:05+07:00"),
 			user:          entity.User{ID: "alpha"},
 			app:           entity.App{ID: "app"},
+			scopes:        []scope.Scope{scope.ShortLinkRead, scope.ShortLinkWrite},
 			expectHasErr:  false,
 			expectAPIKey: entity.APIKey{
 				AppID:      "app",
 				Key:        "secret",
 				IsDisabled: false,
 				CreatedAt:  must.Time(t, "2020-07-17T15:04:05+07:00"),
+				Scopes:     []string{"shortlink:read", "shortlink:write"},
 			},
 			expectKey: `{"app_id":"app","key":"secret"}`,
 		},
@@ -257,9 +368,11 @@ func TestThirdPartyApp_GenerateAPIKey(t *testing.T) {
 			tm := timer.NewStub(testCase.now)
 			apiKeyRepo := repository.NewAPIKeyFake(testCase.existingAPIKeys)
 			appRepo := repository.NewAppFake(testCase.existingApps)
-			thirdPartyApp := NewThirdPartyApp(auth, tokenizer, keyGen, tm, &apiKeyRepo, appRepo)
+			thirdPartyApp := NewThirdPartyApp(auth, tokenizer, keyGen, tm, &apiKeyRepo, &appRepo, newLogger(t))
 
-			gotKey, err := thirdPartyApp.GenerateAPIKey(testCase.user, testCase.app)
+			gotKey, err := thirdPartyApp.GenerateAPIKey(
+				testCase.user, testCase.app, testCase.scopes, nil,
+			)
 			if testCase.expectHasErr {
 				assert.NotEqual(t, nil, err)
 				return
@@ -273,3 +386,422 @@ func TestThirdPartyApp_GenerateAPIKey(t *testing.T) {
 		})
 	}
 }
+
+func TestThirdPartyApp_GetApp_lastUsedAt(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05+07:00")
+	testCases := []struct {
+		name             string
+		lastUsedAt       *time.Time
+		expectLastUsedAt *time.Time
+	}{
+		{
+			name:             "first use",
+			lastUsedAt:       nil,
+			expectLastUsedAt: &now,
+		},
+		{
+			name:             "used within a minute",
+			lastUsedAt:       mustTimePtr(t, "2020-07-17T15:03:35+07:00"),
+			expectLastUsedAt: mustTimePtr(t, "2020-07-17T15:03:35+07:00"),
+		},
+		{
+			name:             "used more than a minute ago",
+			lastUsedAt:       mustTimePtr(t, "2020-07-17T15:02:05+07:00"),
+			expectLastUsedAt: &now,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+			auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+
+			apiKeyRepo := repository.NewAPIKeyFake([]entity.APIKey{
+				{
+					AppID:      "alpha",
+					Key:        "secret",
+					ExpireAt:   mustTimePtr(t, "2020-07-18T15:04:05+07:00"),
+					LastUsedAt: testCase.lastUsedAt,
+					Scopes:     []string{"shortlink:read"},
+				},
+			})
+			appRepo := repository.NewAppFake([]entity.App{{ID: "alpha"}})
+			thirdPartyApp := NewThirdPartyApp(
+				auth, crypto.NewTokenizerFake(), keyGen, timer.NewStub(now), &apiKeyRepo, &appRepo, newLogger(t),
+			)
+
+			apiKey := `{"app_id": "alpha","key":"secret"}`
+			_, err = thirdPartyApp.GetApp(Credential{APIKey: &apiKey}, scope.ShortLinkRead)
+			assert.Equal(t, nil, err)
+
+			gotAPIKey, err := apiKeyRepo.GetAPIKey("alpha", "secret")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectLastUsedAt, gotAPIKey.LastUsedAt)
+		})
+	}
+}
+
+func TestThirdPartyApp_IdentifyAPIKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		apiKey         *string
+		expectHasErr   bool
+		expectedAPIKey entity.APIKey
+	}{
+		{
+			name:         "no credential",
+			expectHasErr: true,
+		},
+		{
+			name:         "malformed api key",
+			apiKey:       ptr.String(`{"key": "secret"}`),
+			expectHasErr: true,
+		},
+		{
+			name:   "revoked api key is still identified",
+			apiKey: ptr.String(`{"app_id": "alpha","key":"secret"}`),
+			expectedAPIKey: entity.APIKey{
+				AppID: "alpha",
+				Key:   "secret",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := must.Time(t, "2020-07-17T15:04:05+07:00")
+			thirdPartyApp, _ := newThirdPartyAppForAPIKeys(
+				t,
+				map[string][]role.Role{},
+				[]entity.APIKey{{AppID: "alpha", Key: "secret", IsDisabled: true}},
+				[]keygen.Key{},
+				now,
+			)
+
+			gotAPIKey, err := thirdPartyApp.IdentifyAPIKey(Credential{APIKey: testCase.apiKey})
+			if testCase.expectHasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedAPIKey, gotAPIKey)
+		})
+	}
+}
+
+func TestThirdPartyApp_ListAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		existingAPIKeys []entity.APIKey
+		userRoles       map[string][]role.Role
+		user            entity.User
+		app             entity.App
+		expectHasErr    bool
+		expectAPIKeys   []entity.APIKey
+	}{
+		{
+			name: "No permission to view API keys",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Basic},
+			},
+			user:         entity.User{ID: "alpha"},
+			app:          entity.App{ID: "app"},
+			expectHasErr: true,
+		},
+		{
+			name: "List API keys of the app",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+				{AppID: "other", Key: "password"},
+				{AppID: "app", Key: "revoked", IsDisabled: true},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			user:         entity.User{ID: "alpha"},
+			app:          entity.App{ID: "app"},
+			expectHasErr: false,
+			expectAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+				{AppID: "app", Key: "revoked", IsDisabled: true},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			thirdPartyApp, _ := newThirdPartyAppForAPIKeys(
+				t, testCase.userRoles, testCase.existingAPIKeys, nil, time.Now(),
+			)
+
+			gotAPIKeys, err := thirdPartyApp.ListAPIKeys(testCase.user, testCase.app)
+			if testCase.expectHasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectAPIKeys, gotAPIKeys)
+		})
+	}
+}
+
+func TestThirdPartyApp_RevokeAPIKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		existingAPIKeys []entity.APIKey
+		userRoles       map[string][]role.Role
+		user            entity.User
+		app             entity.App
+		key             string
+		expectHasErr    bool
+	}{
+		{
+			name: "No permission to revoke API key",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Basic},
+			},
+			user:         entity.User{ID: "alpha"},
+			app:          entity.App{ID: "app"},
+			key:          "secret",
+			expectHasErr: true,
+		},
+		{
+			name: "API key not found",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			user:         entity.User{ID: "alpha"},
+			app:          entity.App{ID: "app"},
+			key:          "password",
+			expectHasErr: true,
+		},
+		{
+			name: "Successfully revoked API key",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			user:         entity.User{ID: "alpha"},
+			app:          entity.App{ID: "app"},
+			key:          "secret",
+			expectHasErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			thirdPartyApp, apiKeyRepo := newThirdPartyAppForAPIKeys(
+				t, testCase.userRoles, testCase.existingAPIKeys, nil, time.Now(),
+			)
+
+			err := thirdPartyApp.RevokeAPIKey(testCase.user, testCase.app, testCase.key)
+			if testCase.expectHasErr {
+				assert.NotEqual(t, nil, err)
+				assert.Equal(t, false, strings.Contains(err.Error(), testCase.key))
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			apiKey, err := apiKeyRepo.GetAPIKey(testCase.app.ID, testCase.key)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, apiKey.IsDisabled)
+		})
+	}
+}
+
+func TestThirdPartyApp_RotateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		existingAPIKeys []entity.APIKey
+		userRoles       map[string][]role.Role
+		availableKeys   []keygen.Key
+		now             time.Time
+		user            entity.User
+		app             entity.App
+		key             string
+		scopes          []scope.Scope
+		gracePeriod     time.Duration
+		expectHasErr    bool
+		expectKey       string
+		expectExpireAt  *time.Time
+	}{
+		{
+			name: "No permission to rotate API key",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Basic},
+			},
+			availableKeys: []keygen.Key{"password"},
+			now:           must.Time(t, "2020-07-17T15:04:05+07:00"),
+			user:          entity.User{ID: "alpha"},
+			app:           entity.App{ID: "app"},
+			key:           "secret",
+			gracePeriod:   time.Hour,
+			expectHasErr:  true,
+		},
+		{
+			name: "Revoked API key can't be rotated",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret", IsDisabled: true},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			availableKeys: []keygen.Key{"password"},
+			now:           must.Time(t, "2020-07-17T15:04:05+07:00"),
+			user:          entity.User{ID: "alpha"},
+			app:           entity.App{ID: "app"},
+			key:           "secret",
+			gracePeriod:   time.Hour,
+			expectHasErr:  true,
+		},
+		{
+			name: "Old key expires after grace period",
+			existingAPIKeys: []entity.APIKey{
+				{AppID: "app", Key: "secret"},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			availableKeys:  []keygen.Key{"password"},
+			now:            must.Time(t, "2020-07-17T15:04:05+07:00"),
+			user:           entity.User{ID: "alpha"},
+			app:            entity.App{ID: "app"},
+			key:            "secret",
+			gracePeriod:    time.Hour,
+			expectHasErr:   false,
+			scopes:         []scope.Scope{scope.ShortLinkRead},
+			expectKey:      `{"app_id":"app","key":"password"}`,
+			expectExpireAt: mustTimePtr(t, "2020-07-17T16:04:05+07:00"),
+		},
+		{
+			name: "Old key keeps earlier expiry",
+			existingAPIKeys: []entity.APIKey{
+				{
+					AppID:    "app",
+					Key:      "secret",
+					ExpireAt: mustTimePtr(t, "2020-07-17T15:30:05+07:00"),
+				},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			availableKeys:  []keygen.Key{"password"},
+			now:            must.Time(t, "2020-07-17T15:04:05+07:00"),
+			user:           entity.User{ID: "alpha"},
+			app:            entity.App{ID: "app"},
+			key:            "secret",
+			gracePeriod:    time.Hour,
+			expectHasErr:   false,
+			scopes:         []scope.Scope{scope.ShortLinkRead},
+			expectKey:      `{"app_id":"app","key":"password"}`,
+			expectExpireAt: mustTimePtr(t, "2020-07-17T15:30:05+07:00"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			thirdPartyApp, apiKeyRepo := newThirdPartyAppForAPIKeys(
+				t, testCase.userRoles, testCase.existingAPIKeys,
+				testCase.availableKeys, testCase.now,
+			)
+
+			gotKey, err := thirdPartyApp.RotateAPIKey(
+				testCase.user, testCase.app, testCase.key, testCase.scopes, testCase.gracePeriod,
+			)
+			if testCase.expectHasErr {
+				assert.NotEqual(t, nil, err)
+				assert.Equal(t, false, strings.Contains(err.Error(), testCase.key))
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectKey, gotKey)
+
+			oldAPIKey, err := apiKeyRepo.GetAPIKey(testCase.app.ID, testCase.key)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectExpireAt, oldAPIKey.ExpireAt)
+
+			newAPIKey, err := apiKeyRepo.GetAPIKey(
+				testCase.app.ID, string(testCase.availableKeys[0]),
+			)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, (*time.Time)(nil), newAPIKey.ExpireAt)
+		})
+	}
+}
+
+func newThirdPartyAppForAPIKeys(
+	t *testing.T,
+	userRoles map[string][]role.Role,
+	apiKeys []entity.APIKey,
+	availableKeys []keygen.Key,
+	now time.Time,
+) (ThirdPartyApp, *repository.APIKeyFake) {
+	userRoleRepo := repository.NewUserRoleFake(userRoles)
+	auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+
+	keyFetcher := keygen.NewKeyFetcherFake(availableKeys)
+	keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+	assert.Equal(t, nil, err)
+
+	apiKeyRepo := repository.NewAPIKeyFake(apiKeys)
+	appRepo := repository.NewAppFake([]entity.App{{ID: "app"}})
+	thirdPartyApp := NewThirdPartyApp(
+		auth, crypto.NewTokenizerFake(), keyGen, timer.NewStub(now), &apiKeyRepo, &appRepo, newLogger(t),
+	)
+	return thirdPartyApp, &apiKeyRepo
+}
+
+func newLogger(t *testing.T) logger.Logger {
+	entryRepo := logger.NewEntryRepoFake()
+	lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+	assert.Equal(t, nil, err)
+	return lg
+}
+
+func mustTimePtr(t *testing.T, timeString string) *time.Time {
+	tm := must.Time(t, timeString)
+	return &tm
+}
@@ -2,6 +2,7 @@ package authorizer
 
 import (
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
 )
@@ -9,7 +10,8 @@ import (
 // Authorizer checks whether an user is granted required permissions in order
 // to perform certain operations.
 type Authorizer struct {
-	rbac rbac.RBAC
+	rbac      rbac.RBAC
+	evaluator policy.Evaluator
 }
 
 // CanCreateChange decides whether a user is allowed to create a change.
@@ -42,7 +44,93 @@ func (a Authorizer) CanGenerateAPIKey(user entity.User) (bool, error) {
 	return a.rbac.HasPermission(user, permission.CreateAPIKey)
 }
 
-// NewAuthorizer creates a new Authorizer object
+// CanViewAPIKeys decides whether a user is allowed to list the api keys of
+// an app.
+func (a Authorizer) CanViewAPIKeys(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ViewAPIKey)
+}
+
+// CanRevokeAPIKey decides whether a user is allowed to revoke an api key.
+func (a Authorizer) CanRevokeAPIKey(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.RevokeAPIKey)
+}
+
+// CanUseRestrictedAlias decides whether a user is allowed to claim reserved
+// aliases or aliases containing blocked words.
+func (a Authorizer) CanUseRestrictedAlias(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.UseRestrictedAlias)
+}
+
+// CanViewSessions decides whether a user is allowed to view the sign in
+// sessions of other users.
+func (a Authorizer) CanViewSessions(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ViewSession)
+}
+
+// CanRevokeSessions decides whether a user is allowed to revoke the sign in
+// sessions of other users.
+func (a Authorizer) CanRevokeSessions(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.RevokeSession)
+}
+
+// CanViewUsers decides whether a user is allowed to list users together with
+// their roles and role changes.
+func (a Authorizer) CanViewUsers(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ViewUser)
+}
+
+// CanGrantRole decides whether a user is allowed to grant roles to users.
+func (a Authorizer) CanGrantRole(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.UpgradeUser)
+}
+
+// CanRevokeRole decides whether a user is allowed to revoke roles from users.
+func (a Authorizer) CanRevokeRole(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.DowngradeUser)
+}
+
+// CanViewRoles decides whether a user is allowed to list roles together with
+// their permissions.
+func (a Authorizer) CanViewRoles(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ViewRole)
+}
+
+// CanManageRoles decides whether a user is allowed to create, update and
+// delete custom roles.
+func (a Authorizer) CanManageRoles(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ManageRole)
+}
+
+// CanManageShortLinkAttributes decides whether a user is allowed to view and
+// change the attributes of short links, which policies grant permissions on.
+func (a Authorizer) CanManageShortLinkAttributes(user entity.User) (bool, error) {
+	return a.rbac.HasPermission(user, permission.ManageShortLinkAttribute)
+}
+
+// CanEditShortLink decides whether a user is allowed to edit the given short
+// link, either as its owner or through a policy scoped to the short link.
+func (a Authorizer) CanEditShortLink(user entity.User, shortLink policy.Resource) (bool, error) {
+	return a.evaluator.IsAllowed(user, permission.EditShortLink, shortLink)
+}
+
+// CanViewShortLinkAnalytics decides whether a user is allowed to view the
+// analytics of the given short link, either as its owner or through a policy
+// scoped to the short link, such as one for the short links with a tag.
+func (a Authorizer) CanViewShortLinkAnalytics(user entity.User, shortLink policy.Resource) (bool, error) {
+	return a.evaluator.IsAllowed(user, permission.ViewShortLinkAnalytics, shortLink)
+}
+
+// NewAuthorizer creates a new Authorizer object without any policy, so that
+// users are only allowed to access their own resources.
 func NewAuthorizer(rbac rbac.RBAC) Authorizer {
-	return Authorizer{rbac: rbac}
+	return NewPolicyAuthorizer(rbac, []policy.Policy{})
+}
+
+// NewPolicyAuthorizer creates Authorizer which also grants permissions on the
+// resources matching the given policies.
+func NewPolicyAuthorizer(rbac rbac.RBAC, policies []policy.Policy) Authorizer {
+	return Authorizer{
+		rbac:      rbac,
+		evaluator: policy.NewEvaluator(rbac, policies),
+	}
 }
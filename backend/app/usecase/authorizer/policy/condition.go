@@ -0,0 +1,31 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// Condition decides whether a policy applies to the given user and resource.
+type Condition func(user entity.User, resource Resource) bool
+
+// IDHasPrefix matches the resources whose ID starts with the prefix, such as
+// short links with aliases reserved for a team.
+func IDHasPrefix(prefix string) Condition {
+	return func(user entity.User, resource Resource) bool {
+		return strings.HasPrefix(resource.ID, prefix)
+	}
+}
+
+// HasAttribute matches the resources with the given value among the values of
+// the attribute, such as short links tagged with a given tag.
+func HasAttribute(name string, value string) Condition {
+	return func(user entity.User, resource Resource) bool {
+		for _, attrValue := range resource.Attributes[name] {
+			if attrValue == value {
+				return true
+			}
+		}
+		return false
+	}
+}
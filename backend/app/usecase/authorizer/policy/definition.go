@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+// The types of conditions which can be defined outside of code.
+const (
+	// IDPrefixCondition matches the resources whose ID starts with Value.
+	IDPrefixCondition = "id_prefix"
+	// AttributeCondition matches the resources with Value among the values of
+	// the attribute called Name.
+	AttributeCondition = "attribute"
+)
+
+// Definition describes a policy with names only, so that policies can be kept
+// outside of code, such as in a configuration file.
+// A policy must either list the roles it applies to or set AllUsers.
+type Definition struct {
+	Roles        []string
+	AllUsers     bool
+	Permission   string
+	ResourceType string
+	Conditions   []ConditionDefinition
+}
+
+// ConditionDefinition describes a condition of a policy.
+type ConditionDefinition struct {
+	Type  string
+	Name  string
+	Value string
+}
+
+// ErrInvalidDefinition represents a policy definition referring to unknown
+// permissions, resource types or condition types, or one which doesn't say
+// who it applies to.
+type ErrInvalidDefinition struct {
+	Index  int
+	Reason string
+}
+
+var _ error = (*ErrInvalidDefinition)(nil)
+
+func (e ErrInvalidDefinition) Error() string {
+	return fmt.Sprintf("invalid policy definition at %d: %s", e.Index, e.Reason)
+}
+
+// NewPolicies creates the policies from their definitions.
+func NewPolicies(definitions []Definition) ([]Policy, error) {
+	policies := make([]Policy, 0, len(definitions))
+	for idx, definition := range definitions {
+		policy, err := newPolicy(definition)
+		if err != nil {
+			return nil, ErrInvalidDefinition{Index: idx, Reason: err.Error()}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func newPolicy(definition Definition) (Policy, error) {
+	perm, ok := permission.Parse(definition.Permission)
+	if !ok {
+		return Policy{}, fmt.Errorf("unknown permission(%s)", definition.Permission)
+	}
+
+	resourceType := ResourceType(definition.ResourceType)
+	if resourceType != ShortLink {
+		return Policy{}, fmt.Errorf("unknown resource type(%s)", definition.ResourceType)
+	}
+
+	if len(definition.Roles) == 0 && !definition.AllUsers {
+		return Policy{}, errors.New("roles missing without applying to all users")
+	}
+	if len(definition.Roles) > 0 && definition.AllUsers {
+		return Policy{}, errors.New("roles listed while applying to all users")
+	}
+
+	roles := make([]role.Role, 0, len(definition.Roles))
+	for _, name := range definition.Roles {
+		roles = append(roles, role.Role(name))
+	}
+
+	conditions := make([]Condition, 0, len(definition.Conditions))
+	for _, conditionDefinition := range definition.Conditions {
+		condition, err := newCondition(conditionDefinition)
+		if err != nil {
+			return Policy{}, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return Policy{
+		Roles:        roles,
+		AllUsers:     definition.AllUsers,
+		Permission:   perm,
+		ResourceType: resourceType,
+		Conditions:   conditions,
+	}, nil
+}
+
+func newCondition(definition ConditionDefinition) (Condition, error) {
+	switch definition.Type {
+	case IDPrefixCondition:
+		return IDHasPrefix(definition.Value), nil
+	case AttributeCondition:
+		return HasAttribute(definition.Name, definition.Value), nil
+	default:
+		return nil, fmt.Errorf("unknown condition type(%s)", definition.Type)
+	}
+}
@@ -0,0 +1,154 @@
+// +build !integration all
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+func TestNewPolicies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		definitions []Definition
+		hasErr      bool
+		expectedErr error
+	}{
+		{
+			name:        "no policy",
+			definitions: []Definition{},
+			hasErr:      false,
+		},
+		{
+			name: "unknown permission",
+			definitions: []Definition{
+				{AllUsers: true, Permission: "fly", ResourceType: "short_link"},
+			},
+			hasErr:      true,
+			expectedErr: ErrInvalidDefinition{Index: 0, Reason: "unknown permission(fly)"},
+		},
+		{
+			name: "unknown resource type",
+			definitions: []Definition{
+				{AllUsers: true, Permission: "edit_short_link", ResourceType: "short_link"},
+				{AllUsers: true, Permission: "edit_short_link", ResourceType: "app"},
+			},
+			hasErr:      true,
+			expectedErr: ErrInvalidDefinition{Index: 1, Reason: "unknown resource type(app)"},
+		},
+		{
+			name: "unknown condition type",
+			definitions: []Definition{
+				{
+					AllUsers:     true,
+					Permission:   "edit_short_link",
+					ResourceType: "short_link",
+					Conditions:   []ConditionDefinition{{Type: "owner"}},
+				},
+			},
+			hasErr:      true,
+			expectedErr: ErrInvalidDefinition{Index: 0, Reason: "unknown condition type(owner)"},
+		},
+		{
+			name: "roles missing",
+			definitions: []Definition{
+				{Permission: "edit_short_link", ResourceType: "short_link"},
+			},
+			hasErr: true,
+			expectedErr: ErrInvalidDefinition{
+				Index:  0,
+				Reason: "roles missing without applying to all users",
+			},
+		},
+		{
+			name: "roles listed while applying to all users",
+			definitions: []Definition{
+				{
+					Roles:        []string{"short_link_editor"},
+					AllUsers:     true,
+					Permission:   "edit_short_link",
+					ResourceType: "short_link",
+				},
+			},
+			hasErr: true,
+			expectedErr: ErrInvalidDefinition{
+				Index:  0,
+				Reason: "roles listed while applying to all users",
+			},
+		},
+		{
+			name: "valid policies",
+			definitions: []Definition{
+				{
+					Roles:        []string{"short_link_editor"},
+					Permission:   "edit_short_link",
+					ResourceType: "short_link",
+					Conditions: []ConditionDefinition{
+						{Type: "id_prefix", Value: "growth-"},
+						{Type: "attribute", Name: "team", Value: "growth"},
+					},
+				},
+				{
+					AllUsers:     true,
+					Permission:   "view_short_link_analytics",
+					ResourceType: "short_link",
+					Conditions: []ConditionDefinition{
+						{Type: "attribute", Name: "tag", Value: "marketing"},
+					},
+				},
+			},
+			hasErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			policies, err := NewPolicies(testCase.definitions)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, len(testCase.definitions), len(policies))
+		})
+	}
+}
+
+func TestNewPolicies_Conditions(t *testing.T) {
+	t.Parallel()
+
+	policies, err := NewPolicies([]Definition{
+		{
+			Roles:        []string{"short_link_editor"},
+			Permission:   "edit_short_link",
+			ResourceType: "short_link",
+			Conditions: []ConditionDefinition{
+				{Type: "id_prefix", Value: "growth-"},
+				{Type: "attribute", Name: "team", Value: "growth"},
+			},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(policies))
+
+	user := entity.User{ID: "alpha"}
+	roles := []role.Role{role.ShortLinkEditor}
+	resource := Resource{
+		Type:       ShortLink,
+		ID:         "growth-plan",
+		Attributes: map[string][]string{"team": {"growth"}},
+	}
+	assert.Equal(t, true, policies[0].Allows(user, roles, permission.EditShortLink, resource))
+
+	resource.ID = "search-plan"
+	assert.Equal(t, false, policies[0].Allows(user, roles, permission.EditShortLink, resource))
+}
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+)
+
+// Evaluator decides whether an user is granted a permission on a given
+// resource. Owners are granted every permission on their own resources, while
+// the other users need a policy scoped to the resource. The permissions the
+// roles grant on every resource are not taken into account.
+type Evaluator struct {
+	rbac     rbac.RBAC
+	policies []Policy
+}
+
+// IsAllowed checks whether the user is granted the permission on the
+// resource.
+func (e Evaluator) IsAllowed(
+	user entity.User,
+	permission permission.Permission,
+	resource Resource,
+) (bool, error) {
+	if resource.OwnerID != "" && resource.OwnerID == user.ID {
+		return true, nil
+	}
+	if len(e.policies) == 0 {
+		return false, nil
+	}
+
+	roles, err := e.rbac.GetRoles(user)
+	if err != nil {
+		return false, err
+	}
+	for _, policy := range e.policies {
+		if policy.Allows(user, roles, permission, resource) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NewEvaluator creates Evaluator.
+func NewEvaluator(rbac rbac.RBAC, policies []Policy) Evaluator {
+	return Evaluator{rbac: rbac, policies: policies}
+}
@@ -0,0 +1,193 @@
+// +build !integration all
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestEvaluator_IsAllowed(t *testing.T) {
+	t.Parallel()
+
+	policies := []Policy{
+		{
+			Roles:        []role.Role{role.ShortLinkViewer},
+			Permission:   permission.EditShortLink,
+			ResourceType: ShortLink,
+			Conditions:   []Condition{HasAttribute("team", "growth")},
+		},
+		{
+			Roles:        []role.Role{role.ChangeLogEditor},
+			Permission:   permission.EditShortLink,
+			ResourceType: ShortLink,
+			Conditions:   []Condition{IDHasPrefix("docs-"), HasAttribute("tag", "public")},
+		},
+		{
+			AllUsers:     true,
+			Permission:   permission.ViewShortLinkAnalytics,
+			ResourceType: ShortLink,
+			Conditions:   []Condition{HasAttribute("tag", "marketing")},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		roles      map[string][]role.Role
+		user       entity.User
+		permission permission.Permission
+		resource   Resource
+		isAllowed  bool
+	}{
+		{
+			name:       "no policy applies",
+			roles:      map[string][]role.Role{},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource:   Resource{Type: ShortLink, ID: "abc", OwnerID: "beta"},
+			isAllowed:  false,
+		},
+		{
+			name:       "permission granted by role without policy",
+			roles:      map[string][]role.Role{"alpha": {role.ShortLinkEditor, role.Admin}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource:   Resource{Type: ShortLink, ID: "abc", OwnerID: "beta"},
+			isAllowed:  false,
+		},
+		{
+			name:       "owner of resource",
+			roles:      map[string][]role.Role{},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource:   Resource{Type: ShortLink, ID: "abc", OwnerID: "alpha"},
+			isAllowed:  true,
+		},
+		{
+			name:       "owner of resource without policy",
+			roles:      map[string][]role.Role{},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.ViewShortLinkAnalytics,
+			resource:   Resource{Type: ShortLink, ID: "abc", OwnerID: "alpha"},
+			isAllowed:  true,
+		},
+		{
+			name:       "policy for another permission",
+			roles:      map[string][]role.Role{"alpha": {role.ShortLinkViewer}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.DeleteShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "abc",
+				Attributes: map[string][]string{"team": {"growth"}},
+			},
+			isAllowed: false,
+		},
+		{
+			name:       "resource of another team",
+			roles:      map[string][]role.Role{"alpha": {role.ShortLinkViewer}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "abc",
+				Attributes: map[string][]string{"team": {"search"}},
+			},
+			isAllowed: false,
+		},
+		{
+			name:       "resource of the team",
+			roles:      map[string][]role.Role{"alpha": {role.ShortLinkViewer}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "abc",
+				Attributes: map[string][]string{"team": {"search", "growth"}},
+			},
+			isAllowed: true,
+		},
+		{
+			name:       "role not covered by policy",
+			roles:      map[string][]role.Role{"alpha": {role.Basic}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "abc",
+				Attributes: map[string][]string{"team": {"growth"}},
+			},
+			isAllowed: false,
+		},
+		{
+			name:       "only some conditions met",
+			roles:      map[string][]role.Role{"alpha": {role.ChangeLogEditor}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "docs-api",
+				Attributes: map[string][]string{"tag": {"internal"}},
+			},
+			isAllowed: false,
+		},
+		{
+			name:       "all conditions met",
+			roles:      map[string][]role.Role{"alpha": {role.ChangeLogEditor}},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.EditShortLink,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "docs-api",
+				Attributes: map[string][]string{"tag": {"internal", "public"}},
+			},
+			isAllowed: true,
+		},
+		{
+			name:       "analytics of short link tagged",
+			roles:      map[string][]role.Role{},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.ViewShortLinkAnalytics,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "sale",
+				OwnerID:    "beta",
+				Attributes: map[string][]string{"tag": {"marketing"}},
+			},
+			isAllowed: true,
+		},
+		{
+			name:       "analytics of short link not tagged",
+			roles:      map[string][]role.Role{},
+			user:       entity.User{ID: "alpha"},
+			permission: permission.ViewShortLinkAnalytics,
+			resource: Resource{
+				Type:       ShortLink,
+				ID:         "sale",
+				OwnerID:    "beta",
+				Attributes: map[string][]string{"tag": {"internal"}},
+			},
+			isAllowed: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRoleRepo := repository.NewUserRoleFake(testCase.roles)
+			evaluator := NewEvaluator(rbac.NewRBAC(userRoleRepo), policies)
+
+			isAllowed, err := evaluator.IsAllowed(testCase.user, testCase.permission, testCase.resource)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.isAllowed, isAllowed)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+// Policy grants a permission on the resources of a type which meet all the
+// conditions to the users assigned any of the roles, or to every user when
+// AllUsers is set.
+type Policy struct {
+	// Roles lists the roles the policy applies to, unless AllUsers is set.
+	Roles        []role.Role
+	AllUsers     bool
+	Permission   permission.Permission
+	ResourceType ResourceType
+	Conditions   []Condition
+}
+
+// Allows checks whether the policy grants the permission on the resource to
+// the user with the given roles.
+func (p Policy) Allows(
+	user entity.User,
+	roles []role.Role,
+	permission permission.Permission,
+	resource Resource,
+) bool {
+	if p.Permission != permission || p.ResourceType != resource.Type {
+		return false
+	}
+	if !p.appliesTo(roles) {
+		return false
+	}
+	for _, condition := range p.Conditions {
+		if !condition(user, resource) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Policy) appliesTo(roles []role.Role) bool {
+	if p.AllUsers {
+		return true
+	}
+	for _, userRole := range roles {
+		for _, policyRole := range p.Roles {
+			if userRole == policyRole {
+				return true
+			}
+		}
+	}
+	return false
+}
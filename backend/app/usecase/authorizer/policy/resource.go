@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"errors"
+
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// ResourceType represents the kind of objects guarded by policies.
+type ResourceType string
+
+// ShortLink represents short links identified by their aliases.
+const ShortLink ResourceType = "short_link"
+
+const (
+	// TeamAttribute names the teams owning a resource.
+	TeamAttribute = "team"
+	// TagAttribute names the tags of a resource.
+	TagAttribute = "tag"
+)
+
+// Resource represents an object a permission is requested on, together with
+// the information policies are evaluated against.
+type Resource struct {
+	Type    ResourceType
+	ID      string
+	OwnerID string
+	// Attributes describe the resource, such as the team owning it or its
+	// tags. An attribute can have multiple values.
+	Attributes map[string][]string
+}
+
+// ResourceFinder describes the objects stored in repositories as resources.
+type ResourceFinder struct {
+	userShortLinkRepo      repository.UserShortLink
+	shortLinkAttributeRepo repository.ShortLinkAttribute
+}
+
+// FindShortLink describes the short link with the given alias, together with
+// the user who created it and its attributes. The owner is left empty for the
+// short links created by apps.
+func (r ResourceFinder) FindShortLink(alias string) (Resource, error) {
+	ownerID, err := r.userShortLinkRepo.FindUserIDByAlias(alias)
+	var notFound repository.ErrEntryNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return Resource{}, err
+	}
+
+	attributes, err := r.shortLinkAttributeRepo.GetAttributes(alias)
+	if err != nil {
+		return Resource{}, err
+	}
+	return Resource{
+		Type:       ShortLink,
+		ID:         alias,
+		OwnerID:    ownerID,
+		Attributes: attributes,
+	}, nil
+}
+
+// NewResourceFinder creates ResourceFinder.
+func NewResourceFinder(
+	userShortLinkRepo repository.UserShortLink,
+	shortLinkAttributeRepo repository.ShortLinkAttribute,
+) ResourceFinder {
+	return ResourceFinder{
+		userShortLinkRepo:      userShortLinkRepo,
+		shortLinkAttributeRepo: shortLinkAttributeRepo,
+	}
+}
@@ -0,0 +1,67 @@
+// +build !integration all
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestResourceFinder_FindShortLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		alias            string
+		expectedResource Resource
+	}{
+		{
+			name:  "short link created by user",
+			alias: "docs",
+			expectedResource: Resource{
+				Type:    ShortLink,
+				ID:      "docs",
+				OwnerID: "alpha",
+				Attributes: map[string][]string{
+					"team": {"growth"},
+					"tag":  {"public", "api"},
+				},
+			},
+		},
+		{
+			name:  "short link without owner",
+			alias: "app",
+			expectedResource: Resource{
+				Type:       ShortLink,
+				ID:         "app",
+				Attributes: map[string][]string{},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(
+				[]entity.User{{ID: "alpha"}},
+				[]entity.ShortLink{{Alias: "docs"}},
+			)
+			attributeRepo := repository.NewShortLinkAttributeFake(map[string]map[string][]string{
+				"docs": {
+					"team": {"growth"},
+					"tag":  {"public", "api"},
+				},
+			})
+			finder := NewResourceFinder(&userShortLinkRepo, &attributeRepo)
+
+			resource, err := finder.FindShortLink(testCase.alias)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedResource, resource)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package rbac
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// RoleCacheLifetime limits how long the custom roles are cached, so that the
+// changes made through other instances of the service are picked up.
+const RoleCacheLifetime = time.Minute
+
+type roleCache struct {
+	mutex       sync.Mutex
+	definitions map[role.Role]role.Definition
+	expireAt    time.Time
+}
+
+// RoleDefinitions resolves the definitions of built-in roles from code and
+// the ones of custom roles from storage. Custom roles are cached until they
+// are invalidated or the cache expires.
+type RoleDefinitions struct {
+	roleRepo repository.Role
+	timer    timer.Timer
+	cache    *roleCache
+}
+
+// GetDefinition fetches the definition of the given role. It returns false
+// when the role is not defined.
+func (r RoleDefinitions) GetDefinition(name role.Role) (role.Definition, bool, error) {
+	if name.IsBuiltIn() {
+		return role.Definition{
+			Name:        name,
+			Permissions: role.BuiltInPermissions(name),
+			IsBuiltIn:   true,
+		}, true, nil
+	}
+
+	customRoles, err := r.getCustomRoles()
+	if err != nil {
+		return role.Definition{}, false, err
+	}
+	definition, ok := customRoles[name]
+	return definition, ok, nil
+}
+
+// GetDefinitions fetches the definitions of all the roles, with the built-in
+// ones first.
+func (r RoleDefinitions) GetDefinitions() ([]role.Definition, error) {
+	customRoles, err := r.getCustomRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := role.BuiltIns()
+	customDefinitions := make([]role.Definition, 0, len(customRoles))
+	for _, definition := range customRoles {
+		customDefinitions = append(customDefinitions, definition)
+	}
+	sort.Slice(customDefinitions, func(i, j int) bool {
+		return customDefinitions[i].Name < customDefinitions[j].Name
+	})
+	return append(definitions, customDefinitions...), nil
+}
+
+// Invalidate drops the cached custom roles after they are changed.
+func (r RoleDefinitions) Invalidate() {
+	if r.cache == nil {
+		return
+	}
+	r.cache.mutex.Lock()
+	defer r.cache.mutex.Unlock()
+	r.cache.definitions = nil
+}
+
+func (r RoleDefinitions) getCustomRoles() (map[role.Role]role.Definition, error) {
+	if r.roleRepo == nil {
+		return map[role.Role]role.Definition{}, nil
+	}
+
+	r.cache.mutex.Lock()
+	defer r.cache.mutex.Unlock()
+
+	now := r.timer.Now()
+	if r.cache.definitions != nil && now.Before(r.cache.expireAt) {
+		return r.cache.definitions, nil
+	}
+
+	customRoles, err := r.roleRepo.GetCustomRoles()
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[role.Role]role.Definition, len(customRoles))
+	for _, definition := range customRoles {
+		definitions[definition.Name] = definition
+	}
+	r.cache.definitions = definitions
+	r.cache.expireAt = now.Add(RoleCacheLifetime)
+	return definitions, nil
+}
+
+// NewRoleDefinitions creates RoleDefinitions.
+func NewRoleDefinitions(roleRepo repository.Role, timer timer.Timer) RoleDefinitions {
+	return RoleDefinitions{
+		roleRepo: roleRepo,
+		timer:    timer,
+		cache:    &roleCache{},
+	}
+}
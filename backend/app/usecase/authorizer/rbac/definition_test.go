@@ -0,0 +1,94 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestRBAC_HasPermission_CustomRole(t *testing.T) {
+	userRoles := map[string][]role.Role{"alpha": {role.Basic, "editor"}}
+	roleRepo := repository.NewRoleFake([]role.Definition{
+		{Name: "editor", Permissions: []permission.Permission{permission.EditChange}},
+	}, userRoles)
+	definitions := NewRoleDefinitions(&roleRepo, timer.NewStub(time.Now()))
+	ac := NewCustomRBAC(repository.NewUserRoleFake(userRoles), definitions)
+	user := entity.User{ID: "alpha"}
+
+	hasPermission, err := ac.HasPermission(user, permission.EditChange)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, hasPermission)
+
+	hasPermission, err = ac.HasPermission(user, permission.DeleteChange)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, hasPermission)
+
+	err = roleRepo.UpdateRole(role.Definition{
+		Name:        "editor",
+		Permissions: []permission.Permission{permission.DeleteChange},
+	})
+	assert.Equal(t, nil, err)
+
+	hasPermission, err = ac.HasPermission(user, permission.DeleteChange)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, hasPermission)
+
+	definitions.Invalidate()
+
+	hasPermission, err = ac.HasPermission(user, permission.DeleteChange)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, hasPermission)
+}
+
+func TestRBAC_IsRoleDefined(t *testing.T) {
+	roleRepo := repository.NewRoleFake([]role.Definition{
+		{Name: "editor", Permissions: []permission.Permission{permission.EditChange}},
+	}, map[string][]role.Role{})
+	definitions := NewRoleDefinitions(&roleRepo, timer.NewStub(time.Now()))
+
+	testCases := []struct {
+		name            string
+		ac              RBAC
+		role            role.Role
+		expectIsDefined bool
+	}{
+		{
+			name:            "built-in role",
+			ac:              NewRBAC(repository.NewUserRoleFake(map[string][]role.Role{})),
+			role:            role.Admin,
+			expectIsDefined: true,
+		},
+		{
+			name:            "custom role without storage",
+			ac:              NewRBAC(repository.NewUserRoleFake(map[string][]role.Role{})),
+			role:            "editor",
+			expectIsDefined: false,
+		},
+		{
+			name:            "custom role",
+			ac:              NewCustomRBAC(repository.NewUserRoleFake(map[string][]role.Role{}), definitions),
+			role:            "editor",
+			expectIsDefined: true,
+		},
+		{
+			name:            "unknown role",
+			ac:              NewCustomRBAC(repository.NewUserRoleFake(map[string][]role.Role{}), definitions),
+			role:            "owner",
+			expectIsDefined: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gotIsDefined, err := testCase.ac.IsRoleDefined(testCase.role)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectIsDefined, gotIsDefined)
+		})
+	}
+}
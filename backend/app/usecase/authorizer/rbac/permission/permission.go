@@ -11,16 +11,81 @@ const (
 	EditShortLink
 	DisableShortLink
 	DeleteShortLink
+	ViewShortLinkAnalytics
+	ManageShortLinkAttribute
 
 	CreateChange
 	ViewChange
 	EditChange
 	DeleteChange
 
+	ViewUser
 	UpgradeUser
 	DowngradeUser
 	DisableUser
 	DeleteUser
 
 	CreateAPIKey
+	ViewAPIKey
+	RevokeAPIKey
+
+	UseRestrictedAlias
+
+	ViewSession
+	RevokeSession
+
+	ViewRole
+	ManageRole
 )
+
+var names = map[Permission]string{
+	ViewAdminPanel: "view_admin_panel",
+
+	CreateShortLink:  "create_short_link",
+	ViewShortLink:    "view_short_link",
+	EditShortLink:    "edit_short_link",
+	DisableShortLink: "disable_short_link",
+	DeleteShortLink:  "delete_short_link",
+
+	ViewShortLinkAnalytics:   "view_short_link_analytics",
+	ManageShortLinkAttribute: "manage_short_link_attribute",
+
+	CreateChange: "create_change",
+	ViewChange:   "view_change",
+	EditChange:   "edit_change",
+	DeleteChange: "delete_change",
+
+	ViewUser:      "view_user",
+	UpgradeUser:   "upgrade_user",
+	DowngradeUser: "downgrade_user",
+	DisableUser:   "disable_user",
+	DeleteUser:    "delete_user",
+
+	CreateAPIKey: "create_api_key",
+	ViewAPIKey:   "view_api_key",
+	RevokeAPIKey: "revoke_api_key",
+
+	UseRestrictedAlias: "use_restricted_alias",
+
+	ViewSession:   "view_session",
+	RevokeSession: "revoke_session",
+
+	ViewRole:   "view_role",
+	ManageRole: "manage_role",
+}
+
+// Name retrieves the stable name of the permission, which is used to store
+// the permissions of custom roles.
+func (p Permission) Name() string {
+	return names[p]
+}
+
+// Parse finds the permission with the given name.
+func Parse(name string) (Permission, bool) {
+	for permission, permissionName := range names {
+		if permissionName == name {
+			return permission, true
+		}
+	}
+	return 0, false
+}
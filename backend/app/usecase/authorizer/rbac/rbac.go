@@ -5,34 +5,81 @@ import (
 
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
 // RBAC represents Role-based access control authorization policy.
 type RBAC struct {
 	userRoleRepo repository.UserRole
+	definitions  RoleDefinitions
 }
 
 // HasPermission checks whether an user has a the given permission.
 func (a RBAC) HasPermission(user entity.User, permission permission.Permission) (bool, error) {
-	roles, err := a.userRoleRepo.GetRoles(user)
-	var entryErr repository.ErrEntryNotFound
-	if errors.As(err, &entryErr) {
-		return false, nil
+	roles, err := a.GetRoles(user)
+	if err != nil {
+		return false, err
 	}
+
+	for _, r := range roles {
+		definition, ok, err := a.definitions.GetDefinition(r)
+		if err != nil {
+			return false, err
+		}
+		if ok && definition.HasPermission(permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsPrivileged checks whether any role assigned to the user grants a
+// permission.
+func (a RBAC) IsPrivileged(user entity.User) (bool, error) {
+	roles, err := a.GetRoles(user)
 	if err != nil {
 		return false, err
 	}
 
-	for _, role := range roles {
-		if role.HasPermission(permission) {
+	for _, r := range roles {
+		definition, ok, err := a.definitions.GetDefinition(r)
+		if err != nil {
+			return false, err
+		}
+		if ok && len(definition.Permissions) > 0 {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
-// NewRBAC create RBAC.
+// IsRoleDefined checks whether the role is either built-in or created by
+// admins.
+func (a RBAC) IsRoleDefined(r role.Role) (bool, error) {
+	_, ok, err := a.definitions.GetDefinition(r)
+	return ok, err
+}
+
+// GetRoles fetches the roles assigned to an user.
+func (a RBAC) GetRoles(user entity.User) ([]role.Role, error) {
+	roles, err := a.userRoleRepo.GetRoles(user)
+	var entryErr repository.ErrEntryNotFound
+	if errors.As(err, &entryErr) {
+		return []role.Role{}, nil
+	}
+	return roles, err
+}
+
+// NewRBAC create RBAC which only recognizes built-in roles.
 func NewRBAC(userRoleRepo repository.UserRole) RBAC {
-	return RBAC{userRoleRepo: userRoleRepo}
+	return NewCustomRBAC(userRoleRepo, RoleDefinitions{})
+}
+
+// NewCustomRBAC create RBAC which recognizes both built-in and custom roles.
+func NewCustomRBAC(userRoleRepo repository.UserRole, definitions RoleDefinitions) RBAC {
+	return RBAC{
+		userRoleRepo: userRoleRepo,
+		definitions:  definitions,
+	}
 }
@@ -2,6 +2,7 @@ package rbac
 
 import (
 	"testing"
+	"time"
 
 	"github.com/short-d/app/fw/assert"
 	"github.com/short-d/short/backend/app/entity"
@@ -57,3 +58,142 @@ func TestRBAC_HasPermission(t *testing.T) {
 		})
 	}
 }
+
+func TestRBAC_GetRoles(t *testing.T) {
+	testCases := []struct {
+		name          string
+		user          entity.User
+		userRoles     map[string][]role.Role
+		expectedRoles []role.Role
+	}{
+		{
+			name:          "user not found",
+			user:          entity.User{ID: "alpha"},
+			userRoles:     map[string][]role.Role{},
+			expectedRoles: []role.Role{},
+		},
+		{
+			name: "user has roles",
+			user: entity.User{ID: "alpha"},
+			userRoles: map[string][]role.Role{"alpha": {
+				role.Basic,
+				role.Premium,
+			}},
+			expectedRoles: []role.Role{role.Basic, role.Premium},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeRolesRepo := repository.NewUserRoleFake(testCase.userRoles)
+			ac := NewRBAC(fakeRolesRepo)
+
+			gotRoles, err := ac.GetRoles(testCase.user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedRoles, gotRoles)
+		})
+	}
+}
+
+func TestRBAC_IsPrivileged(t *testing.T) {
+	testCases := []struct {
+		name               string
+		user               entity.User
+		userRoles          map[string][]role.Role
+		expectIsPrivileged bool
+	}{
+		{
+			name:               "has no role",
+			user:               entity.User{ID: "alpha"},
+			userRoles:          map[string][]role.Role{},
+			expectIsPrivileged: false,
+		},
+		{
+			name: "no role grants permissions",
+			user: entity.User{ID: "alpha"},
+			userRoles: map[string][]role.Role{"alpha": {
+				role.Basic,
+				role.Premium,
+			}},
+			expectIsPrivileged: false,
+		},
+		{
+			name: "one of the roles grants permissions",
+			user: entity.User{ID: "alpha"},
+			userRoles: map[string][]role.Role{"alpha": {
+				role.Basic,
+				role.ShortLinkViewer,
+			}},
+			expectIsPrivileged: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeRolesRepo := repository.NewUserRoleFake(testCase.userRoles)
+			ac := NewRBAC(fakeRolesRepo)
+
+			gotIsPrivileged, err := ac.IsPrivileged(testCase.user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectIsPrivileged, gotIsPrivileged)
+		})
+	}
+}
+
+func TestRBAC_RequiresTwoFactor(t *testing.T) {
+	policy := TwoFactorPolicy{
+		Roles: []role.Role{role.Admin, role.SecuritySpecialist},
+	}
+
+	testCases := []struct {
+		name             string
+		user             entity.User
+		userRoles        map[string][]role.Role
+		expectIsRequired bool
+	}{
+		{
+			name:             "has no role",
+			user:             entity.User{ID: "alpha"},
+			userRoles:        map[string][]role.Role{},
+			expectIsRequired: false,
+		},
+		{
+			name: "no role requires two factor",
+			user: entity.User{ID: "alpha"},
+			userRoles: map[string][]role.Role{"alpha": {
+				role.Basic,
+				role.ChangeLogEditor,
+			}},
+			expectIsRequired: false,
+		},
+		{
+			name: "one of the roles requires two factor",
+			user: entity.User{ID: "alpha"},
+			userRoles: map[string][]role.Role{"alpha": {
+				role.Basic,
+				role.SecuritySpecialist,
+			}},
+			expectIsRequired: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeRolesRepo := repository.NewUserRoleFake(testCase.userRoles)
+			ac := NewRBAC(fakeRolesRepo)
+
+			gotIsRequired, err := ac.RequiresTwoFactor(testCase.user, policy)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectIsRequired, gotIsRequired)
+		})
+	}
+}
+
+func TestTwoFactorPolicy_IsEnforced(t *testing.T) {
+	enforceFrom := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	policy := TwoFactorPolicy{EnforceFrom: enforceFrom}
+
+	assert.Equal(t, false, policy.IsEnforced(enforceFrom.Add(-time.Second)))
+	assert.Equal(t, true, policy.IsEnforced(enforceFrom))
+	assert.Equal(t, true, policy.IsEnforced(enforceFrom.Add(time.Hour)))
+}
@@ -1,6 +1,10 @@
 package role
 
-import "github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+import (
+	"sort"
+
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+)
 
 // Role contains a list of permissions
 type Role string
@@ -8,6 +12,9 @@ type Role string
 const (
 	Basic Role = "basic"
 
+	// Premium is granted to paying users who enjoy higher quotas.
+	Premium Role = "premium"
+
 	SecuritySpecialist Role = "security_specialist"
 
 	ShortLinkViewer Role = "short_link_viewer"
@@ -20,7 +27,8 @@ const (
 )
 
 var permissions = map[Role][]permission.Permission{
-	Basic: {},
+	Basic:   {},
+	Premium: {},
 	ShortLinkViewer: {
 		permission.ViewAdminPanel,
 
@@ -52,7 +60,16 @@ var permissions = map[Role][]permission.Permission{
 		permission.ViewAdminPanel,
 
 		permission.DisableShortLink,
+		permission.ViewUser,
 		permission.DisableUser,
+
+		permission.ViewAPIKey,
+		permission.RevokeAPIKey,
+
+		permission.ViewSession,
+		permission.RevokeSession,
+
+		permission.ViewRole,
 	},
 	Admin: {
 		permission.ViewAdminPanel,
@@ -62,27 +79,75 @@ var permissions = map[Role][]permission.Permission{
 		permission.EditShortLink,
 		permission.DisableShortLink,
 		permission.DeleteShortLink,
+		permission.ManageShortLinkAttribute,
 
 		permission.ViewChange,
 		permission.CreateChange,
 		permission.EditChange,
 		permission.DeleteChange,
 
+		permission.ViewUser,
 		permission.UpgradeUser,
 		permission.DowngradeUser,
 		permission.DisableUser,
 		permission.DeleteUser,
 
 		permission.CreateAPIKey,
+		permission.ViewAPIKey,
+		permission.RevokeAPIKey,
+
+		permission.UseRestrictedAlias,
+
+		permission.ViewSession,
+		permission.RevokeSession,
+
+		permission.ViewRole,
+		permission.ManageRole,
 	},
 }
 
-// HasPermission checks whether a role grants the requested permission.
-func (r Role) HasPermission(permission permission.Permission) bool {
-	for _, value := range permissions[r] {
+// BuiltInPermissions retrieves the permissions granted by a built-in role.
+func BuiltInPermissions(r Role) []permission.Permission {
+	return permissions[r]
+}
+
+// IsBuiltIn checks whether the role is defined in code rather than created
+// by admins. Built-in roles can't be changed.
+func (r Role) IsBuiltIn() bool {
+	_, ok := permissions[r]
+	return ok
+}
+
+// Definition represents a role together with the permissions it grants.
+type Definition struct {
+	Name        Role
+	Permissions []permission.Permission
+	IsBuiltIn   bool
+}
+
+// HasPermission checks whether the role grants the requested permission.
+func (d Definition) HasPermission(permission permission.Permission) bool {
+	for _, value := range d.Permissions {
 		if value == permission {
 			return true
 		}
 	}
 	return false
 }
+
+// BuiltIns retrieves the definitions of all the built-in roles ordered by
+// name.
+func BuiltIns() []Definition {
+	definitions := make([]Definition, 0, len(permissions))
+	for name, rolePermissions := range permissions {
+		definitions = append(definitions, Definition{
+			Name:        name,
+			Permissions: rolePermissions,
+			IsBuiltIn:   true,
+		})
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
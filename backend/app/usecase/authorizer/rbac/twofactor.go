@@ -0,0 +1,43 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+// TwoFactorPolicy lists the roles whose users must sign in with a second
+// factor. Enforcement starts at EnforceFrom, leaving the users a grace period
+// to enroll.
+type TwoFactorPolicy struct {
+	Roles       []role.Role
+	EnforceFrom time.Time
+}
+
+// IsRequired checks whether any of the given roles requires a second factor.
+func (p TwoFactorPolicy) IsRequired(roles []role.Role) bool {
+	for _, userRole := range roles {
+		for _, requiredRole := range p.Roles {
+			if userRole == requiredRole {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsEnforced checks whether the grace period is over at the given time.
+func (p TwoFactorPolicy) IsEnforced(now time.Time) bool {
+	return !now.Before(p.EnforceFrom)
+}
+
+// RequiresTwoFactor checks whether the user is assigned any role which
+// requires a second factor under the given policy.
+func (a RBAC) RequiresTwoFactor(user entity.User, policy TwoFactorPolicy) (bool, error) {
+	roles, err := a.GetRoles(user)
+	if err != nil {
+		return false, err
+	}
+	return policy.IsRequired(roles), nil
+}
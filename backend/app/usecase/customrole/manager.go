@@ -0,0 +1,233 @@
+package customrole
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// ErrUnauthorizedAction represents the failure of managing roles without the
+// required permission.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrInvalidRoleName represents the failure of naming a role with anything
+// other than 1 to 50 lowercase letters, digits and underscores.
+type ErrInvalidRoleName string
+
+var _ error = (*ErrInvalidRoleName)(nil)
+
+func (e ErrInvalidRoleName) Error() string {
+	return fmt.Sprintf("role name(%s) is invalid", string(e))
+}
+
+// ErrUnknownPermission represents the failure of granting a permission which
+// is not defined to a role.
+type ErrUnknownPermission string
+
+var _ error = (*ErrUnknownPermission)(nil)
+
+func (e ErrUnknownPermission) Error() string {
+	return fmt.Sprintf("permission(%s) is unknown", string(e))
+}
+
+// ErrRoleExists represents the failure of creating a role which is already
+// defined.
+type ErrRoleExists string
+
+var _ error = (*ErrRoleExists)(nil)
+
+func (e ErrRoleExists) Error() string {
+	return fmt.Sprintf("role(%s) already exists", string(e))
+}
+
+// ErrRoleNotFound represents the failure of changing a custom role which
+// doesn't exist.
+type ErrRoleNotFound string
+
+var _ error = (*ErrRoleNotFound)(nil)
+
+func (e ErrRoleNotFound) Error() string {
+	return fmt.Sprintf("role(%s) not found", string(e))
+}
+
+// ErrBuiltInRole represents the failure of changing a built-in role.
+type ErrBuiltInRole string
+
+var _ error = (*ErrBuiltInRole)(nil)
+
+func (e ErrBuiltInRole) Error() string {
+	return fmt.Sprintf("role(%s) is built-in and can't be changed", string(e))
+}
+
+// Manager lists roles, and creates, updates and deletes custom roles on
+// behalf of admins.
+type Manager struct {
+	roleRepo    repository.Role
+	definitions rbac.RoleDefinitions
+	authorizer  authorizer.Authorizer
+	timer       timer.Timer
+}
+
+// GetRoles retrieves the definitions of all the roles, with the built-in ones
+// first.
+func (m Manager) GetRoles(viewer entity.User) ([]role.Definition, error) {
+	err := m.checkPermission(viewer, m.authorizer.CanViewRoles, "view roles")
+	if err != nil {
+		return nil, err
+	}
+	return m.definitions.GetDefinitions()
+}
+
+// CreateRole creates a custom role granting the given permissions.
+func (m Manager) CreateRole(
+	actor entity.User,
+	name string,
+	permissionNames []string,
+) (role.Definition, error) {
+	definition, err := m.prepareChange(actor, name, permissionNames, "create")
+	if err != nil {
+		return role.Definition{}, err
+	}
+
+	err = m.roleRepo.CreateRole(definition)
+	var exists repository.ErrEntryExists
+	if errors.As(err, &exists) {
+		return role.Definition{}, ErrRoleExists(name)
+	}
+	if err != nil {
+		return role.Definition{}, err
+	}
+	m.definitions.Invalidate()
+	return definition, nil
+}
+
+// UpdateRole replaces the permissions granted by a custom role.
+func (m Manager) UpdateRole(
+	actor entity.User,
+	name string,
+	permissionNames []string,
+) (role.Definition, error) {
+	definition, err := m.prepareChange(actor, name, permissionNames, "update")
+	if err != nil {
+		return role.Definition{}, err
+	}
+
+	err = m.roleRepo.UpdateRole(definition)
+	var notFound repository.ErrEntryNotFound
+	if errors.As(err, &notFound) {
+		return role.Definition{}, ErrRoleNotFound(name)
+	}
+	if err != nil {
+		return role.Definition{}, err
+	}
+	m.definitions.Invalidate()
+	return definition, nil
+}
+
+// DeleteRole deletes a custom role and revokes it from all of its users,
+// recording each revocation on behalf of the actor.
+func (m Manager) DeleteRole(actor entity.User, name string) error {
+	_, err := m.prepareChange(actor, name, []string{}, "delete")
+	if err != nil {
+		return err
+	}
+
+	err = m.roleRepo.DeleteRole(role.Role(name), actor.ID, m.timer.Now().UTC())
+	var notFound repository.ErrEntryNotFound
+	if errors.As(err, &notFound) {
+		return ErrRoleNotFound(name)
+	}
+	if err != nil {
+		return err
+	}
+	m.definitions.Invalidate()
+	return nil
+}
+
+func (m Manager) prepareChange(
+	actor entity.User,
+	name string,
+	permissionNames []string,
+	action string,
+) (role.Definition, error) {
+	err := m.checkPermission(
+		actor,
+		m.authorizer.CanManageRoles,
+		fmt.Sprintf("%s role(%s)", action, name),
+	)
+	if err != nil {
+		return role.Definition{}, err
+	}
+
+	if !roleNamePattern.MatchString(name) {
+		return role.Definition{}, ErrInvalidRoleName(name)
+	}
+	r := role.Role(name)
+	if r.IsBuiltIn() {
+		return role.Definition{}, ErrBuiltInRole(name)
+	}
+
+	permissions := make([]permission.Permission, 0, len(permissionNames))
+	isAdded := make(map[permission.Permission]bool)
+	for _, permissionName := range permissionNames {
+		p, ok := permission.Parse(permissionName)
+		if !ok {
+			return role.Definition{}, ErrUnknownPermission(permissionName)
+		}
+		if isAdded[p] {
+			continue
+		}
+		isAdded[p] = true
+		permissions = append(permissions, p)
+	}
+	return role.Definition{Name: r, Permissions: permissions}, nil
+}
+
+func (m Manager) checkPermission(
+	user entity.User,
+	can func(user entity.User) (bool, error),
+	action string,
+) error {
+	isAllowed, err := can(user)
+	if err != nil {
+		return err
+	}
+	if !isAllowed {
+		return ErrUnauthorizedAction{UserID: user.ID, Action: action}
+	}
+	return nil
+}
+
+// NewManager creates Manager.
+func NewManager(
+	roleRepo repository.Role,
+	definitions rbac.RoleDefinitions,
+	authorizer authorizer.Authorizer,
+	timer timer.Timer,
+) Manager {
+	return Manager{
+		roleRepo:    roleRepo,
+		definitions: definitions,
+		authorizer:  authorizer,
+		timer:       timer,
+	}
+}
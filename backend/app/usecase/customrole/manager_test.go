@@ -0,0 +1,359 @@
+// +build !integration all
+
+package customrole
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestManager_GetRoles(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+	editor := role.Definition{
+		Name:        "editor",
+		Permissions: []permission.Permission{permission.EditChange},
+	}
+
+	testCases := []struct {
+		name        string
+		roles       map[string][]role.Role
+		viewer      entity.User
+		hasErr      bool
+		expectedErr error
+	}{
+		{
+			name:        "viewer without permission",
+			roles:       map[string][]role.Role{"alpha": {role.Basic}},
+			viewer:      entity.User{ID: "alpha"},
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "view roles"},
+		},
+		{
+			name:   "viewer with permission",
+			roles:  map[string][]role.Role{"alpha": {role.SecuritySpecialist}},
+			viewer: entity.User{ID: "alpha"},
+			hasErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, _, _ := newManager(testCase.roles, []role.Definition{editor}, now)
+
+			gotDefinitions, err := manager.GetRoles(testCase.viewer)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, append(role.BuiltIns(), editor), gotDefinitions)
+		})
+	}
+}
+
+func TestManager_CreateRole(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name               string
+		roles              map[string][]role.Role
+		actor              entity.User
+		roleName           string
+		permissions        []string
+		hasErr             bool
+		expectedErr        error
+		expectedDefinition role.Definition
+	}{
+		{
+			name:        "actor without permission",
+			roles:       map[string][]role.Role{"alpha": {role.SecuritySpecialist}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			permissions: []string{"edit_change"},
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "create role(editor)"},
+		},
+		{
+			name:        "invalid name",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "Change Editor",
+			permissions: []string{"edit_change"},
+			hasErr:      true,
+			expectedErr: ErrInvalidRoleName("Change Editor"),
+		},
+		{
+			name:        "built-in role",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "admin",
+			permissions: []string{"edit_change"},
+			hasErr:      true,
+			expectedErr: ErrBuiltInRole("admin"),
+		},
+		{
+			name:        "unknown permission",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			permissions: []string{"edit_change", "fly"},
+			hasErr:      true,
+			expectedErr: ErrUnknownPermission("fly"),
+		},
+		{
+			name:        "role exists",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "auditor",
+			permissions: []string{"edit_change"},
+			hasErr:      true,
+			expectedErr: ErrRoleExists("auditor"),
+		},
+		{
+			name:        "role created",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			permissions: []string{"view_change", "edit_change", "view_change"},
+			hasErr:      false,
+			expectedDefinition: role.Definition{
+				Name: "editor",
+				Permissions: []permission.Permission{
+					permission.ViewChange,
+					permission.EditChange,
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			auditor := role.Definition{Name: "auditor", Permissions: []permission.Permission{}}
+			manager, ac, _ := newManager(testCase.roles, []role.Definition{auditor}, now)
+
+			gotDefinition, err := manager.CreateRole(testCase.actor, testCase.roleName, testCase.permissions)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedDefinition, gotDefinition)
+
+			isDefined, err := ac.IsRoleDefined(role.Role(testCase.roleName))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, isDefined)
+		})
+	}
+}
+
+func TestManager_UpdateRole(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name        string
+		roles       map[string][]role.Role
+		actor       entity.User
+		roleName    string
+		permissions []string
+		hasErr      bool
+		expectedErr error
+	}{
+		{
+			name:        "actor without permission",
+			roles:       map[string][]role.Role{"alpha": {role.SecuritySpecialist}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			permissions: []string{"delete_change"},
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "update role(editor)"},
+		},
+		{
+			name:        "built-in role",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "premium",
+			permissions: []string{"delete_change"},
+			hasErr:      true,
+			expectedErr: ErrBuiltInRole("premium"),
+		},
+		{
+			name:        "role not found",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "auditor",
+			permissions: []string{"delete_change"},
+			hasErr:      true,
+			expectedErr: ErrRoleNotFound("auditor"),
+		},
+		{
+			name: "role updated",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {"editor"},
+			},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			permissions: []string{"delete_change"},
+			hasErr:      false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			editor := role.Definition{
+				Name:        "editor",
+				Permissions: []permission.Permission{permission.EditChange},
+			}
+			manager, ac, _ := newManager(testCase.roles, []role.Definition{editor}, now)
+
+			_, err := manager.UpdateRole(testCase.actor, testCase.roleName, testCase.permissions)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			beta := entity.User{ID: "beta"}
+			hasPermission, err := ac.HasPermission(beta, permission.DeleteChange)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, hasPermission)
+
+			hasPermission, err = ac.HasPermission(beta, permission.EditChange)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, false, hasPermission)
+		})
+	}
+}
+
+func TestManager_DeleteRole(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name                string
+		roles               map[string][]role.Role
+		actor               entity.User
+		roleName            string
+		hasErr              bool
+		expectedErr         error
+		expectedRoles       []role.Role
+		expectedRoleChanges []entity.RoleChange
+	}{
+		{
+			name:        "actor without permission",
+			roles:       map[string][]role.Role{"alpha": {role.SecuritySpecialist}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "editor",
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "delete role(editor)"},
+		},
+		{
+			name:        "built-in role",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "admin",
+			hasErr:      true,
+			expectedErr: ErrBuiltInRole("admin"),
+		},
+		{
+			name:        "role not found",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			roleName:    "auditor",
+			hasErr:      true,
+			expectedErr: ErrRoleNotFound("auditor"),
+		},
+		{
+			name: "role deleted",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {role.Premium, "editor"},
+			},
+			actor:         entity.User{ID: "alpha"},
+			roleName:      "editor",
+			hasErr:        false,
+			expectedRoles: []role.Role{role.Premium},
+			expectedRoleChanges: []entity.RoleChange{
+				{
+					ActorID:   "alpha",
+					UserID:    "beta",
+					Role:      "editor",
+					Action:    entity.RoleRevoked,
+					CreatedAt: now,
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			editor := role.Definition{
+				Name:        "editor",
+				Permissions: []permission.Permission{permission.EditChange},
+			}
+			manager, ac, roleRepo := newManager(testCase.roles, []role.Definition{editor}, now)
+
+			err := manager.DeleteRole(testCase.actor, testCase.roleName)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			isDefined, err := ac.IsRoleDefined(role.Role(testCase.roleName))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, false, isDefined)
+
+			roles, err := ac.GetRoles(entity.User{ID: "beta"})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+			assert.Equal(t, testCase.expectedRoleChanges, roleRepo.RoleChanges())
+		})
+	}
+}
+
+func newManager(
+	roles map[string][]role.Role,
+	definitions []role.Definition,
+	now time.Time,
+) (Manager, rbac.RBAC, *repository.RoleFake) {
+	userRoles := map[string][]role.Role{}
+	for userID, userRole := range roles {
+		userRoles[userID] = append([]role.Role{}, userRole...)
+	}
+
+	roleRepo := repository.NewRoleFake(append([]role.Definition{}, definitions...), userRoles)
+	roleDefinitions := rbac.NewRoleDefinitions(&roleRepo, timer.NewStub(now))
+	ac := rbac.NewCustomRBAC(repository.NewUserRoleFake(userRoles), roleDefinitions)
+	manager := NewManager(&roleRepo, roleDefinitions, authorizer.NewAuthorizer(ac), timer.NewStub(now))
+	return manager, ac, &roleRepo
+}
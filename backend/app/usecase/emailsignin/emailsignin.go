@@ -0,0 +1,264 @@
+package emailsignin
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+)
+
+// TokenLifetime is how long a sign in link can be used after it is sent.
+const TokenLifetime = 15 * time.Minute
+
+// IdentityProvider is recorded on the sessions started with a sign in link.
+const IdentityProvider = "email"
+
+const (
+	tokenPurpose   = "email_sign_in"
+	maxEmailLength = 254
+)
+
+// ErrInvalidEmail represents an email address which a sign in link can't be
+// sent to.
+type ErrInvalidEmail string
+
+var _ error = (*ErrInvalidEmail)(nil)
+
+func (e ErrInvalidEmail) Error() string {
+	return fmt.Sprintf("invalid email address: %s", string(e))
+}
+
+// ErrTooManyRequests represents the failure of requesting another sign in
+// link for the same email address too soon.
+type ErrTooManyRequests struct {
+	RetryAfter time.Duration
+}
+
+var _ error = (*ErrTooManyRequests)(nil)
+
+func (e ErrTooManyRequests) Error() string {
+	return fmt.Sprintf("too many sign in links requested, retry after %s", e.RetryAfter)
+}
+
+// ErrInvalidToken represents a sign in token which is forged, expired or
+// already used.
+type ErrInvalidToken struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidToken)(nil)
+
+func (e ErrInvalidToken) Error() string {
+	return fmt.Sprintf("invalid sign in token: %s", e.Reason)
+}
+
+// EmailSignIn signs users in with one-time links sent to their email
+// addresses, for those without an account of any external identity provider.
+type EmailSignIn struct {
+	tokenRepo   repository.EmailSignInToken
+	userRepo    repository.User
+	keyGen      keygen.KeyGenerator
+	mailer      Mailer
+	limiter     ratelimit.Limiter
+	limit       ratelimit.Limit
+	tokenizer   crypto.Tokenizer
+	timer       timer.Timer
+	twoFactor   twofactor.TwoFactor
+	callbackURL url.URL
+}
+
+// RequestLink emails a sign in link to the given address. Each address can
+// only request a limited number of links in a period of time.
+func (e EmailSignIn) RequestLink(email string) error {
+	address, err := parseEmail(email)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("email-sign-in:%s", address)
+	decision, err := e.limiter.Allow(key, e.limit)
+	if err != nil {
+		return err
+	}
+	if !decision.IsAllowed {
+		return ErrTooManyRequests{RetryAfter: decision.RetryAfter}
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return err
+	}
+
+	now := e.timer.Now()
+	token := entity.EmailSignInToken{
+		ID:        tokenID,
+		Email:     address,
+		CreatedAt: now,
+		ExpireAt:  now.Add(TokenLifetime),
+	}
+	err = e.tokenRepo.CreateToken(token)
+	if err != nil {
+		return err
+	}
+
+	signedToken, err := e.tokenizer.Encode(crypto.TokenPayload{
+		"purpose":   tokenPurpose,
+		"token_id":  token.ID,
+		"email":     token.Email,
+		"expire_at": token.ExpireAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := e.callbackURL
+	query := link.Query()
+	query.Set("token", signedToken)
+	link.RawQuery = query.Encode()
+
+	return e.mailer.SendEmail(Email{
+		To:      address,
+		Subject: "Sign in to Short",
+		Body: fmt.Sprintf(
+			"Open the link below to sign in to Short. It expires in %d minutes "+
+				"and can only be used once.\n\n%s\n\n"+
+				"If you did not request it, you can safely ignore this email.\n",
+			int(TokenLifetime.Minutes()),
+			link.String(),
+		),
+	})
+}
+
+// SignIn redeems the token in a sign in link and starts a new session on the
+// given device. A new user is created for an email address never seen
+// before. It returns twofactor.ErrSecondFactorRequired when the user has to
+// complete the sign in with a second factor.
+func (e EmailSignIn) SignIn(signedToken string, device entity.Device) (authenticator.AuthTokens, error) {
+	payload, err := e.tokenizer.Decode(signedToken)
+	if err != nil {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "signature mismatch"}
+	}
+	if payload["purpose"] != tokenPurpose {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "issued for another purpose"}
+	}
+	tokenID, ok := payload["token_id"].(string)
+	if !ok || tokenID == "" {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "token ID missing"}
+	}
+
+	now := e.timer.Now()
+	expireAtStr, _ := payload["expire_at"].(string)
+	expireAt, err := time.Parse(time.RFC3339, expireAtStr)
+	if err != nil {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "expiration malformed"}
+	}
+	if !now.Before(expireAt) {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "token expired"}
+	}
+
+	token, err := e.tokenRepo.UseToken(tokenID, now)
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "token expired or already used"}
+	}
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+	if payload["email"] != token.Email {
+		return authenticator.AuthTokens{}, ErrInvalidToken{Reason: "email mismatch"}
+	}
+
+	user, err := e.getOrCreateUser(token.Email)
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+	return e.twoFactor.SignIn(user, IdentityProvider, device)
+}
+
+func (e EmailSignIn) getOrCreateUser(email string) (entity.User, error) {
+	user, err := e.userRepo.GetUserByEmail(email)
+	if err == nil {
+		return user, nil
+	}
+
+	var errNotFound repository.ErrEntryNotFound
+	if !errors.As(err, &errNotFound) {
+		return entity.User{}, err
+	}
+
+	key, err := e.keyGen.NewKey()
+	if err != nil {
+		return entity.User{}, err
+	}
+	user = entity.User{
+		ID:    string(key),
+		Email: email,
+	}
+	err = e.userRepo.CreateUser(user)
+	return user, err
+}
+
+// parseEmail accepts a bare email address, such as alpha@example.com, and
+// rejects display names and address lists. The address is lowercased so that
+// the same mailbox is always rate limited, looked up and signed in as the same
+// user regardless of how it is typed.
+func parseEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if len(email) > maxEmailLength {
+		return "", ErrInvalidEmail(email)
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", ErrInvalidEmail(email)
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+func newTokenID() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// NewEmailSignIn creates EmailSignIn. The sign in links point to the
+// callbackURL, with the token in the token query parameter.
+func NewEmailSignIn(
+	tokenRepo repository.EmailSignInToken,
+	userRepo repository.User,
+	keyGen keygen.KeyGenerator,
+	mailer Mailer,
+	limiter ratelimit.Limiter,
+	limit ratelimit.Limit,
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+	twoFactor twofactor.TwoFactor,
+	callbackURL url.URL,
+) EmailSignIn {
+	return EmailSignIn{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		keyGen:      keyGen,
+		mailer:      mailer,
+		limiter:     limiter,
+		limit:       limit,
+		tokenizer:   tokenizer,
+		timer:       timer,
+		twoFactor:   twoFactor,
+		callbackURL: callbackURL,
+	}
+}
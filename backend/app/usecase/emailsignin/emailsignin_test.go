@@ -0,0 +1,329 @@
+// +build !integration all
+
+package emailsignin
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+)
+
+type emailSignInDeps struct {
+	userRepo      *repository.UserFake
+	mailer        MailerFake
+	authenticator authenticator.Authenticator
+}
+
+func newEmailSignInFake(
+	t *testing.T,
+	now time.Time,
+	users []entity.User,
+	limit ratelimit.Limit,
+	isMailerDown bool,
+) (EmailSignIn, emailSignInDeps) {
+	tokenRepo := repository.NewEmailSignInTokenFake([]entity.EmailSignInToken{})
+	userRepo := repository.NewUserFake(users)
+
+	keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{"user1", "user2"})
+	keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+	assert.Equal(t, nil, err)
+
+	tm := timer.NewStub(now)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(tm), tm)
+	mailer := NewMailerFake(isMailerDown)
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+	callbackURL, err := url.Parse("https://short.example.com/email/sign-in/callback")
+	assert.Equal(t, nil, err)
+
+	emailSignIn := NewEmailSignIn(
+		&tokenRepo,
+		&userRepo,
+		keyGen,
+		mailer,
+		limiter,
+		limit,
+		crypto.NewTokenizerFake(),
+		tm,
+		twofactor.NewTwoFactorFake(now, auth),
+		*callbackURL,
+	)
+	return emailSignIn, emailSignInDeps{
+		userRepo:      &userRepo,
+		mailer:        mailer,
+		authenticator: auth,
+	}
+}
+
+func getSignInToken(t *testing.T, email Email) string {
+	for _, word := range strings.Fields(email.Body) {
+		if !strings.HasPrefix(word, "https://short.example.com/email/sign-in/callback?") {
+			continue
+		}
+		link, err := url.Parse(word)
+		assert.Equal(t, nil, err)
+		return link.Query().Get("token")
+	}
+	t.Fatalf("sign in link not found in %s", email.Body)
+	return ""
+}
+
+func TestEmailSignIn_RequestLink(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	limit := ratelimit.Limit{Requests: 5, Window: time.Hour}
+
+	testCases := []struct {
+		name         string
+		email        string
+		isMailerDown bool
+		hasErr       bool
+		expectedErr  error
+		expectedTo   string
+	}{
+		{
+			name:        "empty email",
+			email:       "",
+			hasErr:      true,
+			expectedErr: ErrInvalidEmail(""),
+		},
+		{
+			name:        "not an email",
+			email:       "alpha",
+			hasErr:      true,
+			expectedErr: ErrInvalidEmail("alpha"),
+		},
+		{
+			name:        "display name",
+			email:       "Alpha <alpha@example.com>",
+			hasErr:      true,
+			expectedErr: ErrInvalidEmail("Alpha <alpha@example.com>"),
+		},
+		{
+			name:        "multiple addresses",
+			email:       "alpha@example.com, beta@example.com",
+			hasErr:      true,
+			expectedErr: ErrInvalidEmail("alpha@example.com, beta@example.com"),
+		},
+		{
+			name:         "mail server down",
+			email:        "alpha@example.com",
+			isMailerDown: true,
+			hasErr:       true,
+		},
+		{
+			name:       "link sent",
+			email:      " alpha@example.com ",
+			hasErr:     false,
+			expectedTo: "alpha@example.com",
+		},
+		{
+			name:       "link sent to lowercase address",
+			email:      "Alpha@Example.com",
+			hasErr:     false,
+			expectedTo: "alpha@example.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			emailSignIn, deps := newEmailSignInFake(t, now, []entity.User{}, limit, testCase.isMailerDown)
+
+			err := emailSignIn.RequestLink(testCase.email)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				if testCase.expectedErr != nil {
+					assert.Equal(t, testCase.expectedErr, err)
+				}
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			sentEmails := deps.mailer.SentEmails()
+			assert.Equal(t, 1, len(sentEmails))
+			assert.Equal(t, testCase.expectedTo, sentEmails[0].To)
+			assert.NotEqual(t, "", getSignInToken(t, sentEmails[0]))
+		})
+	}
+}
+
+func TestEmailSignIn_RequestLinkRateLimit(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	limit := ratelimit.Limit{Requests: 2, Window: time.Hour}
+	emailSignIn, deps := newEmailSignInFake(t, now, []entity.User{}, limit, false)
+
+	err := emailSignIn.RequestLink("alpha@example.com")
+	assert.Equal(t, nil, err)
+	err = emailSignIn.RequestLink("Alpha@Example.com")
+	assert.Equal(t, nil, err)
+
+	err = emailSignIn.RequestLink("alpha@example.com")
+	assert.Equal(t, ErrTooManyRequests{RetryAfter: 30 * time.Minute}, err)
+
+	err = emailSignIn.RequestLink("beta@example.com")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(deps.mailer.SentEmails()))
+}
+
+func TestEmailSignIn_SignIn(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	limit := ratelimit.Limit{Requests: 5, Window: time.Hour}
+	device := entity.Device{ClientIP: "10.0.0.1", UserAgent: "Mozilla/5.0"}
+
+	testCases := []struct {
+		name           string
+		users          []entity.User
+		token          func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string
+		elapsed        time.Duration
+		hasErr         bool
+		expectedUserID string
+	}{
+		{
+			name:  "malformed token",
+			users: []entity.User{},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				return "malformed"
+			},
+			hasErr: true,
+		},
+		{
+			name:  "issued for another purpose",
+			users: []entity.User{},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				token, err := crypto.NewTokenizerFake().Encode(crypto.TokenPayload{
+					"purpose":   "link_account",
+					"token_id":  "token1",
+					"email":     "alpha@example.com",
+					"expire_at": "2020-07-20T10:15:00Z",
+				})
+				assert.Equal(t, nil, err)
+				return token
+			},
+			hasErr: true,
+		},
+		{
+			name:  "token not issued",
+			users: []entity.User{},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				token, err := crypto.NewTokenizerFake().Encode(crypto.TokenPayload{
+					"purpose":   tokenPurpose,
+					"token_id":  "token1",
+					"email":     "alpha@example.com",
+					"expire_at": "2020-07-20T10:15:00Z",
+				})
+				assert.Equal(t, nil, err)
+				return token
+			},
+			hasErr: true,
+		},
+		{
+			name:  "token expired",
+			users: []entity.User{},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				err := emailSignIn.RequestLink("alpha@example.com")
+				assert.Equal(t, nil, err)
+				return getSignInToken(t, mailer.SentEmails()[0])
+			},
+			elapsed: TokenLifetime,
+			hasErr:  true,
+		},
+		{
+			name:  "token already used",
+			users: []entity.User{},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				err := emailSignIn.RequestLink("alpha@example.com")
+				assert.Equal(t, nil, err)
+				token := getSignInToken(t, mailer.SentEmails()[0])
+
+				_, err = emailSignIn.SignIn(token, entity.Device{})
+				assert.Equal(t, nil, err)
+				return token
+			},
+			hasErr: true,
+		},
+		{
+			name: "existing user",
+			users: []entity.User{
+				{ID: "alpha", Email: "alpha@example.com"},
+			},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				err := emailSignIn.RequestLink("alpha@example.com")
+				assert.Equal(t, nil, err)
+				return getSignInToken(t, mailer.SentEmails()[0])
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "alpha",
+		},
+		{
+			name: "existing user with email in another case",
+			users: []entity.User{
+				{ID: "alpha", Email: "alpha@example.com"},
+			},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				err := emailSignIn.RequestLink("ALPHA@example.com")
+				assert.Equal(t, nil, err)
+				return getSignInToken(t, mailer.SentEmails()[0])
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "alpha",
+		},
+		{
+			name: "new user",
+			users: []entity.User{
+				{ID: "alpha", Email: "alpha@example.com"},
+			},
+			token: func(t *testing.T, emailSignIn EmailSignIn, mailer MailerFake) string {
+				err := emailSignIn.RequestLink("beta@example.com")
+				assert.Equal(t, nil, err)
+				return getSignInToken(t, mailer.SentEmails()[0])
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "user1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			emailSignIn, deps := newEmailSignInFake(t, now, testCase.users, limit, false)
+			token := testCase.token(t, emailSignIn, deps.mailer)
+
+			emailSignIn.timer = timer.NewStub(now.Add(testCase.elapsed))
+			authTokens, err := emailSignIn.SignIn(token, device)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			user, err := deps.authenticator.GetUser(authTokens.AccessToken)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedUserID, user.ID)
+			assert.Equal(t, true, deps.userRepo.IsUserIDExist(testCase.expectedUserID))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package emailsignin
+
+// Email represents a plain text email sent to a single recipient.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails, such as through an SMTP server.
+type Mailer interface {
+	SendEmail(email Email) error
+}
@@ -0,0 +1,34 @@
+package emailsignin
+
+import "errors"
+
+var _ Mailer = (*MailerFake)(nil)
+
+// MailerFake keeps the sent emails in memory instead of delivering them.
+type MailerFake struct {
+	sentEmails *[]Email
+	isDown     bool
+}
+
+// SendEmail records the email as sent.
+func (m MailerFake) SendEmail(email Email) error {
+	if m.isDown {
+		return errors.New("mail server is down")
+	}
+	*m.sentEmails = append(*m.sentEmails, email)
+	return nil
+}
+
+// SentEmails retrieves the emails sent so far, with the earliest first.
+func (m MailerFake) SentEmails() []Email {
+	return append([]Email{}, *m.sentEmails...)
+}
+
+// NewMailerFake creates MailerFake. A mailer which is down fails to send any
+// email.
+func NewMailerFake(isDown bool) MailerFake {
+	return MailerFake{
+		sentEmails: &[]Email{},
+		isDown:     isDown,
+	}
+}
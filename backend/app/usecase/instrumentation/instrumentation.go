@@ -29,6 +29,10 @@ type Instrumentation struct {
 	searchFailedCh                  chan ctx.ExecutionContext
 	madeFeatureDecisionCh           chan ctx.ExecutionContext
 	trackCh                         chan ctx.ExecutionContext
+	linkHealthCheckedCh             chan ctx.ExecutionContext
+	linkHealthCheckFailedCh         chan ctx.ExecutionContext
+	rpcHandledCh                    chan ctx.ExecutionContext
+	rpcFailedCh                     chan ctx.ExecutionContext
 }
 
 // RedirectingAliasToLongLink tracks RedirectingAliasToLongLink event.
@@ -135,6 +139,45 @@ func (i Instrumentation) MadeFeatureDecision(
 	}()
 }
 
+// LinkHealthChecked tracks the destinations of short links checked in one run
+// of the health checker.
+func (i Instrumentation) LinkHealthChecked(checked int, broken int) {
+	go func() {
+		c := <-i.linkHealthCheckedCh
+		i.metrics.Count("link-health-checked", checked, 1, c)
+		i.metrics.Count("link-health-broken", broken, 1, c)
+	}()
+}
+
+// LinkHealthCheckFailed tracks the failures when checking the destinations of
+// short links.
+func (i Instrumentation) LinkHealthCheckFailed(err error) {
+	go func() {
+		c := <-i.linkHealthCheckFailedCh
+		i.logger.Error(err)
+		i.metrics.Count("link-health-check-failed", 1, 1, c)
+	}()
+}
+
+// RPCHandled tracks the latency of a gRPC call.
+func (i Instrumentation) RPCHandled() {
+	go func() {
+		c := <-i.rpcHandledCh
+		latency := i.timer.Now().Sub(c.RequestStartAt)
+		i.metrics.Count("rpc-handled", 1, 1, c)
+		i.metrics.Gauge("rpc-latency-ms", float32(latency.Milliseconds()), c)
+	}()
+}
+
+// RPCFailed tracks the failures of gRPC calls.
+func (i Instrumentation) RPCFailed(method string, err error) {
+	go func() {
+		c := <-i.rpcFailedCh
+		i.logger.Error(fmt.Errorf("request(%s) to %s failed: %w", c.RequestID, method, err))
+		i.metrics.Count("rpc-failed", 1, 1, c)
+	}()
+}
+
 // Track records events happened in the system.
 func (i Instrumentation) Track(event string) {
 	go func() {
@@ -180,6 +223,10 @@ func NewInstrumentation(
 	searchFailedCh := make(chan ctx.ExecutionContext)
 	madeFeatureDecisionCh := make(chan ctx.ExecutionContext)
 	trackCh := make(chan ctx.ExecutionContext)
+	linkHealthCheckedCh := make(chan ctx.ExecutionContext)
+	linkHealthCheckFailedCh := make(chan ctx.ExecutionContext)
+	rpcHandledCh := make(chan ctx.ExecutionContext)
+	rpcFailedCh := make(chan ctx.ExecutionContext)
 
 	ins := &Instrumentation{
 		logger:                          logger,
@@ -197,6 +244,10 @@ func NewInstrumentation(
 		searchFailedCh:                  searchFailedCh,
 		madeFeatureDecisionCh:           madeFeatureDecisionCh,
 		trackCh:                         trackCh,
+		linkHealthCheckedCh:             linkHealthCheckedCh,
+		linkHealthCheckFailedCh:         linkHealthCheckFailedCh,
+		rpcHandledCh:                    rpcHandledCh,
+		rpcFailedCh:                     rpcFailedCh,
 	}
 	go func() {
 		c := <-ctxCh
@@ -210,6 +261,10 @@ func NewInstrumentation(
 		go func() { searchFailedCh <- c }()
 		go func() { madeFeatureDecisionCh <- c }()
 		go func() { trackCh <- c }()
+		go func() { linkHealthCheckedCh <- c }()
+		go func() { linkHealthCheckFailedCh <- c }()
+		go func() { rpcHandledCh <- c }()
+		go func() { rpcFailedCh <- c }()
 		close(ctxCh)
 	}()
 	return *ins
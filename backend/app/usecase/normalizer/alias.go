@@ -0,0 +1,60 @@
+package normalizer
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// AliasPolicy decides which normalization steps are applied to an alias
+// before it is validated, persisted or compared with other aliases.
+type AliasPolicy struct {
+	FoldCase   bool
+	UnicodeNFC bool
+	TrimSpace  bool
+}
+
+// Alias converts aliases which look the same to users into the same canonical
+// form, so that "Docs" and "docs" cannot point to different long links.
+type Alias struct {
+	policy AliasPolicy
+	folder cases.Caser
+}
+
+// Normalize converts the given alias into its canonical form. The alias is
+// left unchanged when the policy has no normalization step enabled.
+func (a Alias) Normalize(alias string) string {
+	if a.policy.TrimSpace {
+		alias = strings.TrimSpace(alias)
+	}
+
+	if a.policy.FoldCase {
+		alias = a.folder.String(alias)
+	}
+
+	// Case folding may decompose characters, so NFC needs to be applied last.
+	if a.policy.UnicodeNFC {
+		alias = norm.NFC.String(alias)
+	}
+	return alias
+}
+
+// IsSame checks whether two aliases have the same canonical form.
+func (a Alias) IsSame(alias string, otherAlias string) bool {
+	return a.Normalize(alias) == a.Normalize(otherAlias)
+}
+
+// IsEnabled checks whether the alias will be modified by any normalization
+// step.
+func (a Alias) IsEnabled() bool {
+	return a.policy.FoldCase || a.policy.UnicodeNFC || a.policy.TrimSpace
+}
+
+// NewAlias creates alias normalizer with the given policy.
+func NewAlias(policy AliasPolicy) Alias {
+	return Alias{
+		policy: policy,
+		folder: cases.Fold(),
+	}
+}
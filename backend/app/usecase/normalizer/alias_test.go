@@ -0,0 +1,111 @@
+// +build !integration all
+
+package normalizer
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestAlias_Normalize(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		policy   AliasPolicy
+		alias    string
+		expAlias string
+	}{
+		{
+			name:     "normalization disabled",
+			policy:   AliasPolicy{},
+			alias:    " Docs ",
+			expAlias: " Docs ",
+		},
+		{
+			name:     "fold case",
+			policy:   AliasPolicy{FoldCase: true},
+			alias:    "DoCs",
+			expAlias: "docs",
+		},
+		{
+			name:     "fold non ASCII case",
+			policy:   AliasPolicy{FoldCase: true},
+			alias:    "STRASSE-Ä",
+			expAlias: "strasse-ä",
+		},
+		{
+			name:     "trim spaces",
+			policy:   AliasPolicy{TrimSpace: true},
+			alias:    "\t docs \n",
+			expAlias: "docs",
+		},
+		{
+			name:     "compose unicode characters",
+			policy:   AliasPolicy{UnicodeNFC: true},
+			alias:    "café",
+			expAlias: "café",
+		},
+		{
+			name: "apply all steps",
+			policy: AliasPolicy{
+				FoldCase:   true,
+				UnicodeNFC: true,
+				TrimSpace:  true,
+			},
+			alias:    " CAFÉ ",
+			expAlias: "café",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			normalizer := NewAlias(testCase.policy)
+			assert.Equal(t, testCase.expAlias, normalizer.Normalize(testCase.alias))
+		})
+	}
+}
+
+func TestAlias_IsSame(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		policy    AliasPolicy
+		alias     string
+		other     string
+		expIsSame bool
+	}{
+		{
+			name:      "different case without folding",
+			policy:    AliasPolicy{},
+			alias:     "Docs",
+			other:     "docs",
+			expIsSame: false,
+		},
+		{
+			name:      "different case with folding",
+			policy:    AliasPolicy{FoldCase: true},
+			alias:     "Docs",
+			other:     "docs",
+			expIsSame: true,
+		},
+		{
+			name:      "different aliases",
+			policy:    AliasPolicy{FoldCase: true, TrimSpace: true},
+			alias:     "docs",
+			other:     "doc",
+			expIsSame: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			normalizer := NewAlias(testCase.policy)
+			assert.Equal(t, testCase.expIsSame, normalizer.IsSame(testCase.alias, testCase.other))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package normalizer
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// LongLink converts long links into their canonical form so that links which
+// differ only in the case of scheme or host, or in the encoding of an
+// internationalized domain name, are stored the same way.
+type LongLink struct {
+}
+
+// Normalize lowercases the scheme and converts the host into its ASCII
+// (punycode) form. Links which cannot be parsed are returned unchanged and
+// left to the validator to reject.
+func (l LongLink) Normalize(longLink string) string {
+	longLink = strings.TrimSpace(longLink)
+	u, err := url.Parse(longLink)
+	if err != nil || u.Host == "" {
+		return longLink
+	}
+
+	host := u.Hostname()
+	if net.ParseIP(host) == nil {
+		host, err = idna.Lookup.ToASCII(host)
+		if err != nil {
+			return longLink
+		}
+	}
+
+	host = strings.ToLower(host)
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" {
+		host = host + ":" + port
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = host
+	return u.String()
+}
+
+// NewLongLink creates long link normalizer.
+func NewLongLink() LongLink {
+	return LongLink{}
+}
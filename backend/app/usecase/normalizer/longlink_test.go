@@ -0,0 +1,66 @@
+// +build !integration all
+
+package normalizer
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestLongLink_Normalize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		longLink    string
+		expLongLink string
+	}{
+		{
+			name:        "already normalized",
+			longLink:    "https://www.google.com/search?q=Short",
+			expLongLink: "https://www.google.com/search?q=Short",
+		},
+		{
+			name:        "uppercase scheme and host",
+			longLink:    "HTTPS://WWW.Google.COM/Search",
+			expLongLink: "https://www.google.com/Search",
+		},
+		{
+			name:        "internationalized domain name",
+			longLink:    "https://bücher.example/katalog",
+			expLongLink: "https://xn--bcher-kva.example/katalog",
+		},
+		{
+			name:        "keep port",
+			longLink:    "http://Example.com:8080/",
+			expLongLink: "http://example.com:8080/",
+		},
+		{
+			name:        "keep IPv6 host",
+			longLink:    "http://[2001:DB8::1]/",
+			expLongLink: "http://[2001:db8::1]/",
+		},
+		{
+			name:        "trim space",
+			longLink:    " https://google.com ",
+			expLongLink: "https://google.com",
+		},
+		{
+			name:        "not URL",
+			longLink:    "randomLink",
+			expLongLink: "randomLink",
+		},
+	}
+
+	longLinkNormalizer := NewLongLink()
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotLongLink := longLinkNormalizer.Normalize(testCase.longLink)
+			assert.Equal(t, testCase.expLongLink, gotLongLink)
+		})
+	}
+}
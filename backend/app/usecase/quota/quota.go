@@ -0,0 +1,315 @@
+package quota
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// Period represents the time span a quota applies to.
+type Period string
+
+// The constants enumerate all supported quota periods.
+const (
+	Daily Period = "daily"
+	Total Period = "total"
+)
+
+// Limit caps the number of short links an user can create. A non-positive
+// value means no limit.
+type Limit struct {
+	Daily int
+	Total int
+}
+
+// Unlimited represents a Limit without any cap.
+var Unlimited = Limit{}
+
+func (l Limit) isDailyUnlimited() bool {
+	return l.Daily <= 0
+}
+
+func (l Limit) isTotalUnlimited() bool {
+	return l.Total <= 0
+}
+
+// merge combines two limits into the more generous one.
+func (l Limit) merge(other Limit) Limit {
+	return Limit{
+		Daily: mergeCap(l.Daily, other.Daily),
+		Total: mergeCap(l.Total, other.Total),
+	}
+}
+
+func mergeCap(this int, other int) int {
+	if this <= 0 || other <= 0 {
+		return 0
+	}
+	if other > this {
+		return other
+	}
+	return this
+}
+
+// Policy assigns short link creation limits to roles. Users without any of
+// the listed roles are subject to the default limit. Third party apps are
+// subject to the app limit.
+type Policy struct {
+	Default Limit
+	Roles   map[role.Role]Limit
+	App     Limit
+}
+
+// Usage represents the number of short links an user has created against the
+// limit. Every short link created is counted, so deleting a short link doesn't
+// give its quota back.
+type Usage struct {
+	DailyCreated int
+	TotalCreated int
+	Limit        Limit
+	// DailyResetAt is the time when the daily usage is reset.
+	DailyResetAt time.Time
+}
+
+// DailyRemaining returns the number of short links the user can still create
+// today, or nil if there is no daily limit.
+func (u Usage) DailyRemaining() *int {
+	if u.Limit.isDailyUnlimited() {
+		return nil
+	}
+	return remaining(u.Limit.Daily, u.DailyCreated)
+}
+
+// TotalRemaining returns the number of short links the user can still create,
+// or nil if there is no total limit.
+func (u Usage) TotalRemaining() *int {
+	if u.Limit.isTotalUnlimited() {
+		return nil
+	}
+	return remaining(u.Limit.Total, u.TotalCreated)
+}
+
+// exceeded returns the period and the limit which the usage has reached.
+func (u Usage) exceeded() (Period, int, bool) {
+	if !u.Limit.isTotalUnlimited() && u.TotalCreated >= u.Limit.Total {
+		return Total, u.Limit.Total, true
+	}
+	if !u.Limit.isDailyUnlimited() && u.DailyCreated >= u.Limit.Daily {
+		return Daily, u.Limit.Daily, true
+	}
+	return "", 0, false
+}
+
+func remaining(limit int, used int) *int {
+	left := limit - used
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+// ErrQuotaExceeded represents the failure of creating a short link after the
+// user used up the quota.
+type ErrQuotaExceeded struct {
+	UserID string
+	Period Period
+	Limit  int
+}
+
+var _ error = (*ErrQuotaExceeded)(nil)
+
+func (e ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("user(%s) exceeded the %s quota of %d short links", e.UserID, e.Period, e.Limit)
+}
+
+// ErrAppQuotaExceeded represents the failure of creating a short link after
+// the third party app used up the quota.
+type ErrAppQuotaExceeded struct {
+	AppID  string
+	Period Period
+	Limit  int
+}
+
+var _ error = (*ErrAppQuotaExceeded)(nil)
+
+func (e ErrAppQuotaExceeded) Error() string {
+	return fmt.Sprintf("app(%s) exceeded the %s quota of %d short links", e.AppID, e.Period, e.Limit)
+}
+
+// Quota enforces the limits on the number of short links users and third
+// party apps can create.
+type Quota struct {
+	userShortLinkRepo repository.UserShortLink
+	appShortLinkRepo  repository.AppShortLink
+	rbac              rbac.RBAC
+	timer             timer.Timer
+	policy            Policy
+}
+
+// GetUsage fetches the number of short links created by the user and the
+// limit the user is subject to.
+func (q Quota) GetUsage(user entity.User) (Usage, error) {
+	limit, err := q.getLimit(user)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	totalCreated, err := q.userShortLinkRepo.CountShortLinksByUser(user)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	dayStart := startOfDay(q.timer.Now())
+	dailyCreated, err := q.userShortLinkRepo.CountShortLinksByUserSince(user, dayStart)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		DailyCreated: dailyCreated,
+		TotalCreated: totalCreated,
+		Limit:        limit,
+		DailyResetAt: dayStart.AddDate(0, 0, 1),
+	}, nil
+}
+
+// CheckCreateShortLink returns ErrQuotaExceeded if the user is not allowed to
+// create any more short link.
+func (q Quota) CheckCreateShortLink(user entity.User) error {
+	usage, err := q.GetUsage(user)
+	if err != nil {
+		return err
+	}
+
+	period, limit, isExceeded := usage.exceeded()
+	if isExceeded {
+		return ErrQuotaExceeded{UserID: user.ID, Period: period, Limit: limit}
+	}
+	return nil
+}
+
+// NewUserQuotaCheck creates the check which rejects the creation of a short
+// link with ErrQuotaExceeded once the user used up the quota. Unlike
+// CheckCreateShortLink, the check runs in the transaction which persists the
+// short link, so concurrent requests cannot exceed the quota.
+func (q Quota) NewUserQuotaCheck(user entity.User) (repository.QuotaCheck, error) {
+	limit, err := q.getLimit(user)
+	if err != nil {
+		return repository.QuotaCheck{}, err
+	}
+	return q.newQuotaCheck(limit, func(period Period, limit int) error {
+		return ErrQuotaExceeded{UserID: user.ID, Period: period, Limit: limit}
+	}), nil
+}
+
+// GetAppUsage fetches the number of short links created by the third party
+// app and the limit the app is subject to.
+func (q Quota) GetAppUsage(app entity.App) (Usage, error) {
+	totalCreated, err := q.appShortLinkRepo.CountShortLinksByApp(app)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	dayStart := startOfDay(q.timer.Now())
+	dailyCreated, err := q.appShortLinkRepo.CountShortLinksByAppSince(app, dayStart)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		DailyCreated: dailyCreated,
+		TotalCreated: totalCreated,
+		Limit:        q.policy.App,
+		DailyResetAt: dayStart.AddDate(0, 0, 1),
+	}, nil
+}
+
+// CheckCreateAppShortLink returns ErrAppQuotaExceeded if the third party app
+// is not allowed to create any more short link.
+func (q Quota) CheckCreateAppShortLink(app entity.App) error {
+	usage, err := q.GetAppUsage(app)
+	if err != nil {
+		return err
+	}
+
+	period, limit, isExceeded := usage.exceeded()
+	if isExceeded {
+		return ErrAppQuotaExceeded{AppID: app.ID, Period: period, Limit: limit}
+	}
+	return nil
+}
+
+// NewAppQuotaCheck creates the check which rejects the creation of a short
+// link with ErrAppQuotaExceeded once the third party app used up the quota.
+func (q Quota) NewAppQuotaCheck(app entity.App) repository.QuotaCheck {
+	return q.newQuotaCheck(q.policy.App, func(period Period, limit int) error {
+		return ErrAppQuotaExceeded{AppID: app.ID, Period: period, Limit: limit}
+	})
+}
+
+func (q Quota) newQuotaCheck(
+	limit Limit,
+	newErr func(period Period, limit int) error,
+) repository.QuotaCheck {
+	return repository.QuotaCheck{
+		Since: startOfDay(q.timer.Now()),
+		Check: func(totalCreated int, createdSince int) error {
+			usage := Usage{
+				DailyCreated: createdSince,
+				TotalCreated: totalCreated,
+				Limit:        limit,
+			}
+			period, limit, isExceeded := usage.exceeded()
+			if isExceeded {
+				return newErr(period, limit)
+			}
+			return nil
+		},
+	}
+}
+
+// getLimit picks the most generous limit among the default one and the ones
+// granted to the roles of the user.
+func (q Quota) getLimit(user entity.User) (Limit, error) {
+	roles, err := q.rbac.GetRoles(user)
+	if err != nil {
+		return Limit{}, err
+	}
+
+	limit := q.policy.Default
+	for _, r := range roles {
+		roleLimit, ok := q.policy.Roles[r]
+		if ok {
+			limit = limit.merge(roleLimit)
+		}
+	}
+	return limit, nil
+}
+
+// startOfDay truncates the time to the beginning of the day in UTC.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// NewQuota creates Quota.
+func NewQuota(
+	userShortLinkRepo repository.UserShortLink,
+	appShortLinkRepo repository.AppShortLink,
+	rbac rbac.RBAC,
+	timer timer.Timer,
+	policy Policy,
+) Quota {
+	return Quota{
+		userShortLinkRepo: userShortLinkRepo,
+		appShortLinkRepo:  appShortLinkRepo,
+		rbac:              rbac,
+		timer:             timer,
+		policy:            policy,
+	}
+}
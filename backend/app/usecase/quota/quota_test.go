@@ -0,0 +1,336 @@
+// +build !integration all
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestQuota_GetUsage(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Default: Limit{Daily: 2, Total: 10},
+		Roles: map[role.Role]Limit{
+			role.Premium: {Daily: 20, Total: 1000},
+			role.Admin:   Unlimited,
+		},
+	}
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	user := entity.User{ID: "alpha"}
+
+	testCases := []struct {
+		name           string
+		roles          map[string][]role.Role
+		users          []entity.User
+		shortLinks     []entity.ShortLink
+		expectedUsage  Usage
+		dailyRemaining *int
+		totalRemaining *int
+	}{
+		{
+			name:  "basic user without short links",
+			roles: map[string][]role.Role{},
+			expectedUsage: Usage{
+				Limit:        Limit{Daily: 2, Total: 10},
+				DailyResetAt: must.Time(t, "2020-05-02T00:00:00Z"),
+			},
+			dailyRemaining: ptr.Int(2),
+			totalRemaining: ptr.Int(10),
+		},
+		{
+			name:  "basic user with short links",
+			roles: map[string][]role.Role{"alpha": {role.Basic}},
+			users: []entity.User{user, user, {ID: "beta"}},
+			shortLinks: []entity.ShortLink{
+				{Alias: "old", CreatedAt: ptr.Time(must.Time(t, "2020-04-30T23:59:59Z"))},
+				{Alias: "new", CreatedAt: ptr.Time(must.Time(t, "2020-05-01T00:00:00Z"))},
+				{Alias: "other", CreatedAt: ptr.Time(must.Time(t, "2020-05-01T01:00:00Z"))},
+			},
+			expectedUsage: Usage{
+				DailyCreated: 1,
+				TotalCreated: 2,
+				Limit:        Limit{Daily: 2, Total: 10},
+				DailyResetAt: must.Time(t, "2020-05-02T00:00:00Z"),
+			},
+			dailyRemaining: ptr.Int(1),
+			totalRemaining: ptr.Int(8),
+		},
+		{
+			name:  "premium user",
+			roles: map[string][]role.Role{"alpha": {role.Basic, role.Premium}},
+			expectedUsage: Usage{
+				Limit:        Limit{Daily: 20, Total: 1000},
+				DailyResetAt: must.Time(t, "2020-05-02T00:00:00Z"),
+			},
+			dailyRemaining: ptr.Int(20),
+			totalRemaining: ptr.Int(1000),
+		},
+		{
+			name:  "admin without limit",
+			roles: map[string][]role.Role{"alpha": {role.Premium, role.Admin}},
+			expectedUsage: Usage{
+				Limit:        Unlimited,
+				DailyResetAt: must.Time(t, "2020-05-02T00:00:00Z"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newQuota(testCase.roles, testCase.users, testCase.shortLinks, now, policy)
+			usage, err := q.GetUsage(user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedUsage, usage)
+			assert.Equal(t, testCase.dailyRemaining, usage.DailyRemaining())
+			assert.Equal(t, testCase.totalRemaining, usage.TotalRemaining())
+		})
+	}
+}
+
+func TestQuota_CheckCreateShortLink(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Default: Limit{Daily: 1, Total: 2},
+		Roles: map[role.Role]Limit{
+			role.Premium: {Daily: 5, Total: 0},
+		},
+	}
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	user := entity.User{ID: "alpha"}
+	yesterday := ptr.Time(must.Time(t, "2020-04-30T08:02:16Z"))
+	today := ptr.Time(must.Time(t, "2020-05-01T08:00:00Z"))
+
+	testCases := []struct {
+		name        string
+		roles       map[string][]role.Role
+		shortLinks  []entity.ShortLink
+		expectedErr error
+	}{
+		{
+			name:        "within quota",
+			roles:       map[string][]role.Role{},
+			shortLinks:  []entity.ShortLink{{Alias: "a", CreatedAt: yesterday}},
+			expectedErr: nil,
+		},
+		{
+			name:        "daily quota exceeded",
+			roles:       map[string][]role.Role{},
+			shortLinks:  []entity.ShortLink{{Alias: "a", CreatedAt: today}},
+			expectedErr: ErrQuotaExceeded{UserID: "alpha", Period: Daily, Limit: 1},
+		},
+		{
+			name:  "total quota exceeded",
+			roles: map[string][]role.Role{},
+			shortLinks: []entity.ShortLink{
+				{Alias: "a", CreatedAt: yesterday},
+				{Alias: "b", CreatedAt: yesterday},
+			},
+			expectedErr: ErrQuotaExceeded{UserID: "alpha", Period: Total, Limit: 2},
+		},
+		{
+			name:  "premium user without total quota",
+			roles: map[string][]role.Role{"alpha": {role.Premium}},
+			shortLinks: []entity.ShortLink{
+				{Alias: "a", CreatedAt: yesterday},
+				{Alias: "b", CreatedAt: yesterday},
+				{Alias: "c", CreatedAt: today},
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			users := make([]entity.User, len(testCase.shortLinks))
+			for idx := range users {
+				users[idx] = user
+			}
+			q := newQuota(testCase.roles, users, testCase.shortLinks, now, policy)
+			err := q.CheckCreateShortLink(user)
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+func TestQuota_CheckCreateAppShortLink(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Default: Unlimited,
+		App:     Limit{Daily: 1, Total: 2},
+	}
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	app := entity.App{ID: "alpha"}
+	yesterday := ptr.Time(must.Time(t, "2020-04-30T08:02:16Z"))
+	today := ptr.Time(must.Time(t, "2020-05-01T08:00:00Z"))
+
+	testCases := []struct {
+		name        string
+		apps        []entity.App
+		shortLinks  []entity.ShortLinkInput
+		expectedErr error
+	}{
+		{
+			name:        "within quota",
+			apps:        []entity.App{app},
+			shortLinks:  []entity.ShortLinkInput{{CustomAlias: ptr.String("a"), CreatedAt: yesterday}},
+			expectedErr: nil,
+		},
+		{
+			name:        "daily quota exceeded",
+			apps:        []entity.App{app},
+			shortLinks:  []entity.ShortLinkInput{{CustomAlias: ptr.String("a"), CreatedAt: today}},
+			expectedErr: ErrAppQuotaExceeded{AppID: "alpha", Period: Daily, Limit: 1},
+		},
+		{
+			name: "total quota exceeded",
+			apps: []entity.App{app, app},
+			shortLinks: []entity.ShortLinkInput{
+				{CustomAlias: ptr.String("a"), CreatedAt: yesterday},
+				{CustomAlias: ptr.String("b"), CreatedAt: yesterday},
+			},
+			expectedErr: ErrAppQuotaExceeded{AppID: "alpha", Period: Total, Limit: 2},
+		},
+		{
+			name: "short links of other apps",
+			apps: []entity.App{{ID: "beta"}, {ID: "beta"}},
+			shortLinks: []entity.ShortLinkInput{
+				{CustomAlias: ptr.String("a"), CreatedAt: today},
+				{CustomAlias: ptr.String("b"), CreatedAt: today},
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			for idx, shortLink := range testCase.shortLinks {
+				err := appShortLinkRepo.CreateRelation(testCase.apps[idx], shortLink)
+				assert.Equal(t, nil, err)
+			}
+			userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+			q := NewQuota(&userShortLinkRepo, &appShortLinkRepo, rbac.NewRBAC(userRoleRepo), timer.NewStub(now), policy)
+
+			err := q.CheckCreateAppShortLink(app)
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+func TestQuota_NewUserQuotaCheck(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Default: Limit{Daily: 1, Total: 2},
+		Roles: map[role.Role]Limit{
+			role.Premium: {Daily: 5, Total: 0},
+		},
+	}
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	user := entity.User{ID: "alpha"}
+
+	testCases := []struct {
+		name         string
+		roles        map[string][]role.Role
+		totalCreated int
+		createdSince int
+		expectedErr  error
+	}{
+		{
+			name:         "within quota",
+			roles:        map[string][]role.Role{},
+			totalCreated: 1,
+			createdSince: 0,
+			expectedErr:  nil,
+		},
+		{
+			name:         "daily quota exceeded",
+			roles:        map[string][]role.Role{},
+			totalCreated: 1,
+			createdSince: 1,
+			expectedErr:  ErrQuotaExceeded{UserID: "alpha", Period: Daily, Limit: 1},
+		},
+		{
+			name:         "total quota exceeded",
+			roles:        map[string][]role.Role{},
+			totalCreated: 2,
+			createdSince: 0,
+			expectedErr:  ErrQuotaExceeded{UserID: "alpha", Period: Total, Limit: 2},
+		},
+		{
+			name:         "premium user without total quota",
+			roles:        map[string][]role.Role{"alpha": {role.Premium}},
+			totalCreated: 100,
+			createdSince: 4,
+			expectedErr:  nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := newQuota(testCase.roles, nil, nil, now, policy)
+			quotaCheck, err := q.NewUserQuotaCheck(user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, must.Time(t, "2020-05-01T00:00:00Z"), quotaCheck.Since)
+
+			err = quotaCheck.Check(testCase.totalCreated, testCase.createdSince)
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+func TestQuota_NewAppQuotaCheck(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Default: Unlimited,
+		App:     Limit{Daily: 1, Total: 2},
+	}
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	q := newQuota(map[string][]role.Role{}, nil, nil, now, policy)
+
+	quotaCheck := q.NewAppQuotaCheck(entity.App{ID: "alpha"})
+	assert.Equal(t, must.Time(t, "2020-05-01T00:00:00Z"), quotaCheck.Since)
+	assert.Equal(t, nil, quotaCheck.Check(1, 0))
+	assert.Equal(t, ErrAppQuotaExceeded{AppID: "alpha", Period: Daily, Limit: 1}, quotaCheck.Check(1, 1))
+	assert.Equal(t, ErrAppQuotaExceeded{AppID: "alpha", Period: Total, Limit: 2}, quotaCheck.Check(2, 0))
+}
+
+func newQuota(
+	roles map[string][]role.Role,
+	users []entity.User,
+	shortLinks []entity.ShortLink,
+	now time.Time,
+	policy Policy,
+) Quota {
+	userShortLinkRepo := repository.NewUserShortLinkRepoFake(users, shortLinks)
+	appShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+	userRoleRepo := repository.NewUserRoleFake(roles)
+	return NewQuota(&userShortLinkRepo, &appShortLinkRepo, rbac.NewRBAC(userRoleRepo), timer.NewStub(now), policy)
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"sync"
+
+	"github.com/short-d/app/fw/timer"
+)
+
+// sweepThreshold is the number of buckets above which full buckets are
+// discarded to bound the memory usage.
+const sweepThreshold = 10000
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore keeps token buckets in the memory of the current process.
+type MemoryStore struct {
+	mutex          *sync.Mutex
+	buckets        map[string]Bucket
+	timer          timer.Timer
+	sweepThreshold *int
+}
+
+// UpdateBucket loads, updates and saves the bucket under the given key.
+func (m MemoryStore) UpdateBucket(key string, update func(bucket Bucket, isFound bool) Bucket) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	bucket, isFound := m.buckets[key]
+	m.buckets[key] = update(bucket, isFound)
+
+	if len(m.buckets) > *m.sweepThreshold {
+		m.sweep()
+	}
+	return nil
+}
+
+func (m MemoryStore) sweep() {
+	now := m.timer.Now()
+	for key, bucket := range m.buckets {
+		if !now.Before(bucket.FullAt) {
+			delete(m.buckets, key)
+		}
+	}
+
+	nextThreshold := sweepThreshold
+	if len(m.buckets)*2 > nextThreshold {
+		nextThreshold = len(m.buckets) * 2
+	}
+	*m.sweepThreshold = nextThreshold
+}
+
+// NewMemoryStore creates MemoryStore.
+func NewMemoryStore(timer timer.Timer) MemoryStore {
+	threshold := sweepThreshold
+	return MemoryStore{
+		mutex:          &sync.Mutex{},
+		buckets:        make(map[string]Bucket),
+		timer:          timer,
+		sweepThreshold: &threshold,
+	}
+}
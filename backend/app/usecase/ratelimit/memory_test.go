@@ -0,0 +1,55 @@
+// +build !integration all
+
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestMemoryStore_UpdateBucket(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	store := NewMemoryStore(timer.NewStub(now))
+
+	err := store.UpdateBucket("alpha", func(bucket Bucket, isFound bool) Bucket {
+		assert.Equal(t, false, isFound)
+		return Bucket{Tokens: 2, UpdatedAt: now, FullAt: now.Add(time.Minute)}
+	})
+	assert.Equal(t, nil, err)
+
+	err = store.UpdateBucket("alpha", func(bucket Bucket, isFound bool) Bucket {
+		assert.Equal(t, true, isFound)
+		assert.Equal(t, float64(2), bucket.Tokens)
+		return bucket
+	})
+	assert.Equal(t, nil, err)
+}
+
+func TestMemoryStore_sweep(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	store := NewMemoryStore(timer.NewStub(now))
+
+	for idx := 0; idx <= sweepThreshold; idx++ {
+		fullAt := now
+		if idx == 0 {
+			fullAt = now.Add(time.Minute)
+		}
+		err := store.UpdateBucket(fmt.Sprintf("key-%d", idx), func(bucket Bucket, isFound bool) Bucket {
+			return Bucket{FullAt: fullAt}
+		})
+		assert.Equal(t, nil, err)
+	}
+
+	assert.Equal(t, 1, len(store.buckets))
+	_, isFound := store.buckets["key-0"]
+	assert.Equal(t, true, isFound)
+}
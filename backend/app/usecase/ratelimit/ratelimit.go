@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/short-d/app/fw/timer"
+)
+
+// Limit allows up to Requests requests in a burst and refills the quota
+// evenly over Window. A Limit without requests disables rate limiting.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// IsUnlimited checks whether the limit disables rate limiting.
+func (l Limit) IsUnlimited() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+func (l Limit) tokensPerNanosecond() float64 {
+	return float64(l.Requests) / float64(l.Window)
+}
+
+// Policy lists the limits of the rate limited routes.
+type Policy struct {
+	Redirect Limit
+	Search   Limit
+	CloudAPI Limit
+	GraphQL  Limit
+	// EmailSignIn limits the sign in links sent to each email address.
+	EmailSignIn Limit
+}
+
+// Bucket holds the tokens left for a client. Each request consumes one token.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+	// FullAt is the time when the bucket is refilled to its capacity, after
+	// which the bucket is equivalent to a new one and can be discarded.
+	FullAt time.Time
+}
+
+// Decision tells whether a request is allowed and, if not, when the client can
+// retry.
+type Decision struct {
+	IsAllowed  bool
+	RetryAfter time.Duration
+}
+
+// Store persists token buckets. Implementations must load, update and save the
+// bucket under a given key atomically.
+type Store interface {
+	UpdateBucket(key string, update func(bucket Bucket, isFound bool) Bucket) error
+}
+
+// Limiter throttles requests with the token bucket algorithm.
+type Limiter struct {
+	store Store
+	timer timer.Timer
+}
+
+// Allow consumes a token from the bucket of the given key if there is one
+// left.
+func (l Limiter) Allow(key string, limit Limit) (Decision, error) {
+	if limit.IsUnlimited() {
+		return Decision{IsAllowed: true}, nil
+	}
+
+	now := l.timer.Now()
+	capacity := float64(limit.Requests)
+	rate := limit.tokensPerNanosecond()
+
+	var decision Decision
+	err := l.store.UpdateBucket(key, func(bucket Bucket, isFound bool) Bucket {
+		tokens := capacity
+		if isFound {
+			elapsed := now.Sub(bucket.UpdatedAt)
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			tokens = math.Min(capacity, bucket.Tokens+float64(elapsed)*rate)
+		}
+
+		if tokens >= 1 {
+			tokens--
+			decision = Decision{IsAllowed: true}
+		} else {
+			retryAfter := time.Duration(math.Ceil((1 - tokens) / rate))
+			decision = Decision{IsAllowed: false, RetryAfter: retryAfter}
+		}
+
+		timeToFull := time.Duration(math.Ceil((capacity - tokens) / rate))
+		return Bucket{
+			Tokens:    tokens,
+			UpdatedAt: now,
+			FullAt:    now.Add(timeToFull),
+		}
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+	return decision, nil
+}
+
+// NewLimiter creates Limiter.
+func NewLimiter(store Store, timer timer.Timer) Limiter {
+	return Limiter{
+		store: store,
+		timer: timer,
+	}
+}
@@ -0,0 +1,129 @@
+// +build !integration all
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	testCases := []struct {
+		name             string
+		limit            Limit
+		requestAt        []time.Time
+		expectedDecision []Decision
+	}{
+		{
+			name:  "unlimited",
+			limit: Limit{},
+			requestAt: []time.Time{
+				now,
+				now,
+			},
+			expectedDecision: []Decision{
+				{IsAllowed: true},
+				{IsAllowed: true},
+			},
+		},
+		{
+			name:  "burst within capacity",
+			limit: Limit{Requests: 2, Window: time.Minute},
+			requestAt: []time.Time{
+				now,
+				now,
+			},
+			expectedDecision: []Decision{
+				{IsAllowed: true},
+				{IsAllowed: true},
+			},
+		},
+		{
+			name:  "burst exceeding capacity",
+			limit: Limit{Requests: 2, Window: time.Minute},
+			requestAt: []time.Time{
+				now,
+				now,
+				now.Add(10 * time.Second),
+			},
+			expectedDecision: []Decision{
+				{IsAllowed: true},
+				{IsAllowed: true},
+				{IsAllowed: false, RetryAfter: 20 * time.Second},
+			},
+		},
+		{
+			name:  "tokens refilled over time",
+			limit: Limit{Requests: 2, Window: time.Minute},
+			requestAt: []time.Time{
+				now,
+				now,
+				now.Add(30 * time.Second),
+				now.Add(30 * time.Second),
+			},
+			expectedDecision: []Decision{
+				{IsAllowed: true},
+				{IsAllowed: true},
+				{IsAllowed: true},
+				{IsAllowed: false, RetryAfter: 30 * time.Second},
+			},
+		},
+		{
+			name:  "tokens never exceed capacity",
+			limit: Limit{Requests: 1, Window: time.Minute},
+			requestAt: []time.Time{
+				now,
+				now.Add(time.Hour),
+				now.Add(time.Hour),
+			},
+			expectedDecision: []Decision{
+				{IsAllowed: true},
+				{IsAllowed: true},
+				{IsAllowed: false, RetryAfter: time.Minute},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewMemoryStore(timer.NewStub(now))
+			for idx, requestAt := range testCase.requestAt {
+				limiter := NewLimiter(store, timer.NewStub(requestAt))
+				decision, err := limiter.Allow("alpha", testCase.limit)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, testCase.expectedDecision[idx], decision)
+			}
+		})
+	}
+}
+
+func TestLimiter_Allow_separateKeys(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	store := NewMemoryStore(timer.NewStub(now))
+	limiter := NewLimiter(store, timer.NewStub(now))
+	limit := Limit{Requests: 1, Window: time.Minute}
+
+	decision, err := limiter.Allow("alpha", limit)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, decision.IsAllowed)
+
+	decision, err = limiter.Allow("beta", limit)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, decision.IsAllowed)
+
+	decision, err = limiter.Allow("alpha", limit)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, decision.IsAllowed)
+}
@@ -1,11 +1,17 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/short-d/short/backend/app/entity"
 )
 
 // APIKey accesses API keys for third party apps from persistent storage, such as database.
 type APIKey interface {
 	GetAPIKey(appID string, key string) (entity.APIKey, error)
+	GetAPIKeysByApp(appID string) ([]entity.APIKey, error)
 	CreateAPIKey(input entity.APIKeyInput) (entity.APIKey, error)
+	DisableAPIKey(appID string, key string) error
+	UpdateExpireAt(appID string, key string, expireAt time.Time) error
+	UpdateLastUsedAt(appID string, key string, lastUsedAt time.Time) error
 }
@@ -17,12 +17,22 @@ type APIKeyFake struct {
 
 // GetAPIKey fetches an api key for a given app.
 func (a APIKeyFake) GetAPIKey(appID string, key string) (entity.APIKey, error) {
+	idx, err := a.findAPIKey(appID, key)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	return a.apiKeys[idx], nil
+}
+
+// GetAPIKeysByApp fetches all the api keys of a given app.
+func (a APIKeyFake) GetAPIKeysByApp(appID string) ([]entity.APIKey, error) {
+	var apiKeys []entity.APIKey
 	for _, apiKey := range a.apiKeys {
-		if apiKey.AppID == appID && apiKey.Key == key {
-			return apiKey, nil
+		if apiKey.AppID == appID {
+			apiKeys = append(apiKeys, apiKey)
 		}
 	}
-	return entity.APIKey{}, ErrEntryNotFound(fmt.Sprintf("appID(%s),key(%s)", appID, key))
+	return apiKeys, nil
 }
 
 // CreateAPIKey creates an api key for a given app.
@@ -46,11 +56,52 @@ func (a *APIKeyFake) CreateAPIKey(input entity.APIKeyInput) (entity.APIKey, erro
 		Key:        input.GetKey(""),
 		IsDisabled: input.GetIsDisabled(false),
 		CreatedAt:  input.GetCreatedAt(time.Time{}),
+		ExpireAt:   input.ExpireAt,
+		Scopes:     input.Scopes,
 	}
 	a.apiKeys = append(a.apiKeys, apiKey)
 	return apiKey, nil
 }
 
+// DisableAPIKey prevents an api key from being used.
+func (a *APIKeyFake) DisableAPIKey(appID string, key string) error {
+	idx, err := a.findAPIKey(appID, key)
+	if err != nil {
+		return err
+	}
+	a.apiKeys[idx].IsDisabled = true
+	return nil
+}
+
+// UpdateExpireAt changes the time when an api key expires.
+func (a *APIKeyFake) UpdateExpireAt(appID string, key string, expireAt time.Time) error {
+	idx, err := a.findAPIKey(appID, key)
+	if err != nil {
+		return err
+	}
+	a.apiKeys[idx].ExpireAt = &expireAt
+	return nil
+}
+
+// UpdateLastUsedAt records the time when an api key was last used.
+func (a *APIKeyFake) UpdateLastUsedAt(appID string, key string, lastUsedAt time.Time) error {
+	idx, err := a.findAPIKey(appID, key)
+	if err != nil {
+		return err
+	}
+	a.apiKeys[idx].LastUsedAt = &lastUsedAt
+	return nil
+}
+
+func (a APIKeyFake) findAPIKey(appID string, key string) (int, error) {
+	for idx, apiKey := range a.apiKeys {
+		if apiKey.AppID == appID && apiKey.Key == key {
+			return idx, nil
+		}
+	}
+	return 0, ErrEntryNotFound(fmt.Sprintf("api key of app(%s) not found", appID))
+}
+
 // NewAPIKeyFake creates in memory implementation of APIKey repository.
 func NewAPIKeyFake(apiKeys []entity.APIKey) APIKeyFake {
 	return APIKeyFake{apiKeys: apiKeys}
@@ -5,4 +5,8 @@ import "github.com/short-d/short/backend/app/entity"
 // App accesses third party app info from persistent storage, such as database.
 type App interface {
 	GetAppByID(id string) (entity.App, error)
+	GetAppsByOwner(ownerID string) ([]entity.App, error)
+	CreateApp(newApp entity.App) (entity.App, error)
+	UpdateApp(newApp entity.App) (entity.App, error)
+	DeleteApp(id string) error
 }
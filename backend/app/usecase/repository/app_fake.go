@@ -15,12 +15,61 @@ type AppFake struct {
 
 // GetAppByID fetches an app with given ID from memory.
 func (a AppFake) GetAppByID(id string) (entity.App, error) {
+	idx, err := a.findApp(id)
+	if err != nil {
+		return entity.App{}, err
+	}
+	return a.apps[idx], nil
+}
+
+// GetAppsByOwner fetches all the apps owned by the given user from memory.
+func (a AppFake) GetAppsByOwner(ownerID string) ([]entity.App, error) {
+	var apps []entity.App
 	for _, app := range a.apps {
+		if app.OwnerID == ownerID {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
+
+// CreateApp adds a new app to memory.
+func (a *AppFake) CreateApp(newApp entity.App) (entity.App, error) {
+	_, err := a.findApp(newApp.ID)
+	if err == nil {
+		return entity.App{}, ErrEntryExists(fmt.Sprintf("ID(%s)", newApp.ID))
+	}
+	a.apps = append(a.apps, newApp)
+	return newApp, nil
+}
+
+// UpdateApp renames an existing app in memory.
+func (a *AppFake) UpdateApp(newApp entity.App) (entity.App, error) {
+	idx, err := a.findApp(newApp.ID)
+	if err != nil {
+		return entity.App{}, err
+	}
+	a.apps[idx].Name = newApp.Name
+	return a.apps[idx], nil
+}
+
+// DeleteApp removes an app with given ID from memory.
+func (a *AppFake) DeleteApp(id string) error {
+	idx, err := a.findApp(id)
+	if err != nil {
+		return err
+	}
+	a.apps = append(a.apps[:idx], a.apps[idx+1:]...)
+	return nil
+}
+
+func (a AppFake) findApp(id string) (int, error) {
+	for idx, app := range a.apps {
 		if app.ID == id {
-			return app, nil
+			return idx, nil
 		}
 	}
-	return entity.App{}, ErrEntryNotFound(fmt.Sprintf("ID(%s)", id))
+	return 0, ErrEntryNotFound(fmt.Sprintf("ID(%s)", id))
 }
 
 // NewAppFake create in-memory implementation of App repository.
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// AppShortLink accesses App-ShortLink relationship from storage, such as database.
+type AppShortLink interface {
+	CreateRelation(app entity.App, shortLinkInput entity.ShortLinkInput) error
+	FindAliasesByApp(app entity.App) ([]string, error)
+	HasMapping(app entity.App, alias string) (bool, error)
+	// CountShortLinksByApp counts the short links ever created by the app,
+	// including the deleted ones.
+	CountShortLinksByApp(app entity.App) (int, error)
+	// CountShortLinksByAppSince counts the short links created by the app at
+	// or after the given time, including the deleted ones.
+	CountShortLinksByAppSince(app entity.App, since time.Time) (int, error)
+}
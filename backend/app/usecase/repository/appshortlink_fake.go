@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+var _ AppShortLink = (*AppShortLinkFake)(nil)
+
+// AppShortLinkFake represents in memory implementation of App-ShortLink relationship accessor.
+type AppShortLinkFake struct {
+	appIDs     []string
+	aliases    []string
+	createdAts []*time.Time
+}
+
+// CreateRelation creates the relationship between an App and a ShortLink created by it.
+func (a *AppShortLinkFake) CreateRelation(app entity.App, shortLinkInput entity.ShortLinkInput) error {
+	if shortLinkInput.CustomAlias == nil {
+		return errors.New("empty alias")
+	}
+	alias := shortLinkInput.GetCustomAlias("")
+	isExist, err := a.HasMapping(app, alias)
+	if err != nil {
+		return err
+	}
+	if isExist {
+		return errors.New("relationship exists")
+	}
+	a.appIDs = append(a.appIDs, app.ID)
+	a.aliases = append(a.aliases, alias)
+	a.createdAts = append(a.createdAts, shortLinkInput.CreatedAt)
+	return nil
+}
+
+// FindAliasesByApp fetches the aliases of all the ShortLinks created by the given app.
+func (a AppShortLinkFake) FindAliasesByApp(app entity.App) ([]string, error) {
+	var aliases []string
+	for idx, appID := range a.appIDs {
+		if appID != app.ID {
+			continue
+		}
+		aliases = append(aliases, a.aliases[idx])
+	}
+	return aliases, nil
+}
+
+// HasMapping checks whether a given short link belongs to an app.
+func (a AppShortLinkFake) HasMapping(app entity.App, alias string) (bool, error) {
+	for idx, appID := range a.appIDs {
+		if appID == app.ID && a.aliases[idx] == alias {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountShortLinksByApp counts the short links created by the given app.
+func (a AppShortLinkFake) CountShortLinksByApp(app entity.App) (int, error) {
+	count := 0
+	for _, appID := range a.appIDs {
+		if appID == app.ID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountShortLinksByAppSince counts the short links created by the given app
+// at or after the given time.
+func (a AppShortLinkFake) CountShortLinksByAppSince(app entity.App, since time.Time) (int, error) {
+	count := 0
+	for idx, appID := range a.appIDs {
+		if appID != app.ID {
+			continue
+		}
+		createdAt := a.createdAts[idx]
+		if createdAt == nil || createdAt.Before(since) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// NewAppShortLinkRepoFake creates AppShortLinkFake
+func NewAppShortLinkRepoFake(apps []entity.App, aliases []string) AppShortLinkFake {
+	appIDs := make([]string, len(apps))
+	for idx, app := range apps {
+		appIDs[idx] = app.ID
+	}
+	return AppShortLinkFake{
+		appIDs:     appIDs,
+		aliases:    aliases,
+		createdAts: make([]*time.Time, len(apps)),
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// EmailSignInToken accesses the one-time sign in tokens sent by email from
+// persistent storage, such as database.
+type EmailSignInToken interface {
+	CreateToken(token entity.EmailSignInToken) error
+	// UseToken marks the token as used if it is neither used nor expired at
+	// the given time. Otherwise, it returns ErrEntryNotFound.
+	UseToken(id string, usedAt time.Time) (entity.EmailSignInToken, error)
+}
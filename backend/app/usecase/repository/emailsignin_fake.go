@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+var _ EmailSignInToken = (*EmailSignInTokenFake)(nil)
+
+// EmailSignInTokenFake represents in memory implementation of
+// EmailSignInToken repository.
+type EmailSignInTokenFake struct {
+	tokens []entity.EmailSignInToken
+}
+
+// CreateToken adds a new token.
+func (e *EmailSignInTokenFake) CreateToken(token entity.EmailSignInToken) error {
+	for _, existingToken := range e.tokens {
+		if existingToken.ID == token.ID {
+			return ErrEntryExists(fmt.Sprintf("email sign in token(%s)", token.ID))
+		}
+	}
+	e.tokens = append(e.tokens, token)
+	return nil
+}
+
+// UseToken marks the token as used if it is neither used nor expired.
+func (e *EmailSignInTokenFake) UseToken(id string, usedAt time.Time) (entity.EmailSignInToken, error) {
+	for idx, token := range e.tokens {
+		if token.ID != id || token.UsedAt != nil || !usedAt.Before(token.ExpireAt) {
+			continue
+		}
+		e.tokens[idx].UsedAt = &usedAt
+		return e.tokens[idx], nil
+	}
+	return entity.EmailSignInToken{}, ErrEntryNotFound(
+		fmt.Sprintf("unused email sign in token(%s) not found", id))
+}
+
+// NewEmailSignInTokenFake creates EmailSignInTokenFake.
+func NewEmailSignInTokenFake(tokens []entity.EmailSignInToken) EmailSignInTokenFake {
+	return EmailSignInTokenFake{tokens: tokens}
+}
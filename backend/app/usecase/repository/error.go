@@ -20,3 +20,14 @@ type ErrEntryExists string
 func (e ErrEntryExists) Error() string {
 	return string(e)
 }
+
+var _ error = (*ErrLastEntry)(nil)
+
+// ErrLastEntry represents the failure of removing the last remaining table
+// entry of its kind.
+type ErrLastEntry string
+
+// Error coverts ErrLastEntry into human readable message to easy debugging.
+func (e ErrLastEntry) Error() string {
+	return string(e)
+}
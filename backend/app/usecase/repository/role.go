@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+// Role accesses the definitions of custom roles from storage, such as
+// database. Built-in roles are reserved in storage but can't be changed.
+type Role interface {
+	GetCustomRoles() ([]role.Definition, error)
+	CreateRole(definition role.Definition) error
+	UpdateRole(definition role.Definition) error
+	// DeleteRole removes the custom role and revokes it from its users,
+	// recording each revocation as a change made by the actor.
+	DeleteRole(name role.Role, actorID string, deletedAt time.Time) error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+var _ Role = (*RoleFake)(nil)
+
+// RoleFake represents in memory implementation of Role repository.
+type RoleFake struct {
+	definitions []role.Definition
+	userRoles   map[string][]role.Role
+	roleChanges []entity.RoleChange
+}
+
+// GetCustomRoles fetches the definitions of all the custom roles.
+func (r RoleFake) GetCustomRoles() ([]role.Definition, error) {
+	definitions := []role.Definition{}
+	for _, definition := range r.definitions {
+		if !definition.IsBuiltIn {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions, nil
+}
+
+// CreateRole adds a new custom role.
+func (r *RoleFake) CreateRole(definition role.Definition) error {
+	if definition.Name.IsBuiltIn() || r.findRole(definition.Name) >= 0 {
+		return ErrEntryExists(fmt.Sprintf("role(%s)", definition.Name))
+	}
+	r.definitions = append(r.definitions, definition)
+	return nil
+}
+
+// UpdateRole replaces the permissions of a custom role.
+func (r *RoleFake) UpdateRole(definition role.Definition) error {
+	idx := r.findRole(definition.Name)
+	if idx < 0 {
+		return ErrEntryNotFound(fmt.Sprintf("custom role(%s) not found", definition.Name))
+	}
+	r.definitions[idx].Permissions = definition.Permissions
+	return nil
+}
+
+// DeleteRole removes a custom role and revokes it from its users.
+func (r *RoleFake) DeleteRole(name role.Role, actorID string, deletedAt time.Time) error {
+	idx := r.findRole(name)
+	if idx < 0 {
+		return ErrEntryNotFound(fmt.Sprintf("custom role(%s) not found", name))
+	}
+	r.definitions = append(r.definitions[:idx], r.definitions[idx+1:]...)
+
+	for userID, roles := range r.userRoles {
+		var remainingRoles []role.Role
+		for _, userRole := range roles {
+			if userRole != name {
+				remainingRoles = append(remainingRoles, userRole)
+				continue
+			}
+			r.roleChanges = append(r.roleChanges, entity.RoleChange{
+				ActorID:   actorID,
+				UserID:    userID,
+				Role:      string(name),
+				Action:    entity.RoleRevoked,
+				CreatedAt: deletedAt,
+			})
+		}
+		r.userRoles[userID] = remainingRoles
+	}
+	return nil
+}
+
+// RoleChanges retrieves the role changes recorded while deleting roles.
+func (r RoleFake) RoleChanges() []entity.RoleChange {
+	return r.roleChanges
+}
+
+func (r RoleFake) findRole(name role.Role) int {
+	for idx, definition := range r.definitions {
+		if definition.Name == name && !definition.IsBuiltIn {
+			return idx
+		}
+	}
+	return -1
+}
+
+// NewRoleFake creates RoleFake. It shares the roles of users with
+// UserRoleFake created from the same map.
+func NewRoleFake(definitions []role.Definition, userRoles map[string][]role.Role) RoleFake {
+	return RoleFake{
+		definitions: definitions,
+		userRoles:   userRoles,
+	}
+}
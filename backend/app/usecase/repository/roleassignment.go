@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/short-d/short/backend/app/entity"
+
+// RoleAssignment grants and revokes roles of users, recording every change in
+// the audit log together with the change itself, in storage such as database.
+type RoleAssignment interface {
+	GrantRole(change entity.RoleChange) error
+	// RevokeRole fails with ErrLastEntry when keepLastHolder is set and the
+	// user is the only one left with the role.
+	RevokeRole(change entity.RoleChange, keepLastHolder bool) error
+	GetRoleChanges(userID string) ([]entity.RoleChange, error)
+}
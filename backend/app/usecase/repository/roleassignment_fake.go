@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+)
+
+var _ RoleAssignment = (*RoleAssignmentFake)(nil)
+
+// RoleAssignmentFake represents in memory implementation of RoleAssignment
+// repository. It shares the roles of users with UserRoleFake created from the
+// same map.
+type RoleAssignmentFake struct {
+	userRoles   map[string][]role.Role
+	roleChanges []entity.RoleChange
+}
+
+// GrantRole adds the role to the user and records the change.
+func (r *RoleAssignmentFake) GrantRole(change entity.RoleChange) error {
+	if r.hasRole(change.UserID, role.Role(change.Role)) {
+		return ErrEntryExists(fmt.Sprintf("role(%s) of user(%s)", change.Role, change.UserID))
+	}
+	r.userRoles[change.UserID] = append(r.userRoles[change.UserID], role.Role(change.Role))
+	r.roleChanges = append(r.roleChanges, change)
+	return nil
+}
+
+// RevokeRole removes the role from the user and records the change.
+func (r *RoleAssignmentFake) RevokeRole(change entity.RoleChange, keepLastHolder bool) error {
+	revokedRole := role.Role(change.Role)
+	if !r.hasRole(change.UserID, revokedRole) {
+		return ErrEntryNotFound(fmt.Sprintf("role(%s) of user(%s) not found", change.Role, change.UserID))
+	}
+	if keepLastHolder && r.countHolders(revokedRole) < 2 {
+		return ErrLastEntry(fmt.Sprintf("user(%s) is the last one with role(%s)", change.UserID, change.Role))
+	}
+
+	var roles []role.Role
+	for _, userRole := range r.userRoles[change.UserID] {
+		if userRole != revokedRole {
+			roles = append(roles, userRole)
+		}
+	}
+	r.userRoles[change.UserID] = roles
+	r.roleChanges = append(r.roleChanges, change)
+	return nil
+}
+
+// GetRoleChanges fetches the role changes of the given user, with the latest
+// first.
+func (r RoleAssignmentFake) GetRoleChanges(userID string) ([]entity.RoleChange, error) {
+	roleChanges := []entity.RoleChange{}
+	for idx := len(r.roleChanges) - 1; idx >= 0; idx-- {
+		if r.roleChanges[idx].UserID == userID {
+			roleChanges = append(roleChanges, r.roleChanges[idx])
+		}
+	}
+	return roleChanges, nil
+}
+
+func (r RoleAssignmentFake) hasRole(userID string, target role.Role) bool {
+	for _, userRole := range r.userRoles[userID] {
+		if userRole == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RoleAssignmentFake) countHolders(target role.Role) int {
+	holders := 0
+	for userID := range r.userRoles {
+		if r.hasRole(userID, target) {
+			holders++
+		}
+	}
+	return holders
+}
+
+// NewRoleAssignmentFake creates RoleAssignmentFake.
+func NewRoleAssignmentFake(userRoles map[string][]role.Role) RoleAssignmentFake {
+	return RoleAssignmentFake{userRoles: userRoles}
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// Session accesses user sessions from persistent storage, such as database.
+type Session interface {
+	GetSessionByID(id string) (entity.Session, error)
+	GetSessionsByUser(userID string) ([]entity.Session, error)
+	CreateSession(session entity.Session) error
+	RotateRefreshToken(id string, generation int, expireAt time.Time) error
+	RevokeSession(id string, revokedAt time.Time) error
+	RevokeSessionsByUser(userID string, revokedAt time.Time) error
+}
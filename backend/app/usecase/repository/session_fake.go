@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+var _ Session = (*SessionFake)(nil)
+
+// SessionFake represents in memory implementation of Session repository.
+type SessionFake struct {
+	sessions []entity.Session
+}
+
+// GetSessionByID fetches the session with the given ID.
+func (s SessionFake) GetSessionByID(id string) (entity.Session, error) {
+	idx, err := s.findSession(id)
+	if err != nil {
+		return entity.Session{}, err
+	}
+	return s.sessions[idx], nil
+}
+
+// GetSessionsByUser fetches all the sessions of the given user, with the
+// latest first.
+func (s SessionFake) GetSessionsByUser(userID string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// CreateSession adds a new session.
+func (s *SessionFake) CreateSession(session entity.Session) error {
+	_, err := s.findSession(session.ID)
+	if err == nil {
+		return ErrEntryExists(fmt.Sprintf("session(%s)", session.ID))
+	}
+	s.sessions = append(s.sessions, session)
+	return nil
+}
+
+// RotateRefreshToken advances the refresh token of an active session to the
+// next generation if it is still at the given generation.
+func (s *SessionFake) RotateRefreshToken(id string, generation int, expireAt time.Time) error {
+	idx, err := s.findSession(id)
+	if err != nil {
+		return err
+	}
+
+	session := s.sessions[idx]
+	if session.RevokedAt != nil || session.RefreshTokenGeneration != generation {
+		return ErrEntryNotFound(fmt.Sprintf("session(%s) at generation %d", id, generation))
+	}
+	s.sessions[idx].RefreshTokenGeneration = generation + 1
+	s.sessions[idx].ExpireAt = expireAt
+	return nil
+}
+
+// RevokeSession invalidates the session with the given ID.
+func (s *SessionFake) RevokeSession(id string, revokedAt time.Time) error {
+	idx, err := s.findSession(id)
+	if err != nil {
+		return err
+	}
+	if s.sessions[idx].RevokedAt == nil {
+		s.sessions[idx].RevokedAt = &revokedAt
+	}
+	return nil
+}
+
+// RevokeSessionsByUser invalidates all the sessions of the given user.
+func (s *SessionFake) RevokeSessionsByUser(userID string, revokedAt time.Time) error {
+	for idx, session := range s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			s.sessions[idx].RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
+
+func (s SessionFake) findSession(id string) (int, error) {
+	for idx, session := range s.sessions {
+		if session.ID == id {
+			return idx, nil
+		}
+	}
+	return 0, ErrEntryNotFound(fmt.Sprintf("session(%s) not found", id))
+}
+
+// NewSessionFake creates in memory implementation of Session repository.
+func NewSessionFake(sessions []entity.Session) SessionFake {
+	return SessionFake{sessions: sessions}
+}
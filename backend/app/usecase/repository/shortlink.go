@@ -2,8 +2,10 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/entity/metatag"
 )
 
 var _ error = (*ErrAliasNotFound)(nil)
@@ -18,12 +20,41 @@ func (e ErrAliasNotFound) Error() string {
 	return fmt.Sprintf("short link with alias(%s) not found", e.Alias)
 }
 
+// QuotaCheck decides whether one more short link can be created, given the
+// number of short links created in total and at or after Since, including the
+// deleted ones. Check rejects the creation by returning an error.
+type QuotaCheck struct {
+	Since time.Time
+	Check func(totalCreated int, createdSince int) error
+}
+
 // ShortLink accesses shortLinks from storage, such as database.
 type ShortLink interface {
 	IsAliasExist(alias string) (bool, error)
 	GetShortLinkByAlias(alias string) (entity.ShortLink, error)
 	CreateShortLink(shortLinkInput entity.ShortLinkInput) error
+	// CreateUserShortLink persists the short link together with its
+	// relationship with the user and records its creation, all in a single
+	// transaction. The creations of the same user are serialized, so that
+	// quotaCheck always sees the creations recorded before.
+	CreateUserShortLink(user entity.User, shortLinkInput entity.ShortLinkInput, quotaCheck QuotaCheck) error
+	// CreateAppShortLink is the same as CreateUserShortLink, except that the
+	// short link is created by the app.
+	CreateAppShortLink(app entity.App, shortLinkInput entity.ShortLinkInput, quotaCheck QuotaCheck) error
 	UpdateShortLink(oldAlias string, shortLinkInput entity.ShortLinkInput) (entity.ShortLink, error)
 	DeleteShortLink(alias string) error
 	GetShortLinksByAliases(aliases []string) ([]entity.ShortLink, error)
+	GetShortLinksCheckedBefore(checkedBefore time.Time, limit int) ([]entity.ShortLink, error)
+	UpdateHealth(alias string, health entity.LinkHealth) error
+	UpdateOpenGraphTags(alias string, openGraphTags metatag.OpenGraph) (entity.ShortLink, error)
+	UpdateTwitterTags(alias string, twitterTags metatag.Twitter) (entity.ShortLink, error)
+	// UpdateScrapedMetaTags stores scraped meta tags unless the long link has
+	// changed or the meta tags are customized through UpdateOpenGraphTags or
+	// UpdateTwitterTags.
+	UpdateScrapedMetaTags(
+		alias string,
+		longLink string,
+		openGraphTags metatag.OpenGraph,
+		twitterTags metatag.Twitter,
+	) error
 }
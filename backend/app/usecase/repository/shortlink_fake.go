@@ -2,18 +2,22 @@ package repository
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/entity/metatag"
 )
 
 var _ ShortLink = (*ShortLinkFake)(nil)
 
 // ShortLinkFake accesses ShortLink information in short_link table through SQL.
 type ShortLinkFake struct {
-	shortLinks map[string]entity.ShortLink
+	shortLinks     map[string]entity.ShortLink
+	customMetaTags map[string]bool
 	// TODO(issue#958) use eventbus for propagating short link change to all related repos
 	userShortLinkRepoFake *UserShortLinkFake
+	appShortLinkRepoFake  *AppShortLinkFake
 }
 
 // IsAliasExist checks whether a given alias exist in short_link table.
@@ -44,6 +48,52 @@ func (s *ShortLinkFake) CreateShortLink(shortLinkInput entity.ShortLinkInput) er
 	return nil
 }
 
+// CreateUserShortLink inserts a new ShortLink created by the user into
+// short_link table if the quota check passes.
+func (s *ShortLinkFake) CreateUserShortLink(user entity.User, shortLinkInput entity.ShortLinkInput, quotaCheck QuotaCheck) error {
+	totalCreated, createdSince := 0, 0
+	if s.userShortLinkRepoFake != nil {
+		totalCreated, _ = s.userShortLinkRepoFake.CountShortLinksByUser(user)
+		createdSince, _ = s.userShortLinkRepoFake.CountShortLinksByUserSince(user, quotaCheck.Since)
+	}
+	err := quotaCheck.Check(totalCreated, createdSince)
+	if err != nil {
+		return err
+	}
+
+	err = s.CreateShortLink(shortLinkInput)
+	if err != nil {
+		return err
+	}
+	if s.userShortLinkRepoFake == nil {
+		return nil
+	}
+	return s.userShortLinkRepoFake.CreateRelation(user, shortLinkInput)
+}
+
+// CreateAppShortLink inserts a new ShortLink created by the app into
+// short_link table if the quota check passes.
+func (s *ShortLinkFake) CreateAppShortLink(app entity.App, shortLinkInput entity.ShortLinkInput, quotaCheck QuotaCheck) error {
+	totalCreated, createdSince := 0, 0
+	if s.appShortLinkRepoFake != nil {
+		totalCreated, _ = s.appShortLinkRepoFake.CountShortLinksByApp(app)
+		createdSince, _ = s.appShortLinkRepoFake.CountShortLinksByAppSince(app, quotaCheck.Since)
+	}
+	err := quotaCheck.Check(totalCreated, createdSince)
+	if err != nil {
+		return err
+	}
+
+	err = s.CreateShortLink(shortLinkInput)
+	if err != nil {
+		return err
+	}
+	if s.appShortLinkRepoFake == nil {
+		return nil
+	}
+	return s.appShortLinkRepoFake.CreateRelation(app, shortLinkInput)
+}
+
 // GetShortLinkByAlias finds an ShortLink in short_link table given alias.
 func (s ShortLinkFake) GetShortLinkByAlias(alias string) (entity.ShortLink, error) {
 	isExist, err := s.IsAliasExist(alias)
@@ -51,7 +101,7 @@ func (s ShortLinkFake) GetShortLinkByAlias(alias string) (entity.ShortLink, erro
 		return entity.ShortLink{}, err
 	}
 	if !isExist {
-		return entity.ShortLink{}, errors.New("alias not found")
+		return entity.ShortLink{}, ErrAliasNotFound{Alias: alias}
 	}
 	shortLink := s.shortLinks[alias]
 	return shortLink, nil
@@ -75,6 +125,94 @@ func (s ShortLinkFake) GetShortLinksByAliases(aliases []string) ([]entity.ShortL
 	return shortLinks, nil
 }
 
+// GetShortLinksCheckedBefore finds at most limit ShortLinks which have never
+// been checked or were last checked before the given time, least recently
+// checked first.
+func (s ShortLinkFake) GetShortLinksCheckedBefore(checkedBefore time.Time, limit int) ([]entity.ShortLink, error) {
+	var shortLinks []entity.ShortLink
+	for _, shortLink := range s.shortLinks {
+		checkedAt := shortLink.Health.CheckedAt
+		if checkedAt != nil && !checkedAt.Before(checkedBefore) {
+			continue
+		}
+		shortLinks = append(shortLinks, shortLink)
+	}
+
+	sort.Slice(shortLinks, func(i, j int) bool {
+		left := shortLinks[i].Health.CheckedAt
+		right := shortLinks[j].Health.CheckedAt
+		switch {
+		case left == nil && right == nil:
+			return shortLinks[i].Alias < shortLinks[j].Alias
+		case left == nil || right == nil:
+			return left == nil
+		case left.Equal(*right):
+			return shortLinks[i].Alias < shortLinks[j].Alias
+		default:
+			return left.Before(*right)
+		}
+	})
+
+	if len(shortLinks) > limit {
+		shortLinks = shortLinks[:limit]
+	}
+	return shortLinks, nil
+}
+
+// UpdateHealth records the result of the latest reachability check of a
+// ShortLink.
+func (s ShortLinkFake) UpdateHealth(alias string, health entity.LinkHealth) error {
+	shortLink, ok := s.shortLinks[alias]
+	if !ok {
+		return ErrAliasNotFound{Alias: alias}
+	}
+	shortLink.Health = health
+	s.shortLinks[alias] = shortLink
+	return nil
+}
+
+// UpdateOpenGraphTags updates OpenGraph meta tags for a given short link.
+func (s ShortLinkFake) UpdateOpenGraphTags(alias string, openGraphTags metatag.OpenGraph) (entity.ShortLink, error) {
+	shortLink, ok := s.shortLinks[alias]
+	if !ok {
+		return entity.ShortLink{}, ErrAliasNotFound{Alias: alias}
+	}
+	shortLink.OpenGraphTags = openGraphTags
+	s.shortLinks[alias] = shortLink
+	s.customMetaTags[alias] = true
+	return shortLink, nil
+}
+
+// UpdateTwitterTags updates Twitter meta tags for a given short link.
+func (s ShortLinkFake) UpdateTwitterTags(alias string, twitterTags metatag.Twitter) (entity.ShortLink, error) {
+	shortLink, ok := s.shortLinks[alias]
+	if !ok {
+		return entity.ShortLink{}, ErrAliasNotFound{Alias: alias}
+	}
+	shortLink.TwitterTags = twitterTags
+	s.shortLinks[alias] = shortLink
+	s.customMetaTags[alias] = true
+	return shortLink, nil
+}
+
+// UpdateScrapedMetaTags stores scraped meta tags unless the long link has
+// changed or the meta tags are customized.
+func (s ShortLinkFake) UpdateScrapedMetaTags(
+	alias string,
+	longLink string,
+	openGraphTags metatag.OpenGraph,
+	twitterTags metatag.Twitter,
+) error {
+	shortLink, ok := s.shortLinks[alias]
+	if !ok || shortLink.LongLink != longLink || s.customMetaTags[alias] {
+		return nil
+	}
+	shortLink.OpenGraphTags = openGraphTags
+	shortLink.TwitterTags = twitterTags
+	s.shortLinks[alias] = shortLink
+	return nil
+}
+
 // UpdateShortLink updates an existing ShortLink with new properties.
 func (s ShortLinkFake) UpdateShortLink(oldAlias string, shortLinkInput entity.ShortLinkInput) (entity.ShortLink, error) {
 	if shortLinkInput.CustomAlias == nil {
@@ -87,9 +225,11 @@ func (s ShortLinkFake) UpdateShortLink(oldAlias string, shortLinkInput entity.Sh
 	}
 
 	// TODO(issue#958) use eventbus for propagating short link change to all related repos
-	err := s.userShortLinkRepoFake.UpdateAliasCascade(oldAlias, shortLinkInput)
-	if err != nil {
-		return entity.ShortLink{}, err
+	if s.userShortLinkRepoFake != nil {
+		err := s.userShortLinkRepoFake.UpdateAliasCascade(oldAlias, shortLinkInput)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
 	}
 
 	now := time.Now().UTC()
@@ -120,9 +260,15 @@ func (s ShortLinkFake) DeleteShortLink(alias string) error {
 }
 
 // NewShortLinkFake creates in memory ShortLink repository
-func NewShortLinkFake(userShortLinkRepoFake *UserShortLinkFake, shortLinks map[string]entity.ShortLink) ShortLinkFake {
+func NewShortLinkFake(
+	userShortLinkRepoFake *UserShortLinkFake,
+	appShortLinkRepoFake *AppShortLinkFake,
+	shortLinks map[string]entity.ShortLink,
+) ShortLinkFake {
 	return ShortLinkFake{
 		shortLinks:            shortLinks,
+		customMetaTags:        map[string]bool{},
 		userShortLinkRepoFake: userShortLinkRepoFake,
+		appShortLinkRepoFake:  appShortLinkRepoFake,
 	}
 }
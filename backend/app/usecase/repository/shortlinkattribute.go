@@ -0,0 +1,14 @@
+package repository
+
+// ShortLinkAttribute accesses the attributes describing short links, such as
+// the team owning them and their tags, from storage such as database.
+type ShortLinkAttribute interface {
+	// GetAttributes fetches every value of each attribute of the short link.
+	GetAttributes(alias string) (map[string][]string, error)
+	// AddAttribute adds the value to the attribute of the short link. Adding
+	// a value the attribute already has makes no change.
+	AddAttribute(alias string, name string, value string) error
+	// RemoveAttribute removes the value from the attribute of the short link.
+	// It returns ErrEntryNotFound if the attribute doesn't have the value.
+	RemoveAttribute(alias string, name string, value string) error
+}
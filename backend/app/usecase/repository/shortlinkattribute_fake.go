@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+)
+
+var _ ShortLinkAttribute = (*ShortLinkAttributeFake)(nil)
+
+// ShortLinkAttributeFake represents in memory implementation of
+// ShortLinkAttribute repository.
+type ShortLinkAttributeFake struct {
+	attributes map[string]map[string][]string
+}
+
+// GetAttributes fetches every value of each attribute of the short link.
+func (s ShortLinkAttributeFake) GetAttributes(alias string) (map[string][]string, error) {
+	attributes := map[string][]string{}
+	for name, values := range s.attributes[alias] {
+		attributes[name] = append([]string{}, values...)
+	}
+	return attributes, nil
+}
+
+// AddAttribute adds the value to the attribute of the short link.
+func (s *ShortLinkAttributeFake) AddAttribute(alias string, name string, value string) error {
+	if s.attributes[alias] == nil {
+		s.attributes[alias] = map[string][]string{}
+	}
+	values := s.attributes[alias][name]
+	idx := sort.SearchStrings(values, value)
+	if idx < len(values) && values[idx] == value {
+		return nil
+	}
+	values = append(values, "")
+	copy(values[idx+1:], values[idx:])
+	values[idx] = value
+	s.attributes[alias][name] = values
+	return nil
+}
+
+// RemoveAttribute removes the value from the attribute of the short link.
+func (s *ShortLinkAttributeFake) RemoveAttribute(alias string, name string, value string) error {
+	values := s.attributes[alias][name]
+	for idx, current := range values {
+		if current != value {
+			continue
+		}
+		values = append(values[:idx:idx], values[idx+1:]...)
+		if len(values) == 0 {
+			delete(s.attributes[alias], name)
+			return nil
+		}
+		s.attributes[alias][name] = values
+		return nil
+	}
+	return ErrEntryNotFound(fmt.Sprintf("attribute(%s=%s) of short link(%s) not found", name, value, alias))
+}
+
+// NewShortLinkAttributeFake creates ShortLinkAttributeFake with the attributes
+// of each short link, keyed by alias.
+func NewShortLinkAttributeFake(attributes map[string]map[string][]string) ShortLinkAttributeFake {
+	if attributes == nil {
+		attributes = map[string]map[string][]string{}
+	}
+	return ShortLinkAttributeFake{attributes: attributes}
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// ShortLinkVisit accesses the visits of short links from storage such as
+// database.
+type ShortLinkVisit interface {
+	// RecordVisit counts a visit to the short link at the given time.
+	RecordVisit(alias string, visitedAt time.Time) error
+	// GetAnalytics fetches how often the short link has been visited. The
+	// analytics are empty for the short links never visited.
+	GetAnalytics(alias string) (entity.ShortLinkAnalytics, error)
+}
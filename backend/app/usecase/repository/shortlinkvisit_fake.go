@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+var _ ShortLinkVisit = (*ShortLinkVisitFake)(nil)
+
+// ShortLinkVisitFake represents in memory implementation of ShortLinkVisit
+// repository.
+type ShortLinkVisitFake struct {
+	analytics map[string]entity.ShortLinkAnalytics
+}
+
+// RecordVisit counts a visit to the short link at the given time.
+func (s *ShortLinkVisitFake) RecordVisit(alias string, visitedAt time.Time) error {
+	analytics := s.analytics[alias]
+	analytics.VisitCount++
+	analytics.LastVisitedAt = &visitedAt
+	s.analytics[alias] = analytics
+	return nil
+}
+
+// GetAnalytics fetches how often the short link has been visited.
+func (s ShortLinkVisitFake) GetAnalytics(alias string) (entity.ShortLinkAnalytics, error) {
+	return s.analytics[alias], nil
+}
+
+// NewShortLinkVisitFake creates ShortLinkVisitFake with the analytics of each
+// short link, keyed by alias.
+func NewShortLinkVisitFake(analytics map[string]entity.ShortLinkAnalytics) ShortLinkVisitFake {
+	if analytics == nil {
+		analytics = map[string]entity.ShortLinkAnalytics{}
+	}
+	return ShortLinkVisitFake{analytics: analytics}
+}
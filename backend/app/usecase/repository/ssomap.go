@@ -6,4 +6,6 @@ type SSOMap interface {
 	IsSSOUserExist(ssoUserID string) (bool, error)
 	GetShortUserID(ssoUserID string) (string, error)
 	CreateMapping(sshUserID string, shortUserID string) error
+	GetSSOUserID(shortUserID string) (string, error)
+	DeleteMapping(ssoUserID string, shortUserID string) error
 }
@@ -60,6 +60,30 @@ func (s *SSOMapFake) CreateMapping(ssoUserID string, userID string) error {
 	return nil
 }
 
+// GetSSOUserID retrieves the external user ID that is linked to the internal
+// user.
+func (s SSOMapFake) GetSSOUserID(shortUserID string) (string, error) {
+	for idx, currUserID := range s.userIDs {
+		if currUserID == shortUserID {
+			return s.ssoUserIDs[idx], nil
+		}
+	}
+	return "", ErrEntryNotFound("SSO user ID not found")
+}
+
+// DeleteMapping unlinks an external user from an internal user.
+func (s *SSOMapFake) DeleteMapping(ssoUserID string, shortUserID string) error {
+	for idx, currSSOUserID := range s.ssoUserIDs {
+		if currSSOUserID != ssoUserID || s.userIDs[idx] != shortUserID {
+			continue
+		}
+		s.ssoUserIDs = append(s.ssoUserIDs[:idx], s.ssoUserIDs[idx+1:]...)
+		s.userIDs = append(s.userIDs[:idx], s.userIDs[idx+1:]...)
+		return nil
+	}
+	return ErrEntryNotFound("mapping not found")
+}
+
 // NewsSSOMapFake creates in memory implementation of SSOMapFake repository.
 func NewsSSOMapFake(
 	ssoUserIDs []string,
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// TwoFactor accesses the second factors of users and their recovery codes
+// from persistent storage, such as database.
+type TwoFactor interface {
+	GetTwoFactor(userID string) (entity.TwoFactor, error)
+	// CreateTwoFactor replaces the pending enrollment of the user, if any. It
+	// returns ErrEntryExists if the user already enabled a second factor.
+	CreateTwoFactor(twoFactor entity.TwoFactor, recoveryCodeHashes []string) error
+	EnableTwoFactor(userID string, enabledAt time.Time) error
+	// UseTimeStep records the time step of an accepted password. It returns
+	// ErrEntryNotFound unless the time step is later than the last one used.
+	UseTimeStep(userID string, timeStep int64) error
+	// UseRecoveryCode marks the recovery code as used. It returns
+	// ErrEntryNotFound if the code doesn't exist or is already used.
+	UseRecoveryCode(userID string, codeHash string, usedAt time.Time) error
+	DeleteTwoFactor(userID string) error
+}
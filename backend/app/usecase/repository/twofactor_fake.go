@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+var _ TwoFactor = (*TwoFactorFake)(nil)
+
+type recoveryCode struct {
+	userID   string
+	codeHash string
+	isUsed   bool
+}
+
+// TwoFactorFake represents in memory implementation of TwoFactor repository.
+type TwoFactorFake struct {
+	twoFactors    []entity.TwoFactor
+	recoveryCodes []recoveryCode
+}
+
+// GetTwoFactor fetches the second factor of the given user.
+func (t TwoFactorFake) GetTwoFactor(userID string) (entity.TwoFactor, error) {
+	idx, err := t.findTwoFactor(userID)
+	if err != nil {
+		return entity.TwoFactor{}, err
+	}
+	return t.twoFactors[idx], nil
+}
+
+// CreateTwoFactor replaces the pending enrollment of the user with a new one.
+func (t *TwoFactorFake) CreateTwoFactor(twoFactor entity.TwoFactor, recoveryCodeHashes []string) error {
+	idx, err := t.findTwoFactor(twoFactor.UserID)
+	if err == nil {
+		if t.twoFactors[idx].IsEnabled() {
+			return ErrEntryExists(fmt.Sprintf("two factor of user(%s)", twoFactor.UserID))
+		}
+		err = t.DeleteTwoFactor(twoFactor.UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.twoFactors = append(t.twoFactors, twoFactor)
+	for _, codeHash := range recoveryCodeHashes {
+		t.recoveryCodes = append(t.recoveryCodes, recoveryCode{
+			userID:   twoFactor.UserID,
+			codeHash: codeHash,
+		})
+	}
+	return nil
+}
+
+// EnableTwoFactor marks the second factor of the user as enabled.
+func (t *TwoFactorFake) EnableTwoFactor(userID string, enabledAt time.Time) error {
+	idx, err := t.findTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	t.twoFactors[idx].EnabledAt = &enabledAt
+	return nil
+}
+
+// UseTimeStep records the time step of an accepted password if it is later
+// than the last one used.
+func (t *TwoFactorFake) UseTimeStep(userID string, timeStep int64) error {
+	idx, err := t.findTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if timeStep <= t.twoFactors[idx].LastTimeStep {
+		return ErrEntryNotFound(
+			fmt.Sprintf("two factor of user(%s) before time step %d not found", userID, timeStep))
+	}
+	t.twoFactors[idx].LastTimeStep = timeStep
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used.
+func (t *TwoFactorFake) UseRecoveryCode(userID string, codeHash string, usedAt time.Time) error {
+	for idx, code := range t.recoveryCodes {
+		if code.userID != userID || code.codeHash != codeHash || code.isUsed {
+			continue
+		}
+		t.recoveryCodes[idx].isUsed = true
+		return nil
+	}
+	return ErrEntryNotFound(fmt.Sprintf("unused recovery code of user(%s) not found", userID))
+}
+
+// DeleteTwoFactor removes the second factor of the user with its recovery
+// codes.
+func (t *TwoFactorFake) DeleteTwoFactor(userID string) error {
+	idx, err := t.findTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	t.twoFactors = append(t.twoFactors[:idx], t.twoFactors[idx+1:]...)
+
+	var recoveryCodes []recoveryCode
+	for _, code := range t.recoveryCodes {
+		if code.userID != userID {
+			recoveryCodes = append(recoveryCodes, code)
+		}
+	}
+	t.recoveryCodes = recoveryCodes
+	return nil
+}
+
+func (t TwoFactorFake) findTwoFactor(userID string) (int, error) {
+	for idx, twoFactor := range t.twoFactors {
+		if twoFactor.UserID == userID {
+			return idx, nil
+		}
+	}
+	return 0, ErrEntryNotFound(fmt.Sprintf("two factor of user(%s) not found", userID))
+}
+
+// NewTwoFactorFake creates TwoFactorFake.
+func NewTwoFactorFake(twoFactors []entity.TwoFactor) TwoFactorFake {
+	return TwoFactorFake{twoFactors: twoFactors}
+}
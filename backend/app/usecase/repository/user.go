@@ -9,4 +9,7 @@ type User interface {
 	GetUserByID(id string) (entity.User, error)
 	GetUserByEmail(email string) (entity.User, error)
 	CreateUser(user entity.User) error
+	// GetUsers fetches at most limit users ordered by ID, starting after the
+	// user with the given ID.
+	GetUsers(afterID string, limit int) ([]entity.User, error)
 }
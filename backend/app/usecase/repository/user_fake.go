@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/short-d/short/backend/app/entity"
 )
@@ -74,6 +75,27 @@ func (u *UserFake) CreateUser(user entity.User) error {
 	return nil
 }
 
+// GetUsers fetches at most limit users ordered by ID, starting after the
+// user with the given ID.
+func (u UserFake) GetUsers(afterID string, limit int) ([]entity.User, error) {
+	users := make([]entity.User, len(u.users))
+	copy(users, u.users)
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	page := []entity.User{}
+	for _, user := range users {
+		if len(page) >= limit {
+			break
+		}
+		if user.ID > afterID {
+			page = append(page, user)
+		}
+	}
+	return page, nil
+}
+
 // NewUserFake create in memory user repository implementation.
 func NewUserFake(users []entity.User) UserFake {
 	return UserFake{
@@ -0,0 +1,11 @@
+package repository
+
+import "time"
+
+// UserMerger combines two users belonging to the same person. Everything
+// owned by the source user is moved to the target user before the source
+// user is removed from storage, such as database. The moved roles are
+// recorded as changes made by the target user at mergedAt.
+type UserMerger interface {
+	MergeUser(sourceUserID string, targetUserID string, mergedAt time.Time) error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ UserMerger = (*UserMergerFake)(nil)
+
+// UserMergerFake represents in memory implementation of UserMerger.
+type UserMergerFake struct {
+	userRepo *UserFake
+	ssoMaps  []*SSOMapFake
+}
+
+// MergeUser moves the external accounts of the source user to the target user
+// and removes the source user.
+func (u UserMergerFake) MergeUser(sourceUserID string, targetUserID string, mergedAt time.Time) error {
+	if sourceUserID == targetUserID {
+		return fmt.Errorf("can't merge user %s into itself", sourceUserID)
+	}
+	for _, ssoMap := range u.ssoMaps {
+		for idx, userID := range ssoMap.userIDs {
+			if userID == sourceUserID {
+				ssoMap.userIDs[idx] = targetUserID
+			}
+		}
+	}
+	for idx, user := range u.userRepo.users {
+		if user.ID == sourceUserID {
+			u.userRepo.users = append(u.userRepo.users[:idx], u.userRepo.users[idx+1:]...)
+			return nil
+		}
+	}
+	return ErrEntryNotFound(fmt.Sprintf("user %s not found", sourceUserID))
+}
+
+// NewUserMergerFake creates UserMergerFake.
+func NewUserMergerFake(userRepo *UserFake, ssoMaps ...*SSOMapFake) UserMergerFake {
+	return UserMergerFake{
+		userRepo: userRepo,
+		ssoMaps:  ssoMaps,
+	}
+}
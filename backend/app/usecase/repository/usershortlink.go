@@ -1,10 +1,23 @@
 package repository
 
-import "github.com/short-d/short/backend/app/entity"
+import (
+	"time"
+
+	"github.com/short-d/short/backend/app/entity"
+)
 
 // UserShortLink accesses User-ShortLink relationship from storage, such as database.
 type UserShortLink interface {
 	CreateRelation(user entity.User, shortLinkInput entity.ShortLinkInput) error
 	FindAliasesByUser(user entity.User) ([]string, error)
 	HasMapping(user entity.User, alias string) (bool, error)
+	// FindUserIDByAlias fails with ErrEntryNotFound when the short link is not
+	// created by any user.
+	FindUserIDByAlias(alias string) (string, error)
+	// CountShortLinksByUser counts the short links ever created by the user,
+	// including the deleted ones.
+	CountShortLinksByUser(user entity.User) (int, error)
+	// CountShortLinksByUserSince counts the short links created by the user at
+	// or after the given time, including the deleted ones.
+	CountShortLinksByUserSince(user entity.User, since time.Time) (int, error)
 }
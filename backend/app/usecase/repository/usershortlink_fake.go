@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/short-d/short/backend/app/entity"
 )
@@ -60,6 +61,44 @@ func (u UserShortLinkFake) HasMapping(user entity.User, alias string) (bool, err
 	return false, nil
 }
 
+// FindUserIDByAlias fetches the ID of the user who created the short link.
+func (u UserShortLinkFake) FindUserIDByAlias(alias string) (string, error) {
+	for idx, currUser := range u.users {
+		if u.shortLinks[idx].Alias == alias {
+			return currUser.ID, nil
+		}
+	}
+	return "", ErrEntryNotFound(fmt.Sprintf("owner of short link(%s) not found", alias))
+}
+
+// CountShortLinksByUser counts the short links created by the given user.
+func (u UserShortLinkFake) CountShortLinksByUser(user entity.User) (int, error) {
+	count := 0
+	for _, currUser := range u.users {
+		if currUser.ID == user.ID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountShortLinksByUserSince counts the short links created by the given user
+// at or after the given time.
+func (u UserShortLinkFake) CountShortLinksByUserSince(user entity.User, since time.Time) (int, error) {
+	count := 0
+	for idx, currUser := range u.users {
+		if currUser.ID != user.ID {
+			continue
+		}
+		createdAt := u.shortLinks[idx].CreatedAt
+		if createdAt == nil || createdAt.Before(since) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
 // UpdateAliasCascade updates user-shortlink relationships to reflect changes to alias.
 // TODO(issue#958) use eventbus for propagating short link change to all related repos
 func (u *UserShortLinkFake) UpdateAliasCascade(oldAlias string, shortLinkInput entity.ShortLinkInput) error {
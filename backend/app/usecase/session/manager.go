@@ -0,0 +1,124 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// ErrUnauthorizedAction represents the failure of managing sessions of
+// another user without the required permission.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrSessionNotFound represents the failure of finding a session with given
+// ID.
+type ErrSessionNotFound string
+
+var _ error = (*ErrSessionNotFound)(nil)
+
+func (e ErrSessionNotFound) Error() string {
+	return fmt.Sprintf("session(%s) not found", string(e))
+}
+
+// Manager lists and revokes the places where users are signed in.
+type Manager struct {
+	sessionRepo repository.Session
+	authorizer  authorizer.Authorizer
+	timer       timer.Timer
+}
+
+// GetActiveSessions retrieves the sessions the given user is currently signed
+// in with, with the latest first.
+func (m Manager) GetActiveSessions(user entity.User) ([]entity.Session, error) {
+	sessions, err := m.sessionRepo.GetSessionsByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.timer.Now()
+	activeSessions := make([]entity.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsActive(now) {
+			activeSessions = append(activeSessions, session)
+		}
+	}
+	return activeSessions, nil
+}
+
+// GetUserSessions retrieves all the sessions of any user, including the
+// expired and revoked ones, for security review.
+func (m Manager) GetUserSessions(viewer entity.User, userID string) ([]entity.Session, error) {
+	canView, err := m.authorizer.CanViewSessions(viewer)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrUnauthorizedAction{
+			UserID: viewer.ID,
+			Action: fmt.Sprintf("view sessions of user(%s)", userID),
+		}
+	}
+
+	sessions, err := m.sessionRepo.GetSessionsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if sessions == nil {
+		return []entity.Session{}, nil
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs the owner of the session out of it. Users can revoke
+// their own sessions, while revoking sessions of other users requires
+// permission.
+func (m Manager) RevokeSession(user entity.User, id string) error {
+	session, err := m.sessionRepo.GetSessionByID(id)
+	var notFound repository.ErrEntryNotFound
+	if errors.As(err, &notFound) {
+		return ErrSessionNotFound(id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if session.UserID != user.ID {
+		canRevoke, err := m.authorizer.CanRevokeSessions(user)
+		if err != nil {
+			return err
+		}
+		if !canRevoke {
+			return ErrUnauthorizedAction{
+				UserID: user.ID,
+				Action: fmt.Sprintf("revoke session(%s)", id),
+			}
+		}
+	}
+	return m.sessionRepo.RevokeSession(id, m.timer.Now().UTC())
+}
+
+// NewManager creates Manager.
+func NewManager(
+	sessionRepo repository.Session,
+	authorizer authorizer.Authorizer,
+	timer timer.Timer,
+) Manager {
+	return Manager{
+		sessionRepo: sessionRepo,
+		authorizer:  authorizer,
+		timer:       timer,
+	}
+}
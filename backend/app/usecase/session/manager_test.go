@@ -0,0 +1,239 @@
+// +build !integration all
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestManager_GetActiveSessions(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	sessions := newSessions(now)
+
+	testCases := []struct {
+		name             string
+		user             entity.User
+		expectedSessions []entity.Session
+	}{
+		{
+			name:             "no session",
+			user:             entity.User{ID: "gamma"},
+			expectedSessions: []entity.Session{},
+		},
+		{
+			name: "skip expired and revoked sessions",
+			user: entity.User{ID: "alpha"},
+			expectedSessions: []entity.Session{
+				sessions[1],
+				sessions[0],
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, _ := newManager(sessions, map[string][]role.Role{}, now)
+
+			gotSessions, err := manager.GetActiveSessions(testCase.user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedSessions, gotSessions)
+		})
+	}
+}
+
+func TestManager_GetUserSessions(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	sessions := newSessions(now)
+
+	testCases := []struct {
+		name             string
+		roles            map[string][]role.Role
+		viewer           entity.User
+		userID           string
+		hasErr           bool
+		expectedSessions []entity.Session
+	}{
+		{
+			name:   "viewer without permission",
+			roles:  map[string][]role.Role{"beta": {role.Basic}},
+			viewer: entity.User{ID: "beta"},
+			userID: "alpha",
+			hasErr: true,
+		},
+		{
+			name:             "user without session",
+			roles:            map[string][]role.Role{"beta": {role.SecuritySpecialist}},
+			viewer:           entity.User{ID: "beta"},
+			userID:           "gamma",
+			hasErr:           false,
+			expectedSessions: []entity.Session{},
+		},
+		{
+			name:   "include expired and revoked sessions",
+			roles:  map[string][]role.Role{"beta": {role.SecuritySpecialist}},
+			viewer: entity.User{ID: "beta"},
+			userID: "alpha",
+			hasErr: false,
+			expectedSessions: []entity.Session{
+				sessions[3],
+				sessions[2],
+				sessions[1],
+				sessions[0],
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, _ := newManager(sessions, testCase.roles, now)
+
+			gotSessions, err := manager.GetUserSessions(testCase.viewer, testCase.userID)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedSessions, gotSessions)
+		})
+	}
+}
+
+func TestManager_RevokeSession(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	sessions := newSessions(now)
+
+	testCases := []struct {
+		name      string
+		roles     map[string][]role.Role
+		user      entity.User
+		sessionID string
+		hasErr    bool
+	}{
+		{
+			name:      "session not found",
+			roles:     map[string][]role.Role{},
+			user:      entity.User{ID: "alpha"},
+			sessionID: "unknown",
+			hasErr:    true,
+		},
+		{
+			name:      "revoke own session",
+			roles:     map[string][]role.Role{},
+			user:      entity.User{ID: "alpha"},
+			sessionID: "laptop",
+			hasErr:    false,
+		},
+		{
+			name:      "revoke session of other user without permission",
+			roles:     map[string][]role.Role{"beta": {role.Basic}},
+			user:      entity.User{ID: "beta"},
+			sessionID: "laptop",
+			hasErr:    true,
+		},
+		{
+			name:      "revoke session of other user with permission",
+			roles:     map[string][]role.Role{"beta": {role.SecuritySpecialist}},
+			user:      entity.User{ID: "beta"},
+			sessionID: "laptop",
+			hasErr:    false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, sessionRepo := newManager(sessions, testCase.roles, now)
+
+			err := manager.RevokeSession(testCase.user, testCase.sessionID)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			gotSession, err := sessionRepo.GetSessionByID(testCase.sessionID)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, false, gotSession.IsActive(now))
+		})
+	}
+}
+
+func newSessions(now time.Time) []entity.Session {
+	revokedAt := now.Add(-time.Minute)
+	return []entity.Session{
+		{
+			ID:               "laptop",
+			UserID:           "alpha",
+			IdentityProvider: "github",
+			Device: entity.Device{
+				ClientIP:  "10.0.0.1",
+				Location:  "San Francisco, California, United States",
+				UserAgent: "Mozilla/5.0",
+			},
+			CreatedAt: now.Add(-4 * time.Hour),
+			ExpireAt:  now.Add(time.Hour),
+		},
+		{
+			ID:               "phone",
+			UserID:           "alpha",
+			IdentityProvider: "google",
+			CreatedAt:        now.Add(-3 * time.Hour),
+			ExpireAt:         now.Add(time.Hour),
+		},
+		{
+			ID:        "expired",
+			UserID:    "alpha",
+			CreatedAt: now.Add(-2 * time.Hour),
+			ExpireAt:  now.Add(-time.Hour),
+		},
+		{
+			ID:        "revoked",
+			UserID:    "alpha",
+			CreatedAt: now.Add(-time.Hour),
+			ExpireAt:  now.Add(time.Hour),
+			RevokedAt: &revokedAt,
+		},
+		{
+			ID:        "tablet",
+			UserID:    "beta",
+			CreatedAt: now.Add(-time.Hour),
+			ExpireAt:  now.Add(time.Hour),
+		},
+	}
+}
+
+func newManager(
+	sessions []entity.Session,
+	roles map[string][]role.Role,
+	now time.Time,
+) (Manager, *repository.SessionFake) {
+	sessions = append([]entity.Session{}, sessions...)
+	sessionRepo := repository.NewSessionFake(sessions)
+	userRoleRepo := repository.NewUserRoleFake(roles)
+	auth := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+	return NewManager(&sessionRepo, auth, timer.NewStub(now)), &sessionRepo
+}
@@ -0,0 +1,36 @@
+package shortlink
+
+import (
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/validator"
+)
+
+// isAliasAllowed checks whether the user can claim the given alias. Users
+// permitted to use restricted aliases bypass the reserved and blocked word
+// lists, but the alias still needs to have valid format.
+func isAliasAllowed(
+	aliasValidator validator.CustomAlias,
+	authorizer authorizer.Authorizer,
+	alias string,
+	user entity.User,
+) (bool, validator.Violation, error) {
+	isValid, violation := aliasValidator.IsValid(alias)
+	if isValid {
+		return true, validator.Valid, nil
+	}
+
+	isValidFormat, formatViolation := aliasValidator.IsValidFormat(alias)
+	if !isValidFormat {
+		return false, formatViolation, nil
+	}
+
+	canBypass, err := authorizer.CanUseRestrictedAlias(user)
+	if err != nil {
+		return false, violation, err
+	}
+	if !canBypass {
+		return false, violation, nil
+	}
+	return true, validator.Valid, nil
+}
@@ -0,0 +1,79 @@
+package shortlink
+
+import (
+	"errors"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ Analytics = (*AnalyticsPersist)(nil)
+
+// Analytics records the visits to short links and reports them to the users
+// allowed to view them.
+type Analytics interface {
+	RecordVisit(alias string) error
+	GetAnalytics(alias string, user entity.User) (entity.ShortLinkAnalytics, error)
+}
+
+// AnalyticsPersist keeps the visits to short links in the data store.
+type AnalyticsPersist struct {
+	shortLinkRepo  repository.ShortLink
+	visitRepo      repository.ShortLinkVisit
+	authorizer     authorizer.Authorizer
+	resourceFinder policy.ResourceFinder
+	timer          timer.Timer
+}
+
+// RecordVisit counts a visit to the short link with the given alias, as
+// stored in the data store.
+func (a AnalyticsPersist) RecordVisit(alias string) error {
+	return a.visitRepo.RecordVisit(alias, a.timer.Now())
+}
+
+// GetAnalytics reports how often the short link has been visited. Users can
+// view the analytics of the short links they own, or the short links a policy
+// allows them to view the analytics of.
+func (a AnalyticsPersist) GetAnalytics(alias string, user entity.User) (entity.ShortLinkAnalytics, error) {
+	shortLink, err := a.shortLinkRepo.GetShortLinkByAlias(alias)
+	var errNotFound repository.ErrAliasNotFound
+	if errors.As(err, &errNotFound) {
+		return entity.ShortLinkAnalytics{}, ErrShortLinkNotFound(alias)
+	}
+	if err != nil {
+		return entity.ShortLinkAnalytics{}, err
+	}
+
+	resource, err := a.resourceFinder.FindShortLink(shortLink.Alias)
+	if err != nil {
+		return entity.ShortLinkAnalytics{}, err
+	}
+	canView, err := a.authorizer.CanViewShortLinkAnalytics(user, resource)
+	if err != nil {
+		return entity.ShortLinkAnalytics{}, err
+	}
+	if !canView {
+		return entity.ShortLinkAnalytics{}, ErrShortLinkNotFound(alias)
+	}
+	return a.visitRepo.GetAnalytics(shortLink.Alias)
+}
+
+// NewAnalyticsPersist creates AnalyticsPersist.
+func NewAnalyticsPersist(
+	shortLinkRepo repository.ShortLink,
+	visitRepo repository.ShortLinkVisit,
+	authorizer authorizer.Authorizer,
+	resourceFinder policy.ResourceFinder,
+	timer timer.Timer,
+) AnalyticsPersist {
+	return AnalyticsPersist{
+		shortLinkRepo:  shortLinkRepo,
+		visitRepo:      visitRepo,
+		authorizer:     authorizer,
+		resourceFinder: resourceFinder,
+		timer:          timer,
+	}
+}
@@ -0,0 +1,136 @@
+// +build !integration all
+
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestAnalyticsPersist_GetAnalytics(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-05-01T08:02:16Z")
+	policies := []policy.Policy{
+		{
+			Roles:        []role.Role{role.ShortLinkViewer},
+			Permission:   permission.ViewShortLinkAnalytics,
+			ResourceType: policy.ShortLink,
+			Conditions:   []policy.Condition{policy.HasAttribute(policy.TagAttribute, "marketing")},
+		},
+	}
+	attributes := map[string]map[string][]string{
+		"promo": {policy.TagAttribute: {"marketing"}},
+	}
+
+	testCases := []struct {
+		name              string
+		user              entity.User
+		roles             map[string][]role.Role
+		alias             string
+		visits            int
+		expectedErr       error
+		expectedAnalytics entity.ShortLinkAnalytics
+	}{
+		{
+			name:        "short link not found",
+			user:        entity.User{ID: "alpha"},
+			alias:       "missing",
+			expectedErr: ErrShortLinkNotFound("missing"),
+		},
+		{
+			name:              "owner never visited",
+			user:              entity.User{ID: "alpha"},
+			alias:             "docs",
+			expectedAnalytics: entity.ShortLinkAnalytics{},
+		},
+		{
+			name:   "owner",
+			user:   entity.User{ID: "alpha"},
+			alias:  "docs",
+			visits: 2,
+			expectedAnalytics: entity.ShortLinkAnalytics{
+				VisitCount:    2,
+				LastVisitedAt: &now,
+			},
+		},
+		{
+			name:        "neither owner nor allowed by policy",
+			user:        entity.User{ID: "beta"},
+			roles:       map[string][]role.Role{"beta": {role.ShortLinkViewer}},
+			alias:       "docs",
+			visits:      2,
+			expectedErr: ErrShortLinkNotFound("docs"),
+		},
+		{
+			name:   "allowed by policy",
+			user:   entity.User{ID: "beta"},
+			roles:  map[string][]role.Role{"beta": {role.ShortLinkViewer}},
+			alias:  "promo",
+			visits: 1,
+			expectedAnalytics: entity.ShortLinkAnalytics{
+				VisitCount:    1,
+				LastVisitedAt: &now,
+			},
+		},
+		{
+			name:        "policy for another role",
+			user:        entity.User{ID: "beta"},
+			roles:       map[string][]role.Role{"beta": {role.ChangeLogViewer}},
+			alias:       "promo",
+			visits:      1,
+			expectedErr: ErrShortLinkNotFound("promo"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{
+				"docs":  {Alias: "docs"},
+				"promo": {Alias: "promo"},
+			})
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(
+				[]entity.User{{ID: "alpha"}, {ID: "alpha"}},
+				[]entity.ShortLink{{Alias: "docs"}, {Alias: "promo"}},
+			)
+			attributeRepo := repository.NewShortLinkAttributeFake(attributes)
+			visitRepo := repository.NewShortLinkVisitFake(nil)
+			au := authorizer.NewPolicyAuthorizer(
+				rbac.NewRBAC(repository.NewUserRoleFake(testCase.roles)),
+				policies,
+			)
+			analytics := NewAnalyticsPersist(
+				&shortLinkRepo,
+				&visitRepo,
+				au,
+				policy.NewResourceFinder(&userShortLinkRepo, &attributeRepo),
+				timer.NewStub(now),
+			)
+			for visit := 0; visit < testCase.visits; visit++ {
+				err := analytics.RecordVisit(testCase.alias)
+				assert.Equal(t, nil, err)
+			}
+
+			gotAnalytics, err := analytics.GetAnalytics(testCase.alias, testCase.user)
+			if testCase.expectedErr != nil {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedAnalytics, gotAnalytics)
+		})
+	}
+}
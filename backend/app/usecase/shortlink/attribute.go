@@ -0,0 +1,171 @@
+package shortlink
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// maxAttributeValueLength matches the size of the value column in
+// short_link_attribute table.
+const maxAttributeValueLength = 100
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+var _ Attribute = (*AttributePersist)(nil)
+
+// ErrUnauthorizedAction represents the failure of managing short links
+// without the required permission.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrInvalidAttribute represents an attribute whose name isn't 1 to 50
+// lowercase letters, digits and underscores, or whose value is empty or
+// longer than 100 characters.
+type ErrInvalidAttribute struct {
+	Name  string
+	Value string
+}
+
+var _ error = (*ErrInvalidAttribute)(nil)
+
+func (e ErrInvalidAttribute) Error() string {
+	return fmt.Sprintf("attribute(%s=%s) is invalid", e.Name, e.Value)
+}
+
+// ErrAttributeNotFound represents the failure of removing a value the
+// attribute of the short link doesn't have.
+type ErrAttributeNotFound struct {
+	Name  string
+	Value string
+}
+
+var _ error = (*ErrAttributeNotFound)(nil)
+
+func (e ErrAttributeNotFound) Error() string {
+	return fmt.Sprintf("attribute(%s=%s) not found", e.Name, e.Value)
+}
+
+// Attribute lets admins describe short links with attributes, such as the
+// team owning them or their tags, which policies grant permissions on.
+type Attribute interface {
+	GetAttributes(alias string, user entity.User) (map[string][]string, error)
+	AddAttribute(alias string, name string, value string, user entity.User) (map[string][]string, error)
+	RemoveAttribute(alias string, name string, value string, user entity.User) (map[string][]string, error)
+}
+
+// AttributePersist keeps the attributes of short links in the data store.
+type AttributePersist struct {
+	shortLinkRepo repository.ShortLink
+	attributeRepo repository.ShortLinkAttribute
+	authorizer    authorizer.Authorizer
+}
+
+// GetAttributes fetches every value of each attribute of the short link.
+func (a AttributePersist) GetAttributes(alias string, user entity.User) (map[string][]string, error) {
+	shortLink, err := a.getShortLink(alias, user, "view short link attributes")
+	if err != nil {
+		return nil, err
+	}
+	return a.attributeRepo.GetAttributes(shortLink.Alias)
+}
+
+// AddAttribute adds the value to the attribute of the short link and returns
+// the attributes afterwards.
+func (a AttributePersist) AddAttribute(
+	alias string,
+	name string,
+	value string,
+	user entity.User,
+) (map[string][]string, error) {
+	shortLink, err := a.getShortLink(alias, user, "add short link attributes")
+	if err != nil {
+		return nil, err
+	}
+	if !isAttributeValid(name, value) {
+		return nil, ErrInvalidAttribute{Name: name, Value: value}
+	}
+
+	err = a.attributeRepo.AddAttribute(shortLink.Alias, name, value)
+	if err != nil {
+		return nil, err
+	}
+	return a.attributeRepo.GetAttributes(shortLink.Alias)
+}
+
+// RemoveAttribute removes the value from the attribute of the short link and
+// returns the attributes afterwards.
+func (a AttributePersist) RemoveAttribute(
+	alias string,
+	name string,
+	value string,
+	user entity.User,
+) (map[string][]string, error) {
+	shortLink, err := a.getShortLink(alias, user, "remove short link attributes")
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.attributeRepo.RemoveAttribute(shortLink.Alias, name, value)
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return nil, ErrAttributeNotFound{Name: name, Value: value}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a.attributeRepo.GetAttributes(shortLink.Alias)
+}
+
+// getShortLink checks whether the user can manage attributes before finding
+// the short link, so that the existence of short links isn't revealed.
+func (a AttributePersist) getShortLink(alias string, user entity.User, action string) (entity.ShortLink, error) {
+	canManage, err := a.authorizer.CanManageShortLinkAttributes(user)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+	if !canManage {
+		return entity.ShortLink{}, ErrUnauthorizedAction{UserID: user.ID, Action: action}
+	}
+
+	shortLink, err := a.shortLinkRepo.GetShortLinkByAlias(alias)
+	var errNotFound repository.ErrAliasNotFound
+	if errors.As(err, &errNotFound) {
+		return entity.ShortLink{}, ErrShortLinkNotFound(alias)
+	}
+	return shortLink, err
+}
+
+func isAttributeValid(name string, value string) bool {
+	if !attributeNamePattern.MatchString(name) {
+		return false
+	}
+	length := utf8.RuneCountInString(value)
+	return length > 0 && length <= maxAttributeValueLength
+}
+
+// NewAttributePersist creates AttributePersist.
+func NewAttributePersist(
+	shortLinkRepo repository.ShortLink,
+	attributeRepo repository.ShortLinkAttribute,
+	authorizer authorizer.Authorizer,
+) AttributePersist {
+	return AttributePersist{
+		shortLinkRepo: shortLinkRepo,
+		attributeRepo: attributeRepo,
+		authorizer:    authorizer,
+	}
+}
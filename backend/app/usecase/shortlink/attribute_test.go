@@ -0,0 +1,194 @@
+// +build !integration all
+
+package shortlink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestAttributePersist_AddAttribute(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		roles              map[string][]role.Role
+		alias              string
+		attributeName      string
+		attributeValue     string
+		expectedErr        error
+		expectedAttributes map[string][]string
+	}{
+		{
+			name:           "not admin",
+			roles:          map[string][]role.Role{"alpha": {role.ShortLinkEditor}},
+			alias:          "docs",
+			attributeName:  "team",
+			attributeValue: "growth",
+			expectedErr: ErrUnauthorizedAction{
+				UserID: "alpha",
+				Action: "add short link attributes",
+			},
+		},
+		{
+			name:           "short link not found",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "missing",
+			attributeName:  "team",
+			attributeValue: "growth",
+			expectedErr:    ErrShortLinkNotFound("missing"),
+		},
+		{
+			name:           "invalid name",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "docs",
+			attributeName:  "Team Name",
+			attributeValue: "growth",
+			expectedErr:    ErrInvalidAttribute{Name: "Team Name", Value: "growth"},
+		},
+		{
+			name:           "empty value",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "docs",
+			attributeName:  "team",
+			attributeValue: "",
+			expectedErr:    ErrInvalidAttribute{Name: "team", Value: ""},
+		},
+		{
+			name:           "value too long",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "docs",
+			attributeName:  "team",
+			attributeValue: strings.Repeat("a", 101),
+			expectedErr:    ErrInvalidAttribute{Name: "team", Value: strings.Repeat("a", 101)},
+		},
+		{
+			name:           "attribute added",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "docs",
+			attributeName:  "tag",
+			attributeValue: "api",
+			expectedAttributes: map[string][]string{
+				"tag": {"api", "public"},
+			},
+		},
+		{
+			name:           "value already added",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			alias:          "docs",
+			attributeName:  "tag",
+			attributeValue: "public",
+			expectedAttributes: map[string][]string{
+				"tag": {"public"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{
+				"docs": {Alias: "docs"},
+			})
+			attributeRepo := repository.NewShortLinkAttributeFake(map[string]map[string][]string{
+				"docs": {"tag": {"public"}},
+			})
+			au := authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(testCase.roles)))
+			attribute := NewAttributePersist(&shortLinkRepo, &attributeRepo, au)
+
+			attributes, err := attribute.AddAttribute(
+				testCase.alias,
+				testCase.attributeName,
+				testCase.attributeValue,
+				entity.User{ID: "alpha"},
+			)
+			if testCase.expectedErr != nil {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedAttributes, attributes)
+		})
+	}
+}
+
+func TestAttributePersist_RemoveAttribute(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		roles              map[string][]role.Role
+		attributeName      string
+		attributeValue     string
+		expectedErr        error
+		expectedAttributes map[string][]string
+	}{
+		{
+			name:           "not admin",
+			roles:          map[string][]role.Role{},
+			attributeName:  "tag",
+			attributeValue: "public",
+			expectedErr: ErrUnauthorizedAction{
+				UserID: "alpha",
+				Action: "remove short link attributes",
+			},
+		},
+		{
+			name:           "attribute not found",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			attributeName:  "tag",
+			attributeValue: "private",
+			expectedErr:    ErrAttributeNotFound{Name: "tag", Value: "private"},
+		},
+		{
+			name:           "attribute removed",
+			roles:          map[string][]role.Role{"alpha": {role.Admin}},
+			attributeName:  "tag",
+			attributeValue: "public",
+			expectedAttributes: map[string][]string{
+				"team": {"growth"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{
+				"docs": {Alias: "docs"},
+			})
+			attributeRepo := repository.NewShortLinkAttributeFake(map[string]map[string][]string{
+				"docs": {
+					"tag":  {"public"},
+					"team": {"growth"},
+				},
+			})
+			au := authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(testCase.roles)))
+			attribute := NewAttributePersist(&shortLinkRepo, &attributeRepo, au)
+
+			attributes, err := attribute.RemoveAttribute(
+				"docs",
+				testCase.attributeName,
+				testCase.attributeValue,
+				entity.User{ID: "alpha"},
+			)
+			if testCase.expectedErr != nil {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedAttributes, attributes)
+		})
+	}
+}
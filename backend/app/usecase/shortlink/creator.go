@@ -1,9 +1,14 @@
 package shortlink
 
 import (
+	"errors"
+
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
 	"github.com/short-d/short/backend/app/usecase/validator"
@@ -11,6 +16,10 @@ import (
 
 var _ Creator = (*CreatorPersist)(nil)
 
+// maxAutoAliasAttempts limits the number of keys discarded because they match
+// the alias word list before giving up.
+const maxAutoAliasAttempts = 10
+
 // ErrAliasExist represents alias unavailable error
 type ErrAliasExist string
 
@@ -48,62 +57,152 @@ func (e ErrMaliciousLongLink) Error() string {
 // Creator represents a ShortLink alias creator
 type Creator interface {
 	CreateShortLink(shortLinkInput entity.ShortLinkInput, user entity.User, isPublic bool) (entity.ShortLink, error)
+	CreateAppShortLink(shortLinkInput entity.ShortLinkInput, app entity.App) (entity.ShortLink, error)
 }
 
 // CreatorPersist represents a ShortLink alias creator which persist the generated
 // alias in the repository
 type CreatorPersist struct {
 	shortLinkRepo     repository.ShortLink
-	userShortLinkRepo repository.UserShortLink
 	keyGen            keygen.KeyGenerator
 	longLinkValidator validator.LongLink
 	aliasValidator    validator.CustomAlias
+	aliasNormalizer   normalizer.Alias
+	linkNormalizer    normalizer.LongLink
 	timer             timer.Timer
 	riskDetector      risk.Detector
+	authorizer        authorizer.Authorizer
+	redirectResolver  RedirectResolver
+	metaTagQueue      MetaTagQueue
+	quota             quota.Quota
 }
 
 // CreateShortLink persists a new short link with a given or auto generated alias in the repository.
 // TODO(issue#235): add functionality for public URLs
 func (c CreatorPersist) CreateShortLink(shortLinkInput entity.ShortLinkInput, user entity.User, isPublic bool) (entity.ShortLink, error) {
-	if shortLinkInput.CustomAlias == nil || shortLinkInput.GetCustomAlias("") == "" {
+	err := c.quota.CheckCreateShortLink(user)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
+	shortLinkInput, err = c.prepareShortLinkInput(shortLinkInput, func(alias string) (bool, validator.Violation, error) {
+		return isAliasAllowed(c.aliasValidator, c.authorizer, alias, user)
+	})
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
+	quotaCheck, err := c.quota.NewUserQuotaCheck(user)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
+	shortLink, err := c.createShortLink(shortLinkInput, func(shortLinkInput entity.ShortLinkInput) error {
+		return c.shortLinkRepo.CreateUserShortLink(user, shortLinkInput, quotaCheck)
+	})
+	if err != nil {
+		return shortLink, err
+	}
+
+	c.metaTagQueue.Enqueue(shortLink.Alias, shortLink.LongLink)
+	return shortLink, nil
+}
+
+// CreateAppShortLink persists a new short link on behalf of a third party app.
+// Apps cannot use the restricted aliases.
+func (c CreatorPersist) CreateAppShortLink(shortLinkInput entity.ShortLinkInput, app entity.App) (entity.ShortLink, error) {
+	err := c.quota.CheckCreateAppShortLink(app)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
+	shortLinkInput, err = c.prepareShortLinkInput(shortLinkInput, func(alias string) (bool, validator.Violation, error) {
+		isValid, violation := c.aliasValidator.IsValid(alias)
+		return isValid, violation, nil
+	})
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
+	quotaCheck := c.quota.NewAppQuotaCheck(app)
+	shortLink, err := c.createShortLink(shortLinkInput, func(shortLinkInput entity.ShortLinkInput) error {
+		return c.shortLinkRepo.CreateAppShortLink(app, shortLinkInput, quotaCheck)
+	})
+	if err != nil {
+		return shortLink, err
+	}
+
+	c.metaTagQueue.Enqueue(shortLink.Alias, shortLink.LongLink)
+	return shortLink, nil
+}
+
+// prepareShortLinkInput normalizes and validates the alias and the long link
+// of a new short link.
+func (c CreatorPersist) prepareShortLinkInput(
+	shortLinkInput entity.ShortLinkInput,
+	isAliasAllowed func(alias string) (bool, validator.Violation, error),
+) (entity.ShortLinkInput, error) {
+	customAlias := c.aliasNormalizer.Normalize(shortLinkInput.GetCustomAlias(""))
+	if customAlias == "" {
 		autoAlias, err := c.generateAlias()
 		if err != nil {
 			// TODO(issue#950) create error type for fail create auto alias
-			return entity.ShortLink{}, err
+			return entity.ShortLinkInput{}, err
 		}
-		shortLinkInput.CustomAlias = &autoAlias
+		customAlias = autoAlias
 	}
+	shortLinkInput.CustomAlias = &customAlias
 
-	customAlias := shortLinkInput.GetCustomAlias("")
-	isValid, violation := c.aliasValidator.IsValid(customAlias)
+	isValid, violation, err := isAliasAllowed(customAlias)
+	if err != nil {
+		return entity.ShortLinkInput{}, err
+	}
 	if !isValid {
-		return entity.ShortLink{}, ErrInvalidCustomAlias{customAlias, violation}
+		return entity.ShortLinkInput{}, ErrInvalidCustomAlias{customAlias, violation}
 	}
 
-	longLink := shortLinkInput.GetLongLink("")
+	longLink := c.linkNormalizer.Normalize(shortLinkInput.GetLongLink(""))
 	isValid, violation = c.longLinkValidator.IsValid(longLink)
 	if !isValid {
-		return entity.ShortLink{}, ErrInvalidLongLink{longLink, violation}
+		return entity.ShortLinkInput{}, ErrInvalidLongLink{longLink, violation}
+	}
+
+	longLink, err = c.redirectResolver.FlattenLongLink(longLink, customAlias)
+	if err != nil {
+		return entity.ShortLinkInput{}, err
 	}
 
 	if c.riskDetector.IsURLMalicious(longLink) {
-		return entity.ShortLink{}, ErrMaliciousLongLink(longLink)
+		return entity.ShortLinkInput{}, ErrMaliciousLongLink(longLink)
 	}
 
 	shortLinkInput.LongLink = &longLink
-
-	return c.createShortLink(shortLinkInput, user)
+	return shortLinkInput, nil
 }
 
+// generateAlias fetches a key which does not match the alias word list.
 func (c CreatorPersist) generateAlias() (string, error) {
-	key, err := c.keyGen.NewKey()
-	if err != nil {
-		return "", err
+	for attempt := 0; attempt < maxAutoAliasAttempts; attempt++ {
+		key, err := c.keyGen.NewKey()
+		if err != nil {
+			return "", err
+		}
+
+		alias := string(key)
+		isValid, _ := c.aliasValidator.IsValid(alias)
+		if isValid {
+			return alias, nil
+		}
 	}
-	return string(key), nil
+	return "", errors.New("no valid key available for auto alias")
 }
 
-func (c CreatorPersist) createShortLink(shortLinkInput entity.ShortLinkInput, user entity.User) (entity.ShortLink, error) {
+// createShortLink persists the short link through persist after making sure
+// the alias is not taken.
+func (c CreatorPersist) createShortLink(
+	shortLinkInput entity.ShortLinkInput,
+	persist func(shortLinkInput entity.ShortLinkInput) error,
+) (entity.ShortLink, error) {
 	isExist, err := c.shortLinkRepo.IsAliasExist(shortLinkInput.GetCustomAlias(""))
 	if err != nil {
 		return entity.ShortLink{}, err
@@ -116,37 +215,46 @@ func (c CreatorPersist) createShortLink(shortLinkInput entity.ShortLinkInput, us
 	now := c.timer.Now().UTC()
 	shortLinkInput.CreatedAt = &now
 
-	err = c.shortLinkRepo.CreateShortLink(shortLinkInput)
+	err = persist(shortLinkInput)
 	if err != nil {
 		return entity.ShortLink{}, err
 	}
 
-	err = c.userShortLinkRepo.CreateRelation(user, shortLinkInput)
 	return entity.ShortLink{
 		LongLink:  shortLinkInput.GetLongLink(""),
 		Alias:     shortLinkInput.GetCustomAlias(""),
 		ExpireAt:  shortLinkInput.ExpireAt,
 		CreatedAt: shortLinkInput.CreatedAt,
-	}, err
+	}, nil
 }
 
 // NewCreatorPersist creates CreatorPersist
 func NewCreatorPersist(
 	shortLinkRepo repository.ShortLink,
-	userShortLinkRepo repository.UserShortLink,
 	keyGen keygen.KeyGenerator,
 	longLinkValidator validator.LongLink,
 	aliasValidator validator.CustomAlias,
+	aliasNormalizer normalizer.Alias,
+	linkNormalizer normalizer.LongLink,
 	timer timer.Timer,
 	riskDetector risk.Detector,
+	authorizer authorizer.Authorizer,
+	redirectResolver RedirectResolver,
+	metaTagQueue MetaTagQueue,
+	quota quota.Quota,
 ) CreatorPersist {
 	return CreatorPersist{
 		shortLinkRepo:     shortLinkRepo,
-		userShortLinkRepo: userShortLinkRepo,
 		keyGen:            keyGen,
 		longLinkValidator: longLinkValidator,
 		aliasValidator:    aliasValidator,
+		aliasNormalizer:   aliasNormalizer,
+		linkNormalizer:    linkNormalizer,
 		timer:             timer,
 		riskDetector:      riskDetector,
+		authorizer:        authorizer,
+		redirectResolver:  redirectResolver,
+		metaTagQueue:      metaTagQueue,
+		quota:             quota,
 	}
 }
@@ -10,7 +10,12 @@ import (
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
 	"github.com/short-d/short/backend/app/usecase/validator"
@@ -31,6 +36,9 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 		relationUsers      []entity.User
 		relationShortLinks []entity.ShortLink
 		blockedLongLinks   map[string]bool
+		aliasPolicy        normalizer.AliasPolicy
+		roles              map[string][]role.Role
+		quotaLimit         quota.Limit
 		isPublic           bool
 		// TODO(issue#803): Check error types in tests.
 		expHasErr         bool
@@ -85,6 +93,44 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 			},
 			expHasErr: true,
 		},
+		{
+			name: "alias exists with different case",
+			shortLinks: shortLinks{
+				"docs": entity.ShortLink{
+					Alias: "docs",
+				},
+			},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("Docs"),
+			},
+			aliasPolicy:      normalizer.AliasPolicy{FoldCase: true},
+			expHasErr:        true,
+			shouldAliasExist: true,
+		},
+		{
+			name:       "normalize custom alias",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String(" DoCs "),
+				LongLink:    ptr.String("https://www.google.com"),
+			},
+			aliasPolicy: normalizer.AliasPolicy{
+				FoldCase:  true,
+				TrimSpace: true,
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "docs",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
 		{
 			name:       "create alias successfully",
 			shortLinks: shortLinks{},
@@ -144,6 +190,71 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 				CreatedAt: &utc,
 			},
 		},
+		{
+			name:       "reject reserved alias",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				ID:    "1",
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("Login"),
+			},
+			expHasErr: true,
+		},
+		{
+			name:       "reject alias with blocked word",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				ID:    "1",
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("paypal-support"),
+			},
+			expHasErr: true,
+		},
+		{
+			name:       "admin creates reserved alias",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				ID:    "1",
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("login"),
+			},
+			roles: map[string][]role.Role{
+				"1": {role.Admin},
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "login",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
+		{
+			name:       "skip reserved auto generated key",
+			shortLinks: shortLinks{},
+			availableKeys: []keygen.Key{
+				"login",
+				"test",
+			},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink: ptr.String("https://www.google.com"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "test",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
 		{
 			name:          "no available key",
 			shortLinks:    shortLinks{},
@@ -171,6 +282,67 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 			isPublic:  false,
 			expHasErr: true,
 		},
+		{
+			name:       "normalize long link",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String("220uFicCJj"),
+				LongLink:    ptr.String("HTTPS://Bücher.example/Katalog"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "220uFicCJj",
+				LongLink:  "https://xn--bcher-kva.example/Katalog",
+				CreatedAt: &utc,
+			},
+		},
+		{
+			name:       "reject long link pointing to private network",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String("220uFicCJj"),
+				LongLink:    ptr.String("http://192.168.0.1/admin"),
+			},
+			expHasErr: true,
+		},
+		{
+			name:       "reject long link pointing to itself",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String("220uFicCJj"),
+				LongLink:    ptr.String("https://short-d.com/r/220uFicCJj"),
+			},
+			expHasErr: true,
+		},
+		{
+			name: "flatten long link pointing to another short link",
+			shortLinks: shortLinks{
+				"docs": entity.ShortLink{
+					Alias:    "docs",
+					LongLink: "https://www.google.com",
+				},
+			},
+			user: entity.User{
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String("220uFicCJj"),
+				LongLink:    ptr.String("https://short-d.com/r/docs"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "220uFicCJj",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
 		{
 			name:       "reject malicious long link",
 			shortLinks: shortLinks{},
@@ -188,6 +360,30 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 			isPublic:  false,
 			expHasErr: true,
 		},
+		{
+			name:       "daily quota exceeded",
+			shortLinks: shortLinks{},
+			user: entity.User{
+				ID:    "alpha",
+				Email: "alpha@example.com",
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				CustomAlias: ptr.String("220uFicCJj"),
+				LongLink:    ptr.String("https://www.google.com"),
+			},
+			relationUsers: []entity.User{
+				{ID: "alpha"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "existing",
+					LongLink:  "https://www.google.com",
+					CreatedAt: &utc,
+				},
+			},
+			quotaLimit: quota.Limit{Daily: 1},
+			expHasErr:  true,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -196,27 +392,47 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 			t.Parallel()
 
 			blacklist := risk.NewBlackListFake(testCase.blockedLongLinks)
-			shortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
 			userShortLinkRepo := repository.NewUserShortLinkRepoFake(
 				testCase.relationUsers,
 				testCase.relationShortLinks,
 			)
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			shortLinkRepo := repository.NewShortLinkFake(&userShortLinkRepo, &appShortLinkRepo, testCase.shortLinks)
 			keyFetcher := keygen.NewKeyFetcherFake(testCase.availableKeys)
 			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
 			assert.Equal(t, nil, err)
-			longLinkValidator := validator.NewLongLink()
-			aliasValidator := validator.NewCustomAlias()
+			longLinkValidator := validator.NewLongLink(validator.LongLinkPolicy{})
+			aliasNormalizer := normalizer.NewAlias(testCase.aliasPolicy)
+			wordList := validator.NewAliasWordList([]string{"login"}, []string{"paypal"})
+			aliasValidator := validator.NewCustomAlias(aliasNormalizer, wordList)
 			tm := timer.NewStub(now)
 			riskDetector := risk.NewDetector(blacklist)
+			userRoleRepo := repository.NewUserRoleFake(testCase.roles)
+			au := authorizer.NewAuthorizer(rbac.NewRBAC(userRoleRepo))
+			q := quota.NewQuota(
+				&userShortLinkRepo,
+				&appShortLinkRepo,
+				rbac.NewRBAC(userRoleRepo),
+				tm,
+				quota.Policy{Default: testCase.quotaLimit},
+			)
+			redirectPolicy := RedirectPolicy{ShortLinkDomains: []string{"short-d.com"}}
+			redirectResolver := NewRedirectResolver(redirectPolicy, &shortLinkRepo, aliasNormalizer)
+			metaTagQueue := NewMetaTagQueueFake()
 
 			creator := NewCreatorPersist(
 				&shortLinkRepo,
-				&userShortLinkRepo,
 				keyGen,
 				longLinkValidator,
 				aliasValidator,
+				aliasNormalizer,
+				normalizer.NewLongLink(),
 				tm,
 				riskDetector,
+				au,
+				redirectResolver,
+				metaTagQueue,
+				q,
 			)
 
 			if !testCase.shouldAliasExist {
@@ -240,6 +456,7 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 				isExist, err := userShortLinkRepo.HasMapping(testCase.user, testCase.expectedShortLink.Alias)
 				assert.Equal(t, nil, err)
 				assert.Equal(t, false, isExist)
+				assert.Equal(t, map[string]string{}, metaTagQueue.Enqueued())
 				return
 			}
 			assert.Equal(t, nil, err)
@@ -252,6 +469,156 @@ func TestShortLinkCreatorPersist_CreateShortLink(t *testing.T) {
 			isExist, err := userShortLinkRepo.HasMapping(testCase.user, testCase.expectedShortLink.Alias)
 			assert.Equal(t, nil, err)
 			assert.Equal(t, true, isExist)
+
+			expectedEnqueued := map[string]string{
+				testCase.expectedShortLink.Alias: testCase.expectedShortLink.LongLink,
+			}
+			assert.Equal(t, expectedEnqueued, metaTagQueue.Enqueued())
+		})
+	}
+}
+
+func TestShortLinkCreatorPersist_CreateAppShortLink(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	utc := now.UTC()
+	app := entity.App{ID: "emotic"}
+
+	testCases := []struct {
+		name              string
+		shortLinks        shortLinks
+		appAliases        []string
+		availableKeys     []keygen.Key
+		quotaLimit        quota.Limit
+		shortLinkArgs     entity.ShortLinkInput
+		expHasErr         bool
+		expectedShortLink entity.ShortLink
+	}{
+		{
+			name:       "create short link with custom alias",
+			shortLinks: shortLinks{},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("google"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "google",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
+		{
+			name:          "create short link with auto alias",
+			shortLinks:    shortLinks{},
+			availableKeys: []keygen.Key{"abc"},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink: ptr.String("https://www.google.com"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:     "abc",
+				LongLink:  "https://www.google.com",
+				CreatedAt: &utc,
+			},
+		},
+		{
+			name: "alias exists",
+			shortLinks: shortLinks{
+				"google": entity.ShortLink{Alias: "google"},
+			},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("google"),
+			},
+			expHasErr: true,
+		},
+		{
+			name:       "app quota exceeded",
+			shortLinks: shortLinks{},
+			appAliases: []string{"existing"},
+			quotaLimit: quota.Limit{Total: 1},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("google"),
+			},
+			expHasErr: true,
+		},
+		{
+			name:       "app cannot use restricted alias",
+			shortLinks: shortLinks{},
+			shortLinkArgs: entity.ShortLinkInput{
+				LongLink:    ptr.String("https://www.google.com"),
+				CustomAlias: ptr.String("login"),
+			},
+			expHasErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			blacklist := risk.NewBlackListFake(map[string]bool{})
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			apps := make([]entity.App, len(testCase.appAliases))
+			for idx := range apps {
+				apps[idx] = app
+			}
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(apps, testCase.appAliases)
+			shortLinkRepo := repository.NewShortLinkFake(&userShortLinkRepo, &appShortLinkRepo, testCase.shortLinks)
+			keyFetcher := keygen.NewKeyFetcherFake(testCase.availableKeys)
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+			aliasNormalizer := normalizer.NewAlias(normalizer.AliasPolicy{})
+			wordList := validator.NewAliasWordList([]string{"login"}, []string{"paypal"})
+			redirectResolver := NewRedirectResolver(RedirectPolicy{}, &shortLinkRepo, aliasNormalizer)
+			metaTagQueue := NewMetaTagQueueFake()
+
+			creator := NewCreatorPersist(
+				&shortLinkRepo,
+				keyGen,
+				validator.NewLongLink(validator.LongLinkPolicy{}),
+				validator.NewCustomAlias(aliasNormalizer, wordList),
+				aliasNormalizer,
+				normalizer.NewLongLink(),
+				timer.NewStub(now),
+				risk.NewDetector(blacklist),
+				authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(nil))),
+				redirectResolver,
+				metaTagQueue,
+				quota.NewQuota(
+					&userShortLinkRepo,
+					&appShortLinkRepo,
+					rbac.NewRBAC(repository.NewUserRoleFake(nil)),
+					timer.NewStub(now),
+					quota.Policy{App: testCase.quotaLimit},
+				),
+			)
+
+			shortLink, err := creator.CreateAppShortLink(testCase.shortLinkArgs, app)
+			if testCase.expHasErr {
+				assert.NotEqual(t, nil, err)
+
+				isExist, err := appShortLinkRepo.HasMapping(app, testCase.shortLinkArgs.GetCustomAlias(""))
+				assert.Equal(t, nil, err)
+				assert.Equal(t, false, isExist)
+				assert.Equal(t, map[string]string{}, metaTagQueue.Enqueued())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedShortLink, shortLink)
+
+			isExist, err := appShortLinkRepo.HasMapping(app, testCase.expectedShortLink.Alias)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, isExist)
+
+			aliases, err := userShortLinkRepo.FindAliasesByUser(entity.User{})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, 0, len(aliases))
+			assert.Equal(t, map[string]string{
+				testCase.expectedShortLink.Alias: testCase.expectedShortLink.LongLink,
+			}, metaTagQueue.Enqueued())
 		})
 	}
 }
@@ -0,0 +1,40 @@
+package shortlink
+
+import (
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ Deleter = (*DeleterPersist)(nil)
+
+// Deleter removes existing short links.
+type Deleter interface {
+	DeleteAppShortLink(alias string, app entity.App) error
+}
+
+// DeleterPersist removes short links from the data store.
+type DeleterPersist struct {
+	shortLinkRepo    repository.ShortLink
+	appShortLinkRepo repository.AppShortLink
+}
+
+// DeleteAppShortLink removes a short link created by the given third party
+// app from the repository.
+func (d DeleterPersist) DeleteAppShortLink(alias string, app entity.App) error {
+	hasMapping, err := d.appShortLinkRepo.HasMapping(app, alias)
+	if err != nil {
+		return err
+	}
+	if !hasMapping {
+		return ErrShortLinkNotFound(alias)
+	}
+	return d.shortLinkRepo.DeleteShortLink(alias)
+}
+
+// NewDeleterPersist creates DeleterPersist.
+func NewDeleterPersist(shortLinkRepo repository.ShortLink, appShortLinkRepo repository.AppShortLink) DeleterPersist {
+	return DeleterPersist{
+		shortLinkRepo:    shortLinkRepo,
+		appShortLinkRepo: appShortLinkRepo,
+	}
+}
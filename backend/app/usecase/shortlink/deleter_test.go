@@ -0,0 +1,71 @@
+// +build !integration all
+
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestDeleterPersist_DeleteAppShortLink(t *testing.T) {
+	t.Parallel()
+
+	app := entity.App{ID: "emotic"}
+	testCases := []struct {
+		name        string
+		shortLinks  shortLinks
+		apps        []entity.App
+		aliases     []string
+		alias       string
+		hasErr      bool
+		expectExist bool
+	}{
+		{
+			name: "delete short link created by the app",
+			shortLinks: shortLinks{
+				"google": entity.ShortLink{Alias: "google"},
+			},
+			apps:        []entity.App{app},
+			aliases:     []string{"google"},
+			alias:       "google",
+			hasErr:      false,
+			expectExist: false,
+		},
+		{
+			name: "short link created by another app",
+			shortLinks: shortLinks{
+				"google": entity.ShortLink{Alias: "google"},
+			},
+			apps:        []entity.App{{ID: "other"}},
+			aliases:     []string{"google"},
+			alias:       "google",
+			hasErr:      true,
+			expectExist: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(testCase.apps, testCase.aliases)
+			deleter := NewDeleterPersist(&shortLinkRepo, &appShortLinkRepo)
+
+			err := deleter.DeleteAppShortLink(testCase.alias, app)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+			} else {
+				assert.Equal(t, nil, err)
+			}
+
+			isExist, err := shortLinkRepo.IsAliasExist(testCase.alias)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectExist, isExist)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package shortlink
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/instrumentation"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+const (
+	defaultHealthCheckInterval = 24 * time.Hour
+	defaultHealthBatchSize     = 100
+	defaultHealthConcurrency   = 5
+)
+
+// HealthPolicy controls how often and how aggressively the destinations of
+// short links are checked.
+type HealthPolicy struct {
+	// CheckInterval is the minimum time between two checks of the same link.
+	CheckInterval time.Duration
+	// BatchSize is the maximum number of links checked in one run.
+	BatchSize int
+	// Concurrency is the maximum number of destinations requested at once.
+	Concurrency int
+	// HostInterval is the minimum time between two requests to the same host.
+	HostInterval time.Duration
+}
+
+// HealthReport summarizes a run of the health checker.
+type HealthReport struct {
+	Checked int
+	Broken  int
+}
+
+// HealthChecker checks whether the destinations of short links are still
+// reachable and records the results.
+type HealthChecker struct {
+	policy        HealthPolicy
+	shortLinkRepo repository.ShortLink
+	prober        LinkProber
+	timer         timer.Timer
+	limiter       *hostLimiter
+}
+
+type healthResult struct {
+	alias  string
+	health entity.LinkHealth
+}
+
+// CheckLinks probes the destinations of the short links which are due for a
+// check and records their health.
+func (h HealthChecker) CheckLinks(ins instrumentation.Instrumentation) (HealthReport, error) {
+	checkedBefore := h.timer.Now().Add(-h.policy.CheckInterval)
+	shortLinks, err := h.shortLinkRepo.GetShortLinksCheckedBefore(checkedBefore, h.policy.BatchSize)
+	if err != nil {
+		ins.LinkHealthCheckFailed(err)
+		return HealthReport{}, err
+	}
+
+	report := HealthReport{}
+	var updateErr error
+	for result := range h.probeAll(shortLinks) {
+		err = h.shortLinkRepo.UpdateHealth(result.alias, result.health)
+		if err != nil {
+			updateErr = err
+			continue
+		}
+
+		report.Checked++
+		if result.health.IsBroken() {
+			report.Broken++
+		}
+	}
+
+	ins.LinkHealthChecked(report.Checked, report.Broken)
+	if updateErr != nil {
+		ins.LinkHealthCheckFailed(updateErr)
+		return report, updateErr
+	}
+	return report, nil
+}
+
+func (h HealthChecker) probeAll(shortLinks []entity.ShortLink) <-chan healthResult {
+	jobs := make(chan entity.ShortLink)
+	results := make(chan healthResult)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < h.policy.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shortLink := range jobs {
+				h.limiter.wait(hostOf(shortLink.LongLink))
+				results <- healthResult{
+					alias:  shortLink.Alias,
+					health: h.probe(shortLink.LongLink),
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, shortLink := range shortLinks {
+			jobs <- shortLink
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (h HealthChecker) probe(longLink string) entity.LinkHealth {
+	statusCode, err := h.prober.Probe(longLink)
+	now := h.timer.Now()
+	if err != nil {
+		return entity.LinkHealth{CheckedAt: &now}
+	}
+	return entity.LinkHealth{StatusCode: &statusCode, CheckedAt: &now}
+}
+
+func hostOf(longLink string) string {
+	link, err := url.Parse(longLink)
+	if err != nil {
+		return longLink
+	}
+	return link.Host
+}
+
+// hostLimiter spaces out the requests sent to the same host. Waiting
+// requests are woken up by a ticker of the timer, so they may wait up to one
+// extra interval.
+type hostLimiter struct {
+	interval time.Duration
+	timer    timer.Timer
+	mutex    *sync.Mutex
+	nextAt   map[string]time.Time
+	// ticked is closed on every tick to wake up the waiting requests.
+	ticked chan entity.Empty
+}
+
+func (h *hostLimiter) wait(host string) {
+	if h.interval <= 0 {
+		return
+	}
+
+	for {
+		h.mutex.Lock()
+		now := h.timer.Now()
+		nextAt, ok := h.nextAt[host]
+		if !ok || !nextAt.After(now) {
+			h.nextAt[host] = now.Add(h.interval)
+			h.mutex.Unlock()
+			return
+		}
+		ticked := h.ticked
+		h.mutex.Unlock()
+
+		<-ticked
+	}
+}
+
+func (h *hostLimiter) tick() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := h.timer.Now()
+	for host, nextAt := range h.nextAt {
+		if !nextAt.After(now) {
+			delete(h.nextAt, host)
+		}
+	}
+	close(h.ticked)
+	h.ticked = make(chan entity.Empty)
+}
+
+func newHostLimiter(interval time.Duration, timer timer.Timer) *hostLimiter {
+	limiter := &hostLimiter{
+		interval: interval,
+		timer:    timer,
+		mutex:    &sync.Mutex{},
+		nextAt:   make(map[string]time.Time),
+		ticked:   make(chan entity.Empty),
+	}
+	if interval > 0 {
+		timer.Ticker(interval, limiter.tick)
+	}
+	return limiter
+}
+
+// NewHealthChecker creates HealthChecker.
+func NewHealthChecker(
+	policy HealthPolicy,
+	shortLinkRepo repository.ShortLink,
+	prober LinkProber,
+	timer timer.Timer,
+) HealthChecker {
+	if policy.CheckInterval <= 0 {
+		policy.CheckInterval = defaultHealthCheckInterval
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = defaultHealthBatchSize
+	}
+	if policy.Concurrency <= 0 {
+		policy.Concurrency = defaultHealthConcurrency
+	}
+	return HealthChecker{
+		policy:        policy,
+		shortLinkRepo: shortLinkRepo,
+		prober:        prober,
+		timer:         timer,
+		limiter:       newHostLimiter(policy.HostInterval, timer),
+	}
+}
@@ -0,0 +1,222 @@
+// +build !integration all
+
+package shortlink
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/analytics"
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/ctx"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/metrics"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/instrumentation"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestHealthChecker_CheckLinks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC)
+	recentCheck := now.Add(-time.Hour)
+	staleCheck := now.Add(-48 * time.Hour)
+
+	testCases := []struct {
+		name            string
+		shortLinks      map[string]entity.ShortLink
+		statusCodes     map[string]int
+		policy          HealthPolicy
+		expectedReport  HealthReport
+		expectedHealths map[string]entity.LinkHealth
+		expectedProbes  map[string]int
+	}{
+		{
+			name: "record status codes of reachable destinations",
+			shortLinks: map[string]entity.ShortLink{
+				"ok":   {Alias: "ok", LongLink: "https://a.com/ok"},
+				"gone": {Alias: "gone", LongLink: "https://b.com/gone"},
+			},
+			statusCodes: map[string]int{
+				"https://a.com/ok":   200,
+				"https://b.com/gone": 404,
+			},
+			policy:         HealthPolicy{},
+			expectedReport: HealthReport{Checked: 2, Broken: 1},
+			expectedHealths: map[string]entity.LinkHealth{
+				"ok":   {StatusCode: ptr.Int(200), CheckedAt: &now},
+				"gone": {StatusCode: ptr.Int(404), CheckedAt: &now},
+			},
+			expectedProbes: map[string]int{
+				"https://a.com/ok":   1,
+				"https://b.com/gone": 1,
+			},
+		},
+		{
+			name: "unreachable destination is broken",
+			shortLinks: map[string]entity.ShortLink{
+				"down": {Alias: "down", LongLink: "https://down.com"},
+			},
+			statusCodes:    map[string]int{},
+			policy:         HealthPolicy{},
+			expectedReport: HealthReport{Checked: 1, Broken: 1},
+			expectedHealths: map[string]entity.LinkHealth{
+				"down": {CheckedAt: &now},
+			},
+			expectedProbes: map[string]int{
+				"https://down.com": 1,
+			},
+		},
+		{
+			name: "skip recently checked links",
+			shortLinks: map[string]entity.ShortLink{
+				"recent": {
+					Alias:    "recent",
+					LongLink: "https://a.com/recent",
+					Health:   entity.LinkHealth{StatusCode: ptr.Int(200), CheckedAt: &recentCheck},
+				},
+				"stale": {
+					Alias:    "stale",
+					LongLink: "https://a.com/stale",
+					Health:   entity.LinkHealth{StatusCode: ptr.Int(200), CheckedAt: &staleCheck},
+				},
+			},
+			statusCodes: map[string]int{
+				"https://a.com/recent": 200,
+				"https://a.com/stale":  500,
+			},
+			policy:         HealthPolicy{CheckInterval: 24 * time.Hour},
+			expectedReport: HealthReport{Checked: 1, Broken: 1},
+			expectedHealths: map[string]entity.LinkHealth{
+				"recent": {StatusCode: ptr.Int(200), CheckedAt: &recentCheck},
+				"stale":  {StatusCode: ptr.Int(500), CheckedAt: &now},
+			},
+			expectedProbes: map[string]int{
+				"https://a.com/recent": 0,
+				"https://a.com/stale":  1,
+			},
+		},
+		{
+			name: "check at most batch size links",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com"},
+				"b": {Alias: "b", LongLink: "https://b.com"},
+			},
+			statusCodes: map[string]int{
+				"https://a.com": 200,
+				"https://b.com": 200,
+			},
+			policy:         HealthPolicy{BatchSize: 1},
+			expectedReport: HealthReport{Checked: 1, Broken: 0},
+			expectedHealths: map[string]entity.LinkHealth{
+				"a": {StatusCode: ptr.Int(200), CheckedAt: &now},
+				"b": {},
+			},
+			expectedProbes: map[string]int{
+				"https://a.com": 1,
+				"https://b.com": 0,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			prober := NewLinkProberFake(testCase.statusCodes)
+			tm := timer.NewStub(now)
+
+			checker := NewHealthChecker(testCase.policy, &shortLinkRepo, prober, tm)
+			report, err := checker.CheckLinks(newTestInstrumentation(t, tm))
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedReport, report)
+
+			for alias, expectedHealth := range testCase.expectedHealths {
+				shortLink, err := shortLinkRepo.GetShortLinkByAlias(alias)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, expectedHealth, shortLink.Health)
+			}
+			for longLink, expectedCount := range testCase.expectedProbes {
+				assert.Equal(t, expectedCount, prober.ProbeCount(longLink))
+			}
+		})
+	}
+}
+
+func TestHostLimiter_Wait(t *testing.T) {
+	t.Parallel()
+
+	interval := 20 * time.Second
+	tm := newTimerFake(time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC))
+	limiter := newHostLimiter(interval, tm)
+
+	limiter.wait("a.com")
+	limiter.wait("b.com")
+
+	done := make(chan entity.Empty)
+	go func() {
+		limiter.wait("a.com")
+		close(done)
+	}()
+
+	tm.advance(interval / 2)
+	select {
+	case <-done:
+		t.Fatal("expected request to wait for the interval")
+	default:
+	}
+
+	tm.advance(interval / 2)
+	<-done
+}
+
+// timerFake is a timer which only moves forward and ticks when advanced.
+type timerFake struct {
+	mutex     *sync.Mutex
+	now       time.Time
+	operation func()
+}
+
+func (f *timerFake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+func (f *timerFake) Ticker(interval time.Duration, operation func()) chan bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.operation = operation
+	return make(chan bool)
+}
+
+func (f *timerFake) advance(duration time.Duration) {
+	f.mutex.Lock()
+	f.now = f.now.Add(duration)
+	operation := f.operation
+	f.mutex.Unlock()
+
+	operation()
+}
+
+func newTimerFake(now time.Time) *timerFake {
+	return &timerFake{mutex: &sync.Mutex{}, now: now}
+}
+
+func newTestInstrumentation(t *testing.T, tm timer.Timer) instrumentation.Instrumentation {
+	entryRepo := logger.NewEntryRepoFake()
+	lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+	assert.Equal(t, nil, err)
+
+	ctxCh := make(chan ctx.ExecutionContext)
+	go func() {
+		ctxCh <- ctx.ExecutionContext{}
+	}()
+	return instrumentation.NewInstrumentation(lg, tm, metrics.NewFake(), analytics.NewFake(), ctxCh)
+}
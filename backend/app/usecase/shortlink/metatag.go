@@ -1,27 +1,54 @@
 package shortlink
 
 import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/entity/metatag"
 	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/validator"
 )
 
 var _ MetaTag = (*MetaTagPersist)(nil)
 
+// ErrInvalidMetaTag represents a meta tag which cannot be stored.
+type ErrInvalidMetaTag struct {
+	Tag       string
+	Violation validator.Violation
+}
+
+func (e ErrInvalidMetaTag) Error() string {
+	return fmt.Sprintf("meta tag %s is invalid: %s", e.Tag, e.Violation)
+}
+
 // MetaTag fetches and updates MetaTags for a short link.
 type MetaTag interface {
 	GetOpenGraphTags(alias string) (metatag.OpenGraph, error)
 	GetTwitterTags(alias string) (metatag.Twitter, error)
+	UpdateMetaTags(
+		alias string,
+		openGraphTags *metatag.OpenGraph,
+		twitterTags *metatag.Twitter,
+		user entity.User,
+	) (entity.ShortLink, error)
 }
 
 // MetaTagPersist fetches and updates MetaTags for a short link from persistent storage.
 type MetaTagPersist struct {
-	shortLinkRepo repository.ShortLink
+	shortLinkRepo     repository.ShortLink
+	userShortLinkRepo repository.UserShortLink
+	imageURLValidator validator.ImageURL
 }
 
 const (
 	defaultTitle    = "Short: Free link shortening service"
 	defaultDesc     = "Short enables people to type less for their favorite web sites"
 	defaultImageURL = "https://short-d.com/promo/small-tile.png"
+
+	// maxMetaTagLength matches the size of the meta tag columns in short_link
+	// table.
+	maxMetaTagLength = 200
 )
 
 // GetOpenGraphTags retrieves Open Graph tags for a short link from persistent storage given alias.
@@ -74,7 +101,78 @@ func (m MetaTagPersist) GetTwitterTags(alias string) (metatag.Twitter, error) {
 	return shortLink.TwitterTags, nil
 }
 
+// UpdateMetaTags replaces the Open Graph and Twitter tags of a short link owned
+// by the user. Tags passed as nil are left unchanged, while nil fields clear
+// the corresponding tag so that the default is served.
+func (m MetaTagPersist) UpdateMetaTags(
+	alias string,
+	openGraphTags *metatag.OpenGraph,
+	twitterTags *metatag.Twitter,
+	user entity.User,
+) (entity.ShortLink, error) {
+	hasMapping, err := m.userShortLinkRepo.HasMapping(user, alias)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+	if !hasMapping {
+		return entity.ShortLink{}, ErrShortLinkNotFound(alias)
+	}
+
+	if openGraphTags != nil {
+		err = m.validateTags("og", openGraphTags.Title, openGraphTags.Description, openGraphTags.ImageURL)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
+	}
+	if twitterTags != nil {
+		err = m.validateTags("twitter", twitterTags.Title, twitterTags.Description, twitterTags.ImageURL)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
+	}
+
+	if openGraphTags != nil {
+		_, err = m.shortLinkRepo.UpdateOpenGraphTags(alias, *openGraphTags)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
+	}
+	if twitterTags != nil {
+		_, err = m.shortLinkRepo.UpdateTwitterTags(alias, *twitterTags)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
+	}
+	return m.shortLinkRepo.GetShortLinkByAlias(alias)
+}
+
+func (m MetaTagPersist) validateTags(prefix string, title *string, description *string, imageURL *string) error {
+	if title != nil && utf8.RuneCountInString(*title) > maxMetaTagLength {
+		return ErrInvalidMetaTag{Tag: prefix + ":title", Violation: validator.MetaTagTooLong}
+	}
+	if description != nil && utf8.RuneCountInString(*description) > maxMetaTagLength {
+		return ErrInvalidMetaTag{Tag: prefix + ":description", Violation: validator.MetaTagTooLong}
+	}
+	if imageURL == nil {
+		return nil
+	}
+
+	isValid, violation := m.imageURLValidator.IsValid(*imageURL)
+	if !isValid {
+		return ErrInvalidMetaTag{Tag: prefix + ":image", Violation: violation}
+	}
+	return nil
+}
+
 // NewMetaTagPersist creates NewMetaTagPersist given repository.
-func NewMetaTagPersist(shortLinkRepo repository.ShortLink) MetaTagPersist {
-	return MetaTagPersist{shortLinkRepo: shortLinkRepo}
+func NewMetaTagPersist(
+	shortLinkRepo repository.ShortLink,
+	userShortLinkRepo repository.UserShortLink,
+	imageURLValidator validator.ImageURL,
+) MetaTagPersist {
+	return MetaTagPersist{
+		shortLinkRepo:     shortLinkRepo,
+		userShortLinkRepo: userShortLinkRepo,
+		imageURLValidator: imageURLValidator,
+	}
 }
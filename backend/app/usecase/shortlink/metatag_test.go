@@ -3,6 +3,8 @@
 package shortlink
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/short-d/app/fw/assert"
@@ -10,6 +12,7 @@ import (
 	"github.com/short-d/short/backend/app/entity/metatag"
 	"github.com/short-d/short/backend/app/fw/ptr"
 	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/validator"
 )
 
 func TestMetaTagPersist_GetOpenGraphTags(t *testing.T) {
@@ -110,8 +113,9 @@ func TestMetaTagPersist_GetOpenGraphTags(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			shortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
-			metaTag := NewMetaTagPersist(&shortLinkRepo)
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			metaTag := NewMetaTagPersist(&shortLinkRepo, &userShortLinkRepo, validator.NewImageURL())
 
 			ogTags, err := metaTag.GetOpenGraphTags(testCase.alias)
 			if testCase.expHasErr {
@@ -222,8 +226,9 @@ func TestMetaTagPersist_GetTwitterTags(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			shortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
-			metaTag := NewMetaTagPersist(&shortLinkRepo)
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			metaTag := NewMetaTagPersist(&shortLinkRepo, &userShortLinkRepo, validator.NewImageURL())
 
 			twitterTags, err := metaTag.GetTwitterTags(testCase.alias)
 			if testCase.expHasErr {
@@ -236,3 +241,133 @@ func TestMetaTagPersist_GetTwitterTags(t *testing.T) {
 		})
 	}
 }
+
+func TestMetaTagPersist_UpdateMetaTags(t *testing.T) {
+	t.Parallel()
+
+	owner := entity.User{ID: "owner", Email: "owner@short-d.com"}
+	stranger := entity.User{ID: "stranger", Email: "stranger@short-d.com"}
+	existingTags := metatag.OpenGraph{
+		Title:       ptr.String("Old Title"),
+		Description: ptr.String("Old Description"),
+		ImageURL:    ptr.String("https://short-d.com/old.png"),
+	}
+
+	testCases := []struct {
+		name             string
+		user             entity.User
+		openGraphTags    *metatag.OpenGraph
+		twitterTags      *metatag.Twitter
+		hasErr           bool
+		expViolation     validator.Violation
+		expOpenGraphTags metatag.OpenGraph
+		expTwitterTags   metatag.Twitter
+	}{
+		{
+			name: "update both tags",
+			user: owner,
+			openGraphTags: &metatag.OpenGraph{
+				Title:    ptr.String("New Title"),
+				ImageURL: ptr.String("https://short-d.com/new.png"),
+			},
+			twitterTags: &metatag.Twitter{
+				Description: ptr.String("New Description"),
+			},
+			hasErr: false,
+			expOpenGraphTags: metatag.OpenGraph{
+				Title:    ptr.String("New Title"),
+				ImageURL: ptr.String("https://short-d.com/new.png"),
+			},
+			expTwitterTags: metatag.Twitter{
+				Description: ptr.String("New Description"),
+			},
+		},
+		{
+			name: "leave omitted tags unchanged",
+			user: owner,
+			twitterTags: &metatag.Twitter{
+				Title: ptr.String("Twitter Title"),
+			},
+			hasErr:           false,
+			expOpenGraphTags: existingTags,
+			expTwitterTags: metatag.Twitter{
+				Title: ptr.String("Twitter Title"),
+			},
+		},
+		{
+			name: "short link not owned by user",
+			user: stranger,
+			openGraphTags: &metatag.OpenGraph{
+				Title: ptr.String("New Title"),
+			},
+			hasErr:           true,
+			expOpenGraphTags: existingTags,
+		},
+		{
+			name: "title too long",
+			user: owner,
+			openGraphTags: &metatag.OpenGraph{
+				Title: ptr.String(strings.Repeat("a", 201)),
+			},
+			hasErr:           true,
+			expViolation:     validator.MetaTagTooLong,
+			expOpenGraphTags: existingTags,
+		},
+		{
+			name: "image URL on private host",
+			user: owner,
+			openGraphTags: &metatag.OpenGraph{
+				Title: ptr.String("New Title"),
+			},
+			twitterTags: &metatag.Twitter{
+				ImageURL: ptr.String("http://192.168.0.1/tile.png"),
+			},
+			hasErr:           true,
+			expViolation:     validator.ImageURLInvalid,
+			expOpenGraphTags: existingTags,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLink := entity.ShortLink{
+				Alias:         "short",
+				LongLink:      "https://short-d.com",
+				OpenGraphTags: existingTags,
+			}
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, shortLinks{"short": shortLink})
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(
+				[]entity.User{owner},
+				[]entity.ShortLink{shortLink},
+			)
+			metaTag := NewMetaTagPersist(&shortLinkRepo, &userShortLinkRepo, validator.NewImageURL())
+
+			updatedShortLink, err := metaTag.UpdateMetaTags(
+				"short",
+				testCase.openGraphTags,
+				testCase.twitterTags,
+				testCase.user,
+			)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				if testCase.expViolation != "" {
+					var invalidErr ErrInvalidMetaTag
+					assert.Equal(t, true, errors.As(err, &invalidErr))
+					assert.Equal(t, testCase.expViolation, invalidErr.Violation)
+				}
+			} else {
+				assert.Equal(t, nil, err)
+				assert.Equal(t, testCase.expOpenGraphTags, updatedShortLink.OpenGraphTags)
+				assert.Equal(t, testCase.expTwitterTags, updatedShortLink.TwitterTags)
+			}
+
+			savedShortLink, err := shortLinkRepo.GetShortLinkByAlias("short")
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expOpenGraphTags, savedShortLink.OpenGraphTags)
+			assert.Equal(t, testCase.expTwitterTags, savedShortLink.TwitterTags)
+		})
+	}
+}
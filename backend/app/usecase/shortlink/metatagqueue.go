@@ -0,0 +1,159 @@
+package shortlink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var _ MetaTagQueue = (*MetaTagScrapeQueue)(nil)
+
+const (
+	defaultScrapeWorkers     = 2
+	defaultScrapeQueueSize   = 1000
+	defaultScrapeMaxAttempts = 3
+	defaultScrapeRetryDelay  = time.Minute
+)
+
+// MetaTagQueue schedules refreshing the meta tags of a short link from the
+// page its long link points to. Meta tags customized by the user are kept.
+type MetaTagQueue interface {
+	Enqueue(alias string, longLink string)
+}
+
+// ScrapePolicy controls how meta tags are scraped in the background.
+type ScrapePolicy struct {
+	// Workers is the number of pages scraped at once.
+	Workers int
+	// QueueSize is the maximum number of pending scrapes. New scrapes are
+	// dropped when the queue is full.
+	QueueSize int
+	// MaxAttempts is the maximum number of times a page is scraped before
+	// giving up.
+	MaxAttempts int
+	// RetryDelay is the delay before the first retry. It doubles for each
+	// subsequent retry.
+	RetryDelay time.Duration
+}
+
+type scrapeTask struct {
+	alias    string
+	longLink string
+	attempt  int
+}
+
+// MetaTagScrapeQueue scrapes meta tags in the background and retries failed
+// scrapes with exponential backoff.
+type MetaTagScrapeQueue struct {
+	policy        ScrapePolicy
+	shortLinkRepo repository.ShortLink
+	scraper       MetaTagScraper
+	logger        logger.Logger
+	tasks         chan scrapeTask
+}
+
+// Enqueue schedules scraping the meta tags of the short link.
+func (m MetaTagScrapeQueue) Enqueue(alias string, longLink string) {
+	m.enqueue(scrapeTask{alias: alias, longLink: longLink, attempt: 1})
+}
+
+func (m MetaTagScrapeQueue) enqueue(task scrapeTask) {
+	select {
+	case m.tasks <- task:
+	default:
+		m.logger.Error(fmt.Errorf("meta tag queue is full, dropping alias(%s)", task.alias))
+	}
+}
+
+func (m MetaTagScrapeQueue) work() {
+	for task := range m.tasks {
+		err := m.process(task)
+		if err == nil {
+			continue
+		}
+
+		if task.attempt >= m.policy.MaxAttempts {
+			m.logger.Error(fmt.Errorf("fail to scrape meta tags for alias(%s): %v", task.alias, err))
+			continue
+		}
+
+		retry := task
+		retry.attempt++
+		delay := m.policy.RetryDelay << uint(task.attempt-1)
+		time.AfterFunc(delay, func() {
+			m.enqueue(retry)
+		})
+	}
+}
+
+func (m MetaTagScrapeQueue) process(task scrapeTask) error {
+	openGraphTags, twitterTags, err := m.scraper.Scrape(task.longLink)
+	if err != nil {
+		return err
+	}
+
+	shortLink, err := m.shortLinkRepo.GetShortLinkByAlias(task.alias)
+	if err != nil {
+		return err
+	}
+	// The short link is updated after being scheduled. The newer task takes
+	// care of it.
+	if shortLink.LongLink != task.longLink {
+		return nil
+	}
+	// Pages without meta tags, such as non-HTML documents, leave the existing
+	// tags untouched.
+	if isEmptyOpenGraph(openGraphTags) && isEmptyTwitter(twitterTags) {
+		return nil
+	}
+
+	return m.shortLinkRepo.UpdateScrapedMetaTags(task.alias, task.longLink, openGraphTags, twitterTags)
+}
+
+func isEmptyOpenGraph(tags metatag.OpenGraph) bool {
+	return isEmptyTag(tags.Title) && isEmptyTag(tags.Description) && isEmptyTag(tags.ImageURL)
+}
+
+func isEmptyTwitter(tags metatag.Twitter) bool {
+	return isEmptyTag(tags.Title) && isEmptyTag(tags.Description) && isEmptyTag(tags.ImageURL)
+}
+
+func isEmptyTag(tag *string) bool {
+	return tag == nil || *tag == ""
+}
+
+// NewMetaTagScrapeQueue creates MetaTagScrapeQueue and starts its workers.
+func NewMetaTagScrapeQueue(
+	policy ScrapePolicy,
+	shortLinkRepo repository.ShortLink,
+	scraper MetaTagScraper,
+	logger logger.Logger,
+) MetaTagScrapeQueue {
+	if policy.Workers <= 0 {
+		policy.Workers = defaultScrapeWorkers
+	}
+	if policy.QueueSize <= 0 {
+		policy.QueueSize = defaultScrapeQueueSize
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultScrapeMaxAttempts
+	}
+	if policy.RetryDelay <= 0 {
+		policy.RetryDelay = defaultScrapeRetryDelay
+	}
+
+	queue := MetaTagScrapeQueue{
+		policy:        policy,
+		shortLinkRepo: shortLinkRepo,
+		scraper:       scraper,
+		logger:        logger,
+		tasks:         make(chan scrapeTask, policy.QueueSize),
+	}
+	for i := 0; i < policy.Workers; i++ {
+		go queue.work()
+	}
+	return queue
+}
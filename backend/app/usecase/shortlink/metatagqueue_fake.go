@@ -0,0 +1,38 @@
+package shortlink
+
+import "sync"
+
+var _ MetaTagQueue = (*MetaTagQueueFake)(nil)
+
+// MetaTagQueueFake records the short links scheduled for scraping.
+type MetaTagQueueFake struct {
+	mutex    *sync.Mutex
+	enqueued map[string]string
+}
+
+// Enqueue records the long link scheduled for the short link.
+func (m MetaTagQueueFake) Enqueue(alias string, longLink string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.enqueued[alias] = longLink
+}
+
+// Enqueued returns the long links scheduled for scraping keyed by alias.
+func (m MetaTagQueueFake) Enqueued() map[string]string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	enqueued := make(map[string]string)
+	for alias, longLink := range m.enqueued {
+		enqueued[alias] = longLink
+	}
+	return enqueued
+}
+
+// NewMetaTagQueueFake creates MetaTagQueueFake.
+func NewMetaTagQueueFake() MetaTagQueueFake {
+	return MetaTagQueueFake{
+		mutex:    &sync.Mutex{},
+		enqueued: make(map[string]string),
+	}
+}
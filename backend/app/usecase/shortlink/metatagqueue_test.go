@@ -0,0 +1,217 @@
+// +build !integration all
+
+package shortlink
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/entity/metatag"
+	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestMetaTagScrapeQueue_Process(t *testing.T) {
+	t.Parallel()
+
+	page := ScrapedMetaTags{
+		OpenGraph: metatag.OpenGraph{
+			Title:       ptr.String("OG Title"),
+			Description: ptr.String("OG Description"),
+			ImageURL:    ptr.String("https://a.com/og.png"),
+		},
+		Twitter: metatag.Twitter{
+			Title: ptr.String("Twitter Title"),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		shortLinks    map[string]entity.ShortLink
+		pages         map[string]ScrapedMetaTags
+		isCustomized  bool
+		task          scrapeTask
+		hasErr        bool
+		expShortLinks map[string]entity.ShortLink
+	}{
+		{
+			name: "store scraped meta tags",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com"},
+			},
+			pages: map[string]ScrapedMetaTags{
+				"https://a.com": page,
+			},
+			task:   scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr: false,
+			expShortLinks: map[string]entity.ShortLink{
+				"a": {
+					Alias:         "a",
+					LongLink:      "https://a.com",
+					OpenGraphTags: page.OpenGraph,
+					TwitterTags:   page.Twitter,
+				},
+			},
+		},
+		{
+			name: "skip outdated long link",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://b.com"},
+			},
+			pages: map[string]ScrapedMetaTags{
+				"https://a.com": page,
+			},
+			task:   scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr: false,
+			expShortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://b.com"},
+			},
+		},
+		{
+			name: "keep existing meta tags when page has none",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com", OpenGraphTags: page.OpenGraph},
+			},
+			pages: map[string]ScrapedMetaTags{
+				"https://a.com": {},
+			},
+			task:   scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr: false,
+			expShortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com", OpenGraphTags: page.OpenGraph},
+			},
+		},
+		{
+			name: "keep meta tags customized by user",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {
+					Alias:         "a",
+					LongLink:      "https://a.com",
+					OpenGraphTags: metatag.OpenGraph{Title: ptr.String("Custom Title")},
+				},
+			},
+			pages: map[string]ScrapedMetaTags{
+				"https://a.com": page,
+			},
+			isCustomized: true,
+			task:         scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr:       false,
+			expShortLinks: map[string]entity.ShortLink{
+				"a": {
+					Alias:         "a",
+					LongLink:      "https://a.com",
+					OpenGraphTags: metatag.OpenGraph{Title: ptr.String("Custom Title")},
+				},
+			},
+		},
+		{
+			name: "page not available",
+			shortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com"},
+			},
+			pages:  map[string]ScrapedMetaTags{},
+			task:   scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr: true,
+			expShortLinks: map[string]entity.ShortLink{
+				"a": {Alias: "a", LongLink: "https://a.com"},
+			},
+		},
+		{
+			name:       "short link deleted",
+			shortLinks: map[string]entity.ShortLink{},
+			pages: map[string]ScrapedMetaTags{
+				"https://a.com": page,
+			},
+			task:          scrapeTask{alias: "a", longLink: "https://a.com", attempt: 1},
+			hasErr:        true,
+			expShortLinks: map[string]entity.ShortLink{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			if testCase.isCustomized {
+				for alias, shortLink := range testCase.shortLinks {
+					_, err := shortLinkRepo.UpdateOpenGraphTags(alias, shortLink.OpenGraphTags)
+					assert.Equal(t, nil, err)
+				}
+			}
+			scraper := NewMetaTagScraperFake(testCase.pages)
+			queue := MetaTagScrapeQueue{
+				shortLinkRepo: &shortLinkRepo,
+				scraper:       scraper,
+			}
+
+			err := queue.process(testCase.task)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+			} else {
+				assert.Equal(t, nil, err)
+			}
+
+			for alias, expShortLink := range testCase.expShortLinks {
+				shortLink, err := shortLinkRepo.GetShortLinkByAlias(alias)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, expShortLink, shortLink)
+			}
+		})
+	}
+}
+
+func TestMetaTagScrapeQueue_Retry(t *testing.T) {
+	t.Parallel()
+
+	shortLinkRepo := repository.NewShortLinkFake(nil, nil, map[string]entity.ShortLink{})
+	scraper := newCountingScraper()
+
+	entryRepo := logger.NewEntryRepoFake()
+	lg, err := logger.NewFake(logger.LogOff, &entryRepo)
+	assert.Equal(t, nil, err)
+
+	queue := NewMetaTagScrapeQueue(ScrapePolicy{
+		Workers:     1,
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+	}, &shortLinkRepo, scraper, lg)
+	queue.Enqueue("a", "https://a.com")
+
+	deadline := time.Now().Add(time.Second)
+	for scraper.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Wait long enough for an unexpected fourth attempt to show up.
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, scraper.count())
+}
+
+type countingScraper struct {
+	mutex    *sync.Mutex
+	attempts *int
+}
+
+func (c countingScraper) Scrape(longLink string) (metatag.OpenGraph, metatag.Twitter, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	*c.attempts++
+	return metatag.OpenGraph{}, metatag.Twitter{}, errors.New("page not available")
+}
+
+func (c countingScraper) count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return *c.attempts
+}
+
+func newCountingScraper() countingScraper {
+	attempts := 0
+	return countingScraper{mutex: &sync.Mutex{}, attempts: &attempts}
+}
@@ -0,0 +1,7 @@
+package shortlink
+
+// LinkProber requests the destination of a long link and reports the HTTP
+// status code it responds with.
+type LinkProber interface {
+	Probe(longLink string) (int, error)
+}
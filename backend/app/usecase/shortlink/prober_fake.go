@@ -0,0 +1,45 @@
+package shortlink
+
+import (
+	"errors"
+	"sync"
+)
+
+var _ LinkProber = (*LinkProberFake)(nil)
+
+// LinkProberFake responds with preset status codes for long links.
+type LinkProberFake struct {
+	statusCodes map[string]int
+	mutex       *sync.Mutex
+	probed      map[string]int
+}
+
+// Probe returns the preset status code of the long link, or an error when the
+// long link is unreachable.
+func (l LinkProberFake) Probe(longLink string) (int, error) {
+	l.mutex.Lock()
+	l.probed[longLink]++
+	l.mutex.Unlock()
+
+	statusCode, ok := l.statusCodes[longLink]
+	if !ok {
+		return 0, errors.New("destination unreachable")
+	}
+	return statusCode, nil
+}
+
+// ProbeCount returns the number of times the long link has been probed.
+func (l LinkProberFake) ProbeCount(longLink string) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.probed[longLink]
+}
+
+// NewLinkProberFake creates LinkProberFake with preset status codes.
+func NewLinkProberFake(statusCodes map[string]int) LinkProberFake {
+	return LinkProberFake{
+		statusCodes: statusCodes,
+		mutex:       &sync.Mutex{},
+		probed:      make(map[string]int),
+	}
+}
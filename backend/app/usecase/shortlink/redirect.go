@@ -0,0 +1,134 @@
+package shortlink
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+const defaultMaxChainDepth = 5
+
+// ErrRedirectLoop represents long link which redirects back to the short link
+// itself, either directly or through other short links.
+type ErrRedirectLoop string
+
+func (e ErrRedirectLoop) Error() string {
+	return string(e)
+}
+
+// ErrTooManyRedirects represents long link which goes through more short
+// links than allowed before reaching its destination.
+type ErrTooManyRedirects string
+
+func (e ErrTooManyRedirects) Error() string {
+	return string(e)
+}
+
+// RedirectPolicy configures how long links pointing to short links are
+// detected and followed.
+type RedirectPolicy struct {
+	ShortLinkDomains []string
+	MaxChainDepth    int
+}
+
+// RedirectResolver follows long links pointing to short links served by this
+// service, including the ones on custom domains.
+type RedirectResolver struct {
+	shortLinkRepo   repository.ShortLink
+	aliasNormalizer normalizer.Alias
+	domains         map[string]entity.Empty
+	maxChainDepth   int
+}
+
+// FlattenLongLink follows the redirect chain starting from the long link and
+// returns the first destination which is not a short link. It fails with
+// ErrRedirectLoop when the chain leads back to any of the given aliases or to
+// any short link visited before, and with ErrTooManyRedirects when it is
+// deeper than the max chain depth.
+func (r RedirectResolver) FlattenLongLink(longLink string, aliases ...string) (string, error) {
+	visited := make(map[string]entity.Empty)
+	for _, alias := range aliases {
+		visited[r.aliasNormalizer.Normalize(alias)] = entity.Empty{}
+	}
+
+	for depth := 0; ; depth++ {
+		nextAlias, ok := r.aliasFromLink(longLink)
+		if !ok {
+			return longLink, nil
+		}
+
+		nextAlias = r.aliasNormalizer.Normalize(nextAlias)
+		if _, ok = visited[nextAlias]; ok {
+			return "", ErrRedirectLoop(longLink)
+		}
+		if depth >= r.maxChainDepth {
+			return "", ErrTooManyRedirects(longLink)
+		}
+		visited[nextAlias] = entity.Empty{}
+
+		isExist, err := r.shortLinkRepo.IsAliasExist(nextAlias)
+		if err != nil {
+			return "", err
+		}
+		if !isExist {
+			return longLink, nil
+		}
+
+		shortLink, err := r.shortLinkRepo.GetShortLinkByAlias(nextAlias)
+		if err != nil {
+			return "", err
+		}
+		longLink = shortLink.LongLink
+	}
+}
+
+// aliasFromLink extracts the alias from links such as https://short-d.com/r/docs
+// or https://short-d.com/docs.
+func (r RedirectResolver) aliasFromLink(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if _, ok := r.domains[host]; !ok {
+		return "", false
+	}
+
+	path := strings.Trim(u.Path, "/")
+	path = strings.TrimPrefix(path, "r/")
+	if path == "" || strings.Contains(path, "/") {
+		return "", false
+	}
+	return path, true
+}
+
+// NewRedirectResolver creates RedirectResolver.
+func NewRedirectResolver(
+	policy RedirectPolicy,
+	shortLinkRepo repository.ShortLink,
+	aliasNormalizer normalizer.Alias,
+) RedirectResolver {
+	domains := make(map[string]entity.Empty)
+	for _, domain := range policy.ShortLinkDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" {
+			continue
+		}
+		domains[domain] = entity.Empty{}
+	}
+
+	maxChainDepth := policy.MaxChainDepth
+	if maxChainDepth <= 0 {
+		maxChainDepth = defaultMaxChainDepth
+	}
+	return RedirectResolver{
+		shortLinkRepo:   shortLinkRepo,
+		aliasNormalizer: aliasNormalizer,
+		domains:         domains,
+		maxChainDepth:   maxChainDepth,
+	}
+}
@@ -0,0 +1,175 @@
+//go:build !integration || all
+// +build !integration all
+
+package shortlink
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestRedirectResolver_FlattenLongLink(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		shortLinks      shortLinks
+		policy          RedirectPolicy
+		aliases         []string
+		longLink        string
+		expHasErr       bool
+		expIsLoopErr    bool
+		expIsTooManyErr bool
+		expLongLink     string
+	}{
+		{
+			name:       "external long link",
+			shortLinks: shortLinks{},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:     []string{"docs"},
+			longLink:    "https://www.google.com/r/docs",
+			expLongLink: "https://www.google.com/r/docs",
+		},
+		{
+			name:       "link to itself",
+			shortLinks: shortLinks{},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:      []string{"docs"},
+			longLink:     "https://short-d.com/r/docs",
+			expHasErr:    true,
+			expIsLoopErr: true,
+		},
+		{
+			name:       "link to itself through custom domain",
+			shortLinks: shortLinks{},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com", "go.example.com"},
+			},
+			aliases:      []string{"docs"},
+			longLink:     "https://GO.example.com/docs/",
+			expHasErr:    true,
+			expIsLoopErr: true,
+		},
+		{
+			name: "flatten chain",
+			shortLinks: shortLinks{
+				"a": entity.ShortLink{
+					Alias:    "a",
+					LongLink: "https://short-d.com/r/b",
+				},
+				"b": entity.ShortLink{
+					Alias:    "b",
+					LongLink: "https://www.google.com",
+				},
+			},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:     []string{"docs"},
+			longLink:    "https://short-d.com/r/a",
+			expLongLink: "https://www.google.com",
+		},
+		{
+			name: "chain back to itself",
+			shortLinks: shortLinks{
+				"a": entity.ShortLink{
+					Alias:    "a",
+					LongLink: "https://short-d.com/r/b",
+				},
+				"b": entity.ShortLink{
+					Alias:    "b",
+					LongLink: "https://short-d.com/r/docs",
+				},
+			},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:      []string{"docs"},
+			longLink:     "https://short-d.com/r/a",
+			expHasErr:    true,
+			expIsLoopErr: true,
+		},
+		{
+			name: "existing cycle",
+			shortLinks: shortLinks{
+				"a": entity.ShortLink{
+					Alias:    "a",
+					LongLink: "https://short-d.com/r/b",
+				},
+				"b": entity.ShortLink{
+					Alias:    "b",
+					LongLink: "https://short-d.com/r/a",
+				},
+			},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:      []string{"docs"},
+			longLink:     "https://short-d.com/r/a",
+			expHasErr:    true,
+			expIsLoopErr: true,
+		},
+		{
+			name: "chain too deep",
+			shortLinks: shortLinks{
+				"a": entity.ShortLink{
+					Alias:    "a",
+					LongLink: "https://short-d.com/r/b",
+				},
+				"b": entity.ShortLink{
+					Alias:    "b",
+					LongLink: "https://www.google.com",
+				},
+			},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+				MaxChainDepth:    1,
+			},
+			aliases:         []string{"docs"},
+			longLink:        "https://short-d.com/r/a",
+			expHasErr:       true,
+			expIsTooManyErr: true,
+		},
+		{
+			name:       "short link not found",
+			shortLinks: shortLinks{},
+			policy: RedirectPolicy{
+				ShortLinkDomains: []string{"short-d.com"},
+			},
+			aliases:     []string{"docs"},
+			longLink:    "https://short-d.com/r/a",
+			expLongLink: "https://short-d.com/r/a",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			aliasNormalizer := normalizer.NewAlias(normalizer.AliasPolicy{})
+			resolver := NewRedirectResolver(testCase.policy, &shortLinkRepo, aliasNormalizer)
+
+			longLink, err := resolver.FlattenLongLink(testCase.longLink, testCase.aliases...)
+			if testCase.expHasErr {
+				assert.NotEqual(t, nil, err)
+				_, isLoopErr := err.(ErrRedirectLoop)
+				assert.Equal(t, testCase.expIsLoopErr, isLoopErr)
+				_, isTooManyErr := err.(ErrTooManyRedirects)
+				assert.Equal(t, testCase.expIsTooManyErr, isTooManyErr)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expLongLink, longLink)
+		})
+	}
+}
@@ -14,6 +14,8 @@ var _ Retriever = (*RetrieverPersist)(nil)
 type Retriever interface {
 	GetShortLink(alias string, expiringAt *time.Time) (entity.ShortLink, error)
 	GetShortLinksByUser(user entity.User) ([]entity.ShortLink, error)
+	GetAppShortLink(alias string, app entity.App) (entity.ShortLink, error)
+	GetShortLinksByApp(app entity.App) ([]entity.ShortLink, error)
 }
 
 // RetrieverPersist represents ShortLink retriever that fetches ShortLink from persistent
@@ -21,6 +23,7 @@ type Retriever interface {
 type RetrieverPersist struct {
 	shortLinkRepo     repository.ShortLink
 	userShortLinkRepo repository.UserShortLink
+	appShortLinkRepo  repository.AppShortLink
 }
 
 // GetShortLink retrieves ShortLink from persistent storage given alias
@@ -67,10 +70,37 @@ func (r RetrieverPersist) GetShortLinksByUser(user entity.User) ([]entity.ShortL
 	return r.shortLinkRepo.GetShortLinksByAliases(aliases)
 }
 
+// GetAppShortLink retrieves a ShortLink created by the given app from persistent storage
+func (r RetrieverPersist) GetAppShortLink(alias string, app entity.App) (entity.ShortLink, error) {
+	hasMapping, err := r.appShortLinkRepo.HasMapping(app, alias)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+	if !hasMapping {
+		return entity.ShortLink{}, ErrShortLinkNotFound(alias)
+	}
+	return r.getShortLink(alias)
+}
+
+// GetShortLinksByApp retrieves ShortLinks created by given app from persistent storage
+func (r RetrieverPersist) GetShortLinksByApp(app entity.App) ([]entity.ShortLink, error) {
+	aliases, err := r.appShortLinkRepo.FindAliasesByApp(app)
+	if err != nil {
+		return []entity.ShortLink{}, err
+	}
+
+	return r.shortLinkRepo.GetShortLinksByAliases(aliases)
+}
+
 // NewRetrieverPersist creates persistent ShortLink retriever
-func NewRetrieverPersist(shortLinkRepo repository.ShortLink, userShortLinkRepo repository.UserShortLink) RetrieverPersist {
+func NewRetrieverPersist(
+	shortLinkRepo repository.ShortLink,
+	userShortLinkRepo repository.UserShortLink,
+	appShortLinkRepo repository.AppShortLink,
+) RetrieverPersist {
 	return RetrieverPersist{
 		shortLinkRepo:     shortLinkRepo,
 		userShortLinkRepo: userShortLinkRepo,
+		appShortLinkRepo:  appShortLinkRepo,
 	}
 }
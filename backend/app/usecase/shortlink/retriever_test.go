@@ -104,9 +104,10 @@ func TestRetriever_GetShortLink(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			fakeShortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
+			fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
 			fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake([]entity.User{}, []entity.ShortLink{})
-			retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo)
+			fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
 			shortLink, err := retriever.GetShortLink(testCase.alias, testCase.expiringAt)
 
 			if testCase.hasErr {
@@ -253,9 +254,10 @@ func TestRetrieverPersist_GetShortLinks(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			fakeShortLinkRepo := repository.NewShortLinkFake(nil, testCase.shortLinks)
+			fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
 			fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(testCase.users, testCase.createdShortLinks)
-			retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo)
+			fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
 
 			shortLinks, err := retriever.GetShortLinksByUser(testCase.user)
 			if testCase.hasErr {
@@ -268,3 +270,81 @@ func TestRetrieverPersist_GetShortLinks(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieverPersist_GetAppShortLink(t *testing.T) {
+	t.Parallel()
+
+	app := entity.App{ID: "emotic"}
+	testCases := []struct {
+		name              string
+		shortLinks        shortLinks
+		apps              []entity.App
+		aliases           []string
+		alias             string
+		hasErr            bool
+		expectedShortLink entity.ShortLink
+	}{
+		{
+			name: "short link created by the app",
+			shortLinks: shortLinks{
+				"google": entity.ShortLink{Alias: "google", LongLink: "https://www.google.com/"},
+			},
+			apps:              []entity.App{app},
+			aliases:           []string{"google"},
+			alias:             "google",
+			expectedShortLink: entity.ShortLink{Alias: "google", LongLink: "https://www.google.com/"},
+		},
+		{
+			name: "short link created by another app",
+			shortLinks: shortLinks{
+				"google": entity.ShortLink{Alias: "google", LongLink: "https://www.google.com/"},
+			},
+			apps:    []entity.App{{ID: "other"}},
+			aliases: []string{"google"},
+			alias:   "google",
+			hasErr:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortLinks)
+			fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(testCase.apps, testCase.aliases)
+			retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
+
+			shortLink, err := retriever.GetAppShortLink(testCase.alias, app)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedShortLink, shortLink)
+		})
+	}
+}
+
+func TestRetrieverPersist_GetShortLinksByApp(t *testing.T) {
+	t.Parallel()
+
+	app := entity.App{ID: "emotic"}
+	shortLinks := shortLinks{
+		"google":   entity.ShortLink{Alias: "google", LongLink: "https://www.google.com/"},
+		"facebook": entity.ShortLink{Alias: "facebook", LongLink: "https://www.facebook.com/"},
+	}
+
+	fakeShortLinkRepo := repository.NewShortLinkFake(nil, nil, shortLinks)
+	fakeUserShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+	fakeAppShortLinkRepo := repository.NewAppShortLinkRepoFake(
+		[]entity.App{app, {ID: "other"}},
+		[]string{"google", "facebook"},
+	)
+	retriever := NewRetrieverPersist(&fakeShortLinkRepo, &fakeUserShortLinkRepo, &fakeAppShortLinkRepo)
+
+	gotShortLinks, err := retriever.GetShortLinksByApp(app)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []entity.ShortLink{shortLinks["google"]}, gotShortLinks)
+}
@@ -0,0 +1,9 @@
+package shortlink
+
+import "github.com/short-d/short/backend/app/entity/metatag"
+
+// MetaTagScraper extracts the social meta tags from the page a long link
+// points to.
+type MetaTagScraper interface {
+	Scrape(longLink string) (metatag.OpenGraph, metatag.Twitter, error)
+}
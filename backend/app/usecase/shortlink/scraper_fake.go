@@ -0,0 +1,35 @@
+package shortlink
+
+import (
+	"errors"
+
+	"github.com/short-d/short/backend/app/entity/metatag"
+)
+
+var _ MetaTagScraper = (*MetaTagScraperFake)(nil)
+
+// ScrapedMetaTags represents the meta tags found on a page.
+type ScrapedMetaTags struct {
+	OpenGraph metatag.OpenGraph
+	Twitter   metatag.Twitter
+}
+
+// MetaTagScraperFake returns preset meta tags for long links.
+type MetaTagScraperFake struct {
+	pages map[string]ScrapedMetaTags
+}
+
+// Scrape returns the preset meta tags of the long link, or an error when the
+// page is not available.
+func (m MetaTagScraperFake) Scrape(longLink string) (metatag.OpenGraph, metatag.Twitter, error) {
+	page, ok := m.pages[longLink]
+	if !ok {
+		return metatag.OpenGraph{}, metatag.Twitter{}, errors.New("page not available")
+	}
+	return page.OpenGraph, page.Twitter, nil
+}
+
+// NewMetaTagScraperFake creates MetaTagScraperFake with preset pages.
+func NewMetaTagScraperFake(pages map[string]ScrapedMetaTags) MetaTagScraperFake {
+	return MetaTagScraperFake{pages: pages}
+}
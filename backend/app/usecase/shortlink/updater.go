@@ -3,6 +3,9 @@ package shortlink
 import (
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
 	"github.com/short-d/short/backend/app/usecase/validator"
@@ -27,25 +30,58 @@ func (e ErrEmptyAlias) Error() string {
 // Updater mutates existing short links.
 type Updater interface {
 	UpdateShortLink(oldAlias string, shortLinkInput entity.ShortLinkInput, user entity.User) (entity.ShortLink, error)
+	UpdateAppShortLink(oldAlias string, shortLinkInput entity.ShortLinkInput, app entity.App) (entity.ShortLink, error)
 }
 
 // UpdaterPersist persists the mutated short link in the data store.
 type UpdaterPersist struct {
 	shortLinkRepo     repository.ShortLink
 	userShortLinkRepo repository.UserShortLink
+	appShortLinkRepo  repository.AppShortLink
 	longLinkValidator validator.LongLink
 	aliasValidator    validator.CustomAlias
+	aliasNormalizer   normalizer.Alias
+	linkNormalizer    normalizer.LongLink
 	timer             timer.Timer
 	riskDetector      risk.Detector
+	authorizer        authorizer.Authorizer
+	resourceFinder    policy.ResourceFinder
+	redirectResolver  RedirectResolver
+	metaTagQueue      MetaTagQueue
 }
 
-// UpdateShortLink mutates a short link in the repository.
+// UpdateShortLink mutates a short link in the repository. Users can update
+// the short links they own, or the short links a policy allows them to edit.
 func (u UpdaterPersist) UpdateShortLink(
 	oldAlias string,
 	shortLinkInput entity.ShortLinkInput,
 	user entity.User,
 ) (entity.ShortLink, error) {
-	hasMapping, err := u.userShortLinkRepo.HasMapping(user, oldAlias)
+	resource, err := u.resourceFinder.FindShortLink(oldAlias)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+	canEdit, err := u.authorizer.CanEditShortLink(user, resource)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+	if !canEdit {
+		return entity.ShortLink{}, ErrShortLinkNotFound(oldAlias)
+	}
+
+	return u.updateShortLink(oldAlias, shortLinkInput, func(alias string) (bool, validator.Violation, error) {
+		return isAliasAllowed(u.aliasValidator, u.authorizer, alias, user)
+	})
+}
+
+// UpdateAppShortLink mutates a short link created by the given third party
+// app. Apps cannot use the restricted aliases.
+func (u UpdaterPersist) UpdateAppShortLink(
+	oldAlias string,
+	shortLinkInput entity.ShortLinkInput,
+	app entity.App,
+) (entity.ShortLink, error) {
+	hasMapping, err := u.appShortLinkRepo.HasMapping(app, oldAlias)
 	if err != nil {
 		return entity.ShortLink{}, err
 	}
@@ -53,13 +89,25 @@ func (u UpdaterPersist) UpdateShortLink(
 		return entity.ShortLink{}, ErrShortLinkNotFound(oldAlias)
 	}
 
-	newAlias := shortLinkInput.GetCustomAlias(oldAlias)
+	return u.updateShortLink(oldAlias, shortLinkInput, func(alias string) (bool, validator.Violation, error) {
+		isValid, violation := u.aliasValidator.IsValid(alias)
+		return isValid, violation, nil
+	})
+}
+
+func (u UpdaterPersist) updateShortLink(
+	oldAlias string,
+	shortLinkInput entity.ShortLinkInput,
+	isAliasAllowed func(alias string) (bool, validator.Violation, error),
+) (entity.ShortLink, error) {
+	newAlias := u.aliasNormalizer.Normalize(shortLinkInput.GetCustomAlias(oldAlias))
 	if newAlias == "" {
 		return entity.ShortLink{}, ErrEmptyAlias("alias is empty")
 	}
 
 	// Only check if it exists if user is changing the alias to something else
-	if newAlias != oldAlias {
+	isAliasChanged := !u.aliasNormalizer.IsSame(newAlias, oldAlias)
+	if isAliasChanged {
 		aliasExist, err := u.shortLinkRepo.IsAliasExist(newAlias)
 		if err != nil {
 			return entity.ShortLink{}, err
@@ -74,9 +122,16 @@ func (u UpdaterPersist) UpdateShortLink(
 		return entity.ShortLink{}, err
 	}
 
-	longLink := shortLinkInput.GetLongLink(shortLink.LongLink)
+	longLink := u.linkNormalizer.Normalize(shortLinkInput.GetLongLink(shortLink.LongLink))
 
-	isValid, violation := u.aliasValidator.IsValid(newAlias)
+	// Existing aliases are not restricted by the word list retroactively.
+	isValid, violation := u.aliasValidator.IsValidFormat(newAlias)
+	if isAliasChanged {
+		isValid, violation, err = isAliasAllowed(newAlias)
+		if err != nil {
+			return entity.ShortLink{}, err
+		}
+	}
 	if !isValid {
 		return entity.ShortLink{}, ErrInvalidCustomAlias{newAlias, violation}
 	}
@@ -86,35 +141,62 @@ func (u UpdaterPersist) UpdateShortLink(
 		return entity.ShortLink{}, ErrInvalidLongLink{longLink, violation}
 	}
 
+	longLink, err = u.redirectResolver.FlattenLongLink(longLink, oldAlias, newAlias)
+	if err != nil {
+		return entity.ShortLink{}, err
+	}
+
 	if u.riskDetector.IsURLMalicious(longLink) {
 		return entity.ShortLink{}, ErrMaliciousLongLink(longLink)
 	}
 
 	updateTime := u.timer.Now()
 
-	return u.shortLinkRepo.UpdateShortLink(oldAlias, entity.ShortLinkInput{
+	updatedShortLink, err := u.shortLinkRepo.UpdateShortLink(oldAlias, entity.ShortLinkInput{
 		CustomAlias: &newAlias,
 		LongLink:    &longLink,
-		ExpireAt:    shortLink.ExpireAt,
+		ExpireAt:    shortLinkInput.GetExpireAt(shortLink.ExpireAt),
 		UpdatedAt:   &updateTime,
 	})
+	if err != nil {
+		return updatedShortLink, err
+	}
+
+	if longLink != shortLink.LongLink {
+		u.metaTagQueue.Enqueue(newAlias, longLink)
+	}
+	return updatedShortLink, nil
 }
 
 // NewUpdaterPersist creates a new UpdaterPersist instance.
 func NewUpdaterPersist(
 	shortLinkRepo repository.ShortLink,
 	userShortLinkRepo repository.UserShortLink,
+	appShortLinkRepo repository.AppShortLink,
 	longLinkValidator validator.LongLink,
 	aliasValidator validator.CustomAlias,
+	aliasNormalizer normalizer.Alias,
+	linkNormalizer normalizer.LongLink,
 	timer timer.Timer,
 	riskDetector risk.Detector,
+	authorizer authorizer.Authorizer,
+	resourceFinder policy.ResourceFinder,
+	redirectResolver RedirectResolver,
+	metaTagQueue MetaTagQueue,
 ) UpdaterPersist {
 	return UpdaterPersist{
 		shortLinkRepo,
 		userShortLinkRepo,
+		appShortLinkRepo,
 		longLinkValidator,
 		aliasValidator,
+		aliasNormalizer,
+		linkNormalizer,
 		timer,
 		riskDetector,
+		authorizer,
+		resourceFinder,
+		redirectResolver,
+		metaTagQueue,
 	}
 }
@@ -10,6 +10,12 @@ import (
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/fw/ptr"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/permission"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
 	"github.com/short-d/short/backend/app/usecase/validator"
@@ -19,6 +25,14 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 	t.Parallel()
 
 	now := time.Now().UTC()
+	policies := []policy.Policy{
+		{
+			Roles:        []role.Role{role.ShortLinkEditor},
+			Permission:   permission.EditShortLink,
+			ResourceType: policy.ShortLink,
+			Conditions:   []policy.Condition{policy.HasAttribute(policy.TeamAttribute, "growth")},
+		},
+	}
 
 	testCases := []struct {
 		name               string
@@ -28,7 +42,10 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 		shortLinkInput     entity.ShortLinkInput
 		relationUsers      []entity.User
 		relationShortLinks []entity.ShortLink
+		attributes         map[string]map[string][]string
 		blockedLongLinks   map[string]bool
+		roles              map[string][]role.Role
+		isNotOwner         bool
 		expectedHasErr     bool
 		expectedShortLink  entity.ShortLink
 	}{
@@ -97,6 +114,105 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 				LongLink: "https://httpbin.org",
 			},
 		},
+		{
+			name:  "reject reserved alias",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			user: entity.User{
+				ID:    "1",
+				Email: "gopher@golang.org",
+			},
+			shortLinkInput: entity.ShortLinkInput{
+				CustomAlias: ptr.String("admin"),
+			},
+			relationUsers: []entity.User{
+				{ID: "1"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			expectedHasErr:    true,
+			expectedShortLink: entity.ShortLink{},
+		},
+		{
+			name:  "admin changes alias to reserved alias",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			user: entity.User{
+				ID:    "1",
+				Email: "gopher@golang.org",
+			},
+			shortLinkInput: entity.ShortLinkInput{
+				CustomAlias: ptr.String("admin"),
+			},
+			relationUsers: []entity.User{
+				{ID: "1"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			roles: map[string][]role.Role{
+				"1": {role.Admin},
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:    "admin",
+				LongLink: "https://httpbin.org",
+			},
+		},
+		{
+			name:  "reject long link pointing back through another short link",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+				"docs": entity.ShortLink{
+					Alias:    "docs",
+					LongLink: "https://short-d.com/r/boGp9w35",
+				},
+			},
+			user: entity.User{
+				ID:    "1",
+				Email: "gopher@golang.org",
+			},
+			shortLinkInput: entity.ShortLinkInput{
+				LongLink: ptr.String("https://short-d.com/r/docs"),
+			},
+			relationUsers: []entity.User{
+				{ID: "1"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			expectedHasErr:    true,
+			expectedShortLink: entity.ShortLink{},
+		},
 		{
 			name:  "alias doesn't exist",
 			alias: "eBJRJJty",
@@ -315,6 +431,83 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 			expectedHasErr:    true,
 			expectedShortLink: entity.ShortLink{},
 		},
+		{
+			name:  "short link editor updates short link of another team",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			user: entity.User{
+				ID:    "1",
+				Email: "gopher@golang.org",
+			},
+			shortLinkInput: entity.ShortLinkInput{
+				LongLink: ptr.String("https://httpbin.org/get?p1=v1"),
+			},
+			relationUsers: []entity.User{
+				{ID: "2"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			attributes: map[string]map[string][]string{
+				"boGp9w35": {policy.TeamAttribute: {"search"}},
+			},
+			roles: map[string][]role.Role{
+				"1": {role.ShortLinkEditor},
+			},
+			isNotOwner:        true,
+			expectedHasErr:    true,
+			expectedShortLink: entity.ShortLink{},
+		},
+		{
+			name:  "short link editor updates short link of the team",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			user: entity.User{
+				ID:    "1",
+				Email: "gopher@golang.org",
+			},
+			shortLinkInput: entity.ShortLinkInput{
+				LongLink: ptr.String("https://httpbin.org/get?p1=v1"),
+			},
+			relationUsers: []entity.User{
+				{ID: "2"},
+			},
+			relationShortLinks: []entity.ShortLink{
+				{
+					Alias:     "boGp9w35",
+					LongLink:  "https://httpbin.org",
+					UpdatedAt: &now,
+				},
+			},
+			attributes: map[string]map[string][]string{
+				"boGp9w35": {policy.TeamAttribute: {"growth"}},
+			},
+			roles: map[string][]role.Role{
+				"1": {role.ShortLinkEditor},
+			},
+			isNotOwner:     true,
+			expectedHasErr: false,
+			expectedShortLink: entity.ShortLink{
+				Alias:    "boGp9w35",
+				LongLink: "https://httpbin.org/get?p1=v1",
+			},
+		},
 		{
 			name:  "reject malicious long link",
 			alias: "boGp9w35",
@@ -359,18 +552,35 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 				testCase.relationUsers,
 				testCase.relationShortLinks,
 			)
-			shortLinkRepo := repository.NewShortLinkFake(&userShortLinkRepo, testCase.shortlinks)
-			longLinkValidator := validator.NewLongLink()
-			aliasValidator := validator.NewCustomAlias()
+			shortLinkRepo := repository.NewShortLinkFake(&userShortLinkRepo, nil, testCase.shortlinks)
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(nil, nil)
+			longLinkValidator := validator.NewLongLink(validator.LongLinkPolicy{})
+			aliasNormalizer := normalizer.NewAlias(normalizer.AliasPolicy{})
+			wordList := validator.NewAliasWordList([]string{"admin"}, []string{})
+			aliasValidator := validator.NewCustomAlias(aliasNormalizer, wordList)
 			blacklist := risk.NewBlackListFake(testCase.blockedLongLinks)
 			riskDetector := risk.NewDetector(blacklist)
+			userRoleRepo := repository.NewUserRoleFake(testCase.roles)
+			au := authorizer.NewPolicyAuthorizer(rbac.NewRBAC(userRoleRepo), policies)
+			attributeRepo := repository.NewShortLinkAttributeFake(testCase.attributes)
+			resourceFinder := policy.NewResourceFinder(&userShortLinkRepo, &attributeRepo)
+			redirectPolicy := RedirectPolicy{ShortLinkDomains: []string{"short-d.com"}}
+			redirectResolver := NewRedirectResolver(redirectPolicy, &shortLinkRepo, aliasNormalizer)
+			metaTagQueue := NewMetaTagQueueFake()
 			updater := NewUpdaterPersist(
 				&shortLinkRepo,
 				&userShortLinkRepo,
+				&appShortLinkRepo,
 				longLinkValidator,
 				aliasValidator,
+				aliasNormalizer,
+				normalizer.NewLongLink(),
 				tm,
 				riskDetector,
+				au,
+				resourceFinder,
+				redirectResolver,
+				metaTagQueue,
 			)
 
 			shortLink, err := updater.UpdateShortLink(testCase.alias, testCase.shortLinkInput, testCase.user)
@@ -394,7 +604,176 @@ func TestShortLinkUpdaterPersist_UpdateShortLink(t *testing.T) {
 			}
 			isExist, err := userShortLinkRepo.HasMapping(testCase.user, shortLink.Alias)
 			assert.Equal(t, nil, err)
-			assert.Equal(t, true, isExist)
+			assert.Equal(t, !testCase.isNotOwner, isExist)
+
+			expectedEnqueued := map[string]string{}
+			if shortLink.LongLink != testCase.shortlinks[testCase.alias].LongLink {
+				expectedEnqueued[shortLink.Alias] = shortLink.LongLink
+			}
+			assert.Equal(t, expectedEnqueued, metaTagQueue.Enqueued())
+		})
+	}
+}
+
+func TestShortLinkUpdaterPersist_UpdateAppShortLink(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	nextWeek := now.Add(7 * 24 * time.Hour)
+	app := entity.App{ID: "emotic"}
+
+	testCases := []struct {
+		name              string
+		alias             string
+		shortlinks        shortLinks
+		relationApps      []entity.App
+		relationAliases   []string
+		shortLinkInput    entity.ShortLinkInput
+		expectedHasErr    bool
+		expectedShortLink entity.ShortLink
+	}{
+		{
+			name:  "successfully update long link and alias",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:    "boGp9w35",
+					LongLink: "https://httpbin.org",
+				},
+			},
+			relationApps:    []entity.App{app},
+			relationAliases: []string{"boGp9w35"},
+			shortLinkInput: entity.ShortLinkInput{
+				CustomAlias: ptr.String("httpbin"),
+				LongLink:    ptr.String("https://httpbin.org/get"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:    "httpbin",
+				LongLink: "https://httpbin.org/get",
+			},
+		},
+		{
+			name:  "successfully update expiry",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:    "boGp9w35",
+					LongLink: "https://httpbin.org",
+					ExpireAt: &now,
+				},
+			},
+			relationApps:    []entity.App{app},
+			relationAliases: []string{"boGp9w35"},
+			shortLinkInput: entity.ShortLinkInput{
+				ExpireAt: &nextWeek,
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:    "boGp9w35",
+				LongLink: "https://httpbin.org",
+				ExpireAt: &nextWeek,
+			},
+		},
+		{
+			name:  "keep expiry when not provided",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:    "boGp9w35",
+					LongLink: "https://httpbin.org",
+					ExpireAt: &now,
+				},
+			},
+			relationApps:    []entity.App{app},
+			relationAliases: []string{"boGp9w35"},
+			shortLinkInput: entity.ShortLinkInput{
+				LongLink: ptr.String("https://httpbin.org/get"),
+			},
+			expectedShortLink: entity.ShortLink{
+				Alias:    "boGp9w35",
+				LongLink: "https://httpbin.org/get",
+				ExpireAt: &now,
+			},
+		},
+		{
+			name:  "short link not created by the app",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:    "boGp9w35",
+					LongLink: "https://httpbin.org",
+				},
+			},
+			relationApps:    []entity.App{{ID: "other"}},
+			relationAliases: []string{"boGp9w35"},
+			shortLinkInput: entity.ShortLinkInput{
+				LongLink: ptr.String("https://httpbin.org/get"),
+			},
+			expectedHasErr: true,
+		},
+		{
+			name:  "app cannot use restricted alias",
+			alias: "boGp9w35",
+			shortlinks: shortLinks{
+				"boGp9w35": entity.ShortLink{
+					Alias:    "boGp9w35",
+					LongLink: "https://httpbin.org",
+				},
+			},
+			relationApps:    []entity.App{app},
+			relationAliases: []string{"boGp9w35"},
+			shortLinkInput: entity.ShortLinkInput{
+				CustomAlias: ptr.String("admin"),
+			},
+			expectedHasErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			shortLinkRepo := repository.NewShortLinkFake(nil, nil, testCase.shortlinks)
+			userShortLinkRepo := repository.NewUserShortLinkRepoFake(nil, nil)
+			appShortLinkRepo := repository.NewAppShortLinkRepoFake(testCase.relationApps, testCase.relationAliases)
+			aliasNormalizer := normalizer.NewAlias(normalizer.AliasPolicy{})
+			wordList := validator.NewAliasWordList([]string{"admin"}, []string{})
+			blacklist := risk.NewBlackListFake(map[string]bool{})
+			redirectResolver := NewRedirectResolver(RedirectPolicy{}, &shortLinkRepo, aliasNormalizer)
+			metaTagQueue := NewMetaTagQueueFake()
+			attributeRepo := repository.NewShortLinkAttributeFake(nil)
+			updater := NewUpdaterPersist(
+				&shortLinkRepo,
+				&userShortLinkRepo,
+				&appShortLinkRepo,
+				validator.NewLongLink(validator.LongLinkPolicy{}),
+				validator.NewCustomAlias(aliasNormalizer, wordList),
+				aliasNormalizer,
+				normalizer.NewLongLink(),
+				timer.NewStub(now),
+				risk.NewDetector(blacklist),
+				authorizer.NewAuthorizer(rbac.NewRBAC(repository.NewUserRoleFake(nil))),
+				policy.NewResourceFinder(&userShortLinkRepo, &attributeRepo),
+				redirectResolver,
+				metaTagQueue,
+			)
+
+			shortLink, err := updater.UpdateAppShortLink(testCase.alias, testCase.shortLinkInput, app)
+			if testCase.expectedHasErr {
+				assert.NotEqual(t, nil, err)
+				assert.Equal(t, map[string]string{}, metaTagQueue.Enqueued())
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedShortLink.Alias, shortLink.Alias)
+			assert.Equal(t, testCase.expectedShortLink.LongLink, shortLink.LongLink)
+			assert.Equal(t, testCase.expectedShortLink.ExpireAt, shortLink.ExpireAt)
+
+			expectedEnqueued := map[string]string{}
+			if shortLink.LongLink != testCase.shortlinks[testCase.alias].LongLink {
+				expectedEnqueued[shortLink.Alias] = shortLink.LongLink
+			}
+			assert.Equal(t, expectedEnqueued, metaTagQueue.Enqueued())
 		})
 	}
 }
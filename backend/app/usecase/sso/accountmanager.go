@@ -0,0 +1,316 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// TicketLifetime is how long a link or merge ticket can be redeemed after it
+// is issued.
+const TicketLifetime = 5 * time.Minute
+
+const (
+	linkTicketPurpose  = "link_account"
+	mergeTicketPurpose = "merge_user"
+)
+
+// ErrUnknownProvider represents an identity provider not supported by Short.
+type ErrUnknownProvider string
+
+var _ error = (*ErrUnknownProvider)(nil)
+
+func (e ErrUnknownProvider) Error() string {
+	return fmt.Sprintf("unknown identity provider: %s", string(e))
+}
+
+// ErrAccountNotLinked represents the failure of unlinking an identity
+// provider which the user never linked.
+type ErrAccountNotLinked string
+
+var _ error = (*ErrAccountNotLinked)(nil)
+
+func (e ErrAccountNotLinked) Error() string {
+	return fmt.Sprintf("no account of %s is linked", string(e))
+}
+
+// ErrLastLinkedAccount represents the failure of unlinking the only account
+// the user can sign in with.
+type ErrLastLinkedAccount string
+
+var _ error = (*ErrLastLinkedAccount)(nil)
+
+func (e ErrLastLinkedAccount) Error() string {
+	return fmt.Sprintf("can't unlink the last linked account: %s", string(e))
+}
+
+// ErrInvalidTicket represents a link or merge ticket which is forged,
+// expired or issued to another user.
+type ErrInvalidTicket struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidTicket)(nil)
+
+func (e ErrInvalidTicket) Error() string {
+	return fmt.Sprintf("invalid ticket: %s", e.Reason)
+}
+
+// ErrMergeConflict represents the failure of merging two users which both
+// linked an account of the same identity provider.
+type ErrMergeConflict string
+
+var _ error = (*ErrMergeConflict)(nil)
+
+func (e ErrMergeConflict) Error() string {
+	return fmt.Sprintf("both users linked an account of %s", string(e))
+}
+
+// ErrProtectedUser represents the failure of merging a user who enabled a
+// second factor or is assigned roles granting permissions. Signing in with an
+// identity provider isn't enough to take over such users.
+type ErrProtectedUser string
+
+var _ error = (*ErrProtectedUser)(nil)
+
+func (e ErrProtectedUser) Error() string {
+	return fmt.Sprintf("user(%s) is protected from being merged", string(e))
+}
+
+// AccountManager lets users manage the external accounts they sign in with.
+// Linking another account is a browser round trip to the identity provider,
+// started with a short lived link ticket. Linking an account which already
+// belongs to another user proves the two users are the same person, who then
+// receives a merge ticket to combine them, unless the other user is
+// protected by a second factor or privileged roles.
+type AccountManager struct {
+	// ssoMaps is keyed by the name of the identity provider.
+	ssoMaps       map[string]repository.SSOMap
+	userMerger    repository.UserMerger
+	twoFactorRepo repository.TwoFactor
+	rbac          rbac.RBAC
+	tokenizer     crypto.Tokenizer
+	timer         timer.Timer
+}
+
+// GetLinkedAccounts retrieves the external accounts of the user, sorted by
+// the name of the identity provider.
+func (a AccountManager) GetLinkedAccounts(user entity.User) ([]entity.LinkedAccount, error) {
+	accounts := make([]entity.LinkedAccount, 0)
+	for _, provider := range a.getProviders() {
+		ssoUserID, err := a.ssoMaps[provider].GetSSOUserID(user.ID)
+		var errNotFound repository.ErrEntryNotFound
+		if errors.As(err, &errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, entity.LinkedAccount{
+			Provider:  provider,
+			SSOUserID: ssoUserID,
+		})
+	}
+	return accounts, nil
+}
+
+// UnlinkAccount unlinks the account of the given identity provider as long
+// as the user can still sign in with another one.
+func (a AccountManager) UnlinkAccount(user entity.User, provider string) error {
+	ssoMap, ok := a.ssoMaps[provider]
+	if !ok {
+		return ErrUnknownProvider(provider)
+	}
+
+	accounts, err := a.GetLinkedAccounts(user)
+	if err != nil {
+		return err
+	}
+
+	ssoUserID := ""
+	for _, account := range accounts {
+		if account.Provider == provider {
+			ssoUserID = account.SSOUserID
+		}
+	}
+	if ssoUserID == "" {
+		return ErrAccountNotLinked(provider)
+	}
+	if len(accounts) < 2 {
+		return ErrLastLinkedAccount(provider)
+	}
+	return ssoMap.DeleteMapping(ssoUserID, user.ID)
+}
+
+// IssueLinkTicket authorizes the user to link an account of the given
+// identity provider through the sign in flow.
+func (a AccountManager) IssueLinkTicket(user entity.User, provider string) (string, error) {
+	if _, ok := a.ssoMaps[provider]; !ok {
+		return "", ErrUnknownProvider(provider)
+	}
+	return a.issueTicket(linkTicketPurpose, map[string]interface{}{
+		"user_id":  user.ID,
+		"provider": provider,
+	})
+}
+
+// VerifyLinkTicket retrieves the user linking an account of the given
+// identity provider.
+func (a AccountManager) VerifyLinkTicket(ticket string, provider string) (string, error) {
+	payload, err := a.verifyTicket(linkTicketPurpose, ticket)
+	if err != nil {
+		return "", err
+	}
+	if payload["provider"] != provider {
+		return "", ErrInvalidTicket{Reason: "issued for another provider"}
+	}
+	userID, ok := payload["user_id"].(string)
+	if !ok || userID == "" {
+		return "", ErrInvalidTicket{Reason: "user missing"}
+	}
+	return userID, nil
+}
+
+// IssueMergeTicket authorizes the target user to take over the source user.
+// It must only be issued after the person signed in as both users. It returns
+// ErrProtectedUser when the source user can't be merged.
+func (a AccountManager) IssueMergeTicket(targetUserID string, sourceUserID string) (string, error) {
+	err := a.checkMergeable(sourceUserID)
+	if err != nil {
+		return "", err
+	}
+	return a.issueTicket(mergeTicketPurpose, map[string]interface{}{
+		"target_user_id": targetUserID,
+		"source_user_id": sourceUserID,
+	})
+}
+
+// MergeUser moves everything owned by the source user in the merge ticket to
+// the given user and removes the source user afterwards.
+func (a AccountManager) MergeUser(user entity.User, mergeTicket string) (string, error) {
+	payload, err := a.verifyTicket(mergeTicketPurpose, mergeTicket)
+	if err != nil {
+		return "", err
+	}
+	if payload["target_user_id"] != user.ID {
+		return "", ErrInvalidTicket{Reason: "issued to another user"}
+	}
+	sourceUserID, ok := payload["source_user_id"].(string)
+	if !ok || sourceUserID == "" || sourceUserID == user.ID {
+		return "", ErrInvalidTicket{Reason: "source user missing"}
+	}
+	err = a.checkMergeable(sourceUserID)
+	if err != nil {
+		return "", err
+	}
+
+	targetAccounts, err := a.GetLinkedAccounts(user)
+	if err != nil {
+		return "", err
+	}
+	sourceAccounts, err := a.GetLinkedAccounts(entity.User{ID: sourceUserID})
+	if err != nil {
+		return "", err
+	}
+	for _, targetAccount := range targetAccounts {
+		for _, sourceAccount := range sourceAccounts {
+			if targetAccount.Provider == sourceAccount.Provider {
+				return "", ErrMergeConflict(targetAccount.Provider)
+			}
+		}
+	}
+
+	err = a.userMerger.MergeUser(sourceUserID, user.ID, a.timer.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+	return sourceUserID, nil
+}
+
+// checkMergeable refuses to merge the users whose second factor or roles
+// would be bypassed by signing in with an identity provider alone.
+func (a AccountManager) checkMergeable(userID string) error {
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(userID)
+	var errNotFound repository.ErrEntryNotFound
+	switch {
+	case errors.As(err, &errNotFound):
+	case err != nil:
+		return err
+	case twoFactor.IsEnabled():
+		return ErrProtectedUser(userID)
+	}
+
+	isPrivileged, err := a.rbac.IsPrivileged(entity.User{ID: userID})
+	if err != nil {
+		return err
+	}
+	if isPrivileged {
+		return ErrProtectedUser(userID)
+	}
+	return nil
+}
+
+func (a AccountManager) getProviders() []string {
+	providers := make([]string, 0, len(a.ssoMaps))
+	for provider := range a.ssoMaps {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+func (a AccountManager) issueTicket(purpose string, payload map[string]interface{}) (string, error) {
+	payload["purpose"] = purpose
+	payload["expire_at"] = a.timer.Now().Add(TicketLifetime).UTC().Format(time.RFC3339)
+	return a.tokenizer.Encode(payload)
+}
+
+func (a AccountManager) verifyTicket(purpose string, ticket string) (crypto.TokenPayload, error) {
+	payload, err := a.tokenizer.Decode(ticket)
+	if err != nil {
+		return nil, ErrInvalidTicket{Reason: "signature mismatch"}
+	}
+	if payload["purpose"] != purpose {
+		return nil, ErrInvalidTicket{Reason: "issued for another purpose"}
+	}
+
+	expireAtStr, ok := payload["expire_at"].(string)
+	if !ok {
+		return nil, ErrInvalidTicket{Reason: "expiration missing"}
+	}
+	expireAt, err := time.Parse(time.RFC3339, expireAtStr)
+	if err != nil {
+		return nil, ErrInvalidTicket{Reason: "expiration malformed"}
+	}
+	if !a.timer.Now().Before(expireAt) {
+		return nil, ErrInvalidTicket{Reason: "ticket expired"}
+	}
+	return payload, nil
+}
+
+// NewAccountManager creates AccountManager with the account mappings of each
+// identity provider, keyed by the name of the identity provider.
+func NewAccountManager(
+	ssoMaps map[string]repository.SSOMap,
+	userMerger repository.UserMerger,
+	twoFactorRepo repository.TwoFactor,
+	rbac rbac.RBAC,
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+) AccountManager {
+	return AccountManager{
+		ssoMaps:       ssoMaps,
+		userMerger:    userMerger,
+		twoFactorRepo: twoFactorRepo,
+		rbac:          rbac,
+		tokenizer:     tokenizer,
+		timer:         timer,
+	}
+}
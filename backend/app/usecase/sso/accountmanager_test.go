@@ -0,0 +1,456 @@
+// +build !integration all
+
+package sso
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+type ssoMapping struct {
+	ssoUserIDs []string
+	userIDs    []string
+}
+
+func newAccountManagerFake(
+	t *testing.T,
+	now time.Time,
+	users []entity.User,
+	mappings map[string]ssoMapping,
+	twoFactors []entity.TwoFactor,
+	userRoles map[string][]role.Role,
+) (AccountManager, *repository.UserFake, map[string]*repository.SSOMapFake) {
+	userRepo := repository.NewUserFake(users)
+
+	ssoMaps := map[string]repository.SSOMap{}
+	ssoMapFakes := map[string]*repository.SSOMapFake{}
+	var ssoMapList []*repository.SSOMapFake
+	for provider, mapping := range mappings {
+		ssoMap, err := repository.NewsSSOMapFake(mapping.ssoUserIDs, mapping.userIDs)
+		assert.Equal(t, nil, err)
+		ssoMaps[provider] = &ssoMap
+		ssoMapFakes[provider] = &ssoMap
+		ssoMapList = append(ssoMapList, &ssoMap)
+	}
+
+	userMerger := repository.NewUserMergerFake(&userRepo, ssoMapList...)
+	twoFactorRepo := repository.NewTwoFactorFake(twoFactors)
+	ac := rbac.NewRBAC(repository.NewUserRoleFake(userRoles))
+	accountManager := NewAccountManager(
+		ssoMaps,
+		userMerger,
+		&twoFactorRepo,
+		ac,
+		crypto.NewTokenizerFake(),
+		timer.NewStub(now),
+	)
+	return accountManager, &userRepo, ssoMapFakes
+}
+
+func TestAccountManager_UnlinkAccount(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+
+	testCases := []struct {
+		name                   string
+		mappings               map[string]ssoMapping
+		provider               string
+		expectedErr            error
+		expectedLinkedAccounts []entity.LinkedAccount
+	}{
+		{
+			name: "unknown provider",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+			},
+			provider:    "myspace",
+			expectedErr: ErrUnknownProvider("myspace"),
+		},
+		{
+			name: "account not linked",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+			},
+			provider:    "google",
+			expectedErr: ErrAccountNotLinked("google"),
+		},
+		{
+			name: "last linked account",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+			},
+			provider:    "github",
+			expectedErr: ErrLastLinkedAccount("github"),
+		},
+		{
+			name: "account unlinked",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"alpha"}},
+			},
+			provider:    "github",
+			expectedErr: nil,
+			expectedLinkedAccounts: []entity.LinkedAccount{
+				{Provider: "google", SSOUserID: "1101"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := entity.User{ID: "alpha"}
+			accountManager, _, _ := newAccountManagerFake(t, now, []entity.User{user}, testCase.mappings, nil, nil)
+
+			err := accountManager.UnlinkAccount(user, testCase.provider)
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr != nil {
+				return
+			}
+
+			linkedAccounts, err := accountManager.GetLinkedAccounts(user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedLinkedAccounts, linkedAccounts)
+		})
+	}
+}
+
+func TestAccountManager_VerifyLinkTicket(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	mappings := map[string]ssoMapping{
+		"github": {ssoUserIDs: []string{}, userIDs: []string{}},
+		"google": {ssoUserIDs: []string{}, userIDs: []string{}},
+	}
+
+	testCases := []struct {
+		name           string
+		ticket         func(accountManager AccountManager) string
+		provider       string
+		elapsed        time.Duration
+		hasErr         bool
+		expectedUserID string
+	}{
+		{
+			name: "malformed ticket",
+			ticket: func(accountManager AccountManager) string {
+				return "malformed"
+			},
+			provider: "github",
+			hasErr:   true,
+		},
+		{
+			name: "merge ticket",
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			provider: "github",
+			hasErr:   true,
+		},
+		{
+			name: "issued for another provider",
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueLinkTicket(entity.User{ID: "alpha"}, "google")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			provider: "github",
+			hasErr:   true,
+		},
+		{
+			name: "ticket expired",
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueLinkTicket(entity.User{ID: "alpha"}, "github")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			provider: "github",
+			elapsed:  TicketLifetime,
+			hasErr:   true,
+		},
+		{
+			name: "valid ticket",
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueLinkTicket(entity.User{ID: "alpha"}, "github")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			provider:       "github",
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "alpha",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			accountManager, _, _ := newAccountManagerFake(t, now, []entity.User{}, mappings, nil, nil)
+			ticket := testCase.ticket(accountManager)
+
+			accountManager.timer = timer.NewStub(now.Add(testCase.elapsed))
+			userID, err := accountManager.VerifyLinkTicket(ticket, testCase.provider)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedUserID, userID)
+		})
+	}
+
+	accountManager, _, _ := newAccountManagerFake(t, now, []entity.User{}, mappings, nil, nil)
+	_, err := accountManager.IssueLinkTicket(entity.User{ID: "alpha"}, "myspace")
+	assert.Equal(t, ErrUnknownProvider("myspace"), err)
+}
+
+func TestAccountManager_IssueMergeTicket(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	mappings := map[string]ssoMapping{
+		"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+		"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+	}
+
+	testCases := []struct {
+		name        string
+		twoFactors  []entity.TwoFactor
+		userRoles   map[string][]role.Role
+		expectedErr error
+	}{
+		{
+			name: "source user enrolled two factor without enabling it",
+			twoFactors: []entity.TwoFactor{
+				{UserID: "beta"},
+			},
+			userRoles: map[string][]role.Role{
+				"beta": {role.Basic, role.Premium},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "source user enabled two factor",
+			twoFactors: []entity.TwoFactor{
+				{UserID: "beta", EnabledAt: &now},
+			},
+			expectedErr: ErrProtectedUser("beta"),
+		},
+		{
+			name: "source user has role granting permissions",
+			userRoles: map[string][]role.Role{
+				"beta": {role.Basic, role.ShortLinkViewer},
+			},
+			expectedErr: ErrProtectedUser("beta"),
+		},
+		{
+			name: "only target user is protected",
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", EnabledAt: &now},
+			},
+			userRoles: map[string][]role.Role{
+				"alpha": {role.Admin},
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			accountManager, _, _ := newAccountManagerFake(
+				t,
+				now,
+				[]entity.User{},
+				mappings,
+				testCase.twoFactors,
+				testCase.userRoles,
+			)
+			_, err := accountManager.IssueMergeTicket("alpha", "beta")
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+func TestAccountManager_MergeUser(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	users := []entity.User{
+		{ID: "alpha", Email: "alpha@example.com"},
+		{ID: "beta", Email: "beta@example.com"},
+	}
+
+	testCases := []struct {
+		name           string
+		mappings       map[string]ssoMapping
+		twoFactors     []entity.TwoFactor
+		userRoles      map[string][]role.Role
+		ticket         func(accountManager AccountManager) string
+		elapsed        time.Duration
+		hasErr         bool
+		expectedErr    error
+		expectedSource string
+	}{
+		{
+			name: "link ticket",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueLinkTicket(entity.User{ID: "alpha"}, "github")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			hasErr: true,
+		},
+		{
+			name: "issued to another user",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("beta", "alpha")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			hasErr: true,
+		},
+		{
+			name: "ticket expired",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			elapsed: TicketLifetime,
+			hasErr:  true,
+		},
+		{
+			name: "both users linked the same provider",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat", "monalisa"}, userIDs: []string{"alpha", "beta"}},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			hasErr:      true,
+			expectedErr: ErrMergeConflict("github"),
+		},
+		{
+			name: "source user enabled two factor after the ticket was issued",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+			},
+			twoFactors: []entity.TwoFactor{
+				{UserID: "beta", EnabledAt: &now},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			hasErr:      true,
+			expectedErr: ErrProtectedUser("beta"),
+		},
+		{
+			name: "source user granted a role after the ticket was issued",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+			},
+			userRoles: map[string][]role.Role{
+				"beta": {role.Admin},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			hasErr:      true,
+			expectedErr: ErrProtectedUser("beta"),
+		},
+		{
+			name: "users merged",
+			mappings: map[string]ssoMapping{
+				"github": {ssoUserIDs: []string{"octocat"}, userIDs: []string{"alpha"}},
+				"google": {ssoUserIDs: []string{"1101"}, userIDs: []string{"beta"}},
+			},
+			ticket: func(accountManager AccountManager) string {
+				ticket, err := accountManager.IssueMergeTicket("alpha", "beta")
+				assert.Equal(t, nil, err)
+				return ticket
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedSource: "beta",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			usersCopy := append([]entity.User{}, users...)
+			accountManager, userRepo, _ := newAccountManagerFake(
+				t,
+				now,
+				usersCopy,
+				testCase.mappings,
+				testCase.twoFactors,
+				testCase.userRoles,
+			)
+			issuer, _, _ := newAccountManagerFake(t, now, []entity.User{}, testCase.mappings, nil, nil)
+			ticket := testCase.ticket(issuer)
+
+			accountManager.timer = timer.NewStub(now.Add(testCase.elapsed))
+			user := entity.User{ID: "alpha"}
+			sourceUserID, err := accountManager.MergeUser(user, ticket)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				if testCase.expectedErr != nil {
+					assert.Equal(t, testCase.expectedErr, err)
+				}
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedSource, sourceUserID)
+			assert.Equal(t, false, userRepo.IsUserIDExist(sourceUserID))
+
+			linkedAccounts, err := accountManager.GetLinkedAccounts(user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.LinkedAccount{
+				{Provider: "github", SSOUserID: "octocat"},
+				{Provider: "google", SSOUserID: "1101"},
+			}, linkedAccounts)
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package sso
 
 // IdentityProvider represents external service that verifies the user's
-// identity.
+// identity. The state is the opaque value the identity provider sends back to
+// the callback together with the authorization code.
 type IdentityProvider interface {
-	GetAuthorizationURL() string
-	RequestAccessToken(authorizationCode string) (accessToken string, err error)
+	GetAuthorizationURL(state string) string
+	RequestAccessToken(authorizationCode string, state string) (accessToken string, err error)
 }
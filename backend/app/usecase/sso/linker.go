@@ -2,12 +2,35 @@ package sso
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/keygen"
 	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
+// ErrAccountLinkedToOtherUser represents the failure of linking an external
+// account which already belongs to another Short user.
+type ErrAccountLinkedToOtherUser struct {
+	UserID string
+}
+
+var _ error = (*ErrAccountLinkedToOtherUser)(nil)
+
+func (e ErrAccountLinkedToOtherUser) Error() string {
+	return fmt.Sprintf("account is already linked to user %s", e.UserID)
+}
+
+// ErrProviderAlreadyLinked represents the failure of linking a second
+// account of the same identity provider to a Short user.
+type ErrProviderAlreadyLinked string
+
+var _ error = (*ErrProviderAlreadyLinked)(nil)
+
+func (e ErrProviderAlreadyLinked) Error() string {
+	return fmt.Sprintf("user %s already linked an account of the provider", string(e))
+}
+
 // AccountLinker maps external user accounts to Short user accounts.
 type AccountLinker struct {
 	keyGen   keygen.KeyGenerator
@@ -59,6 +82,32 @@ func (a AccountLinker) CreateAndLinkAccount(ssoUser entity.SSOUser) error {
 	return a.ssoMap.CreateMapping(ssoUser.ID, userID)
 }
 
+// LinkAccount links the external account to an existing Short user, who
+// signed in with another identity provider before.
+func (a AccountLinker) LinkAccount(ssoUser entity.SSOUser, userID string) error {
+	linkedUserID, err := a.ssoMap.GetShortUserID(ssoUser.ID)
+	if err == nil {
+		if linkedUserID == userID {
+			return nil
+		}
+		return ErrAccountLinkedToOtherUser{UserID: linkedUserID}
+	}
+
+	var errNotFound repository.ErrEntryNotFound
+	if !errors.As(err, &errNotFound) {
+		return err
+	}
+
+	_, err = a.ssoMap.GetSSOUserID(userID)
+	if err == nil {
+		return ErrProviderAlreadyLinked(userID)
+	}
+	if !errors.As(err, &errNotFound) {
+		return err
+	}
+	return a.ssoMap.CreateMapping(ssoUser.ID, userID)
+}
+
 func (a AccountLinker) createAccount(ssoUser entity.SSOUser) (string, error) {
 	userID, err := a.generateUnassignedUserID()
 	if err != nil {
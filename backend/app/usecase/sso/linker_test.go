@@ -154,3 +154,73 @@ func TestLinker_CreateAndLinkAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestLinker_LinkAccount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		mappingUserIDs    []string
+		mappingSSOUserIDs []string
+		ssoUser           entity.SSOUser
+		userID            string
+		expectedErr       error
+	}{
+		{
+			name:              "link new account",
+			mappingUserIDs:    []string{},
+			mappingSSOUserIDs: []string{},
+			ssoUser:           entity.SSOUser{ID: "gama"},
+			userID:            "alpha",
+			expectedErr:       nil,
+		},
+		{
+			name:              "account already linked to the user",
+			mappingUserIDs:    []string{"alpha"},
+			mappingSSOUserIDs: []string{"gama"},
+			ssoUser:           entity.SSOUser{ID: "gama"},
+			userID:            "alpha",
+			expectedErr:       nil,
+		},
+		{
+			name:              "account linked to other user",
+			mappingUserIDs:    []string{"beta"},
+			mappingSSOUserIDs: []string{"gama"},
+			ssoUser:           entity.SSOUser{ID: "gama"},
+			userID:            "alpha",
+			expectedErr:       ErrAccountLinkedToOtherUser{UserID: "beta"},
+		},
+		{
+			name:              "user linked another account of the provider",
+			mappingUserIDs:    []string{"alpha"},
+			mappingSSOUserIDs: []string{"delta"},
+			ssoUser:           entity.SSOUser{ID: "gama"},
+			userID:            "alpha",
+			expectedErr:       ErrProviderAlreadyLinked("alpha"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyFetcher := keygen.NewKeyFetcherFake([]keygen.Key{})
+			keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+			assert.Equal(t, nil, err)
+
+			userRepo := repository.NewUserFake([]entity.User{})
+			linkerFactory := NewAccountLinkerFactory(keyGen, &userRepo)
+			ssoMap, err := repository.NewsSSOMapFake(testCase.mappingSSOUserIDs, testCase.mappingUserIDs)
+			assert.Equal(t, nil, err)
+
+			linker := linkerFactory.NewAccountLinker(&ssoMap)
+			err = linker.LinkAccount(testCase.ssoUser, testCase.userID)
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr != nil {
+				return
+			}
+			assert.Equal(t, true, ssoMap.IsRelationExist(testCase.ssoUser.ID, testCase.userID))
+		})
+	}
+}
@@ -3,52 +3,91 @@ package sso
 import (
 	"errors"
 
+	"github.com/short-d/short/backend/app/entity"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
 )
 
 // SingleSignOn enables sign in through external identity providers, such as
 // Github, Facebook, and Google.
 type SingleSignOn struct {
+	// name identifies the external identity provider, such as github.
+	name             string
 	identityProvider IdentityProvider
 	account          Account
 	accountLinker    AccountLinker
 	authenticator    authenticator.Authenticator
+	twoFactor        twofactor.TwoFactor
 }
 
-// SignIn generates access token for a user using authorization code obtained
-// from external identity provider.
-func (o SingleSignOn) SignIn(authorizationCode string) (string, error) {
+// SignIn starts a new session on the given device for a user using
+// authorization code and state obtained from external identity provider.
+// It returns twofactor.ErrSecondFactorRequired when the user has to complete
+// the sign in with a second factor.
+func (o SingleSignOn) SignIn(
+	authorizationCode string,
+	state string,
+	device entity.Device,
+) (authenticator.AuthTokens, error) {
 	if len(authorizationCode) < 1 {
-		return "", errors.New("authorizationCode can't be empty")
+		return authenticator.AuthTokens{}, errors.New("authorizationCode can't be empty")
 	}
 
-	accessToken, err := o.identityProvider.RequestAccessToken(authorizationCode)
+	accessToken, err := o.identityProvider.RequestAccessToken(authorizationCode, state)
 	if err != nil {
-		return "", err
+		return authenticator.AuthTokens{}, err
 	}
 
 	ssoUser, err := o.account.GetSingleSignOnUser(accessToken)
 	if err != nil {
-		return "", err
+		return authenticator.AuthTokens{}, err
 	}
 
 	isLinked, err := o.accountLinker.IsAccountLinked(ssoUser)
 	if err != nil {
-		return "", err
+		return authenticator.AuthTokens{}, err
 	}
 
 	if !isLinked {
 		err = o.accountLinker.CreateAndLinkAccount(ssoUser)
 		if err != nil {
-			return "", err
+			return authenticator.AuthTokens{}, err
 		}
 	}
 
 	user, err := o.accountLinker.GetShortUser(ssoUser)
 	if err != nil {
-		return "", err
+		return authenticator.AuthTokens{}, err
 	}
-	return o.authenticator.GenerateToken(user)
+	return o.twoFactor.SignIn(user, o.name, device)
+}
+
+// LinkAccount links the external account of the user, obtained with the
+// authorization code and state, to the given Short user.
+func (o SingleSignOn) LinkAccount(
+	authorizationCode string,
+	state string,
+	userID string,
+) error {
+	if len(authorizationCode) < 1 {
+		return errors.New("authorizationCode can't be empty")
+	}
+
+	accessToken, err := o.identityProvider.RequestAccessToken(authorizationCode, state)
+	if err != nil {
+		return err
+	}
+
+	ssoUser, err := o.account.GetSingleSignOnUser(accessToken)
+	if err != nil {
+		return err
+	}
+	return o.accountLinker.LinkAccount(ssoUser, userID)
+}
+
+// Name retrieves the name of the external identity provider.
+func (o SingleSignOn) Name() string {
+	return o.name
 }
 
 // IsSignedIn checks whether a user is authenticated by Short.
@@ -57,34 +96,41 @@ func (o SingleSignOn) IsSignedIn(authToken string) bool {
 }
 
 // GetSignInLink retrieves the sign in link of the external account provider.
-func (o SingleSignOn) GetSignInLink() string {
-	return o.identityProvider.GetAuthorizationURL()
+// The state is sent back to the sign in callback unchanged.
+func (o SingleSignOn) GetSignInLink(state string) string {
+	return o.identityProvider.GetAuthorizationURL(state)
 }
 
 // Factory makes SingleSignOn.
 type Factory struct {
 	authenticator authenticator.Authenticator
+	twoFactor     twofactor.TwoFactor
 }
 
 // NewSingleSignOn creates SingleSignOn.
 func (s Factory) NewSingleSignOn(
+	name string,
 	identityProvider IdentityProvider,
 	account Account,
 	accountLinker AccountLinker,
 ) SingleSignOn {
 	return SingleSignOn{
+		name:             name,
 		identityProvider: identityProvider,
 		account:          account,
 		accountLinker:    accountLinker,
 		authenticator:    s.authenticator,
+		twoFactor:        s.twoFactor,
 	}
 }
 
 // NewFactory creates single sign on factory.
 func NewFactory(
 	authenticator authenticator.Authenticator,
+	twoFactor twofactor.TwoFactor,
 ) Factory {
 	return Factory{
 		authenticator: authenticator,
+		twoFactor:     twoFactor,
 	}
 }
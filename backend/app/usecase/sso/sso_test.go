@@ -3,7 +3,6 @@
 package sso
 
 import (
-	"encoding/json"
 	"testing"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/keygen"
 	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
 )
 
 func TestSingleSignOn_SignIn(t *testing.T) {
@@ -117,10 +117,10 @@ func TestSingleSignOn_SignIn(t *testing.T) {
 			assert.Equal(t, nil, err)
 
 			linker := linkerFactory.NewAccountLinker(&ssoMap)
-			factory := NewFactory(auth)
+			factory := NewFactory(auth, twofactor.NewTwoFactorFake(now, auth))
 
-			singleSignOn := factory.NewSingleSignOn(identityProvider, profileService, linker)
-			gotAuthToken, err := singleSignOn.SignIn(testCase.authorizationCode)
+			singleSignOn := factory.NewSingleSignOn("github", identityProvider, profileService, linker)
+			gotAuthTokens, err := singleSignOn.SignIn(testCase.authorizationCode, "", entity.Device{})
 			if testCase.hasErr {
 				assert.NotEqual(t, nil, err)
 				return
@@ -130,16 +130,9 @@ func TestSingleSignOn_SignIn(t *testing.T) {
 				return
 			}
 
-			values := map[string]string{
-				"id":        testCase.expectedUser.ID,
-				"issued_at": now.Format(time.RFC3339Nano),
-			}
-
-			buf, err := json.Marshal(values)
+			gotUser, err := auth.GetUser(gotAuthTokens.AccessToken)
 			assert.Equal(t, nil, err)
-
-			expAuthToken := string(buf)
-			assert.Equal(t, expAuthToken, gotAuthToken)
+			assert.Equal(t, testCase.expectedUser, gotUser)
 		})
 	}
 }
@@ -0,0 +1,172 @@
+package sso
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+)
+
+// StateLifetime is how long a user has to finish signing in with the identity
+// provider.
+const StateLifetime = 10 * time.Minute
+
+// ErrInvalidState represents an OAuth state which is forged, expired or not
+// issued to the current browser.
+type ErrInvalidState struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidState)(nil)
+
+func (e ErrInvalidState) Error() string {
+	return fmt.Sprintf("invalid OAuth state: %s", e.Reason)
+}
+
+// ErrInvalidRedirect represents a post sign in redirect target outside of
+// Short.
+type ErrInvalidRedirect string
+
+var _ error = (*ErrInvalidRedirect)(nil)
+
+func (e ErrInvalidRedirect) Error() string {
+	return fmt.Sprintf("redirect target(%s) is not allowed", string(e))
+}
+
+// stateTokenPurpose prevents other tokens signed with the same secret from
+// being accepted as the state.
+const stateTokenPurpose = "oauth_state"
+
+// Intent describes what to do once the user comes back from the identity
+// provider.
+type Intent struct {
+	// RedirectTo is the path of the web frontend to open afterwards.
+	RedirectTo string
+	// LinkUserID is set when linking the external account to an existing user
+	// instead of signing in.
+	LinkUserID string
+}
+
+// StateSigner protects the sign in flow against login CSRF. The state sent
+// to the identity provider is signed, expires shortly and can only be
+// completed by the browser holding the matching nonce.
+type StateSigner struct {
+	tokenizer crypto.Tokenizer
+	timer     timer.Timer
+}
+
+// Issue creates a state carrying the intent, together with the nonce which
+// must be kept on the browser until the sign in completes.
+func (s StateSigner) Issue(intent Intent) (state string, nonce string, err error) {
+	if err = ValidateRedirect(intent.RedirectTo); err != nil {
+		return "", "", err
+	}
+
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	nonce = base64.RawURLEncoding.EncodeToString(buf)
+
+	state, err = s.tokenizer.Encode(map[string]interface{}{
+		"purpose":      stateTokenPurpose,
+		"nonce_hash":   hashNonce(nonce),
+		"redirect_to":  intent.RedirectTo,
+		"link_user_id": intent.LinkUserID,
+		"expire_at":    s.timer.Now().Add(StateLifetime).UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return state, nonce, nil
+}
+
+// Verify checks the state returned by the identity provider against the
+// nonce kept on the browser and retrieves the intent.
+func (s StateSigner) Verify(state string, nonce string) (Intent, error) {
+	if state == "" || nonce == "" {
+		return Intent{}, ErrInvalidState{Reason: "state or nonce missing"}
+	}
+
+	payload, err := s.tokenizer.Decode(state)
+	if err != nil {
+		return Intent{}, ErrInvalidState{Reason: "signature mismatch"}
+	}
+	if payload["purpose"] != stateTokenPurpose {
+		return Intent{}, ErrInvalidState{Reason: "not a state"}
+	}
+
+	nonceHash, ok := payload["nonce_hash"].(string)
+	if !ok {
+		return Intent{}, ErrInvalidState{Reason: "nonce missing"}
+	}
+	if subtle.ConstantTimeCompare([]byte(nonceHash), []byte(hashNonce(nonce))) != 1 {
+		return Intent{}, ErrInvalidState{Reason: "nonce mismatch"}
+	}
+
+	expireAtStr, ok := payload["expire_at"].(string)
+	if !ok {
+		return Intent{}, ErrInvalidState{Reason: "expiration missing"}
+	}
+	expireAt, err := time.Parse(time.RFC3339, expireAtStr)
+	if err != nil {
+		return Intent{}, ErrInvalidState{Reason: "expiration malformed"}
+	}
+	if !s.timer.Now().Before(expireAt) {
+		return Intent{}, ErrInvalidState{Reason: "state expired"}
+	}
+
+	redirectTo, ok := payload["redirect_to"].(string)
+	if !ok {
+		return Intent{}, ErrInvalidState{Reason: "redirect target missing"}
+	}
+	if err = ValidateRedirect(redirectTo); err != nil {
+		return Intent{}, err
+	}
+	linkUserID, ok := payload["link_user_id"].(string)
+	if !ok {
+		return Intent{}, ErrInvalidState{Reason: "link user missing"}
+	}
+	return Intent{RedirectTo: redirectTo, LinkUserID: linkUserID}, nil
+}
+
+// ValidateRedirect only allows redirecting to a path of the web frontend
+// after sign in, so that the flow can't be used as an open redirect. An
+// empty target stands for the home page.
+func ValidateRedirect(redirectTo string) error {
+	if redirectTo == "" {
+		return nil
+	}
+	if !strings.HasPrefix(redirectTo, "/") ||
+		strings.HasPrefix(redirectTo, "//") ||
+		strings.ContainsAny(redirectTo, "\\\r\n\t") {
+		return ErrInvalidRedirect(redirectTo)
+	}
+
+	u, err := url.Parse(redirectTo)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return ErrInvalidRedirect(redirectTo)
+	}
+	return nil
+}
+
+func hashNonce(nonce string) string {
+	digest := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(digest[:])
+}
+
+// NewStateSigner creates StateSigner.
+func NewStateSigner(tokenizer crypto.Tokenizer, timer timer.Timer) StateSigner {
+	return StateSigner{
+		tokenizer: tokenizer,
+		timer:     timer,
+	}
+}
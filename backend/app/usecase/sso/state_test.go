@@ -0,0 +1,175 @@
+// +build !integration all
+
+package sso
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/fw/must"
+)
+
+func TestStateSigner_Verify(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+
+	testCases := []struct {
+		name           string
+		intent         Intent
+		tamper         func(state string, nonce string) (string, string)
+		elapsed        time.Duration
+		expectedIntent Intent
+		hasErr         bool
+	}{
+		{
+			name:   "state missing",
+			intent: Intent{RedirectTo: "/"},
+			tamper: func(state string, nonce string) (string, string) {
+				return "", nonce
+			},
+			hasErr: true,
+		},
+		{
+			name:   "nonce missing",
+			intent: Intent{RedirectTo: "/"},
+			tamper: func(state string, nonce string) (string, string) {
+				return state, ""
+			},
+			hasErr: true,
+		},
+		{
+			name:   "state malformed",
+			intent: Intent{RedirectTo: "/"},
+			tamper: func(state string, nonce string) (string, string) {
+				return "malformed", nonce
+			},
+			hasErr: true,
+		},
+		{
+			name:   "nonce issued to another browser",
+			intent: Intent{RedirectTo: "/"},
+			tamper: func(state string, nonce string) (string, string) {
+				return state, "attacker"
+			},
+			hasErr: true,
+		},
+		{
+			name:   "redirect target tampered",
+			intent: Intent{RedirectTo: "/"},
+			tamper: func(state string, nonce string) (string, string) {
+				return strings.Replace(state, `"/"`, `"//evil.example.com"`, 1), nonce
+			},
+			hasErr: true,
+		},
+		{
+			name:    "state expired",
+			intent:  Intent{RedirectTo: "/"},
+			elapsed: 10 * time.Minute,
+			hasErr:  true,
+		},
+		{
+			name:           "home page",
+			intent:         Intent{},
+			elapsed:        9 * time.Minute,
+			expectedIntent: Intent{},
+		},
+		{
+			name:           "redirect to path",
+			intent:         Intent{RedirectTo: "/user/settings?tab=sessions#active"},
+			expectedIntent: Intent{RedirectTo: "/user/settings?tab=sessions#active"},
+		},
+		{
+			name:           "link account",
+			intent:         Intent{RedirectTo: "/user/settings", LinkUserID: "alpha"},
+			expectedIntent: Intent{RedirectTo: "/user/settings", LinkUserID: "alpha"},
+		},
+		{
+			name:   "not a state",
+			intent: Intent{},
+			tamper: func(state string, nonce string) (string, string) {
+				return strings.Replace(state, stateTokenPurpose, "access_token", 1), nonce
+			},
+			hasErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			stateSigner := NewStateSigner(crypto.NewTokenizerFake(), timer.NewStub(now))
+			state, nonce, err := stateSigner.Issue(testCase.intent)
+			assert.Equal(t, nil, err)
+
+			if testCase.tamper != nil {
+				state, nonce = testCase.tamper(state, nonce)
+			}
+
+			stateSigner.timer = timer.NewStub(now.Add(testCase.elapsed))
+			intent, err := stateSigner.Verify(state, nonce)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedIntent, intent)
+		})
+	}
+}
+
+func TestStateSigner_Issue(t *testing.T) {
+	t.Parallel()
+
+	stateSigner := NewStateSigner(crypto.NewTokenizerFake(), timer.NewStub(time.Now()))
+
+	_, _, err := stateSigner.Issue(Intent{RedirectTo: "https://evil.example.com"})
+	assert.Equal(t, ErrInvalidRedirect("https://evil.example.com"), err)
+
+	firstState, firstNonce, err := stateSigner.Issue(Intent{RedirectTo: "/"})
+	assert.Equal(t, nil, err)
+	secondState, secondNonce, err := stateSigner.Issue(Intent{RedirectTo: "/"})
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, firstState, secondState)
+	assert.NotEqual(t, firstNonce, secondNonce)
+	assert.Equal(t, false, strings.Contains(firstState, firstNonce))
+}
+
+func TestValidateRedirect(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		redirectTo string
+		hasErr     bool
+	}{
+		{name: "empty", redirectTo: "", hasErr: false},
+		{name: "root", redirectTo: "/", hasErr: false},
+		{name: "path with query", redirectTo: "/search?q=short", hasErr: false},
+		{name: "relative path", redirectTo: "user/settings", hasErr: true},
+		{name: "absolute URL", redirectTo: "https://evil.example.com/", hasErr: true},
+		{name: "protocol relative URL", redirectTo: "//evil.example.com", hasErr: true},
+		{name: "backslash", redirectTo: "/\\evil.example.com", hasErr: true},
+		{name: "javascript", redirectTo: "javascript:alert(1)", hasErr: true},
+		{name: "header injection", redirectTo: "/\r\nSet-Cookie: a=b", hasErr: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateRedirect(testCase.redirectTo)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+		})
+	}
+}
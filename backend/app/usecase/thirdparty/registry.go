@@ -0,0 +1,151 @@
+package thirdparty
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+const maxAppNameLength = 100
+
+var _ Registry = (*Persist)(nil)
+
+// ErrUnauthorizedAction represents the failure of managing an app not owned by
+// the user.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrAppNotFound represents the failure of finding an app with given ID.
+type ErrAppNotFound string
+
+var _ error = (*ErrAppNotFound)(nil)
+
+func (e ErrAppNotFound) Error() string {
+	return fmt.Sprintf("app(%s) not found", string(e))
+}
+
+// ErrInvalidAppName represents an app name which is empty or too long.
+type ErrInvalidAppName string
+
+var _ error = (*ErrInvalidAppName)(nil)
+
+func (e ErrInvalidAppName) Error() string {
+	return fmt.Sprintf("app name(%s) is invalid", string(e))
+}
+
+// Registry registers and manages third party apps on behalf of developers.
+type Registry interface {
+	CreateApp(name string, owner entity.User) (entity.App, error)
+	RenameApp(id string, name string, user entity.User) (entity.App, error)
+	GetAppsByOwner(owner entity.User) ([]entity.App, error)
+	DeleteApp(id string, user entity.User) error
+}
+
+// Persist registers third party apps in persistent data store.
+type Persist struct {
+	keyGen  keygen.KeyGenerator
+	timer   timer.Timer
+	appRepo repository.App
+}
+
+// CreateApp registers a new app owned by the given user.
+func (p Persist) CreateApp(name string, owner entity.User) (entity.App, error) {
+	name = strings.TrimSpace(name)
+	if !isAppNameValid(name) {
+		return entity.App{}, ErrInvalidAppName(name)
+	}
+
+	key, err := p.keyGen.NewKey()
+	if err != nil {
+		return entity.App{}, err
+	}
+
+	newApp := entity.App{
+		ID:        string(key),
+		Name:      name,
+		OwnerID:   owner.ID,
+		CreatedAt: p.timer.Now().UTC(),
+	}
+	return p.appRepo.CreateApp(newApp)
+}
+
+// RenameApp changes the name of an app owned by the given user.
+func (p Persist) RenameApp(id string, name string, user entity.User) (entity.App, error) {
+	name = strings.TrimSpace(name)
+	if !isAppNameValid(name) {
+		return entity.App{}, ErrInvalidAppName(name)
+	}
+
+	app, err := p.getOwnedApp(id, user, "rename")
+	if err != nil {
+		return entity.App{}, err
+	}
+
+	app.Name = name
+	return p.appRepo.UpdateApp(app)
+}
+
+// GetAppsByOwner retrieves all the apps owned by the given user.
+func (p Persist) GetAppsByOwner(owner entity.User) ([]entity.App, error) {
+	return p.appRepo.GetAppsByOwner(owner.ID)
+}
+
+// DeleteApp removes an app owned by the given user together with its API keys.
+func (p Persist) DeleteApp(id string, user entity.User) error {
+	_, err := p.getOwnedApp(id, user, "delete")
+	if err != nil {
+		return err
+	}
+	return p.appRepo.DeleteApp(id)
+}
+
+func (p Persist) getOwnedApp(id string, user entity.User, action string) (entity.App, error) {
+	app, err := p.appRepo.GetAppByID(id)
+	var notFound repository.ErrEntryNotFound
+	if errors.As(err, &notFound) {
+		return entity.App{}, ErrAppNotFound(id)
+	}
+	if err != nil {
+		return entity.App{}, err
+	}
+
+	if app.OwnerID != user.ID {
+		return entity.App{}, ErrUnauthorizedAction{
+			UserID: user.ID,
+			Action: fmt.Sprintf("%s app(%s)", action, id),
+		}
+	}
+	return app, nil
+}
+
+func isAppNameValid(name string) bool {
+	length := utf8.RuneCountInString(name)
+	return length > 0 && length <= maxAppNameLength
+}
+
+// NewPersist creates Persist.
+func NewPersist(
+	keyGen keygen.KeyGenerator,
+	timer timer.Timer,
+	appRepo repository.App,
+) Persist {
+	return Persist{
+		keyGen:  keyGen,
+		timer:   timer,
+		appRepo: appRepo,
+	}
+}
@@ -0,0 +1,248 @@
+// +build !integration all
+
+package thirdparty
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+func TestPersist_CreateApp(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-17T15:04:05Z")
+	testCases := []struct {
+		name          string
+		existingApps  []entity.App
+		availableKeys []keygen.Key
+		appName       string
+		owner         entity.User
+		hasErr        bool
+		expectedApp   entity.App
+	}{
+		{
+			name:          "empty app name",
+			availableKeys: []keygen.Key{"emotic"},
+			appName:       "  ",
+			owner:         entity.User{ID: "alpha"},
+			hasErr:        true,
+		},
+		{
+			name:          "app name too long",
+			availableKeys: []keygen.Key{"emotic"},
+			appName:       strings.Repeat("a", 101),
+			owner:         entity.User{ID: "alpha"},
+			hasErr:        true,
+		},
+		{
+			name:          "no available key",
+			availableKeys: []keygen.Key{},
+			appName:       "Feedback Widget",
+			owner:         entity.User{ID: "alpha"},
+			hasErr:        true,
+		},
+		{
+			name:          "app created",
+			availableKeys: []keygen.Key{"emotic"},
+			appName:       " Feedback Widget ",
+			owner:         entity.User{ID: "alpha"},
+			hasErr:        false,
+			expectedApp: entity.App{
+				ID:        "emotic",
+				Name:      "Feedback Widget",
+				OwnerID:   "alpha",
+				CreatedAt: now,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry, appRepo := newRegistry(t, testCase.existingApps, testCase.availableKeys, now)
+
+			gotApp, err := registry.CreateApp(testCase.appName, testCase.owner)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedApp, gotApp)
+
+			storedApp, err := appRepo.GetAppByID(gotApp.ID)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedApp, storedApp)
+		})
+	}
+}
+
+func TestPersist_RenameApp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		existingApps []entity.App
+		appID        string
+		appName      string
+		user         entity.User
+		hasErr       bool
+		expectedApp  entity.App
+	}{
+		{
+			name:         "app not found",
+			existingApps: []entity.App{},
+			appID:        "emotic",
+			appName:      "Emotic",
+			user:         entity.User{ID: "alpha"},
+			hasErr:       true,
+		},
+		{
+			name: "user is not the owner",
+			existingApps: []entity.App{
+				{ID: "emotic", Name: "Feedback Widget", OwnerID: "beta"},
+			},
+			appID:   "emotic",
+			appName: "Emotic",
+			user:    entity.User{ID: "alpha"},
+			hasErr:  true,
+		},
+		{
+			name: "invalid app name",
+			existingApps: []entity.App{
+				{ID: "emotic", Name: "Feedback Widget", OwnerID: "alpha"},
+			},
+			appID:   "emotic",
+			appName: "",
+			user:    entity.User{ID: "alpha"},
+			hasErr:  true,
+		},
+		{
+			name: "app renamed",
+			existingApps: []entity.App{
+				{ID: "emotic", Name: "Feedback Widget", OwnerID: "alpha"},
+			},
+			appID:   "emotic",
+			appName: "Emotic",
+			user:    entity.User{ID: "alpha"},
+			hasErr:  false,
+			expectedApp: entity.App{
+				ID:      "emotic",
+				Name:    "Emotic",
+				OwnerID: "alpha",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry, _ := newRegistry(t, testCase.existingApps, nil, time.Now())
+
+			gotApp, err := registry.RenameApp(testCase.appID, testCase.appName, testCase.user)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedApp, gotApp)
+		})
+	}
+}
+
+func TestPersist_GetAppsByOwner(t *testing.T) {
+	t.Parallel()
+
+	apps := []entity.App{
+		{ID: "emotic", Name: "Feedback Widget", OwnerID: "alpha"},
+		{ID: "chatbot", Name: "Chat Bot", OwnerID: "beta"},
+		{ID: "gallery", Name: "Photo Gallery", OwnerID: "alpha"},
+	}
+	registry, _ := newRegistry(t, apps, nil, time.Now())
+
+	gotApps, err := registry.GetAppsByOwner(entity.User{ID: "alpha"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []entity.App{apps[0], apps[2]}, gotApps)
+}
+
+func TestPersist_DeleteApp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		existingApps []entity.App
+		appID        string
+		user         entity.User
+		hasErr       bool
+	}{
+		{
+			name:         "app not found",
+			existingApps: []entity.App{},
+			appID:        "emotic",
+			user:         entity.User{ID: "alpha"},
+			hasErr:       true,
+		},
+		{
+			name: "user is not the owner",
+			existingApps: []entity.App{
+				{ID: "emotic", OwnerID: "beta"},
+			},
+			appID:  "emotic",
+			user:   entity.User{ID: "alpha"},
+			hasErr: true,
+		},
+		{
+			name: "app deleted",
+			existingApps: []entity.App{
+				{ID: "emotic", OwnerID: "alpha"},
+			},
+			appID:  "emotic",
+			user:   entity.User{ID: "alpha"},
+			hasErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry, appRepo := newRegistry(t, testCase.existingApps, nil, time.Now())
+
+			err := registry.DeleteApp(testCase.appID, testCase.user)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			_, err = appRepo.GetAppByID(testCase.appID)
+			assert.NotEqual(t, nil, err)
+		})
+	}
+}
+
+func newRegistry(
+	t *testing.T,
+	apps []entity.App,
+	availableKeys []keygen.Key,
+	now time.Time,
+) (Persist, *repository.AppFake) {
+	keyFetcher := keygen.NewKeyFetcherFake(availableKeys)
+	keyGen, err := keygen.NewKeyGenerator(2, &keyFetcher)
+	assert.Equal(t, nil, err)
+
+	appRepo := repository.NewAppFake(apps)
+	return NewPersist(keyGen, timer.NewStub(now), &appRepo), &appRepo
+}
@@ -0,0 +1,96 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// The parameters below are the defaults of RFC 6238, which are supported by
+// all the popular authenticator apps.
+const (
+	timeStepSize = 30 * time.Second
+	codeDigits   = 6
+	secretSize   = 20
+	// allowedSkew is the number of time steps a password can drift away from
+	// the current one, tolerating clock differences.
+	allowedSkew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func newSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(buf), nil
+}
+
+func getTimeStep(now time.Time) int64 {
+	return now.Unix() / int64(timeStepSize/time.Second)
+}
+
+// generateCode computes the password of the given time step defined by RFC
+// 4226 and RFC 6238.
+func generateCode(key []byte, timeStep int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(timeStep))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for idx := 0; idx < codeDigits; idx++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", codeDigits, value%modulo)
+}
+
+// matchCode finds the time step of the given password around the current
+// time.
+func matchCode(secret string, code string, now time.Time) (int64, bool) {
+	key, err := secretEncoding.DecodeString(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	currentStep := getTimeStep(now)
+	for skew := -allowedSkew; skew <= allowedSkew; skew++ {
+		timeStep := currentStep + int64(skew)
+		expectedCode := generateCode(key, timeStep)
+		if subtle.ConstantTimeCompare([]byte(expectedCode), []byte(code)) == 1 {
+			return timeStep, true
+		}
+	}
+	return 0, false
+}
+
+// getProvisioningURI builds the otpauth URI understood by authenticator apps,
+// labeled with the account name under the issuer.
+func getProvisioningURI(issuer string, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", codeDigits))
+	query.Set("period", fmt.Sprintf("%d", int(timeStepSize/time.Second)))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     fmt.Sprintf("/%s:%s", issuer, accountName),
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
@@ -0,0 +1,76 @@
+// +build !integration all
+
+package twofactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestGenerateCode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6238 appendix B, truncated to 6 digits.
+	key := []byte("12345678901234567890")
+	testCases := []struct {
+		name         string
+		unixTime     int64
+		expectedCode string
+	}{
+		{name: "59", unixTime: 59, expectedCode: "287082"},
+		{name: "1111111109", unixTime: 1111111109, expectedCode: "081804"},
+		{name: "1111111111", unixTime: 1111111111, expectedCode: "050471"},
+		{name: "1234567890", unixTime: 1234567890, expectedCode: "005924"},
+		{name: "2000000000", unixTime: 2000000000, expectedCode: "279037"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeStep := getTimeStep(time.Unix(testCase.unixTime, 0))
+			assert.Equal(t, testCase.expectedCode, generateCode(key, timeStep))
+		})
+	}
+}
+
+func TestMatchCode(t *testing.T) {
+	t.Parallel()
+
+	secret := secretEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	testCases := []struct {
+		name             string
+		code             string
+		expectedIsMatch  bool
+		expectedTimeStep int64
+	}{
+		{name: "current time step", code: "050471", expectedIsMatch: true, expectedTimeStep: 37037037},
+		{name: "previous time step", code: generateCode([]byte("12345678901234567890"), 37037036), expectedIsMatch: true, expectedTimeStep: 37037036},
+		{name: "next time step", code: generateCode([]byte("12345678901234567890"), 37037038), expectedIsMatch: true, expectedTimeStep: 37037038},
+		{name: "too old", code: generateCode([]byte("12345678901234567890"), 37037035), expectedIsMatch: false},
+		{name: "wrong code", code: "123456", expectedIsMatch: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeStep, isMatch := matchCode(secret, testCase.code, now)
+			assert.Equal(t, testCase.expectedIsMatch, isMatch)
+			assert.Equal(t, testCase.expectedTimeStep, timeStep)
+		})
+	}
+}
+
+func TestGetProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri := getProvisioningURI("Short", "alpha@example.com", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/Short:alpha@example.com?algorithm=SHA1&digits=6&issuer=Short&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
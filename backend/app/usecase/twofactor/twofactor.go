@@ -0,0 +1,504 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// ChallengeLifetime is how long the user has to enter the second factor after
+// signing in with the first one.
+const ChallengeLifetime = 5 * time.Minute
+
+const (
+	issuer            = "Short"
+	challengePurpose  = "two_factor_challenge"
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10
+)
+
+// attemptLimit slows down guessing the 6 digit passwords.
+var attemptLimit = ratelimit.Limit{Requests: 5, Window: 5 * time.Minute}
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// ErrSecondFactorRequired represents a sign in which has to be completed with
+// a second factor. MustEnroll is set when the user is required to enroll a
+// second factor before signing in.
+type ErrSecondFactorRequired struct {
+	Challenge  string
+	MustEnroll bool
+}
+
+var _ error = (*ErrSecondFactorRequired)(nil)
+
+func (e ErrSecondFactorRequired) Error() string {
+	if e.MustEnroll {
+		return "enrolling a second factor is required to sign in"
+	}
+	return "second factor is required to sign in"
+}
+
+// ErrInvalidChallenge represents a challenge which is forged or expired.
+type ErrInvalidChallenge struct {
+	Reason string
+}
+
+var _ error = (*ErrInvalidChallenge)(nil)
+
+func (e ErrInvalidChallenge) Error() string {
+	return fmt.Sprintf("invalid two factor challenge: %s", e.Reason)
+}
+
+// ErrInvalidCode represents a password or recovery code which is wrong or
+// already used.
+type ErrInvalidCode struct{}
+
+var _ error = (*ErrInvalidCode)(nil)
+
+func (e ErrInvalidCode) Error() string {
+	return "invalid two factor code"
+}
+
+// ErrTooManyAttempts represents the failure of entering wrong codes too
+// many times in a short period of time.
+type ErrTooManyAttempts struct {
+	RetryAfter time.Duration
+}
+
+var _ error = (*ErrTooManyAttempts)(nil)
+
+func (e ErrTooManyAttempts) Error() string {
+	return fmt.Sprintf("too many two factor attempts, retry after %s", e.RetryAfter)
+}
+
+// ErrAlreadyEnabled represents the failure of enrolling a second factor
+// when the user already has one.
+type ErrAlreadyEnabled string
+
+var _ error = (*ErrAlreadyEnabled)(nil)
+
+func (e ErrAlreadyEnabled) Error() string {
+	return fmt.Sprintf("user(%s) already enabled two factor", string(e))
+}
+
+// ErrNotEnrolled represents the failure of confirming or disabling a second
+// factor which the user never enrolled.
+type ErrNotEnrolled string
+
+var _ error = (*ErrNotEnrolled)(nil)
+
+func (e ErrNotEnrolled) Error() string {
+	return fmt.Sprintf("user(%s) didn't enroll two factor", string(e))
+}
+
+// ErrRequiredByRole represents the failure of disabling the second factor
+// required by the roles of the user.
+type ErrRequiredByRole string
+
+var _ error = (*ErrRequiredByRole)(nil)
+
+func (e ErrRequiredByRole) Error() string {
+	return fmt.Sprintf("two factor is required by the roles of user(%s)", string(e))
+}
+
+// challenge represents the pending sign in waiting for the second factor.
+type challenge struct {
+	user             entity.User
+	identityProvider string
+	device           entity.Device
+	mustEnroll       bool
+}
+
+// TwoFactor adds time-based one-time passwords (TOTP) as the second factor
+// to sign in. Users can enroll voluntarily, while users assigned the roles
+// required by the policy must enroll once the grace period is over.
+type TwoFactor struct {
+	twoFactorRepo repository.TwoFactor
+	userRepo      repository.User
+	rbac          rbac.RBAC
+	policy        rbac.TwoFactorPolicy
+	limiter       ratelimit.Limiter
+	authenticator authenticator.Authenticator
+	tokenizer     crypto.Tokenizer
+	timer         timer.Timer
+}
+
+// SignIn starts a new session for the user verified by the first factor,
+// unless a second factor is required, in which case it returns
+// ErrSecondFactorRequired with a challenge to complete the sign in.
+func (t TwoFactor) SignIn(
+	user entity.User,
+	identityProvider string,
+	device entity.Device,
+) (authenticator.AuthTokens, error) {
+	isEnabled, err := t.isEnabled(user)
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+
+	mustEnroll := false
+	if !isEnabled {
+		isRequired, err := t.rbac.RequiresTwoFactor(user, t.policy)
+		if err != nil {
+			return authenticator.AuthTokens{}, err
+		}
+		mustEnroll = isRequired && t.policy.IsEnforced(t.timer.Now())
+		if !mustEnroll {
+			return t.authenticator.SignIn(user, identityProvider, device)
+		}
+	}
+
+	challengeToken, err := t.issueChallenge(challenge{
+		user:             user,
+		identityProvider: identityProvider,
+		device:           device,
+		mustEnroll:       mustEnroll,
+	})
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+	return authenticator.AuthTokens{}, ErrSecondFactorRequired{
+		Challenge:  challengeToken,
+		MustEnroll: mustEnroll,
+	}
+}
+
+// CompleteSignIn verifies the password or the recovery code for the
+// challenge and starts a new session. For the users required to enroll, the
+// password confirms the enrollment started with EnrollAtSignIn.
+func (t TwoFactor) CompleteSignIn(challengeToken string, code string) (authenticator.AuthTokens, error) {
+	pending, err := t.verifyChallenge(challengeToken)
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+
+	if pending.mustEnroll {
+		err = t.ConfirmEnrollment(pending.user, code)
+	} else {
+		err = t.verifyCode(pending.user, code)
+	}
+	if err != nil {
+		return authenticator.AuthTokens{}, err
+	}
+	return t.authenticator.SignIn(pending.user, pending.identityProvider, pending.device)
+}
+
+// Enroll generates a new secret and recovery codes for the user. The second
+// factor is enabled after the user confirms it with a valid password.
+func (t TwoFactor) Enroll(user entity.User) (entity.TwoFactorEnrollment, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return entity.TwoFactorEnrollment{}, err
+	}
+
+	recoveryCodes := make([]string, recoveryCodeCount)
+	codeHashes := make([]string, recoveryCodeCount)
+	for idx := range recoveryCodes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return entity.TwoFactorEnrollment{}, err
+		}
+		recoveryCodes[idx] = code
+		codeHashes[idx] = hashRecoveryCode(code)
+	}
+
+	twoFactor := entity.TwoFactor{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: t.timer.Now().UTC(),
+	}
+	err = t.twoFactorRepo.CreateTwoFactor(twoFactor, codeHashes)
+	var errExists repository.ErrEntryExists
+	if errors.As(err, &errExists) {
+		return entity.TwoFactorEnrollment{}, ErrAlreadyEnabled(user.ID)
+	}
+	if err != nil {
+		return entity.TwoFactorEnrollment{}, err
+	}
+
+	accountName, err := t.getAccountName(user)
+	if err != nil {
+		return entity.TwoFactorEnrollment{}, err
+	}
+	return entity.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: getProvisioningURI(issuer, accountName, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+// EnrollAtSignIn starts enrolling the second factor for the user who is
+// required to enroll before signing in.
+func (t TwoFactor) EnrollAtSignIn(challengeToken string) (entity.TwoFactorEnrollment, error) {
+	pending, err := t.verifyChallenge(challengeToken)
+	if err != nil {
+		return entity.TwoFactorEnrollment{}, err
+	}
+	if !pending.mustEnroll {
+		return entity.TwoFactorEnrollment{}, ErrAlreadyEnabled(pending.user.ID)
+	}
+	return t.Enroll(pending.user)
+}
+
+// ConfirmEnrollment enables the second factor enrolled by the user with a
+// valid password from the authenticator app.
+func (t TwoFactor) ConfirmEnrollment(user entity.User, code string) error {
+	twoFactor, err := t.getTwoFactor(user)
+	if err != nil {
+		return err
+	}
+	if twoFactor.IsEnabled() {
+		return ErrAlreadyEnabled(user.ID)
+	}
+
+	err = t.allowAttempt(user)
+	if err != nil {
+		return err
+	}
+	err = t.verifyPassword(twoFactor, code)
+	if err != nil {
+		return err
+	}
+	return t.twoFactorRepo.EnableTwoFactor(user.ID, t.timer.Now().UTC())
+}
+
+// Disable removes the second factor of the user after verifying a password
+// or a recovery code. It fails if the roles of the user require one.
+func (t TwoFactor) Disable(user entity.User, code string) error {
+	isRequired, err := t.rbac.RequiresTwoFactor(user, t.policy)
+	if err != nil {
+		return err
+	}
+	if isRequired {
+		return ErrRequiredByRole(user.ID)
+	}
+
+	err = t.verifyCode(user, code)
+	if err != nil {
+		return err
+	}
+	return t.twoFactorRepo.DeleteTwoFactor(user.ID)
+}
+
+// GetStatus retrieves whether the user signs in with a second factor and
+// whether the roles of the user require one.
+func (t TwoFactor) GetStatus(user entity.User) (entity.TwoFactorStatus, error) {
+	isEnabled, err := t.isEnabled(user)
+	if err != nil {
+		return entity.TwoFactorStatus{}, err
+	}
+	isRequired, err := t.rbac.RequiresTwoFactor(user, t.policy)
+	if err != nil {
+		return entity.TwoFactorStatus{}, err
+	}
+
+	status := entity.TwoFactorStatus{
+		IsEnabled:  isEnabled,
+		IsRequired: isRequired,
+	}
+	if isRequired {
+		enforceFrom := t.policy.EnforceFrom
+		status.EnforceFrom = &enforceFrom
+	}
+	return status, nil
+}
+
+// verifyCode accepts either a password from the authenticator app or an
+// unused recovery code of the user's enabled second factor.
+func (t TwoFactor) verifyCode(user entity.User, code string) error {
+	twoFactor, err := t.getTwoFactor(user)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return ErrNotEnrolled(user.ID)
+	}
+
+	err = t.allowAttempt(user)
+	if err != nil {
+		return err
+	}
+
+	code = normalizeCode(code)
+	if len(code) == codeDigits {
+		return t.verifyPassword(twoFactor, code)
+	}
+
+	err = t.twoFactorRepo.UseRecoveryCode(user.ID, hashRecoveryCode(code), t.timer.Now().UTC())
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return ErrInvalidCode{}
+	}
+	return err
+}
+
+func (t TwoFactor) verifyPassword(twoFactor entity.TwoFactor, code string) error {
+	timeStep, ok := matchCode(twoFactor.Secret, normalizeCode(code), t.timer.Now())
+	if !ok {
+		return ErrInvalidCode{}
+	}
+
+	err := t.twoFactorRepo.UseTimeStep(twoFactor.UserID, timeStep)
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return ErrInvalidCode{}
+	}
+	return err
+}
+
+func (t TwoFactor) allowAttempt(user entity.User) error {
+	decision, err := t.limiter.Allow(fmt.Sprintf("two-factor:%s", user.ID), attemptLimit)
+	if err != nil {
+		return err
+	}
+	if !decision.IsAllowed {
+		return ErrTooManyAttempts{RetryAfter: decision.RetryAfter}
+	}
+	return nil
+}
+
+func (t TwoFactor) isEnabled(user entity.User) (bool, error) {
+	twoFactor, err := t.twoFactorRepo.GetTwoFactor(user.ID)
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return twoFactor.IsEnabled(), nil
+}
+
+func (t TwoFactor) getTwoFactor(user entity.User) (entity.TwoFactor, error) {
+	twoFactor, err := t.twoFactorRepo.GetTwoFactor(user.ID)
+	var errNotFound repository.ErrEntryNotFound
+	if errors.As(err, &errNotFound) {
+		return entity.TwoFactor{}, ErrNotEnrolled(user.ID)
+	}
+	return twoFactor, err
+}
+
+// getAccountName labels the secret in the authenticator app with the email
+// of the user when available.
+func (t TwoFactor) getAccountName(user entity.User) (string, error) {
+	fullUser, err := t.userRepo.GetUserByID(user.ID)
+	if err != nil {
+		return "", err
+	}
+	if fullUser.Email != "" {
+		return fullUser.Email, nil
+	}
+	return fullUser.ID, nil
+}
+
+func (t TwoFactor) issueChallenge(pending challenge) (string, error) {
+	return t.tokenizer.Encode(crypto.TokenPayload{
+		"purpose":           challengePurpose,
+		"user_id":           pending.user.ID,
+		"identity_provider": pending.identityProvider,
+		"client_ip":         pending.device.ClientIP,
+		"location":          pending.device.Location,
+		"user_agent":        pending.device.UserAgent,
+		"must_enroll":       pending.mustEnroll,
+		"expire_at":         t.timer.Now().Add(ChallengeLifetime).UTC().Format(time.RFC3339),
+	})
+}
+
+func (t TwoFactor) verifyChallenge(challengeToken string) (challenge, error) {
+	payload, err := t.tokenizer.Decode(challengeToken)
+	if err != nil {
+		return challenge{}, ErrInvalidChallenge{Reason: "signature mismatch"}
+	}
+	if payload["purpose"] != challengePurpose {
+		return challenge{}, ErrInvalidChallenge{Reason: "issued for another purpose"}
+	}
+
+	expireAtStr, _ := payload["expire_at"].(string)
+	expireAt, err := time.Parse(time.RFC3339, expireAtStr)
+	if err != nil {
+		return challenge{}, ErrInvalidChallenge{Reason: "expiration malformed"}
+	}
+	if !t.timer.Now().Before(expireAt) {
+		return challenge{}, ErrInvalidChallenge{Reason: "challenge expired"}
+	}
+
+	userID, _ := payload["user_id"].(string)
+	if userID == "" {
+		return challenge{}, ErrInvalidChallenge{Reason: "user missing"}
+	}
+	identityProvider, _ := payload["identity_provider"].(string)
+	clientIP, _ := payload["client_ip"].(string)
+	location, _ := payload["location"].(string)
+	userAgent, _ := payload["user_agent"].(string)
+	mustEnroll, _ := payload["must_enroll"].(bool)
+	return challenge{
+		user:             entity.User{ID: userID},
+		identityProvider: identityProvider,
+		device: entity.Device{
+			ClientIP:  clientIP,
+			Location:  location,
+			UserAgent: userAgent,
+		},
+		mustEnroll: mustEnroll,
+	}, nil
+}
+
+// newRecoveryCode generates a code formatted as xxxxx-xxxxx for readability.
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeSize*5/8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := recoveryCodeEncoding.EncodeToString(buf)
+	half := len(code) / 2
+	return fmt.Sprintf("%s-%s", code[:half], code[half:]), nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCode removes the separators and spaces users may type along with
+// the codes.
+func normalizeCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// NewTwoFactor creates TwoFactor.
+func NewTwoFactor(
+	twoFactorRepo repository.TwoFactor,
+	userRepo repository.User,
+	rbac rbac.RBAC,
+	policy rbac.TwoFactorPolicy,
+	limiter ratelimit.Limiter,
+	authenticator authenticator.Authenticator,
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+) TwoFactor {
+	return TwoFactor{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		rbac:          rbac,
+		policy:        policy,
+		limiter:       limiter,
+		authenticator: authenticator,
+		tokenizer:     tokenizer,
+		timer:         timer,
+	}
+}
@@ -0,0 +1,35 @@
+package twofactor
+
+import (
+	"time"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// NewTwoFactorFake creates fake TwoFactor for easy testing. No user has a
+// second factor and no role requires one, so it signs users in directly with
+// the given authenticator.
+func NewTwoFactorFake(current time.Time, authenticator authenticator.Authenticator) TwoFactor {
+	twoFactorRepo := repository.NewTwoFactorFake([]entity.TwoFactor{})
+	userRepo := repository.NewUserFake([]entity.User{})
+	userRoleRepo := repository.NewUserRoleFake(map[string][]role.Role{})
+	tm := timer.NewStub(current)
+
+	return NewTwoFactor(
+		&twoFactorRepo,
+		&userRepo,
+		rbac.NewRBAC(userRoleRepo),
+		rbac.TwoFactorPolicy{},
+		ratelimit.NewLimiter(ratelimit.NewMemoryStore(tm), tm),
+		authenticator,
+		crypto.NewTokenizerFake(),
+		tm,
+	)
+}
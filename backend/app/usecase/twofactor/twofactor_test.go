@@ -0,0 +1,474 @@
+// +build !integration all
+
+package twofactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+const testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTwoFactorFake(
+	t *testing.T,
+	now time.Time,
+	twoFactors []entity.TwoFactor,
+	userRoles map[string][]role.Role,
+	policy rbac.TwoFactorPolicy,
+) (TwoFactor, authenticator.Authenticator) {
+	twoFactorRepo := repository.NewTwoFactorFake(twoFactors)
+	userRepo := repository.NewUserFake([]entity.User{
+		{ID: "alpha", Email: "alpha@example.com"},
+		{ID: "beta"},
+	})
+	userRoleRepo := repository.NewUserRoleFake(userRoles)
+
+	tm := timer.NewStub(now)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(tm), tm)
+	auth := authenticator.NewAuthenticatorFake(now, time.Hour)
+
+	twoFactor := NewTwoFactor(
+		&twoFactorRepo,
+		&userRepo,
+		rbac.NewRBAC(userRoleRepo),
+		policy,
+		limiter,
+		auth,
+		crypto.NewTokenizerFake(),
+		tm,
+	)
+	return twoFactor, auth
+}
+
+func getCode(t *testing.T, secret string, now time.Time) string {
+	key, err := secretEncoding.DecodeString(secret)
+	assert.Equal(t, nil, err)
+	return generateCode(key, getTimeStep(now))
+}
+
+func TestTwoFactor_SignIn(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	enabledAt := must.Time(t, "2020-07-01T10:00:00Z")
+	user := entity.User{ID: "alpha"}
+	device := entity.Device{ClientIP: "10.0.0.1", UserAgent: "Mozilla/5.0"}
+
+	testCases := []struct {
+		name               string
+		twoFactors         []entity.TwoFactor
+		userRoles          map[string][]role.Role
+		policy             rbac.TwoFactorPolicy
+		expectedIsRequired bool
+		expectedMustEnroll bool
+	}{
+		{
+			name:       "not enrolled",
+			twoFactors: []entity.TwoFactor{},
+			userRoles:  map[string][]role.Role{"alpha": {role.Basic}},
+			policy: rbac.TwoFactorPolicy{
+				Roles: []role.Role{role.Admin},
+			},
+			expectedIsRequired: false,
+		},
+		{
+			name: "enrollment pending",
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", Secret: testSecret},
+			},
+			userRoles: map[string][]role.Role{"alpha": {role.Basic}},
+			policy: rbac.TwoFactorPolicy{
+				Roles: []role.Role{role.Admin},
+			},
+			expectedIsRequired: false,
+		},
+		{
+			name: "enabled",
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", Secret: testSecret, EnabledAt: &enabledAt},
+			},
+			userRoles: map[string][]role.Role{"alpha": {role.Basic}},
+			policy: rbac.TwoFactorPolicy{
+				Roles: []role.Role{role.Admin},
+			},
+			expectedIsRequired: true,
+			expectedMustEnroll: false,
+		},
+		{
+			name:       "required during grace period",
+			twoFactors: []entity.TwoFactor{},
+			userRoles:  map[string][]role.Role{"alpha": {role.Admin}},
+			policy: rbac.TwoFactorPolicy{
+				Roles:       []role.Role{role.Admin},
+				EnforceFrom: now.Add(time.Hour),
+			},
+			expectedIsRequired: false,
+		},
+		{
+			name:       "required after grace period",
+			twoFactors: []entity.TwoFactor{},
+			userRoles:  map[string][]role.Role{"alpha": {role.Admin}},
+			policy: rbac.TwoFactorPolicy{
+				Roles:       []role.Role{role.Admin},
+				EnforceFrom: now,
+			},
+			expectedIsRequired: true,
+			expectedMustEnroll: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			twoFactor, auth := newTwoFactorFake(t, now, testCase.twoFactors, testCase.userRoles, testCase.policy)
+
+			authTokens, err := twoFactor.SignIn(user, "github", device)
+			if !testCase.expectedIsRequired {
+				assert.Equal(t, nil, err)
+				gotUser, err := auth.GetUser(authTokens.AccessToken)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, user.ID, gotUser.ID)
+				return
+			}
+
+			errRequired, ok := err.(ErrSecondFactorRequired)
+			assert.Equal(t, true, ok)
+			assert.Equal(t, testCase.expectedMustEnroll, errRequired.MustEnroll)
+			assert.Equal(t, authenticator.AuthTokens{}, authTokens)
+
+			pending, err := twoFactor.verifyChallenge(errRequired.Challenge)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, challenge{
+				user:             user,
+				identityProvider: "github",
+				device:           device,
+				mustEnroll:       testCase.expectedMustEnroll,
+			}, pending)
+		})
+	}
+}
+
+func TestTwoFactor_Enroll(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	user := entity.User{ID: "alpha"}
+	twoFactor, _ := newTwoFactorFake(t, now, []entity.TwoFactor{}, map[string][]role.Role{}, rbac.TwoFactorPolicy{})
+
+	err := twoFactor.ConfirmEnrollment(user, "123456")
+	assert.Equal(t, ErrNotEnrolled("alpha"), err)
+
+	_, err = twoFactor.Enroll(user)
+	assert.Equal(t, nil, err)
+	enrollment, err := twoFactor.Enroll(user)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, recoveryCodeCount, len(enrollment.RecoveryCodes))
+	assert.Equal(t, getProvisioningURI("Short", "alpha@example.com", enrollment.Secret), enrollment.ProvisioningURI)
+
+	status, err := twoFactor.GetStatus(user)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, entity.TwoFactorStatus{IsEnabled: false}, status)
+
+	err = twoFactor.ConfirmEnrollment(user, "abcdef")
+	assert.Equal(t, ErrInvalidCode{}, err)
+
+	code := getCode(t, enrollment.Secret, now)
+	err = twoFactor.ConfirmEnrollment(user, code)
+	assert.Equal(t, nil, err)
+
+	status, err = twoFactor.GetStatus(user)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, entity.TwoFactorStatus{IsEnabled: true}, status)
+
+	err = twoFactor.ConfirmEnrollment(user, code)
+	assert.Equal(t, ErrAlreadyEnabled("alpha"), err)
+	_, err = twoFactor.Enroll(user)
+	assert.Equal(t, ErrAlreadyEnabled("alpha"), err)
+}
+
+func TestTwoFactor_CompleteSignIn(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	user := entity.User{ID: "alpha"}
+
+	testCases := []struct {
+		name           string
+		code           func(t *testing.T, enrollment entity.TwoFactorEnrollment) string
+		challenge      func(t *testing.T, twoFactor TwoFactor) string
+		elapsed        time.Duration
+		hasErr         bool
+		expectedErr    error
+		expectedUserID string
+	}{
+		{
+			name: "malformed challenge",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return getCode(t, enrollment.Secret, now)
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				return "malformed"
+			},
+			hasErr: true,
+		},
+		{
+			name: "issued for another purpose",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return getCode(t, enrollment.Secret, now)
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				token, err := crypto.NewTokenizerFake().Encode(crypto.TokenPayload{
+					"purpose":   "email_sign_in",
+					"user_id":   "alpha",
+					"expire_at": "2020-07-20T10:05:00Z",
+				})
+				assert.Equal(t, nil, err)
+				return token
+			},
+			hasErr: true,
+		},
+		{
+			name: "challenge expired",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return getCode(t, enrollment.Secret, now.Add(ChallengeLifetime))
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				_, err := twoFactor.SignIn(user, "github", entity.Device{})
+				return err.(ErrSecondFactorRequired).Challenge
+			},
+			elapsed: ChallengeLifetime,
+			hasErr:  true,
+		},
+		{
+			name: "wrong code",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return "abcde-fghij"
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				_, err := twoFactor.SignIn(user, "github", entity.Device{})
+				return err.(ErrSecondFactorRequired).Challenge
+			},
+			hasErr:      true,
+			expectedErr: ErrInvalidCode{},
+		},
+		{
+			name: "password",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return getCode(t, enrollment.Secret, now.Add(time.Minute))
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				_, err := twoFactor.SignIn(user, "github", entity.Device{})
+				return err.(ErrSecondFactorRequired).Challenge
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "alpha",
+		},
+		{
+			name: "recovery code",
+			code: func(t *testing.T, enrollment entity.TwoFactorEnrollment) string {
+				return enrollment.RecoveryCodes[3]
+			},
+			challenge: func(t *testing.T, twoFactor TwoFactor) string {
+				_, err := twoFactor.SignIn(user, "github", entity.Device{})
+				return err.(ErrSecondFactorRequired).Challenge
+			},
+			elapsed:        time.Minute,
+			hasErr:         false,
+			expectedUserID: "alpha",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			twoFactor, auth := newTwoFactorFake(t, now, []entity.TwoFactor{}, map[string][]role.Role{}, rbac.TwoFactorPolicy{})
+			enrollment, err := twoFactor.Enroll(user)
+			assert.Equal(t, nil, err)
+			err = twoFactor.ConfirmEnrollment(user, getCode(t, enrollment.Secret, now))
+			assert.Equal(t, nil, err)
+
+			challengeToken := testCase.challenge(t, twoFactor)
+			code := testCase.code(t, enrollment)
+
+			twoFactor.timer = timer.NewStub(now.Add(testCase.elapsed))
+			authTokens, err := twoFactor.CompleteSignIn(challengeToken, code)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				if testCase.expectedErr != nil {
+					assert.Equal(t, testCase.expectedErr, err)
+				}
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			gotUser, err := auth.GetUser(authTokens.AccessToken)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedUserID, gotUser.ID)
+
+			_, err = twoFactor.CompleteSignIn(challengeToken, code)
+			assert.Equal(t, ErrInvalidCode{}, err)
+		})
+	}
+}
+
+func TestTwoFactor_EnrollAtSignIn(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	user := entity.User{ID: "beta"}
+	policy := rbac.TwoFactorPolicy{
+		Roles:       []role.Role{role.Admin},
+		EnforceFrom: now,
+	}
+	userRoles := map[string][]role.Role{"beta": {role.Admin}}
+	twoFactor, auth := newTwoFactorFake(t, now, []entity.TwoFactor{}, userRoles, policy)
+
+	_, err := twoFactor.SignIn(user, "google", entity.Device{})
+	errRequired, ok := err.(ErrSecondFactorRequired)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, errRequired.MustEnroll)
+
+	enrollment, err := twoFactor.EnrollAtSignIn(errRequired.Challenge)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, getProvisioningURI("Short", "beta", enrollment.Secret), enrollment.ProvisioningURI)
+
+	authTokens, err := twoFactor.CompleteSignIn(errRequired.Challenge, getCode(t, enrollment.Secret, now))
+	assert.Equal(t, nil, err)
+	gotUser, err := auth.GetUser(authTokens.AccessToken)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "beta", gotUser.ID)
+
+	status, err := twoFactor.GetStatus(user)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, entity.TwoFactorStatus{
+		IsEnabled:   true,
+		IsRequired:  true,
+		EnforceFrom: &now,
+	}, status)
+
+	_, err = twoFactor.SignIn(user, "google", entity.Device{})
+	errRequired, ok = err.(ErrSecondFactorRequired)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, false, errRequired.MustEnroll)
+
+	_, err = twoFactor.EnrollAtSignIn(errRequired.Challenge)
+	assert.Equal(t, ErrAlreadyEnabled("beta"), err)
+}
+
+func TestTwoFactor_Disable(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	enabledAt := must.Time(t, "2020-07-01T10:00:00Z")
+	policy := rbac.TwoFactorPolicy{
+		Roles:       []role.Role{role.Admin},
+		EnforceFrom: now.Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name              string
+		user              entity.User
+		twoFactors        []entity.TwoFactor
+		userRoles         map[string][]role.Role
+		code              string
+		expectedErr       error
+		expectedIsEnabled bool
+	}{
+		{
+			name:        "not enrolled",
+			user:        entity.User{ID: "alpha"},
+			twoFactors:  []entity.TwoFactor{},
+			userRoles:   map[string][]role.Role{},
+			code:        getCode(t, testSecret, now),
+			expectedErr: ErrNotEnrolled("alpha"),
+		},
+		{
+			name: "required by role",
+			user: entity.User{ID: "alpha"},
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", Secret: testSecret, EnabledAt: &enabledAt},
+			},
+			userRoles:         map[string][]role.Role{"alpha": {role.Admin}},
+			code:              getCode(t, testSecret, now),
+			expectedErr:       ErrRequiredByRole("alpha"),
+			expectedIsEnabled: true,
+		},
+		{
+			name: "wrong code",
+			user: entity.User{ID: "alpha"},
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", Secret: testSecret, EnabledAt: &enabledAt},
+			},
+			userRoles:         map[string][]role.Role{"alpha": {role.Basic}},
+			code:              "000000",
+			expectedErr:       ErrInvalidCode{},
+			expectedIsEnabled: true,
+		},
+		{
+			name: "disabled",
+			user: entity.User{ID: "alpha"},
+			twoFactors: []entity.TwoFactor{
+				{UserID: "alpha", Secret: testSecret, EnabledAt: &enabledAt},
+			},
+			userRoles:         map[string][]role.Role{"alpha": {role.Basic}},
+			code:              getCode(t, testSecret, now),
+			expectedErr:       nil,
+			expectedIsEnabled: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			twoFactor, _ := newTwoFactorFake(t, now, testCase.twoFactors, testCase.userRoles, policy)
+
+			err := twoFactor.Disable(testCase.user, testCase.code)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			status, err := twoFactor.GetStatus(testCase.user)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedIsEnabled, status.IsEnabled)
+		})
+	}
+}
+
+func TestTwoFactor_TooManyAttempts(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-20T10:00:00Z")
+	enabledAt := must.Time(t, "2020-07-01T10:00:00Z")
+	user := entity.User{ID: "alpha"}
+	twoFactors := []entity.TwoFactor{
+		{UserID: "alpha", Secret: testSecret, EnabledAt: &enabledAt},
+	}
+	twoFactor, _ := newTwoFactorFake(t, now, twoFactors, map[string][]role.Role{}, rbac.TwoFactorPolicy{})
+
+	_, err := twoFactor.SignIn(user, "github", entity.Device{})
+	challengeToken := err.(ErrSecondFactorRequired).Challenge
+
+	for attempt := 0; attempt < attemptLimit.Requests; attempt++ {
+		_, err = twoFactor.CompleteSignIn(challengeToken, "000000")
+		assert.Equal(t, ErrInvalidCode{}, err)
+	}
+
+	_, err = twoFactor.CompleteSignIn(challengeToken, getCode(t, testSecret, now))
+	assert.Equal(t, ErrTooManyAttempts{RetryAfter: time.Minute}, err)
+}
@@ -0,0 +1,264 @@
+package userrole
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+// MaxUsersPerPage is the maximum number of users listed at once.
+const MaxUsersPerPage = 100
+
+// ErrUnauthorizedAction represents the failure of managing roles without the
+// required permission.
+type ErrUnauthorizedAction struct {
+	UserID string
+	Action string
+}
+
+var _ error = (*ErrUnauthorizedAction)(nil)
+
+func (e ErrUnauthorizedAction) Error() string {
+	return fmt.Sprintf("user(%s) is not allowed to %s", e.UserID, e.Action)
+}
+
+// ErrUserNotFound represents the failure of finding an user with given ID.
+type ErrUserNotFound string
+
+var _ error = (*ErrUserNotFound)(nil)
+
+func (e ErrUserNotFound) Error() string {
+	return fmt.Sprintf("user(%s) not found", string(e))
+}
+
+// ErrUnknownRole represents the failure of managing a role which is not
+// defined.
+type ErrUnknownRole string
+
+var _ error = (*ErrUnknownRole)(nil)
+
+func (e ErrUnknownRole) Error() string {
+	return fmt.Sprintf("role(%s) is unknown", string(e))
+}
+
+// ErrRoleAlreadyGranted represents the failure of granting a role which the
+// user already has.
+type ErrRoleAlreadyGranted struct {
+	UserID string
+	Role   role.Role
+}
+
+var _ error = (*ErrRoleAlreadyGranted)(nil)
+
+func (e ErrRoleAlreadyGranted) Error() string {
+	return fmt.Sprintf("user(%s) already has role(%s)", e.UserID, e.Role)
+}
+
+// ErrRoleNotGranted represents the failure of revoking a role which the user
+// doesn't have.
+type ErrRoleNotGranted struct {
+	UserID string
+	Role   role.Role
+}
+
+var _ error = (*ErrRoleNotGranted)(nil)
+
+func (e ErrRoleNotGranted) Error() string {
+	return fmt.Sprintf("user(%s) doesn't have role(%s)", e.UserID, e.Role)
+}
+
+// ErrLastAdmin represents the failure of revoking the admin role from the
+// only admin left, which would leave nobody able to manage roles.
+type ErrLastAdmin string
+
+var _ error = (*ErrLastAdmin)(nil)
+
+func (e ErrLastAdmin) Error() string {
+	return fmt.Sprintf("user(%s) is the last admin", string(e))
+}
+
+// UserRoles represents an user together with the roles granted to the user.
+type UserRoles struct {
+	User  entity.User
+	Roles []role.Role
+}
+
+// Manager lists the roles of users, and grants and revokes them while
+// recording every change for audit.
+type Manager struct {
+	userRepo           repository.User
+	roleAssignmentRepo repository.RoleAssignment
+	rbac               rbac.RBAC
+	authorizer         authorizer.Authorizer
+	timer              timer.Timer
+}
+
+// GetUsers retrieves at most limit users together with their roles, ordered
+// by ID and starting after the user with the given ID. The limit is capped at
+// MaxUsersPerPage.
+func (m Manager) GetUsers(viewer entity.User, afterID string, limit int) ([]UserRoles, error) {
+	err := m.checkPermission(viewer, m.authorizer.CanViewUsers, "view users")
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 1 || limit > MaxUsersPerPage {
+		limit = MaxUsersPerPage
+	}
+	users, err := m.userRepo.GetUsers(afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userRoles := make([]UserRoles, 0, len(users))
+	for _, user := range users {
+		roles, err := m.rbac.GetRoles(user)
+		if err != nil {
+			return nil, err
+		}
+		userRoles = append(userRoles, UserRoles{User: user, Roles: roles})
+	}
+	return userRoles, nil
+}
+
+// GetRoleChanges retrieves the audit log of the role changes of the given
+// user, with the latest first.
+func (m Manager) GetRoleChanges(viewer entity.User, userID string) ([]entity.RoleChange, error) {
+	err := m.checkPermission(
+		viewer,
+		m.authorizer.CanViewUsers,
+		fmt.Sprintf("view role changes of user(%s)", userID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m.roleAssignmentRepo.GetRoleChanges(userID)
+}
+
+// GrantRole grants the role to the given user on behalf of the actor.
+func (m Manager) GrantRole(actor entity.User, userID string, r role.Role) error {
+	err := m.checkPermission(
+		actor,
+		m.authorizer.CanGrantRole,
+		fmt.Sprintf("grant role(%s) to user(%s)", r, userID),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = m.validateChange(userID, r)
+	if err != nil {
+		return err
+	}
+
+	err = m.roleAssignmentRepo.GrantRole(m.newRoleChange(actor, userID, r, entity.RoleGranted))
+	var exists repository.ErrEntryExists
+	if errors.As(err, &exists) {
+		return ErrRoleAlreadyGranted{UserID: userID, Role: r}
+	}
+	return err
+}
+
+// RevokeRole revokes the role from the given user on behalf of the actor.
+// The admin role can't be revoked from the last admin.
+func (m Manager) RevokeRole(actor entity.User, userID string, r role.Role) error {
+	err := m.checkPermission(
+		actor,
+		m.authorizer.CanRevokeRole,
+		fmt.Sprintf("revoke role(%s) from user(%s)", r, userID),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = m.validateChange(userID, r)
+	if err != nil {
+		return err
+	}
+
+	change := m.newRoleChange(actor, userID, r, entity.RoleRevoked)
+	err = m.roleAssignmentRepo.RevokeRole(change, r == role.Admin)
+	var (
+		notFound repository.ErrEntryNotFound
+		last     repository.ErrLastEntry
+	)
+	if errors.As(err, &notFound) {
+		return ErrRoleNotGranted{UserID: userID, Role: r}
+	}
+	if errors.As(err, &last) {
+		return ErrLastAdmin(userID)
+	}
+	return err
+}
+
+func (m Manager) checkPermission(
+	user entity.User,
+	can func(user entity.User) (bool, error),
+	action string,
+) error {
+	isAllowed, err := can(user)
+	if err != nil {
+		return err
+	}
+	if !isAllowed {
+		return ErrUnauthorizedAction{UserID: user.ID, Action: action}
+	}
+	return nil
+}
+
+func (m Manager) validateChange(userID string, r role.Role) error {
+	isDefined, err := m.rbac.IsRoleDefined(r)
+	if err != nil {
+		return err
+	}
+	if !isDefined {
+		return ErrUnknownRole(r)
+	}
+
+	isExist, err := m.userRepo.IsIDExist(userID)
+	if err != nil {
+		return err
+	}
+	if !isExist {
+		return ErrUserNotFound(userID)
+	}
+	return nil
+}
+
+func (m Manager) newRoleChange(
+	actor entity.User,
+	userID string,
+	r role.Role,
+	action entity.RoleChangeAction,
+) entity.RoleChange {
+	return entity.RoleChange{
+		ActorID:   actor.ID,
+		UserID:    userID,
+		Role:      string(r),
+		Action:    action,
+		CreatedAt: m.timer.Now().UTC(),
+	}
+}
+
+// NewManager creates Manager.
+func NewManager(
+	userRepo repository.User,
+	roleAssignmentRepo repository.RoleAssignment,
+	rbac rbac.RBAC,
+	authorizer authorizer.Authorizer,
+	timer timer.Timer,
+) Manager {
+	return Manager{
+		userRepo:           userRepo,
+		roleAssignmentRepo: roleAssignmentRepo,
+		rbac:               rbac,
+		authorizer:         authorizer,
+		timer:              timer,
+	}
+}
@@ -0,0 +1,302 @@
+// +build !integration all
+
+package userrole
+
+import (
+	"testing"
+	"time"
+
+	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/fw/must"
+	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/repository"
+)
+
+var users = []entity.User{
+	{ID: "gamma", Email: "gamma@example.com"},
+	{ID: "alpha", Email: "alpha@example.com"},
+	{ID: "beta", Email: "beta@example.com"},
+}
+
+func TestManager_GetUsers(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name              string
+		roles             map[string][]role.Role
+		viewer            entity.User
+		afterID           string
+		limit             int
+		hasErr            bool
+		expectedUserRoles []UserRoles
+	}{
+		{
+			name:   "viewer without permission",
+			roles:  map[string][]role.Role{"beta": {role.Basic}},
+			viewer: entity.User{ID: "beta"},
+			limit:  2,
+			hasErr: true,
+		},
+		{
+			name: "first page",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {role.SecuritySpecialist, role.Premium},
+			},
+			viewer: entity.User{ID: "beta"},
+			limit:  2,
+			hasErr: false,
+			expectedUserRoles: []UserRoles{
+				{User: users[1], Roles: []role.Role{role.Admin}},
+				{User: users[2], Roles: []role.Role{role.SecuritySpecialist, role.Premium}},
+			},
+		},
+		{
+			name:    "user without role",
+			roles:   map[string][]role.Role{"alpha": {role.Admin}},
+			viewer:  entity.User{ID: "alpha"},
+			afterID: "beta",
+			limit:   0,
+			hasErr:  false,
+			expectedUserRoles: []UserRoles{
+				{User: users[0], Roles: []role.Role{}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, _ := newManager(testCase.roles, now)
+
+			gotUserRoles, err := manager.GetUsers(testCase.viewer, testCase.afterID, testCase.limit)
+			if testCase.hasErr {
+				assert.NotEqual(t, nil, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedUserRoles, gotUserRoles)
+		})
+	}
+}
+
+func TestManager_GrantRole(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name          string
+		roles         map[string][]role.Role
+		actor         entity.User
+		userID        string
+		role          role.Role
+		hasErr        bool
+		expectedErr   error
+		expectedRoles []role.Role
+	}{
+		{
+			name:        "actor without permission",
+			roles:       map[string][]role.Role{"alpha": {role.SecuritySpecialist}},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "beta",
+			role:        role.Premium,
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "grant role(premium) to user(beta)"},
+		},
+		{
+			name:        "unknown role",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "beta",
+			role:        "owner",
+			hasErr:      true,
+			expectedErr: ErrUnknownRole("owner"),
+		},
+		{
+			name:        "user not found",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "delta",
+			role:        role.Premium,
+			hasErr:      true,
+			expectedErr: ErrUserNotFound("delta"),
+		},
+		{
+			name: "role already granted",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {role.Premium},
+			},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "beta",
+			role:        role.Premium,
+			hasErr:      true,
+			expectedErr: ErrRoleAlreadyGranted{UserID: "beta", Role: role.Premium},
+		},
+		{
+			name: "role granted",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {role.Premium},
+			},
+			actor:         entity.User{ID: "alpha"},
+			userID:        "beta",
+			role:          role.ChangeLogEditor,
+			hasErr:        false,
+			expectedRoles: []role.Role{role.Premium, role.ChangeLogEditor},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, userRoleRepo := newManager(testCase.roles, now)
+
+			err := manager.GrantRole(testCase.actor, testCase.userID, testCase.role)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			roles, err := userRoleRepo.GetRoles(entity.User{ID: testCase.userID})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+
+			changes, err := manager.GetRoleChanges(testCase.actor, testCase.userID)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{
+				{
+					ActorID:   testCase.actor.ID,
+					UserID:    testCase.userID,
+					Role:      string(testCase.role),
+					Action:    entity.RoleGranted,
+					CreatedAt: now,
+				},
+			}, changes)
+		})
+	}
+}
+
+func TestManager_RevokeRole(t *testing.T) {
+	t.Parallel()
+
+	now := must.Time(t, "2020-07-21T10:00:00Z")
+
+	testCases := []struct {
+		name          string
+		roles         map[string][]role.Role
+		actor         entity.User
+		userID        string
+		role          role.Role
+		hasErr        bool
+		expectedErr   error
+		expectedRoles []role.Role
+	}{
+		{
+			name: "actor without permission",
+			roles: map[string][]role.Role{
+				"alpha": {role.SecuritySpecialist},
+				"beta":  {role.Premium},
+			},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "beta",
+			role:        role.Premium,
+			hasErr:      true,
+			expectedErr: ErrUnauthorizedAction{UserID: "alpha", Action: "revoke role(premium) from user(beta)"},
+		},
+		{
+			name:        "role not granted",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "beta",
+			role:        role.Premium,
+			hasErr:      true,
+			expectedErr: ErrRoleNotGranted{UserID: "beta", Role: role.Premium},
+		},
+		{
+			name:        "last admin",
+			roles:       map[string][]role.Role{"alpha": {role.Admin}},
+			actor:       entity.User{ID: "alpha"},
+			userID:      "alpha",
+			role:        role.Admin,
+			hasErr:      true,
+			expectedErr: ErrLastAdmin("alpha"),
+		},
+		{
+			name: "admin revoked while another admin is left",
+			roles: map[string][]role.Role{
+				"alpha": {role.Admin},
+				"beta":  {role.Premium, role.Admin},
+			},
+			actor:         entity.User{ID: "alpha"},
+			userID:        "beta",
+			role:          role.Admin,
+			hasErr:        false,
+			expectedRoles: []role.Role{role.Premium},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager, userRoleRepo := newManager(testCase.roles, now)
+
+			err := manager.RevokeRole(testCase.actor, testCase.userID, testCase.role)
+			if testCase.hasErr {
+				assert.Equal(t, testCase.expectedErr, err)
+				return
+			}
+			assert.Equal(t, nil, err)
+
+			roles, err := userRoleRepo.GetRoles(entity.User{ID: testCase.userID})
+			assert.Equal(t, nil, err)
+			assert.Equal(t, testCase.expectedRoles, roles)
+
+			changes, err := manager.GetRoleChanges(testCase.actor, testCase.userID)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []entity.RoleChange{
+				{
+					ActorID:   testCase.actor.ID,
+					UserID:    testCase.userID,
+					Role:      string(testCase.role),
+					Action:    entity.RoleRevoked,
+					CreatedAt: now,
+				},
+			}, changes)
+		})
+	}
+}
+
+func newManager(roles map[string][]role.Role, now time.Time) (Manager, repository.UserRoleFake) {
+	userRoles := map[string][]role.Role{}
+	for userID, userRole := range roles {
+		userRoles[userID] = append([]role.Role{}, userRole...)
+	}
+
+	userRepo := repository.NewUserFake(append([]entity.User{}, users...))
+	userRoleRepo := repository.NewUserRoleFake(userRoles)
+	roleAssignmentRepo := repository.NewRoleAssignmentFake(userRoles)
+	ac := rbac.NewRBAC(userRoleRepo)
+	manager := NewManager(
+		&userRepo,
+		&roleAssignmentRepo,
+		ac,
+		authorizer.NewAuthorizer(ac),
+		timer.NewStub(now),
+	)
+	return manager, userRoleRepo
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/short-d/short/backend/app/entity"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 )
 
 const (
@@ -17,11 +18,31 @@ var forbiddenCharacters = map[rune]entity.Empty{
 
 // CustomAlias represents format validator for custom alias
 type CustomAlias struct {
-	uriPattern *regexp.Regexp
+	uriPattern      *regexp.Regexp
+	aliasNormalizer normalizer.Alias
+	wordList        AliasWordList
 }
 
-// IsValid checks whether the given alias has valid format.
+// IsValid checks whether the given alias has valid format after it is
+// normalized and is not restricted by the word list.
 func (c CustomAlias) IsValid(alias string) (bool, Violation) {
+	isValid, violation := c.IsValidFormat(alias)
+	if !isValid {
+		return false, violation
+	}
+
+	alias = c.aliasNormalizer.Normalize(alias)
+	if alias == "" {
+		return true, Valid
+	}
+	return c.wordList.IsValid(alias)
+}
+
+// IsValidFormat checks whether the given alias has valid format after it is
+// normalized, ignoring the word list. Privileged users can use it to claim
+// reserved aliases.
+func (c CustomAlias) IsValidFormat(alias string) (bool, Violation) {
+	alias = c.aliasNormalizer.Normalize(alias)
 	if alias == "" {
 		return true, Valid
 	}
@@ -49,6 +70,12 @@ func (c CustomAlias) hasForbiddenCharacter(alias string) bool {
 }
 
 // NewCustomAlias creates custom alias validator.
-func NewCustomAlias() CustomAlias {
-	return CustomAlias{}
+func NewCustomAlias(
+	aliasNormalizer normalizer.Alias,
+	wordList AliasWordList,
+) CustomAlias {
+	return CustomAlias{
+		aliasNormalizer: aliasNormalizer,
+		wordList:        wordList,
+	}
 }
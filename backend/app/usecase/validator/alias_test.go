@@ -7,14 +7,17 @@ import (
 	"testing"
 
 	"github.com/short-d/app/fw/assert"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 )
 
 func TestCustomAlias_IsValid(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
-		name       string
-		alias      string
-		expIsValid bool
+		name         string
+		aliasPolicy  normalizer.AliasPolicy
+		alias        string
+		expIsValid   bool
+		expViolation Violation
 	}{
 		{
 			name:       "empty string",
@@ -36,14 +39,73 @@ func TestCustomAlias_IsValid(t *testing.T) {
 			alias:      "#fb",
 			expIsValid: false,
 		},
+		{
+			name:       "alias too long without trimming",
+			alias:      strings.Repeat("a", 45) + "     ",
+			expIsValid: false,
+		},
+		{
+			name:        "alias valid after trimming",
+			aliasPolicy: normalizer.AliasPolicy{TrimSpace: true},
+			alias:       strings.Repeat("a", 45) + "     ",
+			expIsValid:  true,
+		},
+		{
+			name:         "alias reserved",
+			alias:        "Admin",
+			expIsValid:   false,
+			expViolation: AliasReserved,
+		},
+		{
+			name:         "alias contains blocked word",
+			alias:        "paypal-login",
+			expIsValid:   false,
+			expViolation: AliasHasBlockedWord,
+		},
+	}
+
+	wordList := NewAliasWordList([]string{"admin"}, []string{"paypal"})
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			aliasNormalizer := normalizer.NewAlias(testCase.aliasPolicy)
+			validator := NewCustomAlias(aliasNormalizer, wordList)
+			valid, violation := validator.IsValid(testCase.alias)
+			assert.Equal(t, testCase.expIsValid, valid)
+			if !testCase.expIsValid && testCase.expViolation != "" {
+				assert.Equal(t, testCase.expViolation, violation)
+			}
+		})
+	}
+}
+
+func TestCustomAlias_IsValidFormat(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		alias      string
+		expIsValid bool
+	}{
+		{
+			name:       "alias reserved",
+			alias:      "admin",
+			expIsValid: true,
+		},
+		{
+			name:       "alias has forbidden character",
+			alias:      "#admin",
+			expIsValid: false,
+		},
 	}
 
-	validator := NewCustomAlias()
+	wordList := NewAliasWordList([]string{"admin"}, []string{})
+	validator := NewCustomAlias(normalizer.NewAlias(normalizer.AliasPolicy{}), wordList)
 	for _, testCase := range testCases {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
-			valid, _ := validator.IsValid(testCase.alias)
+			valid, _ := validator.IsValidFormat(testCase.alias)
 			assert.Equal(t, testCase.expIsValid, valid)
 		})
 	}
@@ -73,7 +135,10 @@ func TestCustomAlias_hasFragmentCharacter(t *testing.T) {
 		},
 	}
 
-	validator := NewCustomAlias()
+	validator := NewCustomAlias(
+		normalizer.NewAlias(normalizer.AliasPolicy{}),
+		NewAliasWordList([]string{}, []string{}),
+	)
 	for _, testCase := range testCases {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
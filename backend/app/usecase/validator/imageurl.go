@@ -0,0 +1,27 @@
+package validator
+
+var _ Validator = (*ImageURL)(nil)
+
+// ImageURL validates the image URLs of the social meta tags. Image URLs follow
+// the same restrictions as the default long link policy so that link previews
+// never point to internal hosts.
+type ImageURL struct {
+	longLink LongLink
+}
+
+// IsValid checks whether the given image URL is a public http(s) URL.
+func (i ImageURL) IsValid(imageURL string) (bool, Violation) {
+	isValid, violation := i.longLink.IsValid(imageURL)
+	if isValid {
+		return true, Valid
+	}
+	if violation == LongLinkTooLong {
+		return false, ImageURLTooLong
+	}
+	return false, ImageURLInvalid
+}
+
+// NewImageURL creates image URL validator.
+func NewImageURL() ImageURL {
+	return ImageURL{longLink: NewLongLink(LongLinkPolicy{})}
+}
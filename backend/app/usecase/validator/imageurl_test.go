@@ -0,0 +1,68 @@
+// +build !integration all
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestImageURL_IsValid(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		imageURL     string
+		expIsValid   bool
+		expViolation Violation
+	}{
+		{
+			name:         "public https image",
+			imageURL:     "https://short-d.com/promo/small-tile.png",
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "empty",
+			imageURL:     "",
+			expIsValid:   false,
+			expViolation: ImageURLInvalid,
+		},
+		{
+			name:         "not a URL",
+			imageURL:     "small-tile.png",
+			expIsValid:   false,
+			expViolation: ImageURLInvalid,
+		},
+		{
+			name:         "scheme not allowed",
+			imageURL:     "javascript:alert(1)",
+			expIsValid:   false,
+			expViolation: ImageURLInvalid,
+		},
+		{
+			name:         "private host",
+			imageURL:     "http://127.0.0.1/tile.png",
+			expIsValid:   false,
+			expViolation: ImageURLInvalid,
+		},
+		{
+			name:         "too long",
+			imageURL:     "https://short-d.com/" + strings.Repeat("a", 200),
+			expIsValid:   false,
+			expViolation: ImageURLTooLong,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			validator := NewImageURL()
+			isValid, violation := validator.IsValid(testCase.imageURL)
+			assert.Equal(t, testCase.expIsValid, isValid)
+			assert.Equal(t, testCase.expViolation, violation)
+		})
+	}
+}
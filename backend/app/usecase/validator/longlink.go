@@ -1,12 +1,49 @@
 package validator
 
-import "regexp"
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/short-d/short/backend/app/entity"
+	"golang.org/x/net/idna"
+)
 
 const longLinkMaxLength = 200
 
+var defaultAllowedSchemes = []string{"http", "https"}
+
+var hostProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+var privateNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+// LongLinkPolicy configures the restrictions applied to long links. The zero
+// value falls back to the default length limit and only allows http and https.
+type LongLinkPolicy struct {
+	MaxLength      int
+	AllowedSchemes []string
+}
+
 // LongLink represents format validator for original long link
 type LongLink struct {
-	uriPattern *regexp.Regexp
+	maxLength      int
+	allowedSchemes map[string]entity.Empty
 }
 
 // IsValid checks whether the given long link has valid format.
@@ -15,21 +52,91 @@ func (l LongLink) IsValid(longLink string) (bool, Violation) {
 		return false, EmptyLongLink
 	}
 
-	if len(longLink) >= longLinkMaxLength {
+	if len(longLink) >= l.maxLength {
 		return false, LongLinkTooLong
 	}
 
-	if !l.uriPattern.MatchString(longLink) {
+	u, err := url.Parse(longLink)
+	if err != nil || u.Scheme == "" {
+		return false, LongLinkNotURL
+	}
+
+	if _, ok := l.allowedSchemes[strings.ToLower(u.Scheme)]; !ok {
+		return false, LongLinkSchemeNotAllowed
+	}
+
+	if u.Opaque != "" || u.Hostname() == "" {
 		return false, LongLinkNotURL
 	}
 
+	hostname := u.Hostname()
+	if net.ParseIP(hostname) == nil {
+		hostname, err = hostProfile.ToASCII(hostname)
+		if err != nil {
+			return false, LongLinkInvalidHost
+		}
+	}
+
+	if isPrivateHost(strings.ToLower(hostname)) {
+		return false, LongLinkPrivateHost
+	}
 	return true, Valid
 }
 
+// isPrivateHost checks whether the host points to the local machine or to a
+// private network. Hostnames are not resolved, so this only catches obvious
+// mistakes and is not an SSRF control.
+func isPrivateHost(hostname string) bool {
+	if hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(hostname)
+	if ip == nil {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
 // NewLongLink creates long link validator.
-func NewLongLink() LongLink {
-	uriPattern := regexp.MustCompile(`^[a-zA-Z]+://.+$`)
+func NewLongLink(policy LongLinkPolicy) LongLink {
+	maxLength := policy.MaxLength
+	if maxLength <= 0 {
+		maxLength = longLinkMaxLength
+	}
+
+	schemes := policy.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = defaultAllowedSchemes
+	}
+	allowedSchemes := make(map[string]entity.Empty)
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if scheme == "" {
+			continue
+		}
+		allowedSchemes[scheme] = entity.Empty{}
+	}
+
 	return LongLink{
-		uriPattern: uriPattern,
+		maxLength:      maxLength,
+		allowedSchemes: allowedSchemes,
 	}
 }
@@ -12,49 +12,152 @@ import (
 func TestLongLink_IsValid(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
-		name       string
-		longLink   string
-		expIsValid bool
+		name         string
+		policy       LongLinkPolicy
+		longLink     string
+		expIsValid   bool
+		expViolation Violation
 	}{
 		{
-			name:       "empty string",
-			longLink:   "",
-			expIsValid: false,
+			name:         "empty string",
+			longLink:     "",
+			expIsValid:   false,
+			expViolation: EmptyLongLink,
 		},
 		{
-			name:       "no ://",
-			longLink:   "randomLink",
-			expIsValid: false,
+			name:         "no ://",
+			longLink:     "randomLink",
+			expIsValid:   false,
+			expViolation: LongLinkNotURL,
 		},
 		{
-			name:       "only ://",
-			longLink:   "://",
-			expIsValid: false,
+			name:         "only ://",
+			longLink:     "://",
+			expIsValid:   false,
+			expViolation: LongLinkNotURL,
 		},
 		{
-			name:       "no hostname",
-			longLink:   "http://",
-			expIsValid: false,
+			name:         "no hostname",
+			longLink:     "http://",
+			expIsValid:   false,
+			expViolation: LongLinkNotURL,
 		},
 		{
-			name:       "link too long",
-			longLink:   strings.Repeat("helloworld", 20),
-			expIsValid: false,
+			name:         "link too long",
+			longLink:     strings.Repeat("helloworld", 20),
+			expIsValid:   false,
+			expViolation: LongLinkTooLong,
 		},
 		{
-			name:       "link valid",
-			longLink:   "https://google.com",
-			expIsValid: true,
+			name:         "link within custom length limit",
+			policy:       LongLinkPolicy{MaxLength: 500},
+			longLink:     "https://google.com/" + strings.Repeat("a", 300),
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "link exceeds custom length limit",
+			policy:       LongLinkPolicy{MaxLength: 20},
+			longLink:     "https://google.com/search",
+			expIsValid:   false,
+			expViolation: LongLinkTooLong,
+		},
+		{
+			name:         "link valid",
+			longLink:     "https://google.com",
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "uppercase scheme",
+			longLink:     "HTTP://google.com",
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "javascript scheme",
+			longLink:     "javascript:alert(1)",
+			expIsValid:   false,
+			expViolation: LongLinkSchemeNotAllowed,
+		},
+		{
+			name:         "data scheme",
+			longLink:     "data:text/html,<script>alert(1)</script>",
+			expIsValid:   false,
+			expViolation: LongLinkSchemeNotAllowed,
+		},
+		{
+			name:         "scheme not in custom allowlist",
+			policy:       LongLinkPolicy{AllowedSchemes: []string{"https"}},
+			longLink:     "http://google.com",
+			expIsValid:   false,
+			expViolation: LongLinkSchemeNotAllowed,
+		},
+		{
+			name:         "scheme in custom allowlist",
+			policy:       LongLinkPolicy{AllowedSchemes: []string{"https", "ftp"}},
+			longLink:     "ftp://ftp.example.com/file",
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "internationalized domain name",
+			longLink:     "https://bücher.example/katalog",
+			expIsValid:   true,
+			expViolation: Valid,
+		},
+		{
+			name:         "invalid host",
+			longLink:     "https://a..b/",
+			expIsValid:   false,
+			expViolation: LongLinkInvalidHost,
+		},
+		{
+			name:         "localhost",
+			longLink:     "http://localhost:8080/admin",
+			expIsValid:   false,
+			expViolation: LongLinkPrivateHost,
+		},
+		{
+			name:         "loopback IPv4",
+			longLink:     "http://127.0.0.1/",
+			expIsValid:   false,
+			expViolation: LongLinkPrivateHost,
+		},
+		{
+			name:         "loopback IPv6",
+			longLink:     "http://[::1]/",
+			expIsValid:   false,
+			expViolation: LongLinkPrivateHost,
+		},
+		{
+			name:         "private IPv4",
+			longLink:     "http://192.168.1.1/",
+			expIsValid:   false,
+			expViolation: LongLinkPrivateHost,
+		},
+		{
+			name:         "link local IPv4",
+			longLink:     "http://169.254.169.254/latest/meta-data",
+			expIsValid:   false,
+			expViolation: LongLinkPrivateHost,
+		},
+		{
+			name:         "public IPv4",
+			longLink:     "http://8.8.8.8/",
+			expIsValid:   true,
+			expViolation: Valid,
 		},
 	}
 
-	validator := NewLongLink()
 	for _, testCase := range testCases {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
-			valid, _ := validator.IsValid(testCase.longLink)
+			validator := NewLongLink(testCase.policy)
+			valid, violation := validator.IsValid(testCase.longLink)
 			assert.Equal(t, testCase.expIsValid, valid)
+			assert.Equal(t, testCase.expViolation, violation)
 		})
 	}
 }
@@ -4,10 +4,22 @@ package validator
 type Violation string
 
 const (
-	Valid                Violation = "Valid"
-	EmptyLongLink                  = "EmptyLongLink"
-	LongLinkNotURL                 = "LongLinkNotURL"
-	AliasTooLong                   = "AliasTooLong"
-	LongLinkTooLong                = "LongLinkTooLong"
-	HasFragmentCharacter           = "HasFragmentCharacter"
+	Valid                    Violation = "Valid"
+	EmptyLongLink                      = "EmptyLongLink"
+	LongLinkNotURL                     = "LongLinkNotURL"
+	AliasTooLong                       = "AliasTooLong"
+	LongLinkTooLong                    = "LongLinkTooLong"
+	LongLinkSchemeNotAllowed           = "LongLinkSchemeNotAllowed"
+	LongLinkInvalidHost                = "LongLinkInvalidHost"
+	// LongLinkPrivateHost flags long links which name the local machine or a
+	// private network by an IP literal or localhost. Hostnames are not resolved,
+	// so it is not an SSRF control. Fetching long links relies on netguard.Guard
+	// rejecting private addresses at connection time instead.
+	LongLinkPrivateHost  = "LongLinkPrivateHost"
+	HasFragmentCharacter = "HasFragmentCharacter"
+	AliasReserved        = "AliasReserved"
+	AliasHasBlockedWord  = "AliasHasBlockedWord"
+	MetaTagTooLong       = "MetaTagTooLong"
+	ImageURLTooLong      = "ImageURLTooLong"
+	ImageURLInvalid      = "ImageURLInvalid"
 )
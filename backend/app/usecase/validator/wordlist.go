@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/short-d/short/backend/app/entity"
+)
+
+// AliasWordList represents validator rejecting aliases which are reserved by
+// the service or contain profane or impersonating words.
+type AliasWordList struct {
+	reservedWords map[string]entity.Empty
+	blockedWords  []string
+}
+
+// IsValid checks whether the given alias is neither reserved nor contains any
+// blocked word. The comparison is case insensitive.
+func (a AliasWordList) IsValid(alias string) (bool, Violation) {
+	alias = strings.ToLower(alias)
+	if _, ok := a.reservedWords[alias]; ok {
+		return false, AliasReserved
+	}
+
+	for _, word := range a.blockedWords {
+		if strings.Contains(alias, word) {
+			return false, AliasHasBlockedWord
+		}
+	}
+	return true, Valid
+}
+
+// NewAliasWordList creates alias word list validator.
+func NewAliasWordList(reservedWords []string, blockedWords []string) AliasWordList {
+	reserved := make(map[string]entity.Empty)
+	for _, word := range reservedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		reserved[word] = entity.Empty{}
+	}
+
+	var blocked []string
+	for _, word := range blockedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		blocked = append(blocked, word)
+	}
+	return AliasWordList{
+		reservedWords: reserved,
+		blockedWords:  blocked,
+	}
+}
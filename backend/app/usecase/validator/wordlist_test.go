@@ -0,0 +1,81 @@
+// +build !integration all
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/short-d/app/fw/assert"
+)
+
+func TestAliasWordList_IsValid(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		reservedWords []string
+		blockedWords  []string
+		alias         string
+		expIsValid    bool
+		expViolation  Violation
+	}{
+		{
+			name:          "empty word lists",
+			reservedWords: []string{},
+			blockedWords:  []string{},
+			alias:         "admin",
+			expIsValid:    true,
+			expViolation:  Valid,
+		},
+		{
+			name:          "alias reserved",
+			reservedWords: []string{"admin", "api"},
+			blockedWords:  []string{},
+			alias:         "api",
+			expIsValid:    false,
+			expViolation:  AliasReserved,
+		},
+		{
+			name:          "reserved word ignores case",
+			reservedWords: []string{" Login "},
+			blockedWords:  []string{},
+			alias:         "LOGIN",
+			expIsValid:    false,
+			expViolation:  AliasReserved,
+		},
+		{
+			name:          "alias contains reserved word",
+			reservedWords: []string{"api"},
+			blockedWords:  []string{},
+			alias:         "my-api",
+			expIsValid:    true,
+			expViolation:  Valid,
+		},
+		{
+			name:          "alias contains blocked word",
+			reservedWords: []string{},
+			blockedWords:  []string{"paypal"},
+			alias:         "PayPal-support",
+			expIsValid:    false,
+			expViolation:  AliasHasBlockedWord,
+		},
+		{
+			name:          "blank words ignored",
+			reservedWords: []string{""},
+			blockedWords:  []string{" "},
+			alias:         "docs",
+			expIsValid:    true,
+			expViolation:  Valid,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			wordList := NewAliasWordList(testCase.reservedWords, testCase.blockedWords)
+			isValid, violation := wordList.IsValid(testCase.alias)
+			assert.Equal(t, testCase.expIsValid, isValid)
+			assert.Equal(t, testCase.expViolation, violation)
+		})
+	}
+}
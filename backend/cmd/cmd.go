@@ -9,6 +9,7 @@ import (
 	"github.com/short-d/short/backend/app"
 	"github.com/short-d/short/backend/dep"
 	"github.com/short-d/short/backend/dep/provider"
+	"github.com/short-d/short/backend/tool"
 )
 
 // NewRootCmd creates the base command.
@@ -48,23 +49,7 @@ func NewRootCmd(
 		Usage:        "data-id",
 		ShortHelpMsg: "Use user ID to uniquely identify a user",
 		OnExecute: func(cmd cli.Command, args []string) {
-			kgsConfig := provider.KgsRPCConfig{
-				Hostname: config.KgsHostname,
-				Port:     config.KgsPort,
-			}
-			keyGenBufferSize := provider.KeyGenBufferSize(config.KeyGenBufferSize)
-			dataTool, err := dep.InjectDataTool(
-				provider.LogPrefix(config.LogPrefix),
-				config.LogLevel,
-				dbConfig,
-				dbConnector,
-				keyGenBufferSize,
-				kgsConfig,
-			)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
+			dataTool := newDataTool(dbConfig, config, dbConnector)
 			dataTool.EmailToID(batchSize)
 		},
 	})
@@ -75,6 +60,26 @@ func NewRootCmd(
 		"the max number of records to migrate",
 	)
 
+	var aliasMode string
+	aliasCmd := cmdFactory.NewCommand(cli.CommandConfig{
+		Usage:        "data-alias",
+		ShortHelpMsg: "Report alias collisions and normalize existing aliases",
+		OnExecute: func(cmd cli.Command, args []string) {
+			if aliasMode != "report" && aliasMode != "apply" {
+				fmt.Printf("unknown mode: %s\n", aliasMode)
+				os.Exit(1)
+			}
+			dataTool := newDataTool(dbConfig, config, dbConnector)
+			dataTool.NormalizeAlias(aliasMode == "apply")
+		},
+	})
+	aliasCmd.AddStringFlag(
+		&aliasMode,
+		"mode",
+		"report",
+		"report only lists collisions, apply also rewrites aliases",
+	)
+
 	rootCmd := cmdFactory.NewCommand(
 		cli.CommandConfig{
 			Usage:     "short",
@@ -91,9 +96,40 @@ func NewRootCmd(
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	err = rootCmd.AddSubCommand(aliasCmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 	return rootCmd
 }
 
+func newDataTool(
+	dbConfig db.Config,
+	config app.ServiceConfig,
+	dbConnector db.Connector,
+) tool.Data {
+	kgsConfig := provider.KgsRPCConfig{
+		Hostname: config.KgsHostname,
+		Port:     config.KgsPort,
+	}
+	keyGenBufferSize := provider.KeyGenBufferSize(config.KeyGenBufferSize)
+	dataTool, err := dep.InjectDataTool(
+		provider.LogPrefix(config.LogPrefix),
+		config.LogLevel,
+		dbConfig,
+		dbConnector,
+		keyGenBufferSize,
+		kgsConfig,
+		config.AliasPolicy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return dataTool
+}
+
 // Execute runs the root command.
 func Execute(rootCmd cli.Command) {
 	err := rootCmd.Execute()
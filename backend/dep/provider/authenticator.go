@@ -6,12 +6,34 @@ import (
 	"github.com/short-d/app/fw/crypto"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/repository"
 )
 
 // TokenValidDuration represents the duration of a valid token.
 type TokenValidDuration time.Duration
 
-// NewAuthenticator creates Authenticator with TokenValidDuration to uniquely identify duration during dependency injection.
-func NewAuthenticator(tokenizer crypto.Tokenizer, timer timer.Timer, duration TokenValidDuration) authenticator.Authenticator {
-	return authenticator.NewAuthenticator(tokenizer, timer, time.Duration(duration))
+// RefreshTokenValidDuration represents the duration of a session without
+// refreshing its tokens.
+type RefreshTokenValidDuration time.Duration
+
+// NewAuthenticator creates Authenticator with TokenValidDuration and
+// RefreshTokenValidDuration to uniquely identify durations during dependency
+// injection.
+func NewAuthenticator(
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+	keyGen keygen.KeyGenerator,
+	sessionRepo repository.Session,
+	duration TokenValidDuration,
+	refreshDuration RefreshTokenValidDuration,
+) authenticator.Authenticator {
+	return authenticator.NewAuthenticator(
+		tokenizer,
+		timer,
+		keyGen,
+		sessionRepo,
+		time.Duration(duration),
+		time.Duration(refreshDuration),
+	)
 }
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"net/url"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
+	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+)
+
+// EmailSignInCallbackURL represents the URL the sign in links sent by email
+// point to.
+type EmailSignInCallbackURL string
+
+// NewEmailSignIn creates EmailSignIn with EmailSignInCallbackURL and the
+// email sign in limit of the rate limit policy.
+func NewEmailSignIn(
+	tokenRepo repository.EmailSignInToken,
+	userRepo repository.User,
+	keyGen keygen.KeyGenerator,
+	mailer emailsignin.Mailer,
+	limiter ratelimit.Limiter,
+	rateLimitPolicy ratelimit.Policy,
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+	twoFactor twofactor.TwoFactor,
+	callbackURL EmailSignInCallbackURL,
+) (emailsignin.EmailSignIn, error) {
+	parsedURL, err := url.Parse(string(callbackURL))
+	if err != nil {
+		return emailsignin.EmailSignIn{}, err
+	}
+	return emailsignin.NewEmailSignIn(
+		tokenRepo,
+		userRepo,
+		keyGen,
+		mailer,
+		limiter,
+		rateLimitPolicy.EmailSignIn,
+		tokenizer,
+		timer,
+		twoFactor,
+		*parsedURL,
+	), nil
+}
@@ -45,6 +45,7 @@ func NewFacebookSSO(
 ) facebook.SingleSignOn {
 	return facebook.SingleSignOn(
 		ssoFactory.NewSingleSignOn(
+			"facebook",
 			identityProvider,
 			account,
 			sso.AccountLinker(linker)),
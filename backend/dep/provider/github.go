@@ -41,6 +41,7 @@ func NewGithubSSO(
 ) github.SingleSignOn {
 	return github.SingleSignOn(
 		ssoFactory.NewSingleSignOn(
+			"github",
 			identityProvider,
 			account,
 			sso.AccountLinker(accountLinker),
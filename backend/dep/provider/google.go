@@ -45,6 +45,7 @@ func NewGoogleSSO(
 ) google.SingleSignOn {
 	return google.SingleSignOn(
 		ssoFactory.NewSingleSignOn(
+			"google",
 			identityProvider,
 			account,
 			sso.AccountLinker(linker)),
@@ -6,7 +6,9 @@ import (
 	"github.com/short-d/app/fw/service"
 	"github.com/short-d/short/backend/app/adapter/gqlapi"
 	"github.com/short-d/short/backend/app/adapter/gqlapi/resolver"
+	"github.com/short-d/short/backend/app/adapter/request"
 	"github.com/short-d/short/backend/app/fw/filesystem"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 )
 
 // GraphQLSchemaPath represents the local of GraphQL schema.
@@ -21,6 +23,16 @@ func NewShortGraphQLAPI(
 	return gqlapi.NewShort(string(schemaPath), fileSystem, resolver)
 }
 
+// NewGraphQLHandler creates graphql.Handler which throttles the requests
+// exceeding the rate limit of GraphQL API.
+func NewGraphQLHandler(
+	handler graphql.GraphGopherHandler,
+	throttler request.Throttler,
+	rateLimitPolicy ratelimit.Policy,
+) graphql.Handler {
+	return throttler.ThrottleGraphQL("graphql", rateLimitPolicy.GraphQL, handler)
+}
+
 // GraphQLPath represents the path for GraphQL APIs.
 type GraphQLPath string
 
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/rpc"
+	"github.com/short-d/app/fw/security"
+	"github.com/short-d/short/backend/app/adapter/grpcapi"
+	"github.com/short-d/short/backend/app/adapter/kgs"
+)
+
+// GRPCHealthCheckInterval represents the time between two health checks of
+// the dependencies of gRPC API.
+type GRPCHealthCheckInterval time.Duration
+
+// EnableGRPCReflection represents whether gRPC server reflection is enabled.
+type EnableGRPCReflection bool
+
+// NewGRPCHealthServer creates HealthServer which reports the status of the
+// database and key generation service.
+func NewGRPCHealthServer(
+	interval GRPCHealthCheckInterval,
+	sqlDB *sql.DB,
+	kgsRPC kgs.RPC,
+	logger logger.Logger,
+) grpcapi.HealthServer {
+	dependencies := map[string]grpcapi.Dependency{
+		"db":  sqlDB,
+		"kgs": kgsRPC,
+	}
+	return grpcapi.NewHealthServer(time.Duration(interval), dependencies, logger)
+}
+
+// NewGRPCService creates gRPC service with EnableGRPCReflection to uniquely
+// identify the reflection flag during dependency injection.
+func NewGRPCService(
+	logger logger.Logger,
+	api rpc.API,
+	securityPolicy security.Policy,
+	healthServer grpcapi.HealthServer,
+	enableReflection EnableGRPCReflection,
+	observability grpcapi.Observability,
+	apiKeyAuth grpcapi.APIKeyAuth,
+) (grpcapi.Service, error) {
+	return grpcapi.NewService(
+		logger,
+		api,
+		securityPolicy,
+		healthServer,
+		bool(enableReflection),
+		observability,
+		apiKeyAuth,
+	)
+}
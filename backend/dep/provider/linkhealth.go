@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/adapter/linkhealth"
+	"github.com/short-d/short/backend/app/adapter/netguard"
+	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+)
+
+// LinkProbeTimeout represents the maximum time to wait for the destination of
+// a short link to respond.
+type LinkProbeTimeout time.Duration
+
+// LinkHealthRunInterval represents the time between two runs of the link
+// health checker.
+type LinkHealthRunInterval time.Duration
+
+// NewHTTPProber creates HTTPProber with timeout to uniquely identify timeout
+// during dependency injection. Long links are supplied by users, so the prober
+// is not allowed to reach private addresses.
+func NewHTTPProber(client http.Client, timeout LinkProbeTimeout) linkhealth.HTTPProber {
+	client.Timeout = time.Duration(timeout)
+	client = linkhealth.LimitResponseBody(netguard.Guard(client))
+	return linkhealth.NewHTTPProber(webreq.NewHTTP(client))
+}
+
+// NewLinkHealthJob creates link health checking Job with interval to uniquely
+// identify interval during dependency injection.
+func NewLinkHealthJob(
+	checker shortlink.HealthChecker,
+	instrumentationFactory request.InstrumentationFactory,
+	interval LinkHealthRunInterval,
+) linkhealth.Job {
+	return linkhealth.NewJob(checker, instrumentationFactory, time.Duration(interval))
+}
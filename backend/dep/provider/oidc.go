@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"database/sql"
+
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/webreq"
+	"github.com/short-d/short/backend/app/adapter/oidc"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/usecase/sso"
+)
+
+// NewOIDCIssuer creates Issuer for the OpenID Connect identity provider in
+// the config.
+func NewOIDCIssuer(req webreq.HTTP, config oidc.Config) oidc.Issuer {
+	return oidc.NewIssuer(req, config.IssuerURL)
+}
+
+// NewOIDCIdentityProvider creates IdentityProvider for the OpenID Connect
+// identity provider in the config, deriving the PKCE code verifiers from
+// secret.
+func NewOIDCIdentityProvider(
+	issuer oidc.Issuer,
+	req webreq.HTTP,
+	config oidc.Config,
+	secret JwtSecret,
+) oidc.IdentityProvider {
+	return oidc.NewIdentityProvider(issuer, req, config, []byte(secret))
+}
+
+// NewOIDCAccountLinker creates OIDCAccountLinker which stores the linked
+// accounts under the name of the identity provider.
+func NewOIDCAccountLinker(
+	factory sso.AccountLinkerFactory,
+	db *sql.DB,
+	logger logger.Logger,
+	config oidc.Config,
+) oidc.AccountLinker {
+	ssoAccountRepo := sqldb.NewSSOAccountSQL(db, logger, config.Name)
+	return oidc.AccountLinker(factory.NewAccountLinker(ssoAccountRepo))
+}
+
+// NewOIDCSSO creates OIDCSingleSignOn.
+func NewOIDCSSO(
+	ssoFactory sso.Factory,
+	identityProvider oidc.IdentityProvider,
+	account oidc.Account,
+	linker oidc.AccountLinker,
+	config oidc.Config,
+) oidc.SingleSignOn {
+	return oidc.SingleSignOn(
+		ssoFactory.NewSingleSignOn(
+			config.Name,
+			identityProvider,
+			account,
+			sso.AccountLinker(linker)),
+	)
+}
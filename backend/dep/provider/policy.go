@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/short-d/short/backend/app/fw/filesystem"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
+)
+
+// AccessPolicyPath represents the location of the file listing the
+// policies which grant permissions on resources.
+type AccessPolicyPath string
+
+type authorizationPolicies struct {
+	Policies []authorizationPolicy `json:"policies"`
+}
+
+type authorizationPolicy struct {
+	Roles        []string                 `json:"roles"`
+	AllUsers     bool                     `json:"all_users"`
+	Permission   string                   `json:"permission"`
+	ResourceType string                   `json:"resource_type"`
+	Conditions   []authorizationCondition `json:"conditions"`
+}
+
+type authorizationCondition struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewAccessPolicies loads authorization policies from the file at
+// AccessPolicyPath.
+func NewAccessPolicies(
+	policyPath AccessPolicyPath,
+	fileSystem filesystem.FileSystem,
+) ([]policy.Policy, error) {
+	buf, err := fileSystem.ReadFile(string(policyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var policies authorizationPolicies
+	err = json.Unmarshal(buf, &policies)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]policy.Definition, 0, len(policies.Policies))
+	for _, p := range policies.Policies {
+		conditions := make([]policy.ConditionDefinition, 0, len(p.Conditions))
+		for _, condition := range p.Conditions {
+			conditions = append(conditions, policy.ConditionDefinition{
+				Type:  condition.Type,
+				Name:  condition.Name,
+				Value: condition.Value,
+			})
+		}
+		definitions = append(definitions, policy.Definition{
+			Roles:        p.Roles,
+			AllUsers:     p.AllUsers,
+			Permission:   p.Permission,
+			ResourceType: p.ResourceType,
+			Conditions:   conditions,
+		})
+	}
+	return policy.NewPolicies(definitions)
+}
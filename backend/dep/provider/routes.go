@@ -1,17 +1,22 @@
 package provider
 
 import (
+	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/app/fw/router"
 	"github.com/short-d/app/fw/timer"
 	"github.com/short-d/short/backend/app/adapter/facebook"
 	"github.com/short-d/short/backend/app/adapter/github"
 	"github.com/short-d/short/backend/app/adapter/google"
+	"github.com/short-d/short/backend/app/adapter/oidc"
 	"github.com/short-d/short/backend/app/adapter/request"
 	"github.com/short-d/short/backend/app/adapter/routing"
 	"github.com/short-d/short/backend/app/usecase/authenticator"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
 	"github.com/short-d/short/backend/app/usecase/feature"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 	"github.com/short-d/short/backend/app/usecase/search"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/sso"
 )
 
 // WebFrontendURL represents the URL of the web frontend
@@ -26,29 +31,55 @@ type OpenAPISpecPath string
 // NewShortRoutes creates HTTP routes for Short API with WwwRoot to uniquely identify WwwRoot during dependency injection.
 func NewShortRoutes(
 	instrumentationFactory request.InstrumentationFactory,
+	logger logger.Logger,
+	client request.Client,
 	webFrontendURL WebFrontendURL,
 	timer timer.Timer,
 	shortLinkRetriever shortlink.Retriever,
+	shortLinkCreator shortlink.Creator,
+	shortLinkUpdater shortlink.Updater,
+	shortLinkDeleter shortlink.Deleter,
+	shortLinkAnalytics shortlink.Analytics,
 	featureDecisionMakerFactory feature.DecisionMakerFactory,
 	githubSSO github.SingleSignOn,
 	facebookSSO facebook.SingleSignOn,
 	googleSSO google.SingleSignOn,
+	oidcSSO oidc.SingleSignOn,
+	stateSigner sso.StateSigner,
+	accountManager sso.AccountManager,
+	emailSignIn emailsignin.EmailSignIn,
 	authenticator authenticator.Authenticator,
+	thirdPartyApp authenticator.ThirdPartyApp,
 	search search.Search,
+	throttler request.Throttler,
+	rateLimitPolicy ratelimit.Policy,
 	swaggerUIDir SwaggerUIDir,
 	openAPISpecPath OpenAPISpecPath,
 ) []router.Route {
 	return routing.NewShort(
 		instrumentationFactory,
+		logger,
+		client,
 		string(webFrontendURL),
 		timer,
 		shortLinkRetriever,
+		shortLinkCreator,
+		shortLinkUpdater,
+		shortLinkDeleter,
+		shortLinkAnalytics,
 		featureDecisionMakerFactory,
 		githubSSO,
 		facebookSSO,
 		googleSSO,
+		oidcSSO,
+		stateSigner,
+		accountManager,
+		emailSignIn,
 		authenticator,
+		thirdPartyApp,
 		search,
+		throttler,
+		rateLimitPolicy,
 		string(swaggerUIDir),
 		string(openAPISpecPath),
 	)
@@ -0,0 +1,14 @@
+package provider
+
+import (
+	"net/http"
+
+	"github.com/short-d/short/backend/app/adapter/netguard"
+	"github.com/short-d/short/backend/app/adapter/scraper"
+)
+
+// NewMetaTag creates MetaTag scraper which is not allowed to reach private
+// addresses since long links are supplied by users.
+func NewMetaTag(client http.Client, limit scraper.Limit) scraper.MetaTag {
+	return scraper.NewMetaTag(netguard.Guard(client), limit)
+}
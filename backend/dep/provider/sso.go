@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"database/sql"
+
+	"github.com/short-d/app/fw/crypto"
+	"github.com/short-d/app/fw/logger"
+	"github.com/short-d/app/fw/timer"
+	"github.com/short-d/short/backend/app/adapter/oidc"
+	"github.com/short-d/short/backend/app/adapter/sqldb"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/repository"
+	"github.com/short-d/short/backend/app/usecase/sso"
+)
+
+// NewAccountManager creates AccountManager with the account mappings of all
+// supported identity providers, named the same as their SingleSignOn.
+func NewAccountManager(
+	githubSSOMap sqldb.GithubSSOSql,
+	facebookSSOMap sqldb.FacebookSSOSql,
+	googleSSOMap sqldb.GoogleSSOSql,
+	db *sql.DB,
+	logger logger.Logger,
+	oidcConfig oidc.Config,
+	userMerger sqldb.UserMergerSQL,
+	twoFactorRepo sqldb.TwoFactorSQL,
+	rbac rbac.RBAC,
+	tokenizer crypto.Tokenizer,
+	timer timer.Timer,
+) sso.AccountManager {
+	ssoMaps := map[string]repository.SSOMap{
+		"github":        githubSSOMap,
+		"facebook":      facebookSSOMap,
+		"google":        googleSSOMap,
+		oidcConfig.Name: sqldb.NewSSOAccountSQL(db, logger, oidcConfig.Name),
+	}
+	return sso.NewAccountManager(ssoMaps, userMerger, twoFactorRepo, rbac, tokenizer, timer)
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/short-d/short/backend/app/fw/filesystem"
+	"github.com/short-d/short/backend/app/usecase/validator"
+)
+
+// AliasWordListPath represents the location of the file listing reserved
+// aliases and words blocked from aliases.
+type AliasWordListPath string
+
+type aliasWordList struct {
+	Reserved []string `json:"reserved"`
+	Blocked  []string `json:"blocked"`
+}
+
+// NewAliasWordList loads alias word list from the file at AliasWordListPath.
+func NewAliasWordList(
+	wordListPath AliasWordListPath,
+	fileSystem filesystem.FileSystem,
+) (validator.AliasWordList, error) {
+	buf, err := fileSystem.ReadFile(string(wordListPath))
+	if err != nil {
+		return validator.AliasWordList{}, err
+	}
+
+	var wordList aliasWordList
+	err = json.Unmarshal(buf, &wordList)
+	if err != nil {
+		return validator.AliasWordList{}, err
+	}
+	return validator.NewAliasWordList(wordList.Reserved, wordList.Blocked), nil
+}
@@ -28,32 +28,56 @@ import (
 	"github.com/short-d/short/backend/app/adapter/gqlapi/resolver"
 	"github.com/short-d/short/backend/app/adapter/grpcapi"
 	"github.com/short-d/short/backend/app/adapter/kgs"
+	"github.com/short-d/short/backend/app/adapter/linkhealth"
+	"github.com/short-d/short/backend/app/adapter/oidc"
 	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/adapter/scraper"
+	"github.com/short-d/short/backend/app/adapter/smtp"
 	"github.com/short-d/short/backend/app/adapter/sqldb"
 	"github.com/short-d/short/backend/app/fw/filesystem"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
+	"github.com/short-d/short/backend/app/usecase/emailsignin"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
 	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 	"github.com/short-d/short/backend/app/usecase/validator"
 	"github.com/short-d/short/backend/dep/provider"
 	"github.com/short-d/short/backend/tool"
 )
 
 var authenticatorSet = wire.NewSet(
+	wire.Bind(new(repository.Session), new(sqldb.SessionSQL)),
+	sqldb.NewSessionSQL,
 	provider.NewJwtGo,
 	provider.NewAuthenticator,
 )
 
 var authorizerSet = wire.NewSet(
 	wire.Bind(new(repository.UserRole), new(sqldb.UserRoleSQL)),
+	wire.Bind(new(repository.Role), new(sqldb.RoleSQL)),
 	sqldb.NewUserRoleSQL,
-	rbac.NewRBAC,
-	authorizer.NewAuthorizer,
+	sqldb.NewRoleSQL,
+	rbac.NewRoleDefinitions,
+	rbac.NewCustomRBAC,
+	provider.NewAccessPolicies,
+	authorizer.NewPolicyAuthorizer,
+	wire.Bind(new(repository.ShortLinkAttribute), new(sqldb.ShortLinkAttributeSQL)),
+	sqldb.NewShortLinkAttributeSQL,
+	policy.NewResourceFinder,
 )
 
 var observabilitySet = wire.NewSet(
@@ -92,12 +116,38 @@ var googleAPISet = wire.NewSet(
 	google.NewAPI,
 )
 
+var oidcAPISet = wire.NewSet(
+	provider.NewOIDCIssuer,
+	provider.NewOIDCIdentityProvider,
+	oidc.NewAccount,
+)
+
 var keyGenSet = wire.NewSet(
 	wire.Bind(new(keygen.KeyFetcher), new(kgs.RPC)),
 	provider.NewKgsRPC,
 	provider.NewKeyGenerator,
 )
 
+var rateLimitSet = wire.NewSet(
+	wire.Bind(new(ratelimit.Store), new(ratelimit.MemoryStore)),
+	ratelimit.NewMemoryStore,
+	ratelimit.NewLimiter,
+	request.NewThrottler,
+)
+
+var twoFactorSet = wire.NewSet(
+	wire.Bind(new(repository.TwoFactor), new(sqldb.TwoFactorSQL)),
+	sqldb.NewTwoFactorSQL,
+	twofactor.NewTwoFactor,
+)
+
+var shortLinkAnalyticsSet = wire.NewSet(
+	wire.Bind(new(shortlink.Analytics), new(shortlink.AnalyticsPersist)),
+	wire.Bind(new(repository.ShortLinkVisit), new(sqldb.ShortLinkVisitSQL)),
+	sqldb.NewShortLinkVisitSQL,
+	shortlink.NewAnalyticsPersist,
+)
+
 var featureDecisionSet = wire.NewSet(
 	wire.Bind(new(repository.FeatureToggle), new(sqldb.FeatureToggleSQL)),
 	sqldb.NewFeatureToggleSQL,
@@ -147,29 +197,89 @@ func InjectGRPCService(
 	logLevel logger.LogLevel,
 	sqlDB *sql.DB,
 	securityPolicy security.Policy,
+	enableReflection provider.EnableGRPCReflection,
+	healthCheckInterval provider.GRPCHealthCheckInterval,
+	jwtSecret provider.JwtSecret,
+	bufferSize provider.KeyGenBufferSize,
+	kgsRPCConfig provider.KgsRPCConfig,
+	tokenValidDuration provider.TokenValidDuration,
+	refreshTokenValidDuration provider.RefreshTokenValidDuration,
 	dataDogAPIKey provider.DataDogAPIKey,
-) (service.GRPC, error) {
+	segmentAPIKey provider.SegmentAPIKey,
+	ipStackAPIKey provider.IPStackAPIKey,
+	googleAPIKey provider.GoogleAPIKey,
+	aliasPolicy normalizer.AliasPolicy,
+	aliasWordListPath provider.AliasWordListPath,
+	accessPolicyPath provider.AccessPolicyPath,
+	longLinkPolicy validator.LongLinkPolicy,
+	redirectPolicy shortlink.RedirectPolicy,
+	scrapePolicy shortlink.ScrapePolicy,
+	scrapeLimit scraper.Limit,
+	quotaPolicy quota.Policy,
+) (grpcapi.Service, error) {
 	wire.Build(
 		wire.Bind(new(timer.Timer), new(timer.System)),
+		wire.Bind(new(filesystem.FileSystem), new(filesystem.Local)),
+		wire.Bind(new(risk.BlackList), new(google.SafeBrowsing)),
 		wire.Bind(new(repository.ShortLink), new(sqldb.ShortLinkSQL)),
 		wire.Bind(new(rpc.API), new(grpcapi.Short)),
+		wire.Bind(new(repository.UserShortLink), new(sqldb.UserShortLinkSQL)),
+		wire.Bind(new(repository.AppShortLink), new(sqldb.AppShortLinkSQL)),
+		wire.Bind(new(repository.APIKey), new(sqldb.APIKeySQL)),
+		wire.Bind(new(repository.App), new(sqldb.AppSQL)),
+		wire.Bind(new(geo.Geo), new(geo.IPStack)),
+
+		wire.Bind(new(shortlink.Retriever), new(shortlink.RetrieverPersist)),
+		wire.Bind(new(shortlink.Creator), new(shortlink.CreatorPersist)),
+		wire.Bind(new(shortlink.Updater), new(shortlink.UpdaterPersist)),
+		wire.Bind(new(shortlink.MetaTagQueue), new(shortlink.MetaTagScrapeQueue)),
+		wire.Bind(new(shortlink.MetaTagScraper), new(scraper.MetaTag)),
 		wire.Bind(new(shortlink.MetaTag), new(shortlink.MetaTagPersist)),
 
 		observabilitySet,
+		authenticatorSet,
+		authorizerSet,
+		keyGenSet,
 
 		timer.NewSystem,
+		filesystem.NewLocal,
 		webreq.NewHTTPClient,
 		webreq.NewHTTP,
 		env.NewDeployment,
-		service.NewGRPC,
+		provider.NewIPStack,
+		provider.NewGRPCService,
+		provider.NewGRPCHealthServer,
 
+		provider.NewSafeBrowsing,
+		risk.NewDetector,
+		normalizer.NewAlias,
+		normalizer.NewLongLink,
+		provider.NewAliasWordList,
+		validator.NewLongLink,
+		validator.NewCustomAlias,
+		validator.NewImageURL,
 		sqldb.NewShortLinkSQL,
+		sqldb.NewUserShortLinkSQL,
+		sqldb.NewAppShortLinkSQL,
+		sqldb.NewAPIKeySQL,
+		sqldb.NewAppSQL,
+		authenticator.NewThirdPartyApp,
+		shortlink.NewRetrieverPersist,
+		shortlink.NewCreatorPersist,
+		quota.NewQuota,
+		shortlink.NewUpdaterPersist,
+		shortlink.NewRedirectResolver,
+		shortlink.NewMetaTagScrapeQueue,
 		shortlink.NewMetaTagPersist,
+		provider.NewMetaTag,
 		grpcapi.NewShort,
 
+		grpcapi.NewObservability,
+		grpcapi.NewAPIKeyAuth,
 		grpcapi.NewMetaTagServer,
+		grpcapi.NewShortLinkServer,
 	)
-	return service.GRPC{}, nil
+	return grpcapi.Service{}, nil
 }
 
 // InjectGraphQLService creates GraphQL service with configured dependencies.
@@ -186,35 +296,60 @@ func InjectGraphQLService(
 	bufferSize provider.KeyGenBufferSize,
 	kgsRPCConfig provider.KgsRPCConfig,
 	tokenValidDuration provider.TokenValidDuration,
+	refreshTokenValidDuration provider.RefreshTokenValidDuration,
 	dataDogAPIKey provider.DataDogAPIKey,
 	segmentAPIKey provider.SegmentAPIKey,
 	ipStackAPIKey provider.IPStackAPIKey,
 	googleAPIKey provider.GoogleAPIKey,
+	aliasPolicy normalizer.AliasPolicy,
+	aliasWordListPath provider.AliasWordListPath,
+	accessPolicyPath provider.AccessPolicyPath,
+	longLinkPolicy validator.LongLinkPolicy,
+	redirectPolicy shortlink.RedirectPolicy,
+	scrapePolicy shortlink.ScrapePolicy,
+	scrapeLimit scraper.Limit,
+	rateLimitPolicy ratelimit.Policy,
+	quotaPolicy quota.Policy,
+	twoFactorPolicy rbac.TwoFactorPolicy,
+	oidcConfig oidc.Config,
 ) (service.GraphQL, error) {
 	wire.Build(
 		wire.Bind(new(timer.Timer), new(timer.System)),
-		wire.Bind(new(graphql.Handler), new(graphql.GraphGopherHandler)),
 		wire.Bind(new(graphql.WebUI), new(graphql.GraphiQL)),
 
 		wire.Bind(new(filesystem.FileSystem), new(filesystem.Local)),
 		wire.Bind(new(risk.BlackList), new(google.SafeBrowsing)),
 		wire.Bind(new(repository.UserShortLink), new(sqldb.UserShortLinkSQL)),
+		wire.Bind(new(repository.AppShortLink), new(sqldb.AppShortLinkSQL)),
 		wire.Bind(new(repository.ChangeLog), new(sqldb.ChangeLogSQL)),
 		wire.Bind(new(repository.UserChangeLog), new(sqldb.UserChangeLogSQL)),
 		wire.Bind(new(repository.ShortLink), new(sqldb.ShortLinkSQL)),
+		wire.Bind(new(repository.APIKey), new(sqldb.APIKeySQL)),
+		wire.Bind(new(repository.App), new(sqldb.AppSQL)),
+		wire.Bind(new(repository.User), new(sqldb.UserSQL)),
+		wire.Bind(new(repository.RoleAssignment), new(sqldb.RoleAssignmentSQL)),
 
 		wire.Bind(new(changelog.ChangeLog), new(changelog.Persist)),
+		wire.Bind(new(thirdparty.Registry), new(thirdparty.Persist)),
 		wire.Bind(new(shortlink.Retriever), new(shortlink.RetrieverPersist)),
 		wire.Bind(new(shortlink.Creator), new(shortlink.CreatorPersist)),
 		wire.Bind(new(shortlink.Updater), new(shortlink.UpdaterPersist)),
+		wire.Bind(new(shortlink.MetaTagQueue), new(shortlink.MetaTagScrapeQueue)),
+		wire.Bind(new(shortlink.MetaTagScraper), new(scraper.MetaTag)),
+		wire.Bind(new(shortlink.MetaTag), new(shortlink.MetaTagPersist)),
+		wire.Bind(new(shortlink.Attribute), new(shortlink.AttributePersist)),
 
 		observabilitySet,
 		authenticatorSet,
 		authorizerSet,
 		keyGenSet,
+		rateLimitSet,
+		twoFactorSet,
+		shortLinkAnalyticsSet,
 
 		env.NewDeployment,
 		provider.NewGraphQLService,
+		provider.NewGraphQLHandler,
 		graphql.NewGraphGopherHandler,
 		provider.NewGraphiQL,
 		webreq.NewHTTPClient,
@@ -232,13 +367,38 @@ func InjectGraphQLService(
 		sqldb.NewUserChangeLogSQL,
 		sqldb.NewShortLinkSQL,
 		sqldb.NewUserShortLinkSQL,
+		sqldb.NewAppShortLinkSQL,
+		sqldb.NewAPIKeySQL,
+		sqldb.NewAppSQL,
+		sqldb.NewUserSQL,
+		sqldb.NewRoleAssignmentSQL,
 
+		normalizer.NewAlias,
+		normalizer.NewLongLink,
+		provider.NewAliasWordList,
 		validator.NewLongLink,
 		validator.NewCustomAlias,
+		validator.NewImageURL,
 		changelog.NewPersist,
 		shortlink.NewRetrieverPersist,
 		shortlink.NewCreatorPersist,
+		quota.NewQuota,
 		shortlink.NewUpdaterPersist,
+		shortlink.NewRedirectResolver,
+		shortlink.NewMetaTagScrapeQueue,
+		shortlink.NewMetaTagPersist,
+		shortlink.NewAttributePersist,
+		provider.NewMetaTag,
+		authenticator.NewThirdPartyApp,
+		thirdparty.NewPersist,
+		session.NewManager,
+		userrole.NewManager,
+		customrole.NewManager,
+		sqldb.NewGithubSSOSql,
+		sqldb.NewFacebookSSOSql,
+		sqldb.NewGoogleSSOSql,
+		sqldb.NewUserMergerSQL,
+		provider.NewAccountManager,
 	)
 	return service.GraphQL{}, nil
 }
@@ -257,26 +417,53 @@ func InjectRoutingService(
 	googleClientID provider.GoogleClientID,
 	googleClientSecret provider.GoogleClientSecret,
 	googleRedirectURI provider.GoogleRedirectURI,
+	oidcConfig oidc.Config,
+	smtpConfig smtp.Config,
+	emailSignInCallbackURL provider.EmailSignInCallbackURL,
 	jwtSecret provider.JwtSecret,
 	bufferSize provider.KeyGenBufferSize,
 	kgsRPCConfig provider.KgsRPCConfig,
 	webFrontendURL provider.WebFrontendURL,
 	tokenValidDuration provider.TokenValidDuration,
+	refreshTokenValidDuration provider.RefreshTokenValidDuration,
 	searchTimeout provider.SearchTimeout,
 	swaggerUIDir provider.SwaggerUIDir,
 	openAPISpecPath provider.OpenAPISpecPath,
 	dataDogAPIKey provider.DataDogAPIKey,
 	segmentAPIKey provider.SegmentAPIKey,
 	ipStackAPIKey provider.IPStackAPIKey,
+	googleAPIKey provider.GoogleAPIKey,
+	aliasPolicy normalizer.AliasPolicy,
+	aliasWordListPath provider.AliasWordListPath,
+	accessPolicyPath provider.AccessPolicyPath,
+	longLinkPolicy validator.LongLinkPolicy,
+	redirectPolicy shortlink.RedirectPolicy,
+	scrapePolicy shortlink.ScrapePolicy,
+	scrapeLimit scraper.Limit,
+	rateLimitPolicy ratelimit.Policy,
+	quotaPolicy quota.Policy,
+	twoFactorPolicy rbac.TwoFactorPolicy,
 ) (service.Routing, error) {
 	wire.Build(
 		wire.Bind(new(timer.Timer), new(timer.System)),
 		wire.Bind(new(geo.Geo), new(geo.IPStack)),
+		wire.Bind(new(filesystem.FileSystem), new(filesystem.Local)),
+		wire.Bind(new(risk.BlackList), new(google.SafeBrowsing)),
 
 		wire.Bind(new(shortlink.Retriever), new(shortlink.RetrieverPersist)),
+		wire.Bind(new(shortlink.Creator), new(shortlink.CreatorPersist)),
+		wire.Bind(new(shortlink.Updater), new(shortlink.UpdaterPersist)),
+		wire.Bind(new(shortlink.Deleter), new(shortlink.DeleterPersist)),
+		wire.Bind(new(shortlink.MetaTagQueue), new(shortlink.MetaTagScrapeQueue)),
+		wire.Bind(new(shortlink.MetaTagScraper), new(scraper.MetaTag)),
 		wire.Bind(new(repository.UserShortLink), new(sqldb.UserShortLinkSQL)),
+		wire.Bind(new(repository.AppShortLink), new(sqldb.AppShortLinkSQL)),
 		wire.Bind(new(repository.User), new(sqldb.UserSQL)),
 		wire.Bind(new(repository.ShortLink), new(sqldb.ShortLinkSQL)),
+		wire.Bind(new(repository.APIKey), new(sqldb.APIKeySQL)),
+		wire.Bind(new(repository.App), new(sqldb.AppSQL)),
+		wire.Bind(new(repository.EmailSignInToken), new(sqldb.EmailSignInTokenSQL)),
+		wire.Bind(new(emailsignin.Mailer), new(smtp.Mailer)),
 
 		observabilitySet,
 		authenticatorSet,
@@ -284,8 +471,12 @@ func InjectRoutingService(
 		githubAPISet,
 		facebookAPISet,
 		googleAPISet,
+		oidcAPISet,
 		keyGenSet,
 		featureDecisionSet,
+		rateLimitSet,
+		twoFactorSet,
+		shortLinkAnalyticsSet,
 
 		service.NewRouting,
 		webreq.NewHTTPClient,
@@ -301,22 +492,90 @@ func InjectRoutingService(
 		provider.NewFacebookSSO,
 		provider.NewGoogleAccountLinker,
 		provider.NewGoogleSSO,
+		provider.NewOIDCAccountLinker,
+		provider.NewOIDCSSO,
 		sqldb.NewGithubSSOSql,
 		sqldb.NewFacebookSSOSql,
 		sqldb.NewGoogleSSOSql,
 		sqldb.NewUserSQL,
 		sqldb.NewShortLinkSQL,
 		sqldb.NewUserShortLinkSQL,
+		sqldb.NewAppShortLinkSQL,
+		sqldb.NewAPIKeySQL,
+		sqldb.NewAppSQL,
 
+		filesystem.NewLocal,
+		provider.NewSafeBrowsing,
+		risk.NewDetector,
+		normalizer.NewAlias,
+		normalizer.NewLongLink,
+		provider.NewAliasWordList,
+		validator.NewLongLink,
+		validator.NewCustomAlias,
+		authenticator.NewThirdPartyApp,
 		sso.NewAccountLinkerFactory,
 		sso.NewFactory,
+		sso.NewStateSigner,
+		sqldb.NewUserMergerSQL,
+		provider.NewAccountManager,
+		sqldb.NewEmailSignInTokenSQL,
+		smtp.NewMailer,
+		provider.NewEmailSignIn,
 		shortlink.NewRetrieverPersist,
+		shortlink.NewCreatorPersist,
+		quota.NewQuota,
+		shortlink.NewUpdaterPersist,
+		shortlink.NewDeleterPersist,
+		shortlink.NewRedirectResolver,
+		shortlink.NewMetaTagScrapeQueue,
+		provider.NewMetaTag,
 		provider.NewSearch,
 		provider.NewShortRoutes,
 	)
 	return service.Routing{}, nil
 }
 
+// InjectLinkHealthJob creates link health checking Job with configured
+// dependencies.
+func InjectLinkHealthJob(
+	runtime env.Runtime,
+	prefix provider.LogPrefix,
+	logLevel logger.LogLevel,
+	sqlDB *sql.DB,
+	bufferSize provider.KeyGenBufferSize,
+	kgsRPCConfig provider.KgsRPCConfig,
+	dataDogAPIKey provider.DataDogAPIKey,
+	segmentAPIKey provider.SegmentAPIKey,
+	ipStackAPIKey provider.IPStackAPIKey,
+	aliasPolicy normalizer.AliasPolicy,
+	healthPolicy shortlink.HealthPolicy,
+	probeTimeout provider.LinkProbeTimeout,
+	runInterval provider.LinkHealthRunInterval,
+) (linkhealth.Job, error) {
+	wire.Build(
+		wire.Bind(new(timer.Timer), new(timer.System)),
+		wire.Bind(new(geo.Geo), new(geo.IPStack)),
+		wire.Bind(new(repository.ShortLink), new(sqldb.ShortLinkSQL)),
+		wire.Bind(new(shortlink.LinkProber), new(linkhealth.HTTPProber)),
+
+		observabilitySet,
+		keyGenSet,
+
+		webreq.NewHTTPClient,
+		webreq.NewHTTP,
+		timer.NewSystem,
+		provider.NewIPStack,
+		env.NewDeployment,
+
+		sqldb.NewShortLinkSQL,
+		normalizer.NewAlias,
+		shortlink.NewHealthChecker,
+		provider.NewHTTPProber,
+		provider.NewLinkHealthJob,
+	)
+	return linkhealth.Job{}, nil
+}
+
 // InjectDataTool creates data tool with configured dependencies.
 func InjectDataTool(
 	prefix provider.LogPrefix,
@@ -325,6 +584,7 @@ func InjectDataTool(
 	dbConnector db.Connector,
 	bufferSize provider.KeyGenBufferSize,
 	kgsRPCConfig provider.KgsRPCConfig,
+	aliasPolicy normalizer.AliasPolicy,
 ) (tool.Data, error) {
 	wire.Build(
 		wire.Bind(new(io.Output), new(io.StdOut)),
@@ -338,6 +598,7 @@ func InjectDataTool(
 		provider.NewLocalEntryRepo,
 		provider.NewLogger,
 		timer.NewSystem,
+		normalizer.NewAlias,
 		tool.NewData,
 	)
 	return tool.Data{}, nil
@@ -29,17 +29,31 @@ import (
 	"github.com/short-d/short/backend/app/adapter/gqlapi/resolver"
 	"github.com/short-d/short/backend/app/adapter/grpcapi"
 	"github.com/short-d/short/backend/app/adapter/kgs"
+	"github.com/short-d/short/backend/app/adapter/linkhealth"
+	"github.com/short-d/short/backend/app/adapter/oidc"
 	"github.com/short-d/short/backend/app/adapter/request"
+	"github.com/short-d/short/backend/app/adapter/scraper"
+	"github.com/short-d/short/backend/app/adapter/smtp"
 	"github.com/short-d/short/backend/app/adapter/sqldb"
 	"github.com/short-d/short/backend/app/fw/filesystem"
+	"github.com/short-d/short/backend/app/usecase/authenticator"
 	"github.com/short-d/short/backend/app/usecase/authorizer"
+	"github.com/short-d/short/backend/app/usecase/authorizer/policy"
 	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
 	"github.com/short-d/short/backend/app/usecase/changelog"
+	"github.com/short-d/short/backend/app/usecase/customrole"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
 	"github.com/short-d/short/backend/app/usecase/repository"
 	"github.com/short-d/short/backend/app/usecase/risk"
+	"github.com/short-d/short/backend/app/usecase/session"
 	"github.com/short-d/short/backend/app/usecase/shortlink"
 	"github.com/short-d/short/backend/app/usecase/sso"
+	"github.com/short-d/short/backend/app/usecase/thirdparty"
+	"github.com/short-d/short/backend/app/usecase/twofactor"
+	"github.com/short-d/short/backend/app/usecase/userrole"
 	"github.com/short-d/short/backend/app/usecase/validator"
 	"github.com/short-d/short/backend/dep/provider"
 	"github.com/short-d/short/backend/tool"
@@ -67,7 +81,7 @@ func InjectEnv() env.Env {
 	return goDotEnv
 }
 
-func InjectGRPCService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, securityPolicy security.Policy, dataDogAPIKey provider.DataDogAPIKey) (service.GRPC, error) {
+func InjectGRPCService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, securityPolicy security.Policy, enableReflection provider.EnableGRPCReflection, healthCheckInterval provider.GRPCHealthCheckInterval, jwtSecret provider.JwtSecret, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, tokenValidDuration provider.TokenValidDuration, refreshTokenValidDuration provider.RefreshTokenValidDuration, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey, googleAPIKey provider.GoogleAPIKey, aliasPolicy normalizer.AliasPolicy, aliasWordListPath provider.AliasWordListPath, accessPolicyPath provider.AccessPolicyPath, longLinkPolicy validator.LongLinkPolicy, redirectPolicy shortlink.RedirectPolicy, scrapePolicy shortlink.ScrapePolicy, scrapeLimit scraper.Limit, quotaPolicy quota.Policy) (grpcapi.Service, error) {
 	system := timer.NewSystem()
 	program := runtime.NewProgram()
 	deployment := env.NewDeployment(runtime2)
@@ -76,18 +90,74 @@ func InjectGRPCService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel
 	http := webreq.NewHTTP(client)
 	entryRepository := provider.NewEntryRepositorySwitch(runtime2, deployment, stdOut, dataDogAPIKey, http)
 	loggerLogger := provider.NewLogger(prefix, logLevel, system, program, entryRepository)
-	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB)
-	metaTagPersist := shortlink.NewMetaTagPersist(shortLinkSQL)
+	alias := normalizer.NewAlias(aliasPolicy)
+	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB, alias)
+	userShortLinkSQL := sqldb.NewUserShortLinkSQL(sqlDB)
+	appShortLinkSQL := sqldb.NewAppShortLinkSQL(sqlDB)
+	imageURL := validator.NewImageURL()
+	metaTagPersist := shortlink.NewMetaTagPersist(shortLinkSQL, userShortLinkSQL, imageURL)
 	metaTagServiceServer := grpcapi.NewMetaTagServer(metaTagPersist)
-	short := grpcapi.NewShort(metaTagServiceServer)
-	grpc, err := service.NewGRPC(loggerLogger, short, securityPolicy)
+	rpc, err := provider.NewKgsRPC(kgsRPCConfig)
+	if err != nil {
+		return grpcapi.Service{}, err
+	}
+	keyGenerator, err := provider.NewKeyGenerator(bufferSize, rpc)
+	if err != nil {
+		return grpcapi.Service{}, err
+	}
+	longLink := validator.NewLongLink(longLinkPolicy)
+	local := filesystem.NewLocal()
+	aliasWordList, err := provider.NewAliasWordList(aliasWordListPath, local)
+	if err != nil {
+		return grpcapi.Service{}, err
+	}
+	customAlias := validator.NewCustomAlias(alias, aliasWordList)
+	normalizerLongLink := normalizer.NewLongLink()
+	safeBrowsing := provider.NewSafeBrowsing(googleAPIKey, http)
+	detector := risk.NewDetector(safeBrowsing)
+	userRoleSQL := sqldb.NewUserRoleSQL(sqlDB)
+	roleSQL := sqldb.NewRoleSQL(sqlDB)
+	roleDefinitions := rbac.NewRoleDefinitions(roleSQL, system)
+	rbacRBAC := rbac.NewCustomRBAC(userRoleSQL, roleDefinitions)
+	policyPolicy, err := provider.NewAccessPolicies(accessPolicyPath, local)
+	if err != nil {
+		return grpcapi.Service{}, err
+	}
+	authorizerAuthorizer := authorizer.NewPolicyAuthorizer(rbacRBAC, policyPolicy)
+	redirectResolver := shortlink.NewRedirectResolver(redirectPolicy, shortLinkSQL, alias)
+	metaTag := provider.NewMetaTag(client, scrapeLimit)
+	metaTagScrapeQueue := shortlink.NewMetaTagScrapeQueue(scrapePolicy, shortLinkSQL, metaTag, loggerLogger)
+	quotaQuota := quota.NewQuota(userShortLinkSQL, appShortLinkSQL, rbacRBAC, system, quotaPolicy)
+	creatorPersist := shortlink.NewCreatorPersist(shortLinkSQL, keyGenerator, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, redirectResolver, metaTagScrapeQueue, quotaQuota)
+	retrieverPersist := shortlink.NewRetrieverPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL)
+	shortLinkAttributeSQL := sqldb.NewShortLinkAttributeSQL(sqlDB)
+	resourceFinder := policy.NewResourceFinder(userShortLinkSQL, shortLinkAttributeSQL)
+	updaterPersist := shortlink.NewUpdaterPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, resourceFinder, redirectResolver, metaTagScrapeQueue)
+	tokenizer := provider.NewJwtGo(jwtSecret)
+	sessionSQL := sqldb.NewSessionSQL(sqlDB)
+	authenticatorAuthenticator := provider.NewAuthenticator(tokenizer, system, keyGenerator, sessionSQL, tokenValidDuration, refreshTokenValidDuration)
+	shortLinkServiceServer := grpcapi.NewShortLinkServer(creatorPersist, retrieverPersist, updaterPersist, authenticatorAuthenticator, system)
+	short := grpcapi.NewShort(metaTagServiceServer, shortLinkServiceServer)
+	apiKeySQL := sqldb.NewAPIKeySQL(sqlDB)
+	appSQL := sqldb.NewAppSQL(sqlDB)
+	thirdPartyApp := authenticator.NewThirdPartyApp(authorizerAuthorizer, tokenizer, keyGenerator, system, apiKeySQL, appSQL, loggerLogger)
+	grpcHealthServer := provider.NewGRPCHealthServer(healthCheckInterval, sqlDB, rpc, loggerLogger)
+	dataDog := provider.NewDataDogMetrics(dataDogAPIKey, http, system, runtime2)
+	segment := provider.NewSegment(segmentAPIKey, system, loggerLogger)
+	proxy := network.NewProxy()
+	ipStack := provider.NewIPStack(ipStackAPIKey, http, loggerLogger)
+	requestClient := request.NewClient(proxy, ipStack)
+	instrumentationFactory := request.NewInstrumentationFactory(loggerLogger, system, dataDog, segment, keyGenerator, requestClient)
+	observability := grpcapi.NewObservability(instrumentationFactory)
+	apiKeyAuth := grpcapi.NewAPIKeyAuth(thirdPartyApp)
+	grpcapiService, err := provider.NewGRPCService(loggerLogger, short, securityPolicy, grpcHealthServer, enableReflection, observability, apiKeyAuth)
 	if err != nil {
-		return service.GRPC{}, err
+		return grpcapi.Service{}, err
 	}
-	return grpc, nil
+	return grpcapiService, nil
 }
 
-func InjectGraphQLService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, graphqlSchemaPath provider.GraphQLSchemaPath, graphqlPath provider.GraphQLPath, graphiQLDefaultQuery provider.GraphiQLDefaultQuery, secret provider.ReCaptchaSecret, jwtSecret provider.JwtSecret, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, tokenValidDuration provider.TokenValidDuration, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey, googleAPIKey provider.GoogleAPIKey) (service.GraphQL, error) {
+func InjectGraphQLService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, graphqlSchemaPath provider.GraphQLSchemaPath, graphqlPath provider.GraphQLPath, graphiQLDefaultQuery provider.GraphiQLDefaultQuery, secret provider.ReCaptchaSecret, jwtSecret provider.JwtSecret, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, tokenValidDuration provider.TokenValidDuration, refreshTokenValidDuration provider.RefreshTokenValidDuration, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey, googleAPIKey provider.GoogleAPIKey, aliasPolicy normalizer.AliasPolicy, aliasWordListPath provider.AliasWordListPath, accessPolicyPath provider.AccessPolicyPath, longLinkPolicy validator.LongLinkPolicy, redirectPolicy shortlink.RedirectPolicy, scrapePolicy shortlink.ScrapePolicy, scrapeLimit scraper.Limit, rateLimitPolicy ratelimit.Policy, quotaPolicy quota.Policy, twoFactorPolicy rbac.TwoFactorPolicy, oidcConfig oidc.Config) (service.GraphQL, error) {
 	local := filesystem.NewLocal()
 	system := timer.NewSystem()
 	program := runtime.NewProgram()
@@ -97,9 +167,11 @@ func InjectGraphQLService(runtime2 env.Runtime, prefix provider.LogPrefix, logLe
 	http := webreq.NewHTTP(client)
 	entryRepository := provider.NewEntryRepositorySwitch(runtime2, deployment, stdOut, dataDogAPIKey, http)
 	loggerLogger := provider.NewLogger(prefix, logLevel, system, program, entryRepository)
-	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB)
+	alias := normalizer.NewAlias(aliasPolicy)
+	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB, alias)
 	userShortLinkSQL := sqldb.NewUserShortLinkSQL(sqlDB)
-	retrieverPersist := shortlink.NewRetrieverPersist(shortLinkSQL, userShortLinkSQL)
+	appShortLinkSQL := sqldb.NewAppShortLinkSQL(sqlDB)
+	retrieverPersist := shortlink.NewRetrieverPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL)
 	rpc, err := provider.NewKgsRPC(kgsRPCConfig)
 	if err != nil {
 		return service.GraphQL{}, err
@@ -108,34 +180,79 @@ func InjectGraphQLService(runtime2 env.Runtime, prefix provider.LogPrefix, logLe
 	if err != nil {
 		return service.GraphQL{}, err
 	}
-	longLink := validator.NewLongLink()
-	customAlias := validator.NewCustomAlias()
+	longLink := validator.NewLongLink(longLinkPolicy)
+	aliasWordList, err := provider.NewAliasWordList(aliasWordListPath, local)
+	if err != nil {
+		return service.GraphQL{}, err
+	}
+	customAlias := validator.NewCustomAlias(alias, aliasWordList)
 	safeBrowsing := provider.NewSafeBrowsing(googleAPIKey, http)
 	detector := risk.NewDetector(safeBrowsing)
-	creatorPersist := shortlink.NewCreatorPersist(shortLinkSQL, userShortLinkSQL, keyGenerator, longLink, customAlias, system, detector)
-	updaterPersist := shortlink.NewUpdaterPersist(shortLinkSQL, userShortLinkSQL, longLink, customAlias, system, detector)
+	userRoleSQL := sqldb.NewUserRoleSQL(sqlDB)
+	roleSQL := sqldb.NewRoleSQL(sqlDB)
+	roleDefinitions := rbac.NewRoleDefinitions(roleSQL, system)
+	rbacRBAC := rbac.NewCustomRBAC(userRoleSQL, roleDefinitions)
+	policyPolicy, err := provider.NewAccessPolicies(accessPolicyPath, local)
+	if err != nil {
+		return service.GraphQL{}, err
+	}
+	authorizerAuthorizer := authorizer.NewPolicyAuthorizer(rbacRBAC, policyPolicy)
+	normalizerLongLink := normalizer.NewLongLink()
+	redirectResolver := shortlink.NewRedirectResolver(redirectPolicy, shortLinkSQL, alias)
+	metaTag := provider.NewMetaTag(client, scrapeLimit)
+	metaTagScrapeQueue := shortlink.NewMetaTagScrapeQueue(scrapePolicy, shortLinkSQL, metaTag, loggerLogger)
+	quotaQuota := quota.NewQuota(userShortLinkSQL, appShortLinkSQL, rbacRBAC, system, quotaPolicy)
+	creatorPersist := shortlink.NewCreatorPersist(shortLinkSQL, keyGenerator, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, redirectResolver, metaTagScrapeQueue, quotaQuota)
+	shortLinkAttributeSQL := sqldb.NewShortLinkAttributeSQL(sqlDB)
+	resourceFinder := policy.NewResourceFinder(userShortLinkSQL, shortLinkAttributeSQL)
+	updaterPersist := shortlink.NewUpdaterPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, resourceFinder, redirectResolver, metaTagScrapeQueue)
+	imageURL := validator.NewImageURL()
+	metaTagPersist := shortlink.NewMetaTagPersist(shortLinkSQL, userShortLinkSQL, imageURL)
 	changeLogSQL := sqldb.NewChangeLogSQL(sqlDB)
 	userChangeLogSQL := sqldb.NewUserChangeLogSQL(sqlDB)
-	userRoleSQL := sqldb.NewUserRoleSQL(sqlDB)
-	rbacRBAC := rbac.NewRBAC(userRoleSQL)
-	authorizerAuthorizer := authorizer.NewAuthorizer(rbacRBAC)
 	persist := changelog.NewPersist(keyGenerator, system, changeLogSQL, userChangeLogSQL, authorizerAuthorizer)
 	reCaptcha := provider.NewReCaptchaService(http, secret)
 	verifier := provider.NewVerifier(deployment, reCaptcha)
 	tokenizer := provider.NewJwtGo(jwtSecret)
-	authenticator := provider.NewAuthenticator(tokenizer, system, tokenValidDuration)
-	resolverResolver := resolver.NewResolver(loggerLogger, retrieverPersist, creatorPersist, updaterPersist, persist, verifier, authenticator)
+	sessionSQL := sqldb.NewSessionSQL(sqlDB)
+	authenticatorAuthenticator := provider.NewAuthenticator(tokenizer, system, keyGenerator, sessionSQL, tokenValidDuration, refreshTokenValidDuration)
+	apiKeySQL := sqldb.NewAPIKeySQL(sqlDB)
+	appSQL := sqldb.NewAppSQL(sqlDB)
+	thirdPartyApp := authenticator.NewThirdPartyApp(authorizerAuthorizer, tokenizer, keyGenerator, system, apiKeySQL, appSQL, loggerLogger)
+	thirdpartyPersist := thirdparty.NewPersist(keyGenerator, system, appSQL)
+	manager := session.NewManager(sessionSQL, authorizerAuthorizer, system)
+	githubSSOSql := sqldb.NewGithubSSOSql(sqlDB, loggerLogger)
+	facebookSSOSql := sqldb.NewFacebookSSOSql(sqlDB, loggerLogger)
+	googleSSOSql := sqldb.NewGoogleSSOSql(sqlDB, loggerLogger)
+	userMergerSQL := sqldb.NewUserMergerSQL(sqlDB)
+	twoFactorSQL := sqldb.NewTwoFactorSQL(sqlDB)
+	accountManager := provider.NewAccountManager(githubSSOSql, facebookSSOSql, googleSSOSql, sqlDB, loggerLogger, oidcConfig, userMergerSQL, twoFactorSQL, rbacRBAC, tokenizer, system)
+	userSQL := sqldb.NewUserSQL(sqlDB)
+	memoryStore := ratelimit.NewMemoryStore(system)
+	limiter := ratelimit.NewLimiter(memoryStore, system)
+	twoFactor := twofactor.NewTwoFactor(twoFactorSQL, userSQL, rbacRBAC, twoFactorPolicy, limiter, authenticatorAuthenticator, tokenizer, system)
+	roleAssignmentSQL := sqldb.NewRoleAssignmentSQL(sqlDB)
+	userroleManager := userrole.NewManager(userSQL, roleAssignmentSQL, rbacRBAC, authorizerAuthorizer, system)
+	customroleManager := customrole.NewManager(roleSQL, roleDefinitions, authorizerAuthorizer, system)
+	shortLinkVisitSQL := sqldb.NewShortLinkVisitSQL(sqlDB)
+	analyticsPersist := shortlink.NewAnalyticsPersist(shortLinkSQL, shortLinkVisitSQL, authorizerAuthorizer, resourceFinder, system)
+	attributePersist := shortlink.NewAttributePersist(shortLinkSQL, shortLinkAttributeSQL, authorizerAuthorizer)
+	resolverResolver := resolver.NewResolver(loggerLogger, retrieverPersist, creatorPersist, updaterPersist, metaTagPersist, persist, verifier, authenticatorAuthenticator, thirdPartyApp, thirdpartyPersist, quotaQuota, manager, accountManager, twoFactor, userroleManager, customroleManager, analyticsPersist, attributePersist)
 	api, err := provider.NewShortGraphQLAPI(graphqlSchemaPath, local, resolverResolver)
 	if err != nil {
 		return service.GraphQL{}, err
 	}
 	graphGopherHandler := graphql.NewGraphGopherHandler(api)
+	proxy := network.NewProxy()
+	dataDog := provider.NewDataDogMetrics(dataDogAPIKey, http, system, runtime2)
+	throttler := request.NewThrottler(limiter, proxy, authenticatorAuthenticator, thirdPartyApp, dataDog, loggerLogger, system)
+	handler := provider.NewGraphQLHandler(graphGopherHandler, throttler, rateLimitPolicy)
 	graphiQL := provider.NewGraphiQL(graphqlPath, graphiQLDefaultQuery)
-	graphQL := provider.NewGraphQLService(graphqlPath, graphGopherHandler, graphiQL, loggerLogger)
+	graphQL := provider.NewGraphQLService(graphqlPath, handler, graphiQL, loggerLogger)
 	return graphQL, nil
 }
 
-func InjectRoutingService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, githubClientID provider.GithubClientID, githubClientSecret provider.GithubClientSecret, facebookClientID provider.FacebookClientID, facebookClientSecret provider.FacebookClientSecret, facebookRedirectURI provider.FacebookRedirectURI, googleClientID provider.GoogleClientID, googleClientSecret provider.GoogleClientSecret, googleRedirectURI provider.GoogleRedirectURI, jwtSecret provider.JwtSecret, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, webFrontendURL provider.WebFrontendURL, tokenValidDuration provider.TokenValidDuration, searchTimeout provider.SearchTimeout, swaggerUIDir provider.SwaggerUIDir, openAPISpecPath provider.OpenAPISpecPath, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey) (service.Routing, error) {
+func InjectRoutingService(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, githubClientID provider.GithubClientID, githubClientSecret provider.GithubClientSecret, facebookClientID provider.FacebookClientID, facebookClientSecret provider.FacebookClientSecret, facebookRedirectURI provider.FacebookRedirectURI, googleClientID provider.GoogleClientID, googleClientSecret provider.GoogleClientSecret, googleRedirectURI provider.GoogleRedirectURI, oidcConfig oidc.Config, smtpConfig smtp.Config, emailSignInCallbackURL provider.EmailSignInCallbackURL, jwtSecret provider.JwtSecret, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, webFrontendURL provider.WebFrontendURL, tokenValidDuration provider.TokenValidDuration, refreshTokenValidDuration provider.RefreshTokenValidDuration, searchTimeout provider.SearchTimeout, swaggerUIDir provider.SwaggerUIDir, openAPISpecPath provider.OpenAPISpecPath, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey, googleAPIKey provider.GoogleAPIKey, aliasPolicy normalizer.AliasPolicy, aliasWordListPath provider.AliasWordListPath, accessPolicyPath provider.AccessPolicyPath, longLinkPolicy validator.LongLinkPolicy, redirectPolicy shortlink.RedirectPolicy, scrapePolicy shortlink.ScrapePolicy, scrapeLimit scraper.Limit, rateLimitPolicy ratelimit.Policy, quotaPolicy quota.Policy, twoFactorPolicy rbac.TwoFactorPolicy) (service.Routing, error) {
 	system := timer.NewSystem()
 	program := runtime.NewProgram()
 	deployment := env.NewDeployment(runtime2)
@@ -158,18 +275,52 @@ func InjectRoutingService(runtime2 env.Runtime, prefix provider.LogPrefix, logLe
 	ipStack := provider.NewIPStack(ipStackAPIKey, http, loggerLogger)
 	requestClient := request.NewClient(proxy, ipStack)
 	instrumentationFactory := request.NewInstrumentationFactory(loggerLogger, system, dataDog, segment, keyGenerator, requestClient)
-	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB)
+	alias := normalizer.NewAlias(aliasPolicy)
+	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB, alias)
 	userShortLinkSQL := sqldb.NewUserShortLinkSQL(sqlDB)
-	retrieverPersist := shortlink.NewRetrieverPersist(shortLinkSQL, userShortLinkSQL)
-	featureToggleSQL := sqldb.NewFeatureToggleSQL(sqlDB)
+	appShortLinkSQL := sqldb.NewAppShortLinkSQL(sqlDB)
+	retrieverPersist := shortlink.NewRetrieverPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL)
+	longLink := validator.NewLongLink(longLinkPolicy)
+	local := filesystem.NewLocal()
+	aliasWordList, err := provider.NewAliasWordList(aliasWordListPath, local)
+	if err != nil {
+		return service.Routing{}, err
+	}
+	customAlias := validator.NewCustomAlias(alias, aliasWordList)
+	normalizerLongLink := normalizer.NewLongLink()
+	safeBrowsing := provider.NewSafeBrowsing(googleAPIKey, http)
+	detector := risk.NewDetector(safeBrowsing)
 	userRoleSQL := sqldb.NewUserRoleSQL(sqlDB)
-	rbacRBAC := rbac.NewRBAC(userRoleSQL)
-	authorizerAuthorizer := authorizer.NewAuthorizer(rbacRBAC)
+	roleSQL := sqldb.NewRoleSQL(sqlDB)
+	roleDefinitions := rbac.NewRoleDefinitions(roleSQL, system)
+	rbacRBAC := rbac.NewCustomRBAC(userRoleSQL, roleDefinitions)
+	policyPolicy, err := provider.NewAccessPolicies(accessPolicyPath, local)
+	if err != nil {
+		return service.Routing{}, err
+	}
+	authorizerAuthorizer := authorizer.NewPolicyAuthorizer(rbacRBAC, policyPolicy)
+	redirectResolver := shortlink.NewRedirectResolver(redirectPolicy, shortLinkSQL, alias)
+	metaTag := provider.NewMetaTag(client, scrapeLimit)
+	metaTagScrapeQueue := shortlink.NewMetaTagScrapeQueue(scrapePolicy, shortLinkSQL, metaTag, loggerLogger)
+	quotaQuota := quota.NewQuota(userShortLinkSQL, appShortLinkSQL, rbacRBAC, system, quotaPolicy)
+	creatorPersist := shortlink.NewCreatorPersist(shortLinkSQL, keyGenerator, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, redirectResolver, metaTagScrapeQueue, quotaQuota)
+	shortLinkAttributeSQL := sqldb.NewShortLinkAttributeSQL(sqlDB)
+	resourceFinder := policy.NewResourceFinder(userShortLinkSQL, shortLinkAttributeSQL)
+	updaterPersist := shortlink.NewUpdaterPersist(shortLinkSQL, userShortLinkSQL, appShortLinkSQL, longLink, customAlias, alias, normalizerLongLink, system, detector, authorizerAuthorizer, resourceFinder, redirectResolver, metaTagScrapeQueue)
+	deleterPersist := shortlink.NewDeleterPersist(shortLinkSQL, appShortLinkSQL)
+	shortLinkVisitSQL := sqldb.NewShortLinkVisitSQL(sqlDB)
+	analyticsPersist := shortlink.NewAnalyticsPersist(shortLinkSQL, shortLinkVisitSQL, authorizerAuthorizer, resourceFinder, system)
+	featureToggleSQL := sqldb.NewFeatureToggleSQL(sqlDB)
 	decisionMakerFactory := provider.NewFeatureDecisionMakerFactorySwitch(deployment, featureToggleSQL, authorizerAuthorizer)
 	tokenizer := provider.NewJwtGo(jwtSecret)
-	authenticator := provider.NewAuthenticator(tokenizer, system, tokenValidDuration)
-	factory := sso.NewFactory(authenticator)
+	sessionSQL := sqldb.NewSessionSQL(sqlDB)
+	authenticatorAuthenticator := provider.NewAuthenticator(tokenizer, system, keyGenerator, sessionSQL, tokenValidDuration, refreshTokenValidDuration)
+	twoFactorSQL := sqldb.NewTwoFactorSQL(sqlDB)
 	userSQL := sqldb.NewUserSQL(sqlDB)
+	memoryStore := ratelimit.NewMemoryStore(system)
+	limiter := ratelimit.NewLimiter(memoryStore, system)
+	twoFactor := twofactor.NewTwoFactor(twoFactorSQL, userSQL, rbacRBAC, twoFactorPolicy, limiter, authenticatorAuthenticator, tokenizer, system)
+	factory := sso.NewFactory(authenticatorAuthenticator, twoFactor)
 	accountLinkerFactory := sso.NewAccountLinkerFactory(keyGenerator, userSQL)
 	githubSSOSql := sqldb.NewGithubSSOSql(sqlDB, loggerLogger)
 	accountLinker := provider.NewGithubAccountLinker(accountLinkerFactory, githubSSOSql)
@@ -187,13 +338,62 @@ func InjectRoutingService(runtime2 env.Runtime, prefix provider.LogPrefix, logLe
 	googleSSOSql := sqldb.NewGoogleSSOSql(sqlDB, loggerLogger)
 	googleAccountLinker := provider.NewGoogleAccountLinker(accountLinkerFactory, googleSSOSql)
 	googleSingleSignOn := provider.NewGoogleSSO(factory, googleIdentityProvider, googleAccount, googleAccountLinker)
+	issuer := provider.NewOIDCIssuer(http, oidcConfig)
+	oidcIdentityProvider := provider.NewOIDCIdentityProvider(issuer, http, oidcConfig, jwtSecret)
+	oidcAccount := oidc.NewAccount(issuer, system, oidcConfig)
+	oidcAccountLinker := provider.NewOIDCAccountLinker(accountLinkerFactory, sqlDB, loggerLogger, oidcConfig)
+	oidcSingleSignOn := provider.NewOIDCSSO(factory, oidcIdentityProvider, oidcAccount, oidcAccountLinker, oidcConfig)
+	stateSigner := sso.NewStateSigner(tokenizer, system)
+	userMergerSQL := sqldb.NewUserMergerSQL(sqlDB)
+	accountManager := provider.NewAccountManager(githubSSOSql, facebookSSOSql, googleSSOSql, sqlDB, loggerLogger, oidcConfig, userMergerSQL, twoFactorSQL, rbacRBAC, tokenizer, system)
+	emailSignInTokenSQL := sqldb.NewEmailSignInTokenSQL(sqlDB)
+	mailer := smtp.NewMailer(smtpConfig, system)
+	emailSignIn, err := provider.NewEmailSignIn(emailSignInTokenSQL, userSQL, keyGenerator, mailer, limiter, rateLimitPolicy, tokenizer, system, twoFactor, emailSignInCallbackURL)
+	if err != nil {
+		return service.Routing{}, err
+	}
+	apiKeySQL := sqldb.NewAPIKeySQL(sqlDB)
+	appSQL := sqldb.NewAppSQL(sqlDB)
+	thirdPartyApp := authenticator.NewThirdPartyApp(authorizerAuthorizer, tokenizer, keyGenerator, system, apiKeySQL, appSQL, loggerLogger)
 	search := provider.NewSearch(loggerLogger, shortLinkSQL, userShortLinkSQL, searchTimeout)
-	v := provider.NewShortRoutes(instrumentationFactory, webFrontendURL, system, retrieverPersist, decisionMakerFactory, singleSignOn, facebookSingleSignOn, googleSingleSignOn, authenticator, search, swaggerUIDir, openAPISpecPath)
+	throttler := request.NewThrottler(limiter, proxy, authenticatorAuthenticator, thirdPartyApp, dataDog, loggerLogger, system)
+	v := provider.NewShortRoutes(instrumentationFactory, loggerLogger, requestClient, webFrontendURL, system, retrieverPersist, creatorPersist, updaterPersist, deleterPersist, analyticsPersist, decisionMakerFactory, singleSignOn, facebookSingleSignOn, googleSingleSignOn, oidcSingleSignOn, stateSigner, accountManager, emailSignIn, authenticatorAuthenticator, thirdPartyApp, search, throttler, rateLimitPolicy, swaggerUIDir, openAPISpecPath)
 	routing := service.NewRouting(loggerLogger, v)
 	return routing, nil
 }
 
-func InjectDataTool(prefix provider.LogPrefix, logLevel logger.LogLevel, dbConfig db.Config, dbConnector db.Connector, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig) (tool.Data, error) {
+func InjectLinkHealthJob(runtime2 env.Runtime, prefix provider.LogPrefix, logLevel logger.LogLevel, sqlDB *sql.DB, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, dataDogAPIKey provider.DataDogAPIKey, segmentAPIKey provider.SegmentAPIKey, ipStackAPIKey provider.IPStackAPIKey, aliasPolicy normalizer.AliasPolicy, healthPolicy shortlink.HealthPolicy, probeTimeout provider.LinkProbeTimeout, runInterval provider.LinkHealthRunInterval) (linkhealth.Job, error) {
+	alias := normalizer.NewAlias(aliasPolicy)
+	shortLinkSQL := sqldb.NewShortLinkSQL(sqlDB, alias)
+	client := webreq.NewHTTPClient()
+	httpProber := provider.NewHTTPProber(client, probeTimeout)
+	system := timer.NewSystem()
+	healthChecker := shortlink.NewHealthChecker(healthPolicy, shortLinkSQL, httpProber, system)
+	program := runtime.NewProgram()
+	deployment := env.NewDeployment(runtime2)
+	stdOut := io.NewStdOut()
+	http := webreq.NewHTTP(client)
+	entryRepository := provider.NewEntryRepositorySwitch(runtime2, deployment, stdOut, dataDogAPIKey, http)
+	loggerLogger := provider.NewLogger(prefix, logLevel, system, program, entryRepository)
+	dataDog := provider.NewDataDogMetrics(dataDogAPIKey, http, system, runtime2)
+	segment := provider.NewSegment(segmentAPIKey, system, loggerLogger)
+	rpc, err := provider.NewKgsRPC(kgsRPCConfig)
+	if err != nil {
+		return linkhealth.Job{}, err
+	}
+	keyGenerator, err := provider.NewKeyGenerator(bufferSize, rpc)
+	if err != nil {
+		return linkhealth.Job{}, err
+	}
+	proxy := network.NewProxy()
+	ipStack := provider.NewIPStack(ipStackAPIKey, http, loggerLogger)
+	requestClient := request.NewClient(proxy, ipStack)
+	instrumentationFactory := request.NewInstrumentationFactory(loggerLogger, system, dataDog, segment, keyGenerator, requestClient)
+	job := provider.NewLinkHealthJob(healthChecker, instrumentationFactory, runInterval)
+	return job, nil
+}
+
+func InjectDataTool(prefix provider.LogPrefix, logLevel logger.LogLevel, dbConfig db.Config, dbConnector db.Connector, bufferSize provider.KeyGenBufferSize, kgsRPCConfig provider.KgsRPCConfig, aliasPolicy normalizer.AliasPolicy) (tool.Data, error) {
 	rpc, err := provider.NewKgsRPC(kgsRPCConfig)
 	if err != nil {
 		return tool.Data{}, err
@@ -207,7 +407,8 @@ func InjectDataTool(prefix provider.LogPrefix, logLevel logger.LogLevel, dbConfi
 	stdOut := io.NewStdOut()
 	local := provider.NewLocalEntryRepo(stdOut)
 	loggerLogger := provider.NewLogger(prefix, logLevel, system, program, local)
-	data, err := tool.NewData(dbConfig, dbConnector, keyGenerator, loggerLogger)
+	alias := normalizer.NewAlias(aliasPolicy)
+	data, err := tool.NewData(dbConfig, dbConnector, keyGenerator, alias, loggerLogger)
 	if err != nil {
 		return tool.Data{}, err
 	}
@@ -216,9 +417,9 @@ func InjectDataTool(prefix provider.LogPrefix, logLevel logger.LogLevel, dbConfi
 
 // wire.go:
 
-var authenticatorSet = wire.NewSet(provider.NewJwtGo, provider.NewAuthenticator)
+var authenticatorSet = wire.NewSet(wire.Bind(new(repository.Session), new(sqldb.SessionSQL)), sqldb.NewSessionSQL, provider.NewJwtGo, provider.NewAuthenticator)
 
-var authorizerSet = wire.NewSet(wire.Bind(new(repository.UserRole), new(sqldb.UserRoleSQL)), sqldb.NewUserRoleSQL, rbac.NewRBAC, authorizer.NewAuthorizer)
+var authorizerSet = wire.NewSet(wire.Bind(new(repository.UserRole), new(sqldb.UserRoleSQL)), wire.Bind(new(repository.Role), new(sqldb.RoleSQL)), sqldb.NewUserRoleSQL, sqldb.NewRoleSQL, rbac.NewRoleDefinitions, rbac.NewCustomRBAC, provider.NewAccessPolicies, authorizer.NewPolicyAuthorizer, wire.Bind(new(repository.ShortLinkAttribute), new(sqldb.ShortLinkAttributeSQL)), sqldb.NewShortLinkAttributeSQL, policy.NewResourceFinder)
 
 var observabilitySet = wire.NewSet(wire.Bind(new(io.Output), new(io.StdOut)), wire.Bind(new(runtime.Runtime), new(runtime.Program)), wire.Bind(new(metrics.Metrics), new(metrics.DataDog)), wire.Bind(new(analytics.Analytics), new(analytics.Segment)), wire.Bind(new(network.Network), new(network.Proxy)), io.NewStdOut, provider.NewEntryRepositorySwitch, provider.NewLogger, runtime.NewProgram, provider.NewDataDogMetrics, provider.NewSegment, network.NewProxy, request.NewClient, request.NewInstrumentationFactory)
 
@@ -228,6 +429,12 @@ var facebookAPISet = wire.NewSet(provider.NewFacebookIdentityProvider, facebook.
 
 var googleAPISet = wire.NewSet(provider.NewGoogleIdentityProvider, google.NewAccount, google.NewAPI)
 
+var oidcAPISet = wire.NewSet(provider.NewOIDCIssuer, provider.NewOIDCIdentityProvider, oidc.NewAccount)
+
 var keyGenSet = wire.NewSet(wire.Bind(new(keygen.KeyFetcher), new(kgs.RPC)), provider.NewKgsRPC, provider.NewKeyGenerator)
 
+var rateLimitSet = wire.NewSet(wire.Bind(new(ratelimit.Store), new(ratelimit.MemoryStore)), ratelimit.NewMemoryStore, ratelimit.NewLimiter, request.NewThrottler)
+
+var twoFactorSet = wire.NewSet(wire.Bind(new(repository.TwoFactor), new(sqldb.TwoFactorSQL)), sqldb.NewTwoFactorSQL, twofactor.NewTwoFactor)
+
 var featureDecisionSet = wire.NewSet(wire.Bind(new(repository.FeatureToggle), new(sqldb.FeatureToggleSQL)), sqldb.NewFeatureToggleSQL, provider.NewFeatureDecisionMakerFactorySwitch)
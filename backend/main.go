@@ -1,12 +1,24 @@
 package main
 
 import (
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/short-d/app/fw/db"
 	"github.com/short-d/app/fw/envconfig"
 	"github.com/short-d/app/fw/logger"
 	"github.com/short-d/short/backend/app"
+	"github.com/short-d/short/backend/app/adapter/oidc"
+	"github.com/short-d/short/backend/app/adapter/scraper"
+	"github.com/short-d/short/backend/app/adapter/smtp"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac"
+	"github.com/short-d/short/backend/app/usecase/authorizer/rbac/role"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
+	"github.com/short-d/short/backend/app/usecase/quota"
+	"github.com/short-d/short/backend/app/usecase/ratelimit"
+	"github.com/short-d/short/backend/app/usecase/shortlink"
+	"github.com/short-d/short/backend/app/usecase/validator"
 	"github.com/short-d/short/backend/cmd"
 	"github.com/short-d/short/backend/dep"
 )
@@ -33,6 +45,18 @@ func main() {
 		GoogleClientID       string        `env:"GOOGLE_CLIENT_ID" default:""`
 		GoogleClientSecret   string        `env:"GOOGLE_CLIENT_SECRET" default:""`
 		GoogleRedirectURI    string        `env:"GOOGLE_REDIRECT_URI" default:""`
+		OIDCName             string        `env:"OIDC_NAME" default:"oidc"`
+		OIDCIssuerURL        string        `env:"OIDC_ISSUER_URL" default:""`
+		OIDCClientID         string        `env:"OIDC_CLIENT_ID" default:""`
+		OIDCClientSecret     string        `env:"OIDC_CLIENT_SECRET" default:""`
+		OIDCRedirectURI      string        `env:"OIDC_REDIRECT_URI" default:""`
+		OIDCScopes           string        `env:"OIDC_SCOPES" default:"openid,email,profile"`
+		SMTPHost             string        `env:"SMTP_HOST" default:""`
+		SMTPPort             int           `env:"SMTP_PORT" default:"587"`
+		SMTPUsername         string        `env:"SMTP_USERNAME" default:""`
+		SMTPPassword         string        `env:"SMTP_PASSWORD" default:""`
+		SMTPFrom             string        `env:"SMTP_FROM" default:""`
+		EmailSignInURL       string        `env:"EMAIL_SIGN_IN_CALLBACK_URL" default:""`
 		JWTSecret            string        `env:"JWT_SECRET" default:""`
 		WebFrontendURL       string        `env:"WEB_FRONTEND_URL" default:""`
 		KeyGenBufferSize     int           `env:"KEY_GEN_BUFFER_SIZE" default:"50"`
@@ -41,10 +65,13 @@ func main() {
 		GraphQLAPIPort       int           `env:"GRAPHQL_API_PORT" default:"8080"`
 		HTTPAPIPort          int           `env:"HTTP_API_PORT" default:"80"`
 		GRPCAPIPort          int           `env:"GRPC_API_PORT" default:"8081"`
+		GRPCReflection       bool          `env:"GRPC_ENABLE_REFLECTION" default:"false"`
+		GRPCHealthInterval   time.Duration `env:"GRPC_HEALTH_CHECK_INTERVAL" default:"30s"`
 		EnableEncryption     bool          `env:"ENABLE_ENCRYPTION" default:"false"`
 		CertFilePath         string        `env:"CERT_FILE_PATH" default:"/etc/certs/tls.crt"`
 		KeyFilePath          string        `env:"KEY_FILE_PATH" default:"/etc/certs/tls.key"`
-		AuthTokenLifeTime    time.Duration `env:"AUTH_TOKEN_LIFETIME" default:"1w"`
+		AuthTokenLifeTime    time.Duration `env:"AUTH_TOKEN_LIFETIME" default:"15m"`
+		RefreshTokenLifetime time.Duration `env:"AUTH_REFRESH_TOKEN_LIFETIME" default:"30d"`
 		SearchTimeout        time.Duration `env:"SEARCH_TIMEOUT" default:"1s"`
 		SwaggerUIDir         string        `env:"SWAGGER_UI_DIR" default:"app/adapter/routing/public"`
 		OpenAPISpecPath      string        `env:"OPEN_API_SPEC_PATH" default:"app/adapter/routing/api.yml"`
@@ -54,6 +81,45 @@ func main() {
 		SegmentAPIKey        string        `env:"SEGMENT_API_KEY" default:""`
 		IPStackAPIKey        string        `env:"IP_STACK_API_KEY" default:""`
 		GoogleAPIKey         string        `env:"GOOGLE_API_KEY" default:""`
+		AliasFoldCase        bool          `env:"ALIAS_FOLD_CASE" default:"false"`
+		AliasUnicodeNFC      bool          `env:"ALIAS_UNICODE_NFC" default:"false"`
+		AliasTrimSpace       bool          `env:"ALIAS_TRIM_SPACE" default:"false"`
+		AliasWordListPath    string        `env:"ALIAS_WORD_LIST_PATH" default:"app/adapter/wordlist/alias.json"`
+		AccessPolicyPath     string        `env:"ACCESS_POLICY_PATH" default:"app/adapter/authorization/policy.json"`
+		LongLinkMaxLength    int           `env:"LONG_LINK_MAX_LENGTH" default:"200"`
+		LongLinkSchemes      string        `env:"LONG_LINK_SCHEMES" default:"http,https"`
+		ShortLinkDomains     string        `env:"SHORT_LINK_DOMAINS" default:""`
+		RedirectChainDepth   int           `env:"REDIRECT_CHAIN_DEPTH" default:"5"`
+		LinkHealthInterval   time.Duration `env:"LINK_HEALTH_INTERVAL" default:"10m"`
+		LinkRecheckInterval  time.Duration `env:"LINK_RECHECK_INTERVAL" default:"1d"`
+		LinkHealthBatchSize  int           `env:"LINK_HEALTH_BATCH_SIZE" default:"100"`
+		LinkHealthWorkers    int           `env:"LINK_HEALTH_WORKERS" default:"5"`
+		LinkHostInterval     time.Duration `env:"LINK_HOST_INTERVAL" default:"1s"`
+		LinkProbeTimeout     time.Duration `env:"LINK_PROBE_TIMEOUT" default:"10s"`
+		ScrapeWorkers        int           `env:"META_TAG_SCRAPE_WORKERS" default:"2"`
+		ScrapeQueueSize      int           `env:"META_TAG_SCRAPE_QUEUE_SIZE" default:"1000"`
+		ScrapeMaxAttempts    int           `env:"META_TAG_SCRAPE_MAX_ATTEMPTS" default:"3"`
+		ScrapeRetryDelay     time.Duration `env:"META_TAG_SCRAPE_RETRY_DELAY" default:"1m"`
+		ScrapeTimeout        time.Duration `env:"META_TAG_SCRAPE_TIMEOUT" default:"5s"`
+		ScrapeMaxPageSize    int           `env:"META_TAG_SCRAPE_MAX_PAGE_SIZE" default:"524288"`
+		RedirectRateLimit    int           `env:"RATE_LIMIT_REDIRECT_REQUESTS" default:"120"`
+		RedirectRateWindow   time.Duration `env:"RATE_LIMIT_REDIRECT_WINDOW" default:"1m"`
+		SearchRateLimit      int           `env:"RATE_LIMIT_SEARCH_REQUESTS" default:"30"`
+		SearchRateWindow     time.Duration `env:"RATE_LIMIT_SEARCH_WINDOW" default:"1m"`
+		CloudAPIRateLimit    int           `env:"RATE_LIMIT_CLOUD_API_REQUESTS" default:"600"`
+		CloudAPIRateWindow   time.Duration `env:"RATE_LIMIT_CLOUD_API_WINDOW" default:"1m"`
+		GraphQLRateLimit     int           `env:"RATE_LIMIT_GRAPHQL_REQUESTS" default:"300"`
+		GraphQLRateWindow    time.Duration `env:"RATE_LIMIT_GRAPHQL_WINDOW" default:"1m"`
+		EmailSignInRateLimit int           `env:"RATE_LIMIT_EMAIL_SIGN_IN_REQUESTS" default:"3"`
+		EmailSignInWindow    time.Duration `env:"RATE_LIMIT_EMAIL_SIGN_IN_WINDOW" default:"15m"`
+		BasicDailyQuota      int           `env:"QUOTA_BASIC_DAILY" default:"50"`
+		BasicTotalQuota      int           `env:"QUOTA_BASIC_TOTAL" default:"1000"`
+		PremiumDailyQuota    int           `env:"QUOTA_PREMIUM_DAILY" default:"1000"`
+		PremiumTotalQuota    int           `env:"QUOTA_PREMIUM_TOTAL" default:"0"`
+		AppDailyQuota        int           `env:"QUOTA_APP_DAILY" default:"1000"`
+		AppTotalQuota        int           `env:"QUOTA_APP_TOTAL" default:"0"`
+		TwoFactorRoles       string        `env:"TWO_FACTOR_ROLES" default:"admin,security_specialist"`
+		TwoFactorEnforceFrom string        `env:"TWO_FACTOR_ENFORCE_FROM" default:""`
 	}{}
 
 	err := envConfig.ParseConfigFromEnv(&config)
@@ -86,11 +152,29 @@ func main() {
 		GoogleClientID:       config.GoogleClientID,
 		GoogleClientSecret:   config.GoogleClientSecret,
 		GoogleRedirectURI:    config.GoogleRedirectURI,
+		OIDCConfig: oidc.Config{
+			Name:         config.OIDCName,
+			IssuerURL:    config.OIDCIssuerURL,
+			ClientID:     config.OIDCClientID,
+			ClientSecret: config.OIDCClientSecret,
+			RedirectURI:  config.OIDCRedirectURI,
+			Scopes:       strings.Split(config.OIDCScopes, ","),
+		},
+		SMTPConfig: smtp.Config{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.SMTPFrom,
+		},
+		EmailSignInURL:       config.EmailSignInURL,
 		JwtSecret:            config.JWTSecret,
 		WebFrontendURL:       config.WebFrontendURL,
 		GraphQLAPIPort:       config.GraphQLAPIPort,
 		HTTPAPIPort:          config.HTTPAPIPort,
 		GRPCAPIPort:          config.GRPCAPIPort,
+		GRPCEnableReflection: config.GRPCReflection,
+		GRPCHealthInterval:   config.GRPCHealthInterval,
 		EnableEncryption:     config.EnableEncryption,
 		CertFilePath:         config.CertFilePath,
 		KeyFilePath:          config.KeyFilePath,
@@ -98,6 +182,7 @@ func main() {
 		KgsHostname:          config.KgsHostname,
 		KgsPort:              config.KgsPort,
 		AuthTokenLifetime:    config.AuthTokenLifeTime,
+		RefreshTokenLifetime: config.RefreshTokenLifetime,
 		SearchTimeout:        config.SearchTimeout,
 		SwaggerUIDir:         config.SwaggerUIDir,
 		OpenAPISpecPath:      config.OpenAPISpecPath,
@@ -107,6 +192,80 @@ func main() {
 		SegmentAPIKey:        config.SegmentAPIKey,
 		IPStackAPIKey:        config.IPStackAPIKey,
 		GoogleAPIKey:         config.GoogleAPIKey,
+		AliasWordListPath:    config.AliasWordListPath,
+		AccessPolicyPath:     config.AccessPolicyPath,
+		LongLinkPolicy: validator.LongLinkPolicy{
+			MaxLength:      config.LongLinkMaxLength,
+			AllowedSchemes: strings.Split(config.LongLinkSchemes, ","),
+		},
+		RedirectPolicy: shortlink.RedirectPolicy{
+			ShortLinkDomains: shortLinkDomains(config.ShortLinkDomains, config.WebFrontendURL),
+			MaxChainDepth:    config.RedirectChainDepth,
+		},
+		LinkHealthPolicy: shortlink.HealthPolicy{
+			CheckInterval: config.LinkRecheckInterval,
+			BatchSize:     config.LinkHealthBatchSize,
+			Concurrency:   config.LinkHealthWorkers,
+			HostInterval:  config.LinkHostInterval,
+		},
+		LinkProbeTimeout:   config.LinkProbeTimeout,
+		LinkHealthInterval: config.LinkHealthInterval,
+		ScrapePolicy: shortlink.ScrapePolicy{
+			Workers:     config.ScrapeWorkers,
+			QueueSize:   config.ScrapeQueueSize,
+			MaxAttempts: config.ScrapeMaxAttempts,
+			RetryDelay:  config.ScrapeRetryDelay,
+		},
+		ScrapeLimit: scraper.Limit{
+			Timeout:     config.ScrapeTimeout,
+			MaxPageSize: int64(config.ScrapeMaxPageSize),
+		},
+		AliasPolicy: normalizer.AliasPolicy{
+			FoldCase:   config.AliasFoldCase,
+			UnicodeNFC: config.AliasUnicodeNFC,
+			TrimSpace:  config.AliasTrimSpace,
+		},
+		RateLimitPolicy: ratelimit.Policy{
+			Redirect: ratelimit.Limit{
+				Requests: config.RedirectRateLimit,
+				Window:   config.RedirectRateWindow,
+			},
+			Search: ratelimit.Limit{
+				Requests: config.SearchRateLimit,
+				Window:   config.SearchRateWindow,
+			},
+			CloudAPI: ratelimit.Limit{
+				Requests: config.CloudAPIRateLimit,
+				Window:   config.CloudAPIRateWindow,
+			},
+			GraphQL: ratelimit.Limit{
+				Requests: config.GraphQLRateLimit,
+				Window:   config.GraphQLRateWindow,
+			},
+			EmailSignIn: ratelimit.Limit{
+				Requests: config.EmailSignInRateLimit,
+				Window:   config.EmailSignInWindow,
+			},
+		},
+		QuotaPolicy: quota.Policy{
+			Default: quota.Limit{
+				Daily: config.BasicDailyQuota,
+				Total: config.BasicTotalQuota,
+			},
+			Roles: map[role.Role]quota.Limit{
+				role.Premium: {
+					Daily: config.PremiumDailyQuota,
+					Total: config.PremiumTotalQuota,
+				},
+				role.ShortLinkEditor: quota.Unlimited,
+				role.Admin:           quota.Unlimited,
+			},
+			App: quota.Limit{
+				Daily: config.AppDailyQuota,
+				Total: config.AppTotalQuota,
+			},
+		},
+		TwoFactorPolicy: twoFactorPolicy(config.TwoFactorRoles, config.TwoFactorEnforceFrom),
 	}
 
 	rootCmd := cmd.NewRootCmd(
@@ -118,3 +277,36 @@ func main() {
 	)
 	cmd.Execute(rootCmd)
 }
+
+// shortLinkDomains lists the hosts serving short links, including the host of
+// the web frontend.
+func shortLinkDomains(domains string, webFrontendURL string) []string {
+	shortLinkDomains := strings.Split(domains, ",")
+	frontendURL, err := url.Parse(webFrontendURL)
+	if err == nil && frontendURL.Hostname() != "" {
+		shortLinkDomains = append(shortLinkDomains, frontendURL.Hostname())
+	}
+	return shortLinkDomains
+}
+
+// twoFactorPolicy requires the given roles to sign in with a second factor
+// from the given RFC 3339 time, or immediately if the time is omitted.
+func twoFactorPolicy(roles string, enforceFrom string) rbac.TwoFactorPolicy {
+	policy := rbac.TwoFactorPolicy{}
+	for _, name := range strings.Split(roles, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			policy.Roles = append(policy.Roles, role.Role(name))
+		}
+	}
+	if enforceFrom == "" {
+		return policy
+	}
+
+	enforceFromTime, err := time.Parse(time.RFC3339, enforceFrom)
+	if err != nil {
+		panic(err)
+	}
+	policy.EnforceFrom = enforceFromTime
+	return policy
+}
@@ -8,14 +8,16 @@ import (
 
 	"github.com/short-d/app/fw/db"
 	"github.com/short-d/short/backend/app/usecase/keygen"
+	"github.com/short-d/short/backend/app/usecase/normalizer"
 )
 
 // Data transform existing data to the new format and move them to the correct
 // location.
 type Data struct {
-	keyGen keygen.KeyGenerator
-	db     *sql.DB
-	logger logger.Logger
+	keyGen          keygen.KeyGenerator
+	aliasNormalizer normalizer.Alias
+	db              *sql.DB
+	logger          logger.Logger
 }
 
 // EmailToID generates IDs for users and changes DB tables reference those IDs.
@@ -55,11 +57,73 @@ UPDATE "user_url_relation" SET user_id=$1 WHERE user_email=$2;
 	d.logger.Info(fmt.Sprintf("Migrated %d accounts.", count))
 }
 
+// NormalizeAlias reports aliases which collide with each other under the
+// alias normalization policy. When apply is true, aliases without collision
+// are rewritten to their normalized form. Relations referencing short_link
+// are updated through ON UPDATE CASCADE.
+func (d Data) NormalizeAlias(apply bool) {
+	d.logger.Info("Checking aliases against normalization policy")
+	rows, err := d.db.Query(`
+SELECT alias FROM "short_link";
+`)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string][]string)
+	for rows.Next() {
+		var alias string
+		err = rows.Scan(&alias)
+		if err != nil {
+			panic(err)
+		}
+		normalizedAlias := d.aliasNormalizer.Normalize(alias)
+		groups[normalizedAlias] = append(groups[normalizedAlias], alias)
+	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
+
+	collisions := 0
+	migrated := 0
+	for normalizedAlias, aliases := range groups {
+		if len(aliases) > 1 {
+			collisions++
+			d.logger.Info(fmt.Sprintf(
+				"Collision: %v all normalize to %s", aliases, normalizedAlias,
+			))
+			continue
+		}
+
+		alias := aliases[0]
+		if alias == normalizedAlias {
+			continue
+		}
+		if !apply {
+			d.logger.Info(fmt.Sprintf("Pending: %s -> %s", alias, normalizedAlias))
+			continue
+		}
+		_, err = d.db.Exec(`
+UPDATE "short_link" SET alias=$1 WHERE alias=$2;
+`, normalizedAlias, alias)
+		if err != nil {
+			panic(err)
+		}
+		migrated++
+	}
+	d.logger.Info(fmt.Sprintf(
+		"Found %d collisions. Normalized %d aliases.", collisions, migrated,
+	))
+}
+
 // NewData creates data manage
 func NewData(
 	dbConfig db.Config,
 	dbConnector db.Connector,
 	keyGen keygen.KeyGenerator,
+	aliasNormalizer normalizer.Alias,
 	logger logger.Logger,
 ) (Data, error) {
 	sqlDB, err := dbConnector.Connect(dbConfig)
@@ -67,8 +131,9 @@ func NewData(
 		return Data{}, err
 	}
 	return Data{
-		keyGen: keyGen,
-		db:     sqlDB,
-		logger: logger,
+		keyGen:          keyGen,
+		aliasNormalizer: aliasNormalizer,
+		db:              sqlDB,
+		logger:          logger,
 	}, nil
 }
@@ -16,7 +16,19 @@ export class AuthService {
       return;
     }
     this.saveAuthToken(token);
-    this.routingService.navigateTo('/');
+    this.routingService.navigateTo(
+      this.getSafeRedirectPath(params.get('redirect_to'))
+    );
+  }
+
+  private getSafeRedirectPath(redirectTo: string | null): string {
+    if (!redirectTo || !redirectTo.startsWith('/')) {
+      return '/';
+    }
+    if (redirectTo.startsWith('//') || redirectTo.includes('\\')) {
+      return '/';
+    }
+    return redirectTo;
   }
 
   saveAuthToken(token: string | null) {